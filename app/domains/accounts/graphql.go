@@ -7,7 +7,9 @@ import (
 	"github.com/golly-go/golly/errors"
 	"github.com/golly-go/plugins/eventsource"
 	"github.com/golly-go/plugins/gql"
+	"github.com/google/uuid"
 	"github.com/graphql-go/graphql"
+	"github.com/mitchrodrigues/talent-review-backend/app/domains/accounts/organizations"
 	"github.com/mitchrodrigues/talent-review-backend/app/domains/accounts/users"
 	"github.com/mitchrodrigues/talent-review-backend/app/domains/common"
 	"github.com/mitchrodrigues/talent-review-backend/app/utils/helpers"
@@ -18,6 +20,51 @@ import (
 )
 
 var (
+	organizationSettingsType = graphql.NewObject(graphql.ObjectConfig{
+		Name: "OrganizationSettings",
+		Fields: graphql.Fields{
+			"monthlyTokenBudget": {
+				Type: graphql.Int,
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					return p.Source.(organizations.Settings).MonthlyTokenBudget, nil
+				},
+			},
+			"budgetMode": {
+				Type: graphql.String,
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					return p.Source.(organizations.Settings).Mode(), nil
+				},
+			},
+			"redactPII": {
+				Type: graphql.Boolean,
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					return p.Source.(organizations.Settings).RedactPII, nil
+				},
+			},
+			"minAggregatedResponses": {
+				Type:        graphql.Int,
+				Description: "Responses needed before aggregated-only feedback is shown",
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					return p.Source.(organizations.Settings).AggregatedThreshold(), nil
+				},
+			},
+			"feedbackRetentionDays": {
+				Type:        graphql.Int,
+				Description: "Days after collection closed before written feedback is deleted, 0 keeps it",
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					return p.Source.(organizations.Settings).FeedbackRetentionDays, nil
+				},
+			},
+			"unsubmittedRetentionDays": {
+				Type:        graphql.Int,
+				Description: "Days after which feedback requests never submitted are dropped, 0 keeps them",
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					return p.Source.(organizations.Settings).UnsubmittedRetentionDays, nil
+				},
+			},
+		},
+	})
+
 	organizationType = graphql.NewObject(graphql.ObjectConfig{
 		Name: "Organization",
 		Fields: graphql.Fields{
@@ -33,6 +80,12 @@ var (
 					return p.Source.(Organization).Name, nil
 				},
 			},
+			"settings": {
+				Type: organizationSettingsType,
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					return p.Source.(Organization).Settings, nil
+				},
+			},
 		},
 	})
 
@@ -63,6 +116,12 @@ var (
 					return p.Source.(User).Email, nil
 				},
 			},
+			"role": {
+				Type: graphql.String,
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					return p.Source.(User).Role, nil
+				},
+			},
 			"invitedAt": {
 				Type: graphql.DateTime,
 				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
@@ -160,6 +219,20 @@ var (
 		},
 	})
 
+	organizationSettingsInputType = graphql.NewInputObject(graphql.InputObjectConfig{
+		Name: "OrganizationSettingsInput",
+		Fields: graphql.InputObjectConfigFieldMap{
+			"monthlyTokenBudget": {Type: graphql.Int},
+			"budgetMode":         {Type: graphql.String},
+			"redactPII":          {Type: graphql.Boolean},
+
+			"minAggregatedResponses": {Type: graphql.Int},
+
+			"feedbackRetentionDays":    {Type: graphql.Int},
+			"unsubmittedRetentionDays": {Type: graphql.Int},
+		},
+	})
+
 	mutations = graphql.Fields{
 		"createInvite": {
 			Name: "invite",
@@ -197,6 +270,91 @@ var (
 				},
 			}),
 		},
+		"updateUserRole": {
+			Name: "updateUserRole",
+			Type: userType,
+			Args: graphql.FieldConfigArgument{
+				"id":   {Type: graphql.NewNonNull(graphql.String)},
+				"role": {Type: graphql.NewNonNull(graphql.String)},
+			},
+			Resolve: gql.NewHandler(gql.Options{
+				Handler: func(ctx golly.WebContext, params gql.Params) (interface{}, error) {
+					if _, err := RequireAdmin(ctx.Context); err != nil {
+						return nil, err
+					}
+
+					user, err := FindUserByID(
+						ctx.Context,
+						params.Args["id"].(string),
+						common.OrganizationIDScopeForContext(ctx.Context),
+					)
+					if err != nil {
+						return nil, err
+					}
+
+					err = eventsource.Call(ctx.Context, &user.Aggregate, users.UpdateRole{
+						Role: params.Args["role"].(string),
+					}, params.Metadata())
+
+					return user, errors.WrapGeneric(err)
+				},
+			}),
+		},
+		"updateOrganizationSettings": {
+			Name: "updateOrganizationSettings",
+			Type: organizationType,
+			Args: graphql.FieldConfigArgument{
+				"input": &graphql.ArgumentConfig{Type: graphql.NewNonNull(organizationSettingsInputType)},
+			},
+			Resolve: gql.NewHandler(gql.Options{
+				Handler: func(ctx golly.WebContext, params gql.Params) (interface{}, error) {
+					if _, err := RequireAdmin(ctx.Context); err != nil {
+						return nil, err
+					}
+
+					organization, err := FindOrganizationByID(ctx.Context, identity.FromContext(ctx.Context).OrganizationID)
+					if err != nil {
+						return nil, err
+					}
+
+					if organization.ID == uuid.Nil {
+						return nil, errors.WrapNotFound(fmt.Errorf("record not found"))
+					}
+
+					settings := organization.Settings
+
+					if budget, err := helpers.ExtractArg[int](params.Input, "monthlyTokenBudget"); err == nil {
+						settings.MonthlyTokenBudget = int64(budget)
+					}
+
+					if mode, err := helpers.ExtractArg[string](params.Input, "budgetMode"); err == nil {
+						settings.BudgetMode = organizations.BudgetMode(mode)
+					}
+
+					if redact, err := helpers.ExtractArg[bool](params.Input, "redactPII"); err == nil {
+						settings.RedactPII = redact
+					}
+
+					if responses, err := helpers.ExtractArg[int](params.Input, "minAggregatedResponses"); err == nil {
+						settings.MinAggregatedResponses = responses
+					}
+
+					if days, err := helpers.ExtractArg[int](params.Input, "feedbackRetentionDays"); err == nil {
+						settings.FeedbackRetentionDays = days
+					}
+
+					if days, err := helpers.ExtractArg[int](params.Input, "unsubmittedRetentionDays"); err == nil {
+						settings.UnsubmittedRetentionDays = days
+					}
+
+					err = eventsource.Call(ctx.Context, &organization.Aggregate, organizations.UpdateSettings{
+						Settings: settings,
+					}, params.Metadata())
+
+					return organization, errors.WrapGeneric(err)
+				},
+			}),
+		},
 	}
 )
 
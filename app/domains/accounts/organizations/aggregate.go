@@ -22,6 +22,8 @@ type Aggregate struct {
 	MerchantPlanID     string
 	MerchantPlanName   string
 
+	Settings Settings `gorm:"type:jsonb"`
+
 	ActivatedAt   *time.Time
 	DeactivatedAt *time.Time
 }
@@ -43,6 +45,9 @@ func (org *Aggregate) Apply(ctx golly.Context, evt eventsource.Event) {
 		org.Name = event.Name
 		org.IdpID = event.IdpID
 		org.MerchantPlanName = event.PlanName
+
+	case SettingsUpdated:
+		org.Settings = event.Settings
 	}
 }
 
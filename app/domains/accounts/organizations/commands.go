@@ -2,6 +2,7 @@ package organizations
 
 import (
 	"github.com/golly-go/golly"
+	"github.com/golly-go/golly/errors"
 	"github.com/golly-go/plugins/eventsource"
 	"github.com/google/uuid"
 	"github.com/mitchrodrigues/talent-review-backend/app/utils/workos"
@@ -29,3 +30,26 @@ func (cmd CreateOrganization) Perform(ctx golly.Context, aggregate eventsource.A
 
 	return nil
 }
+
+type UpdateSettings struct {
+	Settings Settings
+}
+
+func (cmd UpdateSettings) Validate(ctx golly.Context, aggregate eventsource.Aggregate) error {
+	return errors.WrapUnprocessable(cmd.Settings.Validate())
+}
+
+func (cmd UpdateSettings) Perform(ctx golly.Context, aggregate eventsource.Aggregate) error {
+	eventsource.Apply(ctx, aggregate, SettingsUpdated{Settings: cmd.Settings})
+	return nil
+}
+
+// RecordPurge records the report of a retention purge of the organization
+type RecordPurge struct {
+	Report DataPurged
+}
+
+func (cmd RecordPurge) Perform(ctx golly.Context, aggregate eventsource.Aggregate) error {
+	eventsource.Apply(ctx, aggregate, cmd.Report)
+	return nil
+}
@@ -1,6 +1,8 @@
 package organizations
 
 import (
+	"time"
+
 	"github.com/google/uuid"
 )
 
@@ -10,3 +12,44 @@ type OrganizationCreated struct {
 	Name     string    `json:"name"`
 	PlanName string    `json:"planName"`
 }
+
+type SettingsUpdated struct {
+	Settings Settings `json:"settings"`
+}
+
+// DataPurged is the report of a retention purge, it is recorded on the
+// organization so the purge shows in the audit log
+type DataPurged struct {
+	OrganizationID uuid.UUID `json:"organizationID"`
+	PurgedAt       time.Time `json:"purgedAt"`
+
+	FeedbackRetentionDays    int `json:"feedbackRetentionDays"`
+	UnsubmittedRetentionDays int `json:"unsubmittedRetentionDays"`
+
+	// FeedbackPurged is how many feedback had their written content,
+	// summary and analysis deleted
+	FeedbackPurged int `json:"feedbackPurged"`
+
+	// RequestsDropped is how many unsubmitted feedback requests were deleted
+	RequestsDropped int `json:"requestsDropped"`
+
+	// EventsTombstoned is how many events of the purged feedback had
+	// their payload replaced by a tombstone
+	EventsTombstoned int64 `json:"eventsTombstoned"`
+
+	// ReviewsPurged is how many performance reviews had their text deleted
+	ReviewsPurged int64 `json:"reviewsPurged"`
+
+	// ConversationsDeleted is how many tara conversations about the
+	// employees of the purged feedback were deleted
+	ConversationsDeleted int64 `json:"conversationsDeleted"`
+
+	// ArchivesDropped is how many data export archives were deleted
+	ArchivesDropped int64 `json:"archivesDropped"`
+}
+
+// Empty is true when the purge did not remove anything
+func (evt DataPurged) Empty() bool {
+	return evt.FeedbackPurged == 0 && evt.RequestsDropped == 0 &&
+		evt.ReviewsPurged == 0 && evt.ConversationsDeleted == 0 && evt.ArchivesDropped == 0
+}
@@ -0,0 +1,102 @@
+package organizations
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+)
+
+type BudgetMode string
+
+const (
+	// BudgetBlock refuses tara requests once the budget is spent
+	BudgetBlock BudgetMode = "block"
+
+	// BudgetDegrade keeps tara running on cheaper models and skips
+	// the optional generations once the budget is spent
+	BudgetDegrade BudgetMode = "degrade"
+
+	DefaultMinAggregatedResponses = 3
+)
+
+// Settings are the organization level knobs stored as jsonb on the
+// organizations table
+type Settings struct {
+	// MonthlyTokenBudget caps the LLM tokens used per calendar month, zero is unlimited
+	MonthlyTokenBudget int64      `json:"monthlyTokenBudget"`
+	BudgetMode         BudgetMode `json:"budgetMode,omitempty"`
+
+	// RedactPII tokenizes names, emails and phone numbers before any
+	// feedback text is sent to the LLM
+	RedactPII bool `json:"redactPII"`
+
+	// MinAggregatedResponses is how many reviewers have to respond
+	// before aggregated-only feedback is shown, zero uses the default
+	MinAggregatedResponses int `json:"minAggregatedResponses,omitempty"`
+
+	// FeedbackRetentionDays deletes the written feedback, its summary
+	// and analysis this many days after its collection closed, zero
+	// keeps it forever
+	FeedbackRetentionDays int `json:"feedbackRetentionDays,omitempty"`
+
+	// UnsubmittedRetentionDays drops feedback requests that were never
+	// submitted this many days after they were sent, zero keeps them
+	UnsubmittedRetentionDays int `json:"unsubmittedRetentionDays,omitempty"`
+}
+
+func (s Settings) Mode() BudgetMode {
+	if s.BudgetMode == "" {
+		return BudgetBlock
+	}
+	return s.BudgetMode
+}
+
+func (s Settings) AggregatedThreshold() int {
+	if s.MinAggregatedResponses <= 0 {
+		return DefaultMinAggregatedResponses
+	}
+	return s.MinAggregatedResponses
+}
+
+// RetentionEnabled is true when any retention period is set
+func (s Settings) RetentionEnabled() bool {
+	return s.FeedbackRetentionDays > 0 || s.UnsubmittedRetentionDays > 0
+}
+
+func (s Settings) Validate() error {
+	switch s.BudgetMode {
+	case "", BudgetBlock, BudgetDegrade:
+	default:
+		return fmt.Errorf("invalid budget mode %s", s.BudgetMode)
+	}
+
+	if s.MonthlyTokenBudget < 0 {
+		return fmt.Errorf("monthly token budget cannot be negative")
+	}
+
+	if s.MinAggregatedResponses < 0 {
+		return fmt.Errorf("minimum aggregated responses cannot be negative")
+	}
+
+	if s.FeedbackRetentionDays < 0 || s.UnsubmittedRetentionDays < 0 {
+		return fmt.Errorf("retention periods cannot be negative")
+	}
+
+	return nil
+}
+
+func (s Settings) Value() (driver.Value, error) {
+	return json.Marshal(s)
+}
+
+func (s *Settings) Scan(value interface{}) error {
+	switch v := value.(type) {
+	case nil:
+		return nil
+	case []byte:
+		return json.Unmarshal(v, s)
+	case string:
+		return json.Unmarshal([]byte(v), s)
+	}
+	return fmt.Errorf("cannot scan %T into settings", value)
+}
@@ -1,10 +1,13 @@
 package accounts
 
 import (
+	"fmt"
+
 	"github.com/golly-go/golly"
 	"github.com/golly-go/golly/errors"
 	"github.com/golly-go/plugins/orm"
 	"github.com/google/uuid"
+	"github.com/mitchrodrigues/talent-review-backend/app/domains/accounts/organizations"
 	"github.com/mitchrodrigues/talent-review-backend/app/utils/identity"
 	"gorm.io/gorm"
 )
@@ -64,3 +67,44 @@ func FindOrganizationByIDPId(ctx golly.Context, idpID string) (Organization, err
 func DefaultUserPreloads(db *gorm.DB) *gorm.DB {
 	return db.Preload("Organization")
 }
+
+// RequireAdmin loads the user on the context and fails unless they
+// are an admin of their organization
+func RequireAdmin(gctx golly.Context) (User, error) {
+	user, err := FindUserForContext(gctx)
+	if err != nil {
+		return user, err
+	}
+
+	if !user.IsAdmin() {
+		return user, errors.WrapForbidden(fmt.Errorf("admin access required"))
+	}
+
+	return user, nil
+}
+
+// RequireHR loads the user on the context and fails unless they are HR
+// or an admin of their organization
+func RequireHR(gctx golly.Context) (User, error) {
+	user, err := FindUserForContext(gctx)
+	if err != nil {
+		return user, err
+	}
+
+	if !user.IsHR() && !user.IsAdmin() {
+		return user, errors.WrapForbidden(fmt.Errorf("hr access required"))
+	}
+
+	return user, nil
+}
+
+// OrganizationSettings returns the settings of an organization, a missing
+// organization simply has the default settings
+func OrganizationSettings(gctx golly.Context, organizationID uuid.UUID) organizations.Settings {
+	organization, err := FindOrganizationByID(gctx, organizationID)
+	if err != nil {
+		gctx.Logger().Warnf("cannot load settings for organization %s: %v", organizationID, err)
+	}
+
+	return organization.Settings
+}
@@ -8,8 +8,20 @@ import (
 	"github.com/golly-go/plugins/orm"
 	"github.com/google/uuid"
 	es "github.com/mitchrodrigues/talent-review-backend/app/utils/esbackend"
+	"github.com/mitchrodrigues/talent-review-backend/app/utils/helpers"
 )
 
+const (
+	RoleMember = "member"
+	RoleAdmin  = "admin"
+
+	// RoleHR can see organization wide people analytics without
+	// administering the organization
+	RoleHR = "hr"
+)
+
+var Roles = []string{RoleMember, RoleAdmin, RoleHR}
+
 type Aggregate struct {
 	eventsource.AggregateBase
 
@@ -26,6 +38,8 @@ type Aggregate struct {
 
 	InvitedAt *time.Time
 	InviterID uuid.UUID
+
+	Role string
 }
 
 func (*Aggregate) Topic() string                             { return "events.users" }
@@ -47,6 +61,7 @@ func (user *Aggregate) Apply(ctx golly.Context, evt eventsource.Event) {
 		user.LastName = event.LastName
 		user.Email = event.Email
 		user.IdpID = event.IdpID
+		user.Role = helpers.Coalesce(event.Role, RoleMember)
 
 	case UserInvited:
 		user.IdpInviteID = event.IdpInviteID
@@ -58,7 +73,13 @@ func (user *Aggregate) Apply(ctx golly.Context, evt eventsource.Event) {
 		user.FirstName = event.FirstName
 		user.LastName = event.LastName
 		user.Email = event.Email
+
+	case RoleUpdated:
+		user.Role = event.Role
 	}
 }
 
+func (user *Aggregate) IsAdmin() bool { return user.Role == RoleAdmin }
+func (user *Aggregate) IsHR() bool    { return user.Role == RoleHR }
+
 var _ eventsource.Aggregate = &Aggregate{}
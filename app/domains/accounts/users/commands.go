@@ -2,6 +2,7 @@ package users
 
 import (
 	"fmt"
+	"slices"
 	"strings"
 	"time"
 
@@ -24,6 +25,7 @@ type CreateUser struct {
 	FirstName string
 	LastName  string
 	Password  string
+	Role      string
 
 	SkipWorkOS string
 }
@@ -69,6 +71,7 @@ func (cmd CreateUser) Perform(ctx golly.Context, aggregate eventsource.Aggregate
 		LastName:       cmd.LastName,
 		Email:          cmd.Email,
 		OrganizationID: cmd.Organization.RecordID(),
+		Role:           helpers.Coalesce(cmd.Role, RoleMember),
 	})
 
 	return nil
@@ -137,6 +140,7 @@ func (cmd InviteUser) Perform(ctx golly.Context, aggregate eventsource.Aggregate
 		LastName:       lastName,
 		Email:          cmd.Email,
 		OrganizationID: cmd.Organization.RecordID(),
+		Role:           RoleMember,
 	})
 
 	eventsource.Apply(ctx, aggregate, UserInvited{
@@ -172,3 +176,21 @@ func (cmd EditUser) Perform(ctx golly.Context, aggregate eventsource.Aggregate)
 
 	return nil
 }
+
+type UpdateRole struct {
+	Role string
+}
+
+func (cmd UpdateRole) Validate(gctx golly.Context, aggregate eventsource.Aggregate) error {
+	if !slices.Contains(Roles, cmd.Role) {
+		return errors.WrapUnprocessable(fmt.Errorf("invalid role %s", cmd.Role))
+	}
+	return nil
+}
+
+func (cmd UpdateRole) Perform(gctx golly.Context, aggregate eventsource.Aggregate) error {
+	eventsource.Apply(gctx, aggregate, RoleUpdated{Role: cmd.Role})
+	return nil
+}
+
+var _ eventsource.Command = UpdateRole{}
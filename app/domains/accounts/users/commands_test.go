@@ -111,6 +111,7 @@ func TestCreateUserPerform(t *testing.T) {
 			assert.Equal(t, tt.cmd.LastName, event.LastName)
 			assert.Equal(t, tt.cmd.Email, event.Email)
 			assert.Equal(t, tt.cmd.Organization.RecordID(), event.OrganizationID)
+			assert.Equal(t, RoleMember, event.Role)
 
 			mock.AssertExpectations(t)
 		})
@@ -302,3 +303,36 @@ func TestEditUserPerform(t *testing.T) {
 		})
 	}
 }
+
+func TestUpdateRole(t *testing.T) {
+	tests := []struct {
+		name      string
+		cmd       UpdateRole
+		expectErr bool
+	}{
+		{name: "admin", cmd: UpdateRole{Role: RoleAdmin}},
+		{name: "member", cmd: UpdateRole{Role: RoleMember}},
+		{name: "hr", cmd: UpdateRole{Role: RoleHR}},
+		{name: "unknown role", cmd: UpdateRole{Role: "owner"}, expectErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := golly.Context{}
+			user := Aggregate{}
+
+			err := tt.cmd.Validate(ctx, &user)
+			if tt.expectErr {
+				assert.Error(t, err)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.NoError(t, tt.cmd.Perform(ctx, &user))
+
+			changes := user.Changes()
+			assert.Len(t, changes, 1)
+			assert.Equal(t, RoleUpdated{Role: tt.cmd.Role}, changes[0].Data)
+		})
+	}
+}
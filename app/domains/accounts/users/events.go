@@ -24,6 +24,8 @@ type UserCreated struct {
 	Email     string
 	FirstName string
 	LastName  string
+
+	Role string
 }
 
 type UserUpdated struct {
@@ -33,3 +35,14 @@ type UserUpdated struct {
 	LastName       string
 	ProfilePicture string
 }
+
+type RoleUpdated struct {
+	Role string
+}
+
+// Names and emails are sealed with the key of the user in the event store
+// so erasing the employee linked to the user shreds them
+func (UserCreated) PersonalFields() []string { return []string{"Email", "FirstName", "LastName"} }
+func (UserUpdated) PersonalFields() []string {
+	return []string{"Email", "FirstName", "LastName", "ProfilePicture"}
+}
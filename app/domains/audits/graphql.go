@@ -6,10 +6,12 @@ import (
 
 	"github.com/golly-go/golly"
 	"github.com/golly-go/plugins/gql"
+	"github.com/golly-go/plugins/orm"
 	"github.com/google/uuid"
 	"github.com/graphql-go/graphql"
 	"github.com/mitchrodrigues/talent-review-backend/app/domains/accounts"
 	"github.com/mitchrodrigues/talent-review-backend/app/domains/common"
+	"github.com/mitchrodrigues/talent-review-backend/app/utils/crypto"
 	"github.com/mitchrodrigues/talent-review-backend/app/utils/pagination"
 )
 
@@ -62,11 +64,19 @@ var (
 				},
 			},
 			"changes": {
-				Type: graphql.String,
-				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
-					b, err := p.Source.(Event).RawData.MarshalJSON()
-					return string(b), err
-				},
+				Type:        graphql.String,
+				Description: "The event payload, personal data of erased employees reads [erased]",
+				Resolve: gql.NewHandler(gql.Options{
+					Handler: func(ctx golly.WebContext, params gql.Params) (interface{}, error) {
+						b, err := params.Source.(Event).RawData.MarshalJSON()
+						if err != nil {
+							return nil, err
+						}
+
+						b, err = crypto.OpenPersonalData(ctx.Context.Context(), orm.DB(ctx.Context), b)
+						return string(b), err
+					},
+				}),
 			},
 			"eventAt": {
 				Type: graphql.String,
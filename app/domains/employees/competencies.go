@@ -0,0 +1,182 @@
+package employees
+
+import (
+	"fmt"
+
+	"github.com/golly-go/golly"
+	"github.com/golly-go/golly/errors"
+	"github.com/golly-go/plugins/orm"
+	"github.com/google/uuid"
+	"github.com/mitchrodrigues/talent-review-backend/app/domains/common"
+	"github.com/mitchrodrigues/talent-review-backend/app/domains/employees/competency"
+	"github.com/mitchrodrigues/talent-review-backend/app/domains/employees/role"
+)
+
+type Competency struct {
+	competency.Aggregate
+}
+
+func (Competency) TableName() string { return "competencies" }
+
+// Expectation is what a competency expects at one level
+type Expectation struct {
+	CompetencyID uuid.UUID
+	Competency   string
+	Description  string
+
+	Level        int
+	Expectations string
+}
+
+func (expectation Expectation) String() string {
+	return fmt.Sprintf("%s (level %d): %s", expectation.Competency, expectation.Level, expectation.Expectations)
+}
+
+// RoleExpectations are the expected behaviors of a role at its current
+// level and at the next level of its track
+type RoleExpectations struct {
+	Level   int
+	Current []Expectation
+	Next    []Expectation
+}
+
+// Strings lists the current then the next level expectations as text
+// for prompts
+func (re RoleExpectations) Strings() []string {
+	return append(
+		golly.Map(re.Current, Expectation.String),
+		golly.Map(re.Next, Expectation.String)...,
+	)
+}
+
+// LevelDiff compares what a competency expects at two levels
+type LevelDiff struct {
+	CompetencyID uuid.UUID
+	Competency   string
+
+	From string
+	To   string
+}
+
+func (diff LevelDiff) Changed() bool { return diff.From != diff.To }
+
+// FindCompetencies lists the competencies of the organization, all
+// tracks when the track is empty
+func FindCompetencies(gctx golly.Context, track role.EmployeeType) ([]Competency, error) {
+	var competencies []Competency
+
+	db := orm.DB(gctx).
+		Model(&Competency{}).
+		Scopes(common.OrganizationIDScopeForContext(gctx))
+
+	if track != "" {
+		db = db.Where("track = ?", track)
+	}
+
+	err := db.Order("track, name").Find(&competencies).Error
+
+	return competencies, errors.WrapGeneric(err)
+}
+
+func FindCompetencyByID(gctx golly.Context, id uuid.UUID) (Competency, error) {
+	var c Competency
+
+	err := orm.DB(gctx).
+		Model(&Competency{}).
+		Scopes(common.OrganizationIDScopeForContext(gctx)).
+		First(&c, "id = ?", id).
+		Error
+
+	return c, errors.WrapNotFound(err)
+}
+
+// ExpectationsForRole returns the expectations of the role at its level
+// and the next one, an employee without a role has none
+func ExpectationsForRole(gctx golly.Context, empRole EmployeeRole) (RoleExpectations, error) {
+	ret := RoleExpectations{Level: empRole.Level, Current: []Expectation{}, Next: []Expectation{}}
+
+	if empRole.ID == uuid.Nil {
+		return ret, nil
+	}
+
+	var competencies []Competency
+
+	err := orm.DB(gctx).
+		Model(&Competency{}).
+		Scopes(common.OrganizationIDScope(empRole.OrganizationID)).
+		Where("track = ?", empRole.Track).
+		Order("name").
+		Find(&competencies).
+		Error
+
+	if err != nil {
+		return ret, errors.WrapGeneric(err)
+	}
+
+	ret.Current = expectationsAt(competencies, empRole.Level)
+
+	if empRole.Level < competency.MaxLevel {
+		ret.Next = expectationsAt(competencies, empRole.Level+1)
+	}
+
+	return ret, nil
+}
+
+// ExpectationsForEmployee returns the expectations of the role of the
+// employee, it does not check permissions so it can back the public
+// feedback form
+func ExpectationsForEmployee(gctx golly.Context, employeeID uuid.UUID) (RoleExpectations, error) {
+	var empRole EmployeeRole
+
+	err := orm.DB(gctx).
+		Model(&EmployeeRole{}).
+		Joins("JOIN employees ON employees.employee_role_id = employee_roles.id").
+		Where("employees.id = ?", employeeID).
+		Find(&empRole).
+		Error
+
+	if err != nil {
+		return RoleExpectations{}, errors.WrapGeneric(err)
+	}
+
+	return ExpectationsForRole(gctx, empRole)
+}
+
+// CompetencyLevelDiff compares the expectations of every competency of
+// the track between two levels
+func CompetencyLevelDiff(gctx golly.Context, track role.EmployeeType, from, to int) ([]LevelDiff, error) {
+	competencies, err := FindCompetencies(gctx, track)
+	if err != nil {
+		return nil, err
+	}
+
+	return golly.Map(competencies, func(c Competency) LevelDiff {
+		fromLevel, _ := c.Levels.For(from)
+		toLevel, _ := c.Levels.For(to)
+
+		return LevelDiff{
+			CompetencyID: c.ID,
+			Competency:   c.Name,
+			From:         fromLevel.Expectations,
+			To:           toLevel.Expectations,
+		}
+	}), nil
+}
+
+func expectationsAt(competencies []Competency, level int) []Expectation {
+	ret := []Expectation{}
+
+	for _, c := range competencies {
+		if lvl, ok := c.Levels.For(level); ok {
+			ret = append(ret, Expectation{
+				CompetencyID: c.ID,
+				Competency:   c.Name,
+				Description:  c.Description,
+				Level:        lvl.Level,
+				Expectations: lvl.Expectations,
+			})
+		}
+	}
+
+	return ret
+}
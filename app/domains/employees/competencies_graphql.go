@@ -0,0 +1,355 @@
+package employees
+
+import (
+	"github.com/golly-go/golly"
+	"github.com/golly-go/plugins/eventsource"
+	"github.com/golly-go/plugins/gql"
+	"github.com/graphql-go/graphql"
+	"github.com/mitchrodrigues/talent-review-backend/app/domains/accounts"
+	"github.com/mitchrodrigues/talent-review-backend/app/domains/employees/competency"
+	"github.com/mitchrodrigues/talent-review-backend/app/domains/employees/role"
+	"github.com/mitchrodrigues/talent-review-backend/app/utils/helpers"
+	"github.com/mitchrodrigues/talent-review-backend/app/utils/identity"
+)
+
+var (
+	careerTrackType = graphql.NewEnum(graphql.EnumConfig{
+		Name: "CareerTrack",
+		Values: graphql.EnumValueConfigMap{
+			"ic":      {Value: string(role.IC)},
+			"manager": {Value: string(role.Manager)},
+		},
+	})
+
+	competencyLevelType = graphql.NewObject(graphql.ObjectConfig{
+		Name: "CompetencyLevel",
+		Fields: graphql.Fields{
+			"level": {
+				Type: graphql.Int,
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					return p.Source.(competency.Level).Level, nil
+				},
+			},
+			"expectations": {
+				Type: graphql.String,
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					return p.Source.(competency.Level).Expectations, nil
+				},
+			},
+		},
+	})
+
+	competencyType = graphql.NewObject(graphql.ObjectConfig{
+		Name: "Competency",
+		Fields: graphql.Fields{
+			"id": {
+				Type: graphql.NewNonNull(graphql.String),
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					return p.Source.(Competency).ID, nil
+				},
+			},
+			"name": {
+				Type: graphql.String,
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					return p.Source.(Competency).Name, nil
+				},
+			},
+			"description": {
+				Type: graphql.String,
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					return p.Source.(Competency).Description, nil
+				},
+			},
+			"track": {
+				Type: careerTrackType,
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					return string(p.Source.(Competency).Track), nil
+				},
+			},
+			"levels": {
+				Type: graphql.NewList(competencyLevelType),
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					return []competency.Level(p.Source.(Competency).Levels), nil
+				},
+			},
+		},
+	})
+
+	competencyExpectationType = graphql.NewObject(graphql.ObjectConfig{
+		Name: "CompetencyExpectation",
+		Fields: graphql.Fields{
+			"competencyID": {
+				Type: graphql.String,
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					return p.Source.(Expectation).CompetencyID, nil
+				},
+			},
+			"competency": {
+				Type: graphql.String,
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					return p.Source.(Expectation).Competency, nil
+				},
+			},
+			"description": {
+				Type: graphql.String,
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					return p.Source.(Expectation).Description, nil
+				},
+			},
+			"level": {
+				Type: graphql.Int,
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					return p.Source.(Expectation).Level, nil
+				},
+			},
+			"expectations": {
+				Type: graphql.String,
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					return p.Source.(Expectation).Expectations, nil
+				},
+			},
+		},
+	})
+
+	RoleExpectationsGQLType = graphql.NewObject(graphql.ObjectConfig{
+		Name: "RoleExpectations",
+		Fields: graphql.Fields{
+			"level": {
+				Type: graphql.Int,
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					return p.Source.(RoleExpectations).Level, nil
+				},
+			},
+			"current": {
+				Type:        graphql.NewList(competencyExpectationType),
+				Description: "Expected behaviors at the current level",
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					return p.Source.(RoleExpectations).Current, nil
+				},
+			},
+			"next": {
+				Type:        graphql.NewList(competencyExpectationType),
+				Description: "Expected behaviors at the next level",
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					return p.Source.(RoleExpectations).Next, nil
+				},
+			},
+		},
+	})
+
+	competencyLevelDiffType = graphql.NewObject(graphql.ObjectConfig{
+		Name: "CompetencyLevelDiff",
+		Fields: graphql.Fields{
+			"competencyID": {
+				Type: graphql.String,
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					return p.Source.(LevelDiff).CompetencyID, nil
+				},
+			},
+			"competency": {
+				Type: graphql.String,
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					return p.Source.(LevelDiff).Competency, nil
+				},
+			},
+			"from": {
+				Type: graphql.String,
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					return p.Source.(LevelDiff).From, nil
+				},
+			},
+			"to": {
+				Type: graphql.String,
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					return p.Source.(LevelDiff).To, nil
+				},
+			},
+			"changed": {
+				Type: graphql.Boolean,
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					return p.Source.(LevelDiff).Changed(), nil
+				},
+			},
+		},
+	})
+
+	competencyLevelInputType = graphql.NewInputObject(graphql.InputObjectConfig{
+		Name: "CompetencyLevelInput",
+		Fields: graphql.InputObjectConfigFieldMap{
+			"level":        {Type: graphql.NewNonNull(graphql.Int)},
+			"expectations": {Type: graphql.String, Description: "Empty removes the level"},
+		},
+	})
+
+	createCompetencyInputType = graphql.NewInputObject(graphql.InputObjectConfig{
+		Name: "CreateCompetencyInput",
+		Fields: graphql.InputObjectConfigFieldMap{
+			"name":        {Type: graphql.NewNonNull(graphql.String)},
+			"description": {Type: graphql.String},
+			"track":       {Type: graphql.NewNonNull(careerTrackType)},
+			"levels":      {Type: graphql.NewList(competencyLevelInputType)},
+		},
+	})
+
+	updateCompetencyInputType = graphql.NewInputObject(graphql.InputObjectConfig{
+		Name: "UpdateCompetencyInput",
+		Fields: graphql.InputObjectConfigFieldMap{
+			"name":        {Type: graphql.String},
+			"description": {Type: graphql.String},
+			"levels":      {Type: graphql.NewList(competencyLevelInputType), Description: "Replaces the levels given, other levels are kept"},
+		},
+	})
+
+	competencyQueries = graphql.Fields{
+		"competencies": &graphql.Field{
+			Type: graphql.NewList(competencyType),
+			Args: graphql.FieldConfigArgument{
+				"track": {Type: careerTrackType, Description: "All tracks when empty"},
+			},
+			Resolve: gql.NewHandler(gql.Options{
+				Handler: func(wctx golly.WebContext, params gql.Params) (interface{}, error) {
+					track, _ := helpers.ExtractArg[string](params.Args, "track")
+
+					return FindCompetencies(wctx.Context, role.EmployeeType(track))
+				},
+			}),
+		},
+		"competency": &graphql.Field{
+			Type: competencyType,
+			Args: graphql.FieldConfigArgument{
+				"id": {Type: graphql.NewNonNull(graphql.String)},
+			},
+			Resolve: gql.NewHandler(gql.Options{
+				Handler: func(wctx golly.WebContext, params gql.Params) (interface{}, error) {
+					id, err := helpers.ExtractAndParseUUID(params.Args, "id")
+					if err != nil {
+						return nil, err
+					}
+
+					return FindCompetencyByID(wctx.Context, id)
+				},
+			}),
+		},
+		"competencyLevelDiff": &graphql.Field{
+			Type:        graphql.NewList(competencyLevelDiffType),
+			Description: "What each competency of the track expects at two levels",
+			Args: graphql.FieldConfigArgument{
+				"track":     {Type: graphql.NewNonNull(careerTrackType)},
+				"fromLevel": {Type: graphql.NewNonNull(graphql.Int)},
+				"toLevel":   {Type: graphql.NewNonNull(graphql.Int)},
+			},
+			Resolve: gql.NewHandler(gql.Options{
+				Handler: func(wctx golly.WebContext, params gql.Params) (interface{}, error) {
+					return CompetencyLevelDiff(wctx.Context,
+						role.EmployeeType(params.Args["track"].(string)),
+						params.Args["fromLevel"].(int),
+						params.Args["toLevel"].(int))
+				},
+			}),
+		},
+	}
+
+	competencyMutations = graphql.Fields{
+		"createCompetency": &graphql.Field{
+			Type: competencyType,
+			Args: graphql.FieldConfigArgument{
+				"input": &graphql.ArgumentConfig{Type: graphql.NewNonNull(createCompetencyInputType)},
+			},
+			Resolve: gql.NewHandler(gql.Options{
+				Handler: func(wctx golly.WebContext, params gql.Params) (interface{}, error) {
+					if _, err := accounts.RequireAdmin(wctx.Context); err != nil {
+						return nil, err
+					}
+
+					var c Competency
+
+					description, _ := helpers.ExtractArg[string](params.Input, "description")
+
+					err := eventsource.Call(wctx.Context, &c.Aggregate, competency.Create{
+						OrganizationID: identity.FromContext(wctx.Context).OrganizationID,
+						Name:           params.Input["name"].(string),
+						Description:    description,
+						Track:          role.EmployeeType(params.Input["track"].(string)),
+						Levels:         parseCompetencyLevels(params.Input),
+					}, params.Metadata())
+
+					return c, err
+				},
+			}),
+		},
+		"updateCompetency": &graphql.Field{
+			Type: competencyType,
+			Args: graphql.FieldConfigArgument{
+				"id":    &graphql.ArgumentConfig{Type: graphql.NewNonNull(graphql.String)},
+				"input": &graphql.ArgumentConfig{Type: graphql.NewNonNull(updateCompetencyInputType)},
+			},
+			Resolve: gql.NewHandler(gql.Options{
+				Handler: func(wctx golly.WebContext, params gql.Params) (interface{}, error) {
+					if _, err := accounts.RequireAdmin(wctx.Context); err != nil {
+						return nil, err
+					}
+
+					id, err := helpers.ExtractAndParseUUID(params.Args, "id")
+					if err != nil {
+						return nil, err
+					}
+
+					c, err := FindCompetencyByID(wctx.Context, id)
+					if err != nil {
+						return nil, err
+					}
+
+					cmd := competency.Update{Levels: parseCompetencyLevels(params.Input)}
+
+					if name, err := helpers.ExtractArg[string](params.Input, "name"); err == nil {
+						cmd.Name = &name
+					}
+
+					if description, err := helpers.ExtractArg[string](params.Input, "description"); err == nil {
+						cmd.Description = &description
+					}
+
+					err = eventsource.Call(wctx.Context, &c.Aggregate, cmd, params.Metadata())
+					return c, err
+				},
+			}),
+		},
+		"deleteCompetency": &graphql.Field{
+			Type: competencyType,
+			Args: graphql.FieldConfigArgument{
+				"id": &graphql.ArgumentConfig{Type: graphql.NewNonNull(graphql.String)},
+			},
+			Resolve: gql.NewHandler(gql.Options{
+				Handler: func(wctx golly.WebContext, params gql.Params) (interface{}, error) {
+					if _, err := accounts.RequireAdmin(wctx.Context); err != nil {
+						return nil, err
+					}
+
+					id, err := helpers.ExtractAndParseUUID(params.Args, "id")
+					if err != nil {
+						return nil, err
+					}
+
+					c, err := FindCompetencyByID(wctx.Context, id)
+					if err != nil {
+						return nil, err
+					}
+
+					err = eventsource.Call(wctx.Context, &c.Aggregate, competency.Delete{}, params.Metadata())
+					return c, err
+				},
+			}),
+		},
+	}
+)
+
+func parseCompetencyLevels(input map[string]interface{}) competency.Levels {
+	levels, _ := helpers.ExtractArg[[]interface{}](input, "levels")
+
+	return golly.Map(levels, func(item interface{}) competency.Level {
+		fields := item.(map[string]interface{})
+		expectations, _ := helpers.ExtractArg[string](fields, "expectations")
+
+		return competency.Level{Level: fields["level"].(int), Expectations: expectations}
+	})
+}
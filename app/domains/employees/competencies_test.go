@@ -0,0 +1,84 @@
+package employees
+
+import (
+	"context"
+	"testing"
+
+	"github.com/golly-go/golly"
+	"github.com/golly-go/plugins/orm"
+	"github.com/google/uuid"
+	"github.com/mitchrodrigues/talent-review-backend/app/domains/employees/competency"
+	"github.com/mitchrodrigues/talent-review-backend/app/domains/employees/role"
+	"github.com/mitchrodrigues/talent-review-backend/app/utils/identity"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCompetencyExpectations(t *testing.T) {
+	gctx := orm.CreateTestContext(golly.NewContext(context.TODO()), Competency{}, EmployeeRole{}, Employee{})
+
+	organizationID := uuid.New()
+	gctx = identity.ToContext(gctx, identity.Identity{UID: uuid.New(), OrganizationID: organizationID})
+
+	createCompetency := func(orgID uuid.UUID, name string, track role.EmployeeType, levels competency.Levels) Competency {
+		c := Competency{Aggregate: competency.Aggregate{OrganizationID: orgID, Name: name, Track: track, Levels: levels}}
+		orm.DB(gctx).Create(&c)
+		return c
+	}
+
+	execution := createCompetency(organizationID, "Execution", role.IC, competency.Levels{
+		{Level: 3, Expectations: "Delivers features"},
+		{Level: 4, Expectations: "Delivers projects"},
+	})
+	createCompetency(organizationID, "Impact", role.IC, competency.Levels{{Level: 3, Expectations: "Improves the team"}})
+	createCompetency(organizationID, "People", role.Manager, competency.Levels{{Level: 3, Expectations: "Grows reports"}})
+	createCompetency(uuid.New(), "Execution", role.IC, competency.Levels{{Level: 3, Expectations: "Other org"}})
+
+	empRole := EmployeeRole{Aggregate: role.Aggregate{OrganizationID: organizationID, Title: "Engineer", Level: 3, Track: role.IC}}
+	orm.DB(gctx).Create(&empRole)
+
+	emp := NewTestEmployee(uuid.New(), organizationID, "jane@example.com", nil)
+	emp.EmployeeRoleID = empRole.ID
+	orm.DB(gctx).Create(&emp)
+
+	t.Run("role expectations", func(t *testing.T) {
+		expectations, err := ExpectationsForEmployee(gctx, emp.ID)
+		assert.NoError(t, err)
+
+		assert.Equal(t, 3, expectations.Level)
+		assert.Equal(t, []string{
+			"Execution (level 3): Delivers features",
+			"Impact (level 3): Improves the team",
+			"Execution (level 4): Delivers projects",
+		}, expectations.Strings())
+		assert.Equal(t, execution.ID, expectations.Current[0].CompetencyID)
+	})
+
+	t.Run("employee without a role", func(t *testing.T) {
+		expectations, err := ExpectationsForEmployee(gctx, uuid.New())
+		assert.NoError(t, err)
+		assert.Empty(t, expectations.Strings())
+	})
+
+	t.Run("level diff", func(t *testing.T) {
+		diff, err := CompetencyLevelDiff(gctx, role.IC, 3, 4)
+		assert.NoError(t, err)
+
+		if assert.Len(t, diff, 2) {
+			assert.Equal(t, LevelDiff{CompetencyID: execution.ID, Competency: "Execution", From: "Delivers features", To: "Delivers projects"}, diff[0])
+			assert.True(t, diff[0].Changed())
+
+			assert.Equal(t, "Improves the team", diff[1].From)
+			assert.Equal(t, "", diff[1].To)
+		}
+	})
+
+	t.Run("competencies of a track", func(t *testing.T) {
+		competencies, err := FindCompetencies(gctx, role.Manager)
+		assert.NoError(t, err)
+		assert.Len(t, competencies, 1)
+
+		competencies, err = FindCompetencies(gctx, "")
+		assert.NoError(t, err)
+		assert.Len(t, competencies, 3)
+	})
+}
@@ -0,0 +1,127 @@
+package competency
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	"github.com/golly-go/golly"
+	"github.com/golly-go/plugins/eventsource"
+	"github.com/golly-go/plugins/orm"
+	"github.com/google/uuid"
+	"github.com/mitchrodrigues/talent-review-backend/app/domains/employees/role"
+	"github.com/mitchrodrigues/talent-review-backend/app/utils/esbackend"
+	"gorm.io/gorm"
+)
+
+const (
+	MinLevel = 0
+	MaxLevel = 11
+)
+
+// Aggregate is a competency of the career framework of an organization,
+// it describes the behaviors expected at each level of a track and
+// applies to every role of that track
+type Aggregate struct {
+	eventsource.AggregateBase
+
+	orm.ModelUUID
+
+	OrganizationID uuid.UUID
+
+	Name        string
+	Description string
+	Track       role.EmployeeType
+
+	Levels Levels `gorm:"type:jsonb"`
+}
+
+func (*Aggregate) Topic() string                             { return "events.competencies" }
+func (*Aggregate) Repo(golly.Context) eventsource.Repository { return esbackend.PostgresRepository{} }
+func (*Aggregate) TableName() string                         { return "competencies" }
+
+func (competency *Aggregate) GetID() string   { return competency.ID.String() }
+func (competency *Aggregate) SetID(id string) { competency.ID, _ = uuid.Parse(id) }
+
+func (competency *Aggregate) Apply(ctx golly.Context, evt eventsource.Event) {
+	switch event := evt.Data.(type) {
+	case Created:
+		competency.ID = event.ID
+		competency.OrganizationID = event.OrganizationID
+		competency.Name = event.Name
+		competency.Description = event.Description
+		competency.Track = event.Track
+
+		competency.CreatedAt = evt.CreatedAt
+
+	case Updated:
+		competency.Name = event.Name
+		competency.Description = event.Description
+
+	case LevelSet:
+		competency.Levels = competency.Levels.Set(event.Level, event.Expectations)
+
+	case Deleted:
+		competency.DeletedAt = gorm.DeletedAt{Time: evt.CreatedAt, Valid: true}
+	}
+	competency.UpdatedAt = evt.CreatedAt
+}
+
+// Level is what the competency expects of an employee at a level
+type Level struct {
+	Level        int    `json:"level"`
+	Expectations string `json:"expectations"`
+}
+
+// Levels are stored as jsonb ordered by level
+type Levels []Level
+
+// For returns the expectations at the level
+func (l Levels) For(level int) (Level, bool) {
+	for _, lvl := range l {
+		if lvl.Level == level {
+			return lvl, true
+		}
+	}
+	return Level{}, false
+}
+
+// Set replaces the expectations of a level, empty expectations remove it
+func (l Levels) Set(level int, expectations string) Levels {
+	ret := Levels{}
+	for _, lvl := range l {
+		if lvl.Level != level {
+			ret = append(ret, lvl)
+		}
+	}
+
+	if expectations != "" {
+		ret = append(ret, Level{Level: level, Expectations: expectations})
+	}
+
+	sort.Slice(ret, func(i, j int) bool { return ret[i].Level < ret[j].Level })
+
+	return ret
+}
+
+func (l Levels) Value() (driver.Value, error) {
+	if l == nil {
+		return []byte("[]"), nil
+	}
+	return json.Marshal(l)
+}
+
+func (l *Levels) Scan(value interface{}) error {
+	switch v := value.(type) {
+	case nil:
+		return nil
+	case []byte:
+		return json.Unmarshal(v, l)
+	case string:
+		return json.Unmarshal([]byte(v), l)
+	}
+	return fmt.Errorf("cannot scan %T into levels", value)
+}
+
+var _ eventsource.Aggregate = &Aggregate{}
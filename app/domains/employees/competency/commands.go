@@ -0,0 +1,145 @@
+package competency
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/golly-go/golly"
+	"github.com/golly-go/golly/errors"
+	"github.com/golly-go/plugins/eventsource"
+	"github.com/golly-go/plugins/orm"
+	"github.com/google/uuid"
+	"github.com/mitchrodrigues/talent-review-backend/app/domains/employees/role"
+)
+
+var (
+	ErrorNameRequired     = fmt.Errorf("competency name is required")
+	ErrorInvalidTrack     = fmt.Errorf("competency track must be IC or MNG")
+	ErrorInvalidLevel     = fmt.Errorf("competency level must be between %d and %d", MinLevel, MaxLevel)
+	ErrorCompetencyExists = fmt.Errorf("competency exists")
+	ErrorNotCreated       = fmt.Errorf("competency has not been created")
+)
+
+type Create struct {
+	OrganizationID uuid.UUID
+
+	Name        string
+	Description string
+	Track       role.EmployeeType
+
+	Levels Levels
+}
+
+func (cmd Create) Validate(ctx golly.Context, aggregate eventsource.Aggregate) error {
+	if strings.TrimSpace(cmd.Name) == "" {
+		return errors.WrapUnprocessable(ErrorNameRequired)
+	}
+
+	if cmd.Track != role.IC && cmd.Track != role.Manager {
+		return errors.WrapUnprocessable(ErrorInvalidTrack)
+	}
+
+	if err := validateLevels(cmd.Levels); err != nil {
+		return err
+	}
+
+	var existing Aggregate
+
+	orm.DB(ctx).Model(&existing).Find(&existing, map[string]interface{}{
+		"name":            strings.TrimSpace(cmd.Name),
+		"track":           cmd.Track,
+		"organization_id": cmd.OrganizationID,
+	})
+
+	if existing.ID != uuid.Nil {
+		return errors.WrapInvalidFields(ErrorCompetencyExists)
+	}
+
+	return nil
+}
+
+func (cmd Create) Perform(ctx golly.Context, aggregate eventsource.Aggregate) error {
+	id, _ := uuid.NewV7()
+
+	eventsource.Apply(ctx, aggregate, Created{
+		ID:             id,
+		OrganizationID: cmd.OrganizationID,
+		Name:           strings.TrimSpace(cmd.Name),
+		Description:    cmd.Description,
+		Track:          cmd.Track,
+	})
+
+	for _, level := range cmd.Levels {
+		eventsource.Apply(ctx, aggregate, LevelSet{Level: level.Level, Expectations: level.Expectations})
+	}
+
+	return nil
+}
+
+// Update changes the competency, nil fields keep their current value and
+// the levels given replace the expectations of those levels
+type Update struct {
+	Name        *string
+	Description *string
+
+	Levels Levels
+}
+
+func (cmd Update) Validate(ctx golly.Context, aggregate eventsource.Aggregate) error {
+	if aggregate.(*Aggregate).ID == uuid.Nil {
+		return errors.WrapUnprocessable(ErrorNotCreated)
+	}
+
+	if cmd.Name != nil && strings.TrimSpace(*cmd.Name) == "" {
+		return errors.WrapUnprocessable(ErrorNameRequired)
+	}
+
+	return validateLevels(cmd.Levels)
+}
+
+func (cmd Update) Perform(ctx golly.Context, aggregate eventsource.Aggregate) error {
+	competency := aggregate.(*Aggregate)
+
+	if cmd.Name != nil || cmd.Description != nil {
+		evt := Updated{Name: competency.Name, Description: competency.Description}
+
+		if cmd.Name != nil {
+			evt.Name = strings.TrimSpace(*cmd.Name)
+		}
+
+		if cmd.Description != nil {
+			evt.Description = *cmd.Description
+		}
+
+		eventsource.Apply(ctx, aggregate, evt)
+	}
+
+	for _, level := range cmd.Levels {
+		eventsource.Apply(ctx, aggregate, LevelSet{Level: level.Level, Expectations: level.Expectations})
+	}
+
+	return nil
+}
+
+type Delete struct{}
+
+func (Delete) Validate(ctx golly.Context, aggregate eventsource.Aggregate) error {
+	if aggregate.(*Aggregate).ID == uuid.Nil {
+		return errors.WrapUnprocessable(ErrorNotCreated)
+	}
+	return nil
+}
+
+func (Delete) Perform(ctx golly.Context, aggregate eventsource.Aggregate) error {
+	eventsource.Apply(ctx, aggregate, Deleted{})
+	return nil
+}
+
+func validateLevels(levels Levels) error {
+	for _, level := range levels {
+		if level.Level < MinLevel || level.Level > MaxLevel {
+			return errors.WrapUnprocessable(ErrorInvalidLevel)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,85 @@
+package competency
+
+import (
+	"context"
+	"testing"
+
+	"github.com/golly-go/golly"
+	"github.com/golly-go/plugins/orm"
+	"github.com/google/uuid"
+	"github.com/mitchrodrigues/talent-review-backend/app/domains/employees/role"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCreateValidate(t *testing.T) {
+	gctx := orm.CreateTestContext(golly.NewContext(context.TODO()), Aggregate{})
+
+	organizationID := uuid.New()
+
+	existing := Aggregate{OrganizationID: organizationID, Name: "Execution", Track: role.IC}
+	orm.DB(gctx).Create(&existing)
+
+	tests := []struct {
+		name      string
+		cmd       Create
+		expectErr bool
+	}{
+		{name: "valid", cmd: Create{OrganizationID: organizationID, Name: "Impact", Track: role.IC}},
+		{name: "same name on another track", cmd: Create{OrganizationID: organizationID, Name: "Execution", Track: role.Manager}},
+		{name: "exists", cmd: Create{OrganizationID: organizationID, Name: "Execution", Track: role.IC}, expectErr: true},
+		{name: "missing name", cmd: Create{OrganizationID: organizationID, Name: " ", Track: role.IC}, expectErr: true},
+		{name: "invalid track", cmd: Create{OrganizationID: organizationID, Name: "Impact", Track: "X"}, expectErr: true},
+		{name: "invalid level", cmd: Create{OrganizationID: organizationID, Name: "Impact", Track: role.IC, Levels: Levels{{Level: 12}}}, expectErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.cmd.Validate(gctx, &Aggregate{})
+			if tt.expectErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestPerform(t *testing.T) {
+	gctx := golly.NewContext(context.TODO())
+
+	agg := &Aggregate{}
+
+	assert.NoError(t, Create{
+		Name:  " Impact ",
+		Track: role.IC,
+		Levels: Levels{
+			{Level: 3, Expectations: "Owns projects"},
+			{Level: 2, Expectations: "Owns features"},
+		},
+	}.Perform(gctx, agg))
+
+	assert.NotEqual(t, uuid.Nil, agg.ID)
+	assert.Equal(t, "Impact", agg.Name)
+	assert.Equal(t, Levels{{Level: 2, Expectations: "Owns features"}, {Level: 3, Expectations: "Owns projects"}}, agg.Levels)
+
+	description := "Delivers results"
+
+	assert.NoError(t, Update{
+		Description: &description,
+		Levels:      Levels{{Level: 2}, {Level: 4, Expectations: "Owns areas"}},
+	}.Perform(gctx, agg))
+
+	assert.Equal(t, "Impact", agg.Name)
+	assert.Equal(t, "Delivers results", agg.Description)
+	assert.Equal(t, Levels{{Level: 3, Expectations: "Owns projects"}, {Level: 4, Expectations: "Owns areas"}}, agg.Levels)
+
+	level, ok := agg.Levels.For(4)
+	assert.True(t, ok)
+	assert.Equal(t, "Owns areas", level.Expectations)
+
+	_, ok = agg.Levels.For(2)
+	assert.False(t, ok)
+
+	assert.NoError(t, Delete{}.Perform(gctx, agg))
+	assert.True(t, agg.DeletedAt.Valid)
+}
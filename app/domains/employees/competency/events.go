@@ -0,0 +1,26 @@
+package competency
+
+import (
+	"github.com/google/uuid"
+	"github.com/mitchrodrigues/talent-review-backend/app/domains/employees/role"
+)
+
+type Created struct {
+	ID             uuid.UUID         `json:"id"`
+	OrganizationID uuid.UUID         `json:"organizationID"`
+	Name           string            `json:"name"`
+	Description    string            `json:"description"`
+	Track          role.EmployeeType `json:"track"`
+}
+
+type Updated struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+}
+
+type LevelSet struct {
+	Level        int    `json:"level"`
+	Expectations string `json:"expectations"`
+}
+
+type Deleted struct{}
@@ -1,12 +1,16 @@
 package employee
 
 import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
 	"time"
 
 	"github.com/golly-go/golly"
 	"github.com/golly-go/plugins/eventsource"
 	"github.com/golly-go/plugins/orm"
 	"github.com/google/uuid"
+	"github.com/mitchrodrigues/talent-review-backend/app/utils/crypto"
 	"github.com/mitchrodrigues/talent-review-backend/app/utils/esbackend"
 )
 
@@ -49,6 +53,22 @@ type ChangeData struct {
 	Field    string      `json:"field"`
 }
 
+func (c ChangeData) Value() (driver.Value, error) {
+	return json.Marshal(c)
+}
+
+func (c *ChangeData) Scan(value interface{}) error {
+	switch v := value.(type) {
+	case nil:
+		return nil
+	case []byte:
+		return json.Unmarshal(v, c)
+	case string:
+		return json.Unmarshal([]byte(v), c)
+	}
+	return fmt.Errorf("cannot scan %T into change data", value)
+}
+
 type Aggregate struct {
 	eventsource.AggregateBase
 
@@ -66,7 +86,14 @@ type Aggregate struct {
 
 	EmployeeRoleID uuid.UUID
 
+	// LevelStartAt is when the employee started at the level of their
+	// role, it is set when a promotion takes effect
+	LevelStartAt *time.Time
+
 	TerminatedAt *time.Time
+
+	// ErasedAt is set once the personal data of the employee was erased
+	ErasedAt *time.Time
 }
 
 func (*Aggregate) Topic() string                             { return "events.employees" }
@@ -105,8 +132,17 @@ func (employee *Aggregate) Apply(ctx golly.Context, evt eventsource.Event) {
 	case RoleUpdated:
 		employee.EmployeeRoleID = event.EmployeeRoleID
 
+		if event.LevelStartAt != nil {
+			employee.LevelStartAt = event.LevelStartAt
+		}
+
 	case Terminate:
 		employee.TerminatedAt = &event.TerminatedAt
+
+	case Erased:
+		employee.Name = crypto.Erased
+		employee.Email = crypto.Erased
+		employee.ErasedAt = &event.ErasedAt
 	}
 
 	employee.UpdatedAt = evt.CreatedAt
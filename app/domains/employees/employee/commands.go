@@ -3,8 +3,10 @@ package employee
 import (
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/golly-go/golly"
+	"github.com/golly-go/golly/errors"
 	"github.com/golly-go/plugins/eventsource"
 	"github.com/golly-go/plugins/orm"
 	"github.com/google/uuid"
@@ -13,6 +15,11 @@ import (
 	"github.com/mitchrodrigues/talent-review-backend/app/utils/helpers"
 )
 
+var (
+	ErrorInvalidPromotion = fmt.Errorf("promotion needs a role and an effective date")
+	ErrorAlreadyErased    = fmt.Errorf("employee was already erased")
+)
+
 type Create struct {
 	Name  string `validate:"required"`
 	Email string `validate:"email"`
@@ -58,7 +65,7 @@ func (cmd Create) Perform(ctx golly.Context, aggregate eventsource.Aggregate) er
 	}
 
 	if cmd.EmployeeRoleID != uuid.Nil {
-		eventsource.Apply(ctx, aggregate, RoleUpdated{cmd.EmployeeRoleID})
+		eventsource.Apply(ctx, aggregate, RoleUpdated{EmployeeRoleID: cmd.EmployeeRoleID})
 	}
 
 	if cmd.ManagerID != uuid.Nil {
@@ -106,7 +113,7 @@ func (cmd Update) Perform(ctx golly.Context, aggregate eventsource.Aggregate) er
 	}
 
 	if cmd.EmployeeRoleID != uuid.Nil {
-		eventsource.Apply(ctx, aggregate, RoleUpdated{cmd.EmployeeRoleID})
+		eventsource.Apply(ctx, aggregate, RoleUpdated{EmployeeRoleID: cmd.EmployeeRoleID})
 	}
 
 	return nil
@@ -120,3 +127,46 @@ func (cmd UpdateUser) Perform(gctx golly.Context, aggregate eventsource.Aggregat
 	eventsource.Apply(gctx, aggregate, UserUpdated(cmd))
 	return nil
 }
+
+// Promote moves the employee to the role of an approved promotion, the
+// effective date is recorded as the start of their level
+type Promote struct {
+	PromotionID    uuid.UUID
+	EmployeeRoleID uuid.UUID
+	EffectiveAt    time.Time
+}
+
+func (cmd Promote) Validate(ctx golly.Context, aggregate eventsource.Aggregate) error {
+	if cmd.EmployeeRoleID == uuid.Nil || cmd.EffectiveAt.IsZero() {
+		return errors.WrapUnprocessable(ErrorInvalidPromotion)
+	}
+	return nil
+}
+
+func (cmd Promote) Perform(ctx golly.Context, aggregate eventsource.Aggregate) error {
+	eventsource.Apply(ctx, aggregate, RoleUpdated{
+		EmployeeRoleID: cmd.EmployeeRoleID,
+		LevelStartAt:   &cmd.EffectiveAt,
+		PromotionID:    &cmd.PromotionID,
+	})
+	return nil
+}
+
+// Erase replaces the personal data of the employee with crypto.Erased,
+// the key sealing it in the event store is destroyed by the caller
+type Erase struct{}
+
+func (cmd Erase) Validate(ctx golly.Context, aggregate eventsource.Aggregate) error {
+	if aggregate.(*Aggregate).ErasedAt != nil {
+		return errors.WrapUnprocessable(ErrorAlreadyErased)
+	}
+	return nil
+}
+
+func (cmd Erase) Perform(ctx golly.Context, aggregate eventsource.Aggregate) error {
+	eventsource.Apply(ctx, aggregate, Erased{
+		ErasedAt: time.Now(),
+		Email:    aggregate.(*Aggregate).Email,
+	})
+	return nil
+}
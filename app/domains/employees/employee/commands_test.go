@@ -3,11 +3,13 @@ package employee
 import (
 	"context"
 	"testing"
+	"time"
 
 	"github.com/golly-go/golly"
 	"github.com/golly-go/plugins/orm"
 	"github.com/google/uuid"
 	"github.com/mitchrodrigues/talent-review-backend/app/domains/accounts"
+	"github.com/mitchrodrigues/talent-review-backend/app/utils/crypto"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -75,3 +77,49 @@ func TestCreateEmployeePerform(t *testing.T) {
 		})
 	}
 }
+
+func TestPromote(t *testing.T) {
+	ctx := golly.NewContext(context.TODO())
+
+	promotionID, roleID := uuid.New(), uuid.New()
+	effectiveAt := time.Date(2024, 7, 1, 0, 0, 0, 0, time.UTC)
+
+	assert.Error(t, Promote{PromotionID: promotionID, EffectiveAt: effectiveAt}.Validate(ctx, &Aggregate{}))
+	assert.Error(t, Promote{PromotionID: promotionID, EmployeeRoleID: roleID}.Validate(ctx, &Aggregate{}))
+
+	cmd := Promote{PromotionID: promotionID, EmployeeRoleID: roleID, EffectiveAt: effectiveAt}
+	assert.NoError(t, cmd.Validate(ctx, &Aggregate{}))
+
+	employee := &Aggregate{}
+	assert.NoError(t, cmd.Perform(ctx, employee))
+
+	assert.Equal(t, roleID, employee.EmployeeRoleID)
+	assert.Equal(t, &effectiveAt, employee.LevelStartAt)
+
+	changes := employee.Changes()
+	if assert.Len(t, changes, 1) {
+		assert.Equal(t, &promotionID, changes[0].Data.(RoleUpdated).PromotionID)
+	}
+}
+
+func TestErase(t *testing.T) {
+	ctx := golly.NewContext(context.TODO())
+
+	employee := &Aggregate{Name: "Jane Smith", Email: "jane.smith@example.com"}
+
+	cmd := Erase{}
+	assert.NoError(t, cmd.Validate(ctx, employee))
+	assert.NoError(t, cmd.Perform(ctx, employee))
+
+	assert.Equal(t, crypto.Erased, employee.Name)
+	assert.Equal(t, crypto.Erased, employee.Email)
+	assert.NotNil(t, employee.ErasedAt)
+
+	changes := employee.Changes()
+	if assert.Len(t, changes, 1) {
+		// the email is handed to subscriptions but never stored
+		assert.Equal(t, "jane.smith@example.com", changes[0].Data.(Erased).Email)
+	}
+
+	assert.ErrorContains(t, cmd.Validate(ctx, employee), ErrorAlreadyErased.Error())
+}
@@ -47,8 +47,29 @@ type WorkerTypeUpdated struct {
 
 type RoleUpdated struct {
 	EmployeeRoleID uuid.UUID
+
+	// LevelStartAt and PromotionID are set when the role changed
+	// through a promotion
+	LevelStartAt *time.Time `json:",omitempty"`
+	PromotionID  *uuid.UUID `json:",omitempty"`
 }
 
 type Terminate struct {
 	TerminatedAt time.Time
 }
+
+// Erased is applied once the personal data of the employee was forgotten,
+// Email is only kept in memory for the subscriptions scrubbing the read
+// models that reference the employee by email
+type Erased struct {
+	ErasedAt time.Time `json:"erasedAt"`
+	Email    string    `json:"-"`
+}
+
+// Names and emails are sealed with the key of the employee in the event
+// store so erasing the employee shreds them
+func (Created) PersonalFields() []string                { return []string{"name", "email"} }
+func (Updated) PersonalFields() []string                { return []string{"name", "email"} }
+func (PersonalDetailsUpdated) PersonalFields() []string { return []string{"name", "email"} }
+
+func (evt Created) DataSubjectID() uuid.UUID { return evt.ID }
@@ -10,5 +10,7 @@ func Initalizer(app golly.Application) error {
 
 	eventsource.Subscribe("users.Aggregate", "users.UserCreated", UpdateEmployeeUser)
 
+	golly.RegisterServices(&PromotionService{})
+
 	return nil
 }
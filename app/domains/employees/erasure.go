@@ -0,0 +1,69 @@
+package employees
+
+import (
+	"github.com/golly-go/golly"
+	"github.com/golly-go/golly/errors"
+	"github.com/golly-go/plugins/eventsource"
+	"github.com/golly-go/plugins/orm"
+	"github.com/google/uuid"
+	"github.com/mitchrodrigues/talent-review-backend/app/domains/accounts"
+	"github.com/mitchrodrigues/talent-review-backend/app/domains/employees/employee"
+	"github.com/mitchrodrigues/talent-review-backend/app/utils/crypto"
+)
+
+// EraseEmployee forgets the personal data of an employee for a GDPR
+// erasure request: the keys sealing their name and email in the event
+// store, as an employee, as a reviewer and as the user linked to them,
+// are destroyed, the user is scrubbed and the other read models are
+// scrubbed by the employee.Erased subscriptions. The Erased event is the audit record of
+// who erased them and when. Only admins can erase employees
+func EraseEmployee(gctx golly.Context, id uuid.UUID, metadata eventsource.Metadata) (Employee, error) {
+	if _, err := accounts.RequireAdmin(gctx); err != nil {
+		return Employee{}, err
+	}
+
+	emp, err := Service(gctx).FindEmployeeByID(gctx, id)
+	if err != nil {
+		return emp, err
+	}
+
+	if emp.ErasedAt != nil {
+		return emp, errors.WrapUnprocessable(employee.ErrorAlreadyErased)
+	}
+
+	if crypto.Provider() == nil {
+		gctx.Logger().Warnf("erasing employee %s while encryption is off, their events stay readable", emp.ID)
+	}
+
+	// the key goes first, forgetting is idempotent so a failed erasure
+	// can be retried
+	subjects := []uuid.UUID{emp.ID, crypto.EmailSubjectID(emp.OrganizationID, emp.Email)}
+	if emp.UserID != nil {
+		subjects = append(subjects, *emp.UserID)
+	}
+
+	for _, subjectID := range subjects {
+		if err := crypto.ForgetSubject(gctx.Context(), orm.DB(gctx), emp.OrganizationID, subjectID); err != nil {
+			return emp, errors.WrapGeneric(err)
+		}
+	}
+
+	if emp.UserID != nil {
+		err = orm.DB(gctx).
+			Model(&accounts.User{}).
+			Where("id = ? AND organization_id = ?", *emp.UserID, emp.OrganizationID).
+			UpdateColumns(map[string]interface{}{
+				"email":      crypto.Erased,
+				"first_name": crypto.Erased,
+				"last_name":  crypto.Erased,
+			}).
+			Error
+
+		if err != nil {
+			return emp, errors.WrapGeneric(err)
+		}
+	}
+
+	err = eventsource.Call(gctx, &emp.Aggregate, employee.Erase{}, metadata)
+	return emp, err
+}
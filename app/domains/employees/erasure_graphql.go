@@ -0,0 +1,30 @@
+package employees
+
+import (
+	"github.com/golly-go/golly"
+	"github.com/golly-go/plugins/gql"
+	"github.com/graphql-go/graphql"
+	"github.com/mitchrodrigues/talent-review-backend/app/utils/helpers"
+)
+
+var (
+	erasureMutations = graphql.Fields{
+		"eraseEmployee": &graphql.Field{
+			Type:        EmployeeGQLType,
+			Description: "Forget the personal data of an employee, their name, email and feedback read [erased] afterwards. This cannot be undone",
+			Args: graphql.FieldConfigArgument{
+				"id": &graphql.ArgumentConfig{Type: graphql.NewNonNull(graphql.String)},
+			},
+			Resolve: gql.NewHandler(gql.Options{
+				Handler: func(wctx golly.WebContext, params gql.Params) (interface{}, error) {
+					id, err := helpers.ExtractAndParseUUID(params.Args, "id")
+					if err != nil {
+						return nil, err
+					}
+
+					return EraseEmployee(wctx.Context, id, params.Metadata())
+				},
+			}),
+		},
+	}
+)
@@ -0,0 +1,160 @@
+package employees
+
+import (
+	"context"
+	"testing"
+
+	"github.com/golly-go/golly"
+	"github.com/golly-go/plugins/eventsource"
+	"github.com/golly-go/plugins/orm"
+	"github.com/google/uuid"
+	"github.com/jinzhu/gorm/dialects/postgres"
+	"github.com/mitchrodrigues/talent-review-backend/app/domains/accounts"
+	"github.com/mitchrodrigues/talent-review-backend/app/domains/accounts/users"
+	"github.com/mitchrodrigues/talent-review-backend/app/domains/employees/employee"
+	"github.com/mitchrodrigues/talent-review-backend/app/utils/crypto"
+	"github.com/mitchrodrigues/talent-review-backend/app/utils/esbackend"
+	"github.com/mitchrodrigues/talent-review-backend/app/utils/identity"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEraseEmployee(t *testing.T) {
+	defer crypto.UseProvider(crypto.NewTestKeyProvider())()
+
+	// events are only stored with a backend
+	eventsource.SetEventRepository(esbackend.Backend{})
+	defer eventsource.SetEventRepository(nil)
+
+	gctx := orm.CreateTestContext(golly.NewContext(context.TODO()),
+		Employee{}, accounts.User{}, crypto.SubjectKey{}, esbackend.Event{})
+
+	organizationID := uuid.New()
+
+	createUser := func(role string) accounts.User {
+		user := accounts.User{Aggregate: users.Aggregate{OrganizationID: organizationID, Email: uuid.NewString(), Role: role}}
+		orm.DB(gctx).Create(&user)
+		return user
+	}
+
+	admin := createUser(users.RoleAdmin)
+	member := createUser(users.RoleMember)
+
+	as := func(userID uuid.UUID) golly.Context {
+		return identity.ToContext(gctx, identity.Identity{UID: userID, OrganizationID: organizationID})
+	}
+
+	var emp Employee
+	err := eventsource.Call(as(admin.ID), &emp.Aggregate, employee.Create{
+		Name:           "Jane Smith",
+		Email:          "jane.smith@example.com",
+		OrganizationID: organizationID,
+		WorkerType:     employee.FTE,
+	}, eventsource.Metadata{})
+	assert.NoError(t, err)
+
+	storedEvents := func() []esbackend.Event {
+		var events []esbackend.Event
+		orm.DB(gctx).Model(&esbackend.Event{}).Where("aggregate_id = ?", emp.ID).Order("version").Find(&events)
+		return events
+	}
+
+	changes := func(evt esbackend.Event) string {
+		b, err := crypto.OpenPersonalData(context.TODO(), orm.DB(gctx), evt.RawData.RawMessage)
+		assert.NoError(t, err)
+		return string(b)
+	}
+
+	created := storedEvents()[0]
+	assert.NotContains(t, string(created.RawData.RawMessage), "Jane")
+	assert.Contains(t, changes(created), "Jane Smith")
+
+	// the user of the employee edits their profile
+	linked := createUser(users.RoleMember)
+	orm.DB(gctx).Model(&Employee{}).Where("id = ?", emp.ID).UpdateColumn("user_id", linked.ID)
+
+	err = eventsource.Call(as(linked.ID), &linked.Aggregate, users.EditUser{
+		FirstName: "Jane",
+		LastName:  "Smith",
+		Email:     "jane.smith@example.com",
+	}, eventsource.Metadata{})
+	assert.NoError(t, err)
+
+	userEvent := func() esbackend.Event {
+		var event esbackend.Event
+		orm.DB(gctx).Model(&esbackend.Event{}).Where("aggregate_id = ? AND type = ?", linked.ID, "users.UserUpdated").First(&event)
+		return event
+	}
+	assert.NotContains(t, string(userEvent().RawData.RawMessage), "Jane")
+	assert.Contains(t, changes(userEvent()), "Jane")
+
+	// events stored before personal data was sealed
+	legacyID := uuid.New()
+	orm.DB(gctx).Create(&esbackend.Event{
+		ModelUUID:      orm.ModelUUID{ID: uuid.New()},
+		AggregateID:    legacyID,
+		AggregateType:  "employee.Aggregate",
+		Type:           "employee.Created",
+		Version:        1,
+		RawData:        postgres.Jsonb{RawMessage: []byte(`{"id":"` + legacyID.String() + `","name":"John Doe","email":"john@example.com","organizationID":"` + organizationID.String() + `"}`)},
+		OrganizationID: &organizationID,
+	})
+
+	t.Run("events stored before sealing are sealed by the backfill", func(t *testing.T) {
+		count, err := esbackend.SealEvents(gctx, employee.Created{}, employee.Updated{})
+		assert.NoError(t, err)
+		assert.Equal(t, 1, count)
+
+		var legacy esbackend.Event
+		orm.DB(gctx).Model(&esbackend.Event{}).Where("aggregate_id = ?", legacyID).First(&legacy)
+		assert.NotContains(t, string(legacy.RawData.RawMessage), "John")
+		assert.Contains(t, changes(legacy), "John Doe")
+
+		count, err = esbackend.SealEvents(gctx, employee.Created{})
+		assert.NoError(t, err)
+		assert.Equal(t, 0, count)
+	})
+
+	t.Run("only admins erase", func(t *testing.T) {
+		_, err := EraseEmployee(as(member.ID), emp.ID, eventsource.Metadata{})
+		assert.Error(t, err)
+	})
+
+	erased, err := EraseEmployee(as(admin.ID), emp.ID, eventsource.Metadata{})
+	assert.NoError(t, err)
+	assert.Equal(t, crypto.Erased, erased.Name)
+	assert.NotNil(t, erased.ErasedAt)
+
+	t.Run("the projection is scrubbed", func(t *testing.T) {
+		reloaded, err := Service(as(admin.ID)).FindEmployeeByID(as(admin.ID), emp.ID)
+		assert.NoError(t, err)
+		assert.Equal(t, crypto.Erased, reloaded.Name)
+		assert.Equal(t, crypto.Erased, reloaded.Email)
+	})
+
+	t.Run("events read erased and record the erasure", func(t *testing.T) {
+		events := storedEvents()
+		assert.JSONEq(t,
+			`{"id":"`+emp.ID.String()+`","name":"[erased]","email":"[erased]","organizationID":"`+organizationID.String()+`"}`,
+			changes(events[0]))
+
+		last := events[len(events)-1]
+		assert.Equal(t, "employee.Erased", last.Type)
+		assert.Equal(t, admin.ID, *last.UserID)
+		assert.NotContains(t, string(last.RawData.RawMessage), "jane")
+	})
+
+	t.Run("the linked user is scrubbed and shredded", func(t *testing.T) {
+		user, err := accounts.FindUserByID(gctx, linked.ID.String())
+		assert.NoError(t, err)
+		assert.Equal(t, crypto.Erased, user.FirstName)
+		assert.Equal(t, crypto.Erased, user.LastName)
+		assert.Equal(t, crypto.Erased, user.Email)
+
+		assert.NotContains(t, changes(userEvent()), "Jane")
+	})
+
+	t.Run("cannot erase twice", func(t *testing.T) {
+		_, err := EraseEmployee(as(admin.ID), emp.ID, eventsource.Metadata{})
+		assert.ErrorContains(t, err, employee.ErrorAlreadyErased.Error())
+	})
+}
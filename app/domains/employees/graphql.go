@@ -69,6 +69,15 @@ var (
 					}
 				},
 			},
+			"expectations": {
+				Type:        RoleExpectationsGQLType,
+				Description: "Expected behaviors of the career framework at the level of the role and the next one",
+				Resolve: gql.NewHandler(gql.Options{
+					Handler: func(wctx golly.WebContext, params gql.Params) (interface{}, error) {
+						return ExpectationsForRole(wctx.Context, params.Source.(EmployeeRole))
+					},
+				}),
+			},
 		},
 	})
 
@@ -111,6 +120,20 @@ var (
 					},
 				}),
 			},
+			"levelStartAt": {
+				Type:        graphql.DateTime,
+				Description: "When the employee started at the level of their role",
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					return p.Source.(Employee).LevelStartAt, nil
+				},
+			},
+			"erasedAt": {
+				Type:        graphql.DateTime,
+				Description: "When the personal data of the employee was erased",
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					return p.Source.(Employee).ErasedAt, nil
+				},
+			},
 			"workerType": {
 				Type: graphql.NewNonNull(graphql.String),
 				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
@@ -632,6 +655,6 @@ func AddCircularDependencies() {
 func InitGraphQL() {
 	AddCircularDependencies()
 
-	gql.RegisterQuery(query)
-	gql.RegisterMutation(mutations)
+	gql.RegisterQuery(query, competencyQueries, promotionQueries)
+	gql.RegisterMutation(mutations, competencyMutations, promotionMutations, erasureMutations)
 }
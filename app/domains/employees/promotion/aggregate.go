@@ -0,0 +1,203 @@
+package promotion
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/golly-go/golly"
+	"github.com/golly-go/plugins/eventsource"
+	"github.com/golly-go/plugins/orm"
+	"github.com/google/uuid"
+	"github.com/mitchrodrigues/talent-review-backend/app/domains/tara/prompt"
+	"github.com/mitchrodrigues/talent-review-backend/app/utils/esbackend"
+)
+
+const (
+	StatusProposed  = "PROPOSED"
+	StatusApproved  = "APPROVED"
+	StatusRejected  = "REJECTED"
+	StatusWithdrawn = "WITHDRAWN"
+)
+
+// Aggregate is a promotion case, a proposal to move an employee to a new
+// role that every approver has to sign off on before it takes effect
+type Aggregate struct {
+	eventsource.AggregateBase
+
+	orm.ModelUUID
+
+	OrganizationID uuid.UUID
+	EmployeeID     uuid.UUID
+	ProposerID     uuid.UUID
+
+	CurrentRoleID uuid.UUID
+	TargetRoleID  uuid.UUID
+
+	Justification string
+
+	// Citations and PromptVersions are set when tara drafted the
+	// justification from the feedback of the employee
+	Citations      UUIDs       `gorm:"type:jsonb"`
+	PromptVersions prompt.Refs `gorm:"type:jsonb"`
+
+	EffectiveAt time.Time
+
+	ApproverIDs UUIDs     `gorm:"type:jsonb"`
+	Approvals   Approvals `gorm:"type:jsonb"`
+
+	Status    string
+	DecidedAt *time.Time
+
+	// AppliedAt is set once the employee was moved to the target role,
+	// an approved promotion is applied on or after its effective date
+	AppliedAt *time.Time
+}
+
+func (*Aggregate) Topic() string                             { return "events.promotions" }
+func (*Aggregate) Repo(golly.Context) eventsource.Repository { return esbackend.PostgresRepository{} }
+func (*Aggregate) TableName() string                         { return "promotions" }
+
+func (promotion *Aggregate) GetID() string   { return promotion.ID.String() }
+func (promotion *Aggregate) SetID(id string) { promotion.ID, _ = uuid.Parse(id) }
+
+func (promotion *Aggregate) Apply(ctx golly.Context, evt eventsource.Event) {
+	switch event := evt.Data.(type) {
+	case Proposed:
+		promotion.ID = event.ID
+		promotion.OrganizationID = event.OrganizationID
+		promotion.EmployeeID = event.EmployeeID
+		promotion.ProposerID = event.ProposerID
+		promotion.CurrentRoleID = event.CurrentRoleID
+		promotion.TargetRoleID = event.TargetRoleID
+		promotion.EffectiveAt = event.EffectiveAt
+		promotion.ApproverIDs = event.ApproverIDs
+		promotion.Approvals = Approvals{}
+		promotion.Status = StatusProposed
+
+		promotion.CreatedAt = evt.CreatedAt
+
+	case JustificationUpdated:
+		promotion.Justification = event.Justification
+		promotion.Citations = event.Citations
+		promotion.PromptVersions = event.PromptVersions
+
+	case EffectiveAtUpdated:
+		promotion.EffectiveAt = event.EffectiveAt
+
+	case ApprovalRecorded:
+		promotion.Approvals = append(promotion.Approvals, Approval{
+			UserID:    event.UserID,
+			Approved:  event.Approved,
+			Comment:   event.Comment,
+			CreatedAt: evt.CreatedAt,
+		})
+
+	case Approved:
+		promotion.Status = StatusApproved
+		promotion.DecidedAt = &evt.CreatedAt
+
+	case Rejected:
+		promotion.Status = StatusRejected
+		promotion.DecidedAt = &evt.CreatedAt
+
+	case Withdrawn:
+		promotion.Status = StatusWithdrawn
+		promotion.DecidedAt = &evt.CreatedAt
+
+	case Applied:
+		promotion.AppliedAt = &evt.CreatedAt
+	}
+	promotion.UpdatedAt = evt.CreatedAt
+}
+
+func (promotion *Aggregate) Open() bool { return promotion.Status == StatusProposed }
+
+// Due is true when the promotion is approved, not applied yet and its
+// effective date has passed
+func (promotion *Aggregate) Due(now time.Time) bool {
+	return promotion.Status == StatusApproved && promotion.AppliedAt == nil && !promotion.EffectiveAt.After(now)
+}
+
+// IsApprover is true when the user has to sign off on the promotion
+func (promotion *Aggregate) IsApprover(userID uuid.UUID) bool {
+	return slices.Contains(promotion.ApproverIDs, userID)
+}
+
+// CanView is true for the proposer and the approvers
+func (promotion *Aggregate) CanView(userID uuid.UUID) bool {
+	return promotion.ProposerID == userID || promotion.IsApprover(userID)
+}
+
+// Pending lists the approvers that have not decided yet
+func (promotion *Aggregate) Pending() []uuid.UUID {
+	return slices.DeleteFunc(slices.Clone(promotion.ApproverIDs), func(id uuid.UUID) bool {
+		_, decided := promotion.Approvals.For(id)
+		return decided
+	})
+}
+
+// Approval is the decision of one approver
+type Approval struct {
+	UserID    uuid.UUID `json:"userID"`
+	Approved  bool      `json:"approved"`
+	Comment   string    `json:"comment"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+// Approvals are stored as jsonb
+type Approvals []Approval
+
+func (a Approvals) For(userID uuid.UUID) (Approval, bool) {
+	for _, approval := range a {
+		if approval.UserID == userID {
+			return approval, true
+		}
+	}
+	return Approval{}, false
+}
+
+func (a Approvals) Value() (driver.Value, error) {
+	if a == nil {
+		return []byte("[]"), nil
+	}
+	return json.Marshal(a)
+}
+
+func (a *Approvals) Scan(value interface{}) error {
+	switch v := value.(type) {
+	case nil:
+		return nil
+	case []byte:
+		return json.Unmarshal(v, a)
+	case string:
+		return json.Unmarshal([]byte(v), a)
+	}
+	return fmt.Errorf("cannot scan %T into approvals", value)
+}
+
+// UUIDs are stored as a jsonb array
+type UUIDs []uuid.UUID
+
+func (u UUIDs) Value() (driver.Value, error) {
+	if u == nil {
+		return []byte("[]"), nil
+	}
+	return json.Marshal(u)
+}
+
+func (u *UUIDs) Scan(value interface{}) error {
+	switch v := value.(type) {
+	case nil:
+		return nil
+	case []byte:
+		return json.Unmarshal(v, u)
+	case string:
+		return json.Unmarshal([]byte(v), u)
+	}
+	return fmt.Errorf("cannot scan %T into uuids", value)
+}
+
+var _ eventsource.Aggregate = &Aggregate{}
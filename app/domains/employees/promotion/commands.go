@@ -0,0 +1,253 @@
+package promotion
+
+import (
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/golly-go/golly"
+	"github.com/golly-go/golly/errors"
+	"github.com/golly-go/plugins/eventsource"
+	"github.com/google/uuid"
+	"github.com/mitchrodrigues/talent-review-backend/app/domains/tara/prompt"
+)
+
+var (
+	ErrorEmployeeRequired     = fmt.Errorf("promotion employee is required")
+	ErrorProposerRequired     = fmt.Errorf("promotion proposer is required")
+	ErrorTargetRoleRequired   = fmt.Errorf("promotion target role is required")
+	ErrorSameRole             = fmt.Errorf("promotion target role is the current role")
+	ErrorEffectiveAtRequired  = fmt.Errorf("promotion effective date is required")
+	ErrorApproversRequired    = fmt.Errorf("promotion needs at least one approver")
+	ErrorProposerApprover     = fmt.Errorf("the proposer cannot approve their own promotion")
+	ErrorNotOpen              = fmt.Errorf("promotion has already been decided")
+	ErrorNotApprover          = fmt.Errorf("you are not an approver of this promotion")
+	ErrorAlreadyDecided       = fmt.Errorf("you have already decided on this promotion")
+	ErrorNotProposer          = fmt.Errorf("only the proposer can change the promotion")
+	ErrorJustificationMissing = fmt.Errorf("promotion needs a justification before it can be approved")
+	ErrorNotDue               = fmt.Errorf("promotion is not approved or not effective yet")
+)
+
+type Propose struct {
+	OrganizationID uuid.UUID
+	EmployeeID     uuid.UUID
+	ProposerID     uuid.UUID
+
+	CurrentRoleID uuid.UUID
+	TargetRoleID  uuid.UUID
+
+	Justification string
+	EffectiveAt   time.Time
+	ApproverIDs   []uuid.UUID
+}
+
+func (cmd Propose) Validate(ctx golly.Context, aggregate eventsource.Aggregate) error {
+	switch {
+	case cmd.EmployeeID == uuid.Nil:
+		return errors.WrapUnprocessable(ErrorEmployeeRequired)
+	case cmd.ProposerID == uuid.Nil:
+		return errors.WrapUnprocessable(ErrorProposerRequired)
+	case cmd.TargetRoleID == uuid.Nil:
+		return errors.WrapUnprocessable(ErrorTargetRoleRequired)
+	case cmd.TargetRoleID == cmd.CurrentRoleID:
+		return errors.WrapUnprocessable(ErrorSameRole)
+	case cmd.EffectiveAt.IsZero():
+		return errors.WrapUnprocessable(ErrorEffectiveAtRequired)
+	case len(cmd.ApproverIDs) == 0:
+		return errors.WrapUnprocessable(ErrorApproversRequired)
+	}
+
+	for _, approverID := range cmd.ApproverIDs {
+		if approverID == cmd.ProposerID {
+			return errors.WrapUnprocessable(ErrorProposerApprover)
+		}
+	}
+
+	return nil
+}
+
+func (cmd Propose) Perform(ctx golly.Context, aggregate eventsource.Aggregate) error {
+	id, _ := uuid.NewV7()
+
+	approvers := UUIDs{}
+	for _, approverID := range cmd.ApproverIDs {
+		if !slices.Contains(approvers, approverID) {
+			approvers = append(approvers, approverID)
+		}
+	}
+
+	eventsource.Apply(ctx, aggregate, Proposed{
+		ID:             id,
+		OrganizationID: cmd.OrganizationID,
+		EmployeeID:     cmd.EmployeeID,
+		ProposerID:     cmd.ProposerID,
+		CurrentRoleID:  cmd.CurrentRoleID,
+		TargetRoleID:   cmd.TargetRoleID,
+		EffectiveAt:    cmd.EffectiveAt,
+		ApproverIDs:    approvers,
+	})
+
+	if cmd.Justification != "" {
+		eventsource.Apply(ctx, aggregate, JustificationUpdated{Justification: cmd.Justification, Citations: UUIDs{}})
+	}
+
+	return nil
+}
+
+// Revise lets the proposer change an open promotion, nil fields keep
+// their current value
+type Revise struct {
+	UserID uuid.UUID
+
+	Justification *string
+	EffectiveAt   *time.Time
+}
+
+func (cmd Revise) Validate(ctx golly.Context, aggregate eventsource.Aggregate) error {
+	promotion := aggregate.(*Aggregate)
+
+	if err := validateOpen(promotion); err != nil {
+		return err
+	}
+
+	if promotion.ProposerID != cmd.UserID {
+		return errors.WrapForbidden(ErrorNotProposer)
+	}
+
+	if cmd.EffectiveAt != nil && cmd.EffectiveAt.IsZero() {
+		return errors.WrapUnprocessable(ErrorEffectiveAtRequired)
+	}
+
+	return nil
+}
+
+func (cmd Revise) Perform(ctx golly.Context, aggregate eventsource.Aggregate) error {
+	promotion := aggregate.(*Aggregate)
+
+	// Editing the justification by hand drops what tara cited
+	if cmd.Justification != nil && *cmd.Justification != promotion.Justification {
+		eventsource.Apply(ctx, aggregate, JustificationUpdated{Justification: *cmd.Justification, Citations: UUIDs{}})
+	}
+
+	if cmd.EffectiveAt != nil {
+		eventsource.Apply(ctx, aggregate, EffectiveAtUpdated{EffectiveAt: *cmd.EffectiveAt})
+	}
+
+	return nil
+}
+
+// DraftJustification stores a justification tara drafted
+type DraftJustification struct {
+	Justification  string
+	Citations      []uuid.UUID
+	PromptVersions prompt.Refs
+}
+
+func (cmd DraftJustification) Validate(ctx golly.Context, aggregate eventsource.Aggregate) error {
+	return validateOpen(aggregate.(*Aggregate))
+}
+
+func (cmd DraftJustification) Perform(ctx golly.Context, aggregate eventsource.Aggregate) error {
+	eventsource.Apply(ctx, aggregate, JustificationUpdated{
+		Justification:  cmd.Justification,
+		Citations:      cmd.Citations,
+		PromptVersions: cmd.PromptVersions,
+	})
+	return nil
+}
+
+// Decide records the decision of an approver, a rejection rejects the
+// promotion and it is approved once every approver has approved
+type Decide struct {
+	UserID   uuid.UUID
+	Approved bool
+	Comment  string
+}
+
+func (cmd Decide) Validate(ctx golly.Context, aggregate eventsource.Aggregate) error {
+	promotion := aggregate.(*Aggregate)
+
+	if err := validateOpen(promotion); err != nil {
+		return err
+	}
+
+	if !promotion.IsApprover(cmd.UserID) {
+		return errors.WrapForbidden(ErrorNotApprover)
+	}
+
+	if _, decided := promotion.Approvals.For(cmd.UserID); decided {
+		return errors.WrapUnprocessable(ErrorAlreadyDecided)
+	}
+
+	if cmd.Approved && promotion.Justification == "" {
+		return errors.WrapUnprocessable(ErrorJustificationMissing)
+	}
+
+	return nil
+}
+
+func (cmd Decide) Perform(ctx golly.Context, aggregate eventsource.Aggregate) error {
+	promotion := aggregate.(*Aggregate)
+
+	eventsource.Apply(ctx, aggregate, ApprovalRecorded{
+		UserID:   cmd.UserID,
+		Approved: cmd.Approved,
+		Comment:  cmd.Comment,
+	})
+
+	switch {
+	case !cmd.Approved:
+		eventsource.Apply(ctx, aggregate, Rejected{})
+	case len(promotion.Pending()) == 0:
+		eventsource.Apply(ctx, aggregate, Approved{})
+	}
+
+	return nil
+}
+
+type Withdraw struct {
+	UserID uuid.UUID
+}
+
+func (cmd Withdraw) Validate(ctx golly.Context, aggregate eventsource.Aggregate) error {
+	promotion := aggregate.(*Aggregate)
+
+	if err := validateOpen(promotion); err != nil {
+		return err
+	}
+
+	if promotion.ProposerID != cmd.UserID {
+		return errors.WrapForbidden(ErrorNotProposer)
+	}
+
+	return nil
+}
+
+func (Withdraw) Perform(ctx golly.Context, aggregate eventsource.Aggregate) error {
+	eventsource.Apply(ctx, aggregate, Withdrawn{})
+	return nil
+}
+
+// MarkApplied records that the employee was moved to the target role
+type MarkApplied struct {
+	Now time.Time
+}
+
+func (cmd MarkApplied) Validate(ctx golly.Context, aggregate eventsource.Aggregate) error {
+	if !aggregate.(*Aggregate).Due(cmd.Now) {
+		return errors.WrapUnprocessable(ErrorNotDue)
+	}
+	return nil
+}
+
+func (MarkApplied) Perform(ctx golly.Context, aggregate eventsource.Aggregate) error {
+	eventsource.Apply(ctx, aggregate, Applied{})
+	return nil
+}
+
+func validateOpen(promotion *Aggregate) error {
+	if !promotion.Open() {
+		return errors.WrapUnprocessable(ErrorNotOpen)
+	}
+	return nil
+}
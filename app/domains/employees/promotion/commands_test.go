@@ -0,0 +1,168 @@
+package promotion
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/golly-go/golly"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestProposeValidate(t *testing.T) {
+	gctx := golly.NewContext(context.TODO())
+
+	proposerID, approverID := uuid.New(), uuid.New()
+
+	valid := Propose{
+		EmployeeID:    uuid.New(),
+		ProposerID:    proposerID,
+		CurrentRoleID: uuid.New(),
+		TargetRoleID:  uuid.New(),
+		EffectiveAt:   time.Now(),
+		ApproverIDs:   []uuid.UUID{approverID},
+	}
+
+	with := func(fn func(*Propose)) Propose {
+		cmd := valid
+		fn(&cmd)
+		return cmd
+	}
+
+	tests := []struct {
+		name      string
+		cmd       Propose
+		expectErr error
+	}{
+		{name: "valid", cmd: valid},
+		{name: "missing employee", cmd: with(func(c *Propose) { c.EmployeeID = uuid.Nil }), expectErr: ErrorEmployeeRequired},
+		{name: "missing target role", cmd: with(func(c *Propose) { c.TargetRoleID = uuid.Nil }), expectErr: ErrorTargetRoleRequired},
+		{name: "same role", cmd: with(func(c *Propose) { c.TargetRoleID = c.CurrentRoleID }), expectErr: ErrorSameRole},
+		{name: "missing effective date", cmd: with(func(c *Propose) { c.EffectiveAt = time.Time{} }), expectErr: ErrorEffectiveAtRequired},
+		{name: "no approvers", cmd: with(func(c *Propose) { c.ApproverIDs = nil }), expectErr: ErrorApproversRequired},
+		{name: "proposer approves", cmd: with(func(c *Propose) { c.ApproverIDs = []uuid.UUID{approverID, proposerID} }), expectErr: ErrorProposerApprover},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.cmd.Validate(gctx, &Aggregate{})
+			if tt.expectErr != nil {
+				assert.ErrorContains(t, err, tt.expectErr.Error())
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestPromotionLifecycle(t *testing.T) {
+	gctx := golly.NewContext(context.TODO())
+
+	proposerID, firstID, secondID := uuid.New(), uuid.New(), uuid.New()
+
+	propose := func(justification string) *Aggregate {
+		agg := &Aggregate{}
+
+		assert.NoError(t, Propose{
+			EmployeeID:    uuid.New(),
+			ProposerID:    proposerID,
+			CurrentRoleID: uuid.New(),
+			TargetRoleID:  uuid.New(),
+			Justification: justification,
+			EffectiveAt:   time.Now(),
+			ApproverIDs:   []uuid.UUID{firstID, secondID, firstID},
+		}.Perform(gctx, agg))
+
+		return agg
+	}
+
+	t.Run("approved once every approver approved", func(t *testing.T) {
+		agg := propose("Leads the platform work")
+
+		assert.Equal(t, StatusProposed, agg.Status)
+		assert.Equal(t, UUIDs{firstID, secondID}, agg.ApproverIDs)
+		assert.Equal(t, "Leads the platform work", agg.Justification)
+
+		first := Decide{UserID: firstID, Approved: true, Comment: "Agreed"}
+		assert.NoError(t, first.Validate(gctx, agg))
+		assert.NoError(t, first.Perform(gctx, agg))
+
+		assert.Equal(t, StatusProposed, agg.Status)
+		assert.Equal(t, []uuid.UUID{secondID}, agg.Pending())
+		assert.ErrorContains(t, first.Validate(gctx, agg), ErrorAlreadyDecided.Error())
+
+		assert.ErrorContains(t, Decide{UserID: proposerID, Approved: true}.Validate(gctx, agg), ErrorNotApprover.Error())
+
+		second := Decide{UserID: secondID, Approved: true}
+		assert.NoError(t, second.Validate(gctx, agg))
+		assert.NoError(t, second.Perform(gctx, agg))
+
+		assert.Equal(t, StatusApproved, agg.Status)
+		assert.NotNil(t, agg.DecidedAt)
+		assert.Len(t, agg.Approvals, 2)
+
+		approval, ok := agg.Approvals.For(firstID)
+		assert.True(t, ok)
+		assert.Equal(t, "Agreed", approval.Comment)
+
+		assert.ErrorContains(t, Withdraw{UserID: proposerID}.Validate(gctx, agg), ErrorNotOpen.Error())
+	})
+
+	t.Run("applied once approved and effective", func(t *testing.T) {
+		agg := propose("Leads the platform work")
+
+		now := agg.EffectiveAt
+		assert.ErrorContains(t, MarkApplied{Now: now}.Validate(gctx, agg), ErrorNotDue.Error())
+
+		assert.NoError(t, Decide{UserID: firstID, Approved: true}.Perform(gctx, agg))
+		assert.NoError(t, Decide{UserID: secondID, Approved: true}.Perform(gctx, agg))
+
+		assert.False(t, agg.Due(now.Add(-time.Hour)))
+		assert.ErrorContains(t, MarkApplied{Now: now.Add(-time.Hour)}.Validate(gctx, agg), ErrorNotDue.Error())
+
+		assert.True(t, agg.Due(now))
+		assert.NoError(t, MarkApplied{Now: now}.Validate(gctx, agg))
+		assert.NoError(t, MarkApplied{Now: now}.Perform(gctx, agg))
+
+		assert.NotNil(t, agg.AppliedAt)
+		assert.ErrorContains(t, MarkApplied{Now: now}.Validate(gctx, agg), ErrorNotDue.Error())
+	})
+
+	t.Run("rejected by any approver", func(t *testing.T) {
+		agg := propose("Leads the platform work")
+
+		assert.NoError(t, Decide{UserID: secondID, Approved: false, Comment: "Not yet"}.Perform(gctx, agg))
+
+		assert.Equal(t, StatusRejected, agg.Status)
+		assert.ErrorContains(t, Decide{UserID: firstID, Approved: true}.Validate(gctx, agg), ErrorNotOpen.Error())
+	})
+
+	t.Run("approval needs a justification", func(t *testing.T) {
+		agg := propose("")
+
+		assert.ErrorContains(t, Decide{UserID: firstID, Approved: true}.Validate(gctx, agg), ErrorJustificationMissing.Error())
+		assert.NoError(t, Decide{UserID: firstID, Approved: false}.Validate(gctx, agg))
+
+		assert.NoError(t, DraftJustification{Justification: "Drafted", Citations: []uuid.UUID{uuid.New()}}.Perform(gctx, agg))
+		assert.Len(t, agg.Citations, 1)
+
+		justification := "Edited"
+		assert.NoError(t, Revise{UserID: proposerID, Justification: &justification}.Perform(gctx, agg))
+
+		assert.Equal(t, "Edited", agg.Justification)
+		assert.Empty(t, agg.Citations)
+		assert.NoError(t, Decide{UserID: firstID, Approved: true}.Validate(gctx, agg))
+	})
+
+	t.Run("only the proposer withdraws or revises", func(t *testing.T) {
+		agg := propose("Leads the platform work")
+
+		assert.ErrorContains(t, Withdraw{UserID: firstID}.Validate(gctx, agg), ErrorNotProposer.Error())
+		assert.ErrorContains(t, Revise{UserID: firstID}.Validate(gctx, agg), ErrorNotProposer.Error())
+
+		assert.NoError(t, Withdraw{UserID: proposerID}.Validate(gctx, agg))
+		assert.NoError(t, Withdraw{UserID: proposerID}.Perform(gctx, agg))
+		assert.Equal(t, StatusWithdrawn, agg.Status)
+	})
+}
@@ -0,0 +1,46 @@
+package promotion
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/mitchrodrigues/talent-review-backend/app/domains/tara/prompt"
+)
+
+type Proposed struct {
+	ID             uuid.UUID `json:"id"`
+	OrganizationID uuid.UUID `json:"organizationID"`
+	EmployeeID     uuid.UUID `json:"employeeID"`
+	ProposerID     uuid.UUID `json:"proposerID"`
+
+	CurrentRoleID uuid.UUID `json:"currentRoleID"`
+	TargetRoleID  uuid.UUID `json:"targetRoleID"`
+
+	EffectiveAt time.Time `json:"effectiveAt"`
+	ApproverIDs UUIDs     `json:"approverIDs"`
+}
+
+type JustificationUpdated struct {
+	Justification  string      `json:"-"`
+	Citations      UUIDs       `json:"citations"`
+	PromptVersions prompt.Refs `json:"promptVersions"`
+}
+
+type EffectiveAtUpdated struct {
+	EffectiveAt time.Time `json:"effectiveAt"`
+}
+
+type ApprovalRecorded struct {
+	UserID   uuid.UUID `json:"userID"`
+	Approved bool      `json:"approved"`
+	Comment  string    `json:"-"`
+}
+
+type Approved struct{}
+
+type Rejected struct{}
+
+type Withdrawn struct{}
+
+// Applied is recorded once the employee has been moved to the target role
+type Applied struct{}
@@ -0,0 +1,216 @@
+package employees
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/golly-go/golly"
+	"github.com/golly-go/golly/errors"
+	"github.com/golly-go/plugins/eventsource"
+	"github.com/golly-go/plugins/orm"
+	"github.com/google/uuid"
+	"github.com/mitchrodrigues/talent-review-backend/app/domains/accounts"
+	"github.com/mitchrodrigues/talent-review-backend/app/domains/common"
+	"github.com/mitchrodrigues/talent-review-backend/app/domains/employees/employee"
+	"github.com/mitchrodrigues/talent-review-backend/app/domains/employees/promotion"
+	"github.com/mitchrodrigues/talent-review-backend/app/utils/identity"
+	"gorm.io/gorm"
+)
+
+var (
+	ErrorNotPromotionManager = fmt.Errorf("you can only propose promotions for employees you manage")
+	ErrorUnknownApprover     = fmt.Errorf("promotion approvers must be users of your organization")
+)
+
+type Promotion struct {
+	promotion.Aggregate
+}
+
+func (Promotion) TableName() string { return "promotions" }
+
+type ProposePromotionInput struct {
+	EmployeeID   uuid.UUID
+	TargetRoleID uuid.UUID
+
+	Justification string
+	EffectiveAt   time.Time
+	ApproverIDs   []uuid.UUID
+}
+
+// ProposePromotion opens a promotion case for an employee the current
+// user manages
+func ProposePromotion(gctx golly.Context, input ProposePromotionInput, metadata eventsource.Metadata) (Promotion, error) {
+	var p Promotion
+
+	ident := identity.FromContext(gctx)
+
+	reports, err := Service(gctx).FindEmployeesByManagerAndIDS(gctx, ident.EmployeeID, input.EmployeeID)
+	if err != nil || len(reports) == 0 {
+		return p, errors.WrapForbidden(ErrorNotPromotionManager)
+	}
+
+	targetRole, err := Service(gctx).FindRoleByID(gctx, input.TargetRoleID)
+	if err != nil {
+		return p, errors.WrapGeneric(err)
+	}
+
+	if targetRole.ID == uuid.Nil {
+		return p, errors.WrapUnprocessable(promotion.ErrorTargetRoleRequired)
+	}
+
+	for _, approverID := range input.ApproverIDs {
+		_, err := accounts.FindUserByID(gctx, approverID.String(), common.OrganizationIDScopeForContext(gctx))
+		if err != nil {
+			return p, errors.WrapUnprocessable(ErrorUnknownApprover)
+		}
+	}
+
+	err = eventsource.Call(gctx, &p.Aggregate, promotion.Propose{
+		OrganizationID: ident.OrganizationID,
+		EmployeeID:     reports[0].ID,
+		ProposerID:     ident.UID,
+		CurrentRoleID:  reports[0].EmployeeRoleID,
+		TargetRoleID:   targetRole.ID,
+		Justification:  input.Justification,
+		EffectiveAt:    input.EffectiveAt,
+		ApproverIDs:    input.ApproverIDs,
+	}, metadata)
+
+	return p, err
+}
+
+// UpdatePromotion runs a command (revise, decide, withdraw or draft
+// justification) on a promotion the current user can see. Once the last
+// approver approves the employee is moved to the target role, right away
+// when the effective date has passed and otherwise by ApplyDuePromotions
+func UpdatePromotion(gctx golly.Context, id uuid.UUID, cmd eventsource.Command, metadata eventsource.Metadata) (Promotion, error) {
+	p, err := FindPromotion(gctx, id)
+	if err != nil {
+		return p, err
+	}
+
+	if err := eventsource.Call(gctx, &p.Aggregate, cmd, metadata); err != nil {
+		return p, err
+	}
+
+	if p.Due(time.Now()) {
+		return p, applyPromotion(gctx, &p, time.Now(), metadata)
+	}
+
+	return p, nil
+}
+
+// ApplyDuePromotions moves the employees of every approved promotion whose
+// effective date has passed to their target role. A promotion that failed
+// to apply before is retried
+func ApplyDuePromotions(gctx golly.Context, now time.Time) ([]Promotion, error) {
+	var promotions []Promotion
+
+	err := orm.DB(gctx).
+		Model(&Promotion{}).
+		Where("status = ? AND applied_at IS NULL AND effective_at <= ?", promotion.StatusApproved, now).
+		Order("effective_at").
+		Find(&promotions).
+		Error
+
+	if err != nil {
+		return nil, errors.WrapGeneric(err)
+	}
+
+	applied := make([]Promotion, 0, len(promotions))
+
+	for _, p := range promotions {
+		_, gctx = identity.SetOrganizationID(gctx, p.OrganizationID)
+
+		if err := applyPromotion(gctx, &p, now, eventsource.Metadata{}); err != nil {
+			gctx.Logger().Warnf("cannot apply promotion %s %v", p.ID, err)
+			continue
+		}
+
+		applied = append(applied, p)
+	}
+
+	return applied, nil
+}
+
+// applyPromotion moves the employee to the target role and marks the
+// promotion applied. The two are separate aggregates, an employee already
+// in the target role is not promoted again so a failed apply can be
+// retried
+func applyPromotion(gctx golly.Context, p *Promotion, now time.Time, metadata eventsource.Metadata) error {
+	var emp Employee
+
+	err := baseEmployeeQuery(gctx).
+		First(&emp, "employees.id = ?", p.EmployeeID).
+		Error
+
+	if err != nil {
+		return errors.WrapNotFound(err)
+	}
+
+	if emp.EmployeeRoleID != p.TargetRoleID {
+		err := eventsource.Call(gctx, &emp.Aggregate, employee.Promote{
+			PromotionID:    p.ID,
+			EmployeeRoleID: p.TargetRoleID,
+			EffectiveAt:    p.EffectiveAt,
+		}, metadata)
+
+		if err != nil {
+			return err
+		}
+	}
+
+	return eventsource.Call(gctx, &p.Aggregate, promotion.MarkApplied{Now: now}, metadata)
+}
+
+func promotionQuery(gctx golly.Context) *gorm.DB {
+	return orm.DB(gctx).
+		Model(&Promotion{}).
+		Scopes(common.OrganizationIDScopeForContext(gctx))
+}
+
+// FindPromotion finds a promotion of the organization, only its proposer,
+// its approvers and HR can see it
+func FindPromotion(gctx golly.Context, id uuid.UUID) (Promotion, error) {
+	var p Promotion
+
+	err := promotionQuery(gctx).
+		First(&p, "id = ?", id).
+		Error
+
+	if err != nil {
+		return p, errors.WrapNotFound(err)
+	}
+
+	if !p.CanView(identity.FromContext(gctx).UID) {
+		if _, err := accounts.RequireHR(gctx); err != nil {
+			return Promotion{}, errors.WrapNotFound(gorm.ErrRecordNotFound)
+		}
+	}
+
+	return p, nil
+}
+
+// FindPromotions lists the promotions the current user can see, of one
+// employee when the employee id is given, most recent first
+func FindPromotions(gctx golly.Context, employeeID uuid.UUID) ([]Promotion, error) {
+	var promotions []Promotion
+
+	db := promotionQuery(gctx)
+
+	if employeeID != uuid.Nil {
+		db = db.Where("employee_id = ?", employeeID)
+	}
+
+	if err := db.Order("created_at DESC").Find(&promotions).Error; err != nil {
+		return promotions, errors.WrapGeneric(err)
+	}
+
+	if _, err := accounts.RequireHR(gctx); err == nil {
+		return promotions, nil
+	}
+
+	uid := identity.FromContext(gctx).UID
+
+	return golly.Filter(promotions, func(p Promotion) bool { return p.CanView(uid) }), nil
+}
@@ -0,0 +1,341 @@
+package employees
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/golly-go/golly"
+	"github.com/golly-go/plugins/gql"
+	"github.com/google/uuid"
+	"github.com/graphql-go/graphql"
+	"github.com/mitchrodrigues/talent-review-backend/app/domains/employees/promotion"
+	"github.com/mitchrodrigues/talent-review-backend/app/utils/helpers"
+	"github.com/mitchrodrigues/talent-review-backend/app/utils/identity"
+)
+
+var (
+	promotionStatusType = graphql.NewEnum(graphql.EnumConfig{
+		Name: "PromotionStatus",
+		Values: graphql.EnumValueConfigMap{
+			"proposed":  {Value: promotion.StatusProposed},
+			"approved":  {Value: promotion.StatusApproved},
+			"rejected":  {Value: promotion.StatusRejected},
+			"withdrawn": {Value: promotion.StatusWithdrawn},
+		},
+	})
+
+	promotionApprovalType = graphql.NewObject(graphql.ObjectConfig{
+		Name: "PromotionApproval",
+		Fields: graphql.Fields{
+			"userID": {
+				Type: graphql.String,
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					return p.Source.(promotion.Approval).UserID, nil
+				},
+			},
+			"approved": {
+				Type: graphql.Boolean,
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					return p.Source.(promotion.Approval).Approved, nil
+				},
+			},
+			"comment": {
+				Type: graphql.String,
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					return p.Source.(promotion.Approval).Comment, nil
+				},
+			},
+			"createdAt": {
+				Type: graphql.DateTime,
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					return p.Source.(promotion.Approval).CreatedAt, nil
+				},
+			},
+		},
+	})
+
+	PromotionGQLType = graphql.NewObject(graphql.ObjectConfig{
+		Name: "Promotion",
+		Fields: graphql.Fields{
+			"id": {
+				Type: graphql.NewNonNull(graphql.String),
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					return p.Source.(Promotion).ID, nil
+				},
+			},
+			"employee": {
+				Type: EmployeeGQLType,
+				Resolve: gql.NewHandler(gql.Options{
+					Handler: func(wctx golly.WebContext, params gql.Params) (interface{}, error) {
+						employeeID := params.Source.(Promotion).EmployeeID
+
+						return golly.LoadData(wctx.Context, fmt.Sprintf("employee:%s", employeeID), func(golly.Context) (Employee, error) {
+							return Service(wctx.Context).FindEmployeeByID(wctx.Context, employeeID)
+						})
+					},
+				}),
+			},
+			"proposerID": {
+				Type: graphql.String,
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					return p.Source.(Promotion).ProposerID, nil
+				},
+			},
+			"currentRole": {
+				Type: EmployeeRoleGQLType,
+				Resolve: gql.NewHandler(gql.Options{
+					Handler: func(wctx golly.WebContext, params gql.Params) (interface{}, error) {
+						return promotionRole(wctx, params.Source.(Promotion).CurrentRoleID)
+					},
+				}),
+			},
+			"targetRole": {
+				Type: EmployeeRoleGQLType,
+				Resolve: gql.NewHandler(gql.Options{
+					Handler: func(wctx golly.WebContext, params gql.Params) (interface{}, error) {
+						return promotionRole(wctx, params.Source.(Promotion).TargetRoleID)
+					},
+				}),
+			},
+			"justification": {
+				Type: graphql.String,
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					return p.Source.(Promotion).Justification, nil
+				},
+			},
+			"citations": {
+				Type:        graphql.NewList(graphql.String),
+				Description: "IDs of the feedback a drafted justification is based on",
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					return p.Source.(Promotion).Citations, nil
+				},
+			},
+			"effectiveAt": {
+				Type: graphql.DateTime,
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					return p.Source.(Promotion).EffectiveAt, nil
+				},
+			},
+			"status": {
+				Type: promotionStatusType,
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					return p.Source.(Promotion).Status, nil
+				},
+			},
+			"approverIDs": {
+				Type: graphql.NewList(graphql.String),
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					return p.Source.(Promotion).ApproverIDs, nil
+				},
+			},
+			"pendingApproverIDs": {
+				Type: graphql.NewList(graphql.String),
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					promo := p.Source.(Promotion)
+					return promo.Pending(), nil
+				},
+			},
+			"approvals": {
+				Type: graphql.NewList(promotionApprovalType),
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					return []promotion.Approval(p.Source.(Promotion).Approvals), nil
+				},
+			},
+			"decidedAt": {
+				Type: graphql.DateTime,
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					return p.Source.(Promotion).DecidedAt, nil
+				},
+			},
+			"appliedAt": {
+				Type: graphql.DateTime,
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					return p.Source.(Promotion).AppliedAt, nil
+				},
+			},
+			"createdAt": {
+				Type: graphql.DateTime,
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					return p.Source.(Promotion).CreatedAt, nil
+				},
+			},
+		},
+	})
+
+	proposePromotionInputType = graphql.NewInputObject(graphql.InputObjectConfig{
+		Name: "ProposePromotionInput",
+		Fields: graphql.InputObjectConfigFieldMap{
+			"employeeID":    {Type: graphql.NewNonNull(graphql.String)},
+			"targetRoleID":  {Type: graphql.NewNonNull(graphql.String)},
+			"justification": {Type: graphql.String, Description: "Can be drafted by tara once the promotion is proposed"},
+			"effectiveAt":   {Type: graphql.NewNonNull(graphql.DateTime)},
+			"approverIDs":   {Type: graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(graphql.String))), Description: "User ids that have to approve"},
+		},
+	})
+
+	revisePromotionInputType = graphql.NewInputObject(graphql.InputObjectConfig{
+		Name: "RevisePromotionInput",
+		Fields: graphql.InputObjectConfigFieldMap{
+			"justification": {Type: graphql.String},
+			"effectiveAt":   {Type: graphql.DateTime},
+		},
+	})
+
+	promotionQueries = graphql.Fields{
+		"promotions": &graphql.Field{
+			Type:        graphql.NewList(PromotionGQLType),
+			Description: "Promotions you proposed or have to approve, all of them for HR",
+			Args: graphql.FieldConfigArgument{
+				"employeeID": {Type: graphql.String},
+			},
+			Resolve: gql.NewHandler(gql.Options{
+				Handler: func(wctx golly.WebContext, params gql.Params) (interface{}, error) {
+					employeeID, err := helpers.ExtractAndParseUUID(params.Args, "employeeID")
+					if err != nil {
+						return nil, err
+					}
+
+					return FindPromotions(wctx.Context, employeeID)
+				},
+			}),
+		},
+		"promotion": &graphql.Field{
+			Type: PromotionGQLType,
+			Args: graphql.FieldConfigArgument{
+				"id": {Type: graphql.NewNonNull(graphql.String)},
+			},
+			Resolve: gql.NewHandler(gql.Options{
+				Handler: func(wctx golly.WebContext, params gql.Params) (interface{}, error) {
+					id, err := helpers.ExtractAndParseUUID(params.Args, "id")
+					if err != nil {
+						return nil, err
+					}
+
+					return FindPromotion(wctx.Context, id)
+				},
+			}),
+		},
+	}
+
+	promotionMutations = graphql.Fields{
+		"proposePromotion": &graphql.Field{
+			Type:        PromotionGQLType,
+			Description: "Propose the promotion of an employee you manage",
+			Args: graphql.FieldConfigArgument{
+				"input": &graphql.ArgumentConfig{Type: graphql.NewNonNull(proposePromotionInputType)},
+			},
+			Resolve: gql.NewHandler(gql.Options{
+				Handler: func(wctx golly.WebContext, params gql.Params) (interface{}, error) {
+					employeeID, err := helpers.ExtractAndParseUUID(params.Input, "employeeID")
+					if err != nil {
+						return nil, err
+					}
+
+					targetRoleID, err := helpers.ExtractAndParseUUID(params.Input, "targetRoleID")
+					if err != nil {
+						return nil, err
+					}
+
+					approverIDs := []uuid.UUID{}
+					for _, id := range params.Input["approverIDs"].([]interface{}) {
+						approverID, err := uuid.Parse(id.(string))
+						if err != nil {
+							return nil, err
+						}
+						approverIDs = append(approverIDs, approverID)
+					}
+
+					justification, _ := helpers.ExtractArg[string](params.Input, "justification")
+					effectiveAt, _ := helpers.ExtractArg[time.Time](params.Input, "effectiveAt")
+
+					return ProposePromotion(wctx.Context, ProposePromotionInput{
+						EmployeeID:    employeeID,
+						TargetRoleID:  targetRoleID,
+						Justification: justification,
+						EffectiveAt:   effectiveAt,
+						ApproverIDs:   approverIDs,
+					}, params.Metadata())
+				},
+			}),
+		},
+		"revisePromotion": &graphql.Field{
+			Type: PromotionGQLType,
+			Args: graphql.FieldConfigArgument{
+				"id":    &graphql.ArgumentConfig{Type: graphql.NewNonNull(graphql.String)},
+				"input": &graphql.ArgumentConfig{Type: graphql.NewNonNull(revisePromotionInputType)},
+			},
+			Resolve: gql.NewHandler(gql.Options{
+				Handler: func(wctx golly.WebContext, params gql.Params) (interface{}, error) {
+					id, err := helpers.ExtractAndParseUUID(params.Args, "id")
+					if err != nil {
+						return nil, err
+					}
+
+					cmd := promotion.Revise{UserID: identity.FromContext(wctx.Context).UID}
+
+					if justification, err := helpers.ExtractArg[string](params.Input, "justification"); err == nil {
+						cmd.Justification = &justification
+					}
+
+					if effectiveAt, err := helpers.ExtractArg[time.Time](params.Input, "effectiveAt"); err == nil {
+						cmd.EffectiveAt = &effectiveAt
+					}
+
+					return UpdatePromotion(wctx.Context, id, cmd, params.Metadata())
+				},
+			}),
+		},
+		"approvePromotion": promotionDecisionField(true),
+		"rejectPromotion":  promotionDecisionField(false),
+		"withdrawPromotion": &graphql.Field{
+			Type: PromotionGQLType,
+			Args: graphql.FieldConfigArgument{
+				"id": &graphql.ArgumentConfig{Type: graphql.NewNonNull(graphql.String)},
+			},
+			Resolve: gql.NewHandler(gql.Options{
+				Handler: func(wctx golly.WebContext, params gql.Params) (interface{}, error) {
+					id, err := helpers.ExtractAndParseUUID(params.Args, "id")
+					if err != nil {
+						return nil, err
+					}
+
+					return UpdatePromotion(wctx.Context, id,
+						promotion.Withdraw{UserID: identity.FromContext(wctx.Context).UID},
+						params.Metadata())
+				},
+			}),
+		},
+	}
+)
+
+func promotionDecisionField(approved bool) *graphql.Field {
+	return &graphql.Field{
+		Type: PromotionGQLType,
+		Args: graphql.FieldConfigArgument{
+			"id":      &graphql.ArgumentConfig{Type: graphql.NewNonNull(graphql.String)},
+			"comment": &graphql.ArgumentConfig{Type: graphql.String},
+		},
+		Resolve: gql.NewHandler(gql.Options{
+			Handler: func(wctx golly.WebContext, params gql.Params) (interface{}, error) {
+				id, err := helpers.ExtractAndParseUUID(params.Args, "id")
+				if err != nil {
+					return nil, err
+				}
+
+				comment, _ := helpers.ExtractArg[string](params.Args, "comment")
+
+				return UpdatePromotion(wctx.Context, id, promotion.Decide{
+					UserID:   identity.FromContext(wctx.Context).UID,
+					Approved: approved,
+					Comment:  comment,
+				}, params.Metadata())
+			},
+		}),
+	}
+}
+
+func promotionRole(wctx golly.WebContext, id uuid.UUID) (interface{}, error) {
+	return wctx.Loader().Fetch(wctx.Context, fmt.Sprintf("employee_role:%s", id), func(golly.Context) (interface{}, error) {
+		return Service(wctx.Context).FindRoleByID(wctx.Context, id)
+	})
+}
@@ -0,0 +1,70 @@
+package employees
+
+import (
+	"cmp"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/golly-go/golly"
+)
+
+const defaultPromotionInterval = time.Hour
+
+// PromotionService applies approved promotions once their effective date
+// has passed, it is started with `service promotions` and configured with
+// employees.promotion_interval
+type PromotionService struct {
+	interval time.Duration
+
+	running atomic.Bool
+	quit    chan struct{}
+	once    sync.Once
+}
+
+func (*PromotionService) Name() string { return "promotions" }
+
+func (s *PromotionService) Initialize(app golly.Application) error {
+	s.interval = cmp.Or(app.Config.GetDuration("employees.promotion_interval"), defaultPromotionInterval)
+	s.quit = make(chan struct{})
+	return nil
+}
+
+func (s *PromotionService) Run(gctx golly.Context) error {
+	s.running.Store(true)
+	defer s.running.Store(false)
+
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+
+	for {
+		applied, err := ApplyDuePromotions(gctx, time.Now())
+		if err != nil {
+			gctx.Logger().Errorf("applying promotions failed: %v", err)
+		}
+
+		for _, p := range applied {
+			gctx.Logger().Infof("applied promotion %s of employee %s", p.ID, p.EmployeeID)
+		}
+
+		select {
+		case <-ticker.C:
+		case <-s.quit:
+			return nil
+		case <-gctx.Context().Done():
+			return nil
+		}
+	}
+}
+
+func (s *PromotionService) Running() bool { return s.running.Load() }
+
+func (s *PromotionService) Quit() {
+	s.once.Do(func() {
+		if s.quit != nil {
+			close(s.quit)
+		}
+	})
+}
+
+var _ golly.Service = &PromotionService{}
@@ -0,0 +1,193 @@
+package employees
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/golly-go/golly"
+	"github.com/golly-go/plugins/eventsource"
+	"github.com/golly-go/plugins/orm"
+	"github.com/google/uuid"
+	"github.com/mitchrodrigues/talent-review-backend/app/domains/accounts"
+	"github.com/mitchrodrigues/talent-review-backend/app/domains/accounts/users"
+	"github.com/mitchrodrigues/talent-review-backend/app/domains/employees/promotion"
+	"github.com/mitchrodrigues/talent-review-backend/app/domains/employees/role"
+	"github.com/mitchrodrigues/talent-review-backend/app/utils/esbackend"
+	"github.com/mitchrodrigues/talent-review-backend/app/utils/identity"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPromotionWorkflow(t *testing.T) {
+	gctx := orm.CreateTestContext(golly.NewContext(context.TODO()),
+		Promotion{}, Employee{}, EmployeeRole{}, accounts.User{}, esbackend.Event{})
+
+	organizationID := uuid.New()
+
+	createUser := func(orgID uuid.UUID, role string) accounts.User {
+		user := accounts.User{Aggregate: users.Aggregate{OrganizationID: orgID, Email: uuid.NewString(), Role: role}}
+		orm.DB(gctx).Create(&user)
+		return user
+	}
+
+	managerUser := createUser(organizationID, users.RoleMember)
+	approver := createUser(organizationID, users.RoleMember)
+	hr := createUser(organizationID, users.RoleHR)
+	outsider := createUser(uuid.New(), users.RoleMember)
+
+	createRole := func(title string, level int) EmployeeRole {
+		empRole := EmployeeRole{Aggregate: role.Aggregate{OrganizationID: organizationID, Title: title, Level: level, Track: role.IC}}
+		orm.DB(gctx).Create(&empRole)
+		return empRole
+	}
+
+	engineer := createRole("Engineer", 3)
+	senior := createRole("Senior Engineer", 4)
+
+	manager := NewTestEmployee(uuid.New(), organizationID, "manager@example.com", &managerUser.ID)
+	orm.DB(gctx).Create(&manager)
+
+	report := NewTestEmployee(uuid.New(), organizationID, "report@example.com", nil)
+	report.ManagerID = &manager.ID
+	report.EmployeeRoleID = engineer.ID
+	orm.DB(gctx).Create(&report)
+
+	asUser := func(userID, employeeID uuid.UUID) golly.Context {
+		return identity.ToContext(gctx, identity.Identity{UID: userID, OrganizationID: organizationID, EmployeeID: employeeID})
+	}
+
+	// identities are set on the shared context so each call sets its own
+	asManager := func() golly.Context { return asUser(managerUser.ID, manager.ID) }
+	effectiveAt := time.Date(2024, 9, 1, 0, 0, 0, 0, time.UTC)
+
+	input := ProposePromotionInput{
+		EmployeeID:    report.ID,
+		TargetRoleID:  senior.ID,
+		Justification: "Leads the planner work",
+		EffectiveAt:   effectiveAt,
+		ApproverIDs:   []uuid.UUID{approver.ID},
+	}
+
+	t.Run("only managers propose", func(t *testing.T) {
+		notReport := input
+		notReport.EmployeeID = manager.ID
+
+		_, err := ProposePromotion(asManager(), notReport, eventsource.Metadata{})
+		assert.ErrorContains(t, err, ErrorNotPromotionManager.Error())
+	})
+
+	t.Run("approvers must be in the organization", func(t *testing.T) {
+		withOutsider := input
+		withOutsider.ApproverIDs = []uuid.UUID{outsider.ID}
+
+		_, err := ProposePromotion(asManager(), withOutsider, eventsource.Metadata{})
+		assert.ErrorContains(t, err, ErrorUnknownApprover.Error())
+	})
+
+	p, err := ProposePromotion(asManager(), input, eventsource.Metadata{})
+	assert.NoError(t, err)
+	assert.Equal(t, engineer.ID, p.CurrentRoleID)
+	assert.Equal(t, promotion.StatusProposed, p.Status)
+
+	t.Run("visible to the proposer, approvers and hr", func(t *testing.T) {
+		for _, userID := range []uuid.UUID{managerUser.ID, approver.ID, hr.ID} {
+			promotions, err := FindPromotions(asUser(userID, uuid.Nil), report.ID)
+			assert.NoError(t, err)
+			assert.Len(t, promotions, 1)
+		}
+
+		stranger := createUser(organizationID, users.RoleMember)
+
+		promotions, err := FindPromotions(asUser(stranger.ID, uuid.Nil), report.ID)
+		assert.NoError(t, err)
+		assert.Empty(t, promotions)
+
+		_, err = FindPromotion(asUser(stranger.ID, uuid.Nil), p.ID)
+		assert.Error(t, err)
+	})
+
+	t.Run("approval promotes the employee", func(t *testing.T) {
+		_, err := UpdatePromotion(asManager(), p.ID, promotion.Decide{UserID: managerUser.ID, Approved: true}, eventsource.Metadata{})
+		assert.ErrorContains(t, err, promotion.ErrorNotApprover.Error())
+
+		p, err := UpdatePromotion(asUser(approver.ID, uuid.Nil), p.ID, promotion.Decide{UserID: approver.ID, Approved: true}, eventsource.Metadata{})
+		assert.NoError(t, err)
+		assert.Equal(t, promotion.StatusApproved, p.Status)
+
+		var promoted Employee
+		orm.DB(gctx).First(&promoted, "id = ?", report.ID)
+
+		assert.Equal(t, senior.ID, promoted.EmployeeRoleID)
+		if assert.NotNil(t, promoted.LevelStartAt) {
+			assert.True(t, effectiveAt.Equal(*promoted.LevelStartAt))
+		}
+		assert.NotNil(t, p.AppliedAt)
+	})
+
+	t.Run("future promotions apply on their effective date", func(t *testing.T) {
+		staff := createRole("Staff Engineer", 5)
+
+		future := input
+		future.TargetRoleID = staff.ID
+		future.EffectiveAt = time.Now().Add(48 * time.Hour)
+
+		p, err := ProposePromotion(asManager(), future, eventsource.Metadata{})
+		assert.NoError(t, err)
+
+		p, err = UpdatePromotion(asUser(approver.ID, uuid.Nil), p.ID, promotion.Decide{UserID: approver.ID, Approved: true}, eventsource.Metadata{})
+		assert.NoError(t, err)
+		assert.Equal(t, promotion.StatusApproved, p.Status)
+		assert.Nil(t, p.AppliedAt)
+
+		var current Employee
+		orm.DB(gctx).First(&current, "id = ?", report.ID)
+		assert.Equal(t, senior.ID, current.EmployeeRoleID)
+
+		applied, err := ApplyDuePromotions(gctx, time.Now())
+		assert.NoError(t, err)
+		assert.Empty(t, applied)
+
+		applied, err = ApplyDuePromotions(gctx, future.EffectiveAt)
+		assert.NoError(t, err)
+		if assert.Len(t, applied, 1) {
+			assert.Equal(t, p.ID, applied[0].ID)
+		}
+
+		orm.DB(gctx).First(&current, "id = ?", report.ID)
+		assert.Equal(t, staff.ID, current.EmployeeRoleID)
+
+		applied, err = ApplyDuePromotions(gctx, future.EffectiveAt)
+		assert.NoError(t, err)
+		assert.Empty(t, applied)
+	})
+
+	t.Run("a failed apply is retried", func(t *testing.T) {
+		p, err := ProposePromotion(asManager(), ProposePromotionInput{
+			EmployeeID:    report.ID,
+			TargetRoleID:  engineer.ID,
+			Justification: "Moves back to the planner team",
+			EffectiveAt:   effectiveAt,
+			ApproverIDs:   []uuid.UUID{approver.ID},
+		}, eventsource.Metadata{})
+		assert.NoError(t, err)
+
+		// the role change went through but the promotion was not marked
+		// applied, the retry only records it
+		var current Employee
+		orm.DB(gctx).First(&current, "id = ?", report.ID)
+		orm.DB(gctx).Model(&current).Update("employee_role_id", engineer.ID)
+		orm.DB(gctx).Model(&Promotion{}).Where("id = ?", p.ID).Updates(map[string]interface{}{
+			"status": promotion.StatusApproved,
+		})
+
+		levelStartAt := current.LevelStartAt
+
+		applied, err := ApplyDuePromotions(gctx, time.Now())
+		assert.NoError(t, err)
+		assert.Len(t, applied, 1)
+
+		orm.DB(gctx).First(&current, "id = ?", report.ID)
+		assert.Equal(t, engineer.ID, current.EmployeeRoleID)
+		assert.Equal(t, levelStartAt, current.LevelStartAt)
+	})
+}
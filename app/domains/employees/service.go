@@ -25,6 +25,7 @@ type EmployeeService interface {
 	FindEmployeesByManagerAndIDS(gctx golly.Context, managerID uuid.UUID, employeeIDs ...uuid.UUID) ([]Employee, error)
 	FindEmployeeByID(gctx golly.Context, id uuid.UUID) (Employee, error)
 	FindEmployeeEmailsBySearch(gctx golly.Context, name string) ([]string, error)
+	FindEmployeesForOrganization(gctx golly.Context) ([]Employee, error)
 
 	PluckEmployeeIDsByManagerID(gctx golly.Context, managerID uuid.UUID, scopes ...func(*gorm.DB) *gorm.DB) (uuid.UUIDs, error)
 	PluckIDByUserID(gctx golly.Context, userID uuid.UUID) uuid.UUID
@@ -76,6 +77,16 @@ func (s DefaultEmployeeService) FindEmployeesByIDS(
 	return employees, err
 }
 
+func (s DefaultEmployeeService) FindEmployeesForOrganization(gctx golly.Context) ([]Employee, error) {
+	var employees []Employee
+
+	err := baseEmployeeQuery(gctx).
+		Find(&employees).
+		Error
+
+	return employees, err
+}
+
 func (s DefaultEmployeeService) FindEmployeesForTeam(
 	gctx golly.Context,
 	teamID uuid.UUID,
@@ -21,6 +21,11 @@ func (m *MockEmployeeService) FindEmployeesByIDS(gctx golly.Context, ids uuid.UU
 	return args.Get(0).([]Employee), args.Error(1)
 }
 
+func (m *MockEmployeeService) FindEmployeesForOrganization(gctx golly.Context) ([]Employee, error) {
+	args := m.Called(gctx)
+	return args.Get(0).([]Employee), args.Error(1)
+}
+
 func (m *MockEmployeeService) FindEmployeesForTeam(gctx golly.Context, teamID uuid.UUID, excludeEmployees ...uuid.UUID) ([]Employee, error) {
 	args := m.Called(gctx, teamID, excludeEmployees)
 	return args.Get(0).([]Employee), args.Error(1)
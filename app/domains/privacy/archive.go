@@ -0,0 +1,179 @@
+package privacy
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/json"
+	"html/template"
+	"io"
+	"time"
+)
+
+// WriteArchive writes the report as a ZIP of one JSON file per section
+// and a report.html readable without any tooling
+func WriteArchive(w io.Writer, report Report) error {
+	archive := zip.NewWriter(w)
+
+	files := []struct {
+		name    string
+		content interface{}
+	}{
+		{"employee.json", report.Employee},
+		{"user.json", report.User},
+		{"history.json", report.History},
+		{"feedback_received.json", report.FeedbackReceived},
+		{"feedback_given.json", report.FeedbackGiven},
+		{"summaries.json", report.Summaries},
+		{"audit_events.json", report.AuditEvents},
+		{"llm_usage.json", report.LLMUsage},
+	}
+
+	for _, file := range files {
+		b, err := json.MarshalIndent(file.content, "", "  ")
+		if err != nil {
+			return err
+		}
+
+		if err := writeFile(archive, file.name, report.GeneratedAt, b); err != nil {
+			return err
+		}
+	}
+
+	var html bytes.Buffer
+	if err := reportTemplate.Execute(&html, report); err != nil {
+		return err
+	}
+
+	if err := writeFile(archive, "report.html", report.GeneratedAt, html.Bytes()); err != nil {
+		return err
+	}
+
+	return archive.Close()
+}
+
+// BuildArchive returns the ZIP of the report
+func BuildArchive(report Report) ([]byte, error) {
+	var buf bytes.Buffer
+
+	err := WriteArchive(&buf, report)
+	return buf.Bytes(), err
+}
+
+func writeFile(archive *zip.Writer, name string, modified time.Time, content []byte) error {
+	f, err := archive.CreateHeader(&zip.FileHeader{Name: name, Method: zip.Deflate, Modified: modified})
+	if err != nil {
+		return err
+	}
+
+	_, err = f.Write(content)
+	return err
+}
+
+var reportTemplate = template.Must(template.New("report").Funcs(template.FuncMap{
+	"date": func(t interface{}) string {
+		switch v := t.(type) {
+		case time.Time:
+			return v.Format("2006-01-02 15:04 MST")
+		case *time.Time:
+			if v != nil {
+				return v.Format("2006-01-02 15:04 MST")
+			}
+		}
+		return ""
+	},
+	"json": func(b json.RawMessage) string { return string(b) },
+}).Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>Personal data export</title>
+<style>
+body { font-family: sans-serif; margin: 2em; color: #222; }
+table { border-collapse: collapse; width: 100%; margin-bottom: 2em; }
+th, td { border: 1px solid #ccc; padding: 4px 8px; text-align: left; vertical-align: top; }
+th { background: #f4f4f4; }
+pre { white-space: pre-wrap; margin: 0; }
+</style>
+</head>
+<body>
+<h1>Personal data export</h1>
+<p>Generated {{date .GeneratedAt}}. Every section is also included as JSON in this archive.</p>
+
+{{with .Employee}}
+<h2>Employee record</h2>
+<table>
+<tr><th>Name</th><td>{{.Name}}</td></tr>
+<tr><th>Email</th><td>{{.Email}}</td></tr>
+<tr><th>Worker type</th><td>{{.WorkerType}}</td></tr>
+<tr><th>Level start</th><td>{{date .LevelStartAt}}</td></tr>
+<tr><th>Terminated</th><td>{{date .TerminatedAt}}</td></tr>
+<tr><th>Created</th><td>{{date .CreatedAt}}</td></tr>
+</table>
+{{end}}
+
+{{with .User}}
+<h2>User account</h2>
+<table>
+<tr><th>Name</th><td>{{.FirstName}} {{.LastName}}</td></tr>
+<tr><th>Email</th><td>{{.Email}}</td></tr>
+<tr><th>Role</th><td>{{.Role}}</td></tr>
+<tr><th>Created</th><td>{{date .CreatedAt}}</td></tr>
+</table>
+{{end}}
+
+<h2>History</h2>
+{{if .History}}
+<table>
+<tr><th>When</th><th>Field</th><th>Previous</th><th>Current</th></tr>
+{{range .History}}<tr><td>{{date .ChangedAt}}</td><td>{{.Field}}</td><td>{{.Previous}}</td><td>{{.Current}}</td></tr>
+{{end}}
+</table>
+{{else}}<p>No changes recorded.</p>{{end}}
+
+<h2>Feedback received</h2>
+{{if .FeedbackReceived}}
+<table>
+<tr><th>Requested</th><th>Reviewer</th><th>Status</th><th>Strengths</th><th>Opportunities</th><th>Additional</th></tr>
+{{range .FeedbackReceived}}<tr><td>{{date .CreatedAt}}</td><td>{{if .Reviewer}}{{.Reviewer}}{{else}}Anonymous{{end}}</td><td>{{.Status}}</td><td><pre>{{.Strengths}}</pre></td><td><pre>{{.Opportunities}}</pre></td><td><pre>{{.Additional}}</pre></td></tr>
+{{end}}
+</table>
+{{else}}<p>No feedback received.</p>{{end}}
+
+<h2>Feedback summaries</h2>
+{{if .Summaries}}
+<table>
+<tr><th>Created</th><th>Summary</th><th>Action items</th></tr>
+{{range .Summaries}}<tr><td>{{date .CreatedAt}}</td><td><pre>{{.Summary}}</pre></td><td><ul>{{range .ActionItems}}<li>{{.}}</li>{{end}}</ul></td></tr>
+{{end}}
+</table>
+{{else}}<p>No summaries.</p>{{end}}
+
+<h2>Feedback given</h2>
+{{if .FeedbackGiven}}
+<table>
+<tr><th>Requested</th><th>Status</th><th>Strengths</th><th>Opportunities</th><th>Additional</th></tr>
+{{range .FeedbackGiven}}<tr><td>{{date .CreatedAt}}</td><td>{{.Status}}</td><td><pre>{{.Strengths}}</pre></td><td><pre>{{.Opportunities}}</pre></td><td><pre>{{.Additional}}</pre></td></tr>
+{{end}}
+</table>
+{{else}}<p>No feedback given.</p>{{end}}
+
+<h2>Audit events</h2>
+{{if .AuditEvents}}
+<table>
+<tr><th>When</th><th>Object</th><th>Event</th><th>Changes</th></tr>
+{{range .AuditEvents}}<tr><td>{{date .EventAt}}</td><td>{{.ObjectType}}</td><td>{{.Event}}</td><td><pre>{{json .Changes}}</pre></td></tr>
+{{end}}
+</table>
+{{else}}<p>No audit events.</p>{{end}}
+
+<h2>AI assistant usage</h2>
+{{if .LLMUsage}}
+<table>
+<tr><th>When</th><th>Kind</th><th>Prompt</th><th>Model</th><th>Tokens</th></tr>
+{{range .LLMUsage}}<tr><td>{{date .CreatedAt}}</td><td>{{.Kind}}</td><td>{{.PromptType}}</td><td>{{.Model}}</td><td>{{.TotalTokens}}</td></tr>
+{{end}}
+</table>
+{{else}}<p>No usage recorded.</p>{{end}}
+</body>
+</html>
+`))
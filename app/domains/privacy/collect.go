@@ -0,0 +1,446 @@
+package privacy
+
+import (
+	"encoding/json"
+	"strings"
+	"time"
+
+	"github.com/golly-go/golly"
+	"github.com/golly-go/plugins/orm"
+	"github.com/google/uuid"
+	"github.com/mitchrodrigues/talent-review-backend/app/domains/accounts"
+	"github.com/mitchrodrigues/talent-review-backend/app/domains/employees"
+	"github.com/mitchrodrigues/talent-review-backend/app/domains/employees/employee"
+	"github.com/mitchrodrigues/talent-review-backend/app/domains/reviews"
+	"github.com/mitchrodrigues/talent-review-backend/app/domains/reviews/feedback"
+	"github.com/mitchrodrigues/talent-review-backend/app/domains/tara"
+	"github.com/mitchrodrigues/talent-review-backend/app/utils/crypto"
+	"github.com/mitchrodrigues/talent-review-backend/app/utils/esbackend"
+	"gorm.io/gorm"
+)
+
+// Subject is the person an export is about, an employee, their user or
+// both when the employee has signed in
+type Subject struct {
+	OrganizationID uuid.UUID
+
+	Employee *employees.Employee
+	User     *accounts.User
+}
+
+// Report is everything stored about a subject, each section is written
+// as its own JSON file in the archive
+type Report struct {
+	GeneratedAt time.Time `json:"generatedAt"`
+
+	Employee *EmployeeRecord `json:"employee,omitempty"`
+	User     *UserRecord     `json:"user,omitempty"`
+
+	History          []HistoryRecord  `json:"history"`
+	FeedbackReceived []FeedbackRecord `json:"feedbackReceived"`
+	FeedbackGiven    []FeedbackRecord `json:"feedbackGiven"`
+	Summaries        []SummaryRecord  `json:"summaries"`
+	AuditEvents      []AuditRecord    `json:"auditEvents"`
+	LLMUsage         []UsageRecord    `json:"llmUsage"`
+}
+
+type EmployeeRecord struct {
+	ID             uuid.UUID                   `json:"id"`
+	Name           string                      `json:"name"`
+	Email          string                      `json:"email"`
+	WorkerType     employee.EmployeeWorkerType `json:"workerType"`
+	ManagerID      *uuid.UUID                  `json:"managerID"`
+	TeamID         *uuid.UUID                  `json:"teamID"`
+	EmployeeRoleID uuid.UUID                   `json:"roleID"`
+	LevelStartAt   *time.Time                  `json:"levelStartAt"`
+	TerminatedAt   *time.Time                  `json:"terminatedAt"`
+	ErasedAt       *time.Time                  `json:"erasedAt"`
+	CreatedAt      time.Time                   `json:"createdAt"`
+}
+
+type UserRecord struct {
+	ID        uuid.UUID  `json:"id"`
+	FirstName string     `json:"firstName"`
+	LastName  string     `json:"lastName"`
+	Email     string     `json:"email"`
+	Role      string     `json:"role"`
+	InvitedAt *time.Time `json:"invitedAt"`
+	CreatedAt time.Time  `json:"createdAt"`
+}
+
+type HistoryRecord struct {
+	Field     string      `json:"field"`
+	Previous  interface{} `json:"previous"`
+	Current   interface{} `json:"current"`
+	ChangedBy uuid.UUID   `json:"changedBy"`
+	ChangedAt time.Time   `json:"changedAt"`
+}
+
+// FeedbackRecord is a feedback request and its answer, Reviewer is left
+// out of feedback received that was not attributed to protect the
+// reviewer
+type FeedbackRecord struct {
+	ID              uuid.UUID  `json:"id"`
+	EmployeeID      uuid.UUID  `json:"employeeID"`
+	Reviewer        string     `json:"reviewer,omitempty"`
+	Visibility      string     `json:"visibility"`
+	Status          string     `json:"status"`
+	CollectionEndAt time.Time  `json:"collectionEndAt"`
+	SubmittedAt     *time.Time `json:"submittedAt"`
+	DeclinedAt      *time.Time `json:"declinedAt"`
+
+	Strengths     string `json:"strengths,omitempty"`
+	Opportunities string `json:"opportunities,omitempty"`
+	Additional    string `json:"additional,omitempty"`
+	Rating        int    `json:"rating,omitempty"`
+
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+type SummaryRecord struct {
+	FeedbackID  uuid.UUID `json:"feedbackID"`
+	Summary     string    `json:"summary"`
+	ActionItems []string  `json:"actionItems"`
+	CreatedAt   time.Time `json:"createdAt"`
+}
+
+type AuditRecord struct {
+	ID         uuid.UUID       `json:"id"`
+	Event      string          `json:"event"`
+	ObjectType string          `json:"objectType"`
+	ObjectID   uuid.UUID       `json:"objectID"`
+	UserID     *uuid.UUID      `json:"userID"`
+	Changes    json.RawMessage `json:"changes"`
+	EventAt    time.Time       `json:"eventAt"`
+}
+
+type UsageRecord struct {
+	Kind             string    `json:"kind"`
+	Model            string    `json:"model"`
+	PromptType       string    `json:"promptType"`
+	PromptTokens     int       `json:"promptTokens"`
+	CompletionTokens int       `json:"completionTokens"`
+	TotalTokens      int       `json:"totalTokens"`
+	CreatedAt        time.Time `json:"createdAt"`
+}
+
+// FindSubject loads the employee and the user of an export within the
+// organization, either id can be nil and the other side is looked up
+// through the employee user link
+func FindSubject(gctx golly.Context, organizationID uuid.UUID, employeeID, userID *uuid.UUID) (Subject, error) {
+	subject := Subject{OrganizationID: organizationID}
+
+	db := func() *gorm.DB { return orm.NewDB(gctx).Where("organization_id = ?", organizationID) }
+
+	if employeeID != nil {
+		var emp employees.Employee
+		if err := db().First(&emp, "id = ?", *employeeID).Error; err != nil {
+			return subject, err
+		}
+		subject.Employee = &emp
+
+		if userID == nil {
+			userID = emp.UserID
+		}
+	}
+
+	if userID != nil {
+		var user accounts.User
+		if err := db().First(&user, "id = ?", *userID).Error; err != nil {
+			return subject, err
+		}
+		subject.User = &user
+	}
+
+	if subject.Employee == nil && subject.User != nil {
+		var emps []employees.Employee
+		if err := db().Where("user_id = ?", subject.User.ID).Limit(1).Find(&emps).Error; err != nil {
+			return subject, err
+		}
+
+		if len(emps) > 0 {
+			subject.Employee = &emps[0]
+		}
+	}
+
+	if subject.Employee == nil && subject.User == nil {
+		return subject, gorm.ErrRecordNotFound
+	}
+
+	return subject, nil
+}
+
+// Collect gathers the report of the subject. It reads the tables
+// directly scoped to the organization of the subject as it runs in the
+// background or from the CLI, without the identity of the requester
+func Collect(gctx golly.Context, subject Subject) (Report, error) {
+	report := Report{
+		GeneratedAt:      time.Now().UTC(),
+		History:          []HistoryRecord{},
+		FeedbackReceived: []FeedbackRecord{},
+		FeedbackGiven:    []FeedbackRecord{},
+		Summaries:        []SummaryRecord{},
+		AuditEvents:      []AuditRecord{},
+		LLMUsage:         []UsageRecord{},
+	}
+
+	collectors := []func(golly.Context, Subject, *Report) error{
+		collectEmployee,
+		collectUser,
+		collectFeedback,
+		collectAuditEvents,
+		collectUsage,
+	}
+
+	for _, collect := range collectors {
+		if err := collect(gctx, subject, &report); err != nil {
+			return report, err
+		}
+	}
+
+	return report, nil
+}
+
+func collectEmployee(gctx golly.Context, subject Subject, report *Report) error {
+	emp := subject.Employee
+	if emp == nil {
+		return nil
+	}
+
+	report.Employee = &EmployeeRecord{
+		ID:             emp.ID,
+		Name:           emp.Name,
+		Email:          emp.Email,
+		WorkerType:     emp.WorkerType,
+		ManagerID:      emp.ManagerID,
+		TeamID:         emp.TeamID,
+		EmployeeRoleID: emp.EmployeeRoleID,
+		LevelStartAt:   emp.LevelStartAt,
+		TerminatedAt:   emp.TerminatedAt,
+		ErasedAt:       emp.ErasedAt,
+		CreatedAt:      emp.CreatedAt,
+	}
+
+	var history []employee.EmployeeHistory
+
+	err := orm.NewDB(gctx).
+		Model(&employee.EmployeeHistory{}).
+		Where("employee_id = ?", emp.ID).
+		Order("created_at").
+		Find(&history).
+		Error
+
+	for _, h := range history {
+		report.History = append(report.History, HistoryRecord{
+			Field:     h.Change.Field,
+			Previous:  h.Change.Previous,
+			Current:   h.Change.Current,
+			ChangedBy: h.UserID,
+			ChangedAt: h.CreatedAt,
+		})
+	}
+
+	return err
+}
+
+func collectUser(gctx golly.Context, subject Subject, report *Report) error {
+	user := subject.User
+	if user == nil {
+		return nil
+	}
+
+	report.User = &UserRecord{
+		ID:        user.ID,
+		FirstName: user.FirstName,
+		LastName:  user.LastName,
+		Email:     user.Email,
+		Role:      user.Role,
+		InvitedAt: user.InvitedAt,
+		CreatedAt: user.CreatedAt,
+	}
+	return nil
+}
+
+func collectFeedback(gctx golly.Context, subject Subject, report *Report) error {
+	if subject.Employee != nil {
+		var received []reviews.Feedback
+
+		err := orm.NewDB(gctx).
+			Preload("Details").
+			Preload("Summary").
+			Where("organization_id = ? AND employee_id = ?", subject.OrganizationID, subject.Employee.ID).
+			Order("created_at").
+			Find(&received).
+			Error
+
+		if err != nil {
+			return err
+		}
+
+		for _, fb := range received {
+			record := feedbackRecord(fb)
+			if fb.Visibility != "" && fb.Visibility != feedback.VisibilityAttributed {
+				record.Reviewer = ""
+			}
+			report.FeedbackReceived = append(report.FeedbackReceived, record)
+
+			if fb.Summary.ID != uuid.Nil {
+				report.Summaries = append(report.Summaries, SummaryRecord{
+					FeedbackID:  fb.ID,
+					Summary:     fb.Summary.Summary,
+					ActionItems: actionItems(fb.Summary.ActionItems),
+					CreatedAt:   fb.Summary.CreatedAt,
+				})
+			}
+		}
+	}
+
+	emails := subjectEmails(subject)
+	if len(emails) == 0 {
+		return nil
+	}
+
+	var given []reviews.Feedback
+
+	err := orm.NewDB(gctx).
+		Preload("Details").
+		Where("organization_id = ? AND LOWER(email) IN ?", subject.OrganizationID, emails).
+		Order("created_at").
+		Find(&given).
+		Error
+
+	for _, fb := range given {
+		report.FeedbackGiven = append(report.FeedbackGiven, feedbackRecord(fb))
+	}
+
+	return err
+}
+
+func collectAuditEvents(gctx golly.Context, subject Subject, report *Report) error {
+	objectIDs := []uuid.UUID{}
+
+	if subject.Employee != nil {
+		objectIDs = append(objectIDs, subject.Employee.ID)
+	}
+
+	// events written without an identity carry the nil user id, the
+	// actor clause is only added for a subject with a user
+	about := orm.NewDB(gctx).Where("aggregate_id IN ?", objectIDs)
+
+	if subject.User != nil {
+		about = orm.NewDB(gctx).Where("aggregate_id IN ? OR user_id = ?", append(objectIDs, subject.User.ID), subject.User.ID)
+	}
+
+	var events []esbackend.Event
+
+	err := orm.NewDB(gctx).
+		Model(&esbackend.Event{}).
+		Where("organization_id = ?", subject.OrganizationID).
+		Where(about).
+		Order("created_at").
+		Find(&events).
+		Error
+
+	if err != nil {
+		return err
+	}
+
+	for _, evt := range events {
+		changes, err := crypto.OpenPersonalData(gctx.Context(), orm.NewDB(gctx), evt.RawData.RawMessage)
+		if err != nil {
+			return err
+		}
+
+		report.AuditEvents = append(report.AuditEvents, AuditRecord{
+			ID:         evt.ID,
+			Event:      evt.Type,
+			ObjectType: strings.Split(evt.AggregateType, ".")[0],
+			ObjectID:   evt.AggregateID,
+			UserID:     evt.UserID,
+			Changes:    changes,
+			EventAt:    evt.CreatedAt,
+		})
+	}
+
+	return nil
+}
+
+func collectUsage(gctx golly.Context, subject Subject, report *Report) error {
+	if subject.User == nil {
+		return nil
+	}
+
+	var usages []tara.Usage
+
+	err := orm.NewDB(gctx).
+		Model(&tara.Usage{}).
+		Where("organization_id = ? AND user_id = ?", subject.OrganizationID, subject.User.ID).
+		Order("created_at").
+		Find(&usages).
+		Error
+
+	for _, usage := range usages {
+		report.LLMUsage = append(report.LLMUsage, UsageRecord{
+			Kind:             usage.Kind,
+			Model:            usage.Model,
+			PromptType:       usage.PromptType,
+			PromptTokens:     usage.PromptTokens,
+			CompletionTokens: usage.CompletionTokens,
+			TotalTokens:      usage.TotalTokens,
+			CreatedAt:        usage.CreatedAt,
+		})
+	}
+
+	return err
+}
+
+func feedbackRecord(fb reviews.Feedback) FeedbackRecord {
+	return FeedbackRecord{
+		ID:              fb.ID,
+		EmployeeID:      fb.EmployeeID,
+		Reviewer:        fb.Email,
+		Visibility:      fb.Visibility,
+		Status:          feedbackStatus(fb),
+		CollectionEndAt: fb.CollectionEndAt,
+		SubmittedAt:     fb.SubmittedAt,
+		DeclinedAt:      fb.DeclinedAt,
+		Strengths:       fb.Details.Strengths,
+		Opportunities:   fb.Details.Opportunities,
+		Additional:      fb.Details.Additional,
+		Rating:          fb.Details.Rating,
+		CreatedAt:       fb.CreatedAt,
+	}
+}
+
+func feedbackStatus(fb reviews.Feedback) string {
+	switch {
+	case fb.SubmittedAt != nil:
+		return "submitted"
+	case fb.DeclinedAt != nil:
+		return "declined"
+	default:
+		return "pending"
+	}
+}
+
+func actionItems(value string) []string {
+	items := []string{}
+	if err := json.Unmarshal([]byte(value), &items); err != nil && value != "" {
+		return []string{value}
+	}
+	return items
+}
+
+func subjectEmails(subject Subject) []string {
+	emails := []string{}
+
+	if subject.Employee != nil && subject.Employee.Email != crypto.Erased {
+		emails = append(emails, strings.ToLower(subject.Employee.Email))
+	}
+
+	if subject.User != nil && subject.User.Email != "" {
+		emails = append(emails, strings.ToLower(subject.User.Email))
+	}
+
+	return emails
+}
@@ -0,0 +1,91 @@
+package export
+
+import (
+	"time"
+
+	"github.com/golly-go/golly"
+	"github.com/golly-go/plugins/eventsource"
+	"github.com/golly-go/plugins/orm"
+	"github.com/google/uuid"
+	"github.com/mitchrodrigues/talent-review-backend/app/utils/esbackend"
+)
+
+type Status string
+
+const (
+	StatusPending Status = "PENDING"
+	StatusReady   Status = "READY"
+	StatusFailed  Status = "FAILED"
+
+	// Retention is how long a ready archive can be downloaded
+	Retention = 7 * 24 * time.Hour
+)
+
+// Aggregate is a data subject access export of everything stored about an
+// employee and their user, built in the background into a ZIP archive
+type Aggregate struct {
+	eventsource.AggregateBase
+
+	orm.ModelUUID
+
+	OrganizationID uuid.UUID
+
+	// RequestedBy is the user who asked for the export
+	RequestedBy uuid.UUID
+
+	EmployeeID *uuid.UUID
+	UserID     *uuid.UUID
+
+	Status Status
+	Error  string
+
+	// Archive is the ZIP, it is only stored in the read model
+	Archive []byte
+	Size    int
+
+	CompletedAt *time.Time
+	ExpiresAt   *time.Time
+}
+
+func (*Aggregate) Topic() string                             { return "events.data_exports" }
+func (*Aggregate) Repo(golly.Context) eventsource.Repository { return esbackend.PostgresRepository{} }
+func (*Aggregate) TableName() string                         { return "data_exports" }
+
+func (export *Aggregate) GetID() string   { return export.ID.String() }
+func (export *Aggregate) SetID(id string) { export.ID, _ = uuid.Parse(id) }
+
+func (export *Aggregate) Apply(ctx golly.Context, evt eventsource.Event) {
+	switch event := evt.Data.(type) {
+	case Requested:
+		export.ID = event.ID
+		export.OrganizationID = event.OrganizationID
+		export.RequestedBy = event.RequestedBy
+		export.EmployeeID = event.EmployeeID
+		export.UserID = event.UserID
+		export.Status = StatusPending
+
+		export.CreatedAt = evt.CreatedAt
+
+	case Completed:
+		expiresAt := evt.CreatedAt.Add(Retention)
+
+		export.Status = StatusReady
+		export.Archive = event.Archive
+		export.Size = event.Size
+		export.CompletedAt = &evt.CreatedAt
+		export.ExpiresAt = &expiresAt
+
+	case Failed:
+		export.Status = StatusFailed
+		export.Error = event.Error
+		export.CompletedAt = &evt.CreatedAt
+	}
+	export.UpdatedAt = evt.CreatedAt
+}
+
+// Downloadable is whether the archive is ready and not expired yet
+func (export *Aggregate) Downloadable(now time.Time) bool {
+	return export.Status == StatusReady && export.ExpiresAt != nil && now.Before(*export.ExpiresAt)
+}
+
+var _ eventsource.Aggregate = &Aggregate{}
@@ -0,0 +1,83 @@
+package export
+
+import (
+	"fmt"
+
+	"github.com/golly-go/golly"
+	"github.com/golly-go/golly/errors"
+	"github.com/golly-go/plugins/eventsource"
+	"github.com/google/uuid"
+)
+
+var (
+	ErrorSubjectRequired   = fmt.Errorf("export an employee or a user")
+	ErrorRequesterRequired = fmt.Errorf("export requester is required")
+	ErrorNotPending        = fmt.Errorf("export has already finished")
+	ErrorArchiveRequired   = fmt.Errorf("export archive is empty")
+)
+
+type Request struct {
+	OrganizationID uuid.UUID `validate:"required"`
+	RequestedBy    uuid.UUID
+
+	EmployeeID *uuid.UUID
+	UserID     *uuid.UUID
+}
+
+func (cmd Request) Validate(ctx golly.Context, aggregate eventsource.Aggregate) error {
+	switch {
+	case cmd.EmployeeID == nil && cmd.UserID == nil:
+		return errors.WrapUnprocessable(ErrorSubjectRequired)
+	case cmd.RequestedBy == uuid.Nil:
+		return errors.WrapUnprocessable(ErrorRequesterRequired)
+	}
+	return nil
+}
+
+func (cmd Request) Perform(ctx golly.Context, aggregate eventsource.Aggregate) error {
+	id, _ := uuid.NewV7()
+
+	eventsource.Apply(ctx, aggregate, Requested{
+		ID:             id,
+		OrganizationID: cmd.OrganizationID,
+		RequestedBy:    cmd.RequestedBy,
+		EmployeeID:     cmd.EmployeeID,
+		UserID:         cmd.UserID,
+	})
+	return nil
+}
+
+type Complete struct {
+	Archive []byte
+}
+
+func (cmd Complete) Validate(ctx golly.Context, aggregate eventsource.Aggregate) error {
+	switch {
+	case aggregate.(*Aggregate).Status != StatusPending:
+		return errors.WrapUnprocessable(ErrorNotPending)
+	case len(cmd.Archive) == 0:
+		return errors.WrapUnprocessable(ErrorArchiveRequired)
+	}
+	return nil
+}
+
+func (cmd Complete) Perform(ctx golly.Context, aggregate eventsource.Aggregate) error {
+	eventsource.Apply(ctx, aggregate, Completed{Size: len(cmd.Archive), Archive: cmd.Archive})
+	return nil
+}
+
+type Fail struct {
+	Error string
+}
+
+func (cmd Fail) Validate(ctx golly.Context, aggregate eventsource.Aggregate) error {
+	if aggregate.(*Aggregate).Status != StatusPending {
+		return errors.WrapUnprocessable(ErrorNotPending)
+	}
+	return nil
+}
+
+func (cmd Fail) Perform(ctx golly.Context, aggregate eventsource.Aggregate) error {
+	eventsource.Apply(ctx, aggregate, Failed(cmd))
+	return nil
+}
@@ -0,0 +1,60 @@
+package export
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/golly-go/golly"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRequestValidate(t *testing.T) {
+	gctx := golly.NewContext(context.TODO())
+
+	employeeID := uuid.New()
+
+	assert.NoError(t, Request{RequestedBy: uuid.New(), EmployeeID: &employeeID}.Validate(gctx, &Aggregate{}))
+	assert.ErrorContains(t, Request{RequestedBy: uuid.New()}.Validate(gctx, &Aggregate{}), ErrorSubjectRequired.Error())
+	assert.ErrorContains(t, Request{EmployeeID: &employeeID}.Validate(gctx, &Aggregate{}), ErrorRequesterRequired.Error())
+}
+
+func TestCompleteAndFail(t *testing.T) {
+	gctx := golly.NewContext(context.TODO())
+
+	employeeID := uuid.New()
+
+	pending := func() *Aggregate {
+		export := &Aggregate{}
+		assert.NoError(t, Request{OrganizationID: uuid.New(), RequestedBy: uuid.New(), EmployeeID: &employeeID}.Perform(gctx, export))
+		return export
+	}
+
+	t.Run("complete keeps the archive until it expires", func(t *testing.T) {
+		export := pending()
+
+		assert.ErrorContains(t, Complete{}.Validate(gctx, export), ErrorArchiveRequired.Error())
+
+		cmd := Complete{Archive: []byte("zip")}
+		assert.NoError(t, cmd.Validate(gctx, export))
+		assert.NoError(t, cmd.Perform(gctx, export))
+
+		assert.Equal(t, StatusReady, export.Status)
+		assert.Equal(t, 3, export.Size)
+		assert.True(t, export.Downloadable(time.Now()))
+		assert.False(t, export.Downloadable(time.Now().Add(Retention+time.Hour)))
+
+		assert.ErrorContains(t, cmd.Validate(gctx, export), ErrorNotPending.Error())
+	})
+
+	t.Run("fail", func(t *testing.T) {
+		export := pending()
+
+		assert.NoError(t, Fail{Error: "boom"}.Perform(gctx, export))
+		assert.Equal(t, StatusFailed, export.Status)
+		assert.False(t, export.Downloadable(time.Now()))
+
+		assert.ErrorContains(t, Fail{}.Validate(gctx, export), ErrorNotPending.Error())
+	})
+}
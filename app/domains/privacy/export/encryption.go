@@ -0,0 +1,48 @@
+package export
+
+import (
+	"encoding/base64"
+
+	"github.com/mitchrodrigues/talent-review-backend/app/utils/crypto"
+	"gorm.io/gorm"
+)
+
+// The archive holds everything stored about the subject so it is
+// encrypted at rest with the data key of the organization, archives
+// stored before read back as is
+
+func (export *Aggregate) BeforeSave(tx *gorm.DB) error {
+	if len(export.Archive) == 0 || crypto.Provider() == nil || crypto.IsEncrypted(string(export.Archive)) {
+		return nil
+	}
+
+	value, err := crypto.Encrypt(tx.Statement.Context, tx, export.OrganizationID, base64.StdEncoding.EncodeToString(export.Archive))
+	if err != nil {
+		return err
+	}
+
+	export.Archive = []byte(value)
+	return nil
+}
+
+func (export *Aggregate) AfterSave(tx *gorm.DB) error { return export.decryptArchive(tx) }
+func (export *Aggregate) AfterFind(tx *gorm.DB) error { return export.decryptArchive(tx) }
+
+func (export *Aggregate) decryptArchive(tx *gorm.DB) error {
+	if !crypto.IsEncrypted(string(export.Archive)) {
+		return nil
+	}
+
+	value, err := crypto.Decrypt(tx.Statement.Context, tx, export.OrganizationID, string(export.Archive))
+	if err != nil {
+		return err
+	}
+
+	archive, err := base64.StdEncoding.DecodeString(value)
+	if err != nil {
+		return err
+	}
+
+	export.Archive = archive
+	return nil
+}
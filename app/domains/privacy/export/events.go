@@ -0,0 +1,25 @@
+package export
+
+import (
+	"github.com/google/uuid"
+)
+
+type Requested struct {
+	ID             uuid.UUID  `json:"id"`
+	OrganizationID uuid.UUID  `json:"organizationID"`
+	RequestedBy    uuid.UUID  `json:"requestedBy"`
+	EmployeeID     *uuid.UUID `json:"employeeID"`
+	UserID         *uuid.UUID `json:"userID"`
+}
+
+type Completed struct {
+	Size int `json:"size"`
+
+	// The archive holds the personal data of the subject so it is kept
+	// out of the event store
+	Archive []byte `json:"-"`
+}
+
+type Failed struct {
+	Error string `json:"error"`
+}
@@ -0,0 +1,197 @@
+package privacy
+
+import (
+	"cmp"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/golly-go/golly"
+	"github.com/golly-go/golly/errors"
+	"github.com/golly-go/plugins/eventsource"
+	"github.com/golly-go/plugins/orm"
+	"github.com/google/uuid"
+	"github.com/mitchrodrigues/talent-review-backend/app/domains/accounts"
+	"github.com/mitchrodrigues/talent-review-backend/app/domains/privacy/export"
+	"github.com/mitchrodrigues/talent-review-backend/app/utils/identity"
+)
+
+var (
+	ErrorUnknownSubject = fmt.Errorf("employee or user not found in your organization")
+)
+
+type DataExport struct {
+	export.Aggregate
+}
+
+func (DataExport) TableName() string { return "data_exports" }
+
+func (e DataExport) FileName() string {
+	return fmt.Sprintf("data-export-%s.zip", e.ID)
+}
+
+// DownloadPath is where the archive of the export is served
+func (e DataExport) DownloadPath() string {
+	return fmt.Sprintf("/privacy/exports/%s", e.ID)
+}
+
+// RequestDataExport starts the export of everything stored about an
+// employee or user to answer a data subject access request, the archive
+// is built in the background by DataExportSubscription. Only HR and
+// admins can export
+func RequestDataExport(gctx golly.Context, employeeID, userID *uuid.UUID, metadata eventsource.Metadata) (DataExport, error) {
+	var exp DataExport
+
+	requester, err := accounts.RequireHR(gctx)
+	if err != nil {
+		return exp, err
+	}
+
+	subject, err := FindSubject(gctx, requester.OrganizationID, employeeID, userID)
+	if err != nil {
+		return exp, errors.WrapNotFound(ErrorUnknownSubject)
+	}
+
+	cmd := export.Request{
+		OrganizationID: requester.OrganizationID,
+		RequestedBy:    requester.ID,
+	}
+
+	if subject.Employee != nil {
+		cmd.EmployeeID = &subject.Employee.ID
+	}
+
+	if subject.User != nil {
+		cmd.UserID = &subject.User.ID
+	}
+
+	err = eventsource.Call(gctx, &exp.Aggregate, cmd, metadata)
+	return exp, err
+}
+
+func DataExportSubscription(gctx golly.Context, agg eventsource.Aggregate, evt eventsource.Event) error {
+	switch evt.Data.(type) {
+	case export.Requested:
+		exp := DataExport{Aggregate: *agg.(*export.Aggregate)}
+
+		go func(gctx golly.Context, exp DataExport) {
+			if err := BuildDataExport(gctx, &exp); err != nil {
+				gctx.Logger().Warnf("cannot build data export %s %v", exp.ID, err)
+			}
+		}(gctx, exp)
+	}
+	return nil
+}
+
+// BuildDataExport collects the report of a pending export and stores its
+// archive, failures are recorded on the export so HR can request a new one
+func BuildDataExport(gctx golly.Context, exp *DataExport) error {
+	archive, err := buildArchive(gctx, exp)
+	if err != nil {
+		if ferr := eventsource.Call(gctx, &exp.Aggregate, export.Fail{Error: err.Error()}, eventsource.Metadata{}); ferr != nil {
+			return ferr
+		}
+		return err
+	}
+
+	return eventsource.Call(gctx, &exp.Aggregate, export.Complete{Archive: archive}, eventsource.Metadata{})
+}
+
+func buildArchive(gctx golly.Context, exp *DataExport) ([]byte, error) {
+	subject, err := FindSubject(gctx, exp.OrganizationID, exp.EmployeeID, exp.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	report, err := Collect(gctx, subject)
+	if err != nil {
+		return nil, err
+	}
+
+	return BuildArchive(report)
+}
+
+// FindDataExport finds an export of the organization of the current user,
+// only HR and admins can see exports
+func FindDataExport(gctx golly.Context, id uuid.UUID) (DataExport, error) {
+	var exp DataExport
+
+	if _, err := accounts.RequireHR(gctx); err != nil {
+		return exp, err
+	}
+
+	err := orm.DB(gctx).
+		Model(&exp).
+		Where("organization_id = ?", identity.FromContext(gctx).OrganizationID).
+		First(&exp, "id = ?", id).
+		Error
+
+	return exp, errors.WrapNotFound(err)
+}
+
+// FindDataExports lists the exports of the organization, newest first and
+// without their archives
+func FindDataExports(gctx golly.Context) ([]DataExport, error) {
+	var exports []DataExport
+
+	if _, err := accounts.RequireHR(gctx); err != nil {
+		return exports, err
+	}
+
+	err := orm.DB(gctx).
+		Model(&DataExport{}).
+		Omit("archive").
+		Where("organization_id = ?", identity.FromContext(gctx).OrganizationID).
+		Order("created_at DESC").
+		Find(&exports).
+		Error
+
+	return exports, err
+}
+
+// ExpireDataExports drops the archives of the exports that expired before
+// now, the exports themselves are kept as the record of the request. It
+// returns the number of archives dropped
+func ExpireDataExports(gctx golly.Context, now time.Time) (int64, error) {
+	result := orm.NewDB(gctx).
+		Model(&DataExport{}).
+		Where("archive IS NOT NULL AND expires_at < ?", now).
+		UpdateColumn("archive", nil)
+
+	return result.RowsAffected, result.Error
+}
+
+// dropDataExports drops the archives of the exports of the organization
+// completed before the cutoff
+func dropDataExports(gctx golly.Context, organizationID uuid.UUID, before time.Time) (int64, error) {
+	result := orm.NewDB(gctx).
+		Model(&DataExport{}).
+		Where("organization_id = ? AND archive IS NOT NULL AND completed_at < ?", organizationID, before).
+		UpdateColumn("archive", nil)
+
+	return result.RowsAffected, result.Error
+}
+
+// DownloadDataExport serves the archive of a ready export
+func DownloadDataExport(wctx golly.WebContext) {
+	id, err := uuid.Parse(wctx.URLParam("id"))
+	if err != nil {
+		wctx.RenderStatus(http.StatusNotFound)
+		return
+	}
+
+	exp, err := FindDataExport(wctx.Context, id)
+	if err != nil {
+		wctx.RenderStatus(cmp.Or(errors.Unwrap(err).Status, http.StatusNotFound))
+		return
+	}
+
+	if !exp.Downloadable(time.Now()) {
+		wctx.RenderStatus(http.StatusGone)
+		return
+	}
+
+	wctx.AddHeader("Content-Type", "application/zip")
+	wctx.AddHeader("Content-Disposition", fmt.Sprintf("attachment; filename=%q", exp.FileName()))
+	wctx.RenderData(exp.Archive)
+}
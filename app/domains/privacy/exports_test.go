@@ -0,0 +1,263 @@
+package privacy
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/golly-go/golly"
+	"github.com/golly-go/plugins/eventsource"
+	"github.com/golly-go/plugins/orm"
+	"github.com/google/uuid"
+	"github.com/mitchrodrigues/talent-review-backend/app/domains/accounts"
+	"github.com/mitchrodrigues/talent-review-backend/app/domains/accounts/users"
+	"github.com/mitchrodrigues/talent-review-backend/app/domains/employees"
+	"github.com/mitchrodrigues/talent-review-backend/app/domains/employees/employee"
+	"github.com/mitchrodrigues/talent-review-backend/app/domains/privacy/export"
+	"github.com/mitchrodrigues/talent-review-backend/app/domains/reviews"
+	"github.com/mitchrodrigues/talent-review-backend/app/domains/reviews/feedback"
+	"github.com/mitchrodrigues/talent-review-backend/app/domains/tara"
+	"github.com/mitchrodrigues/talent-review-backend/app/utils/crypto"
+	"github.com/mitchrodrigues/talent-review-backend/app/utils/esbackend"
+	"github.com/mitchrodrigues/talent-review-backend/app/utils/identity"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDataExport(t *testing.T) {
+	defer crypto.UseProvider(crypto.NewTestKeyProvider())()
+
+	gctx := orm.CreateTestContext(golly.NewContext(context.TODO()),
+		DataExport{}, employees.Employee{}, accounts.User{}, employee.EmployeeHistory{},
+		reviews.Feedback{}, reviews.FeedbackDetails{}, reviews.FeedbackSummary{}, tara.Usage{}, esbackend.Event{},
+		crypto.DataKey{})
+
+	organizationID := uuid.New()
+
+	createUser := func(email, role string) accounts.User {
+		user := accounts.User{Aggregate: users.Aggregate{OrganizationID: organizationID, Email: email, FirstName: "Jane", Role: role}}
+		orm.DB(gctx).Create(&user)
+		return user
+	}
+
+	hr := createUser("hr@example.com", users.RoleHR)
+	member := createUser("member@example.com", users.RoleMember)
+	janeUser := createUser("jane@example.com", users.RoleMember)
+
+	jane := employees.NewTestEmployee(uuid.New(), organizationID, "jane@example.com", &janeUser.ID)
+	jane.Name = "Jane Smith"
+	orm.DB(gctx).Create(&jane)
+
+	orm.DB(gctx).Create(&employee.EmployeeHistory{
+		ModelUUID:  orm.ModelUUID{ID: uuid.New()},
+		EmployeeID: jane.ID,
+		UserID:     hr.ID,
+		Change:     employee.ChangeData{Field: "title", Previous: "Engineer", Current: "Senior Engineer"},
+	})
+
+	createFeedback := func(aboutID uuid.UUID, email, visibility, strengths string) {
+		now := time.Now()
+
+		fb := reviews.Feedback{Aggregate: feedback.Aggregate{
+			ModelUUID:       orm.ModelUUID{ID: uuid.New()},
+			Code:            uuid.NewString(),
+			Email:           email,
+			EmployeeID:      aboutID,
+			OrganizationID:  organizationID,
+			CollectionEndAt: now,
+			SubmittedAt:     &now,
+			Visibility:      visibility,
+		}}
+		orm.DB(gctx).Create(&fb)
+
+		orm.DB(gctx).Create(&reviews.FeedbackDetails{FeedbackDetails: feedback.FeedbackDetails{
+			ModelUUID:      orm.ModelUUID{ID: uuid.New()},
+			EmployeeID:     aboutID,
+			FeedbackID:     fb.ID,
+			OrganizationID: organizationID,
+			Strengths:      strengths,
+		}})
+
+		orm.DB(gctx).Create(&reviews.FeedbackSummary{FeedbackSummary: feedback.FeedbackSummary{
+			ModelUUID:      orm.ModelUUID{ID: uuid.New()},
+			EmployeeID:     aboutID,
+			FeedbackID:     fb.ID,
+			OrganizationID: organizationID,
+			Summary:        "Summary of " + strengths,
+			ActionItems:    `["Delegate more"]`,
+		}})
+	}
+
+	createFeedback(jane.ID, "peer@example.com", feedback.VisibilityAnonymous, "Runs great planning meetings.")
+	createFeedback(uuid.New(), "jane@example.com", feedback.VisibilityAttributed, "Always helpful in reviews.")
+	createFeedback(uuid.New(), "someone@example.com", feedback.VisibilityAttributed, "Not about jane.")
+
+	orm.DB(gctx).Create(&tara.Usage{
+		ModelUUID:      orm.ModelUUID{ID: uuid.New()},
+		OrganizationID: organizationID,
+		UserID:         janeUser.ID,
+		Kind:           "chat",
+		Model:          "test-model",
+		PromptType:     "coach",
+		TotalTokens:    42,
+	})
+
+	orm.DB(gctx).Create(&esbackend.Event{
+		ModelUUID:      orm.ModelUUID{ID: uuid.New()},
+		AggregateID:    jane.ID,
+		AggregateType:  "employee.Aggregate",
+		Type:           "employee.TeamUpdated",
+		OrganizationID: &organizationID,
+		UserID:         &hr.ID,
+	})
+
+	// events written without an identity
+	orm.DB(gctx).Create(&esbackend.Event{
+		ModelUUID:      orm.ModelUUID{ID: uuid.New()},
+		AggregateID:    uuid.New(),
+		AggregateType:  "organizations.Aggregate",
+		Type:           "organizations.DataPurged",
+		OrganizationID: &organizationID,
+		UserID:         &uuid.Nil,
+	})
+
+	as := func(userID uuid.UUID) golly.Context {
+		return identity.ToContext(gctx, identity.Identity{UID: userID, OrganizationID: organizationID})
+	}
+
+	t.Run("only hr can export", func(t *testing.T) {
+		_, err := RequestDataExport(as(member.ID), &jane.ID, nil, eventsource.Metadata{})
+		assert.Error(t, err)
+	})
+
+	t.Run("subjects must be in the organization", func(t *testing.T) {
+		other := uuid.New()
+		_, err := RequestDataExport(as(hr.ID), &other, nil, eventsource.Metadata{})
+		assert.ErrorContains(t, err, ErrorUnknownSubject.Error())
+	})
+
+	exp, err := RequestDataExport(as(hr.ID), &jane.ID, nil, eventsource.Metadata{})
+	assert.NoError(t, err)
+	assert.Equal(t, export.StatusPending, exp.Status)
+	assert.Equal(t, &janeUser.ID, exp.UserID)
+	assert.Equal(t, hr.ID, exp.RequestedBy)
+
+	assert.NoError(t, BuildDataExport(as(hr.ID), &exp))
+
+	found, err := FindDataExport(as(hr.ID), exp.ID)
+	assert.NoError(t, err)
+	assert.True(t, found.Downloadable(time.Now()))
+	assert.Equal(t, len(found.Archive), found.Size)
+
+	_, err = FindDataExport(as(member.ID), exp.ID)
+	assert.Error(t, err)
+
+	archive, err := zip.NewReader(bytes.NewReader(found.Archive), int64(len(found.Archive)))
+	assert.NoError(t, err)
+
+	read := func(name string) []byte {
+		f, err := archive.Open(name)
+		if !assert.NoError(t, err) {
+			return nil
+		}
+		defer f.Close()
+
+		b, _ := io.ReadAll(f)
+		return b
+	}
+
+	t.Run("feedback received hides anonymous reviewers", func(t *testing.T) {
+		var received []FeedbackRecord
+		assert.NoError(t, json.Unmarshal(read("feedback_received.json"), &received))
+
+		if assert.Len(t, received, 1) {
+			assert.Equal(t, "Runs great planning meetings.", received[0].Strengths)
+			assert.Empty(t, received[0].Reviewer)
+		}
+
+		var summaries []SummaryRecord
+		assert.NoError(t, json.Unmarshal(read("summaries.json"), &summaries))
+		if assert.Len(t, summaries, 1) {
+			assert.Equal(t, []string{"Delegate more"}, summaries[0].ActionItems)
+		}
+	})
+
+	t.Run("feedback given", func(t *testing.T) {
+		var given []FeedbackRecord
+		assert.NoError(t, json.Unmarshal(read("feedback_given.json"), &given))
+
+		if assert.Len(t, given, 1) {
+			assert.Equal(t, "Always helpful in reviews.", given[0].Strengths)
+		}
+	})
+
+	t.Run("records, history, audit events and usage", func(t *testing.T) {
+		var emp EmployeeRecord
+		assert.NoError(t, json.Unmarshal(read("employee.json"), &emp))
+		assert.Equal(t, "Jane Smith", emp.Name)
+
+		var user UserRecord
+		assert.NoError(t, json.Unmarshal(read("user.json"), &user))
+		assert.Equal(t, janeUser.ID, user.ID)
+
+		var history []HistoryRecord
+		assert.NoError(t, json.Unmarshal(read("history.json"), &history))
+		if assert.Len(t, history, 1) {
+			assert.Equal(t, "Senior Engineer", history[0].Current)
+		}
+
+		var events []AuditRecord
+		assert.NoError(t, json.Unmarshal(read("audit_events.json"), &events))
+		assert.Len(t, events, 1)
+
+		var usage []UsageRecord
+		assert.NoError(t, json.Unmarshal(read("llm_usage.json"), &usage))
+		if assert.Len(t, usage, 1) {
+			assert.Equal(t, 42, usage[0].TotalTokens)
+		}
+	})
+
+	t.Run("employees without a user get their own audit events only", func(t *testing.T) {
+		bob := employees.NewTestEmployee(uuid.New(), organizationID, "bob@example.com", nil)
+		orm.DB(gctx).Create(&bob)
+
+		subject, err := FindSubject(gctx, organizationID, &bob.ID, nil)
+		assert.NoError(t, err)
+
+		report, err := Collect(gctx, subject)
+		assert.NoError(t, err)
+		assert.Empty(t, report.AuditEvents)
+	})
+
+	t.Run("the archive is encrypted at rest", func(t *testing.T) {
+		var stored [][]byte
+		orm.NewDB(gctx).Table("data_exports").Where("id = ?", exp.ID).Pluck("archive", &stored)
+		assert.True(t, crypto.IsEncrypted(string(stored[0])))
+	})
+
+	t.Run("expired archives are dropped", func(t *testing.T) {
+		expired, err := ExpireDataExports(gctx, time.Now())
+		assert.NoError(t, err)
+		assert.Equal(t, int64(0), expired)
+
+		expired, err = ExpireDataExports(gctx, time.Now().Add(export.Retention+time.Hour))
+		assert.NoError(t, err)
+		assert.Equal(t, int64(1), expired)
+
+		found, err := FindDataExport(as(hr.ID), exp.ID)
+		assert.NoError(t, err)
+		assert.Empty(t, found.Archive)
+		assert.Equal(t, export.StatusReady, found.Status)
+	})
+
+	t.Run("html report", func(t *testing.T) {
+		html := string(read("report.html"))
+		assert.Contains(t, html, "Jane Smith")
+		assert.Contains(t, html, "Runs great planning meetings.")
+		assert.Contains(t, html, "Anonymous")
+		assert.NotContains(t, html, "peer@example.com")
+	})
+}
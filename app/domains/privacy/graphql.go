@@ -0,0 +1,158 @@
+package privacy
+
+import (
+	"github.com/golly-go/golly"
+	"github.com/golly-go/plugins/gql"
+	"github.com/google/uuid"
+	"github.com/graphql-go/graphql"
+	"github.com/mitchrodrigues/talent-review-backend/app/domains/privacy/export"
+	"github.com/mitchrodrigues/talent-review-backend/app/utils/helpers"
+)
+
+var (
+	dataExportStatusType = graphql.NewEnum(graphql.EnumConfig{
+		Name: "DataExportStatus",
+		Values: graphql.EnumValueConfigMap{
+			"pending": {Value: export.StatusPending},
+			"ready":   {Value: export.StatusReady},
+			"failed":  {Value: export.StatusFailed},
+		},
+	})
+
+	dataExportType = graphql.NewObject(graphql.ObjectConfig{
+		Name: "DataExport",
+		Fields: graphql.Fields{
+			"id": {
+				Type: graphql.NewNonNull(graphql.String),
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					return p.Source.(DataExport).ID, nil
+				},
+			},
+			"employeeID": {
+				Type: graphql.String,
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					return p.Source.(DataExport).EmployeeID, nil
+				},
+			},
+			"userID": {
+				Type: graphql.String,
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					return p.Source.(DataExport).UserID, nil
+				},
+			},
+			"requestedBy": {
+				Type: graphql.String,
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					return p.Source.(DataExport).RequestedBy, nil
+				},
+			},
+			"status": {
+				Type: dataExportStatusType,
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					return p.Source.(DataExport).Status, nil
+				},
+			},
+			"error": {
+				Type: graphql.String,
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					return p.Source.(DataExport).Error, nil
+				},
+			},
+			"size": {
+				Type:        graphql.Int,
+				Description: "Size of the archive in bytes",
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					return p.Source.(DataExport).Size, nil
+				},
+			},
+			"downloadURL": {
+				Type:        graphql.String,
+				Description: "Path of the ZIP archive while it can be downloaded",
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					exp := p.Source.(DataExport)
+					if exp.Status != export.StatusReady {
+						return nil, nil
+					}
+					return exp.DownloadPath(), nil
+				},
+			},
+			"requestedAt": {
+				Type: graphql.DateTime,
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					return p.Source.(DataExport).CreatedAt, nil
+				},
+			},
+			"completedAt": {
+				Type: graphql.DateTime,
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					return p.Source.(DataExport).CompletedAt, nil
+				},
+			},
+			"expiresAt": {
+				Type: graphql.DateTime,
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					return p.Source.(DataExport).ExpiresAt, nil
+				},
+			},
+		},
+	})
+
+	queries = graphql.Fields{
+		"dataExport": &graphql.Field{
+			Type: dataExportType,
+			Args: graphql.FieldConfigArgument{
+				"id": {Type: graphql.NewNonNull(graphql.String)},
+			},
+			Resolve: gql.NewHandler(gql.Options{
+				Handler: func(wctx golly.WebContext, params gql.Params) (interface{}, error) {
+					id, err := helpers.ExtractAndParseUUID(params.Args, "id")
+					if err != nil {
+						return nil, err
+					}
+
+					return FindDataExport(wctx.Context, id)
+				},
+			}),
+		},
+		"dataExports": &graphql.Field{
+			Type:        graphql.NewList(dataExportType),
+			Description: "Data exports of your organization, most recent first",
+			Resolve: gql.NewHandler(gql.Options{
+				Handler: func(wctx golly.WebContext, params gql.Params) (interface{}, error) {
+					return FindDataExports(wctx.Context)
+				},
+			}),
+		},
+	}
+
+	mutations = graphql.Fields{
+		"requestDataExport": &graphql.Field{
+			Type:        dataExportType,
+			Description: "Export everything stored about an employee or user to answer an access request, give either id",
+			Args: graphql.FieldConfigArgument{
+				"employeeID": {Type: graphql.String},
+				"userID":     {Type: graphql.String},
+			},
+			Resolve: gql.NewHandler(gql.Options{
+				Handler: func(wctx golly.WebContext, params gql.Params) (interface{}, error) {
+					var employeeID, userID *uuid.UUID
+
+					if id, err := helpers.ExtractAndParseUUID(params.Args, "employeeID"); err == nil {
+						employeeID = &id
+					}
+
+					if id, err := helpers.ExtractAndParseUUID(params.Args, "userID"); err == nil {
+						userID = &id
+					}
+
+					return RequestDataExport(wctx.Context, employeeID, userID, params.Metadata())
+				},
+			}),
+		},
+	}
+)
+
+func InitGraphQL() {
+	gql.RegisterQuery(queries)
+	gql.RegisterMutation(mutations)
+}
@@ -0,0 +1,23 @@
+// Package privacy answers data subject requests: exports of everything
+// stored about an employee or user, and applies the retention settings of
+// organizations
+package privacy
+
+import (
+	"github.com/golly-go/golly"
+	"github.com/golly-go/plugins/eventsource"
+)
+
+func Initializer(app golly.Application) error {
+	InitGraphQL()
+
+	app.Routes().Namespace("/privacy", func(r *golly.Route) {
+		r.Get("/exports/{id}", DownloadDataExport)
+	})
+
+	eventsource.Subscribe("export.Aggregate", "export.Requested", DataExportSubscription)
+
+	golly.RegisterServices(&PurgeService{})
+
+	return nil
+}
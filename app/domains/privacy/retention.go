@@ -0,0 +1,154 @@
+package privacy
+
+import (
+	"time"
+
+	"github.com/golly-go/golly"
+	"github.com/golly-go/plugins/eventsource"
+	"github.com/golly-go/plugins/orm"
+	"github.com/google/uuid"
+	"github.com/mitchrodrigues/talent-review-backend/app/domains/accounts"
+	"github.com/mitchrodrigues/talent-review-backend/app/domains/accounts/organizations"
+	"github.com/mitchrodrigues/talent-review-backend/app/domains/reviews"
+	"github.com/mitchrodrigues/talent-review-backend/app/domains/tara"
+	"github.com/mitchrodrigues/talent-review-backend/app/utils/esbackend"
+	"github.com/mitchrodrigues/talent-review-backend/app/utils/identity"
+)
+
+const purgeBatchSize = 500
+
+// Purge applies the retention settings of the organization. Purged rows
+// are hard deleted and the events behind them are tombstoned, the reviewer
+// keys are not shredded since reviewers keep being asked for feedback.
+// The copies derived from the feedback older than the cutoff,
+// performance reviews, tara conversations, cached LLM responses and data
+// export archives, are purged with it. Purges that removed anything are recorded on the
+// organization so they show in the audit log
+func Purge(gctx golly.Context, organization accounts.Organization, now time.Time) (organizations.DataPurged, error) {
+	settings := organization.Settings
+
+	report := organizations.DataPurged{
+		OrganizationID:           organization.ID,
+		PurgedAt:                 now,
+		FeedbackRetentionDays:    settings.FeedbackRetentionDays,
+		UnsubmittedRetentionDays: settings.UnsubmittedRetentionDays,
+	}
+
+	if !settings.RetentionEnabled() {
+		return report, nil
+	}
+
+	// The purge runs outside of any request, the events it records
+	// belong to the organization and to no user
+	gctx = identity.ToContext(gctx, identity.Identity{OrganizationID: organization.ID})
+
+	if days := settings.FeedbackRetentionDays; days > 0 {
+		before := retentionCutoff(now, days)
+
+		purged, tombstoned, err := purgeInBatches(gctx, func() ([]uuid.UUID, error) {
+			ids, conversations, err := reviews.PurgeFeedbackContent(gctx, organization.ID, before, purgeBatchSize)
+			report.ConversationsDeleted += conversations
+			return ids, err
+		}, reviews.ContentEvents...)
+
+		report.FeedbackPurged += purged
+		report.EventsTombstoned += tombstoned
+
+		if err != nil {
+			return report, err
+		}
+
+		if _, err := tara.PurgeCacheBefore(gctx, organization.ID, before); err != nil {
+			return report, err
+		}
+
+		if report.ReviewsPurged, err = reviews.PurgeReviewContent(gctx, organization.ID, before); err != nil {
+			return report, err
+		}
+
+		if report.ArchivesDropped, err = dropDataExports(gctx, organization.ID, before); err != nil {
+			return report, err
+		}
+	}
+
+	if days := settings.UnsubmittedRetentionDays; days > 0 {
+		before := retentionCutoff(now, days)
+
+		dropped, tombstoned, err := purgeInBatches(gctx, func() ([]uuid.UUID, error) {
+			return reviews.DropUnsubmittedFeedback(gctx, organization.ID, now, before, purgeBatchSize)
+		})
+
+		report.RequestsDropped += dropped
+		report.EventsTombstoned += tombstoned
+
+		if err != nil {
+			return report, err
+		}
+	}
+
+	if report.Empty() {
+		return report, nil
+	}
+
+	err := eventsource.Call(gctx, &organization.Aggregate, organizations.RecordPurge{Report: report}, eventsource.Metadata{})
+	return report, err
+}
+
+// PurgeAll purges every organization with a retention setting, one
+// failing organization does not stop the others
+func PurgeAll(gctx golly.Context, now time.Time) ([]organizations.DataPurged, error) {
+	var orgs []accounts.Organization
+	var reports []organizations.DataPurged
+
+	if err := orm.DB(gctx).Model(&accounts.Organization{}).Find(&orgs).Error; err != nil {
+		return reports, err
+	}
+
+	var failed error
+
+	for _, org := range orgs {
+		if !org.Settings.RetentionEnabled() {
+			continue
+		}
+
+		report, err := Purge(gctx, org, now)
+		if err != nil {
+			gctx.Logger().Errorf("cannot purge organization %s: %v", org.ID, err)
+			failed = err
+			continue
+		}
+
+		if !report.Empty() {
+			reports = append(reports, report)
+		}
+	}
+
+	return reports, failed
+}
+
+// purgeInBatches calls purge until it runs out of rows and tombstones the
+// events of the purged aggregates
+func purgeInBatches(gctx golly.Context, purge func() ([]uuid.UUID, error), types ...string) (int, int64, error) {
+	var purged int
+	var tombstoned int64
+
+	for {
+		ids, err := purge()
+		if err != nil {
+			return purged, tombstoned, err
+		}
+
+		purged += len(ids)
+
+		count, err := esbackend.Tombstone(gctx, ids, types...)
+		tombstoned += count
+
+		if err != nil || len(ids) < purgeBatchSize {
+			return purged, tombstoned, err
+		}
+	}
+}
+
+func retentionCutoff(now time.Time, days int) time.Time {
+	return now.AddDate(0, 0, -days)
+}
@@ -0,0 +1,79 @@
+package privacy
+
+import (
+	"cmp"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/golly-go/golly"
+)
+
+const defaultPurgeInterval = 24 * time.Hour
+
+// PurgeService runs the retention purge of every organization and drops
+// the archives of expired data exports on an interval, it is started with
+// `service retention-purge` and configured with privacy.purge_interval
+type PurgeService struct {
+	interval time.Duration
+
+	running atomic.Bool
+	quit    chan struct{}
+	once    sync.Once
+}
+
+func (*PurgeService) Name() string { return "retention-purge" }
+
+func (s *PurgeService) Initialize(app golly.Application) error {
+	s.interval = cmp.Or(app.Config.GetDuration("privacy.purge_interval"), defaultPurgeInterval)
+	s.quit = make(chan struct{})
+	return nil
+}
+
+func (s *PurgeService) Run(gctx golly.Context) error {
+	s.running.Store(true)
+	defer s.running.Store(false)
+
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+
+	for {
+		reports, err := PurgeAll(gctx, time.Now())
+		if err != nil {
+			gctx.Logger().Errorf("retention purge failed: %v", err)
+		}
+
+		for _, report := range reports {
+			gctx.Logger().Infof("purged organization %s: %d feedback, %d requests, %d events tombstoned, %d reviews, %d conversations, %d archives",
+				report.OrganizationID, report.FeedbackPurged, report.RequestsDropped, report.EventsTombstoned,
+				report.ReviewsPurged, report.ConversationsDeleted, report.ArchivesDropped)
+		}
+
+		expired, err := ExpireDataExports(gctx, time.Now())
+		if err != nil {
+			gctx.Logger().Errorf("cannot drop expired data exports: %v", err)
+		} else if expired > 0 {
+			gctx.Logger().Infof("dropped %d expired data export archives", expired)
+		}
+
+		select {
+		case <-ticker.C:
+		case <-s.quit:
+			return nil
+		case <-gctx.Context().Done():
+			return nil
+		}
+	}
+}
+
+func (s *PurgeService) Running() bool { return s.running.Load() }
+
+func (s *PurgeService) Quit() {
+	s.once.Do(func() {
+		if s.quit != nil {
+			close(s.quit)
+		}
+	})
+}
+
+var _ golly.Service = &PurgeService{}
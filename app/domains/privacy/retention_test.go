@@ -0,0 +1,303 @@
+package privacy
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/golly-go/golly"
+	"github.com/golly-go/plugins/eventsource"
+	"github.com/golly-go/plugins/orm"
+	"github.com/google/uuid"
+	"github.com/jinzhu/gorm/dialects/postgres"
+	"github.com/mitchrodrigues/talent-review-backend/app/domains/accounts"
+	"github.com/mitchrodrigues/talent-review-backend/app/domains/accounts/organizations"
+	"github.com/mitchrodrigues/talent-review-backend/app/domains/privacy/export"
+	"github.com/mitchrodrigues/talent-review-backend/app/domains/reviews"
+	"github.com/mitchrodrigues/talent-review-backend/app/domains/reviews/cycle"
+	"github.com/mitchrodrigues/talent-review-backend/app/domains/reviews/feedback"
+	"github.com/mitchrodrigues/talent-review-backend/app/domains/reviews/review"
+	"github.com/mitchrodrigues/talent-review-backend/app/domains/tara"
+	"github.com/mitchrodrigues/talent-review-backend/app/domains/tara/conversation"
+	"github.com/mitchrodrigues/talent-review-backend/app/utils/esbackend"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPurge(t *testing.T) {
+	eventsource.SetEventRepository(esbackend.Backend{})
+	defer eventsource.SetEventRepository(nil)
+
+	gctx := orm.CreateTestContext(golly.NewContext(context.TODO()),
+		accounts.Organization{}, reviews.Feedback{}, reviews.FeedbackDetails{}, reviews.FeedbackSummary{},
+		reviews.FeedbackAnalysis{}, reviews.FeedbackEmbedding{}, reviews.Cycle{}, reviews.PerformanceReview{},
+		tara.Conversation{}, tara.CacheEntry{}, DataExport{}, esbackend.Event{})
+
+	now := time.Now()
+	days := func(n int) time.Time { return now.AddDate(0, 0, -n) }
+
+	org := accounts.Organization{Aggregate: organizations.Aggregate{
+		ModelUUID: orm.ModelUUID{ID: uuid.New()},
+		Settings: organizations.Settings{
+			FeedbackRetentionDays:    365,
+			UnsubmittedRetentionDays: 90,
+		},
+	}}
+	orm.DB(gctx).Create(&org)
+
+	untouched := accounts.Organization{Aggregate: organizations.Aggregate{ModelUUID: orm.ModelUUID{ID: uuid.New()}}}
+	orm.DB(gctx).Create(&untouched)
+
+	event := func(aggregateID uuid.UUID, typ string, data string) esbackend.Event {
+		evt := esbackend.Event{
+			ModelUUID:      orm.ModelUUID{ID: uuid.New()},
+			AggregateID:    aggregateID,
+			AggregateType:  "feedback.Aggregate",
+			Type:           typ,
+			RawData:        postgres.Jsonb{RawMessage: json.RawMessage(data)},
+			OrganizationID: &org.ID,
+		}
+		orm.DB(gctx).Create(&evt)
+		return evt
+	}
+
+	createFeedback := func(organizationID uuid.UUID, createdAt, closedAt time.Time, submitted bool) reviews.Feedback {
+		fb := reviews.Feedback{Aggregate: feedback.Aggregate{
+			ModelUUID:       orm.ModelUUID{ID: uuid.New(), CreatedAt: createdAt},
+			Code:            uuid.NewString(),
+			Email:           "reviewer@example.com",
+			EmployeeID:      uuid.New(),
+			OrganizationID:  organizationID,
+			CollectionEndAt: closedAt,
+		}}
+
+		if submitted {
+			fb.SubmittedAt = &closedAt
+		}
+		orm.DB(gctx).Create(&fb)
+
+		event(fb.ID, "feedback.Created", `{"Email":"reviewer@example.com"}`)
+
+		if submitted {
+			orm.DB(gctx).Create(&reviews.FeedbackDetails{FeedbackDetails: feedback.FeedbackDetails{
+				ModelUUID:      orm.ModelUUID{ID: uuid.New()},
+				FeedbackID:     fb.ID,
+				OrganizationID: organizationID,
+				Strengths:      "Great mentor.",
+			}})
+
+			orm.DB(gctx).Create(&reviews.FeedbackSummary{FeedbackSummary: feedback.FeedbackSummary{
+				ModelUUID:      orm.ModelUUID{ID: uuid.New()},
+				FeedbackID:     fb.ID,
+				OrganizationID: organizationID,
+				Summary:        "A great mentor.",
+			}})
+
+			orm.DB(gctx).Create(&reviews.FeedbackAnalysis{FeedbackAnalysis: feedback.FeedbackAnalysis{
+				ModelUUID:      orm.ModelUUID{ID: uuid.New()},
+				FeedbackID:     fb.ID,
+				OrganizationID: organizationID,
+			}})
+
+			event(fb.ID, "feedback.Submitted", `{}`)
+			event(fb.ID, "feedback.SummaryUpdated", `{"promptVersions":[]}`)
+		}
+
+		return fb
+	}
+
+	expired := createFeedback(org.ID, days(500), days(400), true)
+	recent := createFeedback(org.ID, days(100), days(30), true)
+	stale := createFeedback(org.ID, days(120), days(100), false)
+	pending := createFeedback(org.ID, days(10), days(5), false)
+	otherOrg := createFeedback(untouched.ID, days(800), days(700), true)
+
+	// requested in a cycle, the cycle close date is what counts
+	inCycle := func(fb reviews.Feedback, start, end time.Time) {
+		ownerID := uuid.New()
+		orm.DB(gctx).Model(&fb).UpdateColumn("owner_id", ownerID)
+
+		orm.DB(gctx).Create(&reviews.Cycle{Aggregate: cycle.Aggregate{
+			ModelUUID:      orm.ModelUUID{ID: uuid.New()},
+			OwnerID:        ownerID,
+			OrganizationID: org.ID,
+			StartAt:        start,
+			EndAt:          end,
+		}})
+	}
+
+	cycleClosed := createFeedback(org.ID, days(500), days(300), true)
+	inCycle(cycleClosed, days(600), days(450))
+
+	cycleOpen := createFeedback(org.ID, days(420), days(400), true)
+	inCycle(cycleOpen, days(430), days(200))
+
+	collecting := createFeedback(org.ID, days(120), now.AddDate(0, 0, 5), false)
+
+	// copies derived from the feedback
+	createConversation := func(employeeID uuid.UUID, createdAt time.Time) tara.Conversation {
+		thread := tara.Conversation{Aggregate: conversation.Aggregate{
+			ModelUUID:      orm.ModelUUID{ID: uuid.New(), CreatedAt: createdAt},
+			OrganizationID: org.ID,
+			EmployeeID:     employeeID,
+			Title:          "How is their mentoring?",
+		}}
+		orm.DB(gctx).Create(&thread)
+		return thread
+	}
+
+	expiredThread := createConversation(expired.EmployeeID, days(400))
+	currentThread := createConversation(expired.EmployeeID, days(1))
+	recentThread := createConversation(recent.EmployeeID, days(400))
+
+	createCacheEntry := func(key string, organizationID uuid.UUID, createdAt time.Time) {
+		orm.DB(gctx).Create(&tara.CacheEntry{CacheKey: key, OrganizationID: organizationID, Value: "A great mentor.", CreatedAt: createdAt})
+	}
+
+	createCacheEntry("old", org.ID, days(400))
+	createCacheEntry("fresh", org.ID, days(1))
+	createCacheEntry("other", untouched.ID, days(400))
+
+	performanceReview := func(createdAt time.Time) reviews.PerformanceReview {
+		perfReview := reviews.PerformanceReview{Aggregate: review.Aggregate{
+			ModelUUID:       orm.ModelUUID{ID: uuid.New(), CreatedAt: createdAt},
+			OrganizationID:  org.ID,
+			EmployeeID:      expired.EmployeeID,
+			Summary:         "A great mentor.",
+			Sections:        review.Sections{{Competency: "Mentoring", Content: "A great mentor."}},
+			Rating:          4,
+			RatingRationale: "Mentors the team.",
+		}}
+		orm.DB(gctx).Create(&perfReview)
+		return perfReview
+	}
+
+	oldReview := performanceReview(days(400))
+	newReview := performanceReview(days(10))
+
+	completedAt := days(400)
+	oldExport := DataExport{Aggregate: export.Aggregate{
+		ModelUUID:      orm.ModelUUID{ID: uuid.New()},
+		OrganizationID: org.ID,
+		Status:         export.StatusReady,
+		Archive:        []byte("archive"),
+		CompletedAt:    &completedAt,
+	}}
+	orm.DB(gctx).Create(&oldExport)
+
+	count := func(model interface{}, feedbackID uuid.UUID) int64 {
+		var n int64
+		orm.DB(gctx).Model(model).Unscoped().Where("feedback_id = ?", feedbackID).Count(&n)
+		return n
+	}
+
+	exists := func(id uuid.UUID) bool {
+		var n int64
+		orm.DB(gctx).Model(&reviews.Feedback{}).Unscoped().Where("id = ?", id).Count(&n)
+		return n > 0
+	}
+
+	eventsOf := func(aggregateID uuid.UUID) map[string]string {
+		var events []esbackend.Event
+		orm.DB(gctx).Model(&esbackend.Event{}).Find(&events, "aggregate_id = ?", aggregateID)
+
+		ret := map[string]string{}
+		for _, evt := range events {
+			ret[evt.Type] = string(evt.RawData.RawMessage)
+		}
+		return ret
+	}
+
+	report, err := Purge(gctx, org, now)
+	assert.NoError(t, err)
+
+	assert.Equal(t, 2, report.FeedbackPurged)
+	assert.Equal(t, 1, report.RequestsDropped)
+	assert.Equal(t, int64(3), report.EventsTombstoned)
+	assert.Equal(t, int64(1), report.ReviewsPurged)
+	assert.Equal(t, int64(1), report.ConversationsDeleted)
+	assert.Equal(t, int64(1), report.ArchivesDropped)
+
+	t.Run("feedback content is deleted after the retention period", func(t *testing.T) {
+		assert.True(t, exists(expired.ID))
+		assert.Zero(t, count(&reviews.FeedbackDetails{}, expired.ID))
+		assert.Zero(t, count(&reviews.FeedbackSummary{}, expired.ID))
+		assert.Zero(t, count(&reviews.FeedbackAnalysis{}, expired.ID))
+
+		events := eventsOf(expired.ID)
+		assert.Contains(t, events["feedback.SummaryUpdated"], `"tombstone":true`)
+		assert.NotContains(t, events["feedback.Created"], "tombstone")
+
+		assert.Equal(t, int64(1), count(&reviews.FeedbackDetails{}, recent.ID))
+		assert.Equal(t, int64(1), count(&reviews.FeedbackDetails{}, otherOrg.ID))
+	})
+
+	t.Run("the cutoff is the close date of the cycle", func(t *testing.T) {
+		assert.Zero(t, count(&reviews.FeedbackDetails{}, cycleClosed.ID))
+		assert.Equal(t, int64(1), count(&reviews.FeedbackDetails{}, cycleOpen.ID))
+	})
+
+	t.Run("derived copies are purged", func(t *testing.T) {
+		var threads []uuid.UUID
+		orm.DB(gctx).Model(&tara.Conversation{}).Unscoped().Pluck("id", &threads)
+		assert.NotContains(t, threads, expiredThread.ID)
+		assert.Contains(t, threads, currentThread.ID)
+		assert.Contains(t, threads, recentThread.ID)
+
+		var keys []string
+		orm.DB(gctx).Model(&tara.CacheEntry{}).Order("cache_key").Pluck("cache_key", &keys)
+		assert.Equal(t, []string{"fresh", "other"}, keys)
+
+		var purgedReview, keptReview reviews.PerformanceReview
+		orm.DB(gctx).First(&purgedReview, "id = ?", oldReview.ID)
+		assert.Empty(t, purgedReview.Summary)
+		assert.Empty(t, purgedReview.Sections)
+		assert.Empty(t, purgedReview.RatingRationale)
+		assert.Equal(t, 4, purgedReview.Rating)
+
+		orm.DB(gctx).First(&keptReview, "id = ?", newReview.ID)
+		assert.Equal(t, "A great mentor.", keptReview.Summary)
+
+		var dropped DataExport
+		orm.DB(gctx).First(&dropped, "id = ?", oldExport.ID)
+		assert.Empty(t, dropped.Archive)
+	})
+
+	t.Run("unsubmitted requests are dropped", func(t *testing.T) {
+		assert.False(t, exists(stale.ID))
+		assert.Contains(t, eventsOf(stale.ID)["feedback.Created"], `"tombstone":true`)
+		assert.NotContains(t, eventsOf(stale.ID)["feedback.Created"], "reviewer@example.com")
+
+		assert.True(t, exists(pending.ID))
+		assert.Contains(t, eventsOf(pending.ID)["feedback.Created"], "reviewer@example.com")
+
+		// sent long ago but still collecting
+		assert.True(t, exists(collecting.ID))
+	})
+
+	t.Run("the report is in the audit log", func(t *testing.T) {
+		var events []esbackend.Event
+		orm.DB(gctx).Model(&esbackend.Event{}).Find(&events, "aggregate_id = ?", org.ID)
+
+		if assert.Len(t, events, 1) {
+			assert.Equal(t, "organizations.DataPurged", events[0].Type)
+			assert.Equal(t, org.ID, *events[0].OrganizationID)
+
+			var purged organizations.DataPurged
+			assert.NoError(t, json.Unmarshal(events[0].RawData.RawMessage, &purged))
+			assert.Equal(t, 2, purged.FeedbackPurged)
+			assert.Equal(t, 1, purged.RequestsDropped)
+			assert.Equal(t, int64(1), purged.ReviewsPurged)
+		}
+	})
+
+	t.Run("purging again is a no-op", func(t *testing.T) {
+		reports, err := PurgeAll(gctx, now)
+		assert.NoError(t, err)
+		assert.Empty(t, reports)
+
+		var n int64
+		orm.DB(gctx).Model(&esbackend.Event{}).Where("aggregate_id = ?", org.ID).Count(&n)
+		assert.Equal(t, int64(1), n)
+	})
+}
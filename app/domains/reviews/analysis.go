@@ -0,0 +1,144 @@
+package reviews
+
+import (
+	"time"
+
+	"github.com/golly-go/golly"
+	"github.com/golly-go/golly/errors"
+	"github.com/golly-go/plugins/eventsource"
+	"github.com/golly-go/plugins/orm"
+	"github.com/google/uuid"
+	"github.com/mitchrodrigues/talent-review-backend/app/domains/reviews/feedback"
+	"github.com/mitchrodrigues/talent-review-backend/app/domains/tara"
+	"github.com/mitchrodrigues/talent-review-backend/app/utils/identity"
+	"github.com/mitchrodrigues/talent-review-backend/app/utils/wsyiwig"
+)
+
+const (
+	AnalysisGroupReviewer = "REVIEWER"
+	AnalysisGroupTeam     = "TEAM"
+)
+
+// AnalysisRollup is the bias analysis of many feedbacks grouped by the
+// reviewer that wrote them or the team of the employee they are about
+type AnalysisRollup struct {
+	Key  string
+	Name string
+
+	Feedbacks int
+	Flagged   int
+
+	GenderedLanguage     int
+	PersonalityCritiques int
+	UnsupportedClaims    int
+}
+
+// FlaggedRate is the share of analyzed feedback with at least one finding
+func (rollup AnalysisRollup) FlaggedRate() float64 {
+	if rollup.Feedbacks == 0 {
+		return 0
+	}
+	return float64(rollup.Flagged) / float64(rollup.Feedbacks)
+}
+
+// AnalyzeFeedback runs the tara bias and inclusivity analysis over the
+// feedback and stores the result
+func AnalyzeFeedback(gctx golly.Context, fb *feedback.Aggregate) error {
+	// Feedback is submitted publicly, make sure LLM usage is
+	// accounted against the organization that owns it
+	_, gctx = identity.SetOrganizationID(gctx, fb.OrganizationID)
+
+	details, err := FeedbackService(gctx).FindDetailsByFeedbackID_Unsafe(gctx, fb.ID)
+	if err != nil {
+		return err
+	}
+
+	strengths, _ := wsyiwig.ExtractTextFromJSON(details.Strengths)
+	opportunities, _ := wsyiwig.ExtractTextFromJSON(details.Opportunities)
+	additional, _ := wsyiwig.ExtractTextFromJSON(details.Additional)
+
+	analysis, err := tara.AnalyzeFeedback(gctx, tara.GenerateOptions{
+		Redactor: FeedbackRedactor(gctx, fb.OrganizationID),
+	}, tara.SummarizeFeedbackInput{
+		Strengths:          strengths,
+		Opportunities:      opportunities,
+		AdditionalComments: additional,
+	})
+
+	if err != nil || analysis.Skipped {
+		return err
+	}
+
+	err = eventsource.Call(gctx, fb, feedback.CreateAnalysis{
+		GenderedLanguage:     analysis.Count(tara.FindingGenderedLanguage),
+		PersonalityCritiques: analysis.Count(tara.FindingPersonalityCritique),
+		UnsupportedClaims:    analysis.Count(tara.FindingUnsupportedClaim),
+		Findings: golly.Map(analysis.Findings, func(finding tara.AnalysisFinding) feedback.Finding {
+			return feedback.Finding{
+				Category:    finding.Category,
+				Excerpt:     finding.Excerpt,
+				Explanation: finding.Explanation,
+			}
+		}),
+		PromptVersions: analysis.PromptVersions,
+	}, eventsource.Metadata{})
+
+	return errors.WrapGeneric(err)
+}
+
+// FindFeedbackAnalysis returns the analysis of a feedback of the
+// organization on the context
+func FindFeedbackAnalysis(gctx golly.Context, feedbackID uuid.UUID) (FeedbackAnalysis, error) {
+	var analysis FeedbackAnalysis
+
+	err := orm.DB(gctx).
+		Model(&analysis).
+		Where("organization_id = ?", identity.FromContext(gctx).OrganizationID).
+		First(&analysis, "feedback_id = ?", feedbackID).
+		Error
+
+	return analysis, errors.WrapNotFound(err)
+}
+
+// AnalysisRollups aggregates the analyses of the organization on the
+// context created since the given time, grouped by reviewer or team, the
+// groups with the most flagged feedback come first
+func AnalysisRollups(gctx golly.Context, groupBy string, since time.Time) ([]AnalysisRollup, error) {
+	var rollups []AnalysisRollup
+
+	db := orm.DB(gctx).
+		Table("feedback_analyses").
+		Joins("JOIN feedbacks ON feedbacks.id = feedback_analyses.feedback_id").
+		Where("feedback_analyses.organization_id = ?", identity.FromContext(gctx).OrganizationID).
+		Where("feedback_analyses.created_at >= ?", since).
+		Where("feedback_analyses.deleted_at IS NULL")
+
+	switch groupBy {
+	case AnalysisGroupTeam:
+		db = db.
+			Joins("JOIN employees employee ON employee.id = feedback_analyses.employee_id").
+			Joins("LEFT JOIN teams team ON team.id = employee.team_id").
+			Select(rollupColumns("COALESCE(CAST(team.id AS VARCHAR), '')", "COALESCE(team.name, '')")).
+			Group("team.id, team.name")
+	default:
+		db = db.
+			Select(rollupColumns("feedbacks.email", "feedbacks.email")).
+			Group("feedbacks.email")
+	}
+
+	err := db.
+		Order("flagged DESC, feedbacks DESC").
+		Scan(&rollups).
+		Error
+
+	return rollups, errors.WrapGeneric(err)
+}
+
+func rollupColumns(key, name string) string {
+	return key + " AS key, " + name + " AS name, " +
+		"COUNT(*) AS feedbacks, " +
+		"SUM(CASE WHEN feedback_analyses.gendered_language + feedback_analyses.personality_critiques + feedback_analyses.unsupported_claims > 0 THEN 1 ELSE 0 END) AS flagged, " +
+		"SUM(feedback_analyses.gendered_language) AS gendered_language, " +
+		"SUM(feedback_analyses.personality_critiques) AS personality_critiques, " +
+		"SUM(feedback_analyses.unsupported_claims) AS unsupported_claims"
+}
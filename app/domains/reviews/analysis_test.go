@@ -0,0 +1,146 @@
+package reviews
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/golly-go/golly"
+	"github.com/golly-go/plugins/orm"
+	"github.com/google/uuid"
+	"github.com/mitchrodrigues/talent-review-backend/app/domains/accounts"
+	"github.com/mitchrodrigues/talent-review-backend/app/domains/employees"
+	"github.com/mitchrodrigues/talent-review-backend/app/domains/reviews/feedback"
+	"github.com/mitchrodrigues/talent-review-backend/app/domains/tara"
+	"github.com/mitchrodrigues/talent-review-backend/app/utils/esbackend"
+	"github.com/mitchrodrigues/talent-review-backend/app/utils/identity"
+	"github.com/mitchrodrigues/talent-review-backend/app/utils/openai"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAnalyzeFeedback(t *testing.T) {
+	gctx := orm.CreateTestContext(golly.NewContext(context.TODO()),
+		Feedback{},
+		FeedbackDetails{},
+		FeedbackSummary{},
+		FeedbackAnalysis{},
+		accounts.Organization{},
+		tara.Prompt{},
+		esbackend.Event{})
+
+	gctx = openai.UseProvider(gctx, &openai.FixtureProvider{Default: `{"findings":[
+		{"category":"personality_critique","excerpt":"is abrasive","explanation":"Describes the person, not a behavior."},
+		{"category":"unsupported_claim","excerpt":"great teammate","explanation":"No example."}
+	]}`})
+
+	fb := Feedback{Aggregate: feedback.Aggregate{
+		ModelUUID:      orm.ModelUUID{ID: uuid.New()},
+		Code:           "analysis-code",
+		EmployeeID:     uuid.New(),
+		OrganizationID: uuid.New(),
+	}}
+	orm.DB(gctx).Create(&fb)
+
+	orm.DB(gctx).Create(&FeedbackDetails{FeedbackDetails: feedback.FeedbackDetails{
+		FeedbackID:     fb.ID,
+		EmployeeID:     fb.EmployeeID,
+		OrganizationID: fb.OrganizationID,
+		Strengths:      tiptapDocument("A great teammate."),
+		Opportunities:  tiptapDocument("She is abrasive."),
+	}})
+
+	assert.NoError(t, AnalyzeFeedback(gctx, &fb.Aggregate))
+
+	// re-analyzing replaces the analysis
+	assert.NoError(t, AnalyzeFeedback(gctx, &fb.Aggregate))
+
+	var analyses []FeedbackAnalysis
+	orm.DB(gctx).Find(&analyses, "feedback_id = ?", fb.ID)
+
+	if assert.Len(t, analyses, 1) {
+		analysis := analyses[0]
+
+		assert.True(t, analysis.Flagged())
+		assert.Equal(t, 0, analysis.GenderedLanguage)
+		assert.Equal(t, 1, analysis.PersonalityCritiques)
+		assert.Equal(t, 1, analysis.UnsupportedClaims)
+		assert.Equal(t, "is abrasive", analysis.Findings[0].Excerpt)
+		assert.Contains(t, analysis.PromptVersions, "FeedbackAnalysisPrompt")
+	}
+}
+
+func TestAnalysisRollups(t *testing.T) {
+	gctx := orm.CreateTestContext(golly.NewContext(context.TODO()),
+		Feedback{},
+		FeedbackAnalysis{},
+		employees.Employee{},
+		employees.Team{})
+
+	organizationID := uuid.New()
+	gctx = identity.ToContext(gctx, identity.Identity{UID: uuid.New(), OrganizationID: organizationID})
+
+	team := employees.NewTestTeam(uuid.New(), organizationID, nil)
+	team.Name = "Platform"
+	orm.DB(gctx).Create(&team)
+
+	inTeam := employees.NewTestEmployeeWithTeam(uuid.New(), organizationID, "team@example.com", team.ID)
+	orm.DB(gctx).Create(&inTeam)
+
+	noTeam := employees.NewTestEmployee(uuid.New(), organizationID, "solo@example.com", nil)
+	orm.DB(gctx).Create(&noTeam)
+
+	analyze := func(orgID uuid.UUID, employeeID uuid.UUID, reviewer string, createdAt time.Time, gendered, personality, unsupported int) {
+		fb := Feedback{Aggregate: feedback.Aggregate{
+			ModelUUID:      orm.ModelUUID{ID: uuid.New()},
+			Email:          reviewer,
+			EmployeeID:     employeeID,
+			OrganizationID: orgID,
+		}}
+		orm.DB(gctx).Create(&fb)
+
+		orm.DB(gctx).Create(&FeedbackAnalysis{FeedbackAnalysis: feedback.FeedbackAnalysis{
+			ModelUUID:            orm.ModelUUID{CreatedAt: createdAt},
+			FeedbackID:           fb.ID,
+			EmployeeID:           employeeID,
+			OrganizationID:       orgID,
+			GenderedLanguage:     gendered,
+			PersonalityCritiques: personality,
+			UnsupportedClaims:    unsupported,
+		}})
+	}
+
+	now := time.Now()
+
+	analyze(organizationID, inTeam.ID, "a@example.com", now, 1, 0, 2)
+	analyze(organizationID, inTeam.ID, "a@example.com", now, 0, 1, 0)
+	analyze(organizationID, noTeam.ID, "a@example.com", now, 0, 0, 0)
+	analyze(organizationID, noTeam.ID, "b@example.com", now, 0, 0, 0)
+
+	// too old and another organization
+	analyze(organizationID, inTeam.ID, "b@example.com", now.AddDate(-2, 0, 0), 3, 3, 3)
+	analyze(uuid.New(), inTeam.ID, "b@example.com", now, 3, 3, 3)
+
+	since := now.AddDate(-1, 0, 0)
+
+	t.Run("by reviewer", func(t *testing.T) {
+		rollups, err := AnalysisRollups(gctx, AnalysisGroupReviewer, since)
+		assert.NoError(t, err)
+
+		assert.Equal(t, []AnalysisRollup{
+			{Key: "a@example.com", Name: "a@example.com", Feedbacks: 3, Flagged: 2, GenderedLanguage: 1, PersonalityCritiques: 1, UnsupportedClaims: 2},
+			{Key: "b@example.com", Name: "b@example.com", Feedbacks: 1},
+		}, rollups)
+
+		assert.InDelta(t, 2.0/3.0, rollups[0].FlaggedRate(), 0.0001)
+	})
+
+	t.Run("by team", func(t *testing.T) {
+		rollups, err := AnalysisRollups(gctx, AnalysisGroupTeam, since)
+		assert.NoError(t, err)
+
+		assert.Equal(t, []AnalysisRollup{
+			{Key: team.ID.String(), Name: "Platform", Feedbacks: 2, Flagged: 2, GenderedLanguage: 1, PersonalityCritiques: 1, UnsupportedClaims: 2},
+			{Key: "", Name: "", Feedbacks: 2},
+		}, rollups)
+	})
+}
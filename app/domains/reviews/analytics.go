@@ -0,0 +1,289 @@
+package reviews
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/golly-go/golly"
+	"github.com/golly-go/golly/errors"
+	"github.com/golly-go/plugins/orm"
+	"github.com/mitchrodrigues/talent-review-backend/app/domains/accounts"
+	"github.com/mitchrodrigues/talent-review-backend/app/domains/common"
+	"github.com/mitchrodrigues/talent-review-backend/app/utils/identity"
+	"gorm.io/gorm"
+)
+
+const (
+	AnalyticsGroupTeam    = "TEAM"
+	AnalyticsGroupManager = "MANAGER"
+	AnalyticsGroupRole    = "ROLE"
+	AnalyticsGroupCycle   = "CYCLE"
+
+	AnalyticsIntervalWeek  = "WEEK"
+	AnalyticsIntervalMonth = "MONTH"
+
+	reviewerLoadLimit = 50
+)
+
+// FeedbackAnalyticsInput selects the feedback requested between Since and
+// Until, grouped and bucketed by the time it was requested. An empty
+// GroupBy or Interval does not group or bucket
+type FeedbackAnalyticsInput struct {
+	GroupBy  string
+	Interval string
+
+	Since time.Time
+	Until time.Time
+}
+
+// FeedbackAnalytics is how the feedback requested for a group during a
+// period went, Period is zero when not bucketed
+type FeedbackAnalytics struct {
+	Key    string
+	Name   string
+	Period time.Time
+
+	Requested int
+	Submitted int
+	Declined  int
+	Overdue   int
+
+	MedianHoursToSubmit float64
+}
+
+func (analytics FeedbackAnalytics) ResponseRate() float64 {
+	return rate(analytics.Submitted, analytics.Requested)
+}
+
+func (analytics FeedbackAnalytics) DeclinedRate() float64 {
+	return rate(analytics.Declined, analytics.Requested)
+}
+
+// ReviewerLoad is how much feedback a reviewer was asked for and how much
+// of it is still waiting on them
+type ReviewerLoad struct {
+	Email string
+
+	Requested int
+	Submitted int
+	Declined  int
+	Pending   int
+	Overdue   int
+}
+
+type analyticsRow struct {
+	Key    string
+	Name   string
+	Period string
+
+	Requested int
+	Submitted int
+	Declined  int
+	Overdue   int
+}
+
+type submitDurationRow struct {
+	Key     string
+	Period  string
+	Seconds float64
+}
+
+// FeedbackAnalyticsRollups computes the response rate, median time to
+// submit, declined rate and overdue counts of the feedback visible to
+// the user, HR sees the organization and managers their reports
+func FeedbackAnalyticsRollups(gctx golly.Context, input FeedbackAnalyticsInput) ([]FeedbackAnalytics, error) {
+	db := orm.DB(gctx)
+	now := time.Now()
+
+	key, name := analyticsGroupColumns(db, input.GroupBy)
+	period := analyticsPeriodColumn(db, input.Interval)
+
+	var rows []analyticsRow
+
+	err := analyticsQuery(gctx, input).
+		Scopes(analyticsGroupJoins(input.GroupBy)).
+		Select(fmt.Sprintf("%s AS key, %s AS name, %s AS period, "+
+			"COUNT(*) AS requested, "+
+			"SUM(CASE WHEN feedbacks.submitted_at IS NOT NULL THEN 1 ELSE 0 END) AS submitted, "+
+			"SUM(CASE WHEN feedbacks.declined_at IS NOT NULL THEN 1 ELSE 0 END) AS declined, "+
+			"SUM(CASE WHEN feedbacks.submitted_at IS NULL AND feedbacks.declined_at IS NULL AND feedbacks.collection_end_at < ? THEN 1 ELSE 0 END) AS overdue",
+			key, name, period), now).
+		Group(fmt.Sprintf("%s, %s, %s", key, name, period)).
+		Scan(&rows).
+		Error
+
+	if err != nil {
+		return nil, errors.WrapGeneric(err)
+	}
+
+	var durations []submitDurationRow
+
+	err = analyticsQuery(gctx, input).
+		Scopes(analyticsGroupJoins(input.GroupBy)).
+		Select(fmt.Sprintf("%s AS key, %s AS period, %s AS seconds", key, period, submitSecondsColumn(db))).
+		Where("feedbacks.submitted_at IS NOT NULL").
+		Scan(&durations).
+		Error
+
+	if err != nil {
+		return nil, errors.WrapGeneric(err)
+	}
+
+	ret := golly.Map(rows, func(row analyticsRow) FeedbackAnalytics {
+		analytics := FeedbackAnalytics{
+			Key:       row.Key,
+			Name:      row.Name,
+			Requested: row.Requested,
+			Submitted: row.Submitted,
+			Declined:  row.Declined,
+			Overdue:   row.Overdue,
+		}
+
+		if row.Period != "" {
+			analytics.Period, _ = time.Parse(time.DateOnly, row.Period)
+		}
+
+		seconds := golly.Map(
+			golly.Filter(durations, func(d submitDurationRow) bool { return d.Key == row.Key && d.Period == row.Period }),
+			func(d submitDurationRow) float64 { return d.Seconds })
+
+		analytics.MedianHoursToSubmit = median(seconds) / float64(time.Hour/time.Second)
+
+		return analytics
+	})
+
+	slices.SortFunc(ret, func(a, b FeedbackAnalytics) int {
+		return cmp.Or(a.Period.Compare(b.Period), cmp.Compare(a.Name, b.Name))
+	})
+
+	return ret, nil
+}
+
+// ReviewerLoads lists the reviewers asked for the most feedback, the
+// requests whose reviewer the current user may not see are left out
+func ReviewerLoads(gctx golly.Context, input FeedbackAnalyticsInput) ([]ReviewerLoad, error) {
+	var loads []ReviewerLoad
+
+	err := analyticsQuery(gctx, input).
+		Scopes(common.JoinUserEmployeeRecord(gctx), ReviewerVisibleScope(gctx)).
+		Select("feedbacks.email AS email, "+
+			"COUNT(*) AS requested, "+
+			"SUM(CASE WHEN feedbacks.submitted_at IS NOT NULL THEN 1 ELSE 0 END) AS submitted, "+
+			"SUM(CASE WHEN feedbacks.declined_at IS NOT NULL THEN 1 ELSE 0 END) AS declined, "+
+			"SUM(CASE WHEN feedbacks.submitted_at IS NULL AND feedbacks.declined_at IS NULL THEN 1 ELSE 0 END) AS pending, "+
+			"SUM(CASE WHEN feedbacks.submitted_at IS NULL AND feedbacks.declined_at IS NULL AND feedbacks.collection_end_at < ? THEN 1 ELSE 0 END) AS overdue",
+			time.Now()).
+		Group("feedbacks.email").
+		Order("requested DESC, pending DESC, email").
+		Limit(reviewerLoadLimit).
+		Scan(&loads).
+		Error
+
+	return loads, errors.WrapGeneric(err)
+}
+
+func analyticsQuery(gctx golly.Context, input FeedbackAnalyticsInput) *gorm.DB {
+	ident := identity.FromContext(gctx)
+
+	db := orm.DB(gctx).
+		Table("feedbacks").
+		Joins("JOIN employees employee ON employee.id = feedbacks.employee_id").
+		Where("feedbacks.organization_id = ? AND feedbacks.deleted_at IS NULL", ident.OrganizationID).
+		Where("feedbacks.created_at BETWEEN ? AND ?", input.Since, input.Until)
+
+	if _, err := accounts.RequireHR(gctx); err != nil {
+		db = db.Where("employee.manager_id = ?", ident.EmployeeID)
+	}
+
+	return db
+}
+
+func analyticsGroupJoins(groupBy string) func(*gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		switch groupBy {
+		case AnalyticsGroupTeam:
+			return db.Joins("LEFT JOIN teams team ON team.id = employee.team_id")
+		case AnalyticsGroupManager:
+			return db.Joins("LEFT JOIN employees manager ON manager.id = employee.manager_id")
+		case AnalyticsGroupRole:
+			return db.Joins("LEFT JOIN employee_roles employee_role ON employee_role.id = employee.employee_role_id")
+		case AnalyticsGroupCycle:
+			return db.Joins("JOIN cycles cycle ON " + feedbackCycle)
+		}
+		return db
+	}
+}
+
+func analyticsGroupColumns(db *gorm.DB, groupBy string) (string, string) {
+	switch groupBy {
+	case AnalyticsGroupTeam:
+		return "COALESCE(CAST(team.id AS VARCHAR), '')", "COALESCE(team.name, '')"
+	case AnalyticsGroupManager:
+		return "COALESCE(CAST(manager.id AS VARCHAR), '')", "COALESCE(manager.name, '')"
+	case AnalyticsGroupRole:
+		return "COALESCE(CAST(employee_role.id AS VARCHAR), '')", "COALESCE(employee_role.title, '')"
+	case AnalyticsGroupCycle:
+		return "CAST(cycle.id AS VARCHAR)", dateColumn(db, "cycle.start_at") + " || ' - ' || " + dateColumn(db, "cycle.end_at")
+	}
+	return "''", "''"
+}
+
+// analyticsPeriodColumn truncates the request date to the start of its
+// week (monday) or month as YYYY-MM-DD
+func analyticsPeriodColumn(db *gorm.DB, interval string) string {
+	postgres := db.Dialector.Name() == "postgres"
+
+	switch interval {
+	case AnalyticsIntervalWeek:
+		if postgres {
+			return "TO_CHAR(DATE_TRUNC('week', feedbacks.created_at), 'YYYY-MM-DD')"
+		}
+		return "strftime('%Y-%m-%d', feedbacks.created_at, 'weekday 0', '-6 days')"
+
+	case AnalyticsIntervalMonth:
+		if postgres {
+			return "TO_CHAR(DATE_TRUNC('month', feedbacks.created_at), 'YYYY-MM-DD')"
+		}
+		return "strftime('%Y-%m-01', feedbacks.created_at)"
+	}
+	return "''"
+}
+
+func dateColumn(db *gorm.DB, column string) string {
+	if db.Dialector.Name() == "postgres" {
+		return fmt.Sprintf("TO_CHAR(%s, 'YYYY-MM-DD')", column)
+	}
+	return fmt.Sprintf("strftime('%%Y-%%m-%%d', %s)", column)
+}
+
+func submitSecondsColumn(db *gorm.DB) string {
+	if db.Dialector.Name() == "postgres" {
+		return "EXTRACT(EPOCH FROM (feedbacks.submitted_at - feedbacks.created_at))"
+	}
+	return "(julianday(feedbacks.submitted_at) - julianday(feedbacks.created_at)) * 86400"
+}
+
+func rate(count, total int) float64 {
+	if total == 0 {
+		return 0
+	}
+	return float64(count) / float64(total)
+}
+
+func median(values []float64) float64 {
+	if len(values) == 0 {
+		return 0
+	}
+
+	sorted := slices.Clone(values)
+	slices.Sort(sorted)
+
+	mid := len(sorted) / 2
+
+	if len(sorted)%2 == 0 {
+		return (sorted[mid-1] + sorted[mid]) / 2
+	}
+	return sorted[mid]
+}
@@ -0,0 +1,196 @@
+package reviews
+
+import (
+	"time"
+
+	"github.com/golly-go/golly"
+	"github.com/golly-go/plugins/gql"
+	"github.com/graphql-go/graphql"
+	"github.com/mitchrodrigues/talent-review-backend/app/utils/helpers"
+)
+
+var (
+	analyticsGroupType = graphql.NewEnum(graphql.EnumConfig{
+		Name: "FeedbackAnalyticsGroup",
+		Values: graphql.EnumValueConfigMap{
+			AnalyticsGroupTeam:    {Value: AnalyticsGroupTeam},
+			AnalyticsGroupManager: {Value: AnalyticsGroupManager},
+			AnalyticsGroupRole:    {Value: AnalyticsGroupRole},
+			AnalyticsGroupCycle:   {Value: AnalyticsGroupCycle},
+		},
+	})
+
+	analyticsIntervalType = graphql.NewEnum(graphql.EnumConfig{
+		Name: "FeedbackAnalyticsInterval",
+		Values: graphql.EnumValueConfigMap{
+			AnalyticsIntervalWeek:  {Value: AnalyticsIntervalWeek},
+			AnalyticsIntervalMonth: {Value: AnalyticsIntervalMonth},
+		},
+	})
+
+	feedbackAnalyticsType = graphql.NewObject(graphql.ObjectConfig{
+		Name: "FeedbackAnalytics",
+		Fields: graphql.Fields{
+			"key": {
+				Type:        graphql.String,
+				Description: "Id of the team, manager, role or cycle, empty when not grouped",
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					return p.Source.(FeedbackAnalytics).Key, nil
+				},
+			},
+			"name": {
+				Type: graphql.String,
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					return p.Source.(FeedbackAnalytics).Name, nil
+				},
+			},
+			"period": {
+				Type:        graphql.DateTime,
+				Description: "Start of the week or month, empty when not bucketed",
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					if period := p.Source.(FeedbackAnalytics).Period; !period.IsZero() {
+						return period, nil
+					}
+					return nil, nil
+				},
+			},
+			"requested": {
+				Type: graphql.Int,
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					return p.Source.(FeedbackAnalytics).Requested, nil
+				},
+			},
+			"submitted": {
+				Type: graphql.Int,
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					return p.Source.(FeedbackAnalytics).Submitted, nil
+				},
+			},
+			"declined": {
+				Type: graphql.Int,
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					return p.Source.(FeedbackAnalytics).Declined, nil
+				},
+			},
+			"overdue": {
+				Type:        graphql.Int,
+				Description: "Neither submitted nor declined after the collection ended",
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					return p.Source.(FeedbackAnalytics).Overdue, nil
+				},
+			},
+			"responseRate": {
+				Type: graphql.Float,
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					return p.Source.(FeedbackAnalytics).ResponseRate(), nil
+				},
+			},
+			"declinedRate": {
+				Type: graphql.Float,
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					return p.Source.(FeedbackAnalytics).DeclinedRate(), nil
+				},
+			},
+			"medianHoursToSubmit": {
+				Type: graphql.Float,
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					return p.Source.(FeedbackAnalytics).MedianHoursToSubmit, nil
+				},
+			},
+		},
+	})
+
+	reviewerLoadType = graphql.NewObject(graphql.ObjectConfig{
+		Name: "ReviewerLoad",
+		Fields: graphql.Fields{
+			"email": {
+				Type: graphql.String,
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					return p.Source.(ReviewerLoad).Email, nil
+				},
+			},
+			"requested": {
+				Type: graphql.Int,
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					return p.Source.(ReviewerLoad).Requested, nil
+				},
+			},
+			"submitted": {
+				Type: graphql.Int,
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					return p.Source.(ReviewerLoad).Submitted, nil
+				},
+			},
+			"declined": {
+				Type: graphql.Int,
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					return p.Source.(ReviewerLoad).Declined, nil
+				},
+			},
+			"pending": {
+				Type: graphql.Int,
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					return p.Source.(ReviewerLoad).Pending, nil
+				},
+			},
+			"overdue": {
+				Type: graphql.Int,
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					return p.Source.(ReviewerLoad).Overdue, nil
+				},
+			},
+		},
+	})
+
+	analyticsArgs = graphql.FieldConfigArgument{
+		"since": {Type: graphql.DateTime, Description: "Defaults to a year ago"},
+		"until": {Type: graphql.DateTime, Description: "Defaults to now"},
+	}
+
+	analyticsQueries = graphql.Fields{
+		"feedbackAnalytics": &graphql.Field{
+			Type:        graphql.NewList(feedbackAnalyticsType),
+			Description: "How requested feedback went, for the organization for HR and for your reports otherwise",
+			Args: graphql.FieldConfigArgument{
+				"groupBy":  {Type: analyticsGroupType},
+				"interval": {Type: analyticsIntervalType},
+				"since":    analyticsArgs["since"],
+				"until":    analyticsArgs["until"],
+			},
+			Resolve: gql.NewHandler(gql.Options{
+				Handler: func(wctx golly.WebContext, params gql.Params) (interface{}, error) {
+					return FeedbackAnalyticsRollups(wctx.Context, analyticsInput(params.Args))
+				},
+			}),
+		},
+		"reviewerLoad": &graphql.Field{
+			Type:        graphql.NewList(reviewerLoadType),
+			Description: "Reviewers asked for the most feedback",
+			Args:        analyticsArgs,
+			Resolve: gql.NewHandler(gql.Options{
+				Handler: func(wctx golly.WebContext, params gql.Params) (interface{}, error) {
+					return ReviewerLoads(wctx.Context, analyticsInput(params.Args))
+				},
+			}),
+		},
+	}
+)
+
+func analyticsInput(args map[string]interface{}) FeedbackAnalyticsInput {
+	input := FeedbackAnalyticsInput{}
+
+	input.GroupBy, _ = helpers.ExtractArg[string](args, "groupBy")
+	input.Interval, _ = helpers.ExtractArg[string](args, "interval")
+
+	var err error
+
+	if input.Since, err = helpers.ExtractArg[time.Time](args, "since"); err != nil {
+		input.Since = time.Now().AddDate(-1, 0, 0)
+	}
+
+	if input.Until, err = helpers.ExtractArg[time.Time](args, "until"); err != nil {
+		input.Until = time.Now()
+	}
+
+	return input
+}
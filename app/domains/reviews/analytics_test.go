@@ -0,0 +1,177 @@
+package reviews
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/golly-go/golly"
+	"github.com/golly-go/plugins/eventsource"
+	"github.com/golly-go/plugins/orm"
+	"github.com/google/uuid"
+	"github.com/mitchrodrigues/talent-review-backend/app/domains/accounts"
+	"github.com/mitchrodrigues/talent-review-backend/app/domains/employees"
+	"github.com/mitchrodrigues/talent-review-backend/app/domains/reviews/cycle"
+	"github.com/mitchrodrigues/talent-review-backend/app/domains/reviews/feedback"
+	"github.com/mitchrodrigues/talent-review-backend/app/utils/esbackend"
+	"github.com/mitchrodrigues/talent-review-backend/app/utils/identity"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFeedbackAnalytics(t *testing.T) {
+	gctx := orm.CreateTestContext(golly.NewContext(context.TODO()),
+		Feedback{},
+		Cycle{},
+		employees.Employee{},
+		employees.Team{},
+		employees.EmployeeRole{},
+		accounts.User{},
+		esbackend.Event{})
+
+	ident := identity.Identity{UID: uuid.New(), OrganizationID: uuid.New()}
+	now := time.Now()
+
+	manager := employees.NewTestEmployee(uuid.New(), ident.OrganizationID, "manager@example.com", &ident.UID)
+	orm.DB(gctx).Create(&manager)
+
+	report := employees.NewTestEmployee(uuid.New(), ident.OrganizationID, "report@example.com", nil)
+	report.ManagerID = &manager.ID
+	orm.DB(gctx).Create(&report)
+
+	ident.EmployeeID = manager.ID
+	ctx := identity.ToContext(gctx, ident)
+
+	h1 := Cycle{Aggregate: cycle.Aggregate{
+		OwnerID:        ident.UID,
+		OrganizationID: ident.OrganizationID,
+		StartAt:        now.AddDate(0, 0, -30),
+		EndAt:          now.Add(time.Hour),
+	}}
+	orm.DB(gctx).Create(&h1)
+
+	submitted := NewTestSubmittedFeedback(uuid.New(), ident.OrganizationID, report.ID, "reviewer@example.com")
+	submitted.OwnerID = ident.UID
+	orm.DB(gctx).Create(&submitted)
+
+	createFeedback := func(email string, createdAt time.Time, collectionEndAt time.Time, submittedAt *time.Time) Feedback {
+		fb := Feedback{Aggregate: feedback.Aggregate{
+			ModelUUID:       orm.ModelUUID{ID: uuid.New(), CreatedAt: createdAt},
+			Code:            uuid.NewString(),
+			Email:           email,
+			OwnerID:         ident.UID,
+			EmployeeID:      report.ID,
+			OrganizationID:  ident.OrganizationID,
+			CollectionEndAt: collectionEndAt,
+			SubmittedAt:     submittedAt,
+		}}
+		orm.DB(gctx).Create(&fb)
+		return fb
+	}
+
+	requestedAt := now.AddDate(0, 0, -4)
+	submittedAt := requestedAt.Add(10 * time.Hour)
+
+	createFeedback("reviewer@example.com", requestedAt, now, &submittedAt)
+	createFeedback("busy@example.com", requestedAt, now.AddDate(0, 0, -1), nil)
+	createFeedback("busy@example.com", now, now.AddDate(0, 0, 7), nil)
+	declined := createFeedback("declined@example.com", now, now.AddDate(0, 0, 7), nil)
+
+	// requested before the window
+	createFeedback("busy@example.com", now.AddDate(-2, 0, 0), now.AddDate(-2, 0, 7), nil)
+
+	assert.NoError(t, eventsource.Call(gctx, &declined.Aggregate, feedback.Decline{}, eventsource.Metadata{}))
+
+	input := FeedbackAnalyticsInput{Since: now.AddDate(-1, 0, 0), Until: now.Add(time.Minute)}
+
+	t.Run("declined feedback can not be submitted or declined again", func(t *testing.T) {
+		assert.NotNil(t, declined.DeclinedAt)
+
+		err := eventsource.Call(gctx, &declined.Aggregate, feedback.Submit{}, eventsource.Metadata{})
+		assert.ErrorContains(t, err, feedback.ErrorDeclined.Error())
+
+		err = eventsource.Call(gctx, &declined.Aggregate, feedback.Decline{}, eventsource.Metadata{})
+		assert.ErrorContains(t, err, feedback.ErrorDeclined.Error())
+	})
+
+	t.Run("rolls up the reports of the manager", func(t *testing.T) {
+		rollups, err := FeedbackAnalyticsRollups(ctx, input)
+		assert.NoError(t, err)
+
+		if assert.Len(t, rollups, 1) {
+			rollup := rollups[0]
+
+			assert.Equal(t, 5, rollup.Requested)
+			assert.Equal(t, 2, rollup.Submitted)
+			assert.Equal(t, 1, rollup.Declined)
+			assert.Equal(t, 1, rollup.Overdue)
+			assert.InDelta(t, 0.4, rollup.ResponseRate(), 0.001)
+			assert.InDelta(t, 0.2, rollup.DeclinedRate(), 0.001)
+			assert.InDelta(t, 5, rollup.MedianHoursToSubmit, 0.01)
+			assert.True(t, rollup.Period.IsZero())
+		}
+	})
+
+	t.Run("groups by manager and cycle", func(t *testing.T) {
+		orm.DB(gctx).Model(&employees.Employee{}).Where("id = ?", manager.ID).Update("name", "Morgan")
+
+		rollups, err := FeedbackAnalyticsRollups(ctx, FeedbackAnalyticsInput{GroupBy: AnalyticsGroupManager, Since: input.Since, Until: input.Until})
+		assert.NoError(t, err)
+
+		if assert.Len(t, rollups, 1) {
+			assert.Equal(t, manager.ID.String(), rollups[0].Key)
+			assert.Equal(t, "Morgan", rollups[0].Name)
+		}
+
+		rollups, err = FeedbackAnalyticsRollups(ctx, FeedbackAnalyticsInput{GroupBy: AnalyticsGroupCycle, Since: input.Since, Until: input.Until})
+		assert.NoError(t, err)
+
+		if assert.Len(t, rollups, 1) {
+			assert.Equal(t, h1.ID.String(), rollups[0].Key)
+			assert.Equal(t, 5, rollups[0].Requested)
+		}
+	})
+
+	t.Run("buckets by month", func(t *testing.T) {
+		rollups, err := FeedbackAnalyticsRollups(ctx, FeedbackAnalyticsInput{Interval: AnalyticsIntervalMonth, Since: input.Since, Until: input.Until})
+		assert.NoError(t, err)
+
+		total := 0
+		for _, rollup := range rollups {
+			assert.Equal(t, 1, rollup.Period.Day())
+			total += rollup.Requested
+		}
+		assert.Equal(t, 5, total)
+	})
+
+	t.Run("buckets by week starting monday", func(t *testing.T) {
+		rollups, err := FeedbackAnalyticsRollups(ctx, FeedbackAnalyticsInput{Interval: AnalyticsIntervalWeek, Since: input.Since, Until: input.Until})
+		assert.NoError(t, err)
+
+		for _, rollup := range rollups {
+			assert.Equal(t, time.Monday, rollup.Period.Weekday())
+		}
+	})
+
+	t.Run("reviewer load", func(t *testing.T) {
+		loads, err := ReviewerLoads(ctx, input)
+		assert.NoError(t, err)
+
+		if assert.Len(t, loads, 3) {
+			assert.Equal(t, ReviewerLoad{Email: "busy@example.com", Requested: 2, Pending: 2, Overdue: 1}, loads[0])
+			assert.Equal(t, ReviewerLoad{Email: "reviewer@example.com", Requested: 2, Submitted: 2}, loads[1])
+			assert.Equal(t, ReviewerLoad{Email: "declined@example.com", Requested: 1, Declined: 1}, loads[2])
+		}
+	})
+	t.Run("anonymous reviewers are left out", func(t *testing.T) {
+		anonymous := createFeedback("anonymous@example.com", requestedAt, now, &submittedAt)
+		orm.DB(gctx).Model(&Feedback{}).Where("id = ?", anonymous.ID).Update("visibility", feedback.VisibilityAnonymous)
+
+		loads, err := ReviewerLoads(ctx, input)
+		assert.NoError(t, err)
+		assert.Len(t, loads, 3)
+
+		for _, load := range loads {
+			assert.NotEqual(t, "anonymous@example.com", load.Email)
+		}
+	})
+}
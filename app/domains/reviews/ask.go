@@ -0,0 +1,155 @@
+package reviews
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/golly-go/golly"
+	"github.com/golly-go/golly/errors"
+	"github.com/golly-go/plugins/eventsource"
+	"github.com/golly-go/plugins/orm"
+	"github.com/google/uuid"
+	"github.com/mitchrodrigues/talent-review-backend/app/domains/common"
+	"github.com/mitchrodrigues/talent-review-backend/app/domains/employees"
+	"github.com/mitchrodrigues/talent-review-backend/app/domains/tara"
+	"github.com/mitchrodrigues/talent-review-backend/app/domains/tara/conversation"
+	"github.com/mitchrodrigues/talent-review-backend/app/utils/identity"
+	"gorm.io/gorm"
+)
+
+const (
+	askSnippetLimit = 12
+	askSummaryLimit = 10
+)
+
+var (
+	ErrorNotEmployeeManager      = fmt.Errorf("you can only ask about employees you manage")
+	ErrorConversationForEmployee = fmt.Errorf("conversation is about a different employee")
+)
+
+type AskTaraInput struct {
+	EmployeeID     uuid.UUID
+	ConversationID *uuid.UUID
+	Question       string
+}
+
+// AskTara answers a question about an employee the current user manages
+// from their feedback and stores the turn on the conversation, a new
+// conversation is started when no ID is given
+func AskTara(gctx golly.Context, input AskTaraInput, metadata eventsource.Metadata) (tara.Conversation, error) {
+	var conv tara.Conversation
+
+	if strings.TrimSpace(input.Question) == "" {
+		return conv, errors.WrapUnprocessable(conversation.ErrorQuestionRequired)
+	}
+
+	ident := identity.FromContext(gctx)
+
+	reports, err := employees.Service(gctx).FindEmployeesByManagerAndIDS(gctx, ident.EmployeeID, input.EmployeeID)
+	if err != nil || len(reports) == 0 {
+		return conv, errors.WrapForbidden(ErrorNotEmployeeManager)
+	}
+
+	employee := reports[0]
+
+	if input.ConversationID != nil {
+		if conv, err = tara.FindConversation(gctx, *input.ConversationID); err != nil {
+			return conv, err
+		}
+
+		if conv.EmployeeID != employee.ID {
+			return conv, errors.WrapUnprocessable(ErrorConversationForEmployee)
+		}
+	}
+
+	sources, err := AskTaraSources(gctx, employee.ID, input.Question)
+	if err != nil {
+		return conv, err
+	}
+
+	answer, err := tara.AnswerQuestion(gctx, tara.GenerateOptions{
+		Redactor: FeedbackRedactor(gctx, ident.OrganizationID),
+	}, tara.AskTaraInput{
+		Employee: employee.Name,
+		Question: input.Question,
+		Sources:  sources,
+	}, conv.Turns()...)
+
+	if err != nil {
+		return conv, err
+	}
+
+	if conv.ID == uuid.Nil {
+		err = eventsource.Call(gctx, &conv.Aggregate, conversation.Start{
+			OrganizationID: ident.OrganizationID,
+			UserID:         ident.UID,
+			EmployeeID:     employee.ID,
+			Question:       input.Question,
+		}, metadata)
+
+		if err != nil {
+			return conv, err
+		}
+	}
+
+	err = eventsource.Call(gctx, &conv.Aggregate, conversation.AddTurn{
+		Question:       input.Question,
+		Answer:         answer.Answer,
+		Citations:      answer.Citations,
+		PromptVersions: answer.PromptVersions,
+	}, metadata)
+
+	return conv, err
+}
+
+// AskTaraSources retrieves what tara answers from, the feedback excerpts
+// most relevant to the question and the latest feedback summaries the
+// current user may read
+func AskTaraSources(gctx golly.Context, employeeID uuid.UUID, question string) ([]tara.Source, error) {
+	snippets, err := SearchFeedback(gctx, question, askSnippetLimit, func(db *gorm.DB) *gorm.DB {
+		return db.Where("feedback_embeddings.employee_id = ?", employeeID)
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	var summaries []FeedbackSummary
+
+	err = orm.DB(gctx).
+		Model(&FeedbackSummary{}).
+		Select("feedback_summaries.*").
+		Joins("JOIN feedbacks ON feedbacks.id = feedback_summaries.feedback_id").
+		Scopes(common.JoinUserEmployeeRecord(gctx)).
+		Where("feedback_summaries.organization_id = ? AND feedback_summaries.employee_id = ?", identity.FromContext(gctx).OrganizationID, employeeID).
+		Scopes(AggregatedContentScope(gctx)).
+		Order("feedback_summaries.created_at DESC").
+		Limit(askSummaryLimit).
+		Find(&summaries).
+		Error
+
+	if err != nil {
+		return nil, errors.WrapGeneric(err)
+	}
+
+	sources := golly.Map(snippets, func(snippet FeedbackSearchResult) tara.Source {
+		return tara.Source{
+			ID:      snippet.FeedbackID,
+			Kind:    "feedback",
+			Section: snippet.Section,
+			Date:    snippet.CreatedAt,
+			Content: snippet.Content,
+		}
+	})
+
+	for _, summary := range summaries {
+		sources = append(sources, tara.Source{
+			ID:      summary.FeedbackID,
+			Kind:    "summary",
+			Date:    summary.CreatedAt,
+			Content: summary.Summary,
+		})
+	}
+
+	return sources, nil
+}
@@ -0,0 +1,214 @@
+package reviews
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/golly-go/golly"
+	"github.com/golly-go/plugins/eventsource"
+	"github.com/golly-go/plugins/orm"
+	"github.com/google/uuid"
+	"github.com/mitchrodrigues/talent-review-backend/app/domains/accounts"
+	"github.com/mitchrodrigues/talent-review-backend/app/domains/employees"
+	"github.com/mitchrodrigues/talent-review-backend/app/domains/reviews/feedback"
+	"github.com/mitchrodrigues/talent-review-backend/app/domains/tara"
+	"github.com/mitchrodrigues/talent-review-backend/app/domains/tara/conversation"
+	"github.com/mitchrodrigues/talent-review-backend/app/utils/esbackend"
+	"github.com/mitchrodrigues/talent-review-backend/app/utils/identity"
+	"github.com/mitchrodrigues/talent-review-backend/app/utils/openai"
+	"github.com/stretchr/testify/assert"
+)
+
+// createEmbeddedFeedback creates submitted feedback about the employee
+// and embeds it
+func createEmbeddedFeedback(t *testing.T, gctx golly.Context, organizationID, employeeID uuid.UUID, strengths, opportunities string) Feedback {
+	fb := NewTestSubmittedFeedback(uuid.New(), organizationID, employeeID, "reviewer@example.com")
+	orm.DB(gctx).Create(&fb)
+
+	details := NewTestFeedbackDetails(fb, tiptapDocument(strengths), tiptapDocument(opportunities))
+	orm.DB(gctx).Create(&details)
+
+	assert.NoError(t, EmbedFeedback(gctx, &fb.Aggregate))
+	return fb
+}
+
+func TestAskTara(t *testing.T) {
+	gctx := orm.CreateTestContext(golly.NewContext(context.TODO()),
+		Feedback{},
+		FeedbackDetails{},
+		FeedbackEmbedding{},
+		FeedbackSummary{},
+		accounts.Organization{},
+		employees.Employee{},
+		tara.Conversation{},
+		tara.Prompt{},
+		esbackend.Event{})
+
+	gctx = openai.UseProvider(gctx, openai.NewFixtureProvider(nil))
+
+	organizationID := uuid.New()
+	managerUserID := uuid.New()
+
+	manager := employees.NewTestEmployee(uuid.New(), organizationID, "manager@example.com", &managerUserID)
+	orm.DB(gctx).Create(&manager)
+
+	report := employees.NewTestEmployee(uuid.New(), organizationID, "report@example.com", nil)
+	report.ManagerID = &manager.ID
+	orm.DB(gctx).Create(&report)
+
+	other := employees.NewTestEmployee(uuid.New(), organizationID, "other@example.com", nil)
+	orm.DB(gctx).Create(&other)
+
+	visible := createEmbeddedFeedback(t, gctx, organizationID, report.ID, "Runs great planning meetings.", "Could delegate more often.")
+	hidden := createEmbeddedFeedback(t, gctx, organizationID, other.ID, "Runs great planning meetings.", "Writes clear docs.")
+
+	ctx := openai.UseProvider(identity.ToContext(gctx, identity.Identity{
+		UID:            managerUserID,
+		OrganizationID: organizationID,
+		EmployeeID:     manager.ID,
+	}), &openai.FixtureProvider{
+		Default: fmt.Sprintf(`{"answer":"A peer suggested delegating more.","citations":["%s","%s"]}`,
+			visible.ID, hidden.ID),
+	})
+
+	t.Run("not a report of the user", func(t *testing.T) {
+		_, err := AskTara(ctx, AskTaraInput{EmployeeID: other.ID, Question: "How is it going?"}, eventsource.Metadata{})
+		assert.ErrorContains(t, err, ErrorNotEmployeeManager.Error())
+	})
+
+	t.Run("question required", func(t *testing.T) {
+		_, err := AskTara(ctx, AskTaraInput{EmployeeID: report.ID, Question: " "}, eventsource.Metadata{})
+		assert.ErrorContains(t, err, conversation.ErrorQuestionRequired.Error())
+	})
+
+	var conversationID uuid.UUID
+
+	t.Run("starts a conversation", func(t *testing.T) {
+		conv, err := AskTara(ctx, AskTaraInput{EmployeeID: report.ID, Question: "Does she delegate?"}, eventsource.Metadata{})
+		assert.NoError(t, err)
+
+		conversationID = conv.ID
+
+		assert.Equal(t, "Does she delegate?", conv.Title)
+		if assert.Len(t, conv.Messages, 2) {
+			assert.Equal(t, "A peer suggested delegating more.", conv.Messages[1].Content)
+
+			// feedback the manager cannot see is never cited
+			assert.Equal(t, []uuid.UUID{visible.ID}, conv.Messages[1].Citations)
+		}
+	})
+
+	t.Run("continues a conversation", func(t *testing.T) {
+		conv, err := AskTara(ctx, AskTaraInput{
+			EmployeeID:     report.ID,
+			ConversationID: &conversationID,
+			Question:       "Anything else?",
+		}, eventsource.Metadata{})
+
+		assert.NoError(t, err)
+		assert.Equal(t, conversationID, conv.ID)
+		assert.Len(t, conv.Messages, 4)
+
+		stored, err := tara.FindConversation(ctx, conversationID)
+		assert.NoError(t, err)
+		assert.Equal(t, []tara.Turn{
+			{Question: "Does she delegate?", Answer: "A peer suggested delegating more."},
+			{Question: "Anything else?", Answer: "A peer suggested delegating more."},
+		}, stored.Turns())
+	})
+
+	t.Run("conversation about another employee", func(t *testing.T) {
+		_, err := AskTara(ctx, AskTaraInput{
+			EmployeeID:     other.ID,
+			ConversationID: &conversationID,
+			Question:       "And them?",
+		}, eventsource.Metadata{})
+
+		assert.Error(t, err)
+	})
+}
+
+func TestAskTaraSources(t *testing.T) {
+	gctx := orm.CreateTestContext(golly.NewContext(context.TODO()),
+		Feedback{},
+		FeedbackDetails{},
+		FeedbackEmbedding{},
+		FeedbackSummary{},
+		accounts.Organization{},
+		employees.Employee{})
+
+	gctx = openai.UseProvider(gctx, openai.NewFixtureProvider(nil))
+
+	organizationID := uuid.New()
+	managerUserID := uuid.New()
+
+	manager := employees.NewTestEmployee(uuid.New(), organizationID, "manager@example.com", &managerUserID)
+	orm.DB(gctx).Create(&manager)
+
+	report := employees.NewTestEmployee(uuid.New(), organizationID, "report@example.com", nil)
+	report.ManagerID = &manager.ID
+	orm.DB(gctx).Create(&report)
+
+	visible := createEmbeddedFeedback(t, gctx, organizationID, report.ID, "Runs great planning meetings.", "Could delegate more often.")
+	otherOrg := createEmbeddedFeedback(t, gctx, uuid.New(), report.ID, "Runs great planning meetings.", "Ships fast.")
+
+	ctx := identity.ToContext(gctx, identity.Identity{
+		UID:            managerUserID,
+		OrganizationID: organizationID,
+		EmployeeID:     manager.ID,
+	})
+
+	orm.DB(gctx).Create(&FeedbackSummary{FeedbackSummary: feedback.FeedbackSummary{
+		FeedbackID:     visible.ID,
+		EmployeeID:     report.ID,
+		OrganizationID: report.OrganizationID,
+		Summary:        "Strong planner, should delegate more.",
+	}})
+
+	orm.DB(gctx).Create(&FeedbackSummary{FeedbackSummary: feedback.FeedbackSummary{
+		FeedbackID:     otherOrg.ID,
+		EmployeeID:     report.ID,
+		OrganizationID: otherOrg.OrganizationID,
+		Summary:        "Ships fast.",
+	}})
+
+	sources, err := AskTaraSources(ctx, report.ID, "Could delegate more often.")
+	assert.NoError(t, err)
+
+	if assert.Len(t, sources, 3) {
+		assert.Equal(t, visible.ID, sources[0].ID)
+		assert.Equal(t, "opportunities", sources[0].Section)
+
+		assert.Equal(t, "summary", sources[2].Kind)
+		assert.Equal(t, "Strong planner, should delegate more.", sources[2].Content)
+	}
+	t.Run("aggregated feedback below the threshold is left out", func(t *testing.T) {
+		// alone in its round so its content is held back from the manager
+		aggregated := NewTestSubmittedFeedback(uuid.New(), organizationID, report.ID, "aggregated@example.com")
+		aggregated.CollectionEndAt = time.Now().Add(48 * time.Hour).Truncate(time.Second)
+		aggregated.Visibility = feedback.VisibilityAggregated
+		orm.DB(gctx).Create(&aggregated)
+
+		details := NewTestFeedbackDetails(aggregated, tiptapDocument("Could delegate more often."), "")
+		orm.DB(gctx).Create(&details)
+
+		assert.NoError(t, EmbedFeedback(gctx, &aggregated.Aggregate))
+
+		orm.DB(gctx).Create(&FeedbackSummary{FeedbackSummary: feedback.FeedbackSummary{
+			FeedbackID:     aggregated.ID,
+			EmployeeID:     report.ID,
+			OrganizationID: report.OrganizationID,
+			Summary:        "Only one peer said this.",
+		}})
+
+		sources, err := AskTaraSources(ctx, report.ID, "Could delegate more often.")
+		assert.NoError(t, err)
+		assert.Len(t, sources, 3)
+
+		for _, source := range sources {
+			assert.NotEqual(t, aggregated.ID, source.ID)
+		}
+	})
+}
@@ -2,7 +2,6 @@ package reviews
 
 import (
 	"fmt"
-	"strings"
 	"time"
 
 	"github.com/golly-go/golly"
@@ -20,6 +19,31 @@ type CreateBulkFeedbackInput struct {
 	IncludeTeam      bool
 	IncludeDirects   bool
 	CollectionEndAt  time.Time
+
+	// SuggestReviewers picks that many reviewers per employee with the
+	// suggestion engine instead of IncludeTeam and IncludeDirects
+	SuggestReviewers int
+
+	// MaxOpenRequests caps the requests a suggested reviewer has waiting
+	// on them, defaults to DefaultMaxOpenRequests
+	MaxOpenRequests int
+
+	// Visibility of the requested feedback, empty is attributed
+	Visibility string
+}
+
+// FeedbackPlan is who will be asked for feedback on an employee
+type FeedbackPlan struct {
+	Employee employees.Employee
+
+	Reviewers []ReviewerSuggestion
+	Skipped   []ReviewerSuggestion
+}
+
+func (plan FeedbackPlan) Emails() []string {
+	return golly.Map(plan.Reviewers, func(reviewer ReviewerSuggestion) string {
+		return reviewer.Email
+	})
 }
 
 func CreateBulkFeedback(gctx golly.Context, input CreateBulkFeedbackInput, metadata eventsource.Metadata) ([]Feedback, error) {
@@ -29,9 +53,43 @@ func CreateBulkFeedback(gctx golly.Context, input CreateBulkFeedbackInput, metad
 
 	results := []Feedback{}
 
+	plans, err := PlanBulkFeedback(gctx, input)
+	if err != nil {
+		return results, err
+	}
+
+	for _, plan := range plans {
+		for _, email := range plan.Emails() {
+			gctx.Logger().Debugf("Starting Process Of Bulk Feedback: %#v", plan.Employee)
+
+			record := Feedback{}
+
+			err := eventsource.Call(gctx, &record.Aggregate, feedback.Create{
+				CollectionEndAt: input.CollectionEndAt,
+				EmployeeID:      plan.Employee.ID,
+				OrganizationID:  ident.OrganizationID,
+				Email:           email,
+				Visibility:      input.Visibility,
+			}, metadata)
+
+			if err != nil {
+				return results, err
+			}
+
+			results = append(results, record)
+		}
+	}
+	return results, nil
+}
+
+// PlanBulkFeedback works out who CreateBulkFeedback would ask for
+// feedback on each employee without sending anything
+func PlanBulkFeedback(gctx golly.Context, input CreateBulkFeedbackInput) ([]FeedbackPlan, error) {
+	ident := identity.FromContext(gctx)
+
 	manager, err := employees.Service(gctx).FindEmployeeByUserID(gctx, ident.UID)
 	if err != nil {
-		return []Feedback{}, errors.WrapGeneric(fmt.Errorf("you are not a manager of any team"))
+		return nil, errors.WrapGeneric(fmt.Errorf("you are not a manager of any team"))
 	}
 
 	emps, err := employees.Service(gctx).FindEmployeesByManagerAndIDS(gctx, manager.ID, input.EmployeeIDs...)
@@ -43,18 +101,23 @@ func CreateBulkFeedback(gctx golly.Context, input CreateBulkFeedbackInput, metad
 		return nil, errors.WrapGeneric(fmt.Errorf("you do not have any employees matching the criteria"))
 	}
 
+	if input.SuggestReviewers > 0 {
+		return SuggestReviewers(gctx, manager, emps, input)
+	}
+
+	plans := []FeedbackPlan{}
+
 	for _, employee := range emps {
-		emails := input.AdditionalEmails
+		reviewers := reviewerCandidates{}
+		reviewers.add(ReasonAdditional, input.AdditionalEmails...)
 
 		if input.IncludeTeam {
 			teamMates, err := getTeamMates(gctx, employee.TeamID)
 			if err != nil {
-				return results, err
+				return plans, err
 			}
 
-			emails = append(emails, golly.Map(teamMates, func(employee employees.Employee) string {
-				return employee.Email
-			})...)
+			reviewers.add(ReasonTeam, employeeEmails(teamMates)...)
 		}
 
 		if input.IncludeDirects {
@@ -62,43 +125,28 @@ func CreateBulkFeedback(gctx golly.Context, input CreateBulkFeedbackInput, metad
 
 			emps, err := employees.Service(gctx).FindEmployeesByManagerID(gctx, employee.ID)
 			if err != nil {
-				return results, err
+				return plans, err
 			}
 
-			if len(emps) > 0 {
-				emails = append(emails, golly.Map(emps, func(e employees.Employee) string {
-					return e.Email
-				})...)
-			}
+			reviewers.add(ReasonDirectReport, employeeEmails(emps)...)
 		}
 
-		emails = golly.Unique(emails)
-
-		for _, email := range emails {
-			if strings.EqualFold(email, employee.Email) {
-				continue
-			}
-
-			gctx.Logger().Debugf("Starting Process Of Bulk Feedback: %#v", employee)
+		reviewers.remove(employee.Email)
 
-			record := Feedback{}
-
-			err := eventsource.Call(gctx, &record.Aggregate, feedback.Create{
-				CollectionEndAt: input.CollectionEndAt,
-				EmployeeID:      employee.ID,
-				OrganizationID:  ident.OrganizationID,
-				Email:           email,
-			}, metadata)
-
-			if err != nil {
-				return results, err
-			}
+		plans = append(plans, FeedbackPlan{
+			Employee:  employee,
+			Reviewers: reviewers.list(),
+			Skipped:   []ReviewerSuggestion{},
+		})
+	}
 
-			results = append(results, record)
-		}
+	return plans, nil
+}
 
-	}
-	return results, err
+func employeeEmails(emps []employees.Employee) []string {
+	return golly.Map(emps, func(e employees.Employee) string {
+		return e.Email
+	})
 }
 
 func getTeamMates(gctx golly.Context, teamID *uuid.UUID) ([]employees.Employee, error) {
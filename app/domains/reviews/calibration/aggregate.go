@@ -0,0 +1,187 @@
+package calibration
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/golly-go/golly"
+	"github.com/golly-go/plugins/eventsource"
+	"github.com/golly-go/plugins/orm"
+	"github.com/google/uuid"
+	"github.com/mitchrodrigues/talent-review-backend/app/utils/esbackend"
+)
+
+const (
+	StatusOpen   = "OPEN"
+	StatusClosed = "CLOSED"
+
+	AdjustmentRating    = "RATING"
+	AdjustmentPotential = "POTENTIAL"
+
+	MinRating    = 1
+	MaxRating    = 5
+	MinPotential = 1
+	MaxPotential = 3
+)
+
+// Aggregate is a calibration session, the people taking part compare
+// the ratings of the employees of a cycle and adjust them
+type Aggregate struct {
+	eventsource.AggregateBase
+
+	orm.ModelUUID
+
+	OrganizationID uuid.UUID
+	CycleID        uuid.UUID
+	FacilitatorID  uuid.UUID
+
+	Name string
+
+	// ParticipantIDs are the users calibrating with the facilitator and
+	// EmployeeIDs the employees calibrated, both are fixed when opened
+	ParticipantIDs UUIDs `gorm:"type:jsonb"`
+	EmployeeIDs    UUIDs `gorm:"type:jsonb"`
+
+	Adjustments Adjustments `gorm:"type:jsonb"`
+
+	Status   string
+	ClosedAt *time.Time
+}
+
+func (*Aggregate) Topic() string                             { return "events.calibration_sessions" }
+func (*Aggregate) Repo(golly.Context) eventsource.Repository { return esbackend.PostgresRepository{} }
+func (*Aggregate) TableName() string                         { return "calibration_sessions" }
+
+func (session *Aggregate) GetID() string   { return session.ID.String() }
+func (session *Aggregate) SetID(id string) { session.ID, _ = uuid.Parse(id) }
+
+func (session *Aggregate) Apply(ctx golly.Context, evt eventsource.Event) {
+	switch event := evt.Data.(type) {
+	case Opened:
+		session.ID = event.ID
+		session.OrganizationID = event.OrganizationID
+		session.CycleID = event.CycleID
+		session.FacilitatorID = event.FacilitatorID
+		session.Name = event.Name
+		session.ParticipantIDs = event.ParticipantIDs
+		session.EmployeeIDs = event.EmployeeIDs
+		session.Adjustments = Adjustments{}
+		session.Status = StatusOpen
+
+		session.CreatedAt = evt.CreatedAt
+
+	case RatingAdjusted:
+		session.Adjustments = append(session.Adjustments, Adjustment{
+			Kind:       AdjustmentRating,
+			EmployeeID: event.EmployeeID,
+			UserID:     event.UserID,
+			From:       event.From,
+			To:         event.To,
+			Rationale:  event.Rationale,
+			CreatedAt:  evt.CreatedAt,
+		})
+
+	case PotentialSet:
+		session.Adjustments = append(session.Adjustments, Adjustment{
+			Kind:       AdjustmentPotential,
+			EmployeeID: event.EmployeeID,
+			UserID:     event.UserID,
+			From:       event.From,
+			To:         event.To,
+			Rationale:  event.Rationale,
+			CreatedAt:  evt.CreatedAt,
+		})
+
+	case Closed:
+		session.Status = StatusClosed
+		session.ClosedAt = &evt.CreatedAt
+	}
+	session.UpdatedAt = evt.CreatedAt
+}
+
+func (session *Aggregate) Open() bool { return session.Status == StatusOpen }
+
+// IsParticipant is true for the facilitator and the participants
+func (session *Aggregate) IsParticipant(userID uuid.UUID) bool {
+	return session.FacilitatorID == userID || slices.Contains(session.ParticipantIDs, userID)
+}
+
+func (session *Aggregate) Includes(employeeID uuid.UUID) bool {
+	return slices.Contains(session.EmployeeIDs, employeeID)
+}
+
+// Adjustment is a change to the rating or potential of an employee made
+// during the session and why it was made
+type Adjustment struct {
+	Kind       string    `json:"kind"`
+	EmployeeID uuid.UUID `json:"employeeID"`
+	UserID     uuid.UUID `json:"userID"`
+	From       int       `json:"from"`
+	To         int       `json:"to"`
+	Rationale  string    `json:"rationale"`
+	CreatedAt  time.Time `json:"createdAt"`
+}
+
+// Adjustments are stored as jsonb in the order they were made
+type Adjustments []Adjustment
+
+// Latest is the last adjustment of the kind for the employee
+func (a Adjustments) Latest(kind string, employeeID uuid.UUID) (Adjustment, bool) {
+	for i := len(a) - 1; i >= 0; i-- {
+		if a[i].Kind == kind && a[i].EmployeeID == employeeID {
+			return a[i], true
+		}
+	}
+	return Adjustment{}, false
+}
+
+// For lists the adjustments made to an employee
+func (a Adjustments) For(employeeID uuid.UUID) Adjustments {
+	return slices.DeleteFunc(slices.Clone(a), func(adj Adjustment) bool { return adj.EmployeeID != employeeID })
+}
+
+func (a Adjustments) Value() (driver.Value, error) {
+	if a == nil {
+		return []byte("[]"), nil
+	}
+	return json.Marshal(a)
+}
+
+func (a *Adjustments) Scan(value interface{}) error {
+	switch v := value.(type) {
+	case nil:
+		return nil
+	case []byte:
+		return json.Unmarshal(v, a)
+	case string:
+		return json.Unmarshal([]byte(v), a)
+	}
+	return fmt.Errorf("cannot scan %T into adjustments", value)
+}
+
+// UUIDs are stored as a jsonb array
+type UUIDs []uuid.UUID
+
+func (u UUIDs) Value() (driver.Value, error) {
+	if u == nil {
+		return []byte("[]"), nil
+	}
+	return json.Marshal(u)
+}
+
+func (u *UUIDs) Scan(value interface{}) error {
+	switch v := value.(type) {
+	case nil:
+		return nil
+	case []byte:
+		return json.Unmarshal(v, u)
+	case string:
+		return json.Unmarshal([]byte(v), u)
+	}
+	return fmt.Errorf("cannot scan %T into uuids", value)
+}
+
+var _ eventsource.Aggregate = &Aggregate{}
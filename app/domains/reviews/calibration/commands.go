@@ -0,0 +1,183 @@
+package calibration
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/golly-go/golly"
+	"github.com/golly-go/golly/errors"
+	"github.com/golly-go/plugins/eventsource"
+	"github.com/google/uuid"
+)
+
+var (
+	ErrorCycleRequired       = fmt.Errorf("calibration cycle is required")
+	ErrorNameRequired        = fmt.Errorf("calibration name is required")
+	ErrorEmployeesRequired   = fmt.Errorf("calibration needs at least one employee")
+	ErrorNotOpen             = fmt.Errorf("calibration session is closed")
+	ErrorNotParticipant      = fmt.Errorf("you are not part of this calibration session")
+	ErrorNotFacilitator      = fmt.Errorf("only the facilitator can close the calibration session")
+	ErrorEmployeeNotIncluded = fmt.Errorf("employee is not part of this calibration session")
+	ErrorInvalidRating       = fmt.Errorf("rating must be between %d and %d", MinRating, MaxRating)
+	ErrorInvalidPotential    = fmt.Errorf("potential must be between %d and %d", MinPotential, MaxPotential)
+	ErrorRationaleRequired   = fmt.Errorf("a rationale is required for every adjustment")
+)
+
+type Open struct {
+	OrganizationID uuid.UUID
+	CycleID        uuid.UUID
+	FacilitatorID  uuid.UUID
+
+	Name string
+
+	ParticipantIDs []uuid.UUID
+	EmployeeIDs    []uuid.UUID
+}
+
+func (cmd Open) Validate(ctx golly.Context, aggregate eventsource.Aggregate) error {
+	switch {
+	case cmd.CycleID == uuid.Nil:
+		return errors.WrapUnprocessable(ErrorCycleRequired)
+	case strings.TrimSpace(cmd.Name) == "":
+		return errors.WrapUnprocessable(ErrorNameRequired)
+	case len(cmd.EmployeeIDs) == 0:
+		return errors.WrapUnprocessable(ErrorEmployeesRequired)
+	}
+	return nil
+}
+
+func (cmd Open) Perform(ctx golly.Context, aggregate eventsource.Aggregate) error {
+	id, _ := uuid.NewV7()
+
+	eventsource.Apply(ctx, aggregate, Opened{
+		ID:             id,
+		OrganizationID: cmd.OrganizationID,
+		CycleID:        cmd.CycleID,
+		FacilitatorID:  cmd.FacilitatorID,
+		Name:           strings.TrimSpace(cmd.Name),
+		ParticipantIDs: unique(cmd.ParticipantIDs, cmd.FacilitatorID),
+		EmployeeIDs:    unique(cmd.EmployeeIDs),
+	})
+	return nil
+}
+
+// AdjustRating sets the calibrated rating of an employee, From is the
+// rating the employee had on the grid before the adjustment
+type AdjustRating struct {
+	UserID     uuid.UUID
+	EmployeeID uuid.UUID
+
+	From      int
+	To        int
+	Rationale string
+}
+
+func (cmd AdjustRating) Validate(ctx golly.Context, aggregate eventsource.Aggregate) error {
+	if err := validateAdjustment(aggregate.(*Aggregate), cmd.UserID, cmd.EmployeeID, cmd.Rationale); err != nil {
+		return err
+	}
+
+	if cmd.To < MinRating || cmd.To > MaxRating {
+		return errors.WrapUnprocessable(ErrorInvalidRating)
+	}
+
+	return nil
+}
+
+func (cmd AdjustRating) Perform(ctx golly.Context, aggregate eventsource.Aggregate) error {
+	eventsource.Apply(ctx, aggregate, RatingAdjusted{
+		EmployeeID: cmd.EmployeeID,
+		UserID:     cmd.UserID,
+		From:       cmd.From,
+		To:         cmd.To,
+		Rationale:  strings.TrimSpace(cmd.Rationale),
+	})
+	return nil
+}
+
+// SetPotential places an employee on the potential axis of the nine-box
+type SetPotential struct {
+	UserID     uuid.UUID
+	EmployeeID uuid.UUID
+
+	Potential int
+	Rationale string
+}
+
+func (cmd SetPotential) Validate(ctx golly.Context, aggregate eventsource.Aggregate) error {
+	if err := validateAdjustment(aggregate.(*Aggregate), cmd.UserID, cmd.EmployeeID, cmd.Rationale); err != nil {
+		return err
+	}
+
+	if cmd.Potential < MinPotential || cmd.Potential > MaxPotential {
+		return errors.WrapUnprocessable(ErrorInvalidPotential)
+	}
+
+	return nil
+}
+
+func (cmd SetPotential) Perform(ctx golly.Context, aggregate eventsource.Aggregate) error {
+	session := aggregate.(*Aggregate)
+
+	from, _ := session.Adjustments.Latest(AdjustmentPotential, cmd.EmployeeID)
+
+	eventsource.Apply(ctx, aggregate, PotentialSet{
+		EmployeeID: cmd.EmployeeID,
+		UserID:     cmd.UserID,
+		From:       from.To,
+		To:         cmd.Potential,
+		Rationale:  strings.TrimSpace(cmd.Rationale),
+	})
+	return nil
+}
+
+type Close struct {
+	UserID uuid.UUID
+}
+
+func (cmd Close) Validate(ctx golly.Context, aggregate eventsource.Aggregate) error {
+	session := aggregate.(*Aggregate)
+
+	if !session.Open() {
+		return errors.WrapUnprocessable(ErrorNotOpen)
+	}
+
+	if session.FacilitatorID != cmd.UserID {
+		return errors.WrapForbidden(ErrorNotFacilitator)
+	}
+
+	return nil
+}
+
+func (cmd Close) Perform(ctx golly.Context, aggregate eventsource.Aggregate) error {
+	eventsource.Apply(ctx, aggregate, Closed{UserID: cmd.UserID})
+	return nil
+}
+
+func validateAdjustment(session *Aggregate, userID, employeeID uuid.UUID, rationale string) error {
+	switch {
+	case !session.Open():
+		return errors.WrapUnprocessable(ErrorNotOpen)
+	case !session.IsParticipant(userID):
+		return errors.WrapForbidden(ErrorNotParticipant)
+	case !session.Includes(employeeID):
+		return errors.WrapUnprocessable(ErrorEmployeeNotIncluded)
+	case strings.TrimSpace(rationale) == "":
+		return errors.WrapUnprocessable(ErrorRationaleRequired)
+	}
+	return nil
+}
+
+func unique(ids []uuid.UUID, exclude ...uuid.UUID) UUIDs {
+	ret := UUIDs{}
+
+	for _, id := range ids {
+		if id == uuid.Nil || slices.Contains(exclude, id) || slices.Contains(ret, id) {
+			continue
+		}
+		ret = append(ret, id)
+	}
+
+	return ret
+}
@@ -0,0 +1,105 @@
+package calibration
+
+import (
+	"context"
+	"testing"
+
+	"github.com/golly-go/golly"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOpenValidate(t *testing.T) {
+	tests := []struct {
+		name      string
+		cmd       Open
+		expectErr error
+	}{
+		{name: "valid", cmd: Open{CycleID: uuid.New(), Name: "H1", EmployeeIDs: []uuid.UUID{uuid.New()}}},
+		{name: "missing cycle", cmd: Open{Name: "H1", EmployeeIDs: []uuid.UUID{uuid.New()}}, expectErr: ErrorCycleRequired},
+		{name: "missing name", cmd: Open{CycleID: uuid.New(), Name: " ", EmployeeIDs: []uuid.UUID{uuid.New()}}, expectErr: ErrorNameRequired},
+		{name: "no employees", cmd: Open{CycleID: uuid.New(), Name: "H1"}, expectErr: ErrorEmployeesRequired},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.cmd.Validate(golly.Context{}, &Aggregate{})
+			if tt.expectErr != nil {
+				assert.ErrorContains(t, err, tt.expectErr.Error())
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestCalibrationSession(t *testing.T) {
+	gctx := golly.NewContext(context.TODO())
+
+	facilitatorID, participantID, employeeID := uuid.New(), uuid.New(), uuid.New()
+
+	session := &Aggregate{}
+
+	assert.NoError(t, Open{
+		CycleID:        uuid.New(),
+		FacilitatorID:  facilitatorID,
+		Name:           " H1 calibration ",
+		ParticipantIDs: []uuid.UUID{participantID, facilitatorID, participantID},
+		EmployeeIDs:    []uuid.UUID{employeeID, employeeID},
+	}.Perform(gctx, session))
+
+	assert.Equal(t, "H1 calibration", session.Name)
+	assert.Equal(t, UUIDs{participantID}, session.ParticipantIDs)
+	assert.Equal(t, UUIDs{employeeID}, session.EmployeeIDs)
+	assert.True(t, session.IsParticipant(facilitatorID))
+
+	t.Run("adjustments", func(t *testing.T) {
+		tests := []struct {
+			name      string
+			cmd       AdjustRating
+			expectErr error
+		}{
+			{name: "outsider", cmd: AdjustRating{UserID: uuid.New(), EmployeeID: employeeID, To: 3, Rationale: "x"}, expectErr: ErrorNotParticipant},
+			{name: "unknown employee", cmd: AdjustRating{UserID: participantID, EmployeeID: uuid.New(), To: 3, Rationale: "x"}, expectErr: ErrorEmployeeNotIncluded},
+			{name: "no rationale", cmd: AdjustRating{UserID: participantID, EmployeeID: employeeID, To: 3, Rationale: " "}, expectErr: ErrorRationaleRequired},
+			{name: "invalid rating", cmd: AdjustRating{UserID: participantID, EmployeeID: employeeID, To: 6, Rationale: "x"}, expectErr: ErrorInvalidRating},
+			{name: "valid", cmd: AdjustRating{UserID: participantID, EmployeeID: employeeID, To: 4, Rationale: "x"}},
+		}
+
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				err := tt.cmd.Validate(gctx, session)
+				if tt.expectErr != nil {
+					assert.ErrorContains(t, err, tt.expectErr.Error())
+				} else {
+					assert.NoError(t, err)
+				}
+			})
+		}
+
+		assert.NoError(t, AdjustRating{UserID: participantID, EmployeeID: employeeID, From: 3, To: 4, Rationale: "Led the launch"}.Perform(gctx, session))
+		assert.NoError(t, SetPotential{UserID: facilitatorID, EmployeeID: employeeID, Potential: 2, Rationale: "Ready for more scope"}.Perform(gctx, session))
+		assert.NoError(t, SetPotential{UserID: facilitatorID, EmployeeID: employeeID, Potential: 3, Rationale: "Agreed higher"}.Perform(gctx, session))
+
+		assert.ErrorContains(t, SetPotential{UserID: facilitatorID, EmployeeID: employeeID, Potential: 4, Rationale: "x"}.Validate(gctx, session), ErrorInvalidPotential.Error())
+
+		rating, ok := session.Adjustments.Latest(AdjustmentRating, employeeID)
+		assert.True(t, ok)
+		assert.Equal(t, Adjustment{Kind: AdjustmentRating, EmployeeID: employeeID, UserID: participantID, From: 3, To: 4, Rationale: "Led the launch", CreatedAt: rating.CreatedAt}, rating)
+
+		potential, _ := session.Adjustments.Latest(AdjustmentPotential, employeeID)
+		assert.Equal(t, 2, potential.From)
+		assert.Equal(t, 3, potential.To)
+		assert.Len(t, session.Adjustments.For(employeeID), 3)
+	})
+
+	t.Run("close", func(t *testing.T) {
+		assert.ErrorContains(t, Close{UserID: participantID}.Validate(gctx, session), ErrorNotFacilitator.Error())
+		assert.NoError(t, Close{UserID: facilitatorID}.Validate(gctx, session))
+		assert.NoError(t, Close{UserID: facilitatorID}.Perform(gctx, session))
+
+		assert.Equal(t, StatusClosed, session.Status)
+		assert.NotNil(t, session.ClosedAt)
+		assert.ErrorContains(t, AdjustRating{UserID: participantID, EmployeeID: employeeID, To: 2, Rationale: "x"}.Validate(gctx, session), ErrorNotOpen.Error())
+	})
+}
@@ -0,0 +1,39 @@
+package calibration
+
+import (
+	"github.com/google/uuid"
+)
+
+type Opened struct {
+	ID             uuid.UUID `json:"id"`
+	OrganizationID uuid.UUID `json:"organizationID"`
+	CycleID        uuid.UUID `json:"cycleID"`
+	FacilitatorID  uuid.UUID `json:"facilitatorID"`
+
+	Name string `json:"name"`
+
+	ParticipantIDs UUIDs `json:"participantIDs"`
+	EmployeeIDs    UUIDs `json:"employeeIDs"`
+}
+
+type RatingAdjusted struct {
+	EmployeeID uuid.UUID `json:"employeeID"`
+	UserID     uuid.UUID `json:"userID"`
+
+	From      int    `json:"-"`
+	To        int    `json:"-"`
+	Rationale string `json:"-"`
+}
+
+type PotentialSet struct {
+	EmployeeID uuid.UUID `json:"employeeID"`
+	UserID     uuid.UUID `json:"userID"`
+
+	From      int    `json:"-"`
+	To        int    `json:"-"`
+	Rationale string `json:"-"`
+}
+
+type Closed struct {
+	UserID uuid.UUID `json:"userID"`
+}
@@ -0,0 +1,468 @@
+package reviews
+
+import (
+	"cmp"
+	"fmt"
+	"math"
+	"slices"
+	"strconv"
+
+	"github.com/golly-go/golly"
+	"github.com/golly-go/golly/errors"
+	"github.com/golly-go/plugins/eventsource"
+	"github.com/golly-go/plugins/orm"
+	"github.com/google/uuid"
+	"github.com/mitchrodrigues/talent-review-backend/app/domains/accounts"
+	"github.com/mitchrodrigues/talent-review-backend/app/domains/common"
+	"github.com/mitchrodrigues/talent-review-backend/app/domains/employees"
+	"github.com/mitchrodrigues/talent-review-backend/app/domains/reviews/calibration"
+	"github.com/mitchrodrigues/talent-review-backend/app/domains/reviews/review"
+	"github.com/mitchrodrigues/talent-review-backend/app/utils/identity"
+	"gorm.io/gorm"
+)
+
+const (
+	CalibrationGroupTeam    = "TEAM"
+	CalibrationGroupLevel   = "LEVEL"
+	CalibrationGroupManager = "MANAGER"
+)
+
+var (
+	ErrorNotCalibrationManager = fmt.Errorf("only hr or managers can open a calibration session")
+	ErrorUnknownParticipant    = fmt.Errorf("calibration participants must be users of your organization")
+)
+
+type CalibrationSession struct {
+	calibration.Aggregate
+}
+
+func (CalibrationSession) TableName() string { return "calibration_sessions" }
+
+// CalibrationEntry is an employee on the calibration grid with the
+// ratings the session starts from and the calibrated rating and potential
+type CalibrationEntry struct {
+	Employee employees.Employee
+	Manager  *employees.Employee
+
+	FeedbackRating float64
+	FeedbackCount  int
+	ReviewRating   int
+
+	// Rating is the calibrated rating, the finalized review rating or the
+	// rounded average feedback rating in that order, 0 when unrated
+	Rating     int
+	Calibrated bool
+	Potential  int
+
+	Adjustments calibration.Adjustments
+}
+
+// Performance is the band of the rating on the nine-box, 1 (below
+// expectations) to 3 (above), 0 when unrated
+func (entry CalibrationEntry) Performance() int {
+	switch {
+	case entry.Rating <= 0:
+		return 0
+	case entry.Rating <= 2:
+		return 1
+	case entry.Rating == 3:
+		return 2
+	default:
+		return 3
+	}
+}
+
+// RatingDistribution counts the ratings of a group of employees, Counts
+// holds the number of employees per rating from 1 to 5
+type RatingDistribution struct {
+	Key  string
+	Name string
+
+	Counts  []int
+	Unrated int
+	Average float64
+}
+
+// NineBoxCell holds the employees placed on one performance and
+// potential cell of the nine-box
+type NineBoxCell struct {
+	Performance int
+	Potential   int
+	Entries     []CalibrationEntry
+}
+
+type OpenCalibrationSessionInput struct {
+	Name           string
+	CycleID        uuid.UUID
+	ParticipantIDs []uuid.UUID
+}
+
+// OpenCalibrationSession opens a session for a cycle. HR calibrates the
+// whole organization, a manager calibrates their own reports, the
+// participants they invite see those reports and do not add their own
+func OpenCalibrationSession(gctx golly.Context, input OpenCalibrationSessionInput, metadata eventsource.Metadata) (CalibrationSession, error) {
+	var session CalibrationSession
+
+	ident := identity.FromContext(gctx)
+
+	var cycle Cycle
+
+	err := orm.DB(gctx).
+		Model(&Cycle{}).
+		Scopes(common.OrganizationIDScopeForContext(gctx)).
+		First(&cycle, "id = ?", input.CycleID).
+		Error
+
+	if err != nil {
+		return session, errors.WrapNotFound(err)
+	}
+
+	for _, participantID := range input.ParticipantIDs {
+		_, err := accounts.FindUserByID(gctx, participantID.String(), common.OrganizationIDScopeForContext(gctx))
+		if err != nil {
+			return session, errors.WrapUnprocessable(ErrorUnknownParticipant)
+		}
+	}
+
+	db := orm.DB(gctx).
+		Model(&employees.Employee{}).
+		Where("employees.organization_id = ? AND employees.terminated_at IS NULL", ident.OrganizationID)
+
+	if _, err := accounts.RequireHR(gctx); err != nil {
+		managerIDs := orm.DB(gctx).
+			Model(&employees.Employee{}).
+			Select("id").
+			Where("organization_id = ?", ident.OrganizationID).
+			Where("user_id = ?", ident.UID)
+
+		db = db.Where("employees.manager_id IN (?)", managerIDs)
+	}
+
+	var employeeIDs []uuid.UUID
+
+	if err := db.Pluck("employees.id", &employeeIDs).Error; err != nil {
+		return session, errors.WrapGeneric(err)
+	}
+
+	if len(employeeIDs) == 0 {
+		return session, errors.WrapForbidden(ErrorNotCalibrationManager)
+	}
+
+	err = eventsource.Call(gctx, &session.Aggregate, calibration.Open{
+		OrganizationID: ident.OrganizationID,
+		CycleID:        cycle.ID,
+		FacilitatorID:  ident.UID,
+		Name:           input.Name,
+		ParticipantIDs: input.ParticipantIDs,
+		EmployeeIDs:    employeeIDs,
+	}, metadata)
+
+	return session, err
+}
+
+// UpdateCalibrationSession runs a command on a session the current user
+// can see, the command checks they take part in it
+func UpdateCalibrationSession(gctx golly.Context, id uuid.UUID, cmd eventsource.Command, metadata eventsource.Metadata) (CalibrationSession, error) {
+	session, err := FindCalibrationSession(gctx, id)
+	if err != nil {
+		return session, err
+	}
+
+	err = eventsource.Call(gctx, &session.Aggregate, cmd, metadata)
+	return session, err
+}
+
+// AdjustCalibrationRating changes the rating of an employee of the
+// session recording the rating it had on the grid
+func AdjustCalibrationRating(gctx golly.Context, id, employeeID uuid.UUID, rating int, rationale string, metadata eventsource.Metadata) (CalibrationSession, error) {
+	session, err := FindCalibrationSession(gctx, id)
+	if err != nil {
+		return session, err
+	}
+
+	entries, err := CalibrationEntries(gctx, session)
+	if err != nil {
+		return session, err
+	}
+
+	var from int
+
+	if i := slices.IndexFunc(entries, func(e CalibrationEntry) bool { return e.Employee.ID == employeeID }); i >= 0 {
+		from = entries[i].Rating
+	}
+
+	err = eventsource.Call(gctx, &session.Aggregate, calibration.AdjustRating{
+		UserID:     identity.FromContext(gctx).UID,
+		EmployeeID: employeeID,
+		From:       from,
+		To:         rating,
+		Rationale:  rationale,
+	}, metadata)
+
+	return session, err
+}
+
+func calibrationSessionQuery(gctx golly.Context) *gorm.DB {
+	return orm.DB(gctx).
+		Model(&CalibrationSession{}).
+		Scopes(common.OrganizationIDScopeForContext(gctx))
+}
+
+// FindCalibrationSession finds a session the current user takes part in,
+// HR can see every session of the organization
+func FindCalibrationSession(gctx golly.Context, id uuid.UUID) (CalibrationSession, error) {
+	var session CalibrationSession
+
+	err := calibrationSessionQuery(gctx).
+		First(&session, "id = ?", id).
+		Error
+
+	if err != nil {
+		return session, errors.WrapNotFound(err)
+	}
+
+	if !session.IsParticipant(identity.FromContext(gctx).UID) {
+		if _, err := accounts.RequireHR(gctx); err != nil {
+			return CalibrationSession{}, errors.WrapNotFound(gorm.ErrRecordNotFound)
+		}
+	}
+
+	return session, nil
+}
+
+// FindCalibrationSessions lists the sessions the current user can see,
+// the most recent first
+func FindCalibrationSessions(gctx golly.Context) ([]CalibrationSession, error) {
+	var sessions []CalibrationSession
+
+	if err := calibrationSessionQuery(gctx).Order("created_at DESC").Find(&sessions).Error; err != nil {
+		return sessions, errors.WrapGeneric(err)
+	}
+
+	if _, err := accounts.RequireHR(gctx); err == nil {
+		return sessions, nil
+	}
+
+	uid := identity.FromContext(gctx).UID
+
+	return golly.Filter(sessions, func(s CalibrationSession) bool { return s.IsParticipant(uid) }), nil
+}
+
+type employeeFeedbackRating struct {
+	EmployeeID uuid.UUID
+	Average    float64
+	Total      int
+}
+
+// CalibrationEntries builds the grid of the session from the feedback
+// ratings submitted during its cycle, the finalized reviews of the cycle
+// and the adjustments made so far
+func CalibrationEntries(gctx golly.Context, session CalibrationSession) ([]CalibrationEntry, error) {
+	var cycle Cycle
+
+	err := orm.DB(gctx).
+		Model(&Cycle{}).
+		Where("organization_id = ?", session.OrganizationID).
+		First(&cycle, "id = ?", session.CycleID).
+		Error
+
+	if err != nil {
+		return nil, errors.WrapNotFound(err)
+	}
+
+	var emps []employees.Employee
+
+	err = orm.DB(gctx).
+		Model(&employees.Employee{}).
+		Preload("Team").
+		Preload("Role").
+		Where("employees.organization_id = ? AND employees.id IN ?", session.OrganizationID, []uuid.UUID(session.EmployeeIDs)).
+		Order("employees.name").
+		Find(&emps).
+		Error
+
+	if err != nil {
+		return nil, errors.WrapGeneric(err)
+	}
+
+	managers := map[uuid.UUID]employees.Employee{}
+
+	managerIDs := golly.Map(golly.Filter(emps, func(e employees.Employee) bool { return e.ManagerID != nil }),
+		func(e employees.Employee) uuid.UUID { return *e.ManagerID })
+
+	if len(managerIDs) > 0 {
+		found, err := employees.Service(gctx).FindEmployeesByIDS(gctx, managerIDs)
+		if err != nil {
+			return nil, errors.WrapGeneric(err)
+		}
+
+		for _, manager := range found {
+			managers[manager.ID] = manager
+		}
+	}
+
+	var feedbackRatings []employeeFeedbackRating
+
+	err = orm.DB(gctx).
+		Table("feedback_details").
+		Select("feedbacks.employee_id AS employee_id, AVG(feedback_details.rating) AS average, COUNT(*) AS total").
+		Joins("JOIN feedbacks ON feedbacks.id = feedback_details.feedback_id").
+		Where("feedbacks.organization_id = ? AND feedbacks.employee_id IN ?", session.OrganizationID, []uuid.UUID(session.EmployeeIDs)).
+		Where("feedbacks.submitted_at BETWEEN ? AND ?", cycle.StartAt, cycle.EndAt).
+		Where("feedback_details.rating > 0").
+		Group("feedbacks.employee_id").
+		Scan(&feedbackRatings).
+		Error
+
+	if err != nil {
+		return nil, errors.WrapGeneric(err)
+	}
+
+	var perfReviews []PerformanceReview
+
+	err = orm.DB(gctx).
+		Model(&PerformanceReview{}).
+		Where("organization_id = ? AND cycle_id = ? AND status = ?", session.OrganizationID, session.CycleID, review.StatusFinalized).
+		Order("finalized_at").
+		Find(&perfReviews).
+		Error
+
+	if err != nil {
+		return nil, errors.WrapGeneric(err)
+	}
+
+	entries := make([]CalibrationEntry, 0, len(emps))
+
+	for _, emp := range emps {
+		entry := CalibrationEntry{Employee: emp, Adjustments: session.Adjustments.For(emp.ID)}
+
+		if emp.ManagerID != nil {
+			if manager, ok := managers[*emp.ManagerID]; ok {
+				entry.Manager = &manager
+			}
+		}
+
+		if i := slices.IndexFunc(feedbackRatings, func(r employeeFeedbackRating) bool { return r.EmployeeID == emp.ID }); i >= 0 {
+			entry.FeedbackRating = feedbackRatings[i].Average
+			entry.FeedbackCount = feedbackRatings[i].Total
+		}
+
+		// the latest finalized review of the cycle wins
+		for _, perfReview := range perfReviews {
+			if perfReview.EmployeeID == emp.ID {
+				entry.ReviewRating = perfReview.Rating
+			}
+		}
+
+		switch adj, ok := session.Adjustments.Latest(calibration.AdjustmentRating, emp.ID); {
+		case ok:
+			entry.Rating = adj.To
+			entry.Calibrated = true
+		case entry.ReviewRating > 0:
+			entry.Rating = entry.ReviewRating
+		case entry.FeedbackCount > 0:
+			entry.Rating = min(max(int(math.Round(entry.FeedbackRating)), calibration.MinRating), calibration.MaxRating)
+		}
+
+		if adj, ok := session.Adjustments.Latest(calibration.AdjustmentPotential, emp.ID); ok {
+			entry.Potential = adj.To
+		}
+
+		entries = append(entries, entry)
+	}
+
+	return entries, nil
+}
+
+// CalibrationDistribution groups the entries by team, level or manager
+// and counts their ratings, groups are sorted by name or by level
+func CalibrationDistribution(entries []CalibrationEntry, groupBy string) []RatingDistribution {
+	groups := map[string]*RatingDistribution{}
+
+	for _, entry := range entries {
+		key, name := calibrationGroup(entry, groupBy)
+
+		group, ok := groups[key]
+		if !ok {
+			group = &RatingDistribution{Key: key, Name: name, Counts: make([]int, calibration.MaxRating)}
+			groups[key] = group
+		}
+
+		if entry.Rating == 0 {
+			group.Unrated++
+			continue
+		}
+
+		group.Counts[entry.Rating-1]++
+	}
+
+	ret := make([]RatingDistribution, 0, len(groups))
+
+	for _, group := range groups {
+		var rated, total int
+
+		for i, count := range group.Counts {
+			rated += count
+			total += count * (i + 1)
+		}
+
+		if rated > 0 {
+			group.Average = float64(total) / float64(rated)
+		}
+
+		ret = append(ret, *group)
+	}
+
+	slices.SortFunc(ret, func(a, b RatingDistribution) int {
+		if groupBy == CalibrationGroupLevel {
+			levelA, _ := strconv.Atoi(a.Key)
+			levelB, _ := strconv.Atoi(b.Key)
+			return cmp.Compare(levelA, levelB)
+		}
+		return cmp.Compare(a.Name, b.Name)
+	})
+
+	return ret
+}
+
+func calibrationGroup(entry CalibrationEntry, groupBy string) (string, string) {
+	switch groupBy {
+	case CalibrationGroupLevel:
+		if entry.Employee.Role.ID == uuid.Nil {
+			return "", "No level"
+		}
+		return fmt.Sprint(entry.Employee.Role.Level), fmt.Sprintf("Level %d", entry.Employee.Role.Level)
+
+	case CalibrationGroupManager:
+		if entry.Manager == nil {
+			return "", "No manager"
+		}
+		return entry.Manager.ID.String(), entry.Manager.Name
+
+	default:
+		if entry.Employee.Team.ID == uuid.Nil {
+			return "", "No team"
+		}
+		return entry.Employee.Team.ID.String(), entry.Employee.Team.Name
+	}
+}
+
+// NineBox places the rated entries with a potential on the nine cells of
+// the grid, from low performance and potential to high
+func NineBox(entries []CalibrationEntry) []NineBoxCell {
+	cells := []NineBoxCell{}
+
+	for potential := calibration.MinPotential; potential <= calibration.MaxPotential; potential++ {
+		for performance := 1; performance <= 3; performance++ {
+			cells = append(cells, NineBoxCell{
+				Performance: performance,
+				Potential:   potential,
+				Entries: golly.Filter(entries, func(e CalibrationEntry) bool {
+					return e.Performance() == performance && e.Potential == potential
+				}),
+			})
+		}
+	}
+
+	return cells
+}
@@ -0,0 +1,454 @@
+package reviews
+
+import (
+	"fmt"
+
+	"github.com/golly-go/golly"
+	"github.com/golly-go/plugins/gql"
+	"github.com/google/uuid"
+	"github.com/graphql-go/graphql"
+	"github.com/mitchrodrigues/talent-review-backend/app/domains/employees"
+	"github.com/mitchrodrigues/talent-review-backend/app/domains/reviews/calibration"
+	"github.com/mitchrodrigues/talent-review-backend/app/utils/helpers"
+	"github.com/mitchrodrigues/talent-review-backend/app/utils/identity"
+)
+
+var (
+	calibrationGroupType = graphql.NewEnum(graphql.EnumConfig{
+		Name: "CalibrationGroup",
+		Values: graphql.EnumValueConfigMap{
+			CalibrationGroupTeam:    {Value: CalibrationGroupTeam},
+			CalibrationGroupLevel:   {Value: CalibrationGroupLevel},
+			CalibrationGroupManager: {Value: CalibrationGroupManager},
+		},
+	})
+
+	calibrationAdjustmentType = graphql.NewObject(graphql.ObjectConfig{
+		Name: "CalibrationAdjustment",
+		Fields: graphql.Fields{
+			"kind": {
+				Type:        graphql.String,
+				Description: "RATING or POTENTIAL",
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					return p.Source.(calibration.Adjustment).Kind, nil
+				},
+			},
+			"employeeID": {
+				Type: graphql.String,
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					return p.Source.(calibration.Adjustment).EmployeeID, nil
+				},
+			},
+			"userID": {
+				Type: graphql.String,
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					return p.Source.(calibration.Adjustment).UserID, nil
+				},
+			},
+			"from": {
+				Type: graphql.Int,
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					return p.Source.(calibration.Adjustment).From, nil
+				},
+			},
+			"to": {
+				Type: graphql.Int,
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					return p.Source.(calibration.Adjustment).To, nil
+				},
+			},
+			"rationale": {
+				Type: graphql.String,
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					return p.Source.(calibration.Adjustment).Rationale, nil
+				},
+			},
+			"createdAt": {
+				Type: graphql.DateTime,
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					return p.Source.(calibration.Adjustment).CreatedAt, nil
+				},
+			},
+		},
+	})
+
+	calibrationEntryType = graphql.NewObject(graphql.ObjectConfig{
+		Name: "CalibrationEntry",
+		Fields: graphql.Fields{
+			"employee": {
+				Type: employees.EmployeeGQLType,
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					return p.Source.(CalibrationEntry).Employee, nil
+				},
+			},
+			"feedbackRating": {
+				Type:        graphql.Float,
+				Description: "Average rating of the feedback submitted during the cycle",
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					return p.Source.(CalibrationEntry).FeedbackRating, nil
+				},
+			},
+			"feedbackCount": {
+				Type: graphql.Int,
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					return p.Source.(CalibrationEntry).FeedbackCount, nil
+				},
+			},
+			"reviewRating": {
+				Type:        graphql.Int,
+				Description: "Rating of the finalized performance review of the cycle",
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					return p.Source.(CalibrationEntry).ReviewRating, nil
+				},
+			},
+			"rating": {
+				Type:        graphql.Int,
+				Description: "Calibrated rating, falls back to the review then the feedback rating",
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					return p.Source.(CalibrationEntry).Rating, nil
+				},
+			},
+			"calibrated": {
+				Type: graphql.Boolean,
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					return p.Source.(CalibrationEntry).Calibrated, nil
+				},
+			},
+			"performance": {
+				Type:        graphql.Int,
+				Description: "Performance band of the nine-box from 1 to 3",
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					return p.Source.(CalibrationEntry).Performance(), nil
+				},
+			},
+			"potential": {
+				Type:        graphql.Int,
+				Description: "Potential band of the nine-box from 1 to 3",
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					return p.Source.(CalibrationEntry).Potential, nil
+				},
+			},
+			"adjustments": {
+				Type: graphql.NewList(calibrationAdjustmentType),
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					return []calibration.Adjustment(p.Source.(CalibrationEntry).Adjustments), nil
+				},
+			},
+		},
+	})
+
+	ratingDistributionType = graphql.NewObject(graphql.ObjectConfig{
+		Name: "RatingDistribution",
+		Fields: graphql.Fields{
+			"key": {
+				Type: graphql.String,
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					return p.Source.(RatingDistribution).Key, nil
+				},
+			},
+			"name": {
+				Type: graphql.String,
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					return p.Source.(RatingDistribution).Name, nil
+				},
+			},
+			"counts": {
+				Type:        graphql.NewList(graphql.Int),
+				Description: "Number of employees per rating from 1 to 5",
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					return p.Source.(RatingDistribution).Counts, nil
+				},
+			},
+			"unrated": {
+				Type: graphql.Int,
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					return p.Source.(RatingDistribution).Unrated, nil
+				},
+			},
+			"average": {
+				Type: graphql.Float,
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					return p.Source.(RatingDistribution).Average, nil
+				},
+			},
+		},
+	})
+
+	nineBoxCellType = graphql.NewObject(graphql.ObjectConfig{
+		Name: "NineBoxCell",
+		Fields: graphql.Fields{
+			"performance": {
+				Type: graphql.Int,
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					return p.Source.(NineBoxCell).Performance, nil
+				},
+			},
+			"potential": {
+				Type: graphql.Int,
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					return p.Source.(NineBoxCell).Potential, nil
+				},
+			},
+			"entries": {
+				Type: graphql.NewList(calibrationEntryType),
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					return p.Source.(NineBoxCell).Entries, nil
+				},
+			},
+		},
+	})
+
+	calibrationSessionType = graphql.NewObject(graphql.ObjectConfig{
+		Name: "CalibrationSession",
+		Fields: graphql.Fields{
+			"id": {
+				Type: graphql.NewNonNull(graphql.String),
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					return p.Source.(CalibrationSession).ID, nil
+				},
+			},
+			"name": {
+				Type: graphql.String,
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					return p.Source.(CalibrationSession).Name, nil
+				},
+			},
+			"cycleID": {
+				Type: graphql.String,
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					return p.Source.(CalibrationSession).CycleID, nil
+				},
+			},
+			"facilitatorID": {
+				Type: graphql.String,
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					return p.Source.(CalibrationSession).FacilitatorID, nil
+				},
+			},
+			"participantIDs": {
+				Type: graphql.NewList(graphql.String),
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					return p.Source.(CalibrationSession).ParticipantIDs, nil
+				},
+			},
+			"status": {
+				Type: graphql.String,
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					return p.Source.(CalibrationSession).Status, nil
+				},
+			},
+			"entries": {
+				Type: graphql.NewList(calibrationEntryType),
+				Resolve: gql.NewHandler(gql.Options{
+					Handler: func(wctx golly.WebContext, params gql.Params) (interface{}, error) {
+						return calibrationEntries(wctx, params.Source.(CalibrationSession))
+					},
+				}),
+			},
+			"distribution": {
+				Type: graphql.NewList(ratingDistributionType),
+				Args: graphql.FieldConfigArgument{
+					"groupBy": {Type: graphql.NewNonNull(calibrationGroupType)},
+				},
+				Resolve: gql.NewHandler(gql.Options{
+					Handler: func(wctx golly.WebContext, params gql.Params) (interface{}, error) {
+						entries, err := calibrationEntries(wctx, params.Source.(CalibrationSession))
+						if err != nil {
+							return nil, err
+						}
+
+						return CalibrationDistribution(entries, params.Args["groupBy"].(string)), nil
+					},
+				}),
+			},
+			"nineBox": {
+				Type:        graphql.NewList(nineBoxCellType),
+				Description: "The nine cells of the performance and potential grid",
+				Resolve: gql.NewHandler(gql.Options{
+					Handler: func(wctx golly.WebContext, params gql.Params) (interface{}, error) {
+						entries, err := calibrationEntries(wctx, params.Source.(CalibrationSession))
+						if err != nil {
+							return nil, err
+						}
+
+						return NineBox(entries), nil
+					},
+				}),
+			},
+			"adjustments": {
+				Type: graphql.NewList(calibrationAdjustmentType),
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					return []calibration.Adjustment(p.Source.(CalibrationSession).Adjustments), nil
+				},
+			},
+			"closedAt": {
+				Type: graphql.DateTime,
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					return p.Source.(CalibrationSession).ClosedAt, nil
+				},
+			},
+			"createdAt": {
+				Type: graphql.DateTime,
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					return p.Source.(CalibrationSession).CreatedAt, nil
+				},
+			},
+		},
+	})
+
+	openCalibrationSessionInputType = graphql.NewInputObject(graphql.InputObjectConfig{
+		Name: "OpenCalibrationSessionInput",
+		Fields: graphql.InputObjectConfigFieldMap{
+			"name":           {Type: graphql.NewNonNull(graphql.String)},
+			"cycleID":        {Type: graphql.NewNonNull(graphql.String)},
+			"participantIDs": {Type: graphql.NewList(graphql.NewNonNull(graphql.String)), Description: "User ids calibrating with you"},
+		},
+	})
+
+	calibrationQueries = graphql.Fields{
+		"calibrationSessions": &graphql.Field{
+			Type:        graphql.NewList(calibrationSessionType),
+			Description: "Calibration sessions you take part in, all of them for HR",
+			Resolve: gql.NewHandler(gql.Options{
+				Handler: func(wctx golly.WebContext, params gql.Params) (interface{}, error) {
+					return FindCalibrationSessions(wctx.Context)
+				},
+			}),
+		},
+		"calibrationSession": &graphql.Field{
+			Type: calibrationSessionType,
+			Args: graphql.FieldConfigArgument{
+				"id": {Type: graphql.NewNonNull(graphql.String)},
+			},
+			Resolve: gql.NewHandler(gql.Options{
+				Handler: func(wctx golly.WebContext, params gql.Params) (interface{}, error) {
+					id, err := helpers.ExtractAndParseUUID(params.Args, "id")
+					if err != nil {
+						return nil, err
+					}
+
+					return FindCalibrationSession(wctx.Context, id)
+				},
+			}),
+		},
+	}
+
+	calibrationMutations = graphql.Fields{
+		"openCalibrationSession": &graphql.Field{
+			Type:        calibrationSessionType,
+			Description: "Open a calibration session for a cycle, HR or managers only",
+			Args: graphql.FieldConfigArgument{
+				"input": &graphql.ArgumentConfig{Type: graphql.NewNonNull(openCalibrationSessionInputType)},
+			},
+			Resolve: gql.NewHandler(gql.Options{
+				Handler: func(wctx golly.WebContext, params gql.Params) (interface{}, error) {
+					cycleID, err := helpers.ExtractAndParseUUID(params.Input, "cycleID")
+					if err != nil {
+						return nil, err
+					}
+
+					participants, _ := helpers.ExtractArg[[]interface{}](params.Input, "participantIDs")
+
+					participantIDs := []uuid.UUID{}
+					for _, id := range participants {
+						participantID, err := uuid.Parse(id.(string))
+						if err != nil {
+							return nil, err
+						}
+						participantIDs = append(participantIDs, participantID)
+					}
+
+					return OpenCalibrationSession(wctx.Context, OpenCalibrationSessionInput{
+						Name:           params.Input["name"].(string),
+						CycleID:        cycleID,
+						ParticipantIDs: participantIDs,
+					}, params.Metadata())
+				},
+			}),
+		},
+		"adjustCalibrationRating": &graphql.Field{
+			Type: calibrationSessionType,
+			Args: graphql.FieldConfigArgument{
+				"id":         &graphql.ArgumentConfig{Type: graphql.NewNonNull(graphql.String)},
+				"employeeID": &graphql.ArgumentConfig{Type: graphql.NewNonNull(graphql.String)},
+				"rating":     &graphql.ArgumentConfig{Type: graphql.NewNonNull(graphql.Int)},
+				"rationale":  &graphql.ArgumentConfig{Type: graphql.NewNonNull(graphql.String)},
+			},
+			Resolve: gql.NewHandler(gql.Options{
+				Handler: func(wctx golly.WebContext, params gql.Params) (interface{}, error) {
+					id, err := helpers.ExtractAndParseUUID(params.Args, "id")
+					if err != nil {
+						return nil, err
+					}
+
+					employeeID, err := helpers.ExtractAndParseUUID(params.Args, "employeeID")
+					if err != nil {
+						return nil, err
+					}
+
+					return AdjustCalibrationRating(wctx.Context, id, employeeID,
+						params.Args["rating"].(int),
+						params.Args["rationale"].(string),
+						params.Metadata())
+				},
+			}),
+		},
+		"setCalibrationPotential": &graphql.Field{
+			Type: calibrationSessionType,
+			Args: graphql.FieldConfigArgument{
+				"id":         &graphql.ArgumentConfig{Type: graphql.NewNonNull(graphql.String)},
+				"employeeID": &graphql.ArgumentConfig{Type: graphql.NewNonNull(graphql.String)},
+				"potential":  &graphql.ArgumentConfig{Type: graphql.NewNonNull(graphql.Int), Description: "1 (low) to 3 (high)"},
+				"rationale":  &graphql.ArgumentConfig{Type: graphql.NewNonNull(graphql.String)},
+			},
+			Resolve: gql.NewHandler(gql.Options{
+				Handler: func(wctx golly.WebContext, params gql.Params) (interface{}, error) {
+					id, err := helpers.ExtractAndParseUUID(params.Args, "id")
+					if err != nil {
+						return nil, err
+					}
+
+					employeeID, err := helpers.ExtractAndParseUUID(params.Args, "employeeID")
+					if err != nil {
+						return nil, err
+					}
+
+					return UpdateCalibrationSession(wctx.Context, id, calibration.SetPotential{
+						UserID:     identity.FromContext(wctx.Context).UID,
+						EmployeeID: employeeID,
+						Potential:  params.Args["potential"].(int),
+						Rationale:  params.Args["rationale"].(string),
+					}, params.Metadata())
+				},
+			}),
+		},
+		"closeCalibrationSession": &graphql.Field{
+			Type: calibrationSessionType,
+			Args: graphql.FieldConfigArgument{
+				"id": &graphql.ArgumentConfig{Type: graphql.NewNonNull(graphql.String)},
+			},
+			Resolve: gql.NewHandler(gql.Options{
+				Handler: func(wctx golly.WebContext, params gql.Params) (interface{}, error) {
+					id, err := helpers.ExtractAndParseUUID(params.Args, "id")
+					if err != nil {
+						return nil, err
+					}
+
+					return UpdateCalibrationSession(wctx.Context, id,
+						calibration.Close{UserID: identity.FromContext(wctx.Context).UID},
+						params.Metadata())
+				},
+			}),
+		},
+	}
+)
+
+// calibrationEntries builds the grid once per request and session version
+// as several fields of the session need it
+func calibrationEntries(wctx golly.WebContext, session CalibrationSession) ([]CalibrationEntry, error) {
+	key := fmt.Sprintf("calibration_entries:%s:%d", session.ID, session.Version)
+
+	return golly.LoadData(wctx.Context, key, func(gctx golly.Context) ([]CalibrationEntry, error) {
+		return CalibrationEntries(gctx, session)
+	})
+}
@@ -0,0 +1,192 @@
+package reviews
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/golly-go/golly"
+	"github.com/golly-go/plugins/eventsource"
+	"github.com/golly-go/plugins/orm"
+	"github.com/google/uuid"
+	"github.com/mitchrodrigues/talent-review-backend/app/domains/accounts"
+	"github.com/mitchrodrigues/talent-review-backend/app/domains/employees"
+	"github.com/mitchrodrigues/talent-review-backend/app/domains/employees/role"
+	"github.com/mitchrodrigues/talent-review-backend/app/domains/reviews/calibration"
+	"github.com/mitchrodrigues/talent-review-backend/app/domains/reviews/cycle"
+	"github.com/mitchrodrigues/talent-review-backend/app/domains/reviews/review"
+	"github.com/mitchrodrigues/talent-review-backend/app/utils/esbackend"
+	"github.com/mitchrodrigues/talent-review-backend/app/utils/identity"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCalibrationSession(t *testing.T) {
+	gctx := orm.CreateTestContext(golly.NewContext(context.TODO()),
+		CalibrationSession{},
+		PerformanceReview{},
+		Feedback{},
+		FeedbackDetails{},
+		Cycle{},
+		employees.Employee{},
+		employees.Team{},
+		employees.EmployeeRole{},
+		accounts.User{},
+		esbackend.Event{})
+
+	organizationID := uuid.New()
+	managerUserID := uuid.New()
+
+	h1 := Cycle{Aggregate: cycle.Aggregate{
+		OrganizationID: organizationID,
+		StartAt:        time.Now().AddDate(0, -6, 0),
+		EndAt:          time.Now().Add(time.Hour),
+	}}
+	orm.DB(gctx).Create(&h1)
+
+	senior := employees.EmployeeRole{Aggregate: role.Aggregate{OrganizationID: organizationID, Title: "Senior", Level: 4, Track: role.IC}}
+	orm.DB(gctx).Create(&senior)
+
+	manager := employees.NewTestEmployee(uuid.New(), organizationID, "manager@example.com", &managerUserID)
+	manager.Name = "Morgan"
+	orm.DB(gctx).Create(&manager)
+
+	report := employees.NewTestEmployee(uuid.New(), organizationID, "report@example.com", nil)
+	report.ManagerID = &manager.ID
+	report.EmployeeRoleID = senior.ID
+	orm.DB(gctx).Create(&report)
+
+	other := employees.NewTestEmployee(uuid.New(), organizationID, "other@example.com", nil)
+	orm.DB(gctx).Create(&other)
+
+	second := employees.NewTestEmployee(uuid.New(), organizationID, "second@example.com", nil)
+	second.ManagerID = &manager.ID
+	orm.DB(gctx).Create(&second)
+
+	rated := NewTestSubmittedFeedback(uuid.New(), organizationID, report.ID, "reviewer@example.com")
+	orm.DB(gctx).Create(&rated)
+
+	details := NewTestFeedbackDetails(rated, tiptapDocument("Runs great planning meetings."), "")
+	details.Rating = 4
+	orm.DB(gctx).Create(&details)
+
+	orm.DB(gctx).Create(&PerformanceReview{Aggregate: review.Aggregate{
+		OrganizationID: organizationID,
+		EmployeeID:     second.ID,
+		CycleID:        &h1.ID,
+		Status:         review.StatusFinalized,
+		Rating:         2,
+	}})
+
+	ctx := identity.ToContext(gctx, identity.Identity{
+		UID:            managerUserID,
+		OrganizationID: organizationID,
+		EmployeeID:     manager.ID,
+	})
+
+	session, err := OpenCalibrationSession(ctx, OpenCalibrationSessionInput{Name: "H1", CycleID: h1.ID}, eventsource.Metadata{})
+	assert.NoError(t, err)
+
+	// only the reports of the manager are calibrated
+	assert.ElementsMatch(t, []uuid.UUID{report.ID, second.ID}, []uuid.UUID(session.EmployeeIDs))
+
+	t.Run("grid starts from the review then the feedback rating", func(t *testing.T) {
+		entries, err := CalibrationEntries(ctx, session)
+		assert.NoError(t, err)
+
+		if assert.Len(t, entries, 2) {
+			byID := map[uuid.UUID]CalibrationEntry{entries[0].Employee.ID: entries[0], entries[1].Employee.ID: entries[1]}
+
+			assert.Equal(t, 4, byID[report.ID].Rating)
+			assert.Equal(t, 1, byID[report.ID].FeedbackCount)
+			assert.Equal(t, 2, byID[second.ID].Rating)
+			assert.Equal(t, "Morgan", byID[second.ID].Manager.Name)
+		}
+	})
+
+	t.Run("adjustments are recorded with the prior rating", func(t *testing.T) {
+		session, err := AdjustCalibrationRating(ctx, session.ID, report.ID, 3, "Scope was smaller than the peers", eventsource.Metadata{})
+		assert.NoError(t, err)
+
+		adjustment, ok := session.Adjustments.Latest(calibration.AdjustmentRating, report.ID)
+		assert.True(t, ok)
+		assert.Equal(t, 4, adjustment.From)
+		assert.Equal(t, 3, adjustment.To)
+
+		_, err = UpdateCalibrationSession(ctx, session.ID, calibration.SetPotential{
+			UserID:     identity.FromContext(ctx).UID,
+			EmployeeID: report.ID,
+			Potential:  3,
+			Rationale:  "Grows fast",
+		}, eventsource.Metadata{})
+		assert.NoError(t, err)
+
+		_, err = AdjustCalibrationRating(ctx, session.ID, other.ID, 3, "Not in the session", eventsource.Metadata{})
+		assert.ErrorContains(t, err, calibration.ErrorEmployeeNotIncluded.Error())
+	})
+
+	t.Run("distribution and nine-box", func(t *testing.T) {
+		session, err := FindCalibrationSession(ctx, session.ID)
+		assert.NoError(t, err)
+
+		entries, err := CalibrationEntries(ctx, session)
+		assert.NoError(t, err)
+
+		byLevel := CalibrationDistribution(entries, CalibrationGroupLevel)
+		if assert.Len(t, byLevel, 2) {
+			assert.Equal(t, "Level 4", byLevel[1].Name)
+			assert.Equal(t, []int{0, 0, 1, 0, 0}, byLevel[1].Counts)
+		}
+
+		byManager := CalibrationDistribution(entries, CalibrationGroupManager)
+		if assert.Len(t, byManager, 1) {
+			assert.Equal(t, "Morgan", byManager[0].Name)
+			assert.Equal(t, []int{0, 1, 1, 0, 0}, byManager[0].Counts)
+			assert.Equal(t, 2.5, byManager[0].Average)
+		}
+
+		cells := NineBox(entries)
+		assert.Len(t, cells, 9)
+
+		placed := 0
+		for _, cell := range cells {
+			placed += len(cell.Entries)
+
+			if cell.Performance == 2 && cell.Potential == 3 {
+				assert.Len(t, cell.Entries, 1)
+			}
+		}
+
+		// employees without a potential are not placed
+		assert.Equal(t, 1, placed)
+	})
+
+	t.Run("participants do not widen the session", func(t *testing.T) {
+		otherManagerUser := accounts.User{}
+		otherManagerUser.ID = uuid.New()
+		otherManagerUser.OrganizationID = organizationID
+		otherManagerUser.Email = "other-manager@example.com"
+		orm.DB(gctx).Create(&otherManagerUser)
+
+		otherManager := employees.NewTestEmployee(uuid.New(), organizationID, "other-manager@example.com", &otherManagerUser.ID)
+		orm.DB(gctx).Create(&otherManager)
+		orm.DB(gctx).Model(&employees.Employee{}).Where("id = ?", other.ID).Update("manager_id", otherManager.ID)
+
+		widened, err := OpenCalibrationSession(ctx, OpenCalibrationSessionInput{
+			Name:           "H1 with peers",
+			CycleID:        h1.ID,
+			ParticipantIDs: []uuid.UUID{otherManagerUser.ID},
+		}, eventsource.Metadata{})
+		assert.NoError(t, err)
+
+		assert.ElementsMatch(t, []uuid.UUID{report.ID, second.ID}, []uuid.UUID(widened.EmployeeIDs))
+		assert.NotContains(t, widened.EmployeeIDs, other.ID)
+	})
+
+	t.Run("only participants and hr see the session", func(t *testing.T) {
+		ident := identity.FromContext(ctx)
+		ident.UID = uuid.New()
+
+		_, err := FindCalibrationSession(identity.ToContext(ctx, ident), session.ID)
+		assert.Error(t, err)
+	})
+}
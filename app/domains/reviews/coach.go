@@ -0,0 +1,78 @@
+package reviews
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+	"unicode/utf8"
+
+	"github.com/golly-go/golly"
+	"github.com/golly-go/golly/errors"
+	"github.com/mitchrodrigues/talent-review-backend/app/domains/reviews/feedback"
+	"github.com/mitchrodrigues/talent-review-backend/app/domains/tara"
+	"github.com/mitchrodrigues/talent-review-backend/app/utils/identity"
+	"github.com/mitchrodrigues/talent-review-backend/app/utils/ratelimit"
+)
+
+const (
+	defaultCoachRequestsPerMinute = 6
+	defaultCoachBurst             = 3
+
+	// maxCoachDraftLength bounds what a public caller can send to the LLM
+	maxCoachDraftLength = 5000
+)
+
+var (
+	ErrorCoachRateLimited  = fmt.Errorf("too many writing suggestions requested, try again in a minute")
+	ErrorCoachSection      = fmt.Errorf("section must be strengths or opportunities")
+	ErrorCoachDraftLength  = fmt.Errorf("draft must be at most %d characters", maxCoachDraftLength)
+	ErrorFeedbackSubmitted = fmt.Errorf("feedback has already been submitted")
+
+	coachSections = []string{"strengths", "opportunities"}
+
+	// coachLimiter limits writing coach requests per feedback code, the
+	// mutation is public so the code is the only thing to key on
+	coachLimiter = ratelimit.PerMinute(defaultCoachRequestsPerMinute, defaultCoachBurst)
+)
+
+// ConfigureCoachLimiter sets the writing coach rate limit from config
+func ConfigureCoachLimiter(app golly.Application) {
+	coachLimiter = ratelimit.PerMinute(
+		cmp.Or(app.Config.GetInt("tara.coach.requests_per_minute"), defaultCoachRequestsPerMinute),
+		cmp.Or(app.Config.GetInt("tara.coach.burst"), defaultCoachBurst),
+	)
+}
+
+// CoachFeedbackDraft returns tara suggestions for a draft answer of the
+// feedback form, fb must have been loaded by its code
+func CoachFeedbackDraft(gctx golly.Context, fb Feedback, section, draft string) (tara.Coaching, error) {
+	if !slices.Contains(coachSections, section) {
+		return tara.Coaching{}, errors.WrapUnprocessable(ErrorCoachSection)
+	}
+
+	if utf8.RuneCountInString(draft) > maxCoachDraftLength {
+		return tara.Coaching{}, errors.WrapUnprocessable(ErrorCoachDraftLength)
+	}
+
+	switch {
+	case fb.SubmittedAt != nil:
+		return tara.Coaching{}, errors.WrapUnprocessable(ErrorFeedbackSubmitted)
+	case fb.DeclinedAt != nil:
+		return tara.Coaching{}, errors.WrapUnprocessable(feedback.ErrorDeclined)
+	}
+
+	if !coachLimiter.Allow(fb.Code) {
+		return tara.Coaching{}, errors.WrapUnprocessable(ErrorCoachRateLimited)
+	}
+
+	// Feedback is written publicly, make sure LLM usage is
+	// accounted against the organization that owns it
+	_, gctx = identity.SetOrganizationID(gctx, fb.OrganizationID)
+
+	return tara.CoachFeedback(gctx, tara.GenerateOptions{
+		Redactor: FeedbackRedactor(gctx, fb.OrganizationID),
+	}, tara.WritingCoachInput{
+		Section: section,
+		Draft:   draft,
+	})
+}
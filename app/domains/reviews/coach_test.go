@@ -0,0 +1,79 @@
+package reviews
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golly-go/plugins/orm"
+	"github.com/google/uuid"
+	"github.com/mitchrodrigues/talent-review-backend/app/domains/reviews/feedback"
+	"github.com/mitchrodrigues/talent-review-backend/app/domains/tara"
+	"github.com/mitchrodrigues/talent-review-backend/app/utils/openai"
+	"github.com/mitchrodrigues/talent-review-backend/app/utils/ratelimit"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCoachFeedbackDraft(t *testing.T) {
+	gctx := openai.UseProvider(createTestContext(), &openai.FixtureProvider{
+		Default: `{"suggestions":[{"kind":"missing_specifics","excerpt":"great teammate","suggestion":"Add an example."}]}`,
+	})
+
+	limiter := coachLimiter
+	defer func() { coachLimiter = limiter }()
+
+	coachLimiter = ratelimit.PerMinute(1, 1)
+
+	fb := Feedback{Aggregate: feedback.Aggregate{
+		ModelUUID:      orm.ModelUUID{ID: uuid.New()},
+		Code:           "coach-code",
+		OrganizationID: uuid.New(),
+	}}
+
+	submittedAt := time.Now()
+	submitted := fb
+	submitted.SubmittedAt = &submittedAt
+
+	declinedAt := time.Now()
+	declined := fb
+	declined.DeclinedAt = &declinedAt
+
+	t.Run("invalid section", func(t *testing.T) {
+		_, err := CoachFeedbackDraft(gctx, fb, "additional", "great teammate")
+		assert.ErrorContains(t, err, ErrorCoachSection.Error())
+	})
+
+	t.Run("draft too long", func(t *testing.T) {
+		_, err := CoachFeedbackDraft(gctx, fb, "strengths", strings.Repeat("a", maxCoachDraftLength+1))
+		assert.ErrorContains(t, err, ErrorCoachDraftLength.Error())
+	})
+
+	t.Run("submitted feedback", func(t *testing.T) {
+		_, err := CoachFeedbackDraft(gctx, submitted, "strengths", "great teammate")
+		assert.ErrorContains(t, err, ErrorFeedbackSubmitted.Error())
+	})
+
+	t.Run("declined feedback", func(t *testing.T) {
+		_, err := CoachFeedbackDraft(gctx, declined, "strengths", "great teammate")
+		assert.ErrorContains(t, err, feedback.ErrorDeclined.Error())
+	})
+
+	t.Run("suggestions", func(t *testing.T) {
+		coaching, err := CoachFeedbackDraft(gctx, fb, "strengths", "great teammate")
+		assert.NoError(t, err)
+		assert.Equal(t, []tara.WritingSuggestion{
+			{Kind: tara.SuggestionMissingSpecifics, Excerpt: "great teammate", Suggestion: "Add an example."},
+		}, coaching.Suggestions)
+	})
+
+	t.Run("rate limited per code", func(t *testing.T) {
+		_, err := CoachFeedbackDraft(gctx, fb, "strengths", "great teammate")
+		assert.ErrorContains(t, err, ErrorCoachRateLimited.Error())
+
+		other := fb
+		other.Code = "other-code"
+
+		_, err = CoachFeedbackDraft(gctx, other, "strengths", "great teammate")
+		assert.NoError(t, err)
+	})
+}
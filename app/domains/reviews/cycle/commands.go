@@ -4,10 +4,12 @@ import (
 	"time"
 
 	"github.com/golly-go/golly"
+	"github.com/golly-go/golly/errors"
 	"github.com/golly-go/plugins/eventsource"
 	"github.com/golly-go/plugins/orm"
 	"github.com/google/uuid"
 	"github.com/mitchrodrigues/talent-review-backend/app/domains/common"
+	"github.com/mitchrodrigues/talent-review-backend/app/domains/reviews/feedback"
 	"github.com/mitchrodrigues/talent-review-backend/app/utils/identity"
 )
 
@@ -48,3 +50,21 @@ func (cmd FindOrCreateCycle) Perform(gctx golly.Context, aggregate eventsource.A
 	})
 	return nil
 }
+
+// SetFeedbackVisibility changes the visibility of the feedback requested
+// from now on during the cycle, feedback already requested keeps its own
+type SetFeedbackVisibility struct {
+	Visibility string
+}
+
+func (cmd SetFeedbackVisibility) Validate(gctx golly.Context, aggregate eventsource.Aggregate) error {
+	if !feedback.ValidVisibility(cmd.Visibility) {
+		return errors.WrapUnprocessable(feedback.ErrorInvalidVisibility)
+	}
+	return nil
+}
+
+func (cmd SetFeedbackVisibility) Perform(gctx golly.Context, aggregate eventsource.Aggregate) error {
+	eventsource.Apply(gctx, aggregate, FeedbackVisibilitySet{Visibility: cmd.Visibility})
+	return nil
+}
@@ -8,6 +8,7 @@ import (
 	"github.com/golly-go/golly"
 	"github.com/golly-go/plugins/orm"
 	"github.com/google/uuid"
+	"github.com/mitchrodrigues/talent-review-backend/app/domains/reviews/feedback"
 	"github.com/mitchrodrigues/talent-review-backend/app/utils/identity"
 	"github.com/stretchr/testify/assert"
 )
@@ -87,3 +88,16 @@ func TestFindOrCreateCycle_Perform(t *testing.T) {
 		})
 	}
 }
+
+func TestSetFeedbackVisibility(t *testing.T) {
+	gctx := golly.NewContext(context.TODO())
+	aggregate := &Aggregate{}
+
+	assert.Error(t, SetFeedbackVisibility{Visibility: "SECRET"}.Validate(gctx, aggregate))
+
+	cmd := SetFeedbackVisibility{Visibility: feedback.VisibilityAnonymous}
+	assert.NoError(t, cmd.Validate(gctx, aggregate))
+	assert.NoError(t, cmd.Perform(gctx, aggregate))
+
+	assert.Equal(t, feedback.VisibilityAnonymous, aggregate.FeedbackVisibility)
+}
@@ -20,6 +20,10 @@ type Aggregate struct {
 
 	StartAt time.Time
 	EndAt   time.Time
+
+	// FeedbackVisibility is the visibility of the feedback requested
+	// during the cycle, empty when feedback is attributed
+	FeedbackVisibility string
 }
 
 func (*Aggregate) Topic() string                             { return "" }
@@ -41,6 +45,9 @@ func (cycle *Aggregate) Apply(ctx golly.Context, evt eventsource.Event) {
 		cycle.CreatedAt = evt.CreatedAt
 		cycle.UpdatedAt = evt.CreatedAt
 
+	case FeedbackVisibilitySet:
+		cycle.FeedbackVisibility = event.Visibility
+		cycle.UpdatedAt = evt.CreatedAt
 	}
 }
 
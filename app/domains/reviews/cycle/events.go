@@ -15,3 +15,7 @@ type CycleCreated struct {
 	StartAt time.Time
 	EndAt   time.Time
 }
+
+type FeedbackVisibilitySet struct {
+	Visibility string
+}
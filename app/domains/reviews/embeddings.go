@@ -0,0 +1,261 @@
+package reviews
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/golly-go/golly"
+	"github.com/golly-go/golly/errors"
+	"github.com/golly-go/plugins/eventsource"
+	"github.com/golly-go/plugins/orm"
+	"github.com/google/uuid"
+	"github.com/mitchrodrigues/talent-review-backend/app/domains/common"
+	"github.com/mitchrodrigues/talent-review-backend/app/domains/reviews/feedback"
+	"github.com/mitchrodrigues/talent-review-backend/app/domains/tara"
+	"github.com/mitchrodrigues/talent-review-backend/app/utils/identity"
+	"github.com/mitchrodrigues/talent-review-backend/app/utils/openai"
+	"github.com/mitchrodrigues/talent-review-backend/app/utils/wsyiwig"
+	"gorm.io/gorm"
+)
+
+const (
+	// embeddingChunkSize is the max characters embedded per row, longer
+	// sections are split on sentence boundaries
+	embeddingChunkSize = 1000
+
+	defaultSearchLimit = 10
+	maxSearchLimit     = 50
+)
+
+var (
+	ErrorSearchQueryRequired = fmt.Errorf("search query is required")
+)
+
+// Vector is stored as a pgvector on postgres, the text form "[1,2,3]" is
+// also valid JSON so sqlite stores the same value as text
+type Vector []float64
+
+func (v Vector) Value() (driver.Value, error) {
+	if v == nil {
+		return nil, nil
+	}
+
+	b, err := json.Marshal([]float64(v))
+	return string(b), err
+}
+
+func (v *Vector) Scan(value interface{}) error {
+	switch val := value.(type) {
+	case nil:
+		*v = nil
+		return nil
+	case string:
+		return json.Unmarshal([]byte(val), v)
+	case []byte:
+		return json.Unmarshal(val, v)
+	}
+
+	return fmt.Errorf("cannot scan %T into Vector", value)
+}
+
+type FeedbackEmbedding struct {
+	orm.ModelUUID
+
+	OrganizationID uuid.UUID
+	FeedbackID     uuid.UUID
+	EmployeeID     uuid.UUID
+
+	Section string
+	Content string
+
+	Model     string
+	Embedding Vector `gorm:"type:vector"`
+}
+
+func (FeedbackEmbedding) TableName() string { return "feedback_embeddings" }
+
+type FeedbackSearchResult struct {
+	FeedbackEmbedding
+
+	Score float64
+}
+
+func EmbedFeedbackSubscription(gctx golly.Context, agg eventsource.Aggregate, evt eventsource.Event) error {
+	switch evt.Data.(type) {
+	case feedback.Submitted:
+		fb := *agg.(*feedback.Aggregate)
+
+		go func(gctx golly.Context, fb feedback.Aggregate) {
+			if err := EmbedFeedback(gctx, &fb); err != nil {
+				gctx.Logger().Warnf("cannot embed feedback %s %v", fb.ID.String(), err)
+			}
+		}(gctx, fb)
+	}
+	return nil
+}
+
+// EmbedFeedback replaces the stored embeddings of the feedback with one
+// row per chunk of each written section
+func EmbedFeedback(gctx golly.Context, fb *feedback.Aggregate) error {
+	// Feedback is submitted publicly, make sure LLM usage is
+	// accounted against the organization that owns it
+	_, gctx = identity.SetOrganizationID(gctx, fb.OrganizationID)
+
+	details, err := FeedbackService(gctx).FindDetailsByFeedbackID_Unsafe(gctx, fb.ID)
+	if err != nil {
+		return err
+	}
+
+	opts := tara.GenerateOptions{Redactor: FeedbackRedactor(gctx, fb.OrganizationID)}
+
+	var records []FeedbackEmbedding
+	for _, section := range []struct{ name, content string }{
+		{"strengths", details.Strengths},
+		{"opportunities", details.Opportunities},
+		{"additional", details.Additional},
+	} {
+		text, _ := wsyiwig.ExtractTextFromJSON(section.content)
+
+		for _, chunk := range chunkText(text, embeddingChunkSize) {
+			embedding, err := tara.Embed(gctx, opts, chunk)
+			if err != nil {
+				return err
+			}
+
+			records = append(records, FeedbackEmbedding{
+				OrganizationID: fb.OrganizationID,
+				FeedbackID:     fb.ID,
+				EmployeeID:     fb.EmployeeID,
+				Section:        section.name,
+				Content:        chunk,
+				Model:          embedding.Model,
+				Embedding:      embedding.Vector,
+			})
+		}
+	}
+
+	db := orm.DB(gctx)
+
+	if err := db.Unscoped().Where("feedback_id = ?", fb.ID).Delete(&FeedbackEmbedding{}).Error; err != nil {
+		return errors.WrapGeneric(err)
+	}
+
+	if len(records) == 0 {
+		return nil
+	}
+
+	return errors.WrapGeneric(db.Create(&records).Error)
+}
+
+// SearchFeedback ranks the feedback snippets the current user is
+// permitted to see by similarity to the query, scopes narrow the search
+func SearchFeedback(gctx golly.Context, query string, limit int, scopes ...func(*gorm.DB) *gorm.DB) ([]FeedbackSearchResult, error) {
+	query = strings.TrimSpace(query)
+	if query == "" {
+		return nil, errors.WrapUnprocessable(ErrorSearchQueryRequired)
+	}
+
+	if limit <= 0 {
+		limit = defaultSearchLimit
+	}
+	limit = min(limit, maxSearchLimit)
+
+	ident := identity.FromContext(gctx)
+
+	embedding, err := tara.Embed(gctx, tara.GenerateOptions{
+		Redactor: FeedbackRedactor(gctx, ident.OrganizationID),
+	}, query)
+
+	if err != nil {
+		return nil, err
+	}
+
+	db := orm.DB(gctx).
+		Model(&FeedbackEmbedding{}).
+		Scopes(
+			common.OrganizationIDScopeForContext(gctx, "feedback_embeddings"),
+			common.JoinUserEmployeeRecord(gctx)).
+		Joins("JOIN feedbacks ON feedbacks.id = feedback_embeddings.feedback_id").
+		Joins("JOIN employees employee ON employee.id = feedbacks.employee_id").
+		Where("user_employee_record.id = employee.manager_id OR feedbacks.email = user_employee_record.email").
+		Scopes(AggregatedContentScope(gctx)).
+		Where("feedback_embeddings.model = ?", embedding.Model).
+		Scopes(scopes...)
+
+	var results []FeedbackSearchResult
+
+	// pgvector ranks in the database, everywhere else (sqlite) the
+	// candidates are loaded and ranked in memory
+	if db.Dialector.Name() == "postgres" {
+		err := db.
+			Select("feedback_embeddings.*, 1 - (feedback_embeddings.embedding <=> ?) AS score", Vector(embedding.Vector)).
+			Order("score DESC").
+			Limit(limit).
+			Find(&results).
+			Error
+
+		return results, errors.WrapGeneric(err)
+	}
+
+	if err := db.Select("feedback_embeddings.*").Find(&results).Error; err != nil {
+		return nil, errors.WrapGeneric(err)
+	}
+
+	for pos := range results {
+		results[pos].Score = openai.CosineSimilarity(embedding.Vector, results[pos].Embedding)
+	}
+
+	sort.SliceStable(results, func(i, j int) bool {
+		return results[i].Score > results[j].Score
+	})
+
+	return results[:min(limit, len(results))], nil
+}
+
+// chunkText splits text on sentence boundaries into chunks of at most
+// size characters, a single sentence longer than size is kept whole
+func chunkText(text string, size int) []string {
+	var chunks []string
+	var current strings.Builder
+
+	for _, sentence := range splitSentences(text) {
+		if current.Len() > 0 && current.Len()+len(sentence)+1 > size {
+			chunks = append(chunks, current.String())
+			current.Reset()
+		}
+
+		if current.Len() > 0 {
+			current.WriteString(" ")
+		}
+		current.WriteString(sentence)
+	}
+
+	if current.Len() > 0 {
+		chunks = append(chunks, current.String())
+	}
+
+	return chunks
+}
+
+func splitSentences(text string) []string {
+	var sentences []string
+	var current []string
+
+	for _, word := range strings.Fields(text) {
+		current = append(current, word)
+
+		if strings.ContainsAny(word[len(word)-1:], ".!?") {
+			sentences = append(sentences, strings.Join(current, " "))
+			current = nil
+		}
+	}
+
+	if len(current) > 0 {
+		sentences = append(sentences, strings.Join(current, " "))
+	}
+
+	return sentences
+}
@@ -0,0 +1,178 @@
+package reviews
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/golly-go/golly"
+	"github.com/golly-go/plugins/orm"
+	"github.com/google/uuid"
+	"github.com/mitchrodrigues/talent-review-backend/app/domains/accounts"
+	"github.com/mitchrodrigues/talent-review-backend/app/domains/employees"
+	"github.com/mitchrodrigues/talent-review-backend/app/domains/reviews/feedback"
+	"github.com/mitchrodrigues/talent-review-backend/app/utils/identity"
+	"github.com/mitchrodrigues/talent-review-backend/app/utils/openai"
+	"github.com/mitchrodrigues/talent-review-backend/app/utils/wsyiwig"
+	"github.com/stretchr/testify/assert"
+)
+
+func tiptapDocument(text string) string {
+	b, _ := json.Marshal(wsyiwig.Node{
+		Type: "doc",
+		Content: []wsyiwig.Node{{
+			Type:    "paragraph",
+			Content: []wsyiwig.Node{{Type: "text", Text: text}},
+		}},
+	})
+	return string(b)
+}
+
+func TestChunkText(t *testing.T) {
+	tests := []struct {
+		name     string
+		text     string
+		size     int
+		expected []string
+	}{
+		{
+			name:     "empty",
+			text:     "  ",
+			size:     100,
+			expected: nil,
+		},
+		{
+			name:     "fits in one chunk",
+			text:     "Great work.  Keeps the team calm!",
+			size:     100,
+			expected: []string{"Great work. Keeps the team calm!"},
+		},
+		{
+			name:     "splits on sentences",
+			text:     "First sentence here. Second sentence here. Third one",
+			size:     45,
+			expected: []string{"First sentence here. Second sentence here.", "Third one"},
+		},
+		{
+			name:     "long sentence kept whole",
+			text:     "This sentence is longer than the size. Short.",
+			size:     10,
+			expected: []string{"This sentence is longer than the size.", "Short."},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert.Equal(t, test.expected, chunkText(test.text, test.size))
+		})
+	}
+}
+
+func TestSearchFeedback(t *testing.T) {
+	gctx := orm.CreateTestContext(golly.NewContext(context.TODO()),
+		Feedback{},
+		FeedbackDetails{},
+		FeedbackEmbedding{},
+		accounts.Organization{},
+		employees.Employee{})
+
+	gctx = openai.UseProvider(gctx, openai.NewFixtureProvider(nil))
+
+	organizationID := uuid.New()
+	managerUserID := uuid.New()
+
+	manager := employees.NewTestEmployee(uuid.New(), organizationID, "manager@example.com", &managerUserID)
+	orm.DB(gctx).Create(&manager)
+
+	report := employees.NewTestEmployee(uuid.New(), organizationID, "report@example.com", nil)
+	report.ManagerID = &manager.ID
+	orm.DB(gctx).Create(&report)
+
+	other := employees.NewTestEmployee(uuid.New(), organizationID, "other@example.com", nil)
+	orm.DB(gctx).Create(&other)
+
+	createFeedback := func(employeeID uuid.UUID, orgID uuid.UUID, strengths, opportunities string) Feedback {
+		now := time.Now()
+
+		fb := Feedback{Aggregate: feedback.Aggregate{
+			ModelUUID:       orm.ModelUUID{ID: uuid.New(), CreatedAt: now},
+			Code:            uuid.NewString(),
+			Email:           "reviewer@example.com",
+			EmployeeID:      employeeID,
+			OrganizationID:  orgID,
+			CollectionEndAt: now.Add(24 * time.Hour),
+			SubmittedAt:     &now,
+		}}
+		orm.DB(gctx).Create(&fb)
+
+		orm.DB(gctx).Create(&FeedbackDetails{FeedbackDetails: feedback.FeedbackDetails{
+			FeedbackID:     fb.ID,
+			EmployeeID:     employeeID,
+			OrganizationID: orgID,
+			Strengths:      tiptapDocument(strengths),
+			Opportunities:  tiptapDocument(opportunities),
+			Additional:     tiptapDocument(""),
+		}})
+
+		assert.NoError(t, EmbedFeedback(gctx, &fb.Aggregate))
+		return fb
+	}
+
+	visible := createFeedback(report.ID, organizationID, "Runs great planning meetings.", "Could delegate more often.")
+	hidden := createFeedback(other.ID, organizationID, "Runs great planning meetings.", "Writes clear docs.")
+	otherOrg := createFeedback(report.ID, uuid.New(), "Runs great planning meetings.", "Ships fast.")
+
+	var count int64
+	orm.DB(gctx).Model(&FeedbackEmbedding{}).Where("feedback_id = ?", visible.ID).Count(&count)
+	assert.Equal(t, int64(2), count)
+
+	// Re-embedding replaces the stored rows
+	assert.NoError(t, EmbedFeedback(gctx, &visible.Aggregate))
+	orm.DB(gctx).Model(&FeedbackEmbedding{}).Unscoped().Where("feedback_id = ?", visible.ID).Count(&count)
+	assert.Equal(t, int64(2), count)
+
+	ctx := identity.ToContext(gctx, identity.Identity{
+		UID:            managerUserID,
+		OrganizationID: organizationID,
+		EmployeeID:     manager.ID,
+	})
+
+	t.Run("ranks visible feedback by similarity", func(t *testing.T) {
+		results, err := SearchFeedback(ctx, "Could delegate more often.", 0)
+		assert.NoError(t, err)
+
+		if assert.Len(t, results, 2) {
+			assert.Equal(t, visible.ID, results[0].FeedbackID)
+			assert.Equal(t, "opportunities", results[0].Section)
+			assert.InDelta(t, 1.0, results[0].Score, 0.0001)
+			assert.Greater(t, results[0].Score, results[1].Score)
+		}
+
+		for _, result := range results {
+			assert.NotEqual(t, hidden.ID, result.FeedbackID)
+			assert.NotEqual(t, otherOrg.ID, result.FeedbackID)
+		}
+	})
+
+	t.Run("limit", func(t *testing.T) {
+		results, err := SearchFeedback(ctx, "planning", 1)
+		assert.NoError(t, err)
+		assert.Len(t, results, 1)
+	})
+
+	t.Run("query required", func(t *testing.T) {
+		_, err := SearchFeedback(ctx, "   ", 0)
+		assert.ErrorContains(t, err, ErrorSearchQueryRequired.Error())
+	})
+}
+
+func TestVector_RoundTrip(t *testing.T) {
+	value, err := Vector{0.5, -1, 2}.Value()
+	assert.NoError(t, err)
+	assert.Equal(t, "[0.5,-1,2]", value)
+
+	var v Vector
+	assert.NoError(t, v.Scan(value))
+	assert.Equal(t, Vector{0.5, -1, 2}, v)
+}
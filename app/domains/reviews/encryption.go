@@ -0,0 +1,122 @@
+package reviews
+
+import (
+	"github.com/golly-go/golly"
+	"github.com/golly-go/plugins/orm"
+	"github.com/google/uuid"
+	"github.com/mitchrodrigues/talent-review-backend/app/domains/tara"
+	"github.com/mitchrodrigues/talent-review-backend/app/utils/crypto"
+	"gorm.io/gorm"
+)
+
+const reencryptBatchSize = 100
+
+type encryptedModel interface {
+	EncryptedColumns() map[string]*string
+}
+
+// encryptedDocument is a model with encrypted text nested in jsonb
+// columns, EncryptedText points at the text and EncryptedDocuments are
+// the columns holding it
+type encryptedDocument interface {
+	EncryptedText() []*string
+	EncryptedDocuments() map[string]interface{}
+}
+
+// ReencryptFeedback re-encrypts the feedback content of an organization
+// and the copies derived from it (embeddings, analyses, performance
+// reviews and tara conversations) with its active data key, run after
+// rotating it. Rows still in plaintext are encrypted too
+func ReencryptFeedback(gctx golly.Context, organizationID uuid.UUID) (int, error) {
+	total := 0
+
+	for _, fn := range []func(golly.Context, uuid.UUID) (int, error){
+		reencrypt[FeedbackDetails],
+		reencrypt[FeedbackSummary],
+		reencrypt[FeedbackAnalysis],
+		reencrypt[FeedbackEmbedding],
+		reencrypt[PerformanceReview],
+		reencrypt[tara.Conversation],
+	} {
+		count, err := fn(gctx, organizationID)
+		total += count
+
+		if err != nil {
+			return total, err
+		}
+	}
+
+	return total, nil
+}
+
+func reencrypt[T any](gctx golly.Context, organizationID uuid.UUID) (int, error) {
+	var rows []T
+
+	count := 0
+
+	encrypt := func(field *string) error {
+		value, err := crypto.Encrypt(gctx.Context(), orm.NewDB(gctx), organizationID, *field)
+		*field = value
+		return err
+	}
+
+	err := orm.NewDB(gctx).
+		Model(new(T)).
+		Where("organization_id = ?", organizationID).
+		FindInBatches(&rows, reencryptBatchSize, func(tx *gorm.DB, batch int) error {
+			for i := range rows {
+				row := interface{}(&rows[i])
+
+				updates := map[string]interface{}{}
+
+				if model, ok := row.(encryptedModel); ok {
+					for column, field := range model.EncryptedColumns() {
+						if err := encrypt(field); err != nil {
+							return err
+						}
+						updates[column] = *field
+					}
+				}
+
+				if document, ok := row.(encryptedDocument); ok {
+					for _, field := range document.EncryptedText() {
+						if err := encrypt(field); err != nil {
+							return err
+						}
+					}
+
+					for column, value := range document.EncryptedDocuments() {
+						updates[column] = value
+					}
+				}
+
+				// UpdateColumns skips the hooks and keeps updated_at
+				if err := orm.NewDB(gctx).Model(row).UpdateColumns(updates).Error; err != nil {
+					return err
+				}
+				count++
+			}
+			return nil
+		}).
+		Error
+
+	return count, err
+}
+
+// The content of embeddings is the feedback text split in chunks
+
+func (embedding *FeedbackEmbedding) EncryptedColumns() map[string]*string {
+	return map[string]*string{"content": &embedding.Content}
+}
+
+func (embedding *FeedbackEmbedding) BeforeSave(tx *gorm.DB) error {
+	return crypto.EncryptFields(tx, embedding.OrganizationID, crypto.Fields(embedding.EncryptedColumns())...)
+}
+
+func (embedding *FeedbackEmbedding) AfterSave(tx *gorm.DB) error {
+	return crypto.DecryptFields(tx, embedding.OrganizationID, crypto.Fields(embedding.EncryptedColumns())...)
+}
+
+func (embedding *FeedbackEmbedding) AfterFind(tx *gorm.DB) error {
+	return crypto.DecryptFields(tx, embedding.OrganizationID, crypto.Fields(embedding.EncryptedColumns())...)
+}
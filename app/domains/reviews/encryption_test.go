@@ -0,0 +1,244 @@
+package reviews
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golly-go/golly"
+	"github.com/golly-go/plugins/eventsource"
+	"github.com/golly-go/plugins/orm"
+	"github.com/google/uuid"
+	"github.com/mitchrodrigues/talent-review-backend/app/domains/reviews/feedback"
+	"github.com/mitchrodrigues/talent-review-backend/app/domains/reviews/review"
+	"github.com/mitchrodrigues/talent-review-backend/app/domains/tara"
+	"github.com/mitchrodrigues/talent-review-backend/app/domains/tara/conversation"
+	"github.com/mitchrodrigues/talent-review-backend/app/utils/crypto"
+	"github.com/mitchrodrigues/talent-review-backend/app/utils/esbackend"
+	"github.com/mitchrodrigues/talent-review-backend/app/utils/identity"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFeedbackEncryption(t *testing.T) {
+	defer crypto.UseProvider(crypto.NewTestKeyProvider())()
+
+	gctx := orm.CreateTestContext(golly.NewContext(context.TODO()),
+		Feedback{}, FeedbackDetails{}, FeedbackSummary{}, FeedbackAnalysis{}, FeedbackEmbedding{},
+		PerformanceReview{}, tara.Conversation{}, crypto.DataKey{}, esbackend.Event{})
+
+	organizationID := uuid.New()
+	gctx = identity.ToContext(gctx, identity.Identity{UID: uuid.New(), OrganizationID: organizationID})
+
+	fb := Feedback{Aggregate: feedback.Aggregate{
+		ModelUUID:       orm.ModelUUID{ID: uuid.New()},
+		Code:            uuid.NewString(),
+		Email:           "peer@example.com",
+		EmployeeID:      uuid.New(),
+		OrganizationID:  organizationID,
+		CollectionEndAt: time.Now(),
+	}}
+	orm.DB(gctx).Create(&fb)
+
+	err := eventsource.Call(gctx, &fb.Aggregate, feedback.CreateOrUpdateDetails{
+		Strength:      "Runs great planning meetings.",
+		Opportunities: "Could delegate more often.",
+	}, eventsource.Metadata{})
+	assert.NoError(t, err)
+
+	err = eventsource.Call(gctx, &fb.Aggregate, feedback.CreateSummary{
+		Summary:     "Strong planner.",
+		ActionItems: []string{"Delegate the weekly sync"},
+	}, eventsource.Metadata{})
+	assert.NoError(t, err)
+
+	// the aggregate keeps plaintext in memory once saved
+	assert.Equal(t, "Runs great planning meetings.", fb.Details.Strengths)
+	assert.Equal(t, "Strong planner.", fb.Summary.Summary)
+
+	stored := func(table, column string) string {
+		var values []string
+		orm.NewDB(gctx).Table(table).Where("feedback_id = ?", fb.ID).Pluck(column, &values)
+		if len(values) == 0 {
+			return ""
+		}
+		return values[0]
+	}
+
+	t.Run("content is encrypted at rest", func(t *testing.T) {
+		for table, columns := range map[string][]string{
+			"feedback_details":   {"strengths", "opportunities"},
+			"feedback_summaries": {"summary", "action_items"},
+		} {
+			for _, column := range columns {
+				assert.True(t, crypto.IsEncrypted(stored(table, column)), "%s.%s", table, column)
+			}
+		}
+	})
+
+	t.Run("content is decrypted when loaded", func(t *testing.T) {
+		details, err := FeedbackService(gctx).FindDetailsByFeedbackID_Unsafe(gctx, fb.ID)
+		assert.NoError(t, err)
+		assert.Equal(t, "Runs great planning meetings.", details.Strengths)
+		assert.Equal(t, "Could delegate more often.", details.Opportunities)
+
+		var loaded Feedback
+		orm.NewDB(gctx).Preload("Summary").First(&loaded, "id = ?", fb.ID)
+		assert.Equal(t, []string{"Delegate the weekly sync"}, summaryActionItems(FeedbackSummary{loaded.Summary}))
+	})
+
+	t.Run("rotation re-encrypts with the new data key", func(t *testing.T) {
+		var before FeedbackDetails
+		orm.NewDB(gctx).First(&before, "feedback_id = ?", fb.ID)
+
+		// rows written before encryption was turned on are encrypted too
+		legacy := FeedbackSummary{FeedbackSummary: feedback.FeedbackSummary{
+			ModelUUID:      orm.ModelUUID{ID: uuid.New()},
+			FeedbackID:     uuid.New(),
+			OrganizationID: organizationID,
+		}}
+		orm.NewDB(gctx).Create(&legacy)
+		orm.NewDB(gctx).Table("feedback_summaries").Where("id = ?", legacy.ID).UpdateColumn("summary", "plaintext summary")
+
+		key, err := crypto.RotateDataKey(gctx.Context(), orm.DB(gctx), organizationID)
+		assert.NoError(t, err)
+
+		count, err := ReencryptFeedback(gctx, organizationID)
+		assert.NoError(t, err)
+		assert.Equal(t, 3, count)
+
+		assert.True(t, strings.Contains(stored("feedback_details", "strengths"), key.ID.String()))
+
+		var after FeedbackDetails
+		orm.NewDB(gctx).First(&after, "feedback_id = ?", fb.ID)
+		assert.Equal(t, "Runs great planning meetings.", after.Strengths)
+		assert.Equal(t, before.UpdatedAt.UnixNano(), after.UpdatedAt.UnixNano())
+
+		var summaries []string
+		orm.NewDB(gctx).Table("feedback_summaries").Where("id = ?", legacy.ID).Pluck("summary", &summaries)
+		assert.True(t, crypto.IsEncrypted(summaries[0]))
+	})
+}
+
+func TestDerivedContentEncryption(t *testing.T) {
+	defer crypto.UseProvider(crypto.NewTestKeyProvider())()
+
+	gctx := orm.CreateTestContext(golly.NewContext(context.TODO()),
+		FeedbackDetails{}, FeedbackSummary{}, FeedbackAnalysis{}, FeedbackEmbedding{},
+		PerformanceReview{}, tara.Conversation{}, crypto.DataKey{})
+
+	organizationID := uuid.New()
+	employeeID := uuid.New()
+
+	analysis := FeedbackAnalysis{FeedbackAnalysis: feedback.FeedbackAnalysis{
+		ModelUUID:      orm.ModelUUID{ID: uuid.New()},
+		FeedbackID:     uuid.New(),
+		EmployeeID:     employeeID,
+		OrganizationID: organizationID,
+		Findings:       feedback.Findings{{Category: "unsupported_claim", Excerpt: "She is lazy.", Explanation: "No example given."}},
+	}}
+	assert.NoError(t, orm.NewDB(gctx).Create(&analysis).Error)
+
+	embedding := FeedbackEmbedding{
+		ModelUUID:      orm.ModelUUID{ID: uuid.New()},
+		OrganizationID: organizationID,
+		FeedbackID:     analysis.FeedbackID,
+		EmployeeID:     employeeID,
+		Section:        "strengths",
+		Content:        "Runs great planning meetings.",
+	}
+	assert.NoError(t, orm.NewDB(gctx).Create(&embedding).Error)
+
+	performanceReview := PerformanceReview{Aggregate: review.Aggregate{
+		ModelUUID:       orm.ModelUUID{ID: uuid.New()},
+		OrganizationID:  organizationID,
+		EmployeeID:      employeeID,
+		Summary:         "A strong planner.",
+		Sections:        review.Sections{{Competency: "Planning", Content: "Runs great planning meetings."}},
+		RatingRationale: "Exceeds on planning.",
+	}}
+	assert.NoError(t, orm.NewDB(gctx).Create(&performanceReview).Error)
+
+	thread := tara.Conversation{Aggregate: conversation.Aggregate{
+		ModelUUID:      orm.ModelUUID{ID: uuid.New()},
+		OrganizationID: organizationID,
+		EmployeeID:     employeeID,
+		Title:          "How is her planning?",
+		Messages: conversation.Messages{
+			{Role: conversation.RoleUser, Content: "How is her planning?"},
+			{Role: conversation.RoleAssistant, Content: "Peers say she runs great planning meetings."},
+		},
+	}}
+	assert.NoError(t, orm.NewDB(gctx).Create(&thread).Error)
+
+	// saved models keep plaintext in memory
+	assert.Equal(t, "She is lazy.", analysis.Findings[0].Excerpt)
+	assert.Equal(t, "Runs great planning meetings.", performanceReview.Sections[0].Content)
+
+	stored := func(table, column string, id uuid.UUID) string {
+		var values []string
+		orm.NewDB(gctx).Table(table).Where("id = ?", id).Pluck(column, &values)
+		if len(values) == 0 {
+			return ""
+		}
+		return values[0]
+	}
+
+	t.Run("content is encrypted at rest", func(t *testing.T) {
+		assert.True(t, crypto.IsEncrypted(stored("feedback_embeddings", "content", embedding.ID)))
+		assert.True(t, crypto.IsEncrypted(stored("performance_reviews", "summary", performanceReview.ID)))
+		assert.True(t, crypto.IsEncrypted(stored("performance_reviews", "rating_rationale", performanceReview.ID)))
+		assert.True(t, crypto.IsEncrypted(stored("tara_conversations", "title", thread.ID)))
+
+		for table, document := range map[string]struct {
+			column string
+			id     uuid.UUID
+		}{
+			"feedback_analyses":   {"findings", analysis.ID},
+			"performance_reviews": {"sections", performanceReview.ID},
+			"tara_conversations":  {"messages", thread.ID},
+		} {
+			value := stored(table, document.column, document.id)
+			assert.NotEmpty(t, value)
+			assert.NotContains(t, value, "lazy", "%s.%s", table, document.column)
+			assert.NotContains(t, value, "planning", "%s.%s", table, document.column)
+		}
+	})
+
+	t.Run("content is decrypted when loaded", func(t *testing.T) {
+		var loadedAnalysis FeedbackAnalysis
+		orm.NewDB(gctx).First(&loadedAnalysis, "id = ?", analysis.ID)
+		assert.Equal(t, "She is lazy.", loadedAnalysis.Findings[0].Excerpt)
+		assert.Equal(t, "No example given.", loadedAnalysis.Findings[0].Explanation)
+
+		var loadedEmbedding FeedbackEmbedding
+		orm.NewDB(gctx).First(&loadedEmbedding, "id = ?", embedding.ID)
+		assert.Equal(t, "Runs great planning meetings.", loadedEmbedding.Content)
+
+		var loadedReview PerformanceReview
+		orm.NewDB(gctx).First(&loadedReview, "id = ?", performanceReview.ID)
+		assert.Equal(t, "A strong planner.", loadedReview.Summary)
+		assert.Equal(t, "Runs great planning meetings.", loadedReview.Sections[0].Content)
+
+		var loadedThread tara.Conversation
+		orm.NewDB(gctx).First(&loadedThread, "id = ?", thread.ID)
+		assert.Equal(t, "How is her planning?", loadedThread.Title)
+		assert.Equal(t, "Peers say she runs great planning meetings.", loadedThread.Messages[1].Content)
+	})
+
+	t.Run("rotation re-encrypts the derived content", func(t *testing.T) {
+		key, err := crypto.RotateDataKey(gctx.Context(), orm.DB(gctx), organizationID)
+		assert.NoError(t, err)
+
+		count, err := ReencryptFeedback(gctx, organizationID)
+		assert.NoError(t, err)
+		assert.Equal(t, 4, count)
+
+		assert.Contains(t, stored("feedback_embeddings", "content", embedding.ID), key.ID.String())
+		assert.Contains(t, stored("tara_conversations", "messages", thread.ID), key.ID.String())
+
+		var loadedReview PerformanceReview
+		orm.NewDB(gctx).First(&loadedReview, "id = ?", performanceReview.ID)
+		assert.Equal(t, "Runs great planning meetings.", loadedReview.Sections[0].Content)
+	})
+}
@@ -0,0 +1,101 @@
+package reviews
+
+import (
+	"github.com/golly-go/golly"
+	"github.com/golly-go/plugins/eventsource"
+	"github.com/golly-go/plugins/orm"
+	"github.com/google/uuid"
+	"github.com/mitchrodrigues/talent-review-backend/app/domains/employees/employee"
+	"github.com/mitchrodrigues/talent-review-backend/app/domains/reviews/feedback"
+	"github.com/mitchrodrigues/talent-review-backend/app/domains/reviews/release"
+	"github.com/mitchrodrigues/talent-review-backend/app/domains/reviews/review"
+	"github.com/mitchrodrigues/talent-review-backend/app/domains/tara"
+	"github.com/mitchrodrigues/talent-review-backend/app/utils/crypto"
+	"gorm.io/gorm"
+)
+
+func EraseEmployeeSubscription(gctx golly.Context, agg eventsource.Aggregate, evt eventsource.Event) error {
+	switch event := evt.Data.(type) {
+	case employee.Erased:
+		emp := agg.(*employee.Aggregate)
+		return EraseEmployeeData(gctx, emp.OrganizationID, emp.ID, event.Email)
+	}
+	return nil
+}
+
+// EraseEmployeeData scrubs the read models holding personal data of an
+// erased employee: the feedback, reviews and releases about them read
+// crypto.Erased, the tara conversations about them are deleted and their
+// email is removed from the feedback they were asked to give. The events behind them are shredded with the keys of the
+// employee
+func EraseEmployeeData(gctx golly.Context, organizationID, employeeID uuid.UUID, email string) error {
+	return orm.DB(gctx).Transaction(func(tx *gorm.DB) error {
+		about := func(model interface{}) *gorm.DB {
+			return tx.Model(model).Where("organization_id = ? AND employee_id = ?", organizationID, employeeID)
+		}
+
+		// UpdateColumns skips the encryption hooks, crypto.Erased is
+		// stored as is and reads back as is
+		err := about(&FeedbackDetails{}).UpdateColumns(erasedColumns(&FeedbackDetails{})).Error
+		if err != nil {
+			return err
+		}
+
+		err = about(&FeedbackSummary{}).UpdateColumns(erasedColumns(&FeedbackSummary{})).Error
+		if err != nil {
+			return err
+		}
+
+		err = about(&FeedbackAnalysis{}).UpdateColumn("findings", feedback.Findings{}).Error
+		if err != nil {
+			return err
+		}
+
+		err = about(&FeedbackEmbedding{}).Delete(&FeedbackEmbedding{}).Error
+		if err != nil {
+			return err
+		}
+
+		err = about(&PerformanceReview{}).UpdateColumns(map[string]interface{}{
+			"summary":                    crypto.Erased,
+			"sections":                   review.Sections{},
+			"rating_rationale":           crypto.Erased,
+			"suggested_rating_rationale": crypto.Erased,
+		}).Error
+		if err != nil {
+			return err
+		}
+
+		// tara conversations quote the feedback and are encrypted with the
+		// key of the organization, they are deleted
+		err = about(&tara.Conversation{}).Unscoped().Delete(&tara.Conversation{}).Error
+		if err != nil {
+			return err
+		}
+
+		err = about(&FeedbackRelease{}).UpdateColumns(map[string]interface{}{
+			"note":         crypto.Erased,
+			"action_items": release.Strings{},
+		}).Error
+		if err != nil {
+			return err
+		}
+
+		if email == "" {
+			return nil
+		}
+
+		return tx.Model(&Feedback{}).
+			Where("organization_id = ? AND LOWER(email) = LOWER(?)", organizationID, email).
+			UpdateColumn("email", crypto.Erased).
+			Error
+	})
+}
+
+func erasedColumns(model encryptedModel) map[string]interface{} {
+	columns := map[string]interface{}{}
+	for column := range model.EncryptedColumns() {
+		columns[column] = crypto.Erased
+	}
+	return columns
+}
@@ -0,0 +1,137 @@
+package reviews
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/golly-go/golly"
+	"github.com/golly-go/plugins/eventsource"
+	"github.com/golly-go/plugins/orm"
+	"github.com/google/uuid"
+	"github.com/mitchrodrigues/talent-review-backend/app/domains/employees/employee"
+	"github.com/mitchrodrigues/talent-review-backend/app/domains/reviews/feedback"
+	"github.com/mitchrodrigues/talent-review-backend/app/domains/reviews/release"
+	"github.com/mitchrodrigues/talent-review-backend/app/domains/reviews/review"
+	"github.com/mitchrodrigues/talent-review-backend/app/domains/tara"
+	"github.com/mitchrodrigues/talent-review-backend/app/domains/tara/conversation"
+	"github.com/mitchrodrigues/talent-review-backend/app/utils/crypto"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEraseEmployeeData(t *testing.T) {
+	gctx := orm.CreateTestContext(golly.NewContext(context.TODO()),
+		Feedback{}, FeedbackDetails{}, FeedbackSummary{}, FeedbackAnalysis{}, FeedbackEmbedding{},
+		PerformanceReview{}, FeedbackRelease{}, tara.Conversation{})
+
+	organizationID, employeeID, peerID := uuid.New(), uuid.New(), uuid.New()
+
+	createFeedback := func(aboutID uuid.UUID, email string) Feedback {
+		fb := Feedback{Aggregate: feedback.Aggregate{
+			ModelUUID:       orm.ModelUUID{ID: uuid.New()},
+			Code:            uuid.NewString(),
+			Email:           email,
+			EmployeeID:      aboutID,
+			OrganizationID:  organizationID,
+			CollectionEndAt: time.Now(),
+		}}
+		orm.DB(gctx).Create(&fb)
+
+		orm.DB(gctx).Create(&FeedbackDetails{feedback.FeedbackDetails{
+			ModelUUID:      orm.ModelUUID{ID: uuid.New()},
+			EmployeeID:     aboutID,
+			FeedbackID:     fb.ID,
+			OrganizationID: organizationID,
+			Strengths:      "Runs great planning meetings.",
+		}})
+
+		orm.DB(gctx).Create(&FeedbackSummary{feedback.FeedbackSummary{
+			ModelUUID:      orm.ModelUUID{ID: uuid.New()},
+			EmployeeID:     aboutID,
+			FeedbackID:     fb.ID,
+			OrganizationID: organizationID,
+			Summary:        "Strong planner.",
+		}})
+
+		return fb
+	}
+
+	about := createFeedback(employeeID, "peer@example.com")
+	reviewed := createFeedback(peerID, "Jane@Example.com")
+
+	createConversation := func(organizationID, aboutID uuid.UUID) tara.Conversation {
+		thread := tara.Conversation{Aggregate: conversation.Aggregate{
+			ModelUUID:      orm.ModelUUID{ID: uuid.New()},
+			OrganizationID: organizationID,
+			EmployeeID:     aboutID,
+			Messages:       conversation.Messages{{Role: conversation.RoleAssistant, Content: "Runs great planning meetings."}},
+		}}
+		orm.DB(gctx).Create(&thread)
+		return thread
+	}
+
+	erasedThread := createConversation(organizationID, employeeID)
+	peerThread := createConversation(organizationID, peerID)
+	otherOrgThread := createConversation(uuid.New(), employeeID)
+
+	orm.DB(gctx).Create(&PerformanceReview{review.Aggregate{
+		ModelUUID:      orm.ModelUUID{ID: uuid.New()},
+		OrganizationID: organizationID,
+		EmployeeID:     employeeID,
+		Summary:        "A strong year.",
+	}})
+
+	orm.DB(gctx).Create(&FeedbackRelease{release.Aggregate{
+		ModelUUID:      orm.ModelUUID{ID: uuid.New()},
+		OrganizationID: organizationID,
+		EmployeeID:     employeeID,
+		ActionItems:    release.Strings{"Delegate the weekly sync"},
+		Note:           "Let's talk on Friday",
+	}})
+
+	err := EraseEmployeeSubscription(gctx,
+		&employee.Aggregate{ModelUUID: orm.ModelUUID{ID: employeeID}, OrganizationID: organizationID},
+		eventsource.Event{Data: employee.Erased{ErasedAt: time.Now(), Email: "jane@example.com"}})
+	assert.NoError(t, err)
+
+	t.Run("feedback about the employee is scrubbed", func(t *testing.T) {
+		var details FeedbackDetails
+		orm.DB(gctx).First(&details, "feedback_id = ?", about.ID)
+		assert.Equal(t, crypto.Erased, details.Strengths)
+
+		var summary FeedbackSummary
+		orm.DB(gctx).First(&summary, "feedback_id = ?", about.ID)
+		assert.Equal(t, crypto.Erased, summary.Summary)
+
+		var pr PerformanceReview
+		orm.DB(gctx).First(&pr, "employee_id = ?", employeeID)
+		assert.Equal(t, crypto.Erased, pr.Summary)
+
+		var rel FeedbackRelease
+		orm.DB(gctx).First(&rel, "employee_id = ?", employeeID)
+		assert.Equal(t, crypto.Erased, rel.Note)
+		assert.Empty(t, rel.ActionItems)
+	})
+
+	t.Run("tara conversations about the employee are deleted", func(t *testing.T) {
+		var threads []uuid.UUID
+		orm.DB(gctx).Model(&tara.Conversation{}).Unscoped().Pluck("id", &threads)
+		assert.NotContains(t, threads, erasedThread.ID)
+		assert.Contains(t, threads, peerThread.ID)
+		assert.Contains(t, threads, otherOrgThread.ID)
+	})
+
+	t.Run("feedback they gave keeps its content but not their email", func(t *testing.T) {
+		var fb Feedback
+		orm.DB(gctx).First(&fb, "id = ?", reviewed.ID)
+		assert.Equal(t, crypto.Erased, fb.Email)
+
+		var details FeedbackDetails
+		orm.DB(gctx).First(&details, "feedback_id = ?", reviewed.ID)
+		assert.Equal(t, "Runs great planning meetings.", details.Strengths)
+
+		var peer Feedback
+		orm.DB(gctx).First(&peer, "id = ?", about.ID)
+		assert.Equal(t, "peer@example.com", peer.Email)
+	})
+}
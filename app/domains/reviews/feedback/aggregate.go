@@ -1,15 +1,40 @@
 package feedback
 
 import (
+	"cmp"
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
 	"time"
 
 	"github.com/golly-go/golly"
 	"github.com/golly-go/plugins/eventsource"
 	"github.com/golly-go/plugins/orm"
 	"github.com/google/uuid"
+	"github.com/mitchrodrigues/talent-review-backend/app/domains/tara/prompt"
 	"github.com/mitchrodrigues/talent-review-backend/app/utils/esbackend"
 )
 
+const (
+	// VisibilityAttributed shows the reviewer next to the feedback
+	VisibilityAttributed = "ATTRIBUTED"
+
+	// VisibilityAnonymous hides the reviewer from everyone but HR
+	VisibilityAnonymous = "ANONYMOUS"
+
+	// VisibilityAggregated hides the reviewer from everyone but HR and
+	// the content until enough reviewers responded
+	VisibilityAggregated = "AGGREGATED"
+)
+
+func ValidVisibility(visibility string) bool {
+	switch visibility {
+	case VisibilityAttributed, VisibilityAnonymous, VisibilityAggregated:
+		return true
+	}
+	return false
+}
+
 type FeedbackDetails struct {
 	orm.ModelUUID
 
@@ -34,6 +59,62 @@ type FeedbackSummary struct {
 
 	Summary     string
 	ActionItems string
+
+	// PromptVersions records the tara prompt versions that generated the summary
+	PromptVersions prompt.Refs `gorm:"type:jsonb"`
+}
+
+// FeedbackAnalysis is the bias and inclusivity analysis of a submitted
+// feedback, the counts are kept as columns so they can be aggregated
+type FeedbackAnalysis struct {
+	orm.ModelUUID
+
+	EmployeeID     uuid.UUID
+	FeedbackID     uuid.UUID
+	OrganizationID uuid.UUID
+
+	GenderedLanguage     int
+	PersonalityCritiques int
+	UnsupportedClaims    int
+
+	Findings Findings `gorm:"type:jsonb"`
+
+	PromptVersions prompt.Refs `gorm:"type:jsonb"`
+}
+
+func (FeedbackAnalysis) TableName() string { return "feedback_analyses" }
+
+// Flagged is true when the analysis found anything
+func (analysis FeedbackAnalysis) Flagged() bool {
+	return analysis.GenderedLanguage+analysis.PersonalityCritiques+analysis.UnsupportedClaims > 0
+}
+
+type Finding struct {
+	Category    string `json:"category"`
+	Excerpt     string `json:"excerpt"`
+	Explanation string `json:"explanation"`
+}
+
+// Findings are stored as jsonb
+type Findings []Finding
+
+func (f Findings) Value() (driver.Value, error) {
+	if f == nil {
+		return []byte("[]"), nil
+	}
+	return json.Marshal(f)
+}
+
+func (f *Findings) Scan(value interface{}) error {
+	switch v := value.(type) {
+	case nil:
+		return nil
+	case []byte:
+		return json.Unmarshal(v, f)
+	case string:
+		return json.Unmarshal([]byte(v), f)
+	}
+	return fmt.Errorf("cannot scan %T into findings", value)
 }
 
 type Aggregate struct {
@@ -51,8 +132,16 @@ type Aggregate struct {
 	SubmittedAt     *time.Time
 	CollectionEndAt time.Time
 
-	Details FeedbackDetails `gorm:"foreignKey:FeedbackID"`
-	Summary FeedbackSummary `gorm:"foreignKey:FeedbackID"`
+	// DeclinedAt is set when the reviewer declined to give the feedback
+	DeclinedAt *time.Time
+
+	// Visibility is who sees the reviewer and the content, it is fixed
+	// when the feedback is requested
+	Visibility string
+
+	Details  FeedbackDetails  `gorm:"foreignKey:FeedbackID"`
+	Summary  FeedbackSummary  `gorm:"foreignKey:FeedbackID"`
+	Analysis FeedbackAnalysis `gorm:"foreignKey:FeedbackID"`
 }
 
 func (*Aggregate) Topic() string                             { return "events.feedback" }
@@ -73,6 +162,7 @@ func (feedback *Aggregate) Apply(ctx golly.Context, evt eventsource.Event) {
 		feedback.OrganizationID = event.OrganizationID
 
 		feedback.OwnerID = event.OwnerID
+		feedback.Visibility = cmp.Or(event.Visibility, VisibilityAttributed)
 		feedback.CreatedAt = evt.CreatedAt
 		feedback.UpdatedAt = evt.CreatedAt
 
@@ -100,10 +190,27 @@ func (feedback *Aggregate) Apply(ctx golly.Context, evt eventsource.Event) {
 	case SummaryUpdated:
 		feedback.Summary.Summary = event.Summary
 		feedback.Summary.ActionItems = event.ActionItems
+		feedback.Summary.PromptVersions = event.PromptVersions
+
+	case AnalysisCreated:
+		feedback.Analysis.ID = event.ID
+		feedback.Analysis.FeedbackID = event.FeedbackID
+		feedback.Analysis.OrganizationID = event.OrganizationID
+		feedback.Analysis.EmployeeID = event.EmployeeID
+
+	case AnalysisUpdated:
+		feedback.Analysis.GenderedLanguage = event.GenderedLanguage
+		feedback.Analysis.PersonalityCritiques = event.PersonalityCritiques
+		feedback.Analysis.UnsupportedClaims = event.UnsupportedClaims
+		feedback.Analysis.Findings = event.Findings
+		feedback.Analysis.PromptVersions = event.PromptVersions
 
 	case Submitted:
 		feedback.SubmittedAt = &evt.CreatedAt
 
+	case Declined:
+		feedback.DeclinedAt = &evt.CreatedAt
+
 	}
 }
 
@@ -1,13 +1,17 @@
 package feedback
 
 import (
+	"cmp"
 	"encoding/json"
+	"fmt"
 	"time"
 
 	"github.com/golly-go/golly"
+	"github.com/golly-go/golly/errors"
 	"github.com/golly-go/plugins/eventsource"
 	"github.com/golly-go/plugins/orm"
 	"github.com/google/uuid"
+	"github.com/mitchrodrigues/talent-review-backend/app/domains/tara/prompt"
 	"github.com/mitchrodrigues/talent-review-backend/app/utils/helpers"
 	"github.com/mitchrodrigues/talent-review-backend/app/utils/identity"
 )
@@ -19,6 +23,16 @@ type Create struct {
 	CollectionEndAt time.Time
 
 	Email string
+
+	// Visibility defaults to VisibilityAttributed
+	Visibility string
+}
+
+func (cmd Create) Validate(gctx golly.Context, aggregate eventsource.Aggregate) error {
+	if cmd.Visibility != "" && !ValidVisibility(cmd.Visibility) {
+		return errors.WrapUnprocessable(ErrorInvalidVisibility)
+	}
+	return nil
 }
 
 func (cmd Create) Perform(gctx golly.Context, aggregate eventsource.Aggregate) error {
@@ -37,18 +51,52 @@ func (cmd Create) Perform(gctx golly.Context, aggregate eventsource.Aggregate) e
 		EmployeeID:      cmd.EmployeeID,
 		OrganizationID:  cmd.OrganizationID,
 		OwnerID:         identity.FromContext(gctx).UID,
+		Visibility:      cmp.Or(cmd.Visibility, VisibilityAttributed),
 	})
 
 	return nil
 }
 
+var (
+	ErrorInvalidVisibility = fmt.Errorf("feedback visibility must be %s, %s or %s", VisibilityAttributed, VisibilityAnonymous, VisibilityAggregated)
+	ErrorAlreadySubmitted  = fmt.Errorf("feedback has already been submitted")
+	ErrorDeclined          = fmt.Errorf("feedback has been declined")
+)
+
 type Submit struct{}
 
+func (Submit) Validate(gctx golly.Context, aggregate eventsource.Aggregate) error {
+	if aggregate.(*Aggregate).DeclinedAt != nil {
+		return errors.WrapUnprocessable(ErrorDeclined)
+	}
+	return nil
+}
+
 func (Submit) Perform(gctx golly.Context, aggregate eventsource.Aggregate) error {
 	eventsource.Apply(gctx, aggregate, Submitted{})
 	return nil
 }
 
+// Decline records that the reviewer will not give the feedback
+type Decline struct{}
+
+func (Decline) Validate(gctx golly.Context, aggregate eventsource.Aggregate) error {
+	feedback := aggregate.(*Aggregate)
+
+	switch {
+	case feedback.SubmittedAt != nil:
+		return errors.WrapUnprocessable(ErrorAlreadySubmitted)
+	case feedback.DeclinedAt != nil:
+		return errors.WrapUnprocessable(ErrorDeclined)
+	}
+	return nil
+}
+
+func (Decline) Perform(gctx golly.Context, aggregate eventsource.Aggregate) error {
+	eventsource.Apply(gctx, aggregate, Declined{})
+	return nil
+}
+
 type CreateOrUpdateDetails struct {
 	Strength      string
 	Opportunities string
@@ -101,6 +149,8 @@ func (cmd CreateOrUpdateDetails) Perform(gctx golly.Context, aggregate eventsour
 type CreateSummary struct {
 	Summary     string
 	ActionItems []string
+
+	PromptVersions prompt.Refs
 }
 
 func (cmd CreateSummary) Perform(gctx golly.Context, aggregate eventsource.Aggregate) error {
@@ -124,8 +174,50 @@ func (cmd CreateSummary) Perform(gctx golly.Context, aggregate eventsource.Aggre
 	b, _ := json.Marshal(cmd.ActionItems)
 
 	eventsource.Apply(gctx, aggregate, SummaryUpdated{
-		Summary:     cmd.Summary,
-		ActionItems: string(b),
+		Summary:        cmd.Summary,
+		ActionItems:    string(b),
+		PromptVersions: cmd.PromptVersions,
+	})
+
+	return nil
+}
+
+// CreateAnalysis stores the bias and inclusivity analysis of the feedback,
+// a previous analysis is replaced
+type CreateAnalysis struct {
+	GenderedLanguage     int
+	PersonalityCritiques int
+	UnsupportedClaims    int
+
+	Findings Findings
+
+	PromptVersions prompt.Refs
+}
+
+func (cmd CreateAnalysis) Perform(gctx golly.Context, aggregate eventsource.Aggregate) error {
+	feedback := aggregate.(*Aggregate)
+
+	orm.
+		DB(gctx).
+		Model(feedback.Analysis).
+		Find(&feedback.Analysis, "feedback_id = ?", feedback.ID)
+
+	if feedback.Analysis.ID == uuid.Nil {
+		id, _ := uuid.NewV7()
+		eventsource.Apply(gctx, aggregate, AnalysisCreated{
+			ID:             id,
+			OrganizationID: feedback.OrganizationID,
+			EmployeeID:     feedback.EmployeeID,
+			FeedbackID:     feedback.ID,
+		})
+	}
+
+	eventsource.Apply(gctx, aggregate, AnalysisUpdated{
+		GenderedLanguage:     cmd.GenderedLanguage,
+		PersonalityCritiques: cmd.PersonalityCritiques,
+		UnsupportedClaims:    cmd.UnsupportedClaims,
+		Findings:             cmd.Findings,
+		PromptVersions:       cmd.PromptVersions,
 	})
 
 	return nil
@@ -0,0 +1,76 @@
+package feedback
+
+import (
+	"github.com/mitchrodrigues/talent-review-backend/app/utils/crypto"
+	"gorm.io/gorm"
+)
+
+// The free text of feedback details, summaries and analyses is encrypted
+// at rest with the data key of the organization. The hooks keep the
+// models in plaintext in memory, EncryptedColumns and EncryptedDocuments
+// are what key rotation re-encrypts
+
+func (details *FeedbackDetails) EncryptedColumns() map[string]*string {
+	return map[string]*string{
+		"strengths":     &details.Strengths,
+		"opportunities": &details.Opportunities,
+		"additional":    &details.Additional,
+	}
+}
+
+func (details *FeedbackDetails) BeforeSave(tx *gorm.DB) error {
+	return crypto.EncryptFields(tx, details.OrganizationID, crypto.Fields(details.EncryptedColumns())...)
+}
+
+func (details *FeedbackDetails) AfterSave(tx *gorm.DB) error {
+	return crypto.DecryptFields(tx, details.OrganizationID, crypto.Fields(details.EncryptedColumns())...)
+}
+
+func (details *FeedbackDetails) AfterFind(tx *gorm.DB) error {
+	return crypto.DecryptFields(tx, details.OrganizationID, crypto.Fields(details.EncryptedColumns())...)
+}
+
+func (summary *FeedbackSummary) EncryptedColumns() map[string]*string {
+	return map[string]*string{
+		"summary":      &summary.Summary,
+		"action_items": &summary.ActionItems,
+	}
+}
+
+func (summary *FeedbackSummary) BeforeSave(tx *gorm.DB) error {
+	return crypto.EncryptFields(tx, summary.OrganizationID, crypto.Fields(summary.EncryptedColumns())...)
+}
+
+func (summary *FeedbackSummary) AfterSave(tx *gorm.DB) error {
+	return crypto.DecryptFields(tx, summary.OrganizationID, crypto.Fields(summary.EncryptedColumns())...)
+}
+
+func (summary *FeedbackSummary) AfterFind(tx *gorm.DB) error {
+	return crypto.DecryptFields(tx, summary.OrganizationID, crypto.Fields(summary.EncryptedColumns())...)
+}
+
+// EncryptedText points at the excerpts and explanations of the findings,
+// they quote the feedback
+func (analysis *FeedbackAnalysis) EncryptedText() []*string {
+	text := make([]*string, 0, 2*len(analysis.Findings))
+	for i := range analysis.Findings {
+		text = append(text, &analysis.Findings[i].Excerpt, &analysis.Findings[i].Explanation)
+	}
+	return text
+}
+
+func (analysis *FeedbackAnalysis) EncryptedDocuments() map[string]interface{} {
+	return map[string]interface{}{"findings": analysis.Findings}
+}
+
+func (analysis *FeedbackAnalysis) BeforeSave(tx *gorm.DB) error {
+	return crypto.EncryptFields(tx, analysis.OrganizationID, analysis.EncryptedText()...)
+}
+
+func (analysis *FeedbackAnalysis) AfterSave(tx *gorm.DB) error {
+	return crypto.DecryptFields(tx, analysis.OrganizationID, analysis.EncryptedText()...)
+}
+
+func (analysis *FeedbackAnalysis) AfterFind(tx *gorm.DB) error {
+	return crypto.DecryptFields(tx, analysis.OrganizationID, analysis.EncryptedText()...)
+}
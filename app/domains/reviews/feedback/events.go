@@ -4,6 +4,8 @@ import (
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/mitchrodrigues/talent-review-backend/app/domains/tara/prompt"
+	"github.com/mitchrodrigues/talent-review-backend/app/utils/crypto"
 )
 
 type Created struct {
@@ -16,10 +18,21 @@ type Created struct {
 	Code  string
 
 	CollectionEndAt time.Time
+	Visibility      string
+}
+
+// The reviewer email is sealed with the key of the reviewer in the event
+// store so erasing them shreds it
+func (Created) PersonalFields() []string { return []string{"Email"} }
+
+func (evt Created) DataSubjectID() uuid.UUID {
+	return crypto.EmailSubjectID(evt.OrganizationID, evt.Email)
 }
 
 type Submitted struct{}
 
+type Declined struct{}
+
 type DetailsCreated struct {
 	ID             uuid.UUID
 	FeedbackID     uuid.UUID
@@ -44,6 +57,24 @@ type SummaryCreated struct {
 }
 
 type SummaryUpdated struct {
-	Summary     string `json:"-"`
-	ActionItems string `json:"-"`
+	Summary        string      `json:"-"`
+	ActionItems    string      `json:"-"`
+	PromptVersions prompt.Refs `json:"promptVersions"`
+}
+
+type AnalysisCreated struct {
+	ID             uuid.UUID `json:"id"`
+	OrganizationID uuid.UUID `json:"organizationID"`
+	EmployeeID     uuid.UUID `json:"employeeID"`
+	FeedbackID     uuid.UUID `json:"feedbackID"`
+}
+
+type AnalysisUpdated struct {
+	GenderedLanguage     int `json:"genderedLanguage"`
+	PersonalityCritiques int `json:"personalityCritiques"`
+	UnsupportedClaims    int `json:"unsupportedClaims"`
+
+	// Findings quote the feedback so they are kept out of the event store
+	Findings       Findings    `json:"-"`
+	PromptVersions prompt.Refs `json:"promptVersions"`
 }
@@ -61,7 +61,7 @@ func (service DefaultReviewService) FindAll_Permissioned(gctx golly.Context, sco
 		Find(&feedbacks).
 		Error
 
-	return feedbacks, err
+	return maskReviewers(gctx, feedbacks), err
 }
 
 func (DefaultReviewService) FindByIDs(gctx golly.Context, ids uuid.UUIDs) ([]Feedback, error) {
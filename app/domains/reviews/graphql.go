@@ -1,7 +1,7 @@
 package reviews
 
 import (
-	"encoding/json"
+	"cmp"
 	"fmt"
 	"reflect"
 	"sort"
@@ -13,9 +13,12 @@ import (
 	"github.com/golly-go/plugins/gql"
 	"github.com/google/uuid"
 	"github.com/graphql-go/graphql"
+	"github.com/mitchrodrigues/talent-review-backend/app/domains/accounts"
 	"github.com/mitchrodrigues/talent-review-backend/app/domains/common"
 	"github.com/mitchrodrigues/talent-review-backend/app/domains/employees"
 	"github.com/mitchrodrigues/talent-review-backend/app/domains/reviews/feedback"
+	"github.com/mitchrodrigues/talent-review-backend/app/domains/reviews/review"
+	"github.com/mitchrodrigues/talent-review-backend/app/domains/tara"
 	"github.com/mitchrodrigues/talent-review-backend/app/utils/filters"
 	"github.com/mitchrodrigues/talent-review-backend/app/utils/helpers"
 	"github.com/mitchrodrigues/talent-review-backend/app/utils/identity"
@@ -71,16 +74,206 @@ var (
 			"actionItems": {
 				Type: graphql.NewList(graphql.String),
 				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
-					var items []string
-					str := p.Source.(FeedbackSummary).ActionItems
+					return summaryActionItems(p.Source.(FeedbackSummary)), nil
+				},
+			},
+			"promptVersions": {
+				Type:        graphql.NewList(tara.PromptRefType),
+				Description: "Tara prompt versions that generated the summary",
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					return tara.SortedPromptRefs(p.Source.(FeedbackSummary).PromptVersions), nil
+				},
+			},
+		},
+	})
+
+	analysisGroupType = graphql.NewEnum(graphql.EnumConfig{
+		Name: "FeedbackAnalysisGroup",
+		Values: graphql.EnumValueConfigMap{
+			AnalysisGroupReviewer: {Value: AnalysisGroupReviewer},
+			AnalysisGroupTeam:     {Value: AnalysisGroupTeam},
+		},
+	})
+
+	analysisFindingType = graphql.NewObject(graphql.ObjectConfig{
+		Name: "FeedbackAnalysisFinding",
+		Fields: graphql.Fields{
+			"category": {
+				Type:        graphql.String,
+				Description: "gendered_language, personality_critique or unsupported_claim",
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					return p.Source.(feedback.Finding).Category, nil
+				},
+			},
+			"excerpt": {
+				Type: graphql.String,
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					return p.Source.(feedback.Finding).Excerpt, nil
+				},
+			},
+			"explanation": {
+				Type: graphql.String,
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					return p.Source.(feedback.Finding).Explanation, nil
+				},
+			},
+		},
+	})
+
+	feedbackAnalysisType = graphql.NewObject(graphql.ObjectConfig{
+		Name: "FeedbackAnalysis",
+		Fields: graphql.Fields{
+			"feedbackID": {
+				Type: graphql.String,
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					return p.Source.(FeedbackAnalysis).FeedbackID, nil
+				},
+			},
+			"flagged": {
+				Type: graphql.Boolean,
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					return p.Source.(FeedbackAnalysis).Flagged(), nil
+				},
+			},
+			"genderedLanguage": {
+				Type: graphql.Int,
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					return p.Source.(FeedbackAnalysis).GenderedLanguage, nil
+				},
+			},
+			"personalityCritiques": {
+				Type: graphql.Int,
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					return p.Source.(FeedbackAnalysis).PersonalityCritiques, nil
+				},
+			},
+			"unsupportedClaims": {
+				Type: graphql.Int,
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					return p.Source.(FeedbackAnalysis).UnsupportedClaims, nil
+				},
+			},
+			"findings": {
+				Type: graphql.NewList(analysisFindingType),
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					return []feedback.Finding(p.Source.(FeedbackAnalysis).Findings), nil
+				},
+			},
+			"promptVersions": {
+				Type: graphql.NewList(tara.PromptRefType),
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					return tara.SortedPromptRefs(p.Source.(FeedbackAnalysis).PromptVersions), nil
+				},
+			},
+			"createdAt": {
+				Type: graphql.DateTime,
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					return p.Source.(FeedbackAnalysis).CreatedAt, nil
+				},
+			},
+		},
+	})
 
-					_ = json.Unmarshal([]byte(str), &items)
-					return items, nil
+	analysisRollupType = graphql.NewObject(graphql.ObjectConfig{
+		Name: "FeedbackAnalysisRollup",
+		Fields: graphql.Fields{
+			"key": {
+				Type:        graphql.String,
+				Description: "Reviewer email or team ID",
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					return p.Source.(AnalysisRollup).Key, nil
+				},
+			},
+			"name": {
+				Type: graphql.String,
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					return p.Source.(AnalysisRollup).Name, nil
+				},
+			},
+			"feedbacks": {
+				Type: graphql.Int,
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					return p.Source.(AnalysisRollup).Feedbacks, nil
+				},
+			},
+			"flagged": {
+				Type: graphql.Int,
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					return p.Source.(AnalysisRollup).Flagged, nil
+				},
+			},
+			"flaggedRate": {
+				Type: graphql.Float,
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					return p.Source.(AnalysisRollup).FlaggedRate(), nil
+				},
+			},
+			"genderedLanguage": {
+				Type: graphql.Int,
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					return p.Source.(AnalysisRollup).GenderedLanguage, nil
+				},
+			},
+			"personalityCritiques": {
+				Type: graphql.Int,
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					return p.Source.(AnalysisRollup).PersonalityCritiques, nil
+				},
+			},
+			"unsupportedClaims": {
+				Type: graphql.Int,
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					return p.Source.(AnalysisRollup).UnsupportedClaims, nil
 				},
 			},
 		},
 	})
 
+	feedbackSearchResultType = graphql.NewObject(graphql.ObjectConfig{
+		Name: "FeedbackSearchResult",
+		Fields: graphql.Fields{
+			"feedbackID": {
+				Type: graphql.String,
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					return p.Source.(FeedbackSearchResult).FeedbackID, nil
+				},
+			},
+			"employeeID": {
+				Type: graphql.String,
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					return p.Source.(FeedbackSearchResult).EmployeeID, nil
+				},
+			},
+			"section": {
+				Type: graphql.String,
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					return p.Source.(FeedbackSearchResult).Section, nil
+				},
+			},
+			"snippet": {
+				Type: graphql.String,
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					return p.Source.(FeedbackSearchResult).Content, nil
+				},
+			},
+			"score": {
+				Type: graphql.Float,
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					return p.Source.(FeedbackSearchResult).Score, nil
+				},
+			},
+		},
+	})
+
+	feedbackVisibilityType = graphql.NewEnum(graphql.EnumConfig{
+		Name: "FeedbackVisibility",
+		Values: graphql.EnumValueConfigMap{
+			feedback.VisibilityAttributed: {Value: feedback.VisibilityAttributed, Description: "The reviewer is shown with the feedback"},
+			feedback.VisibilityAnonymous:  {Value: feedback.VisibilityAnonymous, Description: "The reviewer is only shown to HR"},
+			feedback.VisibilityAggregated: {Value: feedback.VisibilityAggregated, Description: "The reviewer is only shown to HR and the content once enough reviewers responded"},
+		},
+	})
+
 	feedbackType = graphql.NewObject(graphql.ObjectConfig{
 		Name: "Feedback",
 		Fields: graphql.Fields{
@@ -103,9 +296,24 @@ var (
 				},
 			},
 			"email": {
-				Type: graphql.String,
+				Type:        graphql.String,
+				Description: "Empty when the feedback is anonymous to you",
+				Resolve: gql.NewHandler(gql.Options{
+					Public: true,
+					Handler: func(wctx golly.WebContext, params gql.Params) (interface{}, error) {
+						fb := params.Source.(Feedback)
+
+						if fb.Email == "" || !CanSeeReviewer(wctx.Context, fb) {
+							return nil, nil
+						}
+						return fb.Email, nil
+					},
+				}),
+			},
+			"visibility": {
+				Type: feedbackVisibilityType,
 				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
-					return p.Source.(Feedback).Email, nil
+					return cmp.Or(p.Source.(Feedback).Visibility, feedback.VisibilityAttributed), nil
 				},
 			},
 			"code": {
@@ -124,6 +332,12 @@ var (
 					return p.Source.(Feedback).SubmittedAt, nil
 				},
 			},
+			"declinedAt": {
+				Type: graphql.DateTime,
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					return p.Source.(Feedback).DeclinedAt, nil
+				},
+			},
 			"employee": {
 				Type: employees.EmployeeGQLType,
 				Resolve: gql.NewHandler(gql.Options{
@@ -141,11 +355,26 @@ var (
 					},
 				}),
 			},
+			"expectations": {
+				Type:        employees.RoleExpectationsGQLType,
+				Description: "Expected behaviors of the employee at their current and next level, for the feedback form",
+				Resolve: gql.NewHandler(gql.Options{
+					Public: true,
+					Handler: func(wctx golly.WebContext, params gql.Params) (interface{}, error) {
+						return employees.ExpectationsForEmployee(wctx.Context, params.Source.(Feedback).EmployeeID)
+					},
+				}),
+			},
 			"details": {
-				Type: feedbackDetailsType,
+				Type:        feedbackDetailsType,
+				Description: "Empty for aggregated-only feedback until enough reviewers responded",
 				Resolve: gql.NewHandler(gql.Options{
 					Public: true,
 					Handler: func(ctx golly.WebContext, params gql.Params) (interface{}, error) {
+						if !CanSeeContent(ctx.Context, params.Source.(Feedback)) {
+							return nil, nil
+						}
+
 						feedbackID := params.Source.(Feedback).ID
 
 						return golly.LoadData(
@@ -163,6 +392,10 @@ var (
 				Resolve: gql.NewHandler(gql.Options{
 					Public: true,
 					Handler: func(wctx golly.WebContext, params gql.Params) (interface{}, error) {
+						if !CanSeeContent(wctx.Context, params.Source.(Feedback)) {
+							return nil, nil
+						}
+
 						feedbackID := params.Source.(Feedback).ID
 
 						return FeedbackService(wctx.Context).
@@ -264,6 +497,123 @@ var (
 		},
 	})
 
+	performanceReviewSectionType = graphql.NewObject(graphql.ObjectConfig{
+		Name: "PerformanceReviewSection",
+		Fields: graphql.Fields{
+			"competency": {
+				Type: graphql.String,
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					return p.Source.(review.Section).Competency, nil
+				},
+			},
+			"content": {
+				Type: graphql.String,
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					return p.Source.(review.Section).Content, nil
+				},
+			},
+			"citations": {
+				Type:        graphql.NewList(graphql.String),
+				Description: "IDs of the feedback the section is based on",
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					return p.Source.(review.Section).Citations, nil
+				},
+			},
+		},
+	})
+
+	performanceReviewType = graphql.NewObject(graphql.ObjectConfig{
+		Name: "PerformanceReview",
+		Fields: graphql.Fields{
+			"id": {
+				Type: graphql.String,
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					return p.Source.(PerformanceReview).ID, nil
+				},
+			},
+			"employeeID": {
+				Type: graphql.String,
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					return p.Source.(PerformanceReview).EmployeeID, nil
+				},
+			},
+			"cycleID": {
+				Type: graphql.String,
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					return p.Source.(PerformanceReview).CycleID, nil
+				},
+			},
+			"status": {
+				Type:        graphql.String,
+				Description: "DRAFT or FINALIZED",
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					return p.Source.(PerformanceReview).Status, nil
+				},
+			},
+			"summary": {
+				Type: graphql.String,
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					return p.Source.(PerformanceReview).Summary, nil
+				},
+			},
+			"sections": {
+				Type: graphql.NewList(performanceReviewSectionType),
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					return []review.Section(p.Source.(PerformanceReview).Sections), nil
+				},
+			},
+			"rating": {
+				Type: graphql.Int,
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					return p.Source.(PerformanceReview).Rating, nil
+				},
+			},
+			"ratingRationale": {
+				Type: graphql.String,
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					return p.Source.(PerformanceReview).RatingRationale, nil
+				},
+			},
+			"suggestedRating": {
+				Type:        graphql.Int,
+				Description: "Rating tara suggested when drafting the review",
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					return p.Source.(PerformanceReview).SuggestedRating, nil
+				},
+			},
+			"suggestedRatingRationale": {
+				Type: graphql.String,
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					return p.Source.(PerformanceReview).SuggestedRatingRationale, nil
+				},
+			},
+			"promptVersions": {
+				Type: graphql.NewList(tara.PromptRefType),
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					return tara.SortedPromptRefs(p.Source.(PerformanceReview).PromptVersions), nil
+				},
+			},
+			"finalizedAt": {
+				Type: graphql.DateTime,
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					return p.Source.(PerformanceReview).FinalizedAt, nil
+				},
+			},
+			"createdAt": {
+				Type: graphql.DateTime,
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					return p.Source.(PerformanceReview).CreatedAt, nil
+				},
+			},
+			"updatedAt": {
+				Type: graphql.DateTime,
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					return p.Source.(PerformanceReview).UpdatedAt, nil
+				},
+			},
+		},
+	})
+
 	queries = graphql.Fields{
 		//********** Feedback ***************//
 		"feedback": {
@@ -306,6 +656,98 @@ var (
 				},
 			}),
 		},
+		"feedbackAnalysis": {
+			Type:        feedbackAnalysisType,
+			Description: "Bias and inclusivity analysis of a feedback, HR only",
+			Args: graphql.FieldConfigArgument{
+				"feedbackID": {Type: graphql.NewNonNull(graphql.String)},
+			},
+			Resolve: gql.NewHandler(gql.Options{
+				Handler: func(wctx golly.WebContext, params gql.Params) (interface{}, error) {
+					if _, err := accounts.RequireHR(wctx.Context); err != nil {
+						return nil, err
+					}
+
+					feedbackID, err := helpers.ExtractAndParseUUID(params.Args, "feedbackID")
+					if err != nil {
+						return nil, err
+					}
+
+					return FindFeedbackAnalysis(wctx.Context, feedbackID)
+				},
+			}),
+		},
+		"feedbackAnalysisRollups": {
+			Type:        graphql.NewList(analysisRollupType),
+			Description: "Feedback analyses aggregated by reviewer or team, HR only",
+			Args: graphql.FieldConfigArgument{
+				"groupBy": {Type: graphql.NewNonNull(analysisGroupType)},
+				"since":   {Type: graphql.DateTime, Description: "Defaults to a year ago"},
+			},
+			Resolve: gql.NewHandler(gql.Options{
+				Handler: func(wctx golly.WebContext, params gql.Params) (interface{}, error) {
+					if _, err := accounts.RequireHR(wctx.Context); err != nil {
+						return nil, err
+					}
+
+					since, err := helpers.ExtractArg[time.Time](params.Args, "since")
+					if err != nil {
+						since = time.Now().AddDate(-1, 0, 0)
+					}
+
+					return AnalysisRollups(wctx.Context, params.Args["groupBy"].(string), since)
+				},
+			}),
+		},
+		"searchFeedback": {
+			Type:        graphql.NewList(feedbackSearchResultType),
+			Description: "Feedback snippets visible to the user ranked by similarity to the query",
+			Args: graphql.FieldConfigArgument{
+				"query": {Type: graphql.NewNonNull(graphql.String)},
+				"limit": {Type: graphql.Int},
+			},
+			Resolve: gql.NewHandler(gql.Options{
+				Handler: func(wctx golly.WebContext, params gql.Params) (interface{}, error) {
+					limit, _ := helpers.ExtractArg[int](params.Args, "limit")
+
+					return SearchFeedback(wctx.Context, params.Args["query"].(string), limit)
+				},
+			}),
+		},
+		//********** Performance Reviews ***************//
+		"performanceReviews": {
+			Type:        graphql.NewList(performanceReviewType),
+			Description: "Performance reviews the current user wrote for an employee",
+			Args: graphql.FieldConfigArgument{
+				"employeeID": {Type: graphql.NewNonNull(graphql.String)},
+			},
+			Resolve: gql.NewHandler(gql.Options{
+				Handler: func(wctx golly.WebContext, params gql.Params) (interface{}, error) {
+					employeeID, err := helpers.ExtractAndParseUUID(params.Args, "employeeID")
+					if err != nil {
+						return nil, err
+					}
+
+					return FindPerformanceReviews(wctx.Context, employeeID)
+				},
+			}),
+		},
+		"performanceReview": {
+			Type: performanceReviewType,
+			Args: graphql.FieldConfigArgument{
+				"id": {Type: graphql.NewNonNull(graphql.String)},
+			},
+			Resolve: gql.NewHandler(gql.Options{
+				Handler: func(wctx golly.WebContext, params gql.Params) (interface{}, error) {
+					id, err := helpers.ExtractAndParseUUID(params.Args, "id")
+					if err != nil {
+						return nil, err
+					}
+
+					return FindPerformanceReview(wctx.Context, id)
+				},
+			}),
+		},
 		"groupedFeedbacks": {
 			Name: "groupedFeedbacks",
 			Args: graphql.FieldConfigArgument{
@@ -393,6 +835,51 @@ var (
 			"includeTeam":      {Type: graphql.Boolean},
 			"includeDirects":   {Type: graphql.Boolean},
 			"collectionEndAt":  {Type: graphql.NewNonNull(graphql.DateTime)},
+			"suggestReviewers": {Type: graphql.Int, Description: "Suggest that many reviewers per employee instead of including the team and directs"},
+			"maxOpenRequests":  {Type: graphql.Int, Description: "Skip suggested reviewers with that many open requests, defaults to 5"},
+			"visibility":       {Type: feedbackVisibilityType, Description: "Defaults to the feedback visibility of your current cycle"},
+		},
+	})
+
+	coachFeedbackInputType = graphql.NewInputObject(graphql.InputObjectConfig{
+		Name: "CoachFeedbackInput",
+		Fields: graphql.InputObjectConfigFieldMap{
+			"section": {Type: graphql.NewNonNull(graphql.String), Description: "strengths or opportunities"},
+			"draft":   {Type: graphql.NewNonNull(graphql.String), Description: "Plain text of the draft answer"},
+		},
+	})
+
+	writingSuggestionType = graphql.NewObject(graphql.ObjectConfig{
+		Name: "WritingSuggestion",
+		Fields: graphql.Fields{
+			"kind": {
+				Type:        graphql.String,
+				Description: "missing_specifics, biased_language or personality_framing",
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					return p.Source.(tara.WritingSuggestion).Kind, nil
+				},
+			},
+			"excerpt": {
+				Type: graphql.String,
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					return p.Source.(tara.WritingSuggestion).Excerpt, nil
+				},
+			},
+			"suggestion": {
+				Type: graphql.String,
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					return p.Source.(tara.WritingSuggestion).Suggestion, nil
+				},
+			},
+		},
+	})
+
+	askTaraInputType = graphql.NewInputObject(graphql.InputObjectConfig{
+		Name: "AskTaraInput",
+		Fields: graphql.InputObjectConfigFieldMap{
+			"employeeID":     {Type: graphql.NewNonNull(graphql.String)},
+			"question":       {Type: graphql.NewNonNull(graphql.String)},
+			"conversationID": {Type: graphql.String, Description: "Continue a conversation, a new one is started when empty"},
 		},
 	})
 
@@ -407,6 +894,33 @@ var (
 		},
 	})
 
+	draftPerformanceReviewInputType = graphql.NewInputObject(graphql.InputObjectConfig{
+		Name: "DraftPerformanceReviewInput",
+		Fields: graphql.InputObjectConfigFieldMap{
+			"employeeID": {Type: graphql.NewNonNull(graphql.String)},
+			"cycleID":    {Type: graphql.String, Description: "Draft from the feedback of the cycle, all feedback is used when empty"},
+		},
+	})
+
+	performanceReviewSectionInputType = graphql.NewInputObject(graphql.InputObjectConfig{
+		Name: "PerformanceReviewSectionInput",
+		Fields: graphql.InputObjectConfigFieldMap{
+			"competency": {Type: graphql.NewNonNull(graphql.String)},
+			"content":    {Type: graphql.String},
+			"citations":  {Type: graphql.NewList(graphql.String)},
+		},
+	})
+
+	revisePerformanceReviewInputType = graphql.NewInputObject(graphql.InputObjectConfig{
+		Name: "RevisePerformanceReviewInput",
+		Fields: graphql.InputObjectConfigFieldMap{
+			"summary":         {Type: graphql.String},
+			"sections":        {Type: graphql.NewList(performanceReviewSectionInputType), Description: "Replaces all sections"},
+			"rating":          {Type: graphql.Int},
+			"ratingRationale": {Type: graphql.String},
+		},
+	})
+
 	mutations = graphql.Fields{
 		//********** Feedback ***************//
 		"submitFeedback": {
@@ -439,6 +953,77 @@ var (
 			}),
 		},
 
+		"declineFeedback": {
+			Name:        "declineFeedback",
+			Type:        feedbackType,
+			Description: "Decline to give the requested feedback",
+			Args: graphql.FieldConfigArgument{
+				"code": &graphql.ArgumentConfig{Type: graphql.NewNonNull(graphql.String)},
+				"id":   &graphql.ArgumentConfig{Type: graphql.NewNonNull(graphql.String)},
+			},
+			Resolve: gql.NewHandler(gql.Options{
+				Public: true,
+				Handler: func(wctx golly.WebContext, params gql.Params) (interface{}, error) {
+					id, err := helpers.ExtractAndParseUUID(params.Args, "id")
+					if err != nil {
+						return nil, err
+					}
+
+					fb, err := FeedbackService(wctx.Context).
+						FindByIDAndCode_Unsafe(wctx.Context, id, params.Args["code"].(string))
+
+					if err != nil {
+						return nil, err
+					}
+
+					if fb.ID == uuid.Nil {
+						return nil, errors.WrapNotFound(fmt.Errorf("not found"))
+					}
+
+					_, gctx := identity.SetOrganizationID(wctx.Context, fb.OrganizationID)
+
+					err = eventsource.Call(gctx, &fb.Aggregate, feedback.Decline{}, params.Metadata())
+					return fb, err
+				},
+			}),
+		},
+
+		"coachFeedback": {
+			Name:        "coachFeedback",
+			Type:        graphql.NewList(writingSuggestionType),
+			Description: "Tara suggestions to make a draft answer specific, behavioral and inclusive",
+			Args: graphql.FieldConfigArgument{
+				"code":  &graphql.ArgumentConfig{Type: graphql.NewNonNull(graphql.String)},
+				"id":    &graphql.ArgumentConfig{Type: graphql.NewNonNull(graphql.String)},
+				"input": &graphql.ArgumentConfig{Type: graphql.NewNonNull(coachFeedbackInputType)},
+			},
+			Resolve: gql.NewHandler(gql.Options{
+				Public: true,
+				Handler: func(wctx golly.WebContext, params gql.Params) (interface{}, error) {
+					id, err := helpers.ExtractAndParseUUID(params.Args, "id")
+					if err != nil {
+						return nil, err
+					}
+
+					fb, err := FeedbackService(wctx.Context).
+						FindByIDAndCode_Unsafe(wctx.Context, id, params.Args["code"].(string))
+					if err != nil {
+						return nil, err
+					}
+
+					if fb.ID == uuid.Nil {
+						return nil, errors.WrapNotFound(fmt.Errorf("not found"))
+					}
+
+					coaching, err := CoachFeedbackDraft(wctx.Context, fb,
+						params.Input["section"].(string),
+						params.Input["draft"].(string))
+
+					return coaching.Suggestions, err
+				},
+			}),
+		},
+
 		"updateFeedbackDetails": {
 			Name: "updateFeedbackDetails",
 			Type: feedbackType,
@@ -505,6 +1090,145 @@ var (
 			}),
 		},
 
+		"askTara": {
+			Name:        "askTara",
+			Type:        tara.ConversationType,
+			Description: "Ask tara about the feedback of an employee you manage",
+			Args: graphql.FieldConfigArgument{
+				"input": &graphql.ArgumentConfig{Type: graphql.NewNonNull(askTaraInputType)},
+			},
+			Resolve: gql.NewHandler(gql.Options{
+				Handler: func(wctx golly.WebContext, params gql.Params) (interface{}, error) {
+					employeeID, err := helpers.ExtractAndParseUUID(params.Input, "employeeID")
+					if err != nil {
+						return nil, err
+					}
+
+					input := AskTaraInput{
+						EmployeeID: employeeID,
+						Question:   params.Input["question"].(string),
+					}
+
+					conversationID, err := helpers.ExtractAndParseUUID(params.Input, "conversationID")
+					if err != nil {
+						return nil, err
+					}
+
+					if conversationID != uuid.Nil {
+						input.ConversationID = &conversationID
+					}
+
+					return AskTara(wctx.Context, input, params.Metadata())
+				},
+			}),
+		},
+
+		//********** Performance Reviews ***************//
+		"draftPerformanceReview": {
+			Name:        "draftPerformanceReview",
+			Type:        performanceReviewType,
+			Description: "Have tara draft the performance review of an employee you manage",
+			Args: graphql.FieldConfigArgument{
+				"input": &graphql.ArgumentConfig{Type: graphql.NewNonNull(draftPerformanceReviewInputType)},
+			},
+			Resolve: gql.NewHandler(gql.Options{
+				Handler: func(wctx golly.WebContext, params gql.Params) (interface{}, error) {
+					employeeID, err := helpers.ExtractAndParseUUID(params.Input, "employeeID")
+					if err != nil {
+						return nil, err
+					}
+
+					input := DraftPerformanceReviewInput{EmployeeID: employeeID}
+
+					cycleID, err := helpers.ExtractAndParseUUID(params.Input, "cycleID")
+					if err != nil {
+						return nil, err
+					}
+
+					if cycleID != uuid.Nil {
+						input.CycleID = &cycleID
+					}
+
+					return DraftPerformanceReview(wctx.Context, input, params.Metadata())
+				},
+			}),
+		},
+		"revisePerformanceReview": {
+			Name: "revisePerformanceReview",
+			Type: performanceReviewType,
+			Args: graphql.FieldConfigArgument{
+				"id":    &graphql.ArgumentConfig{Type: graphql.NewNonNull(graphql.String)},
+				"input": &graphql.ArgumentConfig{Type: graphql.NewNonNull(revisePerformanceReviewInputType)},
+			},
+			Resolve: gql.NewHandler(gql.Options{
+				Handler: func(wctx golly.WebContext, params gql.Params) (interface{}, error) {
+					id, err := helpers.ExtractAndParseUUID(params.Args, "id")
+					if err != nil {
+						return nil, err
+					}
+
+					cmd := review.Revise{}
+
+					if summary, err := helpers.ExtractArg[string](params.Input, "summary"); err == nil {
+						cmd.Summary = &summary
+					}
+
+					if ratingRationale, err := helpers.ExtractArg[string](params.Input, "ratingRationale"); err == nil {
+						cmd.RatingRationale = &ratingRationale
+					}
+
+					if rating, err := helpers.ExtractArg[int](params.Input, "rating"); err == nil {
+						cmd.Rating = &rating
+					}
+
+					if sections, err := helpers.ExtractArg[[]interface{}](params.Input, "sections"); err == nil {
+						parsed, err := parseReviewSections(sections)
+						if err != nil {
+							return nil, err
+						}
+						cmd.Sections = &parsed
+					}
+
+					return UpdatePerformanceReview(wctx.Context, id, cmd, params.Metadata())
+				},
+			}),
+		},
+		"finalizePerformanceReview": {
+			Name: "finalizePerformanceReview",
+			Type: performanceReviewType,
+			Args: graphql.FieldConfigArgument{
+				"id": &graphql.ArgumentConfig{Type: graphql.NewNonNull(graphql.String)},
+			},
+			Resolve: gql.NewHandler(gql.Options{
+				Handler: func(wctx golly.WebContext, params gql.Params) (interface{}, error) {
+					id, err := helpers.ExtractAndParseUUID(params.Args, "id")
+					if err != nil {
+						return nil, err
+					}
+
+					return UpdatePerformanceReview(wctx.Context, id, review.Finalize{}, params.Metadata())
+				},
+			}),
+		},
+		"draftPromotionJustification": {
+			Name:        "draftPromotionJustification",
+			Type:        employees.PromotionGQLType,
+			Description: "Have tara draft the justification of a promotion you proposed from the feedback of the employee",
+			Args: graphql.FieldConfigArgument{
+				"id": &graphql.ArgumentConfig{Type: graphql.NewNonNull(graphql.String)},
+			},
+			Resolve: gql.NewHandler(gql.Options{
+				Handler: func(wctx golly.WebContext, params gql.Params) (interface{}, error) {
+					id, err := helpers.ExtractAndParseUUID(params.Args, "id")
+					if err != nil {
+						return nil, err
+					}
+
+					return DraftPromotionJustification(wctx.Context, id, params.Metadata())
+				},
+			}),
+		},
+
 		"createFeedbacks": {
 			Name: "createFeedbacks",
 			Type: graphql.NewList(feedbackType),
@@ -514,31 +1238,74 @@ var (
 
 			Resolve: gql.NewHandler(gql.Options{
 				Handler: func(ctx golly.WebContext, params gql.Params) (interface{}, error) {
-					employeeIDs := golly.Map(params.Input["employeeIDs"].([]interface{}), func(id interface{}) uuid.UUID {
-						return uuid.MustParse(id.(string))
-					})
-
-					includeTeam, _ := helpers.ExtractArg[bool](params.Input, "includeTeam")
-					includeDirects, _ := helpers.ExtractArg[bool](params.Input, "includeDirects")
-					additionalEmails, _ := helpers.ExtractArg[[]interface{}](params.Input, "additionalEmails")
-
-					return CreateBulkFeedback(ctx.Context, CreateBulkFeedbackInput{
-						EmployeeIDs:     employeeIDs,
-						IncludeTeam:     includeTeam,
-						IncludeDirects:  includeDirects,
-						CollectionEndAt: params.Input["collectionEndAt"].(time.Time),
-						AdditionalEmails: golly.Map(additionalEmails, func(i interface{}) string {
-							return i.(string)
-						}),
-					}, params.Metadata())
+					input := bulkFeedbackInput(params.Input)
+
+					// Requests without a visibility follow the cycle they fall in
+					input.Visibility = cmp.Or(input.Visibility, currentCycleVisibility(ctx.Context))
 
+					return CreateBulkFeedback(ctx.Context, input, params.Metadata())
 				},
 			}),
 		},
 	}
 )
 
+func bulkFeedbackInput(input map[string]interface{}) CreateBulkFeedbackInput {
+	employeeIDs := golly.Map(input["employeeIDs"].([]interface{}), func(id interface{}) uuid.UUID {
+		return uuid.MustParse(id.(string))
+	})
+
+	includeTeam, _ := helpers.ExtractArg[bool](input, "includeTeam")
+	includeDirects, _ := helpers.ExtractArg[bool](input, "includeDirects")
+	additionalEmails, _ := helpers.ExtractArg[[]interface{}](input, "additionalEmails")
+	suggestReviewers, _ := helpers.ExtractArg[int](input, "suggestReviewers")
+	maxOpenRequests, _ := helpers.ExtractArg[int](input, "maxOpenRequests")
+	visibility, _ := helpers.ExtractArg[string](input, "visibility")
+
+	return CreateBulkFeedbackInput{
+		EmployeeIDs:      employeeIDs,
+		IncludeTeam:      includeTeam,
+		IncludeDirects:   includeDirects,
+		CollectionEndAt:  input["collectionEndAt"].(time.Time),
+		SuggestReviewers: suggestReviewers,
+		MaxOpenRequests:  maxOpenRequests,
+		Visibility:       visibility,
+		AdditionalEmails: golly.Map(additionalEmails, func(i interface{}) string {
+			return i.(string)
+		}),
+	}
+}
+
+func parseReviewSections(input []interface{}) (review.Sections, error) {
+	sections := review.Sections{}
+
+	for _, item := range input {
+		fields := item.(map[string]interface{})
+
+		content, _ := helpers.ExtractArg[string](fields, "content")
+		citations, _ := helpers.ExtractArg[[]interface{}](fields, "citations")
+
+		section := review.Section{
+			Competency: fields["competency"].(string),
+			Content:    content,
+			Citations:  []uuid.UUID{},
+		}
+
+		for _, citation := range citations {
+			id, err := uuid.Parse(citation.(string))
+			if err != nil {
+				return nil, errors.WrapUnprocessable(err)
+			}
+			section.Citations = append(section.Citations, id)
+		}
+
+		sections = append(sections, section)
+	}
+
+	return sections, nil
+}
+
 func InitGraphQL() {
-	gql.RegisterQuery(queries)
-	gql.RegisterMutation(mutations)
+	gql.RegisterQuery(queries, calibrationQueries, analyticsQueries, nominationQueries, releaseQueries)
+	gql.RegisterMutation(mutations, calibrationMutations, suggestionMutations, nominationMutations, visibilityMutations, releaseMutations)
 }
@@ -1,6 +1,10 @@
 package reviews
 
-import "github.com/mitchrodrigues/talent-review-backend/app/domains/reviews/feedback"
+import (
+	"github.com/mitchrodrigues/talent-review-backend/app/domains/reviews/cycle"
+	"github.com/mitchrodrigues/talent-review-backend/app/domains/reviews/feedback"
+	"github.com/mitchrodrigues/talent-review-backend/app/domains/reviews/review"
+)
 
 type Feedback struct {
 	feedback.Aggregate
@@ -19,3 +23,27 @@ type FeedbackSummary struct {
 }
 
 func (FeedbackDetails) FeedbackSummary() string { return "feedback_summaries" }
+
+type FeedbackAnalysis struct {
+	feedback.FeedbackAnalysis
+}
+
+func (FeedbackAnalysis) TableName() string { return "feedback_analyses" }
+
+type PerformanceReview struct {
+	review.Aggregate
+}
+
+func (PerformanceReview) TableName() string { return "performance_reviews" }
+
+type Cycle struct {
+	cycle.Aggregate
+}
+
+func (Cycle) TableName() string { return "cycles" }
+
+// feedbackCycle matches feedback to the cycles it was requested in, cycles
+// belong to whoever requested the feedback
+const feedbackCycle = "cycle.owner_id = feedbacks.owner_id " +
+	"AND cycle.organization_id = feedbacks.organization_id " +
+	"AND feedbacks.created_at BETWEEN cycle.start_at AND cycle.end_at"
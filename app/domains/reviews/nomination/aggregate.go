@@ -0,0 +1,138 @@
+package nomination
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/golly-go/golly"
+	"github.com/golly-go/plugins/eventsource"
+	"github.com/golly-go/plugins/orm"
+	"github.com/google/uuid"
+	"github.com/mitchrodrigues/talent-review-backend/app/utils/esbackend"
+)
+
+const (
+	StatusProposed = "PROPOSED"
+	StatusApproved = "APPROVED"
+	StatusRejected = "REJECTED"
+
+	MaxReviewers = 10
+)
+
+// Aggregate is the reviewers an employee nominated for a cycle, their
+// manager approves (possibly after editing the list) or rejects them
+type Aggregate struct {
+	eventsource.AggregateBase
+
+	orm.ModelUUID
+
+	OrganizationID uuid.UUID
+	CycleID        uuid.UUID
+	EmployeeID     uuid.UUID
+
+	// NominatorID is the user of the employee and ApproverID the user
+	// of their manager when nominated
+	NominatorID uuid.UUID
+	ApproverID  uuid.UUID
+
+	Emails Emails `gorm:"type:jsonb"`
+
+	Status    string
+	Note      string
+	DecidedAt *time.Time
+
+	// FeedbackIDs are the feedback requested once approved
+	FeedbackIDs UUIDs `gorm:"type:jsonb"`
+}
+
+func (*Aggregate) Topic() string                             { return "events.nominations" }
+func (*Aggregate) Repo(golly.Context) eventsource.Repository { return esbackend.PostgresRepository{} }
+func (*Aggregate) TableName() string                         { return "nominations" }
+
+func (nomination *Aggregate) GetID() string   { return nomination.ID.String() }
+func (nomination *Aggregate) SetID(id string) { nomination.ID, _ = uuid.Parse(id) }
+
+func (nomination *Aggregate) Apply(ctx golly.Context, evt eventsource.Event) {
+	switch event := evt.Data.(type) {
+	case Proposed:
+		nomination.ID = event.ID
+		nomination.OrganizationID = event.OrganizationID
+		nomination.CycleID = event.CycleID
+		nomination.EmployeeID = event.EmployeeID
+		nomination.NominatorID = event.NominatorID
+		nomination.ApproverID = event.ApproverID
+		nomination.Emails = event.Emails
+		nomination.FeedbackIDs = UUIDs{}
+		nomination.Status = StatusProposed
+
+		nomination.CreatedAt = evt.CreatedAt
+
+	case EmailsUpdated:
+		nomination.Emails = event.Emails
+
+	case Approved:
+		nomination.Emails = event.Emails
+		nomination.Note = event.Note
+		nomination.Status = StatusApproved
+		nomination.DecidedAt = &evt.CreatedAt
+
+	case Rejected:
+		nomination.Note = event.Note
+		nomination.Status = StatusRejected
+		nomination.DecidedAt = &evt.CreatedAt
+
+	case FeedbackRequested:
+		nomination.FeedbackIDs = append(nomination.FeedbackIDs, event.FeedbackIDs...)
+	}
+	nomination.UpdatedAt = evt.CreatedAt
+}
+
+func (nomination *Aggregate) Open() bool { return nomination.Status == StatusProposed }
+
+// Emails are stored as a jsonb array
+type Emails []string
+
+func (e Emails) Value() (driver.Value, error) {
+	if e == nil {
+		return []byte("[]"), nil
+	}
+	return json.Marshal(e)
+}
+
+func (e *Emails) Scan(value interface{}) error {
+	switch v := value.(type) {
+	case nil:
+		return nil
+	case []byte:
+		return json.Unmarshal(v, e)
+	case string:
+		return json.Unmarshal([]byte(v), e)
+	}
+	return fmt.Errorf("cannot scan %T into emails", value)
+}
+
+// UUIDs are stored as a jsonb array
+type UUIDs []uuid.UUID
+
+func (u UUIDs) Value() (driver.Value, error) {
+	if u == nil {
+		return []byte("[]"), nil
+	}
+	return json.Marshal(u)
+}
+
+func (u *UUIDs) Scan(value interface{}) error {
+	switch v := value.(type) {
+	case nil:
+		return nil
+	case []byte:
+		return json.Unmarshal(v, u)
+	case string:
+		return json.Unmarshal([]byte(v), u)
+	}
+	return fmt.Errorf("cannot scan %T into uuids", value)
+}
+
+var _ eventsource.Aggregate = &Aggregate{}
@@ -0,0 +1,211 @@
+package nomination
+
+import (
+	"fmt"
+	"net/mail"
+	"strings"
+
+	"github.com/golly-go/golly"
+	"github.com/golly-go/golly/errors"
+	"github.com/golly-go/plugins/eventsource"
+	"github.com/google/uuid"
+)
+
+var (
+	ErrorCycleRequired     = fmt.Errorf("nomination cycle is required")
+	ErrorEmployeeRequired  = fmt.Errorf("nomination employee is required")
+	ErrorApproverRequired  = fmt.Errorf("you need a manager with an account to approve your nominations")
+	ErrorReviewersRequired = fmt.Errorf("nominate at least one reviewer")
+	ErrorTooManyReviewers  = fmt.Errorf("nominate at most %d reviewers", MaxReviewers)
+	ErrorInvalidEmail      = fmt.Errorf("reviewers must be valid email addresses")
+	ErrorSelfNominated     = fmt.Errorf("you cannot nominate yourself")
+	ErrorNotOpen           = fmt.Errorf("nomination has already been decided")
+	ErrorNotNominator      = fmt.Errorf("only the employee can change their nominations")
+	ErrorNotApprover       = fmt.Errorf("only the manager of the employee can decide on nominations")
+	ErrorNotApproved       = fmt.Errorf("nomination has not been approved")
+)
+
+type Propose struct {
+	OrganizationID uuid.UUID
+	CycleID        uuid.UUID
+	EmployeeID     uuid.UUID
+	NominatorID    uuid.UUID
+	ApproverID     uuid.UUID
+
+	// EmployeeEmail keeps employees from nominating themselves
+	EmployeeEmail string
+	Emails        []string
+}
+
+func (cmd Propose) Validate(ctx golly.Context, aggregate eventsource.Aggregate) error {
+	switch {
+	case cmd.CycleID == uuid.Nil:
+		return errors.WrapUnprocessable(ErrorCycleRequired)
+	case cmd.EmployeeID == uuid.Nil:
+		return errors.WrapUnprocessable(ErrorEmployeeRequired)
+	case cmd.ApproverID == uuid.Nil:
+		return errors.WrapUnprocessable(ErrorApproverRequired)
+	}
+
+	_, err := normalize(cmd.Emails, cmd.EmployeeEmail)
+	return err
+}
+
+func (cmd Propose) Perform(ctx golly.Context, aggregate eventsource.Aggregate) error {
+	id, _ := uuid.NewV7()
+	emails, _ := normalize(cmd.Emails, cmd.EmployeeEmail)
+
+	eventsource.Apply(ctx, aggregate, Proposed{
+		ID:             id,
+		OrganizationID: cmd.OrganizationID,
+		CycleID:        cmd.CycleID,
+		EmployeeID:     cmd.EmployeeID,
+		NominatorID:    cmd.NominatorID,
+		ApproverID:     cmd.ApproverID,
+		Emails:         emails,
+	})
+	return nil
+}
+
+// Revise lets the employee change the reviewers they nominated until
+// their manager decides
+type Revise struct {
+	UserID        uuid.UUID
+	EmployeeEmail string
+	Emails        []string
+}
+
+func (cmd Revise) Validate(ctx golly.Context, aggregate eventsource.Aggregate) error {
+	nomination := aggregate.(*Aggregate)
+
+	switch {
+	case !nomination.Open():
+		return errors.WrapUnprocessable(ErrorNotOpen)
+	case nomination.NominatorID != cmd.UserID:
+		return errors.WrapForbidden(ErrorNotNominator)
+	}
+
+	_, err := normalize(cmd.Emails, cmd.EmployeeEmail)
+	return err
+}
+
+func (cmd Revise) Perform(ctx golly.Context, aggregate eventsource.Aggregate) error {
+	emails, _ := normalize(cmd.Emails, cmd.EmployeeEmail)
+
+	eventsource.Apply(ctx, aggregate, EmailsUpdated{UserID: cmd.UserID, Emails: emails})
+	return nil
+}
+
+// Approve accepts the nominations, Emails replaces the nominated
+// reviewers when the manager edited them and is nil otherwise
+type Approve struct {
+	UserID        uuid.UUID
+	EmployeeEmail string
+	Emails        []string
+	Note          string
+}
+
+func (cmd Approve) Validate(ctx golly.Context, aggregate eventsource.Aggregate) error {
+	nomination := aggregate.(*Aggregate)
+
+	if err := validateDecision(nomination, cmd.UserID); err != nil {
+		return err
+	}
+
+	if cmd.Emails != nil {
+		_, err := normalize(cmd.Emails, cmd.EmployeeEmail)
+		return err
+	}
+	return nil
+}
+
+func (cmd Approve) Perform(ctx golly.Context, aggregate eventsource.Aggregate) error {
+	emails := aggregate.(*Aggregate).Emails
+
+	if cmd.Emails != nil {
+		emails, _ = normalize(cmd.Emails, cmd.EmployeeEmail)
+	}
+
+	eventsource.Apply(ctx, aggregate, Approved{
+		UserID: cmd.UserID,
+		Emails: emails,
+		Note:   strings.TrimSpace(cmd.Note),
+	})
+	return nil
+}
+
+type Reject struct {
+	UserID uuid.UUID
+	Note   string
+}
+
+func (cmd Reject) Validate(ctx golly.Context, aggregate eventsource.Aggregate) error {
+	return validateDecision(aggregate.(*Aggregate), cmd.UserID)
+}
+
+func (cmd Reject) Perform(ctx golly.Context, aggregate eventsource.Aggregate) error {
+	eventsource.Apply(ctx, aggregate, Rejected{UserID: cmd.UserID, Note: strings.TrimSpace(cmd.Note)})
+	return nil
+}
+
+// RecordFeedbackRequests records the feedback requested from the
+// approved reviewers
+type RecordFeedbackRequests struct {
+	FeedbackIDs []uuid.UUID
+}
+
+func (cmd RecordFeedbackRequests) Validate(ctx golly.Context, aggregate eventsource.Aggregate) error {
+	if aggregate.(*Aggregate).Status != StatusApproved {
+		return errors.WrapUnprocessable(ErrorNotApproved)
+	}
+	return nil
+}
+
+func (cmd RecordFeedbackRequests) Perform(ctx golly.Context, aggregate eventsource.Aggregate) error {
+	eventsource.Apply(ctx, aggregate, FeedbackRequested{FeedbackIDs: cmd.FeedbackIDs})
+	return nil
+}
+
+func validateDecision(nomination *Aggregate, userID uuid.UUID) error {
+	switch {
+	case !nomination.Open():
+		return errors.WrapUnprocessable(ErrorNotOpen)
+	case nomination.ApproverID != userID:
+		return errors.WrapForbidden(ErrorNotApprover)
+	}
+	return nil
+}
+
+// normalize validates the nominated emails and removes duplicates
+// ignoring case, keeping the order they were given in
+func normalize(emails []string, employeeEmail string) (Emails, error) {
+	ret := Emails{}
+	seen := map[string]bool{}
+
+	for _, email := range emails {
+		address, err := mail.ParseAddress(strings.TrimSpace(email))
+		if err != nil {
+			return nil, errors.WrapUnprocessable(ErrorInvalidEmail)
+		}
+
+		key := strings.ToLower(address.Address)
+
+		if strings.EqualFold(key, employeeEmail) {
+			return nil, errors.WrapUnprocessable(ErrorSelfNominated)
+		}
+
+		if !seen[key] {
+			seen[key] = true
+			ret = append(ret, address.Address)
+		}
+	}
+
+	switch {
+	case len(ret) == 0:
+		return nil, errors.WrapUnprocessable(ErrorReviewersRequired)
+	case len(ret) > MaxReviewers:
+		return nil, errors.WrapUnprocessable(ErrorTooManyReviewers)
+	}
+
+	return ret, nil
+}
@@ -0,0 +1,130 @@
+package nomination
+
+import (
+	"context"
+	"testing"
+
+	"github.com/golly-go/golly"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestProposeValidate(t *testing.T) {
+	gctx := golly.NewContext(context.TODO())
+
+	valid := Propose{
+		CycleID:       uuid.New(),
+		EmployeeID:    uuid.New(),
+		NominatorID:   uuid.New(),
+		ApproverID:    uuid.New(),
+		EmployeeEmail: "jane@example.com",
+		Emails:        []string{"peer@example.com"},
+	}
+
+	with := func(fn func(*Propose)) Propose {
+		cmd := valid
+		fn(&cmd)
+		return cmd
+	}
+
+	tooMany := []string{}
+	for i := 0; i <= MaxReviewers; i++ {
+		tooMany = append(tooMany, uuid.NewString()+"@example.com")
+	}
+
+	tests := []struct {
+		name      string
+		cmd       Propose
+		expectErr error
+	}{
+		{name: "valid", cmd: valid},
+		{name: "missing cycle", cmd: with(func(c *Propose) { c.CycleID = uuid.Nil }), expectErr: ErrorCycleRequired},
+		{name: "missing approver", cmd: with(func(c *Propose) { c.ApproverID = uuid.Nil }), expectErr: ErrorApproverRequired},
+		{name: "no reviewers", cmd: with(func(c *Propose) { c.Emails = nil }), expectErr: ErrorReviewersRequired},
+		{name: "invalid email", cmd: with(func(c *Propose) { c.Emails = []string{"peer"} }), expectErr: ErrorInvalidEmail},
+		{name: "self nominated", cmd: with(func(c *Propose) { c.Emails = []string{"Jane@example.com"} }), expectErr: ErrorSelfNominated},
+		{name: "too many reviewers", cmd: with(func(c *Propose) { c.Emails = tooMany }), expectErr: ErrorTooManyReviewers},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.cmd.Validate(gctx, &Aggregate{})
+			if tt.expectErr != nil {
+				assert.ErrorContains(t, err, tt.expectErr.Error())
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestNominationLifecycle(t *testing.T) {
+	gctx := golly.NewContext(context.TODO())
+
+	nominatorID, approverID := uuid.New(), uuid.New()
+
+	propose := func() *Aggregate {
+		agg := &Aggregate{}
+
+		assert.NoError(t, Propose{
+			CycleID:       uuid.New(),
+			EmployeeID:    uuid.New(),
+			NominatorID:   nominatorID,
+			ApproverID:    approverID,
+			EmployeeEmail: "jane@example.com",
+			Emails:        []string{" peer@example.com", "Peer@example.com", "lead@example.com"},
+		}.Perform(gctx, agg))
+
+		return agg
+	}
+
+	t.Run("revised by the employee and approved with edits", func(t *testing.T) {
+		agg := propose()
+
+		assert.Equal(t, StatusProposed, agg.Status)
+		assert.Equal(t, Emails{"peer@example.com", "lead@example.com"}, agg.Emails)
+
+		revise := Revise{UserID: nominatorID, EmployeeEmail: "jane@example.com", Emails: []string{"peer@example.com"}}
+		assert.NoError(t, revise.Validate(gctx, agg))
+		assert.NoError(t, revise.Perform(gctx, agg))
+		assert.Equal(t, Emails{"peer@example.com"}, agg.Emails)
+
+		assert.ErrorContains(t, Revise{UserID: approverID, Emails: []string{"x@example.com"}}.Validate(gctx, agg), ErrorNotNominator.Error())
+		assert.ErrorContains(t, Approve{UserID: nominatorID}.Validate(gctx, agg), ErrorNotApprover.Error())
+		assert.ErrorContains(t, RecordFeedbackRequests{}.Validate(gctx, agg), ErrorNotApproved.Error())
+
+		approve := Approve{UserID: approverID, EmployeeEmail: "jane@example.com", Emails: []string{"peer@example.com", "skip@example.com"}, Note: " Added skip "}
+		assert.NoError(t, approve.Validate(gctx, agg))
+		assert.NoError(t, approve.Perform(gctx, agg))
+
+		assert.Equal(t, StatusApproved, agg.Status)
+		assert.Equal(t, Emails{"peer@example.com", "skip@example.com"}, agg.Emails)
+		assert.Equal(t, "Added skip", agg.Note)
+		assert.NotNil(t, agg.DecidedAt)
+
+		feedbackID := uuid.New()
+		assert.NoError(t, RecordFeedbackRequests{FeedbackIDs: []uuid.UUID{feedbackID}}.Perform(gctx, agg))
+		assert.Equal(t, UUIDs{feedbackID}, agg.FeedbackIDs)
+
+		assert.ErrorContains(t, Reject{UserID: approverID}.Validate(gctx, agg), ErrorNotOpen.Error())
+		assert.ErrorContains(t, revise.Validate(gctx, agg), ErrorNotOpen.Error())
+	})
+
+	t.Run("approved as nominated", func(t *testing.T) {
+		agg := propose()
+
+		assert.NoError(t, Approve{UserID: approverID}.Perform(gctx, agg))
+		assert.Equal(t, Emails{"peer@example.com", "lead@example.com"}, agg.Emails)
+	})
+
+	t.Run("rejected by the manager", func(t *testing.T) {
+		agg := propose()
+
+		assert.NoError(t, Reject{UserID: approverID, Note: "Ask your team"}.Validate(gctx, agg))
+		assert.NoError(t, Reject{UserID: approverID, Note: "Ask your team"}.Perform(gctx, agg))
+
+		assert.Equal(t, StatusRejected, agg.Status)
+		assert.Equal(t, "Ask your team", agg.Note)
+		assert.ErrorContains(t, Approve{UserID: approverID}.Validate(gctx, agg), ErrorNotOpen.Error())
+	})
+}
@@ -0,0 +1,36 @@
+package nomination
+
+import (
+	"github.com/google/uuid"
+)
+
+type Proposed struct {
+	ID             uuid.UUID `json:"id"`
+	OrganizationID uuid.UUID `json:"organizationID"`
+	CycleID        uuid.UUID `json:"cycleID"`
+	EmployeeID     uuid.UUID `json:"employeeID"`
+	NominatorID    uuid.UUID `json:"nominatorID"`
+	ApproverID     uuid.UUID `json:"approverID"`
+
+	Emails Emails `json:"emails"`
+}
+
+type EmailsUpdated struct {
+	UserID uuid.UUID `json:"userID"`
+	Emails Emails    `json:"emails"`
+}
+
+type Approved struct {
+	UserID uuid.UUID `json:"userID"`
+	Emails Emails    `json:"emails"`
+	Note   string    `json:"-"`
+}
+
+type Rejected struct {
+	UserID uuid.UUID `json:"userID"`
+	Note   string    `json:"-"`
+}
+
+type FeedbackRequested struct {
+	FeedbackIDs UUIDs `json:"feedbackIDs"`
+}
@@ -0,0 +1,368 @@
+package reviews
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/golly-go/golly"
+	"github.com/golly-go/golly/errors"
+	"github.com/golly-go/plugins/eventsource"
+	"github.com/golly-go/plugins/orm"
+	"github.com/google/uuid"
+	"github.com/mitchrodrigues/talent-review-backend/app/domains/accounts"
+	"github.com/mitchrodrigues/talent-review-backend/app/domains/common"
+	"github.com/mitchrodrigues/talent-review-backend/app/domains/employees"
+	"github.com/mitchrodrigues/talent-review-backend/app/domains/reviews/feedback"
+	"github.com/mitchrodrigues/talent-review-backend/app/domains/reviews/nomination"
+	"github.com/mitchrodrigues/talent-review-backend/app/utils/identity"
+	"github.com/mitchrodrigues/talent-review-backend/app/utils/mailgun"
+	"gorm.io/gorm"
+)
+
+const (
+	// nominationCollectionPeriod is how long reviewers have when the
+	// cycle of an approved nomination already ended
+	nominationCollectionPeriod = 14 * 24 * time.Hour
+)
+
+var (
+	ErrorNotNominationEmployee = fmt.Errorf("only employees can nominate reviewers")
+	ErrorAlreadyNominated      = fmt.Errorf("you already nominated reviewers for this cycle")
+)
+
+type Nomination struct {
+	nomination.Aggregate
+}
+
+func (Nomination) TableName() string { return "nominations" }
+
+type NominateReviewersInput struct {
+	CycleID uuid.UUID
+	Emails  []string
+}
+
+// NominateReviewers lets the current employee propose who should give
+// them feedback for a cycle, their manager has to approve it
+func NominateReviewers(gctx golly.Context, input NominateReviewersInput, metadata eventsource.Metadata) (Nomination, error) {
+	var nom Nomination
+
+	ident := identity.FromContext(gctx)
+
+	employee, err := employees.Service(gctx).FindEmployeeByUserID(gctx, ident.UID)
+	if err != nil {
+		return nom, errors.WrapForbidden(ErrorNotNominationEmployee)
+	}
+
+	var cycle Cycle
+
+	err = orm.DB(gctx).
+		Model(&Cycle{}).
+		Scopes(common.OrganizationIDScopeForContext(gctx)).
+		First(&cycle, "id = ?", input.CycleID).
+		Error
+
+	if err != nil {
+		return nom, errors.WrapNotFound(err)
+	}
+
+	var count int64
+
+	err = nominationQuery(gctx).
+		Where("employee_id = ? AND cycle_id = ? AND status <> ?", employee.ID, cycle.ID, nomination.StatusRejected).
+		Count(&count).
+		Error
+
+	if err != nil {
+		return nom, errors.WrapGeneric(err)
+	}
+
+	if count > 0 {
+		return nom, errors.WrapUnprocessable(ErrorAlreadyNominated)
+	}
+
+	var approverID uuid.UUID
+
+	if employee.ManagerID != nil {
+		manager, err := employees.Service(gctx).FindEmployeeByID(gctx, *employee.ManagerID)
+		if err == nil && manager.UserID != nil {
+			approverID = *manager.UserID
+		}
+	}
+
+	err = eventsource.Call(gctx, &nom.Aggregate, nomination.Propose{
+		OrganizationID: ident.OrganizationID,
+		CycleID:        cycle.ID,
+		EmployeeID:     employee.ID,
+		NominatorID:    ident.UID,
+		ApproverID:     approverID,
+		EmployeeEmail:  employee.Email,
+		Emails:         input.Emails,
+	}, metadata)
+
+	return nom, err
+}
+
+// UpdateNomination runs a command (revise, approve or reject) on a
+// nomination the current user can see. Once approved feedback is
+// requested from each reviewer, until the end of the cycle or for two
+// weeks when the cycle already ended
+func UpdateNomination(gctx golly.Context, id uuid.UUID, cmd eventsource.Command, metadata eventsource.Metadata) (Nomination, error) {
+	nom, err := FindNomination(gctx, id)
+	if err != nil {
+		return nom, err
+	}
+
+	wasOpen := nom.Open()
+
+	if err := eventsource.Call(gctx, &nom.Aggregate, cmd, metadata); err != nil {
+		return nom, err
+	}
+
+	if wasOpen && nom.Status == nomination.StatusApproved {
+		return nom, requestNominatedFeedback(gctx, &nom, metadata)
+	}
+
+	return nom, nil
+}
+
+// ReviseNomination replaces the reviewers the current user nominated
+func ReviseNomination(gctx golly.Context, id uuid.UUID, emails []string, metadata eventsource.Metadata) (Nomination, error) {
+	return updateNominationEmails(gctx, id, func(employeeEmail string) eventsource.Command {
+		return nomination.Revise{
+			UserID:        identity.FromContext(gctx).UID,
+			EmployeeEmail: employeeEmail,
+			Emails:        emails,
+		}
+	}, metadata)
+}
+
+// ApproveNomination approves a nomination as the manager, emails replaces
+// the nominated reviewers when not nil
+func ApproveNomination(gctx golly.Context, id uuid.UUID, emails []string, note string, metadata eventsource.Metadata) (Nomination, error) {
+	return updateNominationEmails(gctx, id, func(employeeEmail string) eventsource.Command {
+		return nomination.Approve{
+			UserID:        identity.FromContext(gctx).UID,
+			EmployeeEmail: employeeEmail,
+			Emails:        emails,
+			Note:          note,
+		}
+	}, metadata)
+}
+
+// updateNominationEmails runs a command changing the reviewers, which
+// needs the email of the employee so they cannot be their own reviewer
+func updateNominationEmails(gctx golly.Context, id uuid.UUID, cmd func(string) eventsource.Command, metadata eventsource.Metadata) (Nomination, error) {
+	nom, err := FindNomination(gctx, id)
+	if err != nil {
+		return nom, err
+	}
+
+	employee, err := employees.Service(gctx).FindEmployeeByID_Unsafe(gctx, nom.EmployeeID)
+	if err != nil {
+		return nom, err
+	}
+
+	return UpdateNomination(gctx, id, cmd(employee.Email), metadata)
+}
+
+// RequestNominationFeedback requests feedback from the reviewers of an
+// approved nomination that were not asked yet, it retries an approval
+// that failed part way through requesting
+func RequestNominationFeedback(gctx golly.Context, id uuid.UUID, metadata eventsource.Metadata) (Nomination, error) {
+	nom, err := FindNomination(gctx, id)
+	if err != nil {
+		return nom, err
+	}
+
+	if nom.ApproverID != identity.FromContext(gctx).UID {
+		return nom, errors.WrapForbidden(nomination.ErrorNotApprover)
+	}
+
+	if nom.Status != nomination.StatusApproved {
+		return nom, errors.WrapUnprocessable(nomination.ErrorNotApproved)
+	}
+
+	return nom, requestNominatedFeedback(gctx, &nom, metadata)
+}
+
+// requestNominatedFeedback requests feedback from each reviewer of the
+// nomination not asked yet. Each request is recorded on the nomination
+// as it is made so a failure leaves the ones made before it and a retry
+// only asks the rest
+func requestNominatedFeedback(gctx golly.Context, nom *Nomination, metadata eventsource.Metadata) error {
+	var cycle Cycle
+
+	err := orm.DB(gctx).
+		Model(&Cycle{}).
+		Scopes(common.OrganizationIDScopeForContext(gctx)).
+		First(&cycle, "id = ?", nom.CycleID).
+		Error
+
+	if err != nil {
+		return errors.WrapNotFound(err)
+	}
+
+	var requested []string
+
+	if len(nom.FeedbackIDs) > 0 {
+		err := orm.DB(gctx).
+			Model(&Feedback{}).
+			Where("id IN ?", []uuid.UUID(nom.FeedbackIDs)).
+			Pluck("email", &requested).
+			Error
+
+		if err != nil {
+			return errors.WrapGeneric(err)
+		}
+	}
+
+	collectionEndAt := cycle.EndAt
+	if collectionEndAt.Before(time.Now()) {
+		collectionEndAt = time.Now().Add(nominationCollectionPeriod)
+	}
+
+	for _, email := range nom.Emails {
+		if slices.ContainsFunc(requested, func(r string) bool { return strings.EqualFold(r, email) }) {
+			continue
+		}
+
+		record := Feedback{}
+
+		err := eventsource.Call(gctx, &record.Aggregate, feedback.Create{
+			OrganizationID:  nom.OrganizationID,
+			EmployeeID:      nom.EmployeeID,
+			CollectionEndAt: collectionEndAt,
+			Email:           email,
+			Visibility:      cycle.FeedbackVisibility,
+		}, metadata)
+
+		if err != nil {
+			return err
+		}
+
+		err = eventsource.Call(gctx, &nom.Aggregate, nomination.RecordFeedbackRequests{FeedbackIDs: []uuid.UUID{record.ID}}, metadata)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func nominationQuery(gctx golly.Context) *gorm.DB {
+	return orm.DB(gctx).
+		Model(&Nomination{}).
+		Scopes(common.OrganizationIDScopeForContext(gctx))
+}
+
+// FindNomination finds a nomination of the organization, only the
+// employee, their manager and HR can see it
+func FindNomination(gctx golly.Context, id uuid.UUID) (Nomination, error) {
+	var nom Nomination
+
+	err := nominationQuery(gctx).
+		First(&nom, "id = ?", id).
+		Error
+
+	if err != nil {
+		return nom, errors.WrapNotFound(err)
+	}
+
+	if !nominationVisible(identity.FromContext(gctx).UID, nom) {
+		if _, err := accounts.RequireHR(gctx); err != nil {
+			return Nomination{}, errors.WrapNotFound(gorm.ErrRecordNotFound)
+		}
+	}
+
+	return nom, nil
+}
+
+// FindNominations lists the nominations of the current user and the ones
+// they have to decide on, all of them for HR, of one cycle when the cycle
+// id is given, most recent first
+func FindNominations(gctx golly.Context, cycleID uuid.UUID) ([]Nomination, error) {
+	var nominations []Nomination
+
+	db := nominationQuery(gctx)
+
+	if cycleID != uuid.Nil {
+		db = db.Where("cycle_id = ?", cycleID)
+	}
+
+	if _, err := accounts.RequireHR(gctx); err != nil {
+		uid := identity.FromContext(gctx).UID
+		db = db.Where("nominator_id = ? OR approver_id = ?", uid, uid)
+	}
+
+	err := db.Order("created_at DESC").Find(&nominations).Error
+	return nominations, errors.WrapGeneric(err)
+}
+
+func nominationVisible(uid uuid.UUID, nom Nomination) bool {
+	return nom.NominatorID == uid || nom.ApproverID == uid
+}
+
+// NominationEmailSubscription lets the manager know about nominations to
+// review and the employee about the decision
+func NominationEmailSubscription(gctx golly.Context, agg eventsource.Aggregate, evt eventsource.Event) error {
+	nom := *agg.(*nomination.Aggregate)
+
+	go func(ctx golly.Context) {
+		ctx = orm.SetDBOnContext(ctx, orm.Connection().Session(&gorm.Session{NewDB: true}))
+
+		params, ok, err := NominationEmail(ctx, nom, evt)
+		if err != nil || !ok {
+			return
+		}
+
+		params.NominationURL = ctx.Config().GetString("app.frontend.url") + "/nominations/" + nom.ID.String()
+
+		if err := mailgun.GetClient(ctx).SendNominationEmail(ctx, params); err != nil {
+			ctx.Logger().Warnf("Unable to send nomination email to %s %v", params.Email, err)
+		}
+	}(gctx)
+
+	return nil
+}
+
+// NominationEmail builds the email sent for a nomination event, without
+// its link, false when the event does not notify anyone
+func NominationEmail(gctx golly.Context, nom nomination.Aggregate, evt eventsource.Event) (mailgun.NominationEmailParams, bool, error) {
+	params := mailgun.NominationEmailParams{
+		Reviewers: nom.Emails,
+		Note:      nom.Note,
+	}
+
+	var recipientID uuid.UUID
+
+	switch evt.Data.(type) {
+	case nomination.Proposed:
+		params.Kind, recipientID = mailgun.NominationProposed, nom.ApproverID
+	case nomination.EmailsUpdated:
+		params.Kind, recipientID = mailgun.NominationUpdated, nom.ApproverID
+	case nomination.Approved:
+		params.Kind, recipientID = mailgun.NominationApproved, nom.NominatorID
+	case nomination.Rejected:
+		params.Kind, recipientID = mailgun.NominationRejected, nom.NominatorID
+	default:
+		return params, false, nil
+	}
+
+	employee, err := employees.Service(gctx).FindEmployeeByID_Unsafe(gctx, nom.EmployeeID)
+	if err != nil {
+		return params, false, err
+	}
+	params.EmployeeName = employee.Name
+
+	recipient, err := accounts.FindUserByID(gctx, recipientID.String(), common.OrganizationIDScope(nom.OrganizationID))
+	if err != nil {
+		return params, false, err
+	}
+
+	params.Name = recipient.FirstName
+	params.Email = recipient.Email
+
+	return params, true, nil
+}
@@ -0,0 +1,236 @@
+package reviews
+
+import (
+	"github.com/golly-go/golly"
+	"github.com/golly-go/plugins/gql"
+	"github.com/graphql-go/graphql"
+	"github.com/mitchrodrigues/talent-review-backend/app/domains/employees"
+	"github.com/mitchrodrigues/talent-review-backend/app/domains/reviews/nomination"
+	"github.com/mitchrodrigues/talent-review-backend/app/utils/helpers"
+	"github.com/mitchrodrigues/talent-review-backend/app/utils/identity"
+)
+
+var (
+	nominationStatusType = graphql.NewEnum(graphql.EnumConfig{
+		Name: "NominationStatus",
+		Values: graphql.EnumValueConfigMap{
+			"proposed": {Value: nomination.StatusProposed},
+			"approved": {Value: nomination.StatusApproved},
+			"rejected": {Value: nomination.StatusRejected},
+		},
+	})
+
+	nominationType = graphql.NewObject(graphql.ObjectConfig{
+		Name: "Nomination",
+		Fields: graphql.Fields{
+			"id": {
+				Type: graphql.NewNonNull(graphql.String),
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					return p.Source.(Nomination).ID, nil
+				},
+			},
+			"cycleID": {
+				Type: graphql.String,
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					return p.Source.(Nomination).CycleID, nil
+				},
+			},
+			"employee": {
+				Type: employees.EmployeeGQLType,
+				Resolve: gql.NewHandler(gql.Options{
+					Handler: func(wctx golly.WebContext, params gql.Params) (interface{}, error) {
+						return employees.Service(wctx.Context).FindEmployeeByID(wctx.Context, params.Source.(Nomination).EmployeeID)
+					},
+				}),
+			},
+			"emails": {
+				Type:        graphql.NewList(graphql.String),
+				Description: "Reviewers nominated, or approved once approved",
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					return []string(p.Source.(Nomination).Emails), nil
+				},
+			},
+			"status": {
+				Type: nominationStatusType,
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					return p.Source.(Nomination).Status, nil
+				},
+			},
+			"note": {
+				Type:        graphql.String,
+				Description: "Left by the manager when deciding",
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					return p.Source.(Nomination).Note, nil
+				},
+			},
+			"feedbackIDs": {
+				Type: graphql.NewList(graphql.String),
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					return p.Source.(Nomination).FeedbackIDs, nil
+				},
+			},
+			"decidedAt": {
+				Type: graphql.DateTime,
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					return p.Source.(Nomination).DecidedAt, nil
+				},
+			},
+			"createdAt": {
+				Type: graphql.DateTime,
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					return p.Source.(Nomination).CreatedAt, nil
+				},
+			},
+		},
+	})
+
+	nominationQueries = graphql.Fields{
+		"nominations": &graphql.Field{
+			Type:        graphql.NewList(nominationType),
+			Description: "Your nominations and the ones you have to decide on, all of them for HR",
+			Args: graphql.FieldConfigArgument{
+				"cycleID": {Type: graphql.String},
+			},
+			Resolve: gql.NewHandler(gql.Options{
+				Handler: func(wctx golly.WebContext, params gql.Params) (interface{}, error) {
+					cycleID, err := helpers.ExtractAndParseUUID(params.Args, "cycleID")
+					if err != nil {
+						return nil, err
+					}
+
+					return FindNominations(wctx.Context, cycleID)
+				},
+			}),
+		},
+		"nomination": &graphql.Field{
+			Type: nominationType,
+			Args: graphql.FieldConfigArgument{
+				"id": {Type: graphql.NewNonNull(graphql.String)},
+			},
+			Resolve: gql.NewHandler(gql.Options{
+				Handler: func(wctx golly.WebContext, params gql.Params) (interface{}, error) {
+					id, err := helpers.ExtractAndParseUUID(params.Args, "id")
+					if err != nil {
+						return nil, err
+					}
+
+					return FindNomination(wctx.Context, id)
+				},
+			}),
+		},
+	}
+
+	nominationMutations = graphql.Fields{
+		"nominateReviewers": &graphql.Field{
+			Type:        nominationType,
+			Description: "Nominate who should give you feedback for a cycle, your manager approves them",
+			Args: graphql.FieldConfigArgument{
+				"cycleID": &graphql.ArgumentConfig{Type: graphql.NewNonNull(graphql.String)},
+				"emails":  &graphql.ArgumentConfig{Type: graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(graphql.String)))},
+			},
+			Resolve: gql.NewHandler(gql.Options{
+				Handler: func(wctx golly.WebContext, params gql.Params) (interface{}, error) {
+					cycleID, err := helpers.ExtractAndParseUUID(params.Args, "cycleID")
+					if err != nil {
+						return nil, err
+					}
+
+					return NominateReviewers(wctx.Context, NominateReviewersInput{
+						CycleID: cycleID,
+						Emails:  nominationEmails(params.Args["emails"]),
+					}, params.Metadata())
+				},
+			}),
+		},
+		"reviseNomination": &graphql.Field{
+			Type: nominationType,
+			Args: graphql.FieldConfigArgument{
+				"id":     &graphql.ArgumentConfig{Type: graphql.NewNonNull(graphql.String)},
+				"emails": &graphql.ArgumentConfig{Type: graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(graphql.String)))},
+			},
+			Resolve: gql.NewHandler(gql.Options{
+				Handler: func(wctx golly.WebContext, params gql.Params) (interface{}, error) {
+					id, err := helpers.ExtractAndParseUUID(params.Args, "id")
+					if err != nil {
+						return nil, err
+					}
+
+					return ReviseNomination(wctx.Context, id, nominationEmails(params.Args["emails"]), params.Metadata())
+				},
+			}),
+		},
+		"approveNomination": &graphql.Field{
+			Type:        nominationType,
+			Description: "Approve the reviewers nominated by one of your reports and request their feedback, emails replaces the nominated reviewers",
+			Args: graphql.FieldConfigArgument{
+				"id":     &graphql.ArgumentConfig{Type: graphql.NewNonNull(graphql.String)},
+				"emails": &graphql.ArgumentConfig{Type: graphql.NewList(graphql.NewNonNull(graphql.String))},
+				"note":   &graphql.ArgumentConfig{Type: graphql.String},
+			},
+			Resolve: gql.NewHandler(gql.Options{
+				Handler: func(wctx golly.WebContext, params gql.Params) (interface{}, error) {
+					id, err := helpers.ExtractAndParseUUID(params.Args, "id")
+					if err != nil {
+						return nil, err
+					}
+
+					note, _ := helpers.ExtractArg[string](params.Args, "note")
+
+					return ApproveNomination(wctx.Context, id, nominationEmails(params.Args["emails"]), note, params.Metadata())
+				},
+			}),
+		},
+		"requestNominationFeedback": &graphql.Field{
+			Type:        nominationType,
+			Description: "Request feedback from the approved reviewers of a nomination that were not asked yet",
+			Args: graphql.FieldConfigArgument{
+				"id": &graphql.ArgumentConfig{Type: graphql.NewNonNull(graphql.String)},
+			},
+			Resolve: gql.NewHandler(gql.Options{
+				Handler: func(wctx golly.WebContext, params gql.Params) (interface{}, error) {
+					id, err := helpers.ExtractAndParseUUID(params.Args, "id")
+					if err != nil {
+						return nil, err
+					}
+
+					return RequestNominationFeedback(wctx.Context, id, params.Metadata())
+				},
+			}),
+		},
+		"rejectNomination": &graphql.Field{
+			Type: nominationType,
+			Args: graphql.FieldConfigArgument{
+				"id":   &graphql.ArgumentConfig{Type: graphql.NewNonNull(graphql.String)},
+				"note": &graphql.ArgumentConfig{Type: graphql.String},
+			},
+			Resolve: gql.NewHandler(gql.Options{
+				Handler: func(wctx golly.WebContext, params gql.Params) (interface{}, error) {
+					id, err := helpers.ExtractAndParseUUID(params.Args, "id")
+					if err != nil {
+						return nil, err
+					}
+
+					note, _ := helpers.ExtractArg[string](params.Args, "note")
+
+					return UpdateNomination(wctx.Context, id, nomination.Reject{
+						UserID: identity.FromContext(wctx.Context).UID,
+						Note:   note,
+					}, params.Metadata())
+				},
+			}),
+		},
+	}
+)
+
+// nominationEmails reads a list of emails argument, nil when it was not
+// given
+func nominationEmails(arg interface{}) []string {
+	list, ok := arg.([]interface{})
+	if !ok {
+		return nil
+	}
+
+	return golly.Map(list, func(email interface{}) string {
+		return email.(string)
+	})
+}
@@ -0,0 +1,172 @@
+package reviews
+
+import (
+	"context"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/golly-go/golly"
+	"github.com/golly-go/plugins/eventsource"
+	"github.com/golly-go/plugins/orm"
+	"github.com/google/uuid"
+	"github.com/mitchrodrigues/talent-review-backend/app/domains/accounts"
+	"github.com/mitchrodrigues/talent-review-backend/app/domains/accounts/users"
+	"github.com/mitchrodrigues/talent-review-backend/app/domains/employees"
+	"github.com/mitchrodrigues/talent-review-backend/app/domains/reviews/cycle"
+	"github.com/mitchrodrigues/talent-review-backend/app/domains/reviews/nomination"
+	"github.com/mitchrodrigues/talent-review-backend/app/utils/esbackend"
+	"github.com/mitchrodrigues/talent-review-backend/app/utils/identity"
+	"github.com/mitchrodrigues/talent-review-backend/app/utils/mailgun"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNominationWorkflow(t *testing.T) {
+	gctx := orm.CreateTestContext(golly.NewContext(context.TODO()),
+		Nomination{}, Feedback{}, Cycle{}, employees.Employee{}, accounts.User{}, esbackend.Event{})
+
+	organizationID := uuid.New()
+
+	createUser := func(firstName string) accounts.User {
+		user := accounts.User{Aggregate: users.Aggregate{OrganizationID: organizationID, Email: firstName + "@example.com", FirstName: firstName, Role: users.RoleMember}}
+		orm.DB(gctx).Create(&user)
+		return user
+	}
+
+	managerUser := createUser("morgan")
+	reportUser := createUser("jane")
+	otherUser := createUser("sam")
+
+	manager := employees.NewTestEmployee(uuid.New(), organizationID, "morgan@example.com", &managerUser.ID)
+	orm.DB(gctx).Create(&manager)
+
+	report := employees.NewTestEmployee(uuid.New(), organizationID, "jane@example.com", &reportUser.ID)
+	report.Name = "Jane"
+	report.ManagerID = &manager.ID
+	orm.DB(gctx).Create(&report)
+
+	other := employees.NewTestEmployee(uuid.New(), organizationID, "sam@example.com", &otherUser.ID)
+	orm.DB(gctx).Create(&other)
+
+	h1 := Cycle{Aggregate: cycle.Aggregate{
+		OrganizationID: organizationID,
+		StartAt:        time.Now().AddDate(0, -1, 0),
+		EndAt:          time.Now().AddDate(0, 1, 0),
+	}}
+	orm.DB(gctx).Create(&h1)
+
+	// identities are set on the shared context so each call sets its own
+	as := func(userID, employeeID uuid.UUID) golly.Context {
+		return identity.ToContext(gctx, identity.Identity{UID: userID, OrganizationID: organizationID, EmployeeID: employeeID})
+	}
+	asManager := func() golly.Context { return as(managerUser.ID, manager.ID) }
+	asReport := func() golly.Context { return as(reportUser.ID, report.ID) }
+
+	input := NominateReviewersInput{CycleID: h1.ID, Emails: []string{"peer@example.com", "lead@example.com"}}
+
+	nom, err := NominateReviewers(asReport(), input, eventsource.Metadata{})
+	assert.NoError(t, err)
+	assert.Equal(t, managerUser.ID, nom.ApproverID)
+	assert.Equal(t, nomination.StatusProposed, nom.Status)
+
+	t.Run("one nomination per cycle", func(t *testing.T) {
+		_, err := NominateReviewers(asReport(), input, eventsource.Metadata{})
+		assert.ErrorContains(t, err, ErrorAlreadyNominated.Error())
+	})
+
+	t.Run("employees without a manager cannot nominate", func(t *testing.T) {
+		_, err := NominateReviewers(as(otherUser.ID, other.ID), input, eventsource.Metadata{})
+		assert.ErrorContains(t, err, nomination.ErrorApproverRequired.Error())
+	})
+
+	t.Run("the manager is emailed", func(t *testing.T) {
+		params, ok, err := NominationEmail(gctx, nom.Aggregate, eventsource.Event{Data: nomination.Proposed{}})
+		assert.NoError(t, err)
+		assert.True(t, ok)
+
+		assert.Equal(t, mailgun.NominationProposed, params.Kind)
+		assert.Equal(t, "morgan@example.com", params.Email)
+		assert.Equal(t, "Jane", params.EmployeeName)
+		assert.Equal(t, []string{"peer@example.com", "lead@example.com"}, params.Reviewers)
+
+		_, ok, _ = NominationEmail(gctx, nom.Aggregate, eventsource.Event{Data: nomination.FeedbackRequested{}})
+		assert.False(t, ok)
+	})
+
+	t.Run("only the employee and their manager see it", func(t *testing.T) {
+		_, err := FindNomination(as(otherUser.ID, other.ID), nom.ID)
+		assert.Error(t, err)
+
+		nominations, err := FindNominations(asManager(), h1.ID)
+		assert.NoError(t, err)
+		assert.Len(t, nominations, 1)
+
+		nominations, err = FindNominations(as(otherUser.ID, other.ID), uuid.Nil)
+		assert.NoError(t, err)
+		assert.Empty(t, nominations)
+	})
+
+	t.Run("the employee cannot nominate themselves", func(t *testing.T) {
+		_, err := ReviseNomination(asReport(), nom.ID, []string{"Jane@example.com"}, eventsource.Metadata{})
+		assert.ErrorContains(t, err, nomination.ErrorSelfNominated.Error())
+	})
+
+	t.Run("only the manager approves", func(t *testing.T) {
+		_, err := ApproveNomination(asReport(), nom.ID, nil, "", eventsource.Metadata{})
+		assert.ErrorContains(t, err, nomination.ErrorNotApprover.Error())
+	})
+
+	t.Run("approval requests feedback from the edited reviewers", func(t *testing.T) {
+		approved, err := ApproveNomination(asManager(), nom.ID, []string{"peer@example.com", "skip@example.com"}, "Swapped in skip", eventsource.Metadata{})
+		assert.NoError(t, err)
+
+		assert.Equal(t, nomination.StatusApproved, approved.Status)
+		assert.Len(t, approved.FeedbackIDs, 2)
+
+		var requested []Feedback
+		orm.DB(gctx).Where("id IN ?", []uuid.UUID(approved.FeedbackIDs)).Find(&requested)
+
+		if assert.Len(t, requested, 2) {
+			for _, fb := range requested {
+				assert.Equal(t, report.ID, fb.EmployeeID)
+				assert.Equal(t, managerUser.ID, fb.OwnerID)
+				assert.WithinDuration(t, h1.EndAt, fb.CollectionEndAt, time.Second)
+			}
+		}
+
+		params, ok, err := NominationEmail(gctx, approved.Aggregate, eventsource.Event{Data: nomination.Approved{}})
+		assert.NoError(t, err)
+		assert.True(t, ok)
+		assert.Equal(t, "jane@example.com", params.Email)
+		assert.Equal(t, "Swapped in skip", params.Note)
+
+		_, err = UpdateNomination(asManager(), nom.ID, nomination.Reject{UserID: managerUser.ID}, eventsource.Metadata{})
+		assert.ErrorContains(t, err, nomination.ErrorNotOpen.Error())
+	})
+
+	t.Run("requests left out by a failed approval are retried", func(t *testing.T) {
+		_, err := RequestNominationFeedback(asReport(), nom.ID, eventsource.Metadata{})
+		assert.ErrorContains(t, err, nomination.ErrorNotApprover.Error())
+
+		// nothing left to request
+		retried, err := RequestNominationFeedback(asManager(), nom.ID, eventsource.Metadata{})
+		assert.NoError(t, err)
+		assert.Len(t, retried.FeedbackIDs, 2)
+
+		// the request to skip@ was never made
+		var skipped Feedback
+		orm.DB(gctx).Where("id IN ? AND email = ?", []uuid.UUID(retried.FeedbackIDs), "skip@example.com").First(&skipped)
+		orm.DB(gctx).Delete(&skipped)
+
+		remaining := slices.DeleteFunc(slices.Clone(retried.FeedbackIDs), func(id uuid.UUID) bool { return id == skipped.ID })
+		orm.DB(gctx).Model(&Nomination{}).Where("id = ?", nom.ID).Update("feedback_ids", remaining)
+
+		retried, err = RequestNominationFeedback(asManager(), nom.ID, eventsource.Metadata{})
+		assert.NoError(t, err)
+		assert.Len(t, retried.FeedbackIDs, 2)
+
+		var emails []string
+		orm.DB(gctx).Model(&Feedback{}).Where("id IN ?", []uuid.UUID(retried.FeedbackIDs)).Pluck("email", &emails)
+		assert.ElementsMatch(t, []string{"peer@example.com", "skip@example.com"}, emails)
+	})
+}
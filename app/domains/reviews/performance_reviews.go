@@ -0,0 +1,256 @@
+package reviews
+
+import (
+	"fmt"
+
+	"github.com/golly-go/golly"
+	"github.com/golly-go/golly/errors"
+	"github.com/golly-go/plugins/eventsource"
+	"github.com/golly-go/plugins/orm"
+	"github.com/google/uuid"
+	"github.com/mitchrodrigues/talent-review-backend/app/domains/common"
+	"github.com/mitchrodrigues/talent-review-backend/app/domains/employees"
+	"github.com/mitchrodrigues/talent-review-backend/app/domains/reviews/review"
+	"github.com/mitchrodrigues/talent-review-backend/app/domains/tara"
+	"github.com/mitchrodrigues/talent-review-backend/app/utils/identity"
+	"github.com/mitchrodrigues/talent-review-backend/app/utils/wsyiwig"
+	"gorm.io/gorm"
+)
+
+const (
+	reviewFeedbackLimit    = 30
+	reviewPriorReviewLimit = 3
+)
+
+var (
+	ErrorNotReviewManager = fmt.Errorf("you can only review employees you manage")
+)
+
+type DraftPerformanceReviewInput struct {
+	EmployeeID uuid.UUID
+
+	// CycleID limits the feedback drafted from to the cycle, all
+	// submitted feedback is used when it is nil
+	CycleID *uuid.UUID
+}
+
+// DraftPerformanceReview has tara draft the review of an employee the
+// current user manages and stores it as a draft the manager revises
+func DraftPerformanceReview(gctx golly.Context, input DraftPerformanceReviewInput, metadata eventsource.Metadata) (PerformanceReview, error) {
+	var perfReview PerformanceReview
+
+	ident := identity.FromContext(gctx)
+
+	reports, err := employees.Service(gctx).FindEmployeesByManagerAndIDS(gctx, ident.EmployeeID, input.EmployeeID)
+	if err != nil || len(reports) == 0 {
+		return perfReview, errors.WrapForbidden(ErrorNotReviewManager)
+	}
+
+	employee := reports[0]
+
+	scopes := []func(*gorm.DB) *gorm.DB{}
+
+	if input.CycleID != nil {
+		var cycle Cycle
+
+		err := orm.DB(gctx).
+			Model(&Cycle{}).
+			Scopes(common.OrganizationIDScopeForContext(gctx)).
+			First(&cycle, "id = ?", *input.CycleID).
+			Error
+
+		if err != nil {
+			return perfReview, errors.WrapNotFound(err)
+		}
+
+		scopes = append(scopes, func(db *gorm.DB) *gorm.DB {
+			return db.Where("feedbacks.submitted_at BETWEEN ? AND ?", cycle.StartAt, cycle.EndAt)
+		})
+	}
+
+	role, err := employees.Service(gctx).FindRoleByID(gctx, employee.EmployeeRoleID)
+	if err != nil {
+		return perfReview, errors.WrapGeneric(err)
+	}
+
+	expectations, err := employees.ExpectationsForRole(gctx, role)
+	if err != nil {
+		return perfReview, err
+	}
+
+	sources, err := ReviewSources(gctx, employee.ID, scopes...)
+	if err != nil {
+		return perfReview, err
+	}
+
+	priorReviews, err := FindPriorPerformanceReviews(gctx, employee.ID)
+	if err != nil {
+		return perfReview, err
+	}
+
+	draft, err := tara.DraftReview(gctx, tara.GenerateOptions{
+		Redactor: FeedbackRedactor(gctx, ident.OrganizationID),
+	}, tara.ReviewDraftInput{
+		Employee:     employee.Name,
+		Role:         roleDescription(role),
+		Expectations: expectations.Strings(),
+		Competencies: golly.Map(expectations.Current, func(e employees.Expectation) string { return e.Competency }),
+		Sources:      sources,
+		PriorReviews: golly.Map(priorReviews, func(prior PerformanceReview) tara.PriorReview {
+			return tara.PriorReview{Date: *prior.FinalizedAt, Rating: prior.Rating, Summary: prior.Summary}
+		}),
+	})
+
+	if err != nil {
+		return perfReview, err
+	}
+
+	err = eventsource.Call(gctx, &perfReview.Aggregate, review.Draft{
+		OrganizationID: ident.OrganizationID,
+		EmployeeID:     employee.ID,
+		AuthorID:       ident.UID,
+		CycleID:        input.CycleID,
+		Summary:        draft.Summary,
+		Sections: golly.Map(draft.Sections, func(section tara.DraftedSection) review.Section {
+			return review.Section{
+				Competency: section.Competency,
+				Content:    section.Content,
+				Citations:  section.Citations,
+			}
+		}),
+		SuggestedRating:          draft.Rating,
+		SuggestedRatingRationale: draft.RatingRationale,
+		PromptVersions:           draft.PromptVersions,
+	}, metadata)
+
+	return perfReview, err
+}
+
+// UpdatePerformanceReview runs a command (revise or finalize) on a review
+// of the current user
+func UpdatePerformanceReview(gctx golly.Context, id uuid.UUID, cmd eventsource.Command, metadata eventsource.Metadata) (PerformanceReview, error) {
+	perfReview, err := FindPerformanceReview(gctx, id)
+	if err != nil {
+		return perfReview, err
+	}
+
+	err = eventsource.Call(gctx, &perfReview.Aggregate, cmd, metadata)
+	return perfReview, err
+}
+
+// ReviewSources are the submitted feedback of the employee and its
+// summaries the current user may read, the most recent first
+func ReviewSources(gctx golly.Context, employeeID uuid.UUID, scopes ...func(*gorm.DB) *gorm.DB) ([]tara.Source, error) {
+	var feedbacks []Feedback
+
+	err := orm.DB(gctx).
+		Model(&Feedback{}).
+		Select("feedbacks.*").
+		Preload("Details").
+		Preload("Summary").
+		Scopes(common.JoinUserEmployeeRecord(gctx)).
+		Where("feedbacks.organization_id = ? AND feedbacks.employee_id = ?", identity.FromContext(gctx).OrganizationID, employeeID).
+		Where("feedbacks.submitted_at IS NOT NULL").
+		Scopes(AggregatedContentScope(gctx)).
+		Scopes(scopes...).
+		Order("feedbacks.submitted_at DESC").
+		Limit(reviewFeedbackLimit).
+		Find(&feedbacks).
+		Error
+
+	if err != nil {
+		return nil, errors.WrapGeneric(err)
+	}
+
+	var sources []tara.Source
+
+	for _, fb := range feedbacks {
+		for _, section := range []struct{ name, content string }{
+			{"strengths", fb.Details.Strengths},
+			{"opportunities", fb.Details.Opportunities},
+			{"additional", fb.Details.Additional},
+		} {
+			text, _ := wsyiwig.ExtractTextFromJSON(section.content)
+			if text == "" {
+				continue
+			}
+
+			sources = append(sources, tara.Source{
+				ID:      fb.ID,
+				Kind:    "feedback",
+				Section: section.name,
+				Date:    *fb.SubmittedAt,
+				Content: text,
+			})
+		}
+
+		if fb.Summary.Summary != "" {
+			sources = append(sources, tara.Source{
+				ID:      fb.ID,
+				Kind:    "summary",
+				Date:    *fb.SubmittedAt,
+				Content: fb.Summary.Summary,
+			})
+		}
+	}
+
+	return sources, nil
+}
+
+func performanceReviewQuery(gctx golly.Context) *gorm.DB {
+	ident := identity.FromContext(gctx)
+
+	return orm.DB(gctx).
+		Model(&PerformanceReview{}).
+		Where("organization_id = ? AND author_id = ?", ident.OrganizationID, ident.UID)
+}
+
+// FindPerformanceReview finds a review written by the current user
+func FindPerformanceReview(gctx golly.Context, id uuid.UUID) (PerformanceReview, error) {
+	var perfReview PerformanceReview
+
+	err := performanceReviewQuery(gctx).
+		First(&perfReview, "id = ?", id).
+		Error
+
+	return perfReview, errors.WrapNotFound(err)
+}
+
+// FindPerformanceReviews lists the reviews the current user wrote for an
+// employee, most recent first
+func FindPerformanceReviews(gctx golly.Context, employeeID uuid.UUID) ([]PerformanceReview, error) {
+	var perfReviews []PerformanceReview
+
+	err := performanceReviewQuery(gctx).
+		Where("employee_id = ?", employeeID).
+		Order("created_at DESC").
+		Find(&perfReviews).
+		Error
+
+	return perfReviews, errors.WrapGeneric(err)
+}
+
+// FindPriorPerformanceReviews finds the latest finalized reviews of the
+// employee whoever wrote them
+func FindPriorPerformanceReviews(gctx golly.Context, employeeID uuid.UUID) ([]PerformanceReview, error) {
+	var perfReviews []PerformanceReview
+
+	err := orm.DB(gctx).
+		Model(&PerformanceReview{}).
+		Where("organization_id = ? AND employee_id = ?", identity.FromContext(gctx).OrganizationID, employeeID).
+		Where("status = ?", review.StatusFinalized).
+		Order("finalized_at DESC").
+		Limit(reviewPriorReviewLimit).
+		Find(&perfReviews).
+		Error
+
+	return perfReviews, errors.WrapGeneric(err)
+}
+
+func roleDescription(role employees.EmployeeRole) string {
+	if role.ID == uuid.Nil {
+		return "unknown"
+	}
+
+	return fmt.Sprintf("%s, level %d, %s track", role.Title, role.Level, role.Track)
+}
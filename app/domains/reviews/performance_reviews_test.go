@@ -0,0 +1,168 @@
+package reviews
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/golly-go/golly"
+	"github.com/golly-go/plugins/eventsource"
+	"github.com/golly-go/plugins/orm"
+	"github.com/google/uuid"
+	"github.com/mitchrodrigues/talent-review-backend/app/domains/accounts"
+	"github.com/mitchrodrigues/talent-review-backend/app/domains/employees"
+	"github.com/mitchrodrigues/talent-review-backend/app/domains/reviews/cycle"
+	"github.com/mitchrodrigues/talent-review-backend/app/domains/reviews/feedback"
+	"github.com/mitchrodrigues/talent-review-backend/app/domains/reviews/review"
+	"github.com/mitchrodrigues/talent-review-backend/app/domains/tara"
+	"github.com/mitchrodrigues/talent-review-backend/app/utils/esbackend"
+	"github.com/mitchrodrigues/talent-review-backend/app/utils/identity"
+	"github.com/mitchrodrigues/talent-review-backend/app/utils/openai"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDraftPerformanceReview(t *testing.T) {
+	gctx := orm.CreateTestContext(golly.NewContext(context.TODO()),
+		Feedback{},
+		FeedbackDetails{},
+		FeedbackSummary{},
+		PerformanceReview{},
+		Cycle{},
+		accounts.Organization{},
+		employees.Employee{},
+		employees.EmployeeRole{},
+		tara.Prompt{},
+		esbackend.Event{})
+
+	organizationID := uuid.New()
+	managerUserID := uuid.New()
+
+	manager := employees.NewTestEmployee(uuid.New(), organizationID, "manager@example.com", &managerUserID)
+	orm.DB(gctx).Create(&manager)
+
+	report := employees.NewTestEmployee(uuid.New(), organizationID, "report@example.com", nil)
+	report.ManagerID = &manager.ID
+	orm.DB(gctx).Create(&report)
+
+	other := employees.NewTestEmployee(uuid.New(), organizationID, "other@example.com", nil)
+	orm.DB(gctx).Create(&other)
+
+	visible := NewTestSubmittedFeedback(uuid.New(), organizationID, report.ID, "reviewer@example.com")
+	orm.DB(gctx).Create(&visible)
+
+	details := NewTestFeedbackDetails(visible, tiptapDocument("Runs great planning meetings."), tiptapDocument("Could delegate more often."))
+	orm.DB(gctx).Create(&details)
+
+	otherOrg := NewTestSubmittedFeedback(uuid.New(), uuid.New(), report.ID, "reviewer@example.com")
+	orm.DB(gctx).Create(&otherOrg)
+
+	ctx := openai.UseProvider(identity.ToContext(gctx, identity.Identity{
+		UID:            managerUserID,
+		OrganizationID: organizationID,
+		EmployeeID:     manager.ID,
+	}), &openai.FixtureProvider{
+		Default: fmt.Sprintf(`{
+			"summary":"A solid period.",
+			"sections":[{"competency":"Execution","content":"You delegate less than you could.","citations":["%s","%s"]}],
+			"rating":3,
+			"rating_rationale":"Meets expectations."
+		}`, visible.ID, otherOrg.ID),
+	})
+
+	t.Run("not a report of the user", func(t *testing.T) {
+		_, err := DraftPerformanceReview(ctx, DraftPerformanceReviewInput{EmployeeID: other.ID}, eventsource.Metadata{})
+		assert.ErrorContains(t, err, ErrorNotReviewManager.Error())
+	})
+
+	var reviewID uuid.UUID
+
+	t.Run("drafts a review", func(t *testing.T) {
+		perfReview, err := DraftPerformanceReview(ctx, DraftPerformanceReviewInput{EmployeeID: report.ID}, eventsource.Metadata{})
+		assert.NoError(t, err)
+
+		reviewID = perfReview.ID
+
+		assert.Equal(t, review.StatusDraft, perfReview.Status)
+		assert.Equal(t, "A solid period.", perfReview.Summary)
+		assert.Equal(t, 3, perfReview.Rating)
+		assert.Equal(t, 3, perfReview.SuggestedRating)
+		assert.Contains(t, perfReview.PromptVersions, "ReviewDraftPrompt")
+
+		if assert.Len(t, perfReview.Sections, 1) {
+			// feedback of another organization is never cited
+			assert.Equal(t, []uuid.UUID{visible.ID}, perfReview.Sections[0].Citations)
+		}
+	})
+
+	t.Run("revise and finalize", func(t *testing.T) {
+		rating := 4
+
+		perfReview, err := UpdatePerformanceReview(ctx, reviewID, review.Revise{Rating: &rating}, eventsource.Metadata{})
+		assert.NoError(t, err)
+		assert.Equal(t, 4, perfReview.Rating)
+		assert.Equal(t, "A solid period.", perfReview.Summary)
+
+		_, err = UpdatePerformanceReview(ctx, reviewID, review.Finalize{}, eventsource.Metadata{})
+		assert.NoError(t, err)
+
+		_, err = UpdatePerformanceReview(ctx, reviewID, review.Revise{Rating: &rating}, eventsource.Metadata{})
+		assert.ErrorContains(t, err, review.ErrorAlreadyFinalized.Error())
+
+		prior, err := FindPriorPerformanceReviews(ctx, report.ID)
+		assert.NoError(t, err)
+		assert.Len(t, prior, 1)
+	})
+
+	t.Run("only the author finds the review", func(t *testing.T) {
+		reviews, err := FindPerformanceReviews(ctx, report.ID)
+		assert.NoError(t, err)
+		assert.Len(t, reviews, 1)
+
+		ident := identity.FromContext(ctx)
+		ident.UID = uuid.New()
+
+		_, err = FindPerformanceReview(identity.ToContext(ctx, ident), reviewID)
+		assert.Error(t, err)
+	})
+
+	t.Run("cycle limits the feedback", func(t *testing.T) {
+		past := Cycle{Aggregate: cycle.Aggregate{
+			OrganizationID: identity.FromContext(ctx).OrganizationID,
+			StartAt:        time.Now().AddDate(-1, 0, 0),
+			EndAt:          time.Now().AddDate(0, -6, 0),
+		}}
+		orm.DB(ctx).Create(&past)
+
+		sources, err := ReviewSources(ctx, report.ID)
+		assert.NoError(t, err)
+		assert.Len(t, sources, 2)
+
+		perfReview, err := DraftPerformanceReview(ctx, DraftPerformanceReviewInput{
+			EmployeeID: report.ID,
+			CycleID:    &past.ID,
+		}, eventsource.Metadata{})
+
+		assert.NoError(t, err)
+		assert.Equal(t, &past.ID, perfReview.CycleID)
+		assert.Empty(t, perfReview.Sections[0].Citations)
+	})
+	t.Run("aggregated feedback below the threshold is left out", func(t *testing.T) {
+		// alone in its round so its content is held back from the manager
+		aggregated := NewTestSubmittedFeedback(uuid.New(), organizationID, report.ID, "aggregated@example.com")
+		aggregated.CollectionEndAt = time.Now().Add(48 * time.Hour).Truncate(time.Second)
+		aggregated.Visibility = feedback.VisibilityAggregated
+		orm.DB(gctx).Create(&aggregated)
+
+		details := NewTestFeedbackDetails(aggregated, tiptapDocument("Only one peer said this."), "")
+		orm.DB(gctx).Create(&details)
+
+		sources, err := ReviewSources(ctx, report.ID)
+		assert.NoError(t, err)
+		assert.Len(t, sources, 2)
+
+		for _, source := range sources {
+			assert.NotEqual(t, aggregated.ID, source.ID)
+		}
+	})
+}
@@ -0,0 +1,77 @@
+package reviews
+
+import (
+	"github.com/golly-go/golly"
+	"github.com/golly-go/golly/errors"
+	"github.com/golly-go/plugins/eventsource"
+	"github.com/google/uuid"
+	"github.com/mitchrodrigues/talent-review-backend/app/domains/employees"
+	"github.com/mitchrodrigues/talent-review-backend/app/domains/employees/promotion"
+	"github.com/mitchrodrigues/talent-review-backend/app/domains/tara"
+	"github.com/mitchrodrigues/talent-review-backend/app/utils/identity"
+)
+
+// DraftPromotionJustification has tara draft the justification of an
+// open promotion from the feedback of the employee, measured against the
+// expectations of the target role. Only the proposer can draft it
+func DraftPromotionJustification(gctx golly.Context, id uuid.UUID, metadata eventsource.Metadata) (employees.Promotion, error) {
+	ident := identity.FromContext(gctx)
+
+	p, err := employees.FindPromotion(gctx, id)
+	if err != nil {
+		return p, err
+	}
+
+	if p.ProposerID != ident.UID {
+		return p, errors.WrapForbidden(promotion.ErrorNotProposer)
+	}
+
+	if !p.Open() {
+		return p, errors.WrapUnprocessable(promotion.ErrorNotOpen)
+	}
+
+	employee, err := employees.Service(gctx).FindEmployeeByID(gctx, p.EmployeeID)
+	if err != nil {
+		return p, err
+	}
+
+	currentRole, err := employees.Service(gctx).FindRoleByID(gctx, p.CurrentRoleID)
+	if err != nil {
+		return p, errors.WrapGeneric(err)
+	}
+
+	targetRole, err := employees.Service(gctx).FindRoleByID(gctx, p.TargetRoleID)
+	if err != nil {
+		return p, errors.WrapGeneric(err)
+	}
+
+	expectations, err := employees.ExpectationsForRole(gctx, targetRole)
+	if err != nil {
+		return p, err
+	}
+
+	sources, err := ReviewSources(gctx, employee.ID)
+	if err != nil {
+		return p, err
+	}
+
+	justification, err := tara.DraftPromotionJustification(gctx, tara.GenerateOptions{
+		Redactor: FeedbackRedactor(gctx, ident.OrganizationID),
+	}, tara.PromotionJustificationInput{
+		Employee:     employee.Name,
+		CurrentRole:  roleDescription(currentRole),
+		TargetRole:   roleDescription(targetRole),
+		Expectations: golly.Map(expectations.Current, employees.Expectation.String),
+		Sources:      sources,
+	})
+
+	if err != nil {
+		return p, err
+	}
+
+	return employees.UpdatePromotion(gctx, p.ID, promotion.DraftJustification{
+		Justification:  justification.Justification,
+		Citations:      justification.Citations,
+		PromptVersions: justification.PromptVersions,
+	}, metadata)
+}
@@ -0,0 +1,112 @@
+package release
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/golly-go/golly"
+	"github.com/golly-go/plugins/eventsource"
+	"github.com/golly-go/plugins/orm"
+	"github.com/google/uuid"
+	"github.com/mitchrodrigues/talent-review-backend/app/utils/esbackend"
+)
+
+// Aggregate is the feedback a manager shared with the employee it is
+// about: the summaries and action items they picked and a personal note
+type Aggregate struct {
+	eventsource.AggregateBase
+
+	orm.ModelUUID
+
+	OrganizationID uuid.UUID
+	EmployeeID     uuid.UUID
+
+	// ManagerID is the user who released the feedback
+	ManagerID uuid.UUID
+
+	// FeedbackIDs are the feedback whose summaries are shared
+	FeedbackIDs UUIDs   `gorm:"type:jsonb"`
+	ActionItems Strings `gorm:"type:jsonb"`
+	Note        string
+
+	// AcknowledgedBy is the user of the employee once they acknowledged
+	AcknowledgedBy *uuid.UUID
+	AcknowledgedAt *time.Time
+}
+
+func (*Aggregate) Topic() string                             { return "events.feedback_releases" }
+func (*Aggregate) Repo(golly.Context) eventsource.Repository { return esbackend.PostgresRepository{} }
+func (*Aggregate) TableName() string                         { return "feedback_releases" }
+
+func (release *Aggregate) GetID() string   { return release.ID.String() }
+func (release *Aggregate) SetID(id string) { release.ID, _ = uuid.Parse(id) }
+
+func (release *Aggregate) Apply(ctx golly.Context, evt eventsource.Event) {
+	switch event := evt.Data.(type) {
+	case Released:
+		release.ID = event.ID
+		release.OrganizationID = event.OrganizationID
+		release.EmployeeID = event.EmployeeID
+		release.ManagerID = event.ManagerID
+		release.FeedbackIDs = event.FeedbackIDs
+		release.ActionItems = event.ActionItems
+		release.Note = event.Note
+
+		release.CreatedAt = evt.CreatedAt
+
+	case Acknowledged:
+		release.AcknowledgedBy = &event.UserID
+		release.AcknowledgedAt = &evt.CreatedAt
+	}
+	release.UpdatedAt = evt.CreatedAt
+}
+
+func (release *Aggregate) Acknowledged() bool { return release.AcknowledgedAt != nil }
+
+// Strings are stored as a jsonb array
+type Strings []string
+
+func (s Strings) Value() (driver.Value, error) {
+	if s == nil {
+		return []byte("[]"), nil
+	}
+	return json.Marshal(s)
+}
+
+func (s *Strings) Scan(value interface{}) error {
+	switch v := value.(type) {
+	case nil:
+		return nil
+	case []byte:
+		return json.Unmarshal(v, s)
+	case string:
+		return json.Unmarshal([]byte(v), s)
+	}
+	return fmt.Errorf("cannot scan %T into strings", value)
+}
+
+// UUIDs are stored as a jsonb array
+type UUIDs []uuid.UUID
+
+func (u UUIDs) Value() (driver.Value, error) {
+	if u == nil {
+		return []byte("[]"), nil
+	}
+	return json.Marshal(u)
+}
+
+func (u *UUIDs) Scan(value interface{}) error {
+	switch v := value.(type) {
+	case nil:
+		return nil
+	case []byte:
+		return json.Unmarshal(v, u)
+	case string:
+		return json.Unmarshal([]byte(v), u)
+	}
+	return fmt.Errorf("cannot scan %T into uuids", value)
+}
+
+var _ eventsource.Aggregate = &Aggregate{}
@@ -0,0 +1,87 @@
+package release
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/golly-go/golly"
+	"github.com/golly-go/golly/errors"
+	"github.com/golly-go/plugins/eventsource"
+	"github.com/google/uuid"
+)
+
+const (
+	MaxNoteLength = 2000
+)
+
+var (
+	ErrorEmployeeRequired    = fmt.Errorf("release employee is required")
+	ErrorManagerRequired     = fmt.Errorf("release manager is required")
+	ErrorSummariesRequired   = fmt.Errorf("share at least one feedback summary")
+	ErrorNoteTooLong         = fmt.Errorf("note must be at most %d characters", MaxNoteLength)
+	ErrorNotEmployee         = fmt.Errorf("only the employee can acknowledge their feedback")
+	ErrorAlreadyAcknowledged = fmt.Errorf("feedback has already been acknowledged")
+)
+
+type Release struct {
+	OrganizationID uuid.UUID
+	EmployeeID     uuid.UUID
+	ManagerID      uuid.UUID
+
+	FeedbackIDs []uuid.UUID
+	ActionItems []string
+	Note        string
+}
+
+func (cmd Release) Validate(ctx golly.Context, aggregate eventsource.Aggregate) error {
+	switch {
+	case cmd.EmployeeID == uuid.Nil:
+		return errors.WrapUnprocessable(ErrorEmployeeRequired)
+	case cmd.ManagerID == uuid.Nil:
+		return errors.WrapUnprocessable(ErrorManagerRequired)
+	case len(cmd.FeedbackIDs) == 0:
+		return errors.WrapUnprocessable(ErrorSummariesRequired)
+	case len(strings.TrimSpace(cmd.Note)) > MaxNoteLength:
+		return errors.WrapUnprocessable(ErrorNoteTooLong)
+	}
+	return nil
+}
+
+func (cmd Release) Perform(ctx golly.Context, aggregate eventsource.Aggregate) error {
+	id, _ := uuid.NewV7()
+
+	eventsource.Apply(ctx, aggregate, Released{
+		ID:             id,
+		OrganizationID: cmd.OrganizationID,
+		EmployeeID:     cmd.EmployeeID,
+		ManagerID:      cmd.ManagerID,
+		FeedbackIDs:    cmd.FeedbackIDs,
+		ActionItems:    Strings(cmd.ActionItems),
+		Note:           strings.TrimSpace(cmd.Note),
+	})
+	return nil
+}
+
+// Acknowledge records that the employee read the released feedback,
+// EmployeeID is the employee record of the user acknowledging
+type Acknowledge struct {
+	UserID     uuid.UUID
+	EmployeeID uuid.UUID
+}
+
+func (cmd Acknowledge) Validate(ctx golly.Context, aggregate eventsource.Aggregate) error {
+	release := aggregate.(*Aggregate)
+
+	switch {
+	case cmd.EmployeeID != release.EmployeeID:
+		return errors.WrapForbidden(ErrorNotEmployee)
+	case release.Acknowledged():
+		return errors.WrapUnprocessable(ErrorAlreadyAcknowledged)
+	}
+	return nil
+}
+
+func (cmd Acknowledge) Perform(ctx golly.Context, aggregate eventsource.Aggregate) error {
+	eventsource.Apply(ctx, aggregate, Acknowledged{UserID: cmd.UserID})
+	return nil
+}
@@ -0,0 +1,67 @@
+package release
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golly-go/golly"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReleaseValidate(t *testing.T) {
+	gctx := golly.NewContext(context.TODO())
+
+	valid := Release{
+		EmployeeID:  uuid.New(),
+		ManagerID:   uuid.New(),
+		FeedbackIDs: []uuid.UUID{uuid.New()},
+		Note:        "Great half, let's talk about the roadmap.",
+	}
+
+	with := func(fn func(*Release)) Release {
+		cmd := valid
+		fn(&cmd)
+		return cmd
+	}
+
+	tests := []struct {
+		name      string
+		cmd       Release
+		expectErr error
+	}{
+		{name: "valid", cmd: valid},
+		{name: "missing employee", cmd: with(func(c *Release) { c.EmployeeID = uuid.Nil }), expectErr: ErrorEmployeeRequired},
+		{name: "missing manager", cmd: with(func(c *Release) { c.ManagerID = uuid.Nil }), expectErr: ErrorManagerRequired},
+		{name: "no summaries", cmd: with(func(c *Release) { c.FeedbackIDs = nil }), expectErr: ErrorSummariesRequired},
+		{name: "note too long", cmd: with(func(c *Release) { c.Note = strings.Repeat("a", MaxNoteLength+1) }), expectErr: ErrorNoteTooLong},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.cmd.Validate(gctx, &Aggregate{})
+			if tt.expectErr != nil {
+				assert.ErrorContains(t, err, tt.expectErr.Error())
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestAcknowledgeValidate(t *testing.T) {
+	gctx := golly.NewContext(context.TODO())
+
+	employeeID := uuid.New()
+	now := time.Now()
+
+	assert.NoError(t, Acknowledge{EmployeeID: employeeID}.Validate(gctx, &Aggregate{EmployeeID: employeeID}))
+
+	err := Acknowledge{EmployeeID: uuid.New()}.Validate(gctx, &Aggregate{EmployeeID: employeeID})
+	assert.ErrorContains(t, err, ErrorNotEmployee.Error())
+
+	err = Acknowledge{EmployeeID: employeeID}.Validate(gctx, &Aggregate{EmployeeID: employeeID, AcknowledgedAt: &now})
+	assert.ErrorContains(t, err, ErrorAlreadyAcknowledged.Error())
+}
@@ -0,0 +1,22 @@
+package release
+
+import (
+	"github.com/google/uuid"
+)
+
+type Released struct {
+	ID             uuid.UUID `json:"id"`
+	OrganizationID uuid.UUID `json:"organizationID"`
+	EmployeeID     uuid.UUID `json:"employeeID"`
+	ManagerID      uuid.UUID `json:"managerID"`
+	FeedbackIDs    UUIDs     `json:"feedbackIDs"`
+
+	// The action items and note are written about the employee so they
+	// are kept out of the event store
+	ActionItems Strings `json:"-"`
+	Note        string  `json:"-"`
+}
+
+type Acknowledged struct {
+	UserID uuid.UUID `json:"userID"`
+}
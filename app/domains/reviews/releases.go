@@ -0,0 +1,306 @@
+package reviews
+
+import (
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/golly-go/golly"
+	"github.com/golly-go/golly/errors"
+	"github.com/golly-go/plugins/eventsource"
+	"github.com/golly-go/plugins/orm"
+	"github.com/google/uuid"
+	"github.com/mitchrodrigues/talent-review-backend/app/domains/accounts"
+	"github.com/mitchrodrigues/talent-review-backend/app/domains/common"
+	"github.com/mitchrodrigues/talent-review-backend/app/domains/employees"
+	"github.com/mitchrodrigues/talent-review-backend/app/domains/reviews/release"
+	"github.com/mitchrodrigues/talent-review-backend/app/utils/identity"
+	"github.com/mitchrodrigues/talent-review-backend/app/utils/mailgun"
+	"gorm.io/gorm"
+)
+
+var (
+	ErrorNotReleaseManager    = fmt.Errorf("only the manager of the employee can share their feedback")
+	ErrorSummaryNotReleasable = fmt.Errorf("only summaries of submitted feedback on the employee can be shared")
+	ErrorUnknownActionItem    = fmt.Errorf("action items must come from the shared summaries")
+)
+
+type FeedbackRelease struct {
+	release.Aggregate
+}
+
+func (FeedbackRelease) TableName() string { return "feedback_releases" }
+
+type ReleaseFeedbackInput struct {
+	EmployeeID  uuid.UUID
+	FeedbackIDs []uuid.UUID
+	ActionItems []string
+	Note        string
+}
+
+// ReleaseFeedback shares summaries of the feedback on a direct report of
+// the current user with them, along with the action items picked from
+// those summaries and a personal note
+func ReleaseFeedback(gctx golly.Context, input ReleaseFeedbackInput, metadata eventsource.Metadata) (FeedbackRelease, error) {
+	var rel FeedbackRelease
+
+	ident := identity.FromContext(gctx)
+
+	manager, err := employees.Service(gctx).FindEmployeeByUserID(gctx, ident.UID)
+	if err != nil {
+		return rel, errors.WrapForbidden(ErrorNotReleaseManager)
+	}
+
+	employee, err := employees.Service(gctx).FindEmployeeByID(gctx, input.EmployeeID)
+	if err != nil {
+		return rel, err
+	}
+
+	if employee.ManagerID == nil || *employee.ManagerID != manager.ID {
+		return rel, errors.WrapForbidden(ErrorNotReleaseManager)
+	}
+
+	feedbackIDs := golly.Unique(input.FeedbackIDs)
+
+	summaries, err := releasableSummaries(gctx, employee.ID, feedbackIDs)
+	if err != nil {
+		return rel, err
+	}
+
+	if len(summaries) != len(feedbackIDs) {
+		return rel, errors.WrapUnprocessable(ErrorSummaryNotReleasable)
+	}
+
+	items := []string{}
+	for _, summary := range summaries {
+		items = append(items, summaryActionItems(summary)...)
+	}
+
+	actionItems := []string{}
+	for _, item := range input.ActionItems {
+		item = strings.TrimSpace(item)
+
+		if !slices.Contains(items, item) {
+			return rel, errors.WrapUnprocessable(ErrorUnknownActionItem)
+		}
+
+		if !slices.Contains(actionItems, item) {
+			actionItems = append(actionItems, item)
+		}
+	}
+
+	err = eventsource.Call(gctx, &rel.Aggregate, release.Release{
+		OrganizationID: ident.OrganizationID,
+		EmployeeID:     employee.ID,
+		ManagerID:      ident.UID,
+		FeedbackIDs:    feedbackIDs,
+		ActionItems:    actionItems,
+		Note:           input.Note,
+	}, metadata)
+
+	return rel, err
+}
+
+// releasableSummaries loads the summaries of the submitted feedback on the
+// employee the manager can read, aggregated-only feedback under its
+// threshold cannot be shared
+func releasableSummaries(gctx golly.Context, employeeID uuid.UUID, feedbackIDs []uuid.UUID) ([]FeedbackSummary, error) {
+	var feedbacks []Feedback
+
+	err := orm.DB(gctx).
+		Model(&Feedback{}).
+		Scopes(common.OrganizationIDScopeForContext(gctx)).
+		Where("employee_id = ? AND submitted_at IS NOT NULL", employeeID).
+		Find(&feedbacks, "id IN ?", feedbackIDs).
+		Error
+
+	if err != nil {
+		return nil, errors.WrapGeneric(err)
+	}
+
+	readable := uuid.UUIDs{}
+	for _, fb := range feedbacks {
+		if CanSeeContent(gctx, fb) {
+			readable = append(readable, fb.ID)
+		}
+	}
+
+	return findSummaries(gctx, readable)
+}
+
+func findSummaries(gctx golly.Context, feedbackIDs []uuid.UUID) ([]FeedbackSummary, error) {
+	var summaries []FeedbackSummary
+
+	if len(feedbackIDs) == 0 {
+		return summaries, nil
+	}
+
+	err := orm.DB(gctx).
+		Model(&FeedbackSummary{}).
+		Scopes(common.OrganizationIDScopeForContext(gctx, "feedback_summaries")).
+		Order("created_at").
+		Find(&summaries, "feedback_id IN ?", feedbackIDs).
+		Error
+
+	return summaries, errors.WrapGeneric(err)
+}
+
+// ReleasedSummaries are the summaries shared by a release, the employee
+// sees them without the feedback or who gave it
+func ReleasedSummaries(gctx golly.Context, rel FeedbackRelease) ([]FeedbackSummary, error) {
+	return findSummaries(gctx, rel.FeedbackIDs)
+}
+
+func summaryActionItems(summary FeedbackSummary) []string {
+	var items []string
+
+	_ = json.Unmarshal([]byte(summary.ActionItems), &items)
+	return items
+}
+
+// AcknowledgeRelease records that the current user read the feedback
+// released to them
+func AcknowledgeRelease(gctx golly.Context, id uuid.UUID, metadata eventsource.Metadata) (FeedbackRelease, error) {
+	rel, err := FindRelease(gctx, id)
+	if err != nil {
+		return rel, err
+	}
+
+	ident := identity.FromContext(gctx)
+
+	employee, err := employees.Service(gctx).FindEmployeeByUserID(gctx, ident.UID)
+	if err != nil {
+		return rel, errors.WrapForbidden(release.ErrorNotEmployee)
+	}
+
+	err = eventsource.Call(gctx, &rel.Aggregate, release.Acknowledge{
+		UserID:     ident.UID,
+		EmployeeID: employee.ID,
+	}, metadata)
+
+	return rel, err
+}
+
+func releaseQuery(gctx golly.Context) *gorm.DB {
+	return orm.DB(gctx).
+		Model(&FeedbackRelease{}).
+		Scopes(common.OrganizationIDScopeForContext(gctx))
+}
+
+// FindRelease finds a release of the organization, only the employee,
+// the manager who released it and HR can see it
+func FindRelease(gctx golly.Context, id uuid.UUID) (FeedbackRelease, error) {
+	var rel FeedbackRelease
+
+	err := releaseQuery(gctx).
+		First(&rel, "id = ?", id).
+		Error
+
+	if err != nil {
+		return rel, errors.WrapNotFound(err)
+	}
+
+	if rel.ManagerID == identity.FromContext(gctx).UID {
+		return rel, nil
+	}
+
+	employee, err := employees.Service(gctx).FindEmployeeByUserID(gctx, identity.FromContext(gctx).UID)
+	if err == nil && employee.ID == rel.EmployeeID {
+		return rel, nil
+	}
+
+	if _, err := accounts.RequireHR(gctx); err != nil {
+		return FeedbackRelease{}, errors.WrapNotFound(gorm.ErrRecordNotFound)
+	}
+
+	return rel, nil
+}
+
+// FindMyFeedback lists the feedback released to the current user, most
+// recent first
+func FindMyFeedback(gctx golly.Context) ([]FeedbackRelease, error) {
+	releases := []FeedbackRelease{}
+
+	employee, err := employees.Service(gctx).FindEmployeeByUserID(gctx, identity.FromContext(gctx).UID)
+	if err != nil {
+		return releases, nil
+	}
+
+	err = releaseQuery(gctx).
+		Where("employee_id = ?", employee.ID).
+		Order("created_at DESC").
+		Find(&releases).
+		Error
+
+	return releases, errors.WrapGeneric(err)
+}
+
+// FindReleases lists the releases of an employee made by the current
+// user, by anyone for HR, most recent first
+func FindReleases(gctx golly.Context, employeeID uuid.UUID) ([]FeedbackRelease, error) {
+	releases := []FeedbackRelease{}
+
+	db := releaseQuery(gctx).Where("employee_id = ?", employeeID)
+
+	if _, err := accounts.RequireHR(gctx); err != nil {
+		db = db.Where("manager_id = ?", identity.FromContext(gctx).UID)
+	}
+
+	err := db.Order("created_at DESC").Find(&releases).Error
+	return releases, errors.WrapGeneric(err)
+}
+
+// FeedbackReleaseEmailSubscription lets the employee know feedback was
+// shared with them and the manager once they acknowledged it
+func FeedbackReleaseEmailSubscription(gctx golly.Context, agg eventsource.Aggregate, evt eventsource.Event) error {
+	rel := *agg.(*release.Aggregate)
+
+	go func(ctx golly.Context) {
+		ctx = orm.SetDBOnContext(ctx, orm.Connection().Session(&gorm.Session{NewDB: true}))
+
+		params, ok, err := FeedbackReleaseEmail(ctx, rel, evt)
+		if err != nil || !ok {
+			return
+		}
+
+		params.ReleaseURL = ctx.Config().GetString("app.frontend.url") + "/my-feedback/" + rel.ID.String()
+
+		if err := mailgun.GetClient(ctx).SendFeedbackReleaseEmail(ctx, params); err != nil {
+			ctx.Logger().Warnf("Unable to send feedback release email to %s %v", params.Email, err)
+		}
+	}(gctx)
+
+	return nil
+}
+
+// FeedbackReleaseEmail builds the email sent for a release event, without
+// its link, false when the event does not notify anyone
+func FeedbackReleaseEmail(gctx golly.Context, rel release.Aggregate, evt eventsource.Event) (mailgun.FeedbackReleaseEmailParams, bool, error) {
+	params := mailgun.FeedbackReleaseEmailParams{Note: rel.Note}
+
+	employee, err := employees.Service(gctx).FindEmployeeByID_Unsafe(gctx, rel.EmployeeID)
+	if err != nil {
+		return params, false, err
+	}
+	params.EmployeeName = employee.Name
+
+	manager, err := accounts.FindUserByID(gctx, rel.ManagerID.String(), common.OrganizationIDScope(rel.OrganizationID))
+	if err != nil {
+		return params, false, err
+	}
+	params.ManagerName = strings.TrimSpace(manager.FirstName + " " + manager.LastName)
+
+	switch evt.Data.(type) {
+	case release.Released:
+		params.Kind = mailgun.FeedbackReleased
+		params.Name, params.Email = employee.Name, employee.Email
+	case release.Acknowledged:
+		params.Kind = mailgun.FeedbackAcknowledged
+		params.Name, params.Email = manager.FirstName, manager.Email
+	default:
+		return params, false, nil
+	}
+
+	return params, true, nil
+}
@@ -0,0 +1,197 @@
+package reviews
+
+import (
+	"strings"
+
+	"github.com/golly-go/golly"
+	"github.com/golly-go/plugins/gql"
+	"github.com/google/uuid"
+	"github.com/graphql-go/graphql"
+	"github.com/mitchrodrigues/talent-review-backend/app/domains/accounts"
+	"github.com/mitchrodrigues/talent-review-backend/app/domains/common"
+	"github.com/mitchrodrigues/talent-review-backend/app/domains/employees"
+	"github.com/mitchrodrigues/talent-review-backend/app/utils/helpers"
+)
+
+var (
+	// releasedSummaryType leaves out the action items of the summary,
+	// the employee only sees the ones their manager picked
+	releasedSummaryType = graphql.NewObject(graphql.ObjectConfig{
+		Name: "ReleasedFeedbackSummary",
+		Fields: graphql.Fields{
+			"id": {
+				Type: graphql.String,
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					return p.Source.(FeedbackSummary).ID, nil
+				},
+			},
+			"summary": {
+				Type: graphql.String,
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					return p.Source.(FeedbackSummary).Summary, nil
+				},
+			},
+		},
+	})
+
+	feedbackReleaseType = graphql.NewObject(graphql.ObjectConfig{
+		Name: "FeedbackRelease",
+		Fields: graphql.Fields{
+			"id": {
+				Type: graphql.NewNonNull(graphql.String),
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					return p.Source.(FeedbackRelease).ID, nil
+				},
+			},
+			"employee": {
+				Type: employees.EmployeeGQLType,
+				Resolve: gql.NewHandler(gql.Options{
+					Handler: func(wctx golly.WebContext, params gql.Params) (interface{}, error) {
+						return employees.Service(wctx.Context).FindEmployeeByID(wctx.Context, params.Source.(FeedbackRelease).EmployeeID)
+					},
+				}),
+			},
+			"managerName": {
+				Type:        graphql.String,
+				Description: "Name of the manager who shared the feedback",
+				Resolve: gql.NewHandler(gql.Options{
+					Handler: func(wctx golly.WebContext, params gql.Params) (interface{}, error) {
+						rel := params.Source.(FeedbackRelease)
+
+						manager, err := accounts.FindUserByID(wctx.Context, rel.ManagerID.String(), common.OrganizationIDScope(rel.OrganizationID))
+						if err != nil {
+							return nil, nil
+						}
+						return strings.TrimSpace(manager.FirstName + " " + manager.LastName), nil
+					},
+				}),
+			},
+			"summaries": {
+				Type: graphql.NewList(releasedSummaryType),
+				Resolve: gql.NewHandler(gql.Options{
+					Handler: func(wctx golly.WebContext, params gql.Params) (interface{}, error) {
+						return ReleasedSummaries(wctx.Context, params.Source.(FeedbackRelease))
+					},
+				}),
+			},
+			"actionItems": {
+				Type: graphql.NewList(graphql.String),
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					return []string(p.Source.(FeedbackRelease).ActionItems), nil
+				},
+			},
+			"note": {
+				Type:        graphql.String,
+				Description: "Personal note from the manager",
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					return p.Source.(FeedbackRelease).Note, nil
+				},
+			},
+			"releasedAt": {
+				Type: graphql.DateTime,
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					return p.Source.(FeedbackRelease).CreatedAt, nil
+				},
+			},
+			"acknowledgedAt": {
+				Type: graphql.DateTime,
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					return p.Source.(FeedbackRelease).AcknowledgedAt, nil
+				},
+			},
+		},
+	})
+
+	releaseFeedbackInputType = graphql.NewInputObject(graphql.InputObjectConfig{
+		Name: "ReleaseFeedbackInput",
+		Fields: graphql.InputObjectConfigFieldMap{
+			"employeeID":  {Type: graphql.NewNonNull(graphql.String)},
+			"feedbackIDs": {Type: graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(graphql.String))), Description: "Feedback whose summaries are shared"},
+			"actionItems": {Type: graphql.NewList(graphql.NewNonNull(graphql.String)), Description: "Picked from the action items of the shared summaries"},
+			"note":        {Type: graphql.String},
+		},
+	})
+
+	releaseQueries = graphql.Fields{
+		"myFeedback": &graphql.Field{
+			Type:        graphql.NewList(feedbackReleaseType),
+			Description: "Feedback your managers shared with you, most recent first",
+			Resolve: gql.NewHandler(gql.Options{
+				Handler: func(wctx golly.WebContext, params gql.Params) (interface{}, error) {
+					return FindMyFeedback(wctx.Context)
+				},
+			}),
+		},
+		"feedbackReleases": &graphql.Field{
+			Type:        graphql.NewList(feedbackReleaseType),
+			Description: "Feedback you shared with an employee, shared by anyone for HR",
+			Args: graphql.FieldConfigArgument{
+				"employeeID": {Type: graphql.NewNonNull(graphql.String)},
+			},
+			Resolve: gql.NewHandler(gql.Options{
+				Handler: func(wctx golly.WebContext, params gql.Params) (interface{}, error) {
+					employeeID, err := helpers.ExtractAndParseUUID(params.Args, "employeeID")
+					if err != nil {
+						return nil, err
+					}
+
+					return FindReleases(wctx.Context, employeeID)
+				},
+			}),
+		},
+	}
+
+	releaseMutations = graphql.Fields{
+		"releaseFeedback": &graphql.Field{
+			Type:        feedbackReleaseType,
+			Description: "Share feedback summaries and action items with your direct report",
+			Args: graphql.FieldConfigArgument{
+				"input": &graphql.ArgumentConfig{Type: graphql.NewNonNull(releaseFeedbackInputType)},
+			},
+			Resolve: gql.NewHandler(gql.Options{
+				Handler: func(wctx golly.WebContext, params gql.Params) (interface{}, error) {
+					employeeID, err := helpers.ExtractAndParseUUID(params.Input, "employeeID")
+					if err != nil {
+						return nil, err
+					}
+
+					feedbackIDs := []uuid.UUID{}
+					for _, id := range params.Input["feedbackIDs"].([]interface{}) {
+						feedbackID, err := uuid.Parse(id.(string))
+						if err != nil {
+							return nil, err
+						}
+						feedbackIDs = append(feedbackIDs, feedbackID)
+					}
+
+					actionItems, _ := helpers.ExtractArg[[]interface{}](params.Input, "actionItems")
+					note, _ := helpers.ExtractArg[string](params.Input, "note")
+
+					return ReleaseFeedback(wctx.Context, ReleaseFeedbackInput{
+						EmployeeID:  employeeID,
+						FeedbackIDs: feedbackIDs,
+						ActionItems: golly.Map(actionItems, func(item interface{}) string { return item.(string) }),
+						Note:        note,
+					}, params.Metadata())
+				},
+			}),
+		},
+		"acknowledgeFeedback": &graphql.Field{
+			Type:        feedbackReleaseType,
+			Description: "Acknowledge feedback your manager shared with you",
+			Args: graphql.FieldConfigArgument{
+				"id": &graphql.ArgumentConfig{Type: graphql.NewNonNull(graphql.String)},
+			},
+			Resolve: gql.NewHandler(gql.Options{
+				Handler: func(wctx golly.WebContext, params gql.Params) (interface{}, error) {
+					id, err := helpers.ExtractAndParseUUID(params.Args, "id")
+					if err != nil {
+						return nil, err
+					}
+
+					return AcknowledgeRelease(wctx.Context, id, params.Metadata())
+				},
+			}),
+		},
+	}
+)
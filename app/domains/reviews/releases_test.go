@@ -0,0 +1,173 @@
+package reviews
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/golly-go/golly"
+	"github.com/golly-go/plugins/eventsource"
+	"github.com/golly-go/plugins/orm"
+	"github.com/google/uuid"
+	"github.com/mitchrodrigues/talent-review-backend/app/domains/accounts"
+	"github.com/mitchrodrigues/talent-review-backend/app/domains/accounts/users"
+	"github.com/mitchrodrigues/talent-review-backend/app/domains/employees"
+	"github.com/mitchrodrigues/talent-review-backend/app/domains/reviews/feedback"
+	"github.com/mitchrodrigues/talent-review-backend/app/domains/reviews/release"
+	"github.com/mitchrodrigues/talent-review-backend/app/utils/esbackend"
+	"github.com/mitchrodrigues/talent-review-backend/app/utils/identity"
+	"github.com/mitchrodrigues/talent-review-backend/app/utils/mailgun"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFeedbackRelease(t *testing.T) {
+	gctx := orm.CreateTestContext(golly.NewContext(context.TODO()),
+		FeedbackRelease{}, Feedback{}, FeedbackSummary{}, employees.Employee{}, accounts.User{}, esbackend.Event{})
+
+	organizationID := uuid.New()
+
+	createUser := func(firstName string) accounts.User {
+		user := accounts.User{Aggregate: users.Aggregate{OrganizationID: organizationID, Email: firstName + "@example.com", FirstName: firstName, Role: users.RoleMember}}
+		orm.DB(gctx).Create(&user)
+		return user
+	}
+
+	managerUser := createUser("morgan")
+	reportUser := createUser("jane")
+	otherUser := createUser("sam")
+
+	manager := employees.NewTestEmployee(uuid.New(), organizationID, "morgan@example.com", &managerUser.ID)
+	orm.DB(gctx).Create(&manager)
+
+	report := employees.NewTestEmployee(uuid.New(), organizationID, "jane@example.com", &reportUser.ID)
+	report.Name = "Jane"
+	report.ManagerID = &manager.ID
+	orm.DB(gctx).Create(&report)
+
+	other := employees.NewTestEmployee(uuid.New(), organizationID, "sam@example.com", &otherUser.ID)
+	orm.DB(gctx).Create(&other)
+
+	// identities are set on the shared context so each call sets its own
+	as := func(userID, employeeID uuid.UUID) golly.Context {
+		return identity.ToContext(gctx, identity.Identity{UID: userID, OrganizationID: organizationID, EmployeeID: employeeID})
+	}
+	asManager := func() golly.Context { return as(managerUser.ID, manager.ID) }
+	asReport := func() golly.Context { return as(reportUser.ID, report.ID) }
+	asOther := func() golly.Context { return as(otherUser.ID, other.ID) }
+
+	createSummary := func(submitted bool, summary string, items ...string) Feedback {
+		now := time.Now()
+
+		fb := Feedback{Aggregate: feedback.Aggregate{
+			ModelUUID:       orm.ModelUUID{ID: uuid.New()},
+			Code:            uuid.NewString(),
+			Email:           "peer@example.com",
+			EmployeeID:      report.ID,
+			OrganizationID:  organizationID,
+			CollectionEndAt: now,
+		}}
+		if submitted {
+			fb.SubmittedAt = &now
+		}
+		orm.DB(gctx).Create(&fb)
+
+		b, _ := json.Marshal(items)
+		orm.DB(gctx).Create(&FeedbackSummary{FeedbackSummary: feedback.FeedbackSummary{
+			ModelUUID:      orm.ModelUUID{ID: uuid.New()},
+			FeedbackID:     fb.ID,
+			EmployeeID:     report.ID,
+			OrganizationID: organizationID,
+			Summary:        summary,
+			ActionItems:    string(b),
+		}})
+		return fb
+	}
+
+	shared := createSummary(true, "Jane runs great planning meetings.", "Delegate the weekly sync", "Write more design docs")
+	pending := createSummary(false, "Not submitted yet.")
+
+	input := ReleaseFeedbackInput{
+		EmployeeID:  report.ID,
+		FeedbackIDs: []uuid.UUID{shared.ID},
+		ActionItems: []string{"Delegate the weekly sync"},
+		Note:        "  Proud of this half.  ",
+	}
+
+	t.Run("only the manager releases", func(t *testing.T) {
+		_, err := ReleaseFeedback(asOther(), input, eventsource.Metadata{})
+		assert.ErrorContains(t, err, ErrorNotReleaseManager.Error())
+
+		_, err = ReleaseFeedback(asReport(), input, eventsource.Metadata{})
+		assert.ErrorContains(t, err, ErrorNotReleaseManager.Error())
+	})
+
+	t.Run("only submitted summaries and their action items", func(t *testing.T) {
+		withPending := input
+		withPending.FeedbackIDs = []uuid.UUID{shared.ID, pending.ID}
+
+		_, err := ReleaseFeedback(asManager(), withPending, eventsource.Metadata{})
+		assert.ErrorContains(t, err, ErrorSummaryNotReleasable.Error())
+
+		madeUp := input
+		madeUp.ActionItems = []string{"Take a sabbatical"}
+
+		_, err = ReleaseFeedback(asManager(), madeUp, eventsource.Metadata{})
+		assert.ErrorContains(t, err, ErrorUnknownActionItem.Error())
+	})
+
+	rel, err := ReleaseFeedback(asManager(), input, eventsource.Metadata{})
+	assert.NoError(t, err)
+	assert.Equal(t, "Proud of this half.", rel.Note)
+	assert.Equal(t, release.Strings{"Delegate the weekly sync"}, rel.ActionItems)
+
+	t.Run("the employee sees it in their feedback", func(t *testing.T) {
+		releases, err := FindMyFeedback(asReport())
+		assert.NoError(t, err)
+
+		if assert.Len(t, releases, 1) {
+			summaries, err := ReleasedSummaries(asReport(), releases[0])
+			assert.NoError(t, err)
+
+			if assert.Len(t, summaries, 1) {
+				assert.Equal(t, "Jane runs great planning meetings.", summaries[0].Summary)
+			}
+		}
+
+		releases, err = FindMyFeedback(asOther())
+		assert.NoError(t, err)
+		assert.Empty(t, releases)
+
+		_, err = FindRelease(asOther(), rel.ID)
+		assert.Error(t, err)
+	})
+
+	t.Run("the employee is emailed", func(t *testing.T) {
+		params, ok, err := FeedbackReleaseEmail(gctx, rel.Aggregate, eventsource.Event{Data: release.Released{}})
+		assert.NoError(t, err)
+		assert.True(t, ok)
+
+		assert.Equal(t, mailgun.FeedbackReleased, params.Kind)
+		assert.Equal(t, "jane@example.com", params.Email)
+		assert.Equal(t, "morgan", params.ManagerName)
+		assert.Equal(t, "Proud of this half.", params.Note)
+	})
+
+	t.Run("only the employee acknowledges, once", func(t *testing.T) {
+		_, err := AcknowledgeRelease(asManager(), rel.ID, eventsource.Metadata{})
+		assert.ErrorContains(t, err, release.ErrorNotEmployee.Error())
+
+		acknowledged, err := AcknowledgeRelease(asReport(), rel.ID, eventsource.Metadata{})
+		assert.NoError(t, err)
+		assert.NotNil(t, acknowledged.AcknowledgedAt)
+		assert.Equal(t, reportUser.ID, *acknowledged.AcknowledgedBy)
+
+		_, err = AcknowledgeRelease(asReport(), rel.ID, eventsource.Metadata{})
+		assert.ErrorContains(t, err, release.ErrorAlreadyAcknowledged.Error())
+
+		params, ok, err := FeedbackReleaseEmail(gctx, acknowledged.Aggregate, eventsource.Event{Data: release.Acknowledged{}})
+		assert.NoError(t, err)
+		assert.True(t, ok)
+		assert.Equal(t, "morgan@example.com", params.Email)
+	})
+}
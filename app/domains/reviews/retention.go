@@ -0,0 +1,135 @@
+package reviews
+
+import (
+	"time"
+
+	"github.com/golly-go/golly"
+	"github.com/golly-go/plugins/orm"
+	"github.com/google/uuid"
+	"github.com/mitchrodrigues/talent-review-backend/app/domains/reviews/review"
+	"github.com/mitchrodrigues/talent-review-backend/app/domains/tara"
+	"gorm.io/gorm"
+)
+
+// ContentEvents are the feedback events recording written content, they
+// are tombstoned once the content is purged
+var ContentEvents = []string{
+	"feedback.DetailsUpdated",
+	"feedback.SummaryUpdated",
+	"feedback.AnalysisUpdated",
+}
+
+// PurgeFeedbackContent hard deletes the details, summary, analysis and
+// embeddings of up to limit feedback whose review cycle closed before the
+// cutoff, the collection end of feedback requested outside of a cycle,
+// and returns their ids with the number of tara conversations deleted.
+// The conversations about the employees of the feedback started before
+// the cutoff may quote it so they go too. The feedback requests
+// themselves are kept so response rates do not change
+func PurgeFeedbackContent(gctx golly.Context, organizationID uuid.UUID, before time.Time, limit int) ([]uuid.UUID, int64, error) {
+	var ids []uuid.UUID
+	var conversations int64
+
+	err := orm.DB(gctx).Transaction(func(tx *gorm.DB) error {
+		err := tx.Model(&Feedback{}).
+			Unscoped().
+			Where("organization_id = ?", organizationID).
+			Where("COALESCE((SELECT MAX(cycle.end_at) FROM cycles cycle WHERE "+feedbackCycle+"), feedbacks.collection_end_at) < ?", before).
+			Where(`(
+				EXISTS (SELECT 1 FROM feedback_details WHERE feedback_details.feedback_id = feedbacks.id) OR
+				EXISTS (SELECT 1 FROM feedback_summaries WHERE feedback_summaries.feedback_id = feedbacks.id) OR
+				EXISTS (SELECT 1 FROM feedback_analyses WHERE feedback_analyses.feedback_id = feedbacks.id)
+			)`).
+			Limit(limit).
+			Pluck("id", &ids).
+			Error
+
+		if err != nil || len(ids) == 0 {
+			return err
+		}
+
+		if err := deleteFeedbackContent(tx, ids); err != nil {
+			return err
+		}
+
+		var employeeIDs []uuid.UUID
+
+		err = tx.Model(&Feedback{}).
+			Unscoped().
+			Distinct("employee_id").
+			Where("id IN ?", ids).
+			Pluck("employee_id", &employeeIDs).
+			Error
+
+		if err != nil {
+			return err
+		}
+
+		result := tx.Unscoped().
+			Where("organization_id = ? AND employee_id IN ? AND created_at < ?", organizationID, employeeIDs, before).
+			Delete(&tara.Conversation{})
+
+		conversations = result.RowsAffected
+		return result.Error
+	})
+
+	return ids, conversations, err
+}
+
+// PurgeReviewContent deletes the text of the performance reviews whose
+// cycle closed before the cutoff, or that were created before it outside
+// of a cycle, and returns how many were purged. The ratings are kept
+func PurgeReviewContent(gctx golly.Context, organizationID uuid.UUID, before time.Time) (int64, error) {
+	result := orm.DB(gctx).
+		Model(&PerformanceReview{}).
+		Unscoped().
+		Where("organization_id = ?", organizationID).
+		Where("COALESCE((SELECT cycle.end_at FROM cycles cycle WHERE cycle.id = performance_reviews.cycle_id), performance_reviews.created_at) < ?", before).
+		Where("(summary <> '' OR rating_rationale <> '' OR suggested_rating_rationale <> '')").
+		UpdateColumns(map[string]interface{}{
+			"summary":                    "",
+			"sections":                   review.Sections{},
+			"rating_rationale":           "",
+			"suggested_rating_rationale": "",
+		})
+
+	return result.RowsAffected, result.Error
+}
+
+// DropUnsubmittedFeedback hard deletes up to limit feedback requests sent
+// before the cutoff whose collection ended and that were never submitted,
+// declined ones included, and returns their ids
+func DropUnsubmittedFeedback(gctx golly.Context, organizationID uuid.UUID, now, before time.Time, limit int) ([]uuid.UUID, error) {
+	var ids []uuid.UUID
+
+	err := orm.DB(gctx).Transaction(func(tx *gorm.DB) error {
+		err := tx.Model(&Feedback{}).
+			Unscoped().
+			Where("organization_id = ? AND submitted_at IS NULL AND created_at < ?", organizationID, before).
+			Where("collection_end_at < ?", now).
+			Limit(limit).
+			Pluck("id", &ids).
+			Error
+
+		if err != nil || len(ids) == 0 {
+			return err
+		}
+
+		if err := deleteFeedbackContent(tx, ids); err != nil {
+			return err
+		}
+
+		return tx.Unscoped().Where("id IN ?", ids).Delete(&Feedback{}).Error
+	})
+
+	return ids, err
+}
+
+func deleteFeedbackContent(tx *gorm.DB, feedbackIDs []uuid.UUID) error {
+	for _, model := range []interface{}{&FeedbackDetails{}, &FeedbackSummary{}, &FeedbackAnalysis{}, &FeedbackEmbedding{}} {
+		if err := tx.Unscoped().Where("feedback_id IN ?", feedbackIDs).Delete(model).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
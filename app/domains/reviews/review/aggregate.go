@@ -0,0 +1,131 @@
+package review
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/golly-go/golly"
+	"github.com/golly-go/plugins/eventsource"
+	"github.com/golly-go/plugins/orm"
+	"github.com/google/uuid"
+	"github.com/mitchrodrigues/talent-review-backend/app/domains/tara/prompt"
+	"github.com/mitchrodrigues/talent-review-backend/app/utils/esbackend"
+)
+
+const (
+	StatusDraft     = "DRAFT"
+	StatusFinalized = "FINALIZED"
+
+	MinRating = 1
+	MaxRating = 5
+)
+
+// Aggregate is the formal performance review a manager writes for an
+// employee, it starts as a tara drafted document the manager revises
+// until it is finalized
+type Aggregate struct {
+	eventsource.AggregateBase
+
+	orm.ModelUUID
+
+	OrganizationID uuid.UUID
+	EmployeeID     uuid.UUID
+	AuthorID       uuid.UUID
+	CycleID        *uuid.UUID
+
+	Status string
+
+	Summary  string
+	Sections Sections `gorm:"type:jsonb"`
+
+	Rating          int
+	RatingRationale string
+
+	// SuggestedRating is the rating tara suggested, it is kept when the
+	// manager changes the rating so the two can be compared
+	SuggestedRating          int
+	SuggestedRatingRationale string
+
+	PromptVersions prompt.Refs `gorm:"type:jsonb"`
+
+	FinalizedAt *time.Time
+}
+
+func (*Aggregate) Topic() string                             { return "events.performance_reviews" }
+func (*Aggregate) Repo(golly.Context) eventsource.Repository { return esbackend.PostgresRepository{} }
+func (*Aggregate) TableName() string                         { return "performance_reviews" }
+
+func (review *Aggregate) GetID() string   { return review.ID.String() }
+func (review *Aggregate) SetID(id string) { review.ID, _ = uuid.Parse(id) }
+
+func (review *Aggregate) Finalized() bool { return review.Status == StatusFinalized }
+
+func (review *Aggregate) Apply(ctx golly.Context, evt eventsource.Event) {
+	switch event := evt.Data.(type) {
+	case Drafted:
+		review.ID = event.ID
+		review.OrganizationID = event.OrganizationID
+		review.EmployeeID = event.EmployeeID
+		review.AuthorID = event.AuthorID
+		review.CycleID = event.CycleID
+		review.Status = StatusDraft
+
+		review.Summary = event.Summary
+		review.Sections = event.Sections
+
+		review.Rating = event.SuggestedRating
+		review.RatingRationale = event.SuggestedRatingRationale
+		review.SuggestedRating = event.SuggestedRating
+		review.SuggestedRatingRationale = event.SuggestedRatingRationale
+
+		review.PromptVersions = event.PromptVersions
+
+		review.CreatedAt = evt.CreatedAt
+
+	case Revised:
+		review.Summary = event.Summary
+		review.Sections = event.Sections
+		review.Rating = event.Rating
+		review.RatingRationale = event.RatingRationale
+
+	case Finalized:
+		review.Status = StatusFinalized
+		review.FinalizedAt = &evt.CreatedAt
+	}
+	review.UpdatedAt = evt.CreatedAt
+}
+
+// Section is the assessment of one competency
+type Section struct {
+	Competency string `json:"competency"`
+	Content    string `json:"content"`
+
+	// Citations are the feedback IDs the section is grounded in
+	Citations []uuid.UUID `json:"citations"`
+}
+
+// Sections are stored as jsonb
+type Sections []Section
+
+func (s Sections) Value() (driver.Value, error) {
+	if s == nil {
+		return []byte("[]"), nil
+	}
+	return json.Marshal(s)
+}
+
+func (s *Sections) Scan(value interface{}) error {
+	switch v := value.(type) {
+	case nil:
+		return nil
+	case []byte:
+		return json.Unmarshal(v, s)
+	case string:
+		return json.Unmarshal([]byte(v), s)
+	}
+	return fmt.Errorf("cannot scan %T into sections", value)
+}
+
+var _ eventsource.Aggregate = &Aggregate{}
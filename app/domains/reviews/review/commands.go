@@ -0,0 +1,160 @@
+package review
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/golly-go/golly"
+	"github.com/golly-go/golly/errors"
+	"github.com/golly-go/plugins/eventsource"
+	"github.com/google/uuid"
+	"github.com/mitchrodrigues/talent-review-backend/app/domains/tara/prompt"
+)
+
+var (
+	ErrorEmployeeRequired   = fmt.Errorf("review employee is required")
+	ErrorAuthorRequired     = fmt.Errorf("review author is required")
+	ErrorNotDrafted         = fmt.Errorf("review has not been drafted")
+	ErrorAlreadyFinalized   = fmt.Errorf("review has already been finalized")
+	ErrorInvalidRating      = fmt.Errorf("rating must be between %d and %d", MinRating, MaxRating)
+	ErrorCompetencyRequired = fmt.Errorf("every section needs a competency")
+)
+
+// Draft stores a new review drafted by tara
+type Draft struct {
+	OrganizationID uuid.UUID
+	EmployeeID     uuid.UUID
+	AuthorID       uuid.UUID
+	CycleID        *uuid.UUID
+
+	Summary  string
+	Sections Sections
+
+	SuggestedRating          int
+	SuggestedRatingRationale string
+
+	PromptVersions prompt.Refs
+}
+
+func (cmd Draft) Validate(ctx golly.Context, aggregate eventsource.Aggregate) error {
+	if cmd.EmployeeID == uuid.Nil {
+		return errors.WrapUnprocessable(ErrorEmployeeRequired)
+	}
+
+	if cmd.AuthorID == uuid.Nil {
+		return errors.WrapUnprocessable(ErrorAuthorRequired)
+	}
+
+	if !validRating(cmd.SuggestedRating) {
+		return errors.WrapUnprocessable(ErrorInvalidRating)
+	}
+
+	return nil
+}
+
+func (cmd Draft) Perform(ctx golly.Context, aggregate eventsource.Aggregate) error {
+	id, _ := uuid.NewV7()
+
+	eventsource.Apply(ctx, aggregate, Drafted{
+		ID:                       id,
+		OrganizationID:           cmd.OrganizationID,
+		EmployeeID:               cmd.EmployeeID,
+		AuthorID:                 cmd.AuthorID,
+		CycleID:                  cmd.CycleID,
+		Summary:                  cmd.Summary,
+		Sections:                 cmd.Sections,
+		SuggestedRating:          cmd.SuggestedRating,
+		SuggestedRatingRationale: cmd.SuggestedRatingRationale,
+		PromptVersions:           cmd.PromptVersions,
+	})
+
+	return nil
+}
+
+// Revise updates the draft, nil fields keep their current value
+type Revise struct {
+	Summary  *string
+	Sections *Sections
+
+	Rating          *int
+	RatingRationale *string
+}
+
+func (cmd Revise) Validate(ctx golly.Context, aggregate eventsource.Aggregate) error {
+	if err := validateDraft(aggregate.(*Aggregate)); err != nil {
+		return err
+	}
+
+	if cmd.Rating != nil && !validRating(*cmd.Rating) {
+		return errors.WrapUnprocessable(ErrorInvalidRating)
+	}
+
+	if cmd.Sections != nil {
+		for _, section := range *cmd.Sections {
+			if strings.TrimSpace(section.Competency) == "" {
+				return errors.WrapUnprocessable(ErrorCompetencyRequired)
+			}
+		}
+	}
+
+	return nil
+}
+
+func (cmd Revise) Perform(ctx golly.Context, aggregate eventsource.Aggregate) error {
+	review := aggregate.(*Aggregate)
+
+	evt := Revised{
+		Summary:         review.Summary,
+		Sections:        review.Sections,
+		Rating:          review.Rating,
+		RatingRationale: review.RatingRationale,
+	}
+
+	if cmd.Summary != nil {
+		evt.Summary = *cmd.Summary
+	}
+
+	if cmd.Sections != nil {
+		evt.Sections = *cmd.Sections
+	}
+
+	if cmd.Rating != nil {
+		evt.Rating = *cmd.Rating
+	}
+
+	if cmd.RatingRationale != nil {
+		evt.RatingRationale = *cmd.RatingRationale
+	}
+
+	eventsource.Apply(ctx, aggregate, evt)
+
+	return nil
+}
+
+// Finalize locks the review, it can no longer be revised
+type Finalize struct{}
+
+func (Finalize) Validate(ctx golly.Context, aggregate eventsource.Aggregate) error {
+	return validateDraft(aggregate.(*Aggregate))
+}
+
+func (Finalize) Perform(ctx golly.Context, aggregate eventsource.Aggregate) error {
+	eventsource.Apply(ctx, aggregate, Finalized{})
+	return nil
+}
+
+func validateDraft(review *Aggregate) error {
+	if review.ID == uuid.Nil {
+		return errors.WrapUnprocessable(ErrorNotDrafted)
+	}
+
+	if review.Finalized() {
+		return errors.WrapUnprocessable(ErrorAlreadyFinalized)
+	}
+
+	return nil
+}
+
+func validRating(rating int) bool {
+	return rating >= MinRating && rating <= MaxRating
+}
@@ -0,0 +1,102 @@
+package review
+
+import (
+	"context"
+	"testing"
+
+	"github.com/golly-go/golly"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDraftValidate(t *testing.T) {
+	tests := []struct {
+		name      string
+		cmd       Draft
+		expectErr bool
+	}{
+		{name: "valid", cmd: Draft{EmployeeID: uuid.New(), AuthorID: uuid.New(), SuggestedRating: 3}},
+		{name: "missing employee", cmd: Draft{AuthorID: uuid.New(), SuggestedRating: 3}, expectErr: true},
+		{name: "missing author", cmd: Draft{EmployeeID: uuid.New(), SuggestedRating: 3}, expectErr: true},
+		{name: "rating out of range", cmd: Draft{EmployeeID: uuid.New(), AuthorID: uuid.New(), SuggestedRating: 6}, expectErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.cmd.Validate(golly.Context{}, &Aggregate{})
+			if tt.expectErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestReviseValidate(t *testing.T) {
+	drafted := &Aggregate{Status: StatusDraft}
+	drafted.ID = uuid.New()
+
+	finalized := &Aggregate{Status: StatusFinalized}
+	finalized.ID = uuid.New()
+
+	rating := 0
+
+	tests := []struct {
+		name      string
+		aggregate *Aggregate
+		cmd       Revise
+		expectErr bool
+	}{
+		{name: "valid", aggregate: drafted, cmd: Revise{Sections: &Sections{{Competency: "Impact"}}}},
+		{name: "not drafted", aggregate: &Aggregate{}, cmd: Revise{}, expectErr: true},
+		{name: "finalized", aggregate: finalized, cmd: Revise{}, expectErr: true},
+		{name: "invalid rating", aggregate: drafted, cmd: Revise{Rating: &rating}, expectErr: true},
+		{name: "section without competency", aggregate: drafted, cmd: Revise{Sections: &Sections{{Content: "x"}}}, expectErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.cmd.Validate(golly.Context{}, tt.aggregate)
+			if tt.expectErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestPerform(t *testing.T) {
+	gctx := golly.NewContext(context.TODO())
+
+	feedbackID := uuid.New()
+
+	agg := &Aggregate{}
+
+	assert.NoError(t, Draft{
+		EmployeeID:               uuid.New(),
+		AuthorID:                 uuid.New(),
+		Summary:                  "Drafted",
+		Sections:                 Sections{{Competency: "Impact", Content: "Shipped", Citations: []uuid.UUID{feedbackID}}},
+		SuggestedRating:          4,
+		SuggestedRatingRationale: "Strong year",
+	}.Perform(gctx, agg))
+
+	assert.NotEqual(t, uuid.Nil, agg.ID)
+	assert.Equal(t, StatusDraft, agg.Status)
+	assert.Equal(t, 4, agg.Rating)
+	assert.Equal(t, "Strong year", agg.RatingRationale)
+
+	rating, summary := 3, "Revised"
+
+	assert.NoError(t, Revise{Rating: &rating, Summary: &summary}.Perform(gctx, agg))
+	assert.Equal(t, "Revised", agg.Summary)
+	assert.Equal(t, 3, agg.Rating)
+	assert.Equal(t, 4, agg.SuggestedRating)
+	assert.Equal(t, []uuid.UUID{feedbackID}, agg.Sections[0].Citations)
+
+	assert.NoError(t, Finalize{}.Perform(gctx, agg))
+	assert.True(t, agg.Finalized())
+	assert.NotNil(t, agg.FinalizedAt)
+}
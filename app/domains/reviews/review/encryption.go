@@ -0,0 +1,42 @@
+package review
+
+import (
+	"github.com/mitchrodrigues/talent-review-backend/app/utils/crypto"
+	"gorm.io/gorm"
+)
+
+// The text of a review is drafted from feedback so it is encrypted at rest
+// with the data key of the organization like the feedback itself
+
+func (review *Aggregate) EncryptedColumns() map[string]*string {
+	return map[string]*string{
+		"summary":                    &review.Summary,
+		"rating_rationale":           &review.RatingRationale,
+		"suggested_rating_rationale": &review.SuggestedRatingRationale,
+	}
+}
+
+// EncryptedText points at the content of the sections
+func (review *Aggregate) EncryptedText() []*string {
+	text := make([]*string, 0, len(review.Sections))
+	for i := range review.Sections {
+		text = append(text, &review.Sections[i].Content)
+	}
+	return text
+}
+
+func (review *Aggregate) EncryptedDocuments() map[string]interface{} {
+	return map[string]interface{}{"sections": review.Sections}
+}
+
+func (review *Aggregate) BeforeSave(tx *gorm.DB) error {
+	return crypto.EncryptFields(tx, review.OrganizationID, crypto.Fields(review.EncryptedColumns(), review.EncryptedText()...)...)
+}
+
+func (review *Aggregate) AfterSave(tx *gorm.DB) error {
+	return crypto.DecryptFields(tx, review.OrganizationID, crypto.Fields(review.EncryptedColumns(), review.EncryptedText()...)...)
+}
+
+func (review *Aggregate) AfterFind(tx *gorm.DB) error {
+	return crypto.DecryptFields(tx, review.OrganizationID, crypto.Fields(review.EncryptedColumns(), review.EncryptedText()...)...)
+}
@@ -0,0 +1,32 @@
+package review
+
+import (
+	"github.com/google/uuid"
+	"github.com/mitchrodrigues/talent-review-backend/app/domains/tara/prompt"
+)
+
+type Drafted struct {
+	ID             uuid.UUID  `json:"id"`
+	OrganizationID uuid.UUID  `json:"organizationID"`
+	EmployeeID     uuid.UUID  `json:"employeeID"`
+	AuthorID       uuid.UUID  `json:"authorID"`
+	CycleID        *uuid.UUID `json:"cycleID"`
+
+	Summary  string   `json:"-"`
+	Sections Sections `json:"-"`
+
+	SuggestedRating          int    `json:"-"`
+	SuggestedRatingRationale string `json:"-"`
+
+	PromptVersions prompt.Refs `json:"promptVersions"`
+}
+
+type Revised struct {
+	Summary  string   `json:"-"`
+	Sections Sections `json:"-"`
+
+	Rating          int    `json:"-"`
+	RatingRationale string `json:"-"`
+}
+
+type Finalized struct{}
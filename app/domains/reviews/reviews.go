@@ -7,9 +7,21 @@ import (
 
 func Initializer(app golly.Application) error {
 	InitGraphQL()
+	ConfigureCoachLimiter(app)
 
 	eventsource.Subscribe("feedback.Aggregate", "feedback.Created", SendFeedbackEmail)
 	eventsource.Subscribe("feedback.Aggregate", "feedback.Submitted", UpdateFeedbackSummarySubscription)
+	eventsource.Subscribe("feedback.Aggregate", "feedback.Submitted", EmbedFeedbackSubscription)
+
+	eventsource.Subscribe("nomination.Aggregate", "nomination.Proposed", NominationEmailSubscription)
+	eventsource.Subscribe("nomination.Aggregate", "nomination.EmailsUpdated", NominationEmailSubscription)
+	eventsource.Subscribe("nomination.Aggregate", "nomination.Approved", NominationEmailSubscription)
+	eventsource.Subscribe("nomination.Aggregate", "nomination.Rejected", NominationEmailSubscription)
+
+	eventsource.Subscribe("release.Aggregate", "release.Released", FeedbackReleaseEmailSubscription)
+	eventsource.Subscribe("release.Aggregate", "release.Acknowledged", FeedbackReleaseEmailSubscription)
+
+	eventsource.Subscribe("employee.Aggregate", "employee.Erased", EraseEmployeeSubscription)
 
 	return nil
 }
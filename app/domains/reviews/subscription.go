@@ -5,10 +5,13 @@ import (
 	"github.com/golly-go/golly/errors"
 	"github.com/golly-go/plugins/eventsource"
 	"github.com/golly-go/plugins/orm"
+	"github.com/google/uuid"
 	"github.com/mitchrodrigues/talent-review-backend/app/domains/accounts"
 	"github.com/mitchrodrigues/talent-review-backend/app/domains/employees"
 	"github.com/mitchrodrigues/talent-review-backend/app/domains/reviews/feedback"
 	"github.com/mitchrodrigues/talent-review-backend/app/domains/tara"
+	"github.com/mitchrodrigues/talent-review-backend/app/domains/tara/prompt"
+	"github.com/mitchrodrigues/talent-review-backend/app/utils/identity"
 	"github.com/mitchrodrigues/talent-review-backend/app/utils/mailgun"
 	"github.com/mitchrodrigues/talent-review-backend/app/utils/wsyiwig"
 	"gorm.io/gorm"
@@ -50,15 +53,22 @@ func SendFeedbackEmail(gctx golly.Context, agg eventsource.Aggregate, evt events
 	return nil
 }
 
+// UpdateFeedbackSummarySubscription summarizes and then analyzes submitted
+// feedback. Both store events on the aggregate so they run one after the
+// other in a single goroutine, on a copy the caller no longer touches
 func UpdateFeedbackSummarySubscription(gctx golly.Context, agg eventsource.Aggregate, evt eventsource.Event) error {
 	switch evt.Data.(type) {
 	case feedback.Submitted:
-		fb := agg.(*feedback.Aggregate)
+		fb := *agg.(*feedback.Aggregate)
 
-		go func(gctx golly.Context, fb *feedback.Aggregate) {
-			if err := UpdateFeedbackSummary(gctx, fb); err != nil {
+		go func(gctx golly.Context, fb feedback.Aggregate) {
+			if err := UpdateFeedbackSummary(gctx, &fb); err != nil {
 				gctx.Logger().Warnf("connot generate summary for feedback %s %v", fb.ID.String(), err)
 			}
+
+			if err := AnalyzeFeedback(gctx, &fb); err != nil {
+				gctx.Logger().Warnf("cannot analyze feedback %s %v", fb.ID.String(), err)
+			}
 		}(gctx, fb)
 
 		return nil
@@ -67,40 +77,73 @@ func UpdateFeedbackSummarySubscription(gctx golly.Context, agg eventsource.Aggre
 }
 
 func UpdateFeedbackSummary(gctx golly.Context, fb *feedback.Aggregate) error {
+	// Feedback is submitted publicly, make sure LLM usage is
+	// accounted against the organization that owns it
+	_, gctx = identity.SetOrganizationID(gctx, fb.OrganizationID)
+
+	summary, err := GenerateFeedbackSummary(gctx, fb, nil)
+	if err != nil {
+		return err
+	}
+
+	err = eventsource.Call(gctx, fb, feedback.CreateSummary{
+		Summary:        summary.Summary,
+		ActionItems:    summary.ActionItems,
+		PromptVersions: summary.PromptVersions,
+	}, eventsource.Metadata{})
 
+	return errors.WrapGeneric(err)
+}
+
+// GenerateFeedbackSummary runs tara over the feedback without storing the
+// result, pinned prompt versions (as recorded on a summary) are used
+// instead of the active ones so a summary can be reproduced and compared
+func GenerateFeedbackSummary(gctx golly.Context, fb *feedback.Aggregate, pinned prompt.Refs) (tara.Summary, error) {
 	details, err := FeedbackService(gctx).FindDetailsByFeedbackID_Unsafe(gctx, fb.ID)
 	if err != nil {
 		gctx.Logger().Warnf("cannot find details for feedback %s %v", fb.ID.String(), err)
-		return err
+		return tara.Summary{}, err
 	}
 
 	strengths, _ := wsyiwig.ExtractTextFromJSON(details.Strengths)
 	opportunities, _ := wsyiwig.ExtractTextFromJSON(details.Opportunities)
 	additional, _ := wsyiwig.ExtractTextFromJSON(details.Additional)
 
-	prompt := tara.NewSummaryFeedbackPrompt(tara.SummarizeFeedbackInput{
+	// The summary is still useful without expectations, do not fail
+	// over the career framework
+	expectations, err := employees.ExpectationsForEmployee(gctx, fb.EmployeeID)
+	if err != nil {
+		gctx.Logger().Warnf("cannot load expectations for feedback %s %v", fb.ID.String(), err)
+	}
+
+	opts := tara.GenerateOptions{
+		Redactor: FeedbackRedactor(gctx, fb.OrganizationID),
+		Prompts:  pinned,
+	}
+
+	summary, err := tara.SummarizeFeedback(gctx, opts, tara.SummarizeFeedbackInput{
 		Strengths:          strengths,
 		Opportunities:      opportunities,
 		AdditionalComments: additional,
+		Expectations:       expectations.Strings(),
 	})
 
-	err = tara.Generate(gctx, prompt)
-	if err != nil {
-		return errors.WrapGeneric(err)
-	}
+	return summary, errors.WrapGeneric(err)
+}
 
-	itemsPrompt := tara.NewFollowUpItemsPrompt()
-	itemsPrompt.AddPreviousPrompts(prompt)
+// FeedbackRedactor returns a redactor seeded with the organization
+// directory when the organization has PII redaction enabled
+func FeedbackRedactor(gctx golly.Context, organizationID uuid.UUID) *tara.Redactor {
+	if !accounts.OrganizationSettings(gctx, organizationID).RedactPII {
+		return nil
+	}
 
-	err = tara.Generate(gctx, itemsPrompt)
+	people, err := employees.Service(gctx).FindEmployeesForOrganization(gctx)
 	if err != nil {
-		return errors.WrapGeneric(err)
+		gctx.Logger().Warnf("cannot load employees for redaction %s %v", organizationID.String(), err)
 	}
 
-	err = eventsource.Call(gctx, fb, feedback.CreateSummary{
-		Summary:     prompt.Summary,
-		ActionItems: itemsPrompt.FollowUpItems.Values(),
-	}, eventsource.Metadata{})
-
-	return errors.WrapGeneric(err)
+	return tara.NewRedactor(golly.Map(people, func(employee employees.Employee) tara.KnownPerson {
+		return tara.KnownPerson{Name: employee.Name, Email: employee.Email}
+	})...)
 }
@@ -0,0 +1,315 @@
+package reviews
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/golly-go/golly"
+	"github.com/golly-go/golly/errors"
+	"github.com/golly-go/plugins/orm"
+	"github.com/google/uuid"
+	"github.com/mitchrodrigues/talent-review-backend/app/domains/common"
+	"github.com/mitchrodrigues/talent-review-backend/app/domains/employees"
+	"github.com/mitchrodrigues/talent-review-backend/app/utils/identity"
+)
+
+const (
+	ReasonAdditional   = "ADDITIONAL"
+	ReasonTeam         = "TEAM"
+	ReasonPeer         = "PEER"
+	ReasonDirectReport = "DIRECT_REPORT"
+	ReasonCollaborator = "COLLABORATOR"
+
+	SkipDeclinedRecently = "DECLINED_RECENTLY"
+	SkipAtCapacity       = "AT_CAPACITY"
+	SkipAlreadyAsked     = "ALREADY_ASKED"
+
+	DefaultMaxOpenRequests = 5
+
+	recentlyDeclinedWindow = 90 * 24 * time.Hour
+	collaborationWindow    = 365 * 24 * time.Hour
+)
+
+// reasonWeights scores a candidate for each way they work with the
+// employee, having given or received feedback counts every time
+var reasonWeights = map[string]int{
+	ReasonTeam:         2,
+	ReasonPeer:         2,
+	ReasonDirectReport: 2,
+	ReasonCollaborator: 3,
+}
+
+// ReviewerSuggestion is a reviewer proposed for an employee and why,
+// SkipReason is set when they were passed over
+type ReviewerSuggestion struct {
+	Email   string
+	Reasons []string
+	Score   int
+
+	// OpenRequests is how many requests were waiting on the reviewer
+	// before this one, including the ones planned for earlier employees
+	OpenRequests int
+
+	SkipReason string
+}
+
+type openRequestRow struct {
+	Email      string
+	EmployeeID uuid.UUID
+	Count      int
+}
+
+// SuggestReviewers proposes input.SuggestReviewers reviewers for each of
+// the employees from their team, the other reports of their manager,
+// their direct reports and the people they exchanged feedback with.
+// Reviewers who declined recently, were already asked about the employee
+// or have input.MaxOpenRequests requests waiting on them are skipped, and
+// the load of the plan is spread across reviewers as it is built
+func SuggestReviewers(gctx golly.Context, manager employees.Employee, emps []employees.Employee, input CreateBulkFeedbackInput) ([]FeedbackPlan, error) {
+	maxOpen := cmp.Or(input.MaxOpenRequests, DefaultMaxOpenRequests)
+
+	open, asked, err := openRequests(gctx)
+	if err != nil {
+		return nil, err
+	}
+
+	declined, err := recentlyDeclined(gctx)
+	if err != nil {
+		return nil, err
+	}
+
+	peers, err := golly.LoadData(gctx, fmt.Sprintf("employees::manager::%s", manager.ID), func(golly.Context) ([]employees.Employee, error) {
+		return employees.Service(gctx).FindEmployeesByManagerID(gctx, manager.ID)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	plans := []FeedbackPlan{}
+
+	for _, employee := range emps {
+		candidates := reviewerCandidates{}
+
+		teamMates, err := getTeamMates(gctx, employee.TeamID)
+		if err != nil {
+			return plans, err
+		}
+		candidates.add(ReasonTeam, employeeEmails(teamMates)...)
+		candidates.add(ReasonPeer, employeeEmails(peers)...)
+
+		directs, err := employees.Service(gctx).FindEmployeesByManagerID(gctx, employee.ID)
+		if err != nil {
+			return plans, err
+		}
+		candidates.add(ReasonDirectReport, employeeEmails(directs)...)
+
+		collaborators, err := collaboratorEmails(gctx, employee)
+		if err != nil {
+			return plans, err
+		}
+		candidates.add(ReasonCollaborator, collaborators...)
+
+		additional := reviewerCandidates{}
+		additional.add(ReasonAdditional, input.AdditionalEmails...)
+
+		for _, email := range append([]string{employee.Email, manager.Email}, input.AdditionalEmails...) {
+			candidates.remove(email)
+		}
+		additional.remove(employee.Email)
+
+		plan := FeedbackPlan{
+			Employee:  employee,
+			Reviewers: additional.list(),
+			Skipped:   []ReviewerSuggestion{},
+		}
+
+		suggestions := candidates.list()
+		for i := range suggestions {
+			suggestions[i].OpenRequests = open[strings.ToLower(suggestions[i].Email)]
+		}
+
+		slices.SortFunc(suggestions, func(a, b ReviewerSuggestion) int {
+			return cmp.Or(
+				cmp.Compare(b.Score, a.Score),
+				cmp.Compare(a.OpenRequests, b.OpenRequests),
+				cmp.Compare(a.Email, b.Email),
+			)
+		})
+
+		picked := 0
+
+		for _, suggestion := range suggestions {
+			if picked == input.SuggestReviewers {
+				break
+			}
+
+			key := strings.ToLower(suggestion.Email)
+
+			switch {
+			case declined[key]:
+				suggestion.SkipReason = SkipDeclinedRecently
+			case asked[employee.ID][key]:
+				suggestion.SkipReason = SkipAlreadyAsked
+			case suggestion.OpenRequests >= maxOpen:
+				suggestion.SkipReason = SkipAtCapacity
+			}
+
+			if suggestion.SkipReason != "" {
+				plan.Skipped = append(plan.Skipped, suggestion)
+				continue
+			}
+
+			open[key]++
+			picked++
+
+			plan.Reviewers = append(plan.Reviewers, suggestion)
+		}
+
+		plans = append(plans, plan)
+	}
+
+	return plans, nil
+}
+
+// openRequests counts the requests that are neither submitted, declined
+// nor past their collection date by reviewer, and which employees each
+// reviewer was asked about
+func openRequests(gctx golly.Context) (map[string]int, map[uuid.UUID]map[string]bool, error) {
+	var rows []openRequestRow
+
+	err := orm.DB(gctx).
+		Model(&Feedback{}).
+		Select("LOWER(email) AS email, employee_id, COUNT(*) AS count").
+		Where("organization_id = ?", identity.FromContext(gctx).OrganizationID).
+		Where("submitted_at IS NULL AND declined_at IS NULL AND collection_end_at >= ?", time.Now()).
+		Group("LOWER(email), employee_id").
+		Scan(&rows).
+		Error
+
+	if err != nil {
+		return nil, nil, errors.WrapGeneric(err)
+	}
+
+	open := map[string]int{}
+	asked := map[uuid.UUID]map[string]bool{}
+
+	for _, row := range rows {
+		open[row.Email] += row.Count
+
+		if asked[row.EmployeeID] == nil {
+			asked[row.EmployeeID] = map[string]bool{}
+		}
+		asked[row.EmployeeID][row.Email] = true
+	}
+
+	return open, asked, nil
+}
+
+func recentlyDeclined(gctx golly.Context) (map[string]bool, error) {
+	var emails []string
+
+	err := orm.DB(gctx).
+		Model(&Feedback{}).
+		Where("organization_id = ?", identity.FromContext(gctx).OrganizationID).
+		Where("declined_at >= ?", time.Now().Add(-recentlyDeclinedWindow)).
+		Distinct().
+		Pluck("LOWER(email)", &emails).
+		Error
+
+	if err != nil {
+		return nil, errors.WrapGeneric(err)
+	}
+
+	declined := map[string]bool{}
+	for _, email := range emails {
+		declined[email] = true
+	}
+	return declined, nil
+}
+
+// collaboratorEmails lists who gave the employee feedback and who the
+// employee gave feedback to recently, once per feedback. Feedback whose
+// reviewer the current user may not see is left out
+func collaboratorEmails(gctx golly.Context, employee employees.Employee) ([]string, error) {
+	var given, received []string
+
+	since := time.Now().Add(-collaborationWindow)
+	organizationID := identity.FromContext(gctx).OrganizationID
+
+	err := orm.DB(gctx).
+		Model(&Feedback{}).
+		Scopes(common.JoinUserEmployeeRecord(gctx)).
+		Where("feedbacks.organization_id = ? AND feedbacks.employee_id = ?", organizationID, employee.ID).
+		Where("feedbacks.submitted_at >= ?", since).
+		Scopes(ReviewerVisibleScope(gctx)).
+		Pluck("feedbacks.email", &given).
+		Error
+
+	if err != nil {
+		return nil, errors.WrapGeneric(err)
+	}
+
+	err = orm.DB(gctx).
+		Model(&Feedback{}).
+		Joins("JOIN employees reviewed ON reviewed.id = feedbacks.employee_id").
+		Scopes(common.JoinUserEmployeeRecord(gctx)).
+		Where("feedbacks.organization_id = ? AND LOWER(feedbacks.email) = ?", organizationID, strings.ToLower(employee.Email)).
+		Where("feedbacks.submitted_at >= ?", since).
+		Scopes(ReviewerVisibleScope(gctx)).
+		Pluck("reviewed.email", &received).
+		Error
+
+	if err != nil {
+		return nil, errors.WrapGeneric(err)
+	}
+
+	return append(given, received...), nil
+}
+
+// reviewerCandidates collects reviewers by case insensitive email in the
+// order they were first added, keeping the email as first given
+type reviewerCandidates struct {
+	order   []string
+	byEmail map[string]*ReviewerSuggestion
+}
+
+func (candidates *reviewerCandidates) add(reason string, emails ...string) {
+	if candidates.byEmail == nil {
+		candidates.byEmail = map[string]*ReviewerSuggestion{}
+	}
+
+	for _, email := range emails {
+		key := strings.ToLower(strings.TrimSpace(email))
+		if key == "" {
+			continue
+		}
+
+		candidate, ok := candidates.byEmail[key]
+		if !ok {
+			candidate = &ReviewerSuggestion{Email: strings.TrimSpace(email), Reasons: []string{}}
+			candidates.byEmail[key] = candidate
+			candidates.order = append(candidates.order, key)
+		}
+
+		if !slices.Contains(candidate.Reasons, reason) {
+			candidate.Reasons = append(candidate.Reasons, reason)
+		}
+		candidate.Score += reasonWeights[reason]
+	}
+}
+
+func (candidates *reviewerCandidates) remove(email string) {
+	key := strings.ToLower(strings.TrimSpace(email))
+
+	delete(candidates.byEmail, key)
+	candidates.order = slices.DeleteFunc(candidates.order, func(k string) bool { return k == key })
+}
+
+func (candidates reviewerCandidates) list() []ReviewerSuggestion {
+	return golly.Map(candidates.order, func(key string) ReviewerSuggestion {
+		return *candidates.byEmail[key]
+	})
+}
@@ -0,0 +1,91 @@
+package reviews
+
+import (
+	"github.com/golly-go/golly"
+	"github.com/golly-go/plugins/gql"
+	"github.com/graphql-go/graphql"
+	"github.com/mitchrodrigues/talent-review-backend/app/domains/employees"
+)
+
+var (
+	reviewerSuggestionType = graphql.NewObject(graphql.ObjectConfig{
+		Name: "ReviewerSuggestion",
+		Fields: graphql.Fields{
+			"email": {
+				Type: graphql.NewNonNull(graphql.String),
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					return p.Source.(ReviewerSuggestion).Email, nil
+				},
+			},
+			"reasons": {
+				Type:        graphql.NewList(graphql.String),
+				Description: "ADDITIONAL, TEAM, PEER, DIRECT_REPORT or COLLABORATOR",
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					return p.Source.(ReviewerSuggestion).Reasons, nil
+				},
+			},
+			"score": {
+				Type: graphql.Int,
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					return p.Source.(ReviewerSuggestion).Score, nil
+				},
+			},
+			"openRequests": {
+				Type: graphql.Int,
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					return p.Source.(ReviewerSuggestion).OpenRequests, nil
+				},
+			},
+			"skipReason": {
+				Type:        graphql.String,
+				Description: "DECLINED_RECENTLY, ALREADY_ASKED or AT_CAPACITY when the reviewer was passed over",
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					if reason := p.Source.(ReviewerSuggestion).SkipReason; reason != "" {
+						return reason, nil
+					}
+					return nil, nil
+				},
+			},
+		},
+	})
+
+	feedbackPlanType = graphql.NewObject(graphql.ObjectConfig{
+		Name: "FeedbackPlan",
+		Fields: graphql.Fields{
+			"employee": {
+				Type: employees.EmployeeGQLType,
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					return p.Source.(FeedbackPlan).Employee, nil
+				},
+			},
+			"reviewers": {
+				Type: graphql.NewList(reviewerSuggestionType),
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					return p.Source.(FeedbackPlan).Reviewers, nil
+				},
+			},
+			"skipped": {
+				Type: graphql.NewList(reviewerSuggestionType),
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					return p.Source.(FeedbackPlan).Skipped, nil
+				},
+			},
+		},
+	})
+
+	suggestionMutations = graphql.Fields{
+		"previewFeedbacks": {
+			Name:        "previewFeedbacks",
+			Type:        graphql.NewList(feedbackPlanType),
+			Description: "Who createFeedbacks would ask for feedback on each employee, nothing is sent",
+			Args: graphql.FieldConfigArgument{
+				"input": &graphql.ArgumentConfig{Type: graphql.NewNonNull(createTeamInputType)},
+			},
+			Resolve: gql.NewHandler(gql.Options{
+				Handler: func(wctx golly.WebContext, params gql.Params) (interface{}, error) {
+					return PlanBulkFeedback(wctx.Context, bulkFeedbackInput(params.Input))
+				},
+			}),
+		},
+	}
+)
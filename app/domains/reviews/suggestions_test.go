@@ -0,0 +1,161 @@
+package reviews
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/golly-go/golly"
+	"github.com/golly-go/plugins/eventsource"
+	"github.com/golly-go/plugins/orm"
+	"github.com/google/uuid"
+	"github.com/mitchrodrigues/talent-review-backend/app/domains/accounts"
+	"github.com/mitchrodrigues/talent-review-backend/app/domains/employees"
+	"github.com/mitchrodrigues/talent-review-backend/app/domains/reviews/feedback"
+	"github.com/mitchrodrigues/talent-review-backend/app/utils/esbackend"
+	"github.com/mitchrodrigues/talent-review-backend/app/utils/identity"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSuggestReviewers(t *testing.T) {
+	gctx := orm.CreateTestContext(golly.NewContext(context.TODO()),
+		Feedback{},
+		accounts.Organization{},
+		accounts.User{},
+		employees.Employee{},
+		esbackend.Event{})
+
+	organizationID := uuid.New()
+	managerUserID := uuid.New()
+	teamID := uuid.New()
+	now := time.Now()
+
+	manager := employees.NewTestEmployee(uuid.New(), organizationID, "manager@example.com", &managerUserID)
+	orm.DB(gctx).Create(&manager)
+
+	report := employees.NewTestEmployeeWithTeam(uuid.New(), organizationID, "report@example.com", teamID)
+	report.ManagerID = &manager.ID
+	orm.DB(gctx).Create(&report)
+
+	other := employees.NewTestEmployee(uuid.New(), organizationID, "other@example.com", nil)
+	orm.DB(gctx).Create(&other)
+
+	// the reviewer already gave the report feedback
+	collaborator := NewTestSubmittedFeedback(uuid.New(), organizationID, report.ID, "reviewer@example.com")
+	orm.DB(gctx).Create(&collaborator)
+
+	ctx := identity.ToContext(gctx, identity.Identity{
+		UID:            managerUserID,
+		OrganizationID: organizationID,
+		EmployeeID:     manager.ID,
+	})
+
+	for _, email := range []string{"a-busy@example.com", "b-declined@example.com", "c-team@example.com"} {
+		teamMate := employees.NewTestEmployeeWithTeam(uuid.New(), organizationID, email, teamID)
+		orm.DB(gctx).Create(&teamMate)
+	}
+
+	second := employees.NewTestEmployee(uuid.New(), organizationID, "second@example.com", nil)
+	second.ManagerID = &manager.ID
+	orm.DB(gctx).Create(&second)
+
+	createFeedback := func(email string, employeeID uuid.UUID, declinedAt *time.Time) {
+		orm.DB(gctx).Create(&Feedback{Aggregate: feedback.Aggregate{
+			ModelUUID:       orm.ModelUUID{ID: uuid.New()},
+			Code:            uuid.NewString(),
+			Email:           email,
+			EmployeeID:      employeeID,
+			OrganizationID:  organizationID,
+			CollectionEndAt: now.AddDate(0, 0, 7),
+			DeclinedAt:      declinedAt,
+		}})
+	}
+
+	createFeedback("A-Busy@example.com", other.ID, nil)
+	createFeedback("a-busy@example.com", second.ID, nil)
+	createFeedback("b-declined@example.com", other.ID, &now)
+
+	input := CreateBulkFeedbackInput{
+		EmployeeIDs:      []uuid.UUID{report.ID},
+		CollectionEndAt:  now.AddDate(0, 0, 7),
+		SuggestReviewers: 4,
+		MaxOpenRequests:  2,
+	}
+
+	emails := func(suggestions []ReviewerSuggestion) []string {
+		ret := []string{}
+		for _, suggestion := range suggestions {
+			ret = append(ret, suggestion.Email+":"+suggestion.SkipReason)
+		}
+		return ret
+	}
+
+	t.Run("ranks collaborators first and skips declined and busy reviewers", func(t *testing.T) {
+		plans, err := PlanBulkFeedback(ctx, input)
+		assert.NoError(t, err)
+
+		if assert.Len(t, plans, 1) {
+			assert.Equal(t, report.ID, plans[0].Employee.ID)
+			assert.Equal(t, []string{"reviewer@example.com:", "c-team@example.com:", "second@example.com:"}, emails(plans[0].Reviewers))
+			assert.Equal(t, []string{"b-declined@example.com:DECLINED_RECENTLY", "a-busy@example.com:AT_CAPACITY"}, emails(plans[0].Skipped))
+
+			assert.Equal(t, []string{ReasonCollaborator}, plans[0].Reviewers[0].Reasons)
+			assert.Equal(t, []string{ReasonPeer}, plans[0].Reviewers[2].Reasons)
+			assert.Equal(t, 2, plans[0].Skipped[1].OpenRequests)
+		}
+	})
+
+	t.Run("stops at the requested number of reviewers", func(t *testing.T) {
+		plans, err := PlanBulkFeedback(ctx, CreateBulkFeedbackInput{
+			EmployeeIDs:      input.EmployeeIDs,
+			AdditionalEmails: []string{"outside@example.com", "Report@example.com"},
+			SuggestReviewers: 1,
+		})
+		assert.NoError(t, err)
+
+		if assert.Len(t, plans, 1) {
+			assert.Equal(t, []string{"outside@example.com:", "reviewer@example.com:"}, emails(plans[0].Reviewers))
+			assert.Empty(t, plans[0].Skipped)
+		}
+	})
+
+	t.Run("reviewers already asked are skipped", func(t *testing.T) {
+		created, err := CreateBulkFeedback(ctx, input, eventsource.Metadata{})
+		assert.NoError(t, err)
+		assert.Len(t, created, 3)
+
+		plans, err := PlanBulkFeedback(ctx, input)
+		assert.NoError(t, err)
+
+		if assert.Len(t, plans, 1) {
+			assert.Empty(t, plans[0].Reviewers)
+			assert.Contains(t, emails(plans[0].Skipped), "reviewer@example.com:ALREADY_ASKED")
+		}
+	})
+	t.Run("anonymous reviewers are not collaborators", func(t *testing.T) {
+		createSubmitted := func(email string, employeeID uuid.UUID) {
+			orm.DB(gctx).Create(&Feedback{Aggregate: feedback.Aggregate{
+				ModelUUID:       orm.ModelUUID{ID: uuid.New()},
+				Code:            uuid.NewString(),
+				Email:           email,
+				EmployeeID:      employeeID,
+				OrganizationID:  organizationID,
+				CollectionEndAt: now.AddDate(0, 0, 7),
+				SubmittedAt:     &now,
+				Visibility:      feedback.VisibilityAnonymous,
+			}})
+		}
+
+		// an anonymous reviewer of the report and anonymous feedback the
+		// report gave
+		createSubmitted("anonymous@example.com", report.ID)
+		createSubmitted(report.Email, other.ID)
+
+		collaborators, err := collaboratorEmails(ctx, report)
+		assert.NoError(t, err)
+
+		assert.Contains(t, collaborators, "reviewer@example.com")
+		assert.NotContains(t, collaborators, "anonymous@example.com")
+		assert.NotContains(t, collaborators, other.Email)
+	})
+}
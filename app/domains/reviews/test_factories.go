@@ -0,0 +1,45 @@
+package reviews
+
+import (
+	"time"
+
+	"github.com/golly-go/plugins/orm"
+	"github.com/google/uuid"
+	"github.com/mitchrodrigues/talent-review-backend/app/domains/reviews/feedback"
+)
+
+func NewTestFeedback(id, organizationID, employeeID uuid.UUID, email string) Feedback {
+	return Feedback{
+		Aggregate: feedback.Aggregate{
+			ModelUUID: orm.ModelUUID{
+				ID: id,
+			},
+			Code:            uuid.NewString(),
+			Email:           email,
+			EmployeeID:      employeeID,
+			OrganizationID:  organizationID,
+			CollectionEndAt: time.Now().Add(24 * time.Hour),
+		},
+	}
+}
+
+func NewTestSubmittedFeedback(id, organizationID, employeeID uuid.UUID, email string) Feedback {
+	fb := NewTestFeedback(id, organizationID, employeeID, email)
+
+	submittedAt := time.Now()
+	fb.SubmittedAt = &submittedAt
+
+	return fb
+}
+
+func NewTestFeedbackDetails(fb Feedback, strengths, opportunities string) FeedbackDetails {
+	return FeedbackDetails{
+		FeedbackDetails: feedback.FeedbackDetails{
+			FeedbackID:     fb.ID,
+			EmployeeID:     fb.EmployeeID,
+			OrganizationID: fb.OrganizationID,
+			Strengths:      strengths,
+			Opportunities:  opportunities,
+		},
+	}
+}
@@ -0,0 +1,209 @@
+package reviews
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/golly-go/golly"
+	"github.com/golly-go/golly/errors"
+	"github.com/golly-go/plugins/eventsource"
+	"github.com/golly-go/plugins/orm"
+	"github.com/google/uuid"
+	"github.com/mitchrodrigues/talent-review-backend/app/domains/accounts"
+	"github.com/mitchrodrigues/talent-review-backend/app/domains/common"
+	"github.com/mitchrodrigues/talent-review-backend/app/domains/employees"
+	"github.com/mitchrodrigues/talent-review-backend/app/domains/reviews/cycle"
+	"github.com/mitchrodrigues/talent-review-backend/app/domains/reviews/feedback"
+	"github.com/mitchrodrigues/talent-review-backend/app/utils/identity"
+	"gorm.io/gorm"
+)
+
+// feedbackViewer is who is looking at feedback, public viewers are
+// reviewers using the code of their feedback
+type feedbackViewer struct {
+	Public bool
+	HR     bool
+	Email  string
+
+	// Threshold is how many responses aggregated-only feedback needs
+	// in the organization of the viewer
+	Threshold int
+}
+
+func (viewer feedbackViewer) isReviewer(fb Feedback) bool {
+	return viewer.Public || (viewer.Email != "" && strings.EqualFold(viewer.Email, fb.Email))
+}
+
+func currentFeedbackViewer(gctx golly.Context) feedbackViewer {
+	ident := identity.FromContext(gctx)
+
+	if ident.UID == uuid.Nil {
+		return feedbackViewer{Public: true}
+	}
+
+	viewer, _ := golly.LoadData(gctx, fmt.Sprintf("feedback:viewer:%s", ident.UID), func(golly.Context) (feedbackViewer, error) {
+		viewer := feedbackViewer{
+			Threshold: accounts.OrganizationSettings(gctx, ident.OrganizationID).AggregatedThreshold(),
+		}
+
+		_, err := accounts.RequireHR(gctx)
+		viewer.HR = err == nil
+
+		if ident.EmployeeID != uuid.Nil {
+			var emails []string
+
+			orm.DB(gctx).
+				Model(&employees.Employee{}).
+				Where("id = ? AND organization_id = ?", ident.EmployeeID, ident.OrganizationID).
+				Pluck("email", &emails)
+
+			if len(emails) > 0 {
+				viewer.Email = emails[0]
+			}
+		}
+
+		return viewer, nil
+	})
+
+	return viewer
+}
+
+// CanSeeReviewer is true when the current user may see who gave the
+// feedback: attributed feedback, HR and the reviewer themselves
+func CanSeeReviewer(gctx golly.Context, fb Feedback) bool {
+	if fb.Visibility == "" || fb.Visibility == feedback.VisibilityAttributed {
+		return true
+	}
+
+	viewer := currentFeedbackViewer(gctx)
+	return viewer.HR || viewer.isReviewer(fb)
+}
+
+// CanSeeContent is true when the current user may read the feedback,
+// aggregated-only feedback is held back (from HR too) until enough
+// reviewers asked in the same round responded
+func CanSeeContent(gctx golly.Context, fb Feedback) bool {
+	if fb.Visibility != feedback.VisibilityAggregated {
+		return true
+	}
+
+	viewer := currentFeedbackViewer(gctx)
+	if viewer.isReviewer(fb) {
+		return true
+	}
+
+	return aggregatedResponses(gctx, fb) >= int64(viewer.Threshold)
+}
+
+// aggregatedResponses counts the aggregated-only feedback submitted for
+// the employee in the same round, rounds share their collection end
+func aggregatedResponses(gctx golly.Context, fb Feedback) int64 {
+	key := fmt.Sprintf("feedback:aggregated:%s:%d", fb.EmployeeID, fb.CollectionEndAt.UnixNano())
+
+	count, _ := golly.LoadData(gctx, key, func(golly.Context) (int64, error) {
+		var count int64
+
+		err := orm.DB(gctx).
+			Model(&Feedback{}).
+			Where("organization_id = ? AND employee_id = ? AND collection_end_at = ?", fb.OrganizationID, fb.EmployeeID, fb.CollectionEndAt).
+			Where("visibility = ? AND submitted_at IS NOT NULL", feedback.VisibilityAggregated).
+			Count(&count).
+			Error
+
+		return count, err
+	})
+
+	return count
+}
+
+// currentCycleVisibility is the feedback visibility of the cycle of the
+// current user running now, empty without one
+func currentCycleVisibility(gctx golly.Context) string {
+	ident := identity.FromContext(gctx)
+
+	var visibility []string
+
+	orm.DB(gctx).
+		Model(&Cycle{}).
+		Where("organization_id = ? AND owner_id = ?", ident.OrganizationID, ident.UID).
+		Where("start_at <= ? AND end_at >= ?", time.Now(), time.Now()).
+		Order("start_at DESC").
+		Limit(1).
+		Pluck("feedback_visibility", &visibility)
+
+	if len(visibility) == 0 {
+		return ""
+	}
+	return visibility[0]
+}
+
+// SetCycleFeedbackVisibility changes the visibility of the feedback
+// requested during a cycle, only its owner and HR can change it
+func SetCycleFeedbackVisibility(gctx golly.Context, id uuid.UUID, visibility string, metadata eventsource.Metadata) (Cycle, error) {
+	var record Cycle
+
+	ident := identity.FromContext(gctx)
+
+	err := orm.DB(gctx).
+		Model(&Cycle{}).
+		Scopes(common.OrganizationIDScopeForContext(gctx)).
+		First(&record, "id = ?", id).
+		Error
+
+	if err != nil {
+		return record, errors.WrapNotFound(err)
+	}
+
+	if record.OwnerID != ident.UID {
+		if _, err := accounts.RequireHR(gctx); err != nil {
+			return record, err
+		}
+	}
+
+	err = eventsource.Call(gctx, &record.Aggregate, cycle.SetFeedbackVisibility{Visibility: visibility}, metadata)
+	return record, err
+}
+
+// maskReviewers blanks the email of the feedback whose reviewer the
+// current user may not see
+func maskReviewers(gctx golly.Context, feedbacks []Feedback) []Feedback {
+	for i := range feedbacks {
+		if !CanSeeReviewer(gctx, feedbacks[i]) {
+			feedbacks[i].Email = ""
+		}
+	}
+	return feedbacks
+}
+
+// ReviewerVisibleScope leaves out the feedback whose reviewer the current
+// user may not see, as CanSeeReviewer does. It needs the feedbacks table
+// and the user_employee_record join
+func ReviewerVisibleScope(gctx golly.Context) func(*gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		if currentFeedbackViewer(gctx).HR {
+			return db
+		}
+
+		return db.Where("feedbacks.visibility IN ? OR feedbacks.email = user_employee_record.email",
+			[]string{"", feedback.VisibilityAttributed})
+	}
+}
+
+// AggregatedContentScope leaves out aggregated-only feedback that has
+// not reached the response threshold, except for its reviewer. It needs
+// the feedbacks table and the user_employee_record join
+func AggregatedContentScope(gctx golly.Context) func(*gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		threshold := accounts.OrganizationSettings(gctx, identity.FromContext(gctx).OrganizationID).AggregatedThreshold()
+
+		responses := orm.DB(gctx).
+			Table("feedbacks responses").
+			Select("COUNT(*)").
+			Where("responses.employee_id = feedbacks.employee_id AND responses.collection_end_at = feedbacks.collection_end_at").
+			Where("responses.visibility = ? AND responses.submitted_at IS NOT NULL AND responses.deleted_at IS NULL", feedback.VisibilityAggregated)
+
+		return db.Where("feedbacks.visibility <> ? OR feedbacks.email = user_employee_record.email OR (?) >= ?",
+			feedback.VisibilityAggregated, responses, threshold)
+	}
+}
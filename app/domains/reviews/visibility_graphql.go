@@ -0,0 +1,65 @@
+package reviews
+
+import (
+	"github.com/golly-go/golly"
+	"github.com/golly-go/plugins/gql"
+	"github.com/graphql-go/graphql"
+	"github.com/mitchrodrigues/talent-review-backend/app/utils/helpers"
+)
+
+var (
+	cycleType = graphql.NewObject(graphql.ObjectConfig{
+		Name: "Cycle",
+		Fields: graphql.Fields{
+			"id": {
+				Type: graphql.NewNonNull(graphql.String),
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					return p.Source.(Cycle).ID, nil
+				},
+			},
+			"startAt": {
+				Type: graphql.DateTime,
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					return p.Source.(Cycle).StartAt, nil
+				},
+			},
+			"endAt": {
+				Type: graphql.DateTime,
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					return p.Source.(Cycle).EndAt, nil
+				},
+			},
+			"feedbackVisibility": {
+				Type:        feedbackVisibilityType,
+				Description: "Visibility of the feedback requested during the cycle",
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					if visibility := p.Source.(Cycle).FeedbackVisibility; visibility != "" {
+						return visibility, nil
+					}
+					return nil, nil
+				},
+			},
+		},
+	})
+
+	visibilityMutations = graphql.Fields{
+		"setCycleFeedbackVisibility": &graphql.Field{
+			Type:        cycleType,
+			Description: "Set the visibility of the feedback requested during a cycle, the cycle owner or HR only",
+			Args: graphql.FieldConfigArgument{
+				"id":         &graphql.ArgumentConfig{Type: graphql.NewNonNull(graphql.String)},
+				"visibility": &graphql.ArgumentConfig{Type: graphql.NewNonNull(feedbackVisibilityType)},
+			},
+			Resolve: gql.NewHandler(gql.Options{
+				Handler: func(wctx golly.WebContext, params gql.Params) (interface{}, error) {
+					id, err := helpers.ExtractAndParseUUID(params.Args, "id")
+					if err != nil {
+						return nil, err
+					}
+
+					return SetCycleFeedbackVisibility(wctx.Context, id, params.Args["visibility"].(string), params.Metadata())
+				},
+			}),
+		},
+	}
+)
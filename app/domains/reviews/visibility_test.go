@@ -0,0 +1,147 @@
+package reviews
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/golly-go/golly"
+	"github.com/golly-go/plugins/eventsource"
+	"github.com/golly-go/plugins/orm"
+	"github.com/google/uuid"
+	"github.com/mitchrodrigues/talent-review-backend/app/domains/accounts"
+	"github.com/mitchrodrigues/talent-review-backend/app/domains/accounts/users"
+	"github.com/mitchrodrigues/talent-review-backend/app/domains/employees"
+	"github.com/mitchrodrigues/talent-review-backend/app/domains/reviews/cycle"
+	"github.com/mitchrodrigues/talent-review-backend/app/domains/reviews/feedback"
+	"github.com/mitchrodrigues/talent-review-backend/app/utils/esbackend"
+	"github.com/mitchrodrigues/talent-review-backend/app/utils/identity"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+)
+
+func TestFeedbackVisibility(t *testing.T) {
+	gctx := orm.CreateTestContext(golly.NewContext(context.TODO()),
+		Feedback{}, Cycle{}, employees.Employee{}, accounts.User{}, accounts.Organization{}, esbackend.Event{})
+
+	organizationID := uuid.New()
+
+	createUser := func(firstName, role string) accounts.User {
+		user := accounts.User{Aggregate: users.Aggregate{OrganizationID: organizationID, Email: firstName + "@example.com", FirstName: firstName, Role: role}}
+		orm.DB(gctx).Create(&user)
+		return user
+	}
+
+	managerUser := createUser("morgan", users.RoleMember)
+	peerUser := createUser("peer", users.RoleMember)
+	hrUser := createUser("harper", users.RoleHR)
+
+	manager := employees.NewTestEmployee(uuid.New(), organizationID, "morgan@example.com", &managerUser.ID)
+	orm.DB(gctx).Create(&manager)
+
+	report := employees.NewTestEmployee(uuid.New(), organizationID, "jane@example.com", nil)
+	report.ManagerID = &manager.ID
+	orm.DB(gctx).Create(&report)
+
+	peer := employees.NewTestEmployee(uuid.New(), organizationID, "peer@example.com", &peerUser.ID)
+	orm.DB(gctx).Create(&peer)
+
+	// identities are set on the shared context so each call sets its own
+	as := func(userID, employeeID uuid.UUID) golly.Context {
+		return identity.ToContext(gctx, identity.Identity{UID: userID, OrganizationID: organizationID, EmployeeID: employeeID})
+	}
+	asManager := func() golly.Context { return as(managerUser.ID, manager.ID) }
+	asPeer := func() golly.Context { return as(peerUser.ID, peer.ID) }
+	asHR := func() golly.Context { return as(hrUser.ID, uuid.Nil) }
+
+	createFeedback := func(visibility, email string, collectionEndAt time.Time, submitted bool) Feedback {
+		fb := Feedback{Aggregate: feedback.Aggregate{
+			ModelUUID:       orm.ModelUUID{ID: uuid.New()},
+			Code:            uuid.NewString(),
+			Email:           email,
+			EmployeeID:      report.ID,
+			OrganizationID:  organizationID,
+			CollectionEndAt: collectionEndAt,
+			Visibility:      visibility,
+		}}
+		if submitted {
+			now := time.Now()
+			fb.SubmittedAt = &now
+		}
+		orm.DB(gctx).Create(&fb)
+		return fb
+	}
+
+	t.Run("the cycle sets the visibility of its owner", func(t *testing.T) {
+		current := Cycle{Aggregate: cycle.Aggregate{
+			OrganizationID: organizationID,
+			OwnerID:        managerUser.ID,
+			StartAt:        time.Now().AddDate(0, -1, 0),
+			EndAt:          time.Now().AddDate(0, 1, 0),
+		}}
+		orm.DB(gctx).Create(&current)
+
+		_, err := SetCycleFeedbackVisibility(asPeer(), current.ID, feedback.VisibilityAnonymous, eventsource.Metadata{})
+		assert.Error(t, err)
+
+		_, err = SetCycleFeedbackVisibility(asManager(), current.ID, "SECRET", eventsource.Metadata{})
+		assert.ErrorContains(t, err, feedback.ErrorInvalidVisibility.Error())
+
+		updated, err := SetCycleFeedbackVisibility(asManager(), current.ID, feedback.VisibilityAnonymous, eventsource.Metadata{})
+		assert.NoError(t, err)
+		assert.Equal(t, feedback.VisibilityAnonymous, updated.FeedbackVisibility)
+
+		assert.Equal(t, feedback.VisibilityAnonymous, currentCycleVisibility(asManager()))
+		assert.Equal(t, "", currentCycleVisibility(asPeer()))
+	})
+
+	t.Run("anonymous reviewers are shown to HR and themselves only", func(t *testing.T) {
+		fb := createFeedback(feedback.VisibilityAnonymous, "Peer@example.com", time.Now().AddDate(0, 0, 7), true)
+
+		assert.False(t, CanSeeReviewer(asManager(), fb))
+		assert.True(t, CanSeeReviewer(asPeer(), fb))
+		assert.True(t, CanSeeReviewer(asHR(), fb))
+		assert.True(t, CanSeeContent(asManager(), fb))
+
+		feedbacks, err := FeedbackService(gctx).FindAll_Permissioned(asManager(), func(db *gorm.DB) *gorm.DB {
+			return db.Where("feedbacks.id = ?", fb.ID)
+		})
+		assert.NoError(t, err)
+		if assert.Len(t, feedbacks, 1) {
+			assert.Empty(t, feedbacks[0].Email)
+		}
+
+		attributed := createFeedback("", "lead@example.com", time.Now().AddDate(0, 0, 7), true)
+		assert.True(t, CanSeeReviewer(asManager(), attributed))
+	})
+
+	t.Run("aggregated feedback needs enough responses", func(t *testing.T) {
+		round := time.Now().AddDate(0, 0, 14).Truncate(time.Second)
+
+		fb := createFeedback(feedback.VisibilityAggregated, "peer@example.com", round, true)
+		createFeedback(feedback.VisibilityAggregated, "lead@example.com", round, true)
+		createFeedback(feedback.VisibilityAggregated, "late@example.com", round, false)
+
+		assert.False(t, CanSeeContent(asManager(), fb))
+		assert.False(t, CanSeeContent(asHR(), fb))
+		assert.True(t, CanSeeContent(asPeer(), fb))
+		assert.False(t, CanSeeReviewer(asManager(), fb))
+
+		scoped := func(ctx golly.Context) []Feedback {
+			feedbacks, err := FeedbackService(ctx).FindAll_Permissioned(ctx, AggregatedContentScope(ctx), func(db *gorm.DB) *gorm.DB {
+				return db.Where("feedbacks.collection_end_at = ?", round)
+			})
+			assert.NoError(t, err)
+			return feedbacks
+		}
+		assert.Len(t, scoped(asManager()), 0)
+		assert.Len(t, scoped(asPeer()), 1)
+
+		other := time.Now().AddDate(0, 0, 21).Truncate(time.Second)
+		for _, email := range []string{"a@example.com", "b@example.com", "c@example.com"} {
+			fb = createFeedback(feedback.VisibilityAggregated, email, other, true)
+		}
+		assert.True(t, CanSeeContent(asManager(), fb))
+		assert.False(t, CanSeeReviewer(asManager(), fb))
+	})
+}
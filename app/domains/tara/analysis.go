@@ -0,0 +1,126 @@
+package tara
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/golly-go/golly"
+	"github.com/mitchrodrigues/talent-review-backend/app/domains/tara/prompt"
+	"github.com/mitchrodrigues/talent-review-backend/app/utils/openai"
+)
+
+const (
+	FindingGenderedLanguage    = "gendered_language"
+	FindingPersonalityCritique = "personality_critique"
+	FindingUnsupportedClaim    = "unsupported_claim"
+)
+
+var (
+	FindingCategories = []string{
+		FindingGenderedLanguage,
+		FindingPersonalityCritique,
+		FindingUnsupportedClaim,
+	}
+
+	feedbackAnalysisDefaults = PromptDefaults{
+		Rules: []string{
+			"Only report findings in these categories: gendered_language, personality_critique, unsupported_claim.",
+			"gendered_language: gendered or stereotyped wording (for example \"abrasive\", \"emotional\", \"bossy\"), comments on appearance, age, family or other traits unrelated to the work.",
+			"personality_critique: a critique of who the person is rather than of a behavior or outcome of their work.",
+			"unsupported_claim: a vague or sweeping claim with no example, situation or impact to support it.",
+			"Quote the exact words of the feedback the finding is about in excerpt.",
+			"Praise can be vague or unsupported too, report it the same way.",
+			"Return no findings when the feedback is specific, behavioral and inclusive, do not invent problems.",
+		},
+		Scenario: []string{
+			"You are reviewing feedback an employee received for bias and inclusivity so HR can spot systemic patterns.",
+			"Classify problems in the feedback, do not judge the employee.",
+		},
+	}
+)
+
+type AnalysisFinding struct {
+	Category    string `json:"category" ai:"string one of gendered_language, personality_critique, unsupported_claim"`
+	Excerpt     string `json:"excerpt" ai:"string exact words of the feedback the finding is about"`
+	Explanation string `json:"explanation" ai:"string one sentence explaining the finding"`
+}
+
+type FeedbackAnalysisPrompt struct {
+	openai.CompletionPromptBase `json:"-"`
+	SummarizeFeedbackInput      `json:"-"`
+	Versioned                   `json:"-"`
+
+	Findings []AnalysisFinding `json:"findings" ai:"findings in the feedback" max:"10"`
+}
+
+func (prompt FeedbackAnalysisPrompt) Context(gctx golly.Context) openai.AIContexts {
+	return openai.AIContexts{
+		openai.NewDefaultContentRole(openai.RoleUser,
+			fmt.Sprintf(
+				"Strengths: %s\nOpportunities: %s\nAdditional Comments: %s",
+				prompt.Strengths,
+				prompt.Opportunities,
+				prompt.AdditionalComments,
+			),
+		),
+	}
+}
+
+func (prompt FeedbackAnalysisPrompt) Rules(gctx golly.Context) []string {
+	return prompt.Version.RulesOr(feedbackAnalysisDefaults.Rules)
+}
+
+func (prompt FeedbackAnalysisPrompt) Scenario(gctx golly.Context) []string {
+	return prompt.Version.ScenarioOr(feedbackAnalysisDefaults.Scenario)
+}
+
+func NewFeedbackAnalysisPrompt(input SummarizeFeedbackInput) *FeedbackAnalysisPrompt {
+	return &FeedbackAnalysisPrompt{SummarizeFeedbackInput: input}
+}
+
+// Analysis is the bias and inclusivity classification of a feedback
+type Analysis struct {
+	Findings       []AnalysisFinding `json:"findings"`
+	PromptVersions prompt.Refs       `json:"promptVersions"`
+
+	// Skipped is set when the organization has run over its budget, the
+	// analysis is optional and nothing was generated
+	Skipped bool `json:"skipped"`
+}
+
+// Count returns the number of findings of the category
+func (a Analysis) Count(category string) int {
+	return len(golly.Filter(a.Findings, func(finding AnalysisFinding) bool {
+		return finding.Category == category
+	}))
+}
+
+// AnalyzeFeedback classifies the feedback for gendered language,
+// personality critiques and unsupported claims
+func AnalyzeFeedback(gctx golly.Context, opts GenerateOptions, input SummarizeFeedbackInput) (Analysis, error) {
+	result := Analysis{Findings: []AnalysisFinding{}, PromptVersions: prompt.Refs{}}
+
+	if Degraded(gctx) {
+		result.Skipped = true
+		return result, nil
+	}
+
+	analysisPrompt := NewFeedbackAnalysisPrompt(input)
+
+	if err := GenerateWithOptions(gctx, opts, analysisPrompt); err != nil {
+		return result, err
+	}
+
+	for _, finding := range analysisPrompt.Findings {
+		finding.Category = strings.ToLower(strings.TrimSpace(finding.Category))
+
+		if slices.Contains(FindingCategories, finding.Category) {
+			result.Findings = append(result.Findings, finding)
+		}
+	}
+
+	result.PromptVersions[analysisPrompt.Version.Name] = analysisPrompt.Version.Ref
+
+	return result, nil
+}
@@ -0,0 +1,38 @@
+package tara
+
+import (
+	"testing"
+
+	"github.com/golly-go/golly"
+	"github.com/mitchrodrigues/talent-review-backend/app/utils/openai"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAnalyzeFeedback(t *testing.T) {
+	_, gctx := newPromptTestContext()
+
+	provider := &capturingProvider{FixtureProvider: openai.FixtureProvider{Default: `{"findings":[
+		{"category":"gendered_language","excerpt":"bossy","explanation":"Gendered descriptor."},
+		{"category":" Unsupported_Claim ","excerpt":"great teammate","explanation":"No example."},
+		{"category":"unsupported_claim","excerpt":"always late","explanation":"Sweeping claim."},
+		{"category":"tone","excerpt":"meh","explanation":"Not a category."}
+	]}`}}
+
+	analysis, err := AnalyzeFeedback(openai.UseProvider(gctx, provider), GenerateOptions{}, SummarizeFeedbackInput{
+		Strengths:     "A great teammate.",
+		Opportunities: "Can be bossy and is always late.",
+	})
+
+	assert.NoError(t, err)
+	assert.False(t, analysis.Skipped)
+	assert.Len(t, analysis.Findings, 3)
+	assert.Equal(t, 1, analysis.Count(FindingGenderedLanguage))
+	assert.Equal(t, 0, analysis.Count(FindingPersonalityCritique))
+	assert.Equal(t, 2, analysis.Count(FindingUnsupportedClaim))
+	assert.Contains(t, analysis.PromptVersions, "FeedbackAnalysisPrompt")
+
+	if assert.Len(t, provider.payloads, 1) {
+		contents := golly.Map(provider.payloads[0].Messages, func(m openai.Message) string { return m.Content })
+		assert.NotEqual(t, -1, indexContaining(contents, "Opportunities: Can be bossy and is always late."))
+	}
+}
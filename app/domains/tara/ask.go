@@ -0,0 +1,170 @@
+package tara
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/golly-go/golly"
+	"github.com/google/uuid"
+	"github.com/mitchrodrigues/talent-review-backend/app/domains/tara/prompt"
+	"github.com/mitchrodrigues/talent-review-backend/app/utils/openai"
+)
+
+const (
+	// NoSourcesAnswer is returned without calling the LLM when there is
+	// no feedback to answer from
+	NoSourcesAnswer = "I could not find any feedback about this employee that answers the question."
+)
+
+var (
+	askTaraDefaults = PromptDefaults{
+		Rules: []string{
+			"Answer only from the sources provided, do not use outside knowledge or assumptions.",
+			"List the id of every source the answer is based on in citations.",
+			"If the sources do not answer the question say so plainly and leave citations empty.",
+			"Refer to reviewers generally (for example \"a peer noted\"), never guess who wrote a piece of feedback.",
+			"Use simple, concise, and professional wording.",
+		},
+		Scenario: []string{
+			"You are helping a manager prepare a performance review by answering questions about the feedback an employee has received.",
+			"Sources are feedback excerpts and feedback summaries about the employee, each labelled with its id and the date it was written.",
+		},
+	}
+)
+
+// Source is a piece of feedback tara may answer from, the ID is the
+// feedback it came from and what citations refer to
+type Source struct {
+	ID      uuid.UUID
+	Kind    string
+	Section string
+	Date    time.Time
+	Content string
+}
+
+func (source Source) String() string {
+	label := source.Kind
+	if source.Section != "" {
+		label += " " + source.Section
+	}
+
+	return fmt.Sprintf("[%s] (%s, %s) %s", source.ID, label, source.Date.Format(time.DateOnly), source.Content)
+}
+
+// Turn is a previous question and answer of a conversation
+type Turn struct {
+	Question string
+	Answer   string
+}
+
+type AskTaraInput struct {
+	Employee string
+	Question string
+	Sources  []Source
+}
+
+type AskTaraPrompt struct {
+	openai.CompletionPromptBase `json:"-"`
+	AskTaraInput                `json:"-"`
+	Versioned                   `json:"-"`
+
+	Answer    string   `json:"answer" ai:"string answer to the question"`
+	Citations []string `json:"citations" ai:"string ids of the sources the answer is based on"`
+}
+
+func (prompt AskTaraPrompt) Context(gctx golly.Context) openai.AIContexts {
+	sources := golly.Map(prompt.Sources, func(source Source) string { return source.String() })
+
+	return openai.AIContexts{
+		openai.NewDefaultContentRole(openai.RoleUser,
+			fmt.Sprintf("Employee: %s\nSources:\n%s", prompt.Employee, strings.Join(sources, "\n")),
+		),
+	}
+}
+
+func (prompt AskTaraPrompt) Rules(gctx golly.Context) []string {
+	return prompt.Version.RulesOr(askTaraDefaults.Rules)
+}
+
+// Scenario ends with the question so it follows the sources and the
+// previous turns of the conversation
+func (prompt AskTaraPrompt) Scenario(gctx golly.Context) []string {
+	return append(prompt.Version.ScenarioOr(askTaraDefaults.Scenario),
+		fmt.Sprintf("Question: %s", prompt.Question))
+}
+
+// PromptToContexts replays a previous turn, the sources it was answered
+// from are not repeated
+func (prompt AskTaraPrompt) PromptToContexts(gctx golly.Context) openai.AIContexts {
+	return openai.AIContexts{
+		openai.NewDefaultContentRole(openai.RoleUser, prompt.Question),
+		openai.NewDefaultContentRole(openai.RoleAI, prompt.Answer),
+	}
+}
+
+func NewAskTaraPrompt(input AskTaraInput, history ...Turn) *AskTaraPrompt {
+	p := &AskTaraPrompt{AskTaraInput: input}
+
+	for _, turn := range history {
+		p.AddPreviousPrompts(&AskTaraPrompt{
+			AskTaraInput: AskTaraInput{Question: turn.Question},
+			Answer:       turn.Answer,
+		})
+	}
+
+	return p
+}
+
+// Answer is tara's reply to a question, citations are limited to the
+// sources it was given
+type Answer struct {
+	Answer         string      `json:"answer"`
+	Citations      []uuid.UUID `json:"citations"`
+	PromptVersions prompt.Refs `json:"promptVersions"`
+}
+
+// AnswerQuestion answers a question about an employee from the sources,
+// the history is the previous turns of the conversation
+func AnswerQuestion(gctx golly.Context, opts GenerateOptions, input AskTaraInput, history ...Turn) (Answer, error) {
+	result := Answer{Citations: []uuid.UUID{}, PromptVersions: prompt.Refs{}}
+
+	if len(input.Sources) == 0 {
+		result.Answer = NoSourcesAnswer
+		return result, nil
+	}
+
+	askPrompt := NewAskTaraPrompt(input, history...)
+
+	if err := GenerateWithOptions(gctx, opts, askPrompt); err != nil {
+		return result, err
+	}
+
+	result.Answer = askPrompt.Answer
+	result.Citations = validCitations(askPrompt.Citations, input.Sources)
+	result.PromptVersions[askPrompt.Version.Name] = askPrompt.Version.Ref
+
+	return result, nil
+}
+
+// validCitations drops citations that are not one of the sources, the
+// model may invent or mangle ids
+func validCitations(citations []string, sources []Source) []uuid.UUID {
+	known := map[uuid.UUID]bool{}
+	for _, source := range sources {
+		known[source.ID] = true
+	}
+
+	ret := []uuid.UUID{}
+	for _, citation := range citations {
+		id, err := uuid.Parse(strings.Trim(strings.TrimSpace(citation), "[]"))
+		if err != nil || !known[id] {
+			continue
+		}
+
+		known[id] = false
+		ret = append(ret, id)
+	}
+
+	return ret
+}
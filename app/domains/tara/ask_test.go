@@ -0,0 +1,112 @@
+package tara
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golly-go/golly"
+	"github.com/google/uuid"
+	"github.com/mitchrodrigues/talent-review-backend/app/utils/openai"
+	"github.com/stretchr/testify/assert"
+)
+
+// capturingProvider keeps the last completion payload it was sent
+type capturingProvider struct {
+	openai.FixtureProvider
+
+	payloads []openai.CompletionPayload
+}
+
+func (cp *capturingProvider) Completions(gctx golly.Context, payload openai.CompletionPayload) (openai.CompletionResponse, error) {
+	cp.payloads = append(cp.payloads, payload)
+	return cp.FixtureProvider.Completions(gctx, payload)
+}
+
+func TestValidCitations(t *testing.T) {
+	known, other := uuid.New(), uuid.New()
+	sources := []Source{{ID: known}, {ID: other}, {ID: known}}
+
+	tests := []struct {
+		name      string
+		citations []string
+		expected  []uuid.UUID
+	}{
+		{name: "none", expected: []uuid.UUID{}},
+		{name: "known", citations: []string{known.String()}, expected: []uuid.UUID{known}},
+		{name: "bracketed", citations: []string{"[" + other.String() + "]"}, expected: []uuid.UUID{other}},
+		{name: "unknown id", citations: []string{uuid.NewString()}, expected: []uuid.UUID{}},
+		{name: "not an id", citations: []string{"feedback 1"}, expected: []uuid.UUID{}},
+		{name: "duplicates", citations: []string{known.String(), known.String(), other.String()}, expected: []uuid.UUID{known, other}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, validCitations(tt.citations, sources))
+		})
+	}
+}
+
+func TestAnswerQuestion(t *testing.T) {
+	_, gctx := newPromptTestContext()
+
+	feedbackID := uuid.New()
+	sources := []Source{{
+		ID:      feedbackID,
+		Kind:    "feedback",
+		Section: "strengths",
+		Date:    time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC),
+		Content: "Keeps stakeholders informed with weekly notes.",
+	}}
+
+	t.Run("no sources", func(t *testing.T) {
+		provider := &capturingProvider{}
+
+		answer, err := AnswerQuestion(openai.UseProvider(gctx, provider), GenerateOptions{}, AskTaraInput{Question: "How is Jane doing?"})
+		assert.NoError(t, err)
+		assert.Equal(t, NoSourcesAnswer, answer.Answer)
+		assert.Empty(t, answer.Citations)
+		assert.Empty(t, provider.payloads)
+	})
+
+	t.Run("answers with valid citations and history", func(t *testing.T) {
+		provider := &capturingProvider{FixtureProvider: openai.FixtureProvider{
+			Default: fmt.Sprintf(`{"answer":"Peers say she keeps stakeholders informed.","citations":["%s","%s"]}`, feedbackID, uuid.New()),
+		}}
+
+		answer, err := AnswerQuestion(openai.UseProvider(gctx, provider), GenerateOptions{}, AskTaraInput{
+			Employee: "Jane",
+			Question: "What about stakeholder communication?",
+			Sources:  sources,
+		}, Turn{Question: "How is Jane doing?", Answer: "Well overall."})
+
+		assert.NoError(t, err)
+		assert.Equal(t, "Peers say she keeps stakeholders informed.", answer.Answer)
+		assert.Equal(t, []uuid.UUID{feedbackID}, answer.Citations)
+		assert.Contains(t, answer.PromptVersions, "AskTaraPrompt")
+
+		if assert.Len(t, provider.payloads, 1) {
+			messages := provider.payloads[0].Messages
+			contents := golly.Map(messages, func(m openai.Message) string { return m.Content })
+
+			sourcesAt := indexContaining(contents, "["+feedbackID.String()+"] (feedback strengths, 2024-03-01)")
+			historyAt := indexContaining(contents, "How is Jane doing?")
+			answerAt := indexContaining(contents, "Well overall.")
+			questionAt := indexContaining(contents, "Question: What about stakeholder communication?")
+
+			assert.True(t, sourcesAt >= 0 && sourcesAt < historyAt, contents)
+			assert.True(t, historyAt < answerAt && answerAt < questionAt, contents)
+			assert.Equal(t, openai.RoleAI, messages[answerAt].Role)
+		}
+	})
+}
+
+func indexContaining(contents []string, str string) int {
+	for pos, content := range contents {
+		if strings.Contains(content, str) {
+			return pos
+		}
+	}
+	return -1
+}
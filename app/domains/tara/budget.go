@@ -0,0 +1,78 @@
+package tara
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/golly-go/golly"
+	"github.com/google/uuid"
+	"github.com/mitchrodrigues/talent-review-backend/app/domains/accounts"
+	"github.com/mitchrodrigues/talent-review-backend/app/domains/accounts/organizations"
+	"github.com/mitchrodrigues/talent-review-backend/app/utils/identity"
+	"github.com/mitchrodrigues/talent-review-backend/app/utils/openai"
+)
+
+var (
+	ErrBudgetExceeded = fmt.Errorf("the organization has used its monthly AI token budget")
+)
+
+type BudgetStatus struct {
+	Budget int64
+	Used   int64
+	Mode   organizations.BudgetMode
+}
+
+func (b BudgetStatus) Exceeded() bool { return b.Budget > 0 && b.Used >= b.Budget }
+func (b BudgetStatus) Blocked() bool  { return b.Exceeded() && b.Mode == organizations.BudgetBlock }
+func (b BudgetStatus) Degraded() bool { return b.Exceeded() && b.Mode == organizations.BudgetDegrade }
+
+func (b BudgetStatus) Remaining() int64 {
+	if b.Budget <= 0 {
+		return -1
+	}
+	return max(b.Budget-b.Used, 0)
+}
+
+// Budget returns the token budget of the organization on the context
+// for the current month
+func Budget(gctx golly.Context) BudgetStatus {
+	return BudgetForOrganization(gctx, identity.FromContext(gctx).OrganizationID, time.Now())
+}
+
+func BudgetForOrganization(gctx golly.Context, organizationID uuid.UUID, month time.Time) BudgetStatus {
+	if organizationID == uuid.Nil {
+		return BudgetStatus{}
+	}
+
+	settings := accounts.OrganizationSettings(gctx, organizationID)
+
+	status := BudgetStatus{Budget: settings.MonthlyTokenBudget, Mode: settings.Mode()}
+	if status.Budget <= 0 {
+		return status
+	}
+
+	usage, err := FindMonthlyUsage(gctx, organizationID, month)
+	if err != nil {
+		gctx.Logger().Warnf("cannot load llm usage for organization %s: %v", organizationID, err)
+	}
+
+	status.Used = usage.TotalTokens
+
+	return status
+}
+
+// Degraded is true when optional tara features should be skipped for
+// the organization on the context
+func Degraded(gctx golly.Context) bool {
+	return Budget(gctx).Degraded()
+}
+
+// economyProvider forces every completion onto the cheapest model
+type economyProvider struct {
+	openai.Provider
+}
+
+func (ep economyProvider) Completions(gctx golly.Context, payload openai.CompletionPayload) (openai.CompletionResponse, error) {
+	payload.Model = openai.EconomyModel
+	return ep.Provider.Completions(gctx, payload)
+}
@@ -0,0 +1,118 @@
+package tara
+
+import (
+	"time"
+
+	"github.com/golly-go/golly"
+	"github.com/golly-go/plugins/orm"
+	"github.com/google/uuid"
+	"github.com/mitchrodrigues/talent-review-backend/app/utils/crypto"
+	"github.com/mitchrodrigues/talent-review-backend/app/utils/identity"
+	"github.com/mitchrodrigues/talent-review-backend/app/utils/openai"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+const (
+	CacheStorePostgres = "postgres"
+	CacheStoreMemory   = "memory"
+)
+
+// CacheEntry is a cached LLM response, responses quote the feedback in
+// the prompt so the value is encrypted with the data key of the
+// organization it was generated for
+type CacheEntry struct {
+	CacheKey       string `gorm:"primaryKey"`
+	OrganizationID uuid.UUID
+	Value          string
+
+	ExpiresAt *time.Time
+	CreatedAt time.Time
+}
+
+func (CacheEntry) TableName() string { return "llm_cache_entries" }
+
+func (entry *CacheEntry) BeforeSave(tx *gorm.DB) error {
+	return crypto.EncryptFields(tx, entry.OrganizationID, &entry.Value)
+}
+
+func (entry *CacheEntry) AfterFind(tx *gorm.DB) error {
+	return crypto.DecryptFields(tx, entry.OrganizationID, &entry.Value)
+}
+
+// DBCache is the openai.CacheStore backed by the llm_cache_entries table
+type DBCache struct{}
+
+// Get only finds responses stored for the organization on the context
+func (DBCache) Get(gctx golly.Context, key string) (string, bool, error) {
+	var entries []CacheEntry
+
+	err := orm.NewDB(gctx).
+		Model(&CacheEntry{}).
+		Where("cache_key = ? AND organization_id = ?", key, identity.FromContext(gctx).OrganizationID).
+		Where("expires_at IS NULL OR expires_at > ?", time.Now()).
+		Limit(1).
+		Find(&entries).
+		Error
+
+	if err != nil || len(entries) == 0 {
+		return "", false, err
+	}
+
+	return entries[0].Value, true, nil
+}
+
+// Set stores the response under the organization on the context, it is
+// not stored without one as it could not be encrypted
+func (DBCache) Set(gctx golly.Context, key string, value string, ttl time.Duration) error {
+	organizationID := identity.FromContext(gctx).OrganizationID
+	if organizationID == uuid.Nil && crypto.Provider() != nil {
+		return nil
+	}
+
+	entry := CacheEntry{CacheKey: key, OrganizationID: organizationID, Value: value, CreatedAt: time.Now()}
+
+	if ttl > 0 {
+		expiresAt := entry.CreatedAt.Add(ttl)
+		entry.ExpiresAt = &expiresAt
+	}
+
+	// keys include the organization so an entry never changes hands
+	return orm.NewDB(gctx).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "cache_key"}},
+		DoUpdates: clause.AssignmentColumns([]string{"value", "expires_at", "created_at"}),
+	}).Create(&entry).Error
+}
+
+// PurgeExpiredCache deletes the expired entries of the DB cache
+func PurgeExpiredCache(gctx golly.Context) (int64, error) {
+	result := orm.NewDB(gctx).
+		Where("expires_at IS NOT NULL AND expires_at <= ?", time.Now()).
+		Delete(&CacheEntry{})
+
+	return result.RowsAffected, result.Error
+}
+
+// PurgeCacheBefore deletes the cached responses of the organization
+// created before the cutoff, they may quote feedback purged by retention
+func PurgeCacheBefore(gctx golly.Context, organizationID uuid.UUID, before time.Time) (int64, error) {
+	result := orm.NewDB(gctx).
+		Where("organization_id = ? AND created_at < ?", organizationID, before).
+		Delete(&CacheEntry{})
+
+	return result.RowsAffected, result.Error
+}
+
+// NewCacheStore returns the store named in config (llm.cache.store),
+// nil disables caching
+func NewCacheStore(name string) openai.CacheStore {
+	switch name {
+	case CacheStorePostgres:
+		return DBCache{}
+	case CacheStoreMemory:
+		return openai.NewMemoryCache()
+	}
+	return nil
+}
+
+var _ openai.CacheStore = DBCache{}
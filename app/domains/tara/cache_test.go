@@ -0,0 +1,88 @@
+package tara
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/golly-go/golly"
+	"github.com/golly-go/plugins/orm"
+	"github.com/google/uuid"
+	"github.com/mitchrodrigues/talent-review-backend/app/utils/crypto"
+	"github.com/mitchrodrigues/talent-review-backend/app/utils/identity"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDBCache(t *testing.T) {
+	gctx := orm.CreateTestContext(golly.NewContext(context.TODO()), CacheEntry{})
+
+	cache := DBCache{}
+
+	_, found, err := cache.Get(gctx, "missing")
+	assert.NoError(t, err)
+	assert.False(t, found)
+
+	assert.NoError(t, cache.Set(gctx, "key", "first", 0))
+	assert.NoError(t, cache.Set(gctx, "key", "second", time.Hour))
+
+	value, found, err := cache.Get(gctx, "key")
+	assert.NoError(t, err)
+	assert.True(t, found)
+	assert.Equal(t, "second", value)
+
+	assert.NoError(t, cache.Set(gctx, "expired", "value", 0))
+	orm.DB(gctx).Model(&CacheEntry{}).Where("cache_key = ?", "expired").Update("expires_at", time.Now().Add(-time.Minute))
+
+	_, found, _ = cache.Get(gctx, "expired")
+	assert.False(t, found)
+
+	purged, err := PurgeExpiredCache(gctx)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), purged)
+
+	_, found, _ = cache.Get(gctx, "key")
+	assert.True(t, found)
+}
+
+func TestDBCacheEncryption(t *testing.T) {
+	defer crypto.UseProvider(crypto.NewTestKeyProvider())()
+
+	gctx := orm.CreateTestContext(golly.NewContext(context.TODO()), CacheEntry{}, crypto.DataKey{})
+
+	cache := DBCache{}
+
+	t.Run("responses are not cached without an organization", func(t *testing.T) {
+		assert.NoError(t, cache.Set(gctx, "anonymous", "Runs great planning meetings.", 0))
+
+		_, found, _ := cache.Get(gctx, "anonymous")
+		assert.False(t, found)
+	})
+
+	t.Run("responses are encrypted at rest", func(t *testing.T) {
+		gctx := identity.ToContext(gctx, identity.Identity{UID: uuid.New(), OrganizationID: uuid.New()})
+
+		assert.NoError(t, cache.Set(gctx, "key", "Runs great planning meetings.", 0))
+
+		var values []string
+		orm.NewDB(gctx).Table("llm_cache_entries").Where("cache_key = ?", "key").Pluck("value", &values)
+		assert.True(t, crypto.IsEncrypted(values[0]))
+
+		value, found, err := cache.Get(gctx, "key")
+		assert.NoError(t, err)
+		assert.True(t, found)
+		assert.Equal(t, "Runs great planning meetings.", value)
+	})
+
+	t.Run("responses are only found by their organization", func(t *testing.T) {
+		owner := identity.Identity{UID: uuid.New(), OrganizationID: uuid.New()}
+
+		assert.NoError(t, cache.Set(identity.ToContext(gctx, owner), "shared", "Runs great planning meetings.", 0))
+
+		_, found, err := cache.Get(identity.ToContext(gctx, identity.Identity{UID: uuid.New(), OrganizationID: uuid.New()}), "shared")
+		assert.NoError(t, err)
+		assert.False(t, found)
+
+		_, found, _ = cache.Get(identity.ToContext(gctx, owner), "shared")
+		assert.True(t, found)
+	})
+}
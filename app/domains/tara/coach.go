@@ -0,0 +1,128 @@
+package tara
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/golly-go/golly"
+	"github.com/mitchrodrigues/talent-review-backend/app/domains/tara/prompt"
+	"github.com/mitchrodrigues/talent-review-backend/app/utils/openai"
+)
+
+const (
+	SuggestionMissingSpecifics   = "missing_specifics"
+	SuggestionBiasedLanguage     = "biased_language"
+	SuggestionPersonalityFraming = "personality_framing"
+)
+
+const (
+	maxWritingSuggestions = 5
+
+	// drafts shorter than this are not worth a call
+	minWritingCoachDraft = 3
+)
+
+var (
+	SuggestionKinds = []string{
+		SuggestionMissingSpecifics,
+		SuggestionBiasedLanguage,
+		SuggestionPersonalityFraming,
+	}
+
+	writingCoachDefaults = PromptDefaults{
+		Rules: []string{
+			"Only suggest changes for these kinds of issues: missing_specifics, biased_language, personality_framing.",
+			"missing_specifics: the draft is vague (for example \"great teammate\") and needs a concrete example, situation or impact.",
+			"biased_language: the draft uses gendered, age related, exclusionary or otherwise non-inclusive wording, or comments on traits unrelated to the work.",
+			"personality_framing: the draft describes who the person is (\"she is abrasive\") instead of what they did (\"she interrupted others in planning\").",
+			"Quote the exact words of the draft the suggestion is about in excerpt.",
+			"Suggestions are short questions or rewrites addressed to the reviewer, never write the feedback for them.",
+			"Return no suggestions when the draft is specific, behavioral and inclusive.",
+		},
+		Scenario: []string{
+			"You are coaching a reviewer who is writing feedback about a colleague, before they submit it.",
+			"Help them make the feedback specific, behavior focused and free of bias.",
+		},
+	}
+)
+
+type WritingCoachInput struct {
+	// Section is the question of the feedback form the draft answers,
+	// strengths or opportunities
+	Section string
+	Draft   string
+}
+
+type WritingSuggestion struct {
+	Kind       string `json:"kind" ai:"string one of missing_specifics, biased_language, personality_framing"`
+	Excerpt    string `json:"excerpt" ai:"string exact words of the draft the suggestion is about"`
+	Suggestion string `json:"suggestion" ai:"string short suggestion for the reviewer"`
+}
+
+type WritingCoachPrompt struct {
+	openai.CompletionPromptBase `json:"-"`
+	WritingCoachInput           `json:"-"`
+	Versioned                   `json:"-"`
+
+	Suggestions []WritingSuggestion `json:"suggestions" ai:"suggestions for the reviewer" max:"5"`
+}
+
+func (prompt WritingCoachPrompt) Context(gctx golly.Context) openai.AIContexts {
+	return openai.AIContexts{
+		openai.NewDefaultContentRole(openai.RoleUser,
+			fmt.Sprintf("Question: %s\nDraft: %s", prompt.Section, prompt.Draft),
+		),
+	}
+}
+
+func (prompt WritingCoachPrompt) Rules(gctx golly.Context) []string {
+	return prompt.Version.RulesOr(writingCoachDefaults.Rules)
+}
+
+func (prompt WritingCoachPrompt) Scenario(gctx golly.Context) []string {
+	return prompt.Version.ScenarioOr(writingCoachDefaults.Scenario)
+}
+
+func NewWritingCoachPrompt(input WritingCoachInput) *WritingCoachPrompt {
+	return &WritingCoachPrompt{WritingCoachInput: input}
+}
+
+// Coaching is tara's feedback on a draft
+type Coaching struct {
+	Suggestions    []WritingSuggestion `json:"suggestions"`
+	PromptVersions prompt.Refs         `json:"promptVersions"`
+}
+
+// CoachFeedback suggests improvements to a draft answer of the feedback
+// form, coaching is optional so nothing is generated for empty drafts or
+// when the organization has run over its budget
+func CoachFeedback(gctx golly.Context, opts GenerateOptions, input WritingCoachInput) (Coaching, error) {
+	result := Coaching{Suggestions: []WritingSuggestion{}, PromptVersions: prompt.Refs{}}
+
+	input.Draft = strings.TrimSpace(input.Draft)
+	if len(input.Draft) < minWritingCoachDraft || Degraded(gctx) {
+		return result, nil
+	}
+
+	coachPrompt := NewWritingCoachPrompt(input)
+
+	if err := GenerateWithOptions(gctx, opts, coachPrompt); err != nil {
+		return result, err
+	}
+
+	for _, suggestion := range coachPrompt.Suggestions {
+		suggestion.Kind = strings.ToLower(strings.TrimSpace(suggestion.Kind))
+
+		if !slices.Contains(SuggestionKinds, suggestion.Kind) || strings.TrimSpace(suggestion.Suggestion) == "" {
+			continue
+		}
+
+		result.Suggestions = append(result.Suggestions, suggestion)
+	}
+
+	result.Suggestions = result.Suggestions[:min(len(result.Suggestions), maxWritingSuggestions)]
+	result.PromptVersions[coachPrompt.Version.Name] = coachPrompt.Version.Ref
+
+	return result, nil
+}
@@ -0,0 +1,59 @@
+package tara
+
+import (
+	"testing"
+
+	"github.com/mitchrodrigues/talent-review-backend/app/utils/openai"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCoachFeedback(t *testing.T) {
+	_, gctx := newPromptTestContext()
+
+	tests := []struct {
+		name     string
+		response string
+		input    WritingCoachInput
+		expected []WritingSuggestion
+		calls    int
+	}{
+		{
+			name:     "empty draft",
+			input:    WritingCoachInput{Section: "strengths", Draft: "  "},
+			expected: []WritingSuggestion{},
+		},
+		{
+			name: "keeps known kinds",
+			response: `{"suggestions":[
+				{"kind":"missing_specifics","excerpt":"great teammate","suggestion":"What did they do that made them a great teammate?"},
+				{"kind":"Personality_Framing","excerpt":"is abrasive","suggestion":"Describe the behavior instead."},
+				{"kind":"grammar","excerpt":"teh","suggestion":"Typo."},
+				{"kind":"biased_language","excerpt":"","suggestion":" "}
+			]}`,
+			input: WritingCoachInput{Section: "strengths", Draft: "Great teammate but is abrasive, teh end."},
+			expected: []WritingSuggestion{
+				{Kind: SuggestionMissingSpecifics, Excerpt: "great teammate", Suggestion: "What did they do that made them a great teammate?"},
+				{Kind: SuggestionPersonalityFraming, Excerpt: "is abrasive", Suggestion: "Describe the behavior instead."},
+			},
+			calls: 1,
+		},
+		{
+			name:     "nothing to suggest",
+			response: `{"suggestions":[]}`,
+			input:    WritingCoachInput{Section: "opportunities", Draft: "Split the release checklist so others can run it."},
+			expected: []WritingSuggestion{},
+			calls:    1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			provider := &capturingProvider{FixtureProvider: openai.FixtureProvider{Default: tt.response}}
+
+			coaching, err := CoachFeedback(openai.UseProvider(gctx, provider), GenerateOptions{}, tt.input)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, coaching.Suggestions)
+			assert.Len(t, provider.payloads, tt.calls)
+		})
+	}
+}
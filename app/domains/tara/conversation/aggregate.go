@@ -0,0 +1,107 @@
+package conversation
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/golly-go/golly"
+	"github.com/golly-go/plugins/eventsource"
+	"github.com/golly-go/plugins/orm"
+	"github.com/google/uuid"
+	"github.com/mitchrodrigues/talent-review-backend/app/domains/tara/prompt"
+	"github.com/mitchrodrigues/talent-review-backend/app/utils/esbackend"
+)
+
+const (
+	RoleUser      = "user"
+	RoleAssistant = "assistant"
+)
+
+// Aggregate is an Ask-Tara thread a user holds about one employee, every
+// question and answer is kept so follow up questions have the history
+type Aggregate struct {
+	eventsource.AggregateBase
+
+	orm.ModelUUID
+
+	OrganizationID uuid.UUID
+	UserID         uuid.UUID
+	EmployeeID     uuid.UUID
+
+	Title    string
+	Messages Messages `gorm:"type:jsonb"`
+}
+
+func (*Aggregate) Topic() string                             { return "events.tara_conversations" }
+func (*Aggregate) Repo(golly.Context) eventsource.Repository { return esbackend.PostgresRepository{} }
+func (*Aggregate) TableName() string                         { return "tara_conversations" }
+
+func (conversation *Aggregate) GetID() string   { return conversation.ID.String() }
+func (conversation *Aggregate) SetID(id string) { conversation.ID, _ = uuid.Parse(id) }
+
+func (conversation *Aggregate) Apply(ctx golly.Context, evt eventsource.Event) {
+	switch event := evt.Data.(type) {
+	case Started:
+		conversation.ID = event.ID
+		conversation.OrganizationID = event.OrganizationID
+		conversation.UserID = event.UserID
+		conversation.EmployeeID = event.EmployeeID
+		conversation.Title = event.Title
+
+		conversation.CreatedAt = evt.CreatedAt
+
+	case Asked:
+		conversation.Messages = append(conversation.Messages, Message{
+			Role:      RoleUser,
+			Content:   event.Question,
+			CreatedAt: evt.CreatedAt,
+		})
+
+	case Answered:
+		conversation.Messages = append(conversation.Messages, Message{
+			Role:           RoleAssistant,
+			Content:        event.Answer,
+			Citations:      event.Citations,
+			PromptVersions: event.PromptVersions,
+			CreatedAt:      evt.CreatedAt,
+		})
+	}
+	conversation.UpdatedAt = evt.CreatedAt
+}
+
+type Message struct {
+	Role    string `json:"role"`
+	Content string `json:"content"`
+
+	// Citations are the feedback IDs the answer is grounded in
+	Citations      []uuid.UUID `json:"citations,omitempty"`
+	PromptVersions prompt.Refs `json:"promptVersions,omitempty"`
+
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+// Messages are stored as jsonb
+type Messages []Message
+
+func (m Messages) Value() (driver.Value, error) {
+	if m == nil {
+		return []byte("[]"), nil
+	}
+	return json.Marshal(m)
+}
+
+func (m *Messages) Scan(value interface{}) error {
+	switch v := value.(type) {
+	case nil:
+		return nil
+	case []byte:
+		return json.Unmarshal(v, m)
+	case string:
+		return json.Unmarshal([]byte(v), m)
+	}
+	return fmt.Errorf("cannot scan %T into messages", value)
+}
+
+var _ eventsource.Aggregate = &Aggregate{}
@@ -0,0 +1,101 @@
+package conversation
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/golly-go/golly"
+	"github.com/golly-go/golly/errors"
+	"github.com/golly-go/plugins/eventsource"
+	"github.com/google/uuid"
+	"github.com/mitchrodrigues/talent-review-backend/app/domains/tara/prompt"
+)
+
+const (
+	maxTitleLength = 80
+)
+
+var (
+	ErrorEmployeeRequired = fmt.Errorf("conversation employee is required")
+	ErrorUserRequired     = fmt.Errorf("conversation user is required")
+	ErrorQuestionRequired = fmt.Errorf("question is required")
+	ErrorNotStarted       = fmt.Errorf("conversation has not been started")
+)
+
+type Start struct {
+	OrganizationID uuid.UUID
+	UserID         uuid.UUID
+	EmployeeID     uuid.UUID
+
+	// Question is the first question, it titles the conversation
+	Question string
+}
+
+func (cmd Start) Validate(ctx golly.Context, aggregate eventsource.Aggregate) error {
+	if cmd.UserID == uuid.Nil {
+		return errors.WrapUnprocessable(ErrorUserRequired)
+	}
+
+	if cmd.EmployeeID == uuid.Nil {
+		return errors.WrapUnprocessable(ErrorEmployeeRequired)
+	}
+
+	return nil
+}
+
+func (cmd Start) Perform(ctx golly.Context, aggregate eventsource.Aggregate) error {
+	id, _ := uuid.NewV7()
+
+	eventsource.Apply(ctx, aggregate, Started{
+		ID:             id,
+		OrganizationID: cmd.OrganizationID,
+		UserID:         cmd.UserID,
+		EmployeeID:     cmd.EmployeeID,
+		Title:          title(cmd.Question),
+	})
+
+	return nil
+}
+
+// AddTurn records a question and the answer tara gave to it
+type AddTurn struct {
+	Question string
+	Answer   string
+
+	Citations      []uuid.UUID
+	PromptVersions prompt.Refs
+}
+
+func (cmd AddTurn) Validate(ctx golly.Context, aggregate eventsource.Aggregate) error {
+	if aggregate.(*Aggregate).ID == uuid.Nil {
+		return errors.WrapUnprocessable(ErrorNotStarted)
+	}
+
+	if strings.TrimSpace(cmd.Question) == "" {
+		return errors.WrapUnprocessable(ErrorQuestionRequired)
+	}
+
+	return nil
+}
+
+func (cmd AddTurn) Perform(ctx golly.Context, aggregate eventsource.Aggregate) error {
+	eventsource.Apply(ctx, aggregate, Asked{Question: strings.TrimSpace(cmd.Question)})
+
+	eventsource.Apply(ctx, aggregate, Answered{
+		Answer:         cmd.Answer,
+		Citations:      cmd.Citations,
+		PromptVersions: cmd.PromptVersions,
+	})
+
+	return nil
+}
+
+func title(question string) string {
+	question = strings.Join(strings.Fields(question), " ")
+
+	if runes := []rune(question); len(runes) > maxTitleLength {
+		return strings.TrimSpace(string(runes[:maxTitleLength-3])) + "..."
+	}
+
+	return question
+}
@@ -0,0 +1,99 @@
+package conversation
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/golly-go/golly"
+	"github.com/google/uuid"
+	"github.com/mitchrodrigues/talent-review-backend/app/domains/tara/prompt"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStartValidate(t *testing.T) {
+	tests := []struct {
+		name      string
+		cmd       Start
+		expectErr bool
+	}{
+		{name: "valid", cmd: Start{UserID: uuid.New(), EmployeeID: uuid.New(), Question: "How is Jane doing?"}},
+		{name: "missing user", cmd: Start{EmployeeID: uuid.New()}, expectErr: true},
+		{name: "missing employee", cmd: Start{UserID: uuid.New()}, expectErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.cmd.Validate(golly.Context{}, &Aggregate{})
+			if tt.expectErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestAddTurnValidate(t *testing.T) {
+	started := &Aggregate{}
+	started.ID = uuid.New()
+
+	tests := []struct {
+		name      string
+		aggregate *Aggregate
+		cmd       AddTurn
+		expectErr bool
+	}{
+		{name: "valid", aggregate: started, cmd: AddTurn{Question: "How is Jane doing?", Answer: "Well"}},
+		{name: "not started", aggregate: &Aggregate{}, cmd: AddTurn{Question: "How is Jane doing?"}, expectErr: true},
+		{name: "empty question", aggregate: started, cmd: AddTurn{Question: "  "}, expectErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.cmd.Validate(golly.Context{}, tt.aggregate)
+			if tt.expectErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestPerform(t *testing.T) {
+	gctx := golly.NewContext(context.TODO())
+
+	userID, employeeID, feedbackID := uuid.New(), uuid.New(), uuid.New()
+
+	agg := &Aggregate{}
+
+	assert.NoError(t, Start{UserID: userID, EmployeeID: employeeID, Question: " What do   peers say? "}.Perform(gctx, agg))
+	assert.NotEqual(t, uuid.Nil, agg.ID)
+	assert.Equal(t, "What do peers say?", agg.Title)
+	assert.Equal(t, employeeID, agg.EmployeeID)
+
+	refs := prompt.Refs{"AskTaraPrompt": {}}
+
+	assert.NoError(t, AddTurn{
+		Question:       " What do peers say? ",
+		Answer:         "They like working with her.",
+		Citations:      []uuid.UUID{feedbackID},
+		PromptVersions: refs,
+	}.Perform(gctx, agg))
+
+	if assert.Len(t, agg.Messages, 2) {
+		assert.Equal(t, Message{Role: RoleUser, Content: "What do peers say?", CreatedAt: agg.Messages[0].CreatedAt}, agg.Messages[0])
+		assert.Equal(t, RoleAssistant, agg.Messages[1].Role)
+		assert.Equal(t, []uuid.UUID{feedbackID}, agg.Messages[1].Citations)
+		assert.Equal(t, refs, agg.Messages[1].PromptVersions)
+	}
+}
+
+func TestTitle(t *testing.T) {
+	assert.Equal(t, "short question", title("short \n question"))
+
+	long := title(strings.Repeat("word ", 40))
+	assert.Len(t, []rune(long), maxTitleLength)
+	assert.True(t, strings.HasSuffix(long, "..."))
+}
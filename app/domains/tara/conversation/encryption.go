@@ -0,0 +1,39 @@
+package conversation
+
+import (
+	"github.com/mitchrodrigues/talent-review-backend/app/utils/crypto"
+	"gorm.io/gorm"
+)
+
+// Questions and answers quote feedback so the conversation is encrypted at
+// rest with the data key of the organization, the title is the first
+// question
+
+func (conversation *Aggregate) EncryptedColumns() map[string]*string {
+	return map[string]*string{"title": &conversation.Title}
+}
+
+// EncryptedText points at the content of the messages
+func (conversation *Aggregate) EncryptedText() []*string {
+	text := make([]*string, 0, len(conversation.Messages))
+	for i := range conversation.Messages {
+		text = append(text, &conversation.Messages[i].Content)
+	}
+	return text
+}
+
+func (conversation *Aggregate) EncryptedDocuments() map[string]interface{} {
+	return map[string]interface{}{"messages": conversation.Messages}
+}
+
+func (conversation *Aggregate) BeforeSave(tx *gorm.DB) error {
+	return crypto.EncryptFields(tx, conversation.OrganizationID, crypto.Fields(conversation.EncryptedColumns(), conversation.EncryptedText()...)...)
+}
+
+func (conversation *Aggregate) AfterSave(tx *gorm.DB) error {
+	return crypto.DecryptFields(tx, conversation.OrganizationID, crypto.Fields(conversation.EncryptedColumns(), conversation.EncryptedText()...)...)
+}
+
+func (conversation *Aggregate) AfterFind(tx *gorm.DB) error {
+	return crypto.DecryptFields(tx, conversation.OrganizationID, crypto.Fields(conversation.EncryptedColumns(), conversation.EncryptedText()...)...)
+}
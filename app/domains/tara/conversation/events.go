@@ -0,0 +1,27 @@
+package conversation
+
+import (
+	"github.com/google/uuid"
+	"github.com/mitchrodrigues/talent-review-backend/app/domains/tara/prompt"
+)
+
+type Started struct {
+	ID             uuid.UUID `json:"id"`
+	OrganizationID uuid.UUID `json:"organizationID"`
+	UserID         uuid.UUID `json:"userID"`
+	EmployeeID     uuid.UUID `json:"employeeID"`
+	Title          string    `json:"-"`
+}
+
+// The questions and answers quote feedback so they are kept out of the
+// event store
+
+type Asked struct {
+	Question string `json:"-"`
+}
+
+type Answered struct {
+	Answer         string      `json:"-"`
+	Citations      []uuid.UUID `json:"citations"`
+	PromptVersions prompt.Refs `json:"promptVersions"`
+}
@@ -0,0 +1,70 @@
+package tara
+
+import (
+	"github.com/golly-go/golly"
+	"github.com/golly-go/golly/errors"
+	"github.com/golly-go/plugins/orm"
+	"github.com/google/uuid"
+	"github.com/mitchrodrigues/talent-review-backend/app/domains/tara/conversation"
+	"github.com/mitchrodrigues/talent-review-backend/app/utils/identity"
+	"gorm.io/gorm"
+)
+
+// Conversation is a stored Ask-Tara thread
+type Conversation struct {
+	conversation.Aggregate
+}
+
+func (Conversation) TableName() string { return "tara_conversations" }
+
+// Turns pairs the questions with their answers so a conversation can be
+// replayed as history
+func (c Conversation) Turns() []Turn {
+	var turns []Turn
+
+	for _, message := range c.Messages {
+		switch message.Role {
+		case conversation.RoleUser:
+			turns = append(turns, Turn{Question: message.Content})
+		case conversation.RoleAssistant:
+			if len(turns) > 0 {
+				turns[len(turns)-1].Answer = message.Content
+			}
+		}
+	}
+
+	return turns
+}
+
+func conversationQuery(gctx golly.Context) *gorm.DB {
+	ident := identity.FromContext(gctx)
+
+	return orm.DB(gctx).
+		Model(&Conversation{}).
+		Where("organization_id = ? AND user_id = ?", ident.OrganizationID, ident.UID)
+}
+
+// FindConversation finds a conversation of the current user
+func FindConversation(gctx golly.Context, id uuid.UUID) (Conversation, error) {
+	var c Conversation
+
+	err := conversationQuery(gctx).
+		First(&c, "id = ?", id).
+		Error
+
+	return c, errors.WrapNotFound(err)
+}
+
+// FindConversations lists the conversations of the current user about
+// an employee, most recent first
+func FindConversations(gctx golly.Context, employeeID uuid.UUID) ([]Conversation, error) {
+	var conversations []Conversation
+
+	err := conversationQuery(gctx).
+		Where("employee_id = ?", employeeID).
+		Order("updated_at DESC").
+		Find(&conversations).
+		Error
+
+	return conversations, errors.WrapGeneric(err)
+}
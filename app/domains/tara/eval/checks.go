@@ -0,0 +1,146 @@
+package eval
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/mitchrodrigues/talent-review-backend/app/domains/tara"
+)
+
+var (
+	// DefaultBannedPhrases are filler and editorializing we never want in a summary
+	DefaultBannedPhrases = []string{
+		"as an ai",
+		"language model",
+		"in conclusion",
+		"overall, ",
+		"rockstar",
+		"it is worth noting",
+		"i think",
+		"i believe",
+	}
+
+	DefaultChecks = []Check{
+		LengthCheck{MinWords: 10, MaxWords: 200},
+		BannedPhrasesCheck{Phrases: DefaultBannedPhrases},
+		ActionItemsCheck{Min: 1, Max: 5},
+		EntityPreservationCheck{},
+	}
+)
+
+// Result of a single check, Score is between 0 and 1
+type Result struct {
+	Check  string  `json:"check"`
+	Passed bool    `json:"passed"`
+	Score  float64 `json:"score"`
+	Detail string  `json:"detail,omitempty"`
+}
+
+// Check is a deterministic scorer of a summary
+type Check interface {
+	Name() string
+	Run(Case, tara.Summary) Result
+}
+
+func passFail(name string, passed bool, detail string) Result {
+	result := Result{Check: name, Passed: passed, Detail: detail}
+	if passed {
+		result.Score = 1
+	}
+	return result
+}
+
+// LengthCheck bounds the word count of the summary
+type LengthCheck struct {
+	MinWords int
+	MaxWords int
+}
+
+func (LengthCheck) Name() string { return "length" }
+
+func (check LengthCheck) Run(c Case, summary tara.Summary) Result {
+	words := len(strings.Fields(summary.Summary))
+
+	passed := words >= check.MinWords && (check.MaxWords <= 0 || words <= check.MaxWords)
+
+	return passFail(check.Name(), passed, fmt.Sprintf("%d words", words))
+}
+
+// BannedPhrasesCheck fails when the summary or action items use any of
+// the phrases, matching is case insensitive
+type BannedPhrasesCheck struct {
+	Phrases []string
+}
+
+func (BannedPhrasesCheck) Name() string { return "banned_phrases" }
+
+func (check BannedPhrasesCheck) Run(c Case, summary tara.Summary) Result {
+	text := strings.ToLower(strings.Join(append([]string{summary.Summary}, summary.ActionItems...), "\n"))
+
+	found := []string{}
+	for _, phrase := range check.Phrases {
+		if strings.Contains(text, strings.ToLower(phrase)) {
+			found = append(found, phrase)
+		}
+	}
+
+	return passFail(check.Name(), len(found) == 0, strings.Join(found, ", "))
+}
+
+// ActionItemsCheck bounds the number of follow up items
+type ActionItemsCheck struct {
+	Min int
+	Max int
+}
+
+func (ActionItemsCheck) Name() string { return "action_items" }
+
+func (check ActionItemsCheck) Run(c Case, summary tara.Summary) Result {
+	count := len(summary.ActionItems)
+
+	passed := count >= check.Min && (check.Max <= 0 || count <= check.Max)
+
+	return passFail(check.Name(), passed, fmt.Sprintf("%d items", count))
+}
+
+// EntityPreservationCheck scores the share of the case entities the
+// summary kept, entities the feedback itself does not mention are ignored
+type EntityPreservationCheck struct{}
+
+func (EntityPreservationCheck) Name() string { return "entities" }
+
+func (check EntityPreservationCheck) Run(c Case, summary tara.Summary) Result {
+	source := strings.ToLower(c.Text())
+	text := strings.ToLower(summary.Summary)
+
+	var expected int
+	missing := []string{}
+
+	for _, entity := range c.Entities {
+		entity = strings.ToLower(strings.TrimSpace(entity))
+		if entity == "" || !strings.Contains(source, entity) {
+			continue
+		}
+
+		expected++
+		if !strings.Contains(text, entity) {
+			missing = append(missing, entity)
+		}
+	}
+
+	if expected == 0 {
+		return passFail(check.Name(), true, "")
+	}
+
+	result := Result{
+		Check:  check.Name(),
+		Passed: len(missing) == 0,
+		Score:  float64(expected-len(missing)) / float64(expected),
+	}
+
+	if len(missing) > 0 {
+		result.Detail = "missing " + strings.Join(missing, ", ")
+	}
+
+	return result
+}
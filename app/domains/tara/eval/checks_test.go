@@ -0,0 +1,96 @@
+package eval
+
+import (
+	"testing"
+
+	"github.com/mitchrodrigues/talent-review-backend/app/domains/tara"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestChecks(t *testing.T) {
+	c := Case{
+		Strengths:     "Alex led the Ledger migration to Postgres.",
+		Opportunities: "Alex could delegate more.",
+		Entities:      []string{"Alex", "Ledger", "Postgres", "Atlas"},
+	}
+
+	tests := []struct {
+		name     string
+		check    Check
+		summary  tara.Summary
+		expected Result
+	}{
+		{
+			name:     "length within bounds",
+			check:    LengthCheck{MinWords: 2, MaxWords: 5},
+			summary:  tara.Summary{Summary: "Alex led the migration."},
+			expected: Result{Check: "length", Passed: true, Score: 1, Detail: "4 words"},
+		},
+		{
+			name:     "too long",
+			check:    LengthCheck{MinWords: 2, MaxWords: 3},
+			summary:  tara.Summary{Summary: "Alex led the migration."},
+			expected: Result{Check: "length", Detail: "4 words"},
+		},
+		{
+			name:     "banned phrase in an action item",
+			check:    BannedPhrasesCheck{Phrases: DefaultBannedPhrases},
+			summary:  tara.Summary{Summary: "Alex led the migration.", ActionItems: []string{"As an AI I suggest a chat"}},
+			expected: Result{Check: "banned_phrases", Detail: "as an ai"},
+		},
+		{
+			name:     "no banned phrases",
+			check:    BannedPhrasesCheck{Phrases: DefaultBannedPhrases},
+			summary:  tara.Summary{Summary: "Alex led the migration."},
+			expected: Result{Check: "banned_phrases", Passed: true, Score: 1},
+		},
+		{
+			name:     "too many action items",
+			check:    ActionItemsCheck{Min: 1, Max: 2},
+			summary:  tara.Summary{ActionItems: []string{"a", "b", "c"}},
+			expected: Result{Check: "action_items", Detail: "3 items"},
+		},
+		{
+			name:     "missing action items",
+			check:    ActionItemsCheck{Min: 1, Max: 5},
+			summary:  tara.Summary{ActionItems: []string{}},
+			expected: Result{Check: "action_items", Detail: "0 items"},
+		},
+		{
+			name:    "entities dropped",
+			check:   EntityPreservationCheck{},
+			summary: tara.Summary{Summary: "alex migrated the database and should delegate."},
+			// Atlas is not in the feedback so it is not expected
+			expected: Result{Check: "entities", Score: 0.3333333333333333, Detail: "missing ledger, postgres"},
+		},
+		{
+			name:     "entities kept",
+			check:    EntityPreservationCheck{},
+			summary:  tara.Summary{Summary: "Alex led the Ledger move to Postgres."},
+			expected: Result{Check: "entities", Passed: true, Score: 1},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, tt.check.Run(c, tt.summary))
+		})
+	}
+}
+
+func TestLoadCorpus(t *testing.T) {
+	corpus, err := LoadCorpus("corpus")
+	assert.NoError(t, err)
+
+	if assert.NotEmpty(t, corpus) {
+		assert.Equal(t, "001_strong_performer", corpus[0].ID)
+	}
+
+	for _, c := range corpus {
+		assert.NotEmpty(t, c.Strengths, c.ID)
+
+		for _, entity := range c.Entities {
+			assert.Contains(t, c.Text(), entity, c.ID)
+		}
+	}
+}
@@ -0,0 +1,87 @@
+package eval
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/mitchrodrigues/talent-review-backend/app/domains/tara"
+)
+
+// Case is a single anonymized feedback of the corpus
+type Case struct {
+	ID          string `json:"id"`
+	Description string `json:"description"`
+
+	Strengths     string `json:"strengths"`
+	Opportunities string `json:"opportunities"`
+	Additional    string `json:"additional"`
+
+	// Entities are names, projects and systems mentioned in the feedback
+	// that a good summary keeps
+	Entities []string `json:"entities"`
+}
+
+func (c Case) Input() tara.SummarizeFeedbackInput {
+	return tara.SummarizeFeedbackInput{
+		Strengths:          c.Strengths,
+		Opportunities:      c.Opportunities,
+		AdditionalComments: c.Additional,
+	}
+}
+
+func (c Case) Text() string {
+	return strings.Join([]string{c.Strengths, c.Opportunities, c.Additional}, "\n")
+}
+
+// LoadCorpus loads every case of a directory (one JSON file per case) or
+// a single JSON file holding a list of cases, ordered by id
+func LoadCorpus(path string) ([]Case, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var cases []Case
+
+	if !info.IsDir() {
+		b, err := os.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+
+		if err := json.Unmarshal(b, &cases); err != nil {
+			return nil, fmt.Errorf("%s: %w", path, err)
+		}
+	} else {
+		files, err := filepath.Glob(filepath.Join(path, "*.json"))
+		if err != nil {
+			return nil, err
+		}
+
+		for _, file := range files {
+			b, err := os.ReadFile(file)
+			if err != nil {
+				return nil, err
+			}
+
+			var c Case
+			if err := json.Unmarshal(b, &c); err != nil {
+				return nil, fmt.Errorf("%s: %w", file, err)
+			}
+
+			if c.ID == "" {
+				c.ID = strings.TrimSuffix(filepath.Base(file), filepath.Ext(file))
+			}
+
+			cases = append(cases, c)
+		}
+	}
+
+	slices.SortFunc(cases, func(a, b Case) int { return strings.Compare(a.ID, b.ID) })
+
+	return cases, nil
+}
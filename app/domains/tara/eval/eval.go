@@ -0,0 +1,55 @@
+package eval
+
+import (
+	"github.com/golly-go/golly"
+	"github.com/mitchrodrigues/talent-review-backend/app/domains/tara"
+)
+
+type Options struct {
+	// Checks default to DefaultChecks
+	Checks []Check
+
+	// Rubric also grades every summary with the LLM
+	Rubric bool
+
+	// Generate is passed to tara, pin prompt versions with Generate.Prompts
+	Generate tara.GenerateOptions
+}
+
+// Run summarizes every case of the corpus and scores the result, the
+// provider is whatever is on the context, tools put a cassette there
+func Run(gctx golly.Context, corpus []Case, opts Options) Report {
+	checks := opts.Checks
+	if len(checks) == 0 {
+		checks = DefaultChecks
+	}
+
+	report := Report{Cases: make([]CaseResult, 0, len(corpus))}
+
+	for _, c := range corpus {
+		result := CaseResult{ID: c.ID}
+
+		summary, err := tara.SummarizeFeedback(gctx, opts.Generate, c.Input())
+		if err != nil {
+			result.Error = err.Error()
+			report.add(result)
+			continue
+		}
+
+		result.Summary = summary
+
+		for _, check := range checks {
+			result.Results = append(result.Results, check.Run(c, summary))
+		}
+
+		if opts.Rubric {
+			result.Results = append(result.Results, Grade(gctx, c, summary))
+		}
+
+		report.add(result)
+	}
+
+	report.finish()
+
+	return report
+}
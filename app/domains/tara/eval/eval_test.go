@@ -0,0 +1,102 @@
+package eval
+
+import (
+	"context"
+	"testing"
+
+	"github.com/golly-go/golly"
+	"github.com/golly-go/plugins/orm"
+	"github.com/mitchrodrigues/talent-review-backend/app/domains/tara"
+	"github.com/mitchrodrigues/talent-review-backend/app/utils/openai"
+	"github.com/stretchr/testify/assert"
+)
+
+// scriptedProvider answers each prompt type with a canned response
+type scriptedProvider struct {
+	openai.FixtureProvider
+
+	responses map[string]string
+}
+
+func (sp *scriptedProvider) Completions(gctx golly.Context, payload openai.CompletionPayload) (openai.CompletionResponse, error) {
+	sp.Default = sp.responses[payload.Format.JSONSchema.Name]
+	return sp.FixtureProvider.Completions(gctx, payload)
+}
+
+func newEvalTestContext(responses map[string]string) golly.Context {
+	gctx := orm.CreateTestContext(golly.NewContext(context.TODO()), tara.Prompt{})
+
+	return openai.UseProvider(gctx, &scriptedProvider{responses: responses})
+}
+
+func TestRun(t *testing.T) {
+	gctx := newEvalTestContext(map[string]string{
+		"SummarizeFeedbackPrompt": `{"summary": "Alex led the Ledger migration to Postgres and could delegate more."}`,
+		"FollowUpItemsPrompt":     `{"follow_up_items": [{"item": "Ask Alex which reviews to hand off"}]}`,
+		"RubricPrompt":            `{"grade": 4, "reason": "faithful"}`,
+	})
+
+	corpus := []Case{
+		{
+			ID:            "a",
+			Strengths:     "Alex led the Ledger migration to Postgres.",
+			Opportunities: "Alex could delegate more.",
+			Entities:      []string{"Alex", "Ledger", "Postgres"},
+		},
+		{
+			ID:        "b",
+			Strengths: "Sam shipped Atlas.",
+			Entities:  []string{"Sam", "Atlas"},
+		},
+	}
+
+	report := Run(gctx, corpus, Options{Rubric: true})
+
+	assert.Len(t, report.Cases, 2)
+	assert.Equal(t, 0, report.Errors)
+	assert.Contains(t, report.PromptVersions, "SummarizeFeedbackPrompt")
+
+	first := report.Cases[0]
+	assert.Equal(t, 0.95, first.Score)
+	assert.Len(t, first.Results, 5)
+	assert.Equal(t, Result{Check: "rubric", Passed: true, Score: 0.75, Detail: "4/5 faithful"}, first.Results[4])
+	assert.Equal(t, []string{"Ask Alex which reviews to hand off"}, first.Summary.ActionItems)
+
+	// the canned summary does not mention Sam or Atlas
+	second := report.Cases[1]
+	assert.Less(t, second.Score, 1.0)
+	assert.Equal(t, "missing sam, atlas", second.Results[3].Detail)
+
+	assert.Equal(t, 9, report.Passed)
+	assert.Equal(t, 1, report.Failed)
+	assert.Equal(t, 0.85, report.Score)
+}
+
+func TestDiff(t *testing.T) {
+	base := Report{
+		Score: 0.5,
+		Cases: []CaseResult{
+			{ID: "a", Score: 0.5, Results: []Result{{Check: "length", Passed: false}}, Summary: tara.Summary{Summary: "old"}},
+			{ID: "b", Score: 1},
+			{ID: "c", Score: 1},
+		},
+	}
+
+	head := Report{
+		Score: 0.75,
+		Cases: []CaseResult{
+			{ID: "a", Score: 1, Results: []Result{{Check: "length", Passed: true, Detail: "12 words"}}, Summary: tara.Summary{Summary: "new"}},
+			{ID: "b", Score: 1},
+			{ID: "d", Score: 0.5},
+		},
+	}
+
+	assert.Equal(t, []string{
+		"score: 0.500 -> 0.750 (+0.250)",
+		"a: 0.500 -> 1.000",
+		"a: length fail -> pass (12 words)",
+		"a: summary changed",
+		"+ d: 0.500",
+		"- c",
+	}, Diff(base, head))
+}
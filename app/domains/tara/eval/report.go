@@ -0,0 +1,197 @@
+package eval
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+	"slices"
+
+	"github.com/mitchrodrigues/talent-review-backend/app/domains/tara"
+	"github.com/mitchrodrigues/talent-review-backend/app/domains/tara/prompt"
+)
+
+// Report is written as indented JSON with a stable order so two reports
+// diff cleanly in review, Diff summarizes the changes between them
+type Report struct {
+	PromptVersions prompt.Refs `json:"promptVersions"`
+
+	Score  float64 `json:"score"`
+	Passed int     `json:"passed"`
+	Failed int     `json:"failed"`
+	Errors int     `json:"errors"`
+
+	Cases []CaseResult `json:"cases"`
+}
+
+type CaseResult struct {
+	ID    string  `json:"id"`
+	Score float64 `json:"score"`
+	Error string  `json:"error,omitempty"`
+
+	Summary tara.Summary `json:"output"`
+	Results []Result     `json:"checks"`
+}
+
+func (r *Report) add(result CaseResult) {
+	if result.Error == "" && len(result.Results) > 0 {
+		var total float64
+		for _, check := range result.Results {
+			total += check.Score
+
+			if check.Passed {
+				r.Passed++
+			} else {
+				r.Failed++
+			}
+		}
+		result.Score = round(total / float64(len(result.Results)))
+	}
+
+	if result.Error != "" {
+		r.Errors++
+	}
+
+	if r.PromptVersions == nil && len(result.Summary.PromptVersions) > 0 {
+		r.PromptVersions = result.Summary.PromptVersions
+	}
+
+	r.Cases = append(r.Cases, result)
+}
+
+func (r *Report) finish() {
+	if len(r.Cases) == 0 {
+		return
+	}
+
+	var total float64
+	for _, c := range r.Cases {
+		total += c.Score
+	}
+
+	r.Score = round(total / float64(len(r.Cases)))
+}
+
+func round(f float64) float64 {
+	return math.Round(f*1000) / 1000
+}
+
+func (r Report) Save(path string) error {
+	b, err := json.MarshalIndent(r, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(path, append(b, '\n'), 0o644)
+}
+
+func LoadReport(path string) (Report, error) {
+	var report Report
+
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return report, err
+	}
+
+	return report, json.Unmarshal(b, &report)
+}
+
+// Diff lists what changed from the base to the head report
+func Diff(base, head Report) []string {
+	lines := []string{
+		fmt.Sprintf("score: %.3f -> %.3f (%+.3f)", base.Score, head.Score, head.Score-base.Score),
+	}
+
+	names := map[string]bool{}
+	for name := range base.PromptVersions {
+		names[name] = true
+	}
+	for name := range head.PromptVersions {
+		names[name] = true
+	}
+
+	for _, name := range sortedKeys(names) {
+		if from, to := base.PromptVersions[name], head.PromptVersions[name]; from != to {
+			lines = append(lines, fmt.Sprintf("prompt %s: %s -> %s", name, from, to))
+		}
+	}
+
+	baseCases := map[string]CaseResult{}
+	for _, c := range base.Cases {
+		baseCases[c.ID] = c
+	}
+
+	seen := map[string]bool{}
+
+	for _, c := range head.Cases {
+		seen[c.ID] = true
+
+		before, found := baseCases[c.ID]
+		if !found {
+			lines = append(lines, fmt.Sprintf("+ %s: %.3f", c.ID, c.Score))
+			continue
+		}
+
+		lines = append(lines, diffCase(before, c)...)
+	}
+
+	for _, c := range base.Cases {
+		if !seen[c.ID] {
+			lines = append(lines, fmt.Sprintf("- %s", c.ID))
+		}
+	}
+
+	return lines
+}
+
+func diffCase(base, head CaseResult) []string {
+	lines := []string{}
+
+	if base.Score != head.Score {
+		lines = append(lines, fmt.Sprintf("%s: %.3f -> %.3f", head.ID, base.Score, head.Score))
+	}
+
+	if base.Error != head.Error {
+		lines = append(lines, fmt.Sprintf("%s: error %q -> %q", head.ID, base.Error, head.Error))
+	}
+
+	checks := map[string]Result{}
+	for _, result := range base.Results {
+		checks[result.Check] = result
+	}
+
+	for _, result := range head.Results {
+		before, found := checks[result.Check]
+		if found && before.Passed != result.Passed {
+			lines = append(lines, fmt.Sprintf("%s: %s %s -> %s (%s)", head.ID, result.Check, status(before.Passed), status(result.Passed), result.Detail))
+		}
+	}
+
+	if base.Summary.Summary != head.Summary.Summary {
+		lines = append(lines, fmt.Sprintf("%s: summary changed", head.ID))
+	}
+
+	if !slices.Equal(base.Summary.ActionItems, head.Summary.ActionItems) {
+		lines = append(lines, fmt.Sprintf("%s: action items changed", head.ID))
+	}
+
+	return lines
+}
+
+func status(passed bool) string {
+	if passed {
+		return "pass"
+	}
+	return "fail"
+}
+
+func sortedKeys(m map[string]bool) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+
+	slices.Sort(keys)
+
+	return keys
+}
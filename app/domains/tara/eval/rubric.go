@@ -0,0 +1,77 @@
+package eval
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/golly-go/golly"
+	"github.com/mitchrodrigues/talent-review-backend/app/domains/tara"
+	"github.com/mitchrodrigues/talent-review-backend/app/utils/openai"
+)
+
+const (
+	rubricPassingGrade = 3
+)
+
+// RubricPrompt asks the LLM to grade a summary against the feedback it
+// summarizes, it is optional since it is neither free nor deterministic
+// (unless replayed from a cassette)
+type RubricPrompt struct {
+	openai.CompletionPromptBase `json:"-"`
+
+	Case    Case         `json:"-"`
+	Summary tara.Summary `json:"-"`
+
+	Grade  int    `json:"grade" ai:"integer grade from 1 (poor) to 5 (excellent)" min:"1" max:"5"`
+	Reason string `json:"reason" ai:"string one sentence explaining the grade"`
+}
+
+func (prompt RubricPrompt) Context(gctx golly.Context) openai.AIContexts {
+	items := golly.Map(prompt.Summary.ActionItems, func(item string) string { return "- " + item })
+
+	return openai.AIContexts{
+		openai.NewDefaultContentRole(openai.RoleUser,
+			fmt.Sprintf(
+				"Feedback:\nStrengths: %s\nOpportunities: %s\nAdditional Comments: %s\n\nSummary:\n%s\n\nFollow-up items:\n%s",
+				prompt.Case.Strengths,
+				prompt.Case.Opportunities,
+				prompt.Case.Additional,
+				prompt.Summary.Summary,
+				strings.Join(items, "\n"),
+			),
+		),
+	}
+}
+
+func (RubricPrompt) Rules(gctx golly.Context) []string {
+	return []string{
+		"Grade how faithful the summary is to the feedback, facts that are missing or invented lower the grade.",
+		"Grade down summaries that editorialize or change the tone of the feedback.",
+		"Grade down summaries that drop the concrete examples given in the feedback.",
+		"Follow-up items should be specific tasks for the manager grounded in the feedback.",
+	}
+}
+
+func (RubricPrompt) Scenario(gctx golly.Context) []string {
+	return []string{
+		"You are reviewing the quality of a summary of feedback about an employee and the follow-up items suggested to their manager.",
+	}
+}
+
+func (RubricPrompt) Model() openai.AIModel { return openai.StandardModel }
+
+// Grade runs the rubric for a case
+func Grade(gctx golly.Context, c Case, summary tara.Summary) Result {
+	prompt := &RubricPrompt{Case: c, Summary: summary}
+
+	if err := tara.Generate(gctx, prompt); err != nil {
+		return Result{Check: "rubric", Detail: err.Error()}
+	}
+
+	return Result{
+		Check:  "rubric",
+		Passed: prompt.Grade >= rubricPassingGrade,
+		Score:  float64(min(max(prompt.Grade, 1), 5)-1) / 4,
+		Detail: fmt.Sprintf("%d/5 %s", prompt.Grade, prompt.Reason),
+	}
+}
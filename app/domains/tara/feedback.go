@@ -2,15 +2,59 @@ package tara
 
 import (
 	"fmt"
+	"strings"
 
 	"github.com/golly-go/golly"
+	"github.com/mitchrodrigues/talent-review-backend/app/domains/tara/prompt"
 	"github.com/mitchrodrigues/talent-review-backend/app/utils/openai"
 )
 
+var (
+	summarizeFeedbackDefaults = PromptDefaults{
+		Rules: []string{
+			"The summary should be concise and cover key points of the feedback.",
+			"Use simple, concise, and professional wording.",
+			"If there are examples in the feedback, be sure to include them in the summary.",
+			"Do not editorialize the feedback.",
+			"Do not omit details from the feedback.",
+			"When expected behaviors are given, note where the feedback shows the employee meeting, exceeding or falling short of them.",
+		},
+		Scenario: []string{
+			"You are given feedback about an employee. Summarize the feedback clearly.",
+			"Ensure that the summary is simple, concise, and uses professional wording without editorializing.",
+		},
+	}
+
+	followUpItemsDefaults = PromptDefaults{
+		Rules: []string{
+			"Identify and highlight specific follow-up items for the manager to review based on the provided feedback.",
+			"Highlight any ambiguities, lack of details, or concerning issues that the manager should follow up on.",
+			"Follow-up items should be specific tasks for the manager, such as clarifying ambiguous feedback, addressing lack of details, or taking action on concerning issues.",
+			"Do not include career advice or general improvement suggestions for the employee.",
+			"Use simple, concise, and professional wording.",
+		},
+		Scenario: []string{
+			"You are given feedback about an employee. Based on this feedback, identify no more then 5 specific follow-up items for the manager to review.",
+			"Highlight any ambiguities, lack of details, or concerning issues that the manager should address.",
+			"Ensure that follow-up items are specific tasks for the manager and do not include career advice or general improvement suggestions for the employee.",
+			"Example follow-up items:",
+			"1. Clarify the specific areas in which the employee needs to improve communication skills.",
+			"2. Follow up to get more details on reported delays in project delivery.",
+			"3. Investigate the reasons behind the employee's inconsistent performance.",
+			"4. Schedule a one-on-one meeting to discuss the employee's concerns about team collaboration.",
+			"5. Ensure the employee receives specific examples of where they excel and where they need improvement.",
+		},
+	}
+)
+
 type SummarizeFeedbackInput struct {
 	Strengths          string
 	Opportunities      string
 	AdditionalComments string
+
+	// Expectations are the behaviors the career framework expects of the
+	// employee at their current and next level
+	Expectations []string
 }
 
 type ActionItem struct {
@@ -28,38 +72,34 @@ func (ai ActionItems) Values() []string {
 type SummarizeFeedbackPrompt struct {
 	openai.CompletionPromptBase `json:"-"`
 	SummarizeFeedbackInput      `json:"-"`
+	Versioned                   `json:"-"`
 
 	Summary string `json:"summary" ai:"string summary of the feedback"`
 }
 
 func (prompt SummarizeFeedbackPrompt) Context(gctx golly.Context) openai.AIContexts {
+	content := fmt.Sprintf(
+		"Strengths: %s\nOpportunities: %s\nAdditional Comments: %s",
+		prompt.SummarizeFeedbackInput.Strengths,
+		prompt.SummarizeFeedbackInput.Opportunities,
+		prompt.SummarizeFeedbackInput.AdditionalComments,
+	)
+
+	if len(prompt.Expectations) > 0 {
+		content += fmt.Sprintf("\nExpected behaviors:\n%s", strings.Join(prompt.Expectations, "\n"))
+	}
+
 	return openai.AIContexts{
-		openai.NewDefaultContentRole(openai.RoleUser,
-			fmt.Sprintf(
-				"Strengths: %s\nOpportunities: %s\nAdditional Comments: %s",
-				prompt.SummarizeFeedbackInput.Strengths,
-				prompt.SummarizeFeedbackInput.Opportunities,
-				prompt.SummarizeFeedbackInput.AdditionalComments,
-			),
-		),
+		openai.NewDefaultContentRole(openai.RoleUser, content),
 	}
 }
 
-func (SummarizeFeedbackPrompt) Rules(gctx golly.Context) []string {
-	return []string{
-		"The summary should be concise and cover key points of the feedback.",
-		"Use simple, concise, and professional wording.",
-		"If there are examples in the feedback, be sure to include them in the summary.",
-		"Do not editorialize the feedback.",
-		"Do not omit details from the feedback.",
-	}
+func (prompt SummarizeFeedbackPrompt) Rules(gctx golly.Context) []string {
+	return prompt.Version.RulesOr(summarizeFeedbackDefaults.Rules)
 }
 
 func (prompt SummarizeFeedbackPrompt) Scenario(gctx golly.Context) []string {
-	return []string{
-		"You are given feedback about an employee. Summarize the feedback clearly.",
-		"Ensure that the summary is simple, concise, and uses professional wording without editorializing.",
-	}
+	return prompt.Version.ScenarioOr(summarizeFeedbackDefaults.Scenario)
 }
 
 func (prompt SummarizeFeedbackPrompt) PromptToContexts(gctx golly.Context) openai.AIContexts {
@@ -75,34 +115,59 @@ func NewSummaryFeedbackPrompt(input SummarizeFeedbackInput) *SummarizeFeedbackPr
 
 type FollowUpItemsPrompt struct {
 	openai.CompletionPromptBase `json:"-"`
+	Versioned                   `json:"-"`
 
-	FollowUpItems ActionItems `json:"follow_up_items" ai:"string follow-up items for the manager"`
+	FollowUpItems ActionItems `json:"follow_up_items" ai:"string follow-up items for the manager" max:"5"`
 }
 
-func (FollowUpItemsPrompt) Rules(gctx golly.Context) []string {
-	return []string{
-		"Identify and highlight specific follow-up items for the manager to review based on the provided feedback.",
-		"Highlight any ambiguities, lack of details, or concerning issues that the manager should follow up on.",
-		"Follow-up items should be specific tasks for the manager, such as clarifying ambiguous feedback, addressing lack of details, or taking action on concerning issues.",
-		"Do not include career advice or general improvement suggestions for the employee.",
-		"Use simple, concise, and professional wording.",
-	}
+func (prompt FollowUpItemsPrompt) Rules(gctx golly.Context) []string {
+	return prompt.Version.RulesOr(followUpItemsDefaults.Rules)
 }
 
 func (prompt FollowUpItemsPrompt) Scenario(gctx golly.Context) []string {
-	return []string{
-		"You are given feedback about an employee. Based on this feedback, identify no more then 5 specific follow-up items for the manager to review.",
-		"Highlight any ambiguities, lack of details, or concerning issues that the manager should address.",
-		"Ensure that follow-up items are specific tasks for the manager and do not include career advice or general improvement suggestions for the employee.",
-		"Example follow-up items:",
-		"1. Clarify the specific areas in which the employee needs to improve communication skills.",
-		"2. Follow up to get more details on reported delays in project delivery.",
-		"3. Investigate the reasons behind the employee's inconsistent performance.",
-		"4. Schedule a one-on-one meeting to discuss the employee's concerns about team collaboration.",
-		"5. Ensure the employee receives specific examples of where they excel and where they need improvement.",
-	}
+	return prompt.Version.ScenarioOr(followUpItemsDefaults.Scenario)
 }
 
 func NewFollowUpItemsPrompt() *FollowUpItemsPrompt {
 	return &FollowUpItemsPrompt{}
 }
+
+// Summary is the result of summarizing a feedback
+type Summary struct {
+	Summary        string      `json:"summary"`
+	ActionItems    []string    `json:"actionItems"`
+	PromptVersions prompt.Refs `json:"promptVersions"`
+}
+
+// SummarizeFeedback summarizes a feedback and collects the follow up
+// items for the manager
+func SummarizeFeedback(gctx golly.Context, opts GenerateOptions, input SummarizeFeedbackInput) (Summary, error) {
+	result := Summary{ActionItems: []string{}, PromptVersions: prompt.Refs{}}
+
+	summaryPrompt := NewSummaryFeedbackPrompt(input)
+
+	if err := GenerateWithOptions(gctx, opts, summaryPrompt); err != nil {
+		return result, err
+	}
+
+	result.Summary = summaryPrompt.Summary
+	result.PromptVersions[summaryPrompt.Version.Name] = summaryPrompt.Version.Ref
+
+	// Follow up items are optional, skip them when the organization
+	// has run over its budget and tara is degraded
+	if Degraded(gctx) {
+		return result, nil
+	}
+
+	itemsPrompt := NewFollowUpItemsPrompt()
+	itemsPrompt.AddPreviousPrompts(summaryPrompt)
+
+	if err := GenerateWithOptions(gctx, opts, itemsPrompt); err != nil {
+		return result, err
+	}
+
+	result.ActionItems = itemsPrompt.FollowUpItems.Values()
+	result.PromptVersions[itemsPrompt.Version.Name] = itemsPrompt.Version.Ref
+
+	return result, nil
+}
@@ -0,0 +1,626 @@
+package tara
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/golly-go/golly"
+	"github.com/golly-go/golly/errors"
+	"github.com/golly-go/plugins/gql"
+	"github.com/google/uuid"
+	"github.com/graphql-go/graphql"
+	"github.com/mitchrodrigues/talent-review-backend/app/domains/accounts"
+	"github.com/mitchrodrigues/talent-review-backend/app/domains/tara/conversation"
+	"github.com/mitchrodrigues/talent-review-backend/app/domains/tara/prompt"
+	"github.com/mitchrodrigues/talent-review-backend/app/utils/helpers"
+)
+
+const (
+	usageMonthFormat  = "2006-01"
+	usageHistoryLimit = 12
+)
+
+type UsageReport struct {
+	OrganizationID uuid.UUID
+	Month          time.Time
+
+	Budget BudgetStatus
+	Totals MonthlyUsage
+
+	Breakdown []UsageBreakdown
+	History   []MonthlyUsage
+}
+
+var (
+	monthlyUsageType = graphql.NewObject(graphql.ObjectConfig{
+		Name: "LLMMonthlyUsage",
+		Fields: graphql.Fields{
+			"month": {
+				Type: graphql.String,
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					return p.Source.(MonthlyUsage).Month.Format(usageMonthFormat), nil
+				},
+			},
+			"requests": {
+				Type: graphql.Int,
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					return p.Source.(MonthlyUsage).Requests, nil
+				},
+			},
+			"promptTokens": {
+				Type: graphql.Int,
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					return p.Source.(MonthlyUsage).PromptTokens, nil
+				},
+			},
+			"completionTokens": {
+				Type: graphql.Int,
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					return p.Source.(MonthlyUsage).CompletionTokens, nil
+				},
+			},
+			"totalTokens": {
+				Type: graphql.Int,
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					return p.Source.(MonthlyUsage).TotalTokens, nil
+				},
+			},
+			"cacheHits": {
+				Type:        graphql.Int,
+				Description: "Calls answered from the response cache, they use no tokens",
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					return p.Source.(MonthlyUsage).CacheHits, nil
+				},
+			},
+			"cacheMisses": {
+				Type: graphql.Int,
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					return p.Source.(MonthlyUsage).CacheMisses, nil
+				},
+			},
+		},
+	})
+
+	usageBreakdownType = graphql.NewObject(graphql.ObjectConfig{
+		Name: "LLMUsageBreakdown",
+		Fields: graphql.Fields{
+			"kind": {
+				Type: graphql.String,
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					return p.Source.(UsageBreakdown).Kind, nil
+				},
+			},
+			"model": {
+				Type: graphql.String,
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					return p.Source.(UsageBreakdown).Model, nil
+				},
+			},
+			"promptType": {
+				Type: graphql.String,
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					return p.Source.(UsageBreakdown).PromptType, nil
+				},
+			},
+			"requests": {
+				Type: graphql.Int,
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					return p.Source.(UsageBreakdown).Requests, nil
+				},
+			},
+			"promptTokens": {
+				Type: graphql.Int,
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					return p.Source.(UsageBreakdown).PromptTokens, nil
+				},
+			},
+			"completionTokens": {
+				Type: graphql.Int,
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					return p.Source.(UsageBreakdown).CompletionTokens, nil
+				},
+			},
+			"totalTokens": {
+				Type: graphql.Int,
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					return p.Source.(UsageBreakdown).TotalTokens, nil
+				},
+			},
+			"cacheHits": {
+				Type:        graphql.Int,
+				Description: "Calls answered from the response cache, they use no tokens",
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					return p.Source.(UsageBreakdown).CacheHits, nil
+				},
+			},
+			"cacheMisses": {
+				Type: graphql.Int,
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					return p.Source.(UsageBreakdown).CacheMisses, nil
+				},
+			},
+		},
+	})
+
+	usageReportType = graphql.NewObject(graphql.ObjectConfig{
+		Name: "LLMUsageReport",
+		Fields: graphql.Fields{
+			"month": {
+				Type: graphql.String,
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					return p.Source.(UsageReport).Month.Format(usageMonthFormat), nil
+				},
+			},
+			"budget": {
+				Type:        graphql.Int,
+				Description: "Monthly token budget, 0 when unlimited",
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					return p.Source.(UsageReport).Budget.Budget, nil
+				},
+			},
+			"budgetMode": {
+				Type: graphql.String,
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					return p.Source.(UsageReport).Budget.Mode, nil
+				},
+			},
+			"remainingTokens": {
+				Type:        graphql.Int,
+				Description: "Tokens left this month, -1 when unlimited",
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					return p.Source.(UsageReport).Budget.Remaining(), nil
+				},
+			},
+			"exceeded": {
+				Type: graphql.Boolean,
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					return p.Source.(UsageReport).Budget.Exceeded(), nil
+				},
+			},
+			"totals": {
+				Type: monthlyUsageType,
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					return p.Source.(UsageReport).Totals, nil
+				},
+			},
+			"breakdown": {
+				Type: graphql.NewList(usageBreakdownType),
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					return p.Source.(UsageReport).Breakdown, nil
+				},
+			},
+			"history": {
+				Type: graphql.NewList(monthlyUsageType),
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					return p.Source.(UsageReport).History, nil
+				},
+			},
+		},
+	})
+
+	promptType = graphql.NewObject(graphql.ObjectConfig{
+		Name: "TaraPrompt",
+		Fields: graphql.Fields{
+			"id": {
+				Type: graphql.String,
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					return p.Source.(Prompt).ID, nil
+				},
+			},
+			"name": {
+				Type: graphql.String,
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					return p.Source.(Prompt).Name, nil
+				},
+			},
+			"version": {
+				Type: graphql.Int,
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					return p.Source.(Prompt).Revision, nil
+				},
+			},
+			"override": {
+				Type:        graphql.Boolean,
+				Description: "True when this is an override of the organization",
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					return p.Source.(Prompt).IsOverride(), nil
+				},
+			},
+			"template": {
+				Type: graphql.String,
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					return p.Source.(Prompt).Template, nil
+				},
+			},
+			"rules": {
+				Type: graphql.NewList(graphql.String),
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					return []string(p.Source.(Prompt).Rules), nil
+				},
+			},
+			"scenario": {
+				Type: graphql.NewList(graphql.String),
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					return []string(p.Source.(Prompt).Scenario), nil
+				},
+			},
+			"tone": {
+				Type: graphql.String,
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					return p.Source.(Prompt).Tone, nil
+				},
+			},
+			"active": {
+				Type: graphql.Boolean,
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					return p.Source.(Prompt).Active, nil
+				},
+			},
+			"createdAt": {
+				Type: graphql.DateTime,
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					return p.Source.(Prompt).CreatedAt, nil
+				},
+			},
+		},
+	})
+
+	PromptRefType = graphql.NewObject(graphql.ObjectConfig{
+		Name: "TaraPromptRef",
+		Fields: graphql.Fields{
+			"name": {
+				Type: graphql.String,
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					return p.Source.(NamedPromptRef).Name, nil
+				},
+			},
+			"id": {
+				Type:        graphql.String,
+				Description: "Empty when the built in prompt was used",
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					if ref := p.Source.(NamedPromptRef); !ref.IsBuiltin() {
+						return ref.ID, nil
+					}
+					return nil, nil
+				},
+			},
+			"version": {
+				Type: graphql.Int,
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					return p.Source.(NamedPromptRef).Revision, nil
+				},
+			},
+			"overrideID": {
+				Type: graphql.String,
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					if ref := p.Source.(NamedPromptRef); ref.OverrideID != uuid.Nil {
+						return ref.OverrideID, nil
+					}
+					return nil, nil
+				},
+			},
+			"overrideVersion": {
+				Type: graphql.Int,
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					return p.Source.(NamedPromptRef).OverrideRevision, nil
+				},
+			},
+			"label": {
+				Type: graphql.String,
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					return p.Source.(NamedPromptRef).String(), nil
+				},
+			},
+		},
+	})
+
+	conversationMessageType = graphql.NewObject(graphql.ObjectConfig{
+		Name: "TaraConversationMessage",
+		Fields: graphql.Fields{
+			"role": {
+				Type:        graphql.String,
+				Description: "user or assistant",
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					return p.Source.(conversation.Message).Role, nil
+				},
+			},
+			"content": {
+				Type: graphql.String,
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					return p.Source.(conversation.Message).Content, nil
+				},
+			},
+			"citations": {
+				Type:        graphql.NewList(graphql.String),
+				Description: "IDs of the feedback the answer is based on",
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					return golly.Map(p.Source.(conversation.Message).Citations, func(id uuid.UUID) string {
+						return id.String()
+					}), nil
+				},
+			},
+			"promptVersions": {
+				Type: graphql.NewList(PromptRefType),
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					return SortedPromptRefs(p.Source.(conversation.Message).PromptVersions), nil
+				},
+			},
+			"createdAt": {
+				Type: graphql.DateTime,
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					return p.Source.(conversation.Message).CreatedAt, nil
+				},
+			},
+		},
+	})
+
+	ConversationType = graphql.NewObject(graphql.ObjectConfig{
+		Name: "TaraConversation",
+		Fields: graphql.Fields{
+			"id": {
+				Type: graphql.String,
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					return p.Source.(Conversation).ID, nil
+				},
+			},
+			"employeeID": {
+				Type: graphql.String,
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					return p.Source.(Conversation).EmployeeID, nil
+				},
+			},
+			"title": {
+				Type: graphql.String,
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					return p.Source.(Conversation).Title, nil
+				},
+			},
+			"messages": {
+				Type: graphql.NewList(conversationMessageType),
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					return []conversation.Message(p.Source.(Conversation).Messages), nil
+				},
+			},
+			"createdAt": {
+				Type: graphql.DateTime,
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					return p.Source.(Conversation).CreatedAt, nil
+				},
+			},
+			"updatedAt": {
+				Type: graphql.DateTime,
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					return p.Source.(Conversation).UpdatedAt, nil
+				},
+			},
+		},
+	})
+
+	promptOverrideInputType = graphql.NewInputObject(graphql.InputObjectConfig{
+		Name: "TaraPromptOverrideInput",
+		Fields: graphql.InputObjectConfigFieldMap{
+			"name":  &graphql.InputObjectFieldConfig{Type: graphql.NewNonNull(graphql.String)},
+			"tone":  &graphql.InputObjectFieldConfig{Type: graphql.String},
+			"rules": &graphql.InputObjectFieldConfig{Type: graphql.NewList(graphql.String)},
+		},
+	})
+
+	query = graphql.Fields{
+		"llmUsage": {
+			Name: "llmUsage",
+			Type: usageReportType,
+			Args: graphql.FieldConfigArgument{
+				"month": {Type: graphql.String, Description: "YYYY-MM, defaults to the current month"},
+			},
+			Resolve: gql.NewHandler(gql.Options{
+				Handler: func(ctx golly.WebContext, params gql.Params) (interface{}, error) {
+					user, err := accounts.RequireAdmin(ctx.Context)
+					if err != nil {
+						return nil, err
+					}
+
+					month := time.Now()
+					if m, err := helpers.ExtractArg[string](params.Args, "month"); err == nil && m != "" {
+						if month, err = time.Parse(usageMonthFormat, m); err != nil {
+							return nil, errors.WrapUnprocessable(fmt.Errorf("invalid month %s", m))
+						}
+					}
+
+					return FindUsageReport(ctx.Context, user.OrganizationID, month)
+				},
+			}),
+		},
+
+		"taraPrompts": {
+			Name: "taraPrompts",
+			Type: graphql.NewList(promptType),
+			Args: graphql.FieldConfigArgument{
+				"name": {Type: graphql.String},
+			},
+			Resolve: gql.NewHandler(gql.Options{
+				Handler: func(ctx golly.WebContext, params gql.Params) (interface{}, error) {
+					user, err := accounts.RequireAdmin(ctx.Context)
+					if err != nil {
+						return nil, err
+					}
+
+					name, _ := helpers.ExtractArg[string](params.Args, "name")
+
+					return FindPromptVersions(ctx.Context, user.OrganizationID, name)
+				},
+			}),
+		},
+		"taraConversations": {
+			Name:        "taraConversations",
+			Type:        graphql.NewList(ConversationType),
+			Description: "Ask-Tara conversations of the current user about an employee",
+			Args: graphql.FieldConfigArgument{
+				"employeeID": {Type: graphql.NewNonNull(graphql.String)},
+			},
+			Resolve: gql.NewHandler(gql.Options{
+				Handler: func(ctx golly.WebContext, params gql.Params) (interface{}, error) {
+					employeeID, err := helpers.ExtractAndParseUUID(params.Args, "employeeID")
+					if err != nil {
+						return nil, err
+					}
+
+					return FindConversations(ctx.Context, employeeID)
+				},
+			}),
+		},
+		"taraConversation": {
+			Name: "taraConversation",
+			Type: ConversationType,
+			Args: graphql.FieldConfigArgument{
+				"id": {Type: graphql.NewNonNull(graphql.String)},
+			},
+			Resolve: gql.NewHandler(gql.Options{
+				Handler: func(ctx golly.WebContext, params gql.Params) (interface{}, error) {
+					id, err := helpers.ExtractAndParseUUID(params.Args, "id")
+					if err != nil {
+						return nil, err
+					}
+
+					return FindConversation(ctx.Context, id)
+				},
+			}),
+		},
+	}
+
+	mutations = graphql.Fields{
+		"overrideTaraPrompt": {
+			Name:        "overrideTaraPrompt",
+			Type:        promptType,
+			Description: "Creates and activates a new override of the tone or rules of a prompt",
+			Args: graphql.FieldConfigArgument{
+				"input": &graphql.ArgumentConfig{Type: graphql.NewNonNull(promptOverrideInputType)},
+			},
+			Resolve: gql.NewHandler(gql.Options{
+				Handler: func(ctx golly.WebContext, params gql.Params) (interface{}, error) {
+					user, err := accounts.RequireAdmin(ctx.Context)
+					if err != nil {
+						return nil, err
+					}
+
+					name, _ := helpers.ExtractArg[string](params.Input, "name")
+					if _, found := DefaultPrompts[name]; !found {
+						return nil, errors.WrapUnprocessable(fmt.Errorf("unknown prompt %s", name))
+					}
+
+					tone, _ := helpers.ExtractArg[string](params.Input, "tone")
+					rules, _ := helpers.ExtractArg[[]interface{}](params.Input, "rules")
+
+					return CreatePromptVersion(ctx.Context, prompt.Create{
+						OrganizationID: user.OrganizationID,
+						Name:           name,
+						Tone:           tone,
+						Rules: golly.Map(rules, func(rule interface{}) string {
+							str, _ := rule.(string)
+							return str
+						}),
+					}, true, params.Metadata())
+				},
+			}),
+		},
+
+		"activateTaraPrompt": {
+			Name:        "activateTaraPrompt",
+			Type:        promptType,
+			Description: "Activates an earlier override of the organization",
+			Args: graphql.FieldConfigArgument{
+				"id": &graphql.ArgumentConfig{Type: graphql.NewNonNull(graphql.String)},
+			},
+			Resolve: gql.NewHandler(gql.Options{
+				Handler: func(ctx golly.WebContext, params gql.Params) (interface{}, error) {
+					user, err := accounts.RequireAdmin(ctx.Context)
+					if err != nil {
+						return nil, err
+					}
+
+					id, err := helpers.ExtractAndParseUUID(params.Args, "id")
+					if err != nil {
+						return nil, err
+					}
+
+					p, err := FindPromptByID(ctx.Context, id)
+					if err != nil || p.OrganizationID != user.OrganizationID {
+						return nil, errors.WrapNotFound(fmt.Errorf("record not found"))
+					}
+
+					return p, ActivatePrompt(ctx.Context, &p, params.Metadata())
+				},
+			}),
+		},
+
+		"resetTaraPrompt": {
+			Name:        "resetTaraPrompt",
+			Type:        graphql.Boolean,
+			Description: "Removes the active override so the organization uses the global prompt",
+			Args: graphql.FieldConfigArgument{
+				"name": &graphql.ArgumentConfig{Type: graphql.NewNonNull(graphql.String)},
+			},
+			Resolve: gql.NewHandler(gql.Options{
+				Handler: func(ctx golly.WebContext, params gql.Params) (interface{}, error) {
+					user, err := accounts.RequireAdmin(ctx.Context)
+					if err != nil {
+						return nil, err
+					}
+
+					name, _ := helpers.ExtractArg[string](params.Args, "name")
+
+					return true, DeactivatePrompt(ctx.Context, user.OrganizationID, name, params.Metadata())
+				},
+			}),
+		},
+	}
+)
+
+type NamedPromptRef struct {
+	Name string
+	prompt.Ref
+}
+
+// SortedPromptRefs flattens recorded prompt versions for display
+func SortedPromptRefs(refs prompt.Refs) []NamedPromptRef {
+	ret := make([]NamedPromptRef, 0, len(refs))
+	for name, ref := range refs {
+		ret = append(ret, NamedPromptRef{Name: name, Ref: ref})
+	}
+
+	slices.SortFunc(ret, func(a, b NamedPromptRef) int {
+		return strings.Compare(a.Name, b.Name)
+	})
+
+	return ret
+}
+
+func FindUsageReport(gctx golly.Context, organizationID uuid.UUID, month time.Time) (UsageReport, error) {
+	report := UsageReport{
+		OrganizationID: organizationID,
+		Month:          StartOfMonth(month),
+		Budget:         BudgetForOrganization(gctx, organizationID, month),
+	}
+
+	var err error
+
+	if report.Totals, err = FindMonthlyUsage(gctx, organizationID, month); err != nil {
+		return report, errors.WrapGeneric(err)
+	}
+
+	if report.Breakdown, err = FindUsageBreakdown(gctx, organizationID, month); err != nil {
+		return report, errors.WrapGeneric(err)
+	}
+
+	if report.History, err = FindMonthlyUsageHistory(gctx, organizationID, usageHistoryLimit); err != nil {
+		return report, errors.WrapGeneric(err)
+	}
+
+	return report, nil
+}
+
+func InitGraphQL() {
+	gql.RegisterQuery(query)
+	gql.RegisterMutation(mutations)
+}
@@ -0,0 +1,105 @@
+package tara
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/golly-go/golly"
+	"github.com/google/uuid"
+	"github.com/mitchrodrigues/talent-review-backend/app/domains/tara/prompt"
+	"github.com/mitchrodrigues/talent-review-backend/app/utils/openai"
+)
+
+var (
+	promotionJustificationDefaults = PromptDefaults{
+		Rules: []string{
+			"Argue for the promotion only with evidence from the sources, do not use outside knowledge or assumptions.",
+			"Measure the employee against the expectations of the target role, not against their peers.",
+			"Point out expectations of the target role the sources do not show evidence for.",
+			"List the id of every source the justification is based on in citations.",
+			"Refer to reviewers generally (for example \"a peer noted\"), never guess who wrote a piece of feedback.",
+			"Write in the third person for the approvers using simple, concise, and professional wording.",
+		},
+		Scenario: []string{
+			"You are helping a manager write the justification of a promotion for the people who approve it.",
+			"Sources are feedback excerpts and feedback summaries about the employee, each labelled with its id and the date it was written.",
+			"The manager will edit your draft before the approvers read it.",
+		},
+	}
+)
+
+type PromotionJustificationInput struct {
+	Employee string
+
+	// CurrentRole and TargetRole describe the title, level and track of the
+	// roles, expectations are what the target level is expected to show
+	CurrentRole  string
+	TargetRole   string
+	Expectations []string
+
+	Sources []Source
+}
+
+type PromotionJustificationPrompt struct {
+	openai.CompletionPromptBase `json:"-"`
+	PromotionJustificationInput `json:"-"`
+	Versioned                   `json:"-"`
+
+	Justification string   `json:"justification" ai:"string justification of the promotion"`
+	Citations     []string `json:"citations" ai:"string ids of the sources the justification is based on"`
+}
+
+func (prompt PromotionJustificationPrompt) Context(gctx golly.Context) openai.AIContexts {
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "Employee: %s\nCurrent role: %s\nTarget role: %s\n", prompt.Employee, prompt.CurrentRole, prompt.TargetRole)
+
+	if len(prompt.Expectations) > 0 {
+		fmt.Fprintf(&b, "Expectations of the target role:\n%s\n", strings.Join(prompt.Expectations, "\n"))
+	}
+
+	sources := golly.Map(prompt.Sources, func(source Source) string { return source.String() })
+	fmt.Fprintf(&b, "Sources:\n%s", strings.Join(sources, "\n"))
+
+	return openai.AIContexts{
+		openai.NewDefaultContentRole(openai.RoleUser, b.String()),
+	}
+}
+
+func (prompt PromotionJustificationPrompt) Rules(gctx golly.Context) []string {
+	return prompt.Version.RulesOr(promotionJustificationDefaults.Rules)
+}
+
+func (prompt PromotionJustificationPrompt) Scenario(gctx golly.Context) []string {
+	return prompt.Version.ScenarioOr(promotionJustificationDefaults.Scenario)
+}
+
+func NewPromotionJustificationPrompt(input PromotionJustificationInput) *PromotionJustificationPrompt {
+	return &PromotionJustificationPrompt{PromotionJustificationInput: input}
+}
+
+// PromotionJustification is the justification tara drafted, citations
+// are limited to the sources it was given
+type PromotionJustification struct {
+	Justification  string      `json:"justification"`
+	Citations      []uuid.UUID `json:"citations"`
+	PromptVersions prompt.Refs `json:"promptVersions"`
+}
+
+// DraftPromotionJustification drafts why an employee should be promoted
+// to the target role from their feedback
+func DraftPromotionJustification(gctx golly.Context, opts GenerateOptions, input PromotionJustificationInput) (PromotionJustification, error) {
+	result := PromotionJustification{Citations: []uuid.UUID{}, PromptVersions: prompt.Refs{}}
+
+	justificationPrompt := NewPromotionJustificationPrompt(input)
+
+	if err := GenerateWithOptions(gctx, opts, justificationPrompt); err != nil {
+		return result, err
+	}
+
+	result.Justification = strings.TrimSpace(justificationPrompt.Justification)
+	result.Citations = validCitations(justificationPrompt.Citations, input.Sources)
+	result.PromptVersions[justificationPrompt.Version.Name] = justificationPrompt.Version.Ref
+
+	return result, nil
+}
@@ -0,0 +1,46 @@
+package tara
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/golly-go/golly"
+	"github.com/google/uuid"
+	"github.com/mitchrodrigues/talent-review-backend/app/utils/openai"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDraftPromotionJustification(t *testing.T) {
+	_, gctx := newPromptTestContext()
+
+	known := uuid.New()
+
+	provider := &capturingProvider{FixtureProvider: openai.FixtureProvider{Default: fmt.Sprintf(`{
+		"justification": " Jane already leads the planner work. ",
+		"citations": ["%s","%s"]
+	}`, known, uuid.New())}}
+
+	justification, err := DraftPromotionJustification(openai.UseProvider(gctx, provider), GenerateOptions{}, PromotionJustificationInput{
+		Employee:     "Jane",
+		CurrentRole:  "Engineer, level 3, IC track",
+		TargetRole:   "Senior Engineer, level 4, IC track",
+		Expectations: []string{"Execution (level 4): Delivers projects"},
+		Sources: []Source{
+			{ID: known, Kind: "feedback", Section: "strengths", Date: time.Now(), Content: "Led the planner project."},
+		},
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, "Jane already leads the planner work.", justification.Justification)
+	assert.Equal(t, []uuid.UUID{known}, justification.Citations)
+	assert.Contains(t, justification.PromptVersions, "PromotionJustificationPrompt")
+
+	if assert.Len(t, provider.payloads, 1) {
+		contents := golly.Map(provider.payloads[0].Messages, func(m openai.Message) string { return m.Content })
+
+		assert.NotEqual(t, -1, indexContaining(contents, "Target role: Senior Engineer, level 4"))
+		assert.NotEqual(t, -1, indexContaining(contents, "Execution (level 4): Delivers projects"))
+		assert.NotEqual(t, -1, indexContaining(contents, "Led the planner project."))
+	}
+}
@@ -0,0 +1,103 @@
+package prompt
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/golly-go/golly"
+	"github.com/golly-go/plugins/eventsource"
+	"github.com/golly-go/plugins/orm"
+	"github.com/google/uuid"
+	"github.com/mitchrodrigues/talent-review-backend/app/utils/esbackend"
+)
+
+// Aggregate is a single version of a tara prompt, versions are never
+// edited, a change is a new version that gets activated.
+//
+// Global versions (no organization) hold the template, rules and scenario,
+// organization versions are overrides that may only change the tone and rules
+type Aggregate struct {
+	eventsource.AggregateBase
+
+	orm.ModelUUID
+
+	OrganizationID uuid.UUID
+
+	// Revision numbers the versions of a prompt per organization, Version
+	// is the aggregate version used for concurrency
+	Name     string
+	Revision int
+
+	Template string
+	Rules    Lines `gorm:"type:jsonb"`
+	Scenario Lines `gorm:"type:jsonb"`
+	Tone     string
+
+	Active      bool
+	ActivatedAt *time.Time
+}
+
+func (*Aggregate) Topic() string                             { return "events.prompts" }
+func (*Aggregate) Repo(golly.Context) eventsource.Repository { return esbackend.PostgresRepository{} }
+func (*Aggregate) TableName() string                         { return "prompts" }
+
+func (prompt *Aggregate) GetID() string   { return prompt.ID.String() }
+func (prompt *Aggregate) SetID(id string) { prompt.ID, _ = uuid.Parse(id) }
+
+// IsOverride is true for organization level versions
+func (prompt Aggregate) IsOverride() bool { return prompt.OrganizationID != uuid.Nil }
+
+// Ref returns the reference recorded on anything this version generated
+func (prompt Aggregate) Ref() Ref {
+	return Ref{ID: prompt.ID, Revision: prompt.Revision}
+}
+
+func (prompt *Aggregate) Apply(ctx golly.Context, evt eventsource.Event) {
+	switch event := evt.Data.(type) {
+	case Created:
+		prompt.ID = event.ID
+		prompt.OrganizationID = event.OrganizationID
+		prompt.Name = event.Name
+		prompt.Revision = event.Revision
+		prompt.Template = event.Template
+		prompt.Rules = event.Rules
+		prompt.Scenario = event.Scenario
+		prompt.Tone = event.Tone
+
+		prompt.CreatedAt = evt.CreatedAt
+
+	case Activated:
+		prompt.Active = true
+		prompt.ActivatedAt = &evt.CreatedAt
+
+	case Deactivated:
+		prompt.Active = false
+	}
+	prompt.UpdatedAt = evt.CreatedAt
+}
+
+// Lines is a list of prompt lines stored as jsonb
+type Lines []string
+
+func (l Lines) Value() (driver.Value, error) {
+	if l == nil {
+		return []byte("[]"), nil
+	}
+	return json.Marshal(l)
+}
+
+func (l *Lines) Scan(value interface{}) error {
+	switch v := value.(type) {
+	case nil:
+		return nil
+	case []byte:
+		return json.Unmarshal(v, l)
+	case string:
+		return json.Unmarshal([]byte(v), l)
+	}
+	return fmt.Errorf("cannot scan %T into lines", value)
+}
+
+var _ eventsource.Aggregate = &Aggregate{}
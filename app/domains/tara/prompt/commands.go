@@ -0,0 +1,96 @@
+package prompt
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/golly-go/golly"
+	"github.com/golly-go/golly/errors"
+	"github.com/golly-go/plugins/eventsource"
+	"github.com/golly-go/plugins/orm"
+	"github.com/google/uuid"
+	"github.com/mitchrodrigues/talent-review-backend/app/utils/openai"
+)
+
+var (
+	ErrorNameRequired    = fmt.Errorf("prompt name is required")
+	ErrorOverrideLimited = fmt.Errorf("organizations can only override the tone and rules of a prompt")
+	ErrorEmptyOverride   = fmt.Errorf("an override must change the tone or the rules")
+)
+
+type Create struct {
+	OrganizationID uuid.UUID
+	Name           string
+
+	Template string
+	Rules    []string
+	Scenario []string
+	Tone     string
+}
+
+func (cmd Create) Validate(ctx golly.Context, aggregate eventsource.Aggregate) error {
+	if strings.TrimSpace(cmd.Name) == "" {
+		return errors.WrapUnprocessable(ErrorNameRequired)
+	}
+
+	if cmd.OrganizationID != uuid.Nil {
+		if cmd.Template != "" || len(cmd.Scenario) > 0 {
+			return errors.WrapUnprocessable(ErrorOverrideLimited)
+		}
+
+		if cmd.Tone == "" && len(cmd.Rules) == 0 {
+			return errors.WrapUnprocessable(ErrorEmptyOverride)
+		}
+	}
+
+	if cmd.Template != "" {
+		if err := openai.Template(cmd.Template).Validate(); err != nil {
+			return errors.WrapUnprocessable(fmt.Errorf("invalid template: %w", err))
+		}
+	}
+
+	return nil
+}
+
+func (cmd Create) Perform(ctx golly.Context, aggregate eventsource.Aggregate) error {
+	id, _ := uuid.NewV7()
+
+	var revision int
+
+	orm.DB(ctx).
+		Model(&Aggregate{}).
+		Where("organization_id = ? AND name = ?", cmd.OrganizationID, cmd.Name).
+		Select("COALESCE(MAX(revision), 0)").
+		Scan(&revision)
+
+	eventsource.Apply(ctx, aggregate, Created{
+		ID:             id,
+		OrganizationID: cmd.OrganizationID,
+		Name:           cmd.Name,
+		Revision:       revision + 1,
+		Template:       cmd.Template,
+		Rules:          golly.Filter(golly.Map(cmd.Rules, strings.TrimSpace), golly.NotEmptyStringFilter),
+		Scenario:       golly.Filter(golly.Map(cmd.Scenario, strings.TrimSpace), golly.NotEmptyStringFilter),
+		Tone:           strings.TrimSpace(cmd.Tone),
+	})
+
+	return nil
+}
+
+type Activate struct{}
+
+func (cmd Activate) Perform(ctx golly.Context, aggregate eventsource.Aggregate) error {
+	if !aggregate.(*Aggregate).Active {
+		eventsource.Apply(ctx, aggregate, Activated{})
+	}
+	return nil
+}
+
+type Deactivate struct{}
+
+func (cmd Deactivate) Perform(ctx golly.Context, aggregate eventsource.Aggregate) error {
+	if aggregate.(*Aggregate).Active {
+		eventsource.Apply(ctx, aggregate, Deactivated{})
+	}
+	return nil
+}
@@ -0,0 +1,107 @@
+package prompt
+
+import (
+	"context"
+	"testing"
+
+	"github.com/golly-go/golly"
+	"github.com/golly-go/plugins/orm"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCreateValidate(t *testing.T) {
+	orgID := uuid.New()
+
+	tests := []struct {
+		name      string
+		cmd       Create
+		expectErr bool
+	}{
+		{name: "global", cmd: Create{Name: "SummarizeFeedbackPrompt", Template: "Rules: {{ rules }}", Rules: []string{"rule"}}},
+		{name: "missing name", cmd: Create{Rules: []string{"rule"}}, expectErr: true},
+		{name: "invalid template", cmd: Create{Name: "SummarizeFeedbackPrompt", Template: "{% if rules %}"}, expectErr: true},
+		{name: "override tone", cmd: Create{OrganizationID: orgID, Name: "SummarizeFeedbackPrompt", Tone: "warm"}},
+		{name: "override rules", cmd: Create{OrganizationID: orgID, Name: "SummarizeFeedbackPrompt", Rules: []string{"rule"}}},
+		{name: "override template", cmd: Create{OrganizationID: orgID, Name: "SummarizeFeedbackPrompt", Template: "{{ fields }}"}, expectErr: true},
+		{name: "override scenario", cmd: Create{OrganizationID: orgID, Name: "SummarizeFeedbackPrompt", Scenario: []string{"scenario"}}, expectErr: true},
+		{name: "empty override", cmd: Create{OrganizationID: orgID, Name: "SummarizeFeedbackPrompt"}, expectErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.cmd.Validate(golly.Context{}, &Aggregate{})
+			if tt.expectErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestCreatePerform(t *testing.T) {
+	gctx := orm.CreateTestContext(golly.NewContext(context.TODO()), Aggregate{})
+
+	orgID := uuid.New()
+
+	orm.DB(gctx).Create(&Aggregate{ModelUUID: orm.ModelUUID{ID: uuid.New()}, Name: "SummarizeFeedbackPrompt", Revision: 1})
+	orm.DB(gctx).Create(&Aggregate{ModelUUID: orm.ModelUUID{ID: uuid.New()}, Name: "SummarizeFeedbackPrompt", Revision: 2})
+	orm.DB(gctx).Create(&Aggregate{ModelUUID: orm.ModelUUID{ID: uuid.New()}, Name: "FollowUpItemsPrompt", Revision: 7})
+
+	tests := []struct {
+		name     string
+		cmd      Create
+		expected Created
+	}{
+		{
+			name:     "next global version",
+			cmd:      Create{Name: "SummarizeFeedbackPrompt", Rules: []string{" rule ", ""}},
+			expected: Created{Name: "SummarizeFeedbackPrompt", Revision: 3, Rules: Lines{"rule"}, Scenario: Lines{}},
+		},
+		{
+			name:     "first override",
+			cmd:      Create{OrganizationID: orgID, Name: "SummarizeFeedbackPrompt", Tone: " warm "},
+			expected: Created{OrganizationID: orgID, Name: "SummarizeFeedbackPrompt", Revision: 1, Rules: Lines{}, Scenario: Lines{}, Tone: "warm"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			prompt := Aggregate{}
+
+			assert.NoError(t, tt.cmd.Perform(gctx, &prompt))
+
+			changes := prompt.Changes()
+			if assert.Len(t, changes, 1) {
+				created := changes[0].Data.(Created)
+				assert.NotEqual(t, uuid.Nil, created.ID)
+
+				created.ID = uuid.Nil
+				assert.Equal(t, tt.expected, created)
+			}
+		})
+	}
+}
+
+func TestActivate(t *testing.T) {
+	prompt := Aggregate{}
+
+	assert.NoError(t, Activate{}.Perform(golly.Context{}, &prompt))
+	assert.NoError(t, Activate{}.Perform(golly.Context{}, &prompt))
+
+	assert.Len(t, prompt.Changes(), 1)
+	assert.True(t, prompt.Active)
+
+	assert.NoError(t, Deactivate{}.Perform(golly.Context{}, &prompt))
+	assert.False(t, prompt.Active)
+}
+
+func TestRefString(t *testing.T) {
+	id := uuid.New()
+
+	assert.Equal(t, "builtin", Ref{}.String())
+	assert.Equal(t, "v3", Ref{ID: id, Revision: 3}.String())
+	assert.Equal(t, "v3+org.v2", Ref{ID: id, Revision: 3, OverrideID: uuid.New(), OverrideRevision: 2}.String())
+	assert.Equal(t, "builtin+org.v1", Ref{OverrideID: uuid.New(), OverrideRevision: 1}.String())
+}
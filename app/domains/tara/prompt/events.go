@@ -0,0 +1,18 @@
+package prompt
+
+import "github.com/google/uuid"
+
+type Created struct {
+	ID             uuid.UUID `json:"id"`
+	OrganizationID uuid.UUID `json:"organizationID"`
+	Name           string    `json:"name"`
+	Revision       int       `json:"revision"`
+	Template       string    `json:"template"`
+	Rules          Lines     `json:"rules"`
+	Scenario       Lines     `json:"scenario"`
+	Tone           string    `json:"tone"`
+}
+
+type Activated struct{}
+
+type Deactivated struct{}
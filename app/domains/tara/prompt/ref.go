@@ -0,0 +1,57 @@
+package prompt
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+
+	"github.com/google/uuid"
+)
+
+// Ref identifies the prompt versions that produced a generation, a nil
+// ID means the built in prompt compiled into the binary was used. The
+// revisions keep their version json keys so recorded refs still load
+type Ref struct {
+	ID       uuid.UUID `json:"id"`
+	Revision int       `json:"version"`
+
+	OverrideID       uuid.UUID `json:"overrideID,omitempty"`
+	OverrideRevision int       `json:"overrideVersion,omitempty"`
+}
+
+func (ref Ref) IsBuiltin() bool { return ref.ID == uuid.Nil }
+
+func (ref Ref) String() string {
+	ret := "builtin"
+	if !ref.IsBuiltin() {
+		ret = fmt.Sprintf("v%d", ref.Revision)
+	}
+
+	if ref.OverrideID != uuid.Nil {
+		ret += fmt.Sprintf("+org.v%d", ref.OverrideRevision)
+	}
+
+	return ret
+}
+
+// Refs maps prompt names to the version used, stored as jsonb
+type Refs map[string]Ref
+
+func (refs Refs) Value() (driver.Value, error) {
+	if refs == nil {
+		return []byte("{}"), nil
+	}
+	return json.Marshal(refs)
+}
+
+func (refs *Refs) Scan(value interface{}) error {
+	switch v := value.(type) {
+	case nil:
+		return nil
+	case []byte:
+		return json.Unmarshal(v, refs)
+	case string:
+		return json.Unmarshal([]byte(v), refs)
+	}
+	return fmt.Errorf("cannot scan %T into prompt refs", value)
+}
@@ -0,0 +1,279 @@
+package tara
+
+import (
+	"fmt"
+
+	"github.com/golly-go/golly"
+	"github.com/golly-go/golly/errors"
+	"github.com/golly-go/plugins/eventsource"
+	"github.com/golly-go/plugins/orm"
+	"github.com/google/uuid"
+	"github.com/mitchrodrigues/talent-review-backend/app/domains/tara/prompt"
+	"github.com/mitchrodrigues/talent-review-backend/app/utils/identity"
+	"github.com/mitchrodrigues/talent-review-backend/app/utils/openai"
+)
+
+// Prompt is a stored version of a tara prompt
+type Prompt struct {
+	prompt.Aggregate
+}
+
+func (Prompt) TableName() string { return "prompts" }
+
+// PromptDefaults are the rules and scenario compiled into the binary,
+// they are used until a version is stored and seed the first version
+type PromptDefaults struct {
+	Rules    []string
+	Scenario []string
+}
+
+var (
+	DefaultPrompts = map[string]PromptDefaults{
+		"SummarizeFeedbackPrompt":      summarizeFeedbackDefaults,
+		"FollowUpItemsPrompt":          followUpItemsDefaults,
+		"AskTaraPrompt":                askTaraDefaults,
+		"WritingCoachPrompt":           writingCoachDefaults,
+		"FeedbackAnalysisPrompt":       feedbackAnalysisDefaults,
+		"ReviewDraftPrompt":            reviewDraftDefaults,
+		"PromotionJustificationPrompt": promotionJustificationDefaults,
+	}
+)
+
+// PromptVersion is the resolved prompt for an organization, the active
+// global version with the organization override applied on top
+type PromptVersion struct {
+	Name string
+	Ref  prompt.Ref
+
+	Template openai.Template
+	Rules    []string
+	Scenario []string
+	Tone     string
+}
+
+func (v PromptVersion) RulesOr(defaults []string) []string {
+	rules := append([]string{}, golly.Coalesce(v.Rules, defaults)...)
+
+	if v.Tone != "" {
+		rules = append(rules, fmt.Sprintf("Use a %s tone.", v.Tone))
+	}
+
+	return rules
+}
+
+func (v PromptVersion) ScenarioOr(defaults []string) []string {
+	return golly.Coalesce(v.Scenario, defaults)
+}
+
+func (v PromptVersion) TemplateOr(template openai.Template) openai.Template {
+	return golly.Coalesce(v.Template, template)
+}
+
+func (v *PromptVersion) apply(p prompt.Aggregate) {
+	if p.IsOverride() {
+		v.Ref.OverrideID, v.Ref.OverrideRevision = p.ID, p.Revision
+	} else {
+		v.Ref.ID, v.Ref.Revision = p.ID, p.Revision
+		v.Template = openai.Template(p.Template)
+		v.Scenario = p.Scenario
+	}
+
+	if len(p.Rules) > 0 {
+		v.Rules = p.Rules
+	}
+
+	if p.Tone != "" {
+		v.Tone = p.Tone
+	}
+}
+
+// Versioned is embedded in prompts that are stored in the registry
+type Versioned struct {
+	Version PromptVersion `json:"-" ai:"-"`
+}
+
+func (v *Versioned) UsePromptVersion(version PromptVersion) { v.Version = version }
+func (v Versioned) PromptVersion() PromptVersion            { return v.Version }
+
+// PromptVersionKey keys cached responses on the exact versions used
+func (v Versioned) PromptVersionKey() string {
+	return fmt.Sprintf("%s@%s+%s", v.Version.Name, v.Version.Ref.ID, v.Version.Ref.OverrideID)
+}
+
+func (v Versioned) PromptString(gctx golly.Context, p openai.Prompt) (string, error) {
+	return v.Version.TemplateOr(openai.MasterPrompt).Generate(openai.Bindings(gctx, p))
+}
+
+type VersionedPrompt interface {
+	UsePromptVersion(PromptVersion)
+	PromptVersion() PromptVersion
+}
+
+// ResolvePromptVersion returns the active version of the named prompt for
+// the organization on the context, falling back to the built in prompt
+func ResolvePromptVersion(gctx golly.Context, name string) PromptVersion {
+	version := PromptVersion{Name: name}
+
+	if p, err := FindActivePrompt(gctx, uuid.Nil, name); err == nil {
+		version.apply(p.Aggregate)
+	}
+
+	if organizationID := identity.FromContext(gctx).OrganizationID; organizationID != uuid.Nil {
+		if p, err := FindActivePrompt(gctx, organizationID, name); err == nil {
+			version.apply(p.Aggregate)
+		}
+	}
+
+	return version
+}
+
+// FindPromptVersion rebuilds the exact version a generation recorded so it
+// can be run again, active or not
+func FindPromptVersion(gctx golly.Context, name string, ref prompt.Ref) (PromptVersion, error) {
+	version := PromptVersion{Name: name}
+
+	for _, id := range []uuid.UUID{ref.ID, ref.OverrideID} {
+		if id == uuid.Nil {
+			continue
+		}
+
+		p, err := FindPromptByID(gctx, id)
+		if err != nil {
+			return version, err
+		}
+
+		version.apply(p.Aggregate)
+	}
+
+	return version, nil
+}
+
+func FindActivePrompt(gctx golly.Context, organizationID uuid.UUID, name string) (Prompt, error) {
+	var p Prompt
+
+	err := orm.DB(gctx).
+		Model(&p).
+		Where("organization_id = ? AND name = ? AND active = ?", organizationID, name, true).
+		Order("revision DESC").
+		First(&p).
+		Error
+
+	return p, errors.WrapNotFound(err)
+}
+
+func FindPromptByID(gctx golly.Context, id uuid.UUID) (Prompt, error) {
+	var p Prompt
+
+	err := orm.DB(gctx).
+		Model(&p).
+		Where("id = ?", id).
+		First(&p).
+		Error
+
+	return p, errors.WrapNotFound(err)
+}
+
+// FindPromptVersions lists the global versions along with the overrides
+// of the organization, newest first
+func FindPromptVersions(gctx golly.Context, organizationID uuid.UUID, name string) ([]Prompt, error) {
+	var prompts []Prompt
+
+	query := orm.DB(gctx).
+		Model(&prompts).
+		Where("organization_id IN ?", uuid.UUIDs{uuid.Nil, organizationID})
+
+	if name != "" {
+		query = query.Where("name = ?", name)
+	}
+
+	err := query.
+		Order("name, organization_id, revision DESC").
+		Find(&prompts).
+		Error
+
+	return prompts, errors.WrapGeneric(err)
+}
+
+// CreatePromptVersion stores a new version and optionally activates it
+func CreatePromptVersion(gctx golly.Context, cmd prompt.Create, activate bool, metadata eventsource.Metadata) (Prompt, error) {
+	var p Prompt
+
+	if err := eventsource.Call(gctx, &p.Aggregate, cmd, metadata); err != nil {
+		return p, err
+	}
+
+	if activate {
+		return p, ActivatePrompt(gctx, &p, metadata)
+	}
+
+	return p, nil
+}
+
+// ActivatePrompt makes the version the active one for its organization
+// and name, deactivating the previous one
+func ActivatePrompt(gctx golly.Context, p *Prompt, metadata eventsource.Metadata) error {
+	if err := DeactivatePrompt(gctx, p.OrganizationID, p.Name, metadata); err != nil {
+		return err
+	}
+
+	return eventsource.Call(gctx, &p.Aggregate, prompt.Activate{}, metadata)
+}
+
+// DeactivatePrompt deactivates the active version for the organization,
+// for an override this resets the organization to the global version
+func DeactivatePrompt(gctx golly.Context, organizationID uuid.UUID, name string, metadata eventsource.Metadata) error {
+	var active []Prompt
+
+	err := orm.DB(gctx).
+		Model(&active).
+		Where("organization_id = ? AND name = ? AND active = ?", organizationID, name, true).
+		Find(&active).
+		Error
+
+	if err != nil {
+		return errors.WrapGeneric(err)
+	}
+
+	for pos := range active {
+		if err := eventsource.Call(gctx, &active[pos].Aggregate, prompt.Deactivate{}, metadata); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// SeedPrompts stores the built in prompts as the first active version of
+// every prompt that has no versions yet
+func SeedPrompts(gctx golly.Context) error {
+	for name, defaults := range DefaultPrompts {
+		var count int64
+
+		err := orm.DB(gctx).
+			Model(&Prompt{}).
+			Where("organization_id = ? AND name = ?", uuid.Nil, name).
+			Count(&count).
+			Error
+
+		if err != nil {
+			return errors.WrapGeneric(err)
+		}
+
+		if count > 0 {
+			continue
+		}
+
+		_, err = CreatePromptVersion(gctx, prompt.Create{
+			Name:     name,
+			Template: string(openai.MasterPrompt),
+			Rules:    defaults.Rules,
+			Scenario: defaults.Scenario,
+		}, true, eventsource.Metadata{})
+
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,175 @@
+package tara
+
+import (
+	"context"
+	"testing"
+
+	"github.com/golly-go/golly"
+	"github.com/golly-go/plugins/orm"
+	"github.com/google/uuid"
+	"github.com/mitchrodrigues/talent-review-backend/app/domains/tara/prompt"
+	"github.com/mitchrodrigues/talent-review-backend/app/utils/identity"
+	"github.com/mitchrodrigues/talent-review-backend/app/utils/openai"
+	"github.com/stretchr/testify/assert"
+)
+
+func newPromptTestContext(prompts ...prompt.Aggregate) (identity.Identity, golly.Context) {
+	gctx := orm.CreateTestContext(golly.NewContext(context.TODO()), Prompt{})
+
+	ident, gctx := identity.NewTestIdentity(gctx)
+
+	for _, p := range prompts {
+		if p.OrganizationID == testOrganization {
+			p.OrganizationID = ident.OrganizationID
+		}
+		orm.DB(gctx).Create(&Prompt{Aggregate: p})
+	}
+
+	return ident, gctx
+}
+
+var (
+	// testOrganization is swapped for the organization of the test identity
+	testOrganization = uuid.MustParse("00000000-0000-0000-0000-00000000000f")
+
+	globalV1 = prompt.Aggregate{
+		ModelUUID: orm.ModelUUID{ID: uuid.New()},
+		Name:      "SummarizeFeedbackPrompt",
+		Revision:  1,
+		Template:  string(openai.MasterPrompt),
+		Rules:     prompt.Lines{"v1 rule"},
+		Scenario:  prompt.Lines{"v1 scenario"},
+	}
+
+	globalV2 = prompt.Aggregate{
+		ModelUUID: orm.ModelUUID{ID: uuid.New()},
+		Name:      "SummarizeFeedbackPrompt",
+		Revision:  2,
+		Active:    true,
+		Template:  "Scenario: {% for field in scenario %}{{ field }}{% endfor %}",
+		Rules:     prompt.Lines{"v2 rule"},
+		Scenario:  prompt.Lines{"v2 scenario"},
+	}
+
+	toneOverride = prompt.Aggregate{
+		ModelUUID:      orm.ModelUUID{ID: uuid.New()},
+		OrganizationID: testOrganization,
+		Name:           "SummarizeFeedbackPrompt",
+		Revision:       1,
+		Active:         true,
+		Tone:           "warm",
+	}
+
+	rulesOverride = prompt.Aggregate{
+		ModelUUID:      orm.ModelUUID{ID: uuid.New()},
+		OrganizationID: testOrganization,
+		Name:           "SummarizeFeedbackPrompt",
+		Revision:       2,
+		Rules:          prompt.Lines{"org rule"},
+	}
+
+	otherOrgOverride = prompt.Aggregate{
+		ModelUUID:      orm.ModelUUID{ID: uuid.New()},
+		OrganizationID: uuid.New(),
+		Name:           "SummarizeFeedbackPrompt",
+		Revision:       1,
+		Active:         true,
+		Rules:          prompt.Lines{"other org rule"},
+	}
+)
+
+func TestResolvePromptVersion(t *testing.T) {
+	tests := []struct {
+		name     string
+		prompts  []prompt.Aggregate
+		rules    []string
+		scenario []string
+		ref      string
+	}{
+		{
+			name:     "builtin",
+			rules:    summarizeFeedbackDefaults.Rules,
+			scenario: summarizeFeedbackDefaults.Scenario,
+			ref:      "builtin",
+		},
+		{
+			name:     "active global version",
+			prompts:  []prompt.Aggregate{globalV1, globalV2},
+			rules:    []string{"v2 rule"},
+			scenario: []string{"v2 scenario"},
+			ref:      "v2",
+		},
+		{
+			name:     "organization tone override",
+			prompts:  []prompt.Aggregate{globalV1, globalV2, toneOverride, rulesOverride, otherOrgOverride},
+			rules:    []string{"v2 rule", "Use a warm tone."},
+			scenario: []string{"v2 scenario"},
+			ref:      "v2+org.v1",
+		},
+		{
+			name:     "other organizations are ignored",
+			prompts:  []prompt.Aggregate{globalV2, otherOrgOverride},
+			rules:    []string{"v2 rule"},
+			scenario: []string{"v2 scenario"},
+			ref:      "v2",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, gctx := newPromptTestContext(tt.prompts...)
+
+			p := NewSummaryFeedbackPrompt(SummarizeFeedbackInput{})
+			p.UsePromptVersion(ResolvePromptVersion(gctx, "SummarizeFeedbackPrompt"))
+
+			assert.Equal(t, tt.rules, p.Rules(gctx))
+			assert.Equal(t, tt.scenario, p.Scenario(gctx))
+			assert.Equal(t, tt.ref, p.PromptVersion().Ref.String())
+		})
+	}
+}
+
+func TestFindPromptVersion(t *testing.T) {
+	_, gctx := newPromptTestContext(globalV1, globalV2, toneOverride, rulesOverride)
+
+	version, err := FindPromptVersion(gctx, "SummarizeFeedbackPrompt", prompt.Ref{ID: globalV1.ID, OverrideID: rulesOverride.ID})
+	assert.NoError(t, err)
+
+	assert.Equal(t, "v1+org.v2", version.Ref.String())
+	assert.Equal(t, []string{"org rule"}, version.RulesOr(nil))
+	assert.Equal(t, []string{"v1 scenario"}, version.ScenarioOr(nil))
+
+	_, err = FindPromptVersion(gctx, "SummarizeFeedbackPrompt", prompt.Ref{ID: uuid.New()})
+	assert.Error(t, err)
+}
+
+func TestVersionedPromptString(t *testing.T) {
+	_, gctx := newPromptTestContext(globalV2)
+
+	p := NewSummaryFeedbackPrompt(SummarizeFeedbackInput{})
+
+	builtin, err := p.PromptString(gctx, p)
+	assert.NoError(t, err)
+	assert.Contains(t, builtin, "Result must be valid JSON")
+
+	p.UsePromptVersion(ResolvePromptVersion(gctx, "SummarizeFeedbackPrompt"))
+
+	str, err := p.PromptString(gctx, p)
+	assert.NoError(t, err)
+	assert.Equal(t, "Scenario: v2 scenario", str)
+}
+
+func TestGenerateRecordsPromptVersion(t *testing.T) {
+	_, gctx := newPromptTestContext(globalV1, globalV2, toneOverride)
+	gctx = openai.UseProvider(gctx, openai.NewFixtureProvider(nil))
+
+	p := NewSummaryFeedbackPrompt(SummarizeFeedbackInput{})
+	assert.NoError(t, Generate(gctx, p))
+	assert.Equal(t, prompt.Ref{ID: globalV2.ID, Revision: 2, OverrideID: toneOverride.ID, OverrideRevision: 1}, p.Version.Ref)
+
+	pinned := NewSummaryFeedbackPrompt(SummarizeFeedbackInput{})
+	assert.NoError(t, GenerateWithOptions(gctx, GenerateOptions{
+		Prompts: prompt.Refs{"SummarizeFeedbackPrompt": {ID: globalV1.ID, Revision: 1}},
+	}, pinned))
+	assert.Equal(t, prompt.Ref{ID: globalV1.ID, Revision: 1}, pinned.Version.Ref)
+}
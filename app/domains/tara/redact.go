@@ -0,0 +1,254 @@
+package tara
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/golly-go/golly"
+	"github.com/mitchrodrigues/talent-review-backend/app/utils/openai"
+)
+
+type PIIKind string
+
+const (
+	PIIPerson PIIKind = "PERSON"
+	PIIEmail  PIIKind = "EMAIL"
+	PIIPhone  PIIKind = "PHONE"
+
+	minNamePartLength = 3
+	minPhoneDigits    = 9
+	maxPhoneDigits    = 15
+)
+
+var (
+	emailPattern = regexp.MustCompile(`(?i)[a-z0-9._%+\-]+@[a-z0-9.\-]+\.[a-z]{2,}`)
+	phonePattern = regexp.MustCompile(`\+?(?:\d{1,3}[\s.\-]?)?(?:\(\d{2,4}\)|\d{2,4})[\s.\-]?\d{3,4}[\s.\-]?\d{3,4}`)
+	tokenPattern = regexp.MustCompile(`\[?\b(PERSON|EMAIL|PHONE)_(\d+)\b\]?`)
+)
+
+// KnownPerson is someone in the organization directory, their names
+// are redacted even when they do not look like PII on their own
+type KnownPerson struct {
+	Name  string
+	Email string
+}
+
+// Redactor swaps PII for stable tokens ([PERSON_1], [EMAIL_2], ...) and
+// can put the originals back into whatever the LLM generates. The same
+// original always maps to the same token for the life of the redactor
+type Redactor struct {
+	names  *regexp.Regexp
+	emails []string
+
+	tokens    map[string]string
+	originals map[string]string
+	counts    map[PIIKind]int
+}
+
+func NewRedactor(people ...KnownPerson) *Redactor {
+	r := &Redactor{
+		tokens:    map[string]string{},
+		originals: map[string]string{},
+		counts:    map[PIIKind]int{},
+	}
+
+	fullNames := map[string]bool{}
+	nameParts := map[string]bool{}
+
+	for _, person := range people {
+		if email := strings.TrimSpace(person.Email); email != "" {
+			r.emails = append(r.emails, email)
+		}
+
+		parts := strings.Fields(person.Name)
+		if len(parts) > 1 {
+			fullNames[strings.Join(parts, " ")] = true
+		}
+
+		for _, part := range parts {
+			if len([]rune(part)) >= minNamePartLength {
+				nameParts[part] = true
+			}
+		}
+	}
+
+	// Everything goes into one alternation so tokens are numbered in reading
+	// order, longest first so "Mary Jane Smith" wins over "Mary". Single names
+	// are matched case sensitive so "will" does not match Will
+	alternatives := []string{}
+
+	for _, name := range sortedByLength(fullNames) {
+		pattern := strings.Join(golly.Map(strings.Fields(name), regexp.QuoteMeta), `\s+`)
+		alternatives = append(alternatives, `(?i:\b`+pattern+`\b)`)
+	}
+
+	for _, part := range sortedByLength(nameParts) {
+		alternatives = append(alternatives, `\b`+regexp.QuoteMeta(part)+`\b`)
+	}
+
+	if len(alternatives) > 0 {
+		r.names = regexp.MustCompile(strings.Join(alternatives, "|"))
+	}
+
+	return r
+}
+
+// Redact replaces emails, phone numbers and known names with tokens
+func (r *Redactor) Redact(text string) string {
+	if r == nil || text == "" {
+		return text
+	}
+
+	for _, email := range r.emails {
+		text = replaceFold(text, email, func(match string) string { return r.token(PIIEmail, match) })
+	}
+
+	text = emailPattern.ReplaceAllStringFunc(text, func(match string) string {
+		return r.token(PIIEmail, match)
+	})
+
+	text = phonePattern.ReplaceAllStringFunc(text, func(match string) string {
+		if digits := countDigits(match); digits < minPhoneDigits || digits > maxPhoneDigits {
+			return match
+		}
+		return r.token(PIIPhone, match)
+	})
+
+	if r.names != nil {
+		text = r.names.ReplaceAllStringFunc(text, func(match string) string {
+			return r.token(PIIPerson, match)
+		})
+	}
+
+	return text
+}
+
+// Restore puts the originals back, tokens the LLM mangled to PERSON_1
+// (without the brackets) are restored as well
+func (r *Redactor) Restore(text string) string {
+	return r.restore(text, func(s string) string { return s })
+}
+
+// RestoreJSON is Restore for JSON documents, originals are escaped so
+// the document stays valid
+func (r *Redactor) RestoreJSON(text string) string {
+	return r.restore(text, func(s string) string {
+		b, _ := json.Marshal(s)
+		return string(b[1 : len(b)-1])
+	})
+}
+
+func (r *Redactor) restore(text string, escape func(string) string) string {
+	if r == nil || len(r.tokens) == 0 {
+		return text
+	}
+
+	return tokenPattern.ReplaceAllStringFunc(text, func(match string) string {
+		parts := tokenPattern.FindStringSubmatch(match)
+
+		original, ok := r.tokens[tokenName(PIIKind(parts[1]), parts[2])]
+		if !ok {
+			return match
+		}
+
+		return escape(original)
+	})
+}
+
+// Redacted returns the number of distinct values that were tokenized
+func (r *Redactor) Redacted() int {
+	if r == nil {
+		return 0
+	}
+	return len(r.tokens)
+}
+
+func (r *Redactor) token(kind PIIKind, original string) string {
+	key := string(kind) + ":" + strings.ToLower(original)
+
+	if token, ok := r.originals[key]; ok {
+		return token
+	}
+
+	r.counts[kind]++
+
+	token := tokenName(kind, strconv.Itoa(r.counts[kind]))
+
+	r.originals[key] = token
+	r.tokens[token] = original
+
+	return token
+}
+
+func tokenName(kind PIIKind, n string) string {
+	return fmt.Sprintf("[%s_%s]", kind, n)
+}
+
+func replaceFold(text, value string, fn func(string) string) string {
+	pattern := regexp.MustCompile(`(?i)` + regexp.QuoteMeta(value))
+	return pattern.ReplaceAllStringFunc(text, fn)
+}
+
+func countDigits(s string) (count int) {
+	for _, r := range s {
+		if unicode.IsDigit(r) {
+			count++
+		}
+	}
+	return count
+}
+
+func sortedByLength(set map[string]bool) []string {
+	ret := make([]string, 0, len(set))
+	for value := range set {
+		ret = append(ret, value)
+	}
+
+	sort.Slice(ret, func(i, j int) bool {
+		if len(ret[i]) == len(ret[j]) {
+			return ret[i] < ret[j]
+		}
+		return len(ret[i]) > len(ret[j])
+	})
+
+	return ret
+}
+
+// RedactingProvider redacts every message before it leaves the process
+// and restores the originals in the completion
+type RedactingProvider struct {
+	openai.Provider
+
+	Redactor *Redactor
+}
+
+func (rp RedactingProvider) Completions(gctx golly.Context, payload openai.CompletionPayload) (openai.CompletionResponse, error) {
+	messages := make([]openai.Message, len(payload.Messages))
+	for pos, message := range payload.Messages {
+		message.Content = rp.Redactor.Redact(message.Content)
+		messages[pos] = message
+	}
+	payload.Messages = messages
+
+	resp, err := rp.Provider.Completions(gctx, payload)
+	if err != nil {
+		return resp, err
+	}
+
+	for pos := range resp.Choices {
+		resp.Choices[pos].Message.Content = rp.Redactor.RestoreJSON(resp.Choices[pos].Message.Content)
+	}
+
+	return resp, nil
+}
+
+func (rp RedactingProvider) Embeddings(gctx golly.Context, text string) (openai.EmbeddingResponse, error) {
+	return rp.Provider.Embeddings(gctx, rp.Redactor.Redact(text))
+}
+
+var _ openai.Provider = RedactingProvider{}
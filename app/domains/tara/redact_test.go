@@ -0,0 +1,100 @@
+package tara
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/golly-go/golly"
+	"github.com/mitchrodrigues/talent-review-backend/app/utils/openai"
+	"github.com/stretchr/testify/assert"
+)
+
+type redactionFixture struct {
+	Description string        `json:"description"`
+	People      []KnownPerson `json:"people"`
+	Input       string        `json:"input"`
+	Redacted    string        `json:"redacted"`
+}
+
+func TestRedactor_Fixtures(t *testing.T) {
+	files, err := filepath.Glob("testdata/redaction/*.json")
+	assert.NoError(t, err)
+	assert.NotEmpty(t, files)
+
+	for _, file := range files {
+		t.Run(filepath.Base(file), func(t *testing.T) {
+			b, err := os.ReadFile(file)
+			assert.NoError(t, err)
+
+			var fixture redactionFixture
+			assert.NoError(t, json.Unmarshal(b, &fixture))
+
+			redactor := NewRedactor(fixture.People...)
+
+			redacted := redactor.Redact(fixture.Input)
+			assert.Equal(t, fixture.Redacted, redacted, fixture.Description)
+
+			// restoring only puts back the first spelling of a repeated value
+			// so compare case insensitively
+			assert.True(t, strings.EqualFold(fixture.Input, redactor.Restore(redacted)), redactor.Restore(redacted))
+		})
+	}
+}
+
+func TestRedactor_Restore(t *testing.T) {
+	redactor := NewRedactor(KnownPerson{Name: "Dana \"DJ\" Jones"})
+
+	redacted := redactor.Redact("Dana \"DJ\" Jones and dana@acme.test")
+	assert.Equal(t, "[PERSON_1] and [EMAIL_1]", redacted)
+
+	// the LLM sometimes drops the brackets
+	assert.Equal(t, "Dana \"DJ\" Jones owns it", redactor.Restore("PERSON_1 owns it"))
+
+	// unknown tokens are left alone
+	assert.Equal(t, "[PERSON_9]", redactor.Restore("[PERSON_9]"))
+
+	// restored JSON stays valid
+	restored := redactor.RestoreJSON(`{"summary": "[PERSON_1] is great"}`)
+	assert.True(t, json.Valid([]byte(restored)), restored)
+}
+
+type recordingProvider struct {
+	openai.FixtureProvider
+
+	payload openai.CompletionPayload
+}
+
+func (rp *recordingProvider) Completions(gctx golly.Context, payload openai.CompletionPayload) (openai.CompletionResponse, error) {
+	rp.payload = payload
+
+	return openai.CompletionResponse{
+		Choices: []openai.Choice{{Message: openai.Message{Content: `{"summary": "[PERSON_1] can be reached at [EMAIL_1]"}`}}},
+	}, nil
+}
+
+func TestRedactingProvider(t *testing.T) {
+	gctx := golly.NewContext(context.Background())
+
+	provider := &recordingProvider{}
+
+	prompt := NewSummaryFeedbackPrompt(SummarizeFeedbackInput{
+		Strengths: "Jordan Park shipped the release, email jordan@acme.test",
+	})
+
+	_, err := openai.Completion(gctx, RedactingProvider{
+		Provider: provider,
+		Redactor: NewRedactor(KnownPerson{Name: "Jordan Park", Email: "jordan@acme.test"}),
+	}, prompt)
+	assert.NoError(t, err)
+
+	for _, message := range provider.payload.Messages {
+		assert.NotContains(t, message.Content, "Jordan")
+		assert.NotContains(t, message.Content, "jordan@acme.test")
+	}
+
+	assert.Equal(t, "Jordan Park can be reached at jordan@acme.test", prompt.Summary)
+}
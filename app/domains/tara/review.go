@@ -0,0 +1,176 @@
+package tara
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/golly-go/golly"
+	"github.com/google/uuid"
+	"github.com/mitchrodrigues/talent-review-backend/app/domains/tara/prompt"
+	"github.com/mitchrodrigues/talent-review-backend/app/utils/openai"
+)
+
+var (
+	// DefaultReviewCompetencies are drafted when the role of the employee
+	// has no competencies of its own
+	DefaultReviewCompetencies = []string{
+		"Impact",
+		"Execution",
+		"Collaboration",
+		"Communication",
+		"Growth",
+	}
+
+	reviewDraftDefaults = PromptDefaults{
+		Rules: []string{
+			"Write one section for each competency given, in the same order, and use the competency name exactly as given.",
+			"Base every statement on the sources, do not use outside knowledge or assumptions.",
+			"List the id of every source a section is based on in its citations.",
+			"When the sources say nothing about a competency write that there was not enough feedback to assess it and leave its citations empty.",
+			"Measure the employee against the expectations of their role and level, not against their peers.",
+			"Suggest an overall rating from 1 (does not meet expectations) to 5 (far exceeds expectations) and explain it in rating_rationale.",
+			"Acknowledge progress or regression since the prior reviews when there are any.",
+			"Refer to reviewers generally (for example \"a peer noted\"), never guess who wrote a piece of feedback.",
+			"Write to the employee in the second person using simple, concise, and professional wording.",
+		},
+		Scenario: []string{
+			"You are helping a manager write the formal performance review of an employee at the end of a review cycle.",
+			"Sources are feedback excerpts and feedback summaries about the employee, each labelled with its id and the date it was written.",
+			"The manager will edit your draft before it is finalized.",
+		},
+	}
+)
+
+// PriorReview is a finalized review the draft can build on
+type PriorReview struct {
+	Date    time.Time
+	Rating  int
+	Summary string
+}
+
+func (review PriorReview) String() string {
+	return fmt.Sprintf("(%s, rating %d) %s", review.Date.Format(time.DateOnly), review.Rating, review.Summary)
+}
+
+type ReviewDraftInput struct {
+	Employee string
+
+	// Role describes the title, level and track of the employee,
+	// expectations are what that level is expected to demonstrate
+	Role         string
+	Expectations []string
+
+	Competencies []string
+	Sources      []Source
+	PriorReviews []PriorReview
+}
+
+type ReviewSection struct {
+	Competency string   `json:"competency" ai:"string name of the competency"`
+	Content    string   `json:"content" ai:"string assessment of the employee for the competency"`
+	Citations  []string `json:"citations" ai:"string ids of the sources the section is based on"`
+}
+
+type ReviewDraftPrompt struct {
+	openai.CompletionPromptBase `json:"-"`
+	ReviewDraftInput            `json:"-"`
+	Versioned                   `json:"-"`
+
+	Summary         string          `json:"summary" ai:"string overall summary of the review period"`
+	Sections        []ReviewSection `json:"sections" ai:"one section per competency" max:"8"`
+	Rating          int             `json:"rating" ai:"integer suggested overall rating" min:"1" max:"5"`
+	RatingRationale string          `json:"rating_rationale" ai:"string why the rating is suggested"`
+}
+
+func (prompt ReviewDraftPrompt) Context(gctx golly.Context) openai.AIContexts {
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "Employee: %s\nRole: %s\n", prompt.Employee, prompt.Role)
+
+	if len(prompt.Expectations) > 0 {
+		fmt.Fprintf(&b, "Expectations:\n%s\n", strings.Join(prompt.Expectations, "\n"))
+	}
+
+	fmt.Fprintf(&b, "Competencies: %s\n", strings.Join(prompt.Competencies, ", "))
+
+	if len(prompt.PriorReviews) > 0 {
+		reviews := golly.Map(prompt.PriorReviews, func(review PriorReview) string { return review.String() })
+		fmt.Fprintf(&b, "Prior reviews:\n%s\n", strings.Join(reviews, "\n"))
+	}
+
+	sources := golly.Map(prompt.Sources, func(source Source) string { return source.String() })
+	fmt.Fprintf(&b, "Sources:\n%s", strings.Join(sources, "\n"))
+
+	return openai.AIContexts{
+		openai.NewDefaultContentRole(openai.RoleUser, b.String()),
+	}
+}
+
+func (prompt ReviewDraftPrompt) Rules(gctx golly.Context) []string {
+	return prompt.Version.RulesOr(reviewDraftDefaults.Rules)
+}
+
+func (prompt ReviewDraftPrompt) Scenario(gctx golly.Context) []string {
+	return prompt.Version.ScenarioOr(reviewDraftDefaults.Scenario)
+}
+
+func NewReviewDraftPrompt(input ReviewDraftInput) *ReviewDraftPrompt {
+	return &ReviewDraftPrompt{ReviewDraftInput: input}
+}
+
+// DraftedSection is a competency section of a drafted review, citations
+// are limited to the sources the draft was given
+type DraftedSection struct {
+	Competency string      `json:"competency"`
+	Content    string      `json:"content"`
+	Citations  []uuid.UUID `json:"citations"`
+}
+
+// ReviewDraft is the review tara drafted, the manager revises it
+type ReviewDraft struct {
+	Summary  string           `json:"summary"`
+	Sections []DraftedSection `json:"sections"`
+
+	Rating          int    `json:"rating"`
+	RatingRationale string `json:"ratingRationale"`
+
+	PromptVersions prompt.Refs `json:"promptVersions"`
+}
+
+// DraftReview drafts the performance review of an employee from their
+// feedback, summaries and prior reviews with a section per competency
+func DraftReview(gctx golly.Context, opts GenerateOptions, input ReviewDraftInput) (ReviewDraft, error) {
+	result := ReviewDraft{Sections: []DraftedSection{}, PromptVersions: prompt.Refs{}}
+
+	if len(input.Competencies) == 0 {
+		input.Competencies = DefaultReviewCompetencies
+	}
+
+	draftPrompt := NewReviewDraftPrompt(input)
+
+	if err := GenerateWithOptions(gctx, opts, draftPrompt); err != nil {
+		return result, err
+	}
+
+	for _, section := range draftPrompt.Sections {
+		section.Competency = strings.TrimSpace(section.Competency)
+
+		if section.Competency == "" {
+			continue
+		}
+
+		result.Sections = append(result.Sections, DraftedSection{
+			Competency: section.Competency,
+			Content:    strings.TrimSpace(section.Content),
+			Citations:  validCitations(section.Citations, input.Sources),
+		})
+	}
+
+	result.Summary = strings.TrimSpace(draftPrompt.Summary)
+	result.Rating = draftPrompt.Rating
+	result.RatingRationale = strings.TrimSpace(draftPrompt.RatingRationale)
+	result.PromptVersions[draftPrompt.Version.Name] = draftPrompt.Version.Ref
+
+	return result, nil
+}
@@ -0,0 +1,58 @@
+package tara
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/golly-go/golly"
+	"github.com/google/uuid"
+	"github.com/mitchrodrigues/talent-review-backend/app/utils/openai"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDraftReview(t *testing.T) {
+	_, gctx := newPromptTestContext()
+
+	known := uuid.New()
+
+	provider := &capturingProvider{FixtureProvider: openai.FixtureProvider{Default: fmt.Sprintf(`{
+		"summary": " A strong half. ",
+		"sections": [
+			{"competency":"Impact","content":"You shipped the planner.","citations":["[%s]","%s"]},
+			{"competency":" ","content":"dropped","citations":[]}
+		],
+		"rating": 4,
+		"rating_rationale": "Exceeded the bar."
+	}`, known, uuid.New())}}
+
+	draft, err := DraftReview(openai.UseProvider(gctx, provider), GenerateOptions{}, ReviewDraftInput{
+		Employee:     "Jane",
+		Role:         "Engineer, level 3, IC",
+		Expectations: []string{"Owns features end to end."},
+		Sources: []Source{
+			{ID: known, Kind: "feedback", Section: "strengths", Date: time.Now(), Content: "Shipped the planner."},
+		},
+		PriorReviews: []PriorReview{{Date: time.Now(), Rating: 3, Summary: "Solid year."}},
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, "A strong half.", draft.Summary)
+	assert.Equal(t, 4, draft.Rating)
+	assert.Equal(t, "Exceeded the bar.", draft.RatingRationale)
+	assert.Contains(t, draft.PromptVersions, "ReviewDraftPrompt")
+
+	if assert.Len(t, draft.Sections, 1) {
+		assert.Equal(t, "Impact", draft.Sections[0].Competency)
+		assert.Equal(t, []uuid.UUID{known}, draft.Sections[0].Citations)
+	}
+
+	if assert.Len(t, provider.payloads, 1) {
+		contents := golly.Map(provider.payloads[0].Messages, func(m openai.Message) string { return m.Content })
+
+		// default competencies are used when none are given
+		assert.NotEqual(t, -1, indexContaining(contents, "Competencies: Impact, Execution, Collaboration"))
+		assert.NotEqual(t, -1, indexContaining(contents, "Owns features end to end."))
+		assert.NotEqual(t, -1, indexContaining(contents, "rating 3) Solid year."))
+	}
+}
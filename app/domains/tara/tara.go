@@ -1,18 +1,123 @@
 package tara
 
 import (
+	"fmt"
+
 	"github.com/golly-go/golly"
+	"github.com/golly-go/golly/errors"
+	"github.com/mitchrodrigues/talent-review-backend/app/domains/tara/prompt"
 	"github.com/mitchrodrigues/talent-review-backend/app/utils/openai"
 )
 
+type GenerateOptions struct {
+	// Redactor when set tokenizes PII before anything is sent to the LLM
+	Redactor *Redactor
+
+	// Prompts pins prompts to recorded versions instead of the active ones
+	Prompts prompt.Refs
+}
+
 func Generate[T openai.CompletionPrompt](gctx golly.Context, prompt T, aiContexts ...openai.AIContext) error {
-	_, err := openai.Completion(gctx, openai.LLM(gctx), prompt)
+	return GenerateWithOptions(gctx, GenerateOptions{}, prompt, aiContexts...)
+}
+
+func GenerateWithOptions[T openai.CompletionPrompt](gctx golly.Context, opts GenerateOptions, prompt T, aiContexts ...openai.AIContext) error {
+	llm, err := provider(gctx, opts)
+	if err != nil {
+		return err
+	}
+
+	if versioned, ok := any(prompt).(VersionedPrompt); ok {
+		versioned.UsePromptVersion(resolvePromptVersion(gctx, openai.PromptTypeName(prompt), opts.Prompts))
+	}
+
+	_, err = openai.Completion(gctx, llm, prompt, aiContexts...)
 	return err
 }
 
+type Embedding struct {
+	Model  string
+	Vector []float64
+}
+
+// Embed returns the embedding vector of the text, budgets and redaction
+// apply the same way they do to completions
+func Embed(gctx golly.Context, opts GenerateOptions, text string) (Embedding, error) {
+	llm, err := provider(gctx, opts)
+	if err != nil {
+		return Embedding{}, err
+	}
+
+	resp, err := llm.Embeddings(gctx, text)
+	if err != nil {
+		return Embedding{}, errors.WrapGeneric(err)
+	}
+
+	if len(resp.Data) == 0 {
+		return Embedding{}, errors.WrapNotFound(fmt.Errorf("no embeddings returned"))
+	}
+
+	return Embedding{Model: resp.Model, Vector: resp.Data[0].Embedding}, nil
+}
+
+func provider(gctx golly.Context, opts GenerateOptions) (openai.Provider, error) {
+	llm := openai.LLM(gctx)
+
+	switch budget := Budget(gctx); {
+	case budget.Blocked():
+		return nil, errors.WrapUnprocessable(ErrBudgetExceeded)
+	case budget.Degraded():
+		llm = economyProvider{llm}
+	}
+
+	if opts.Redactor != nil {
+		llm = RedactingProvider{Provider: llm, Redactor: opts.Redactor}
+	}
+
+	return llm, nil
+}
+
+func resolvePromptVersion(gctx golly.Context, name string, pinned prompt.Refs) PromptVersion {
+	ref, ok := pinned[name]
+	if !ok {
+		return ResolvePromptVersion(gctx, name)
+	}
+
+	version, err := FindPromptVersion(gctx, name, ref)
+	if err != nil {
+		gctx.Logger().Warnf("cannot find prompt %s %s, using the active version: %v", name, ref, err)
+		return ResolvePromptVersion(gctx, name)
+	}
+
+	return version
+}
+
 func Initailizer(app golly.Application) error {
+	InitGraphQL()
+
+	var providers map[string]openai.ProviderConfig
+	if err := app.Config.UnmarshalKey("llm.providers", &providers); err != nil {
+		return err
+	}
+
 	return openai.Initailizer(openai.Config{
 		Token: app.Config.GetString("openai.token"),
+
+		DefaultProvider: app.Config.GetString("llm.default"),
+		Providers:       providers,
+		Organizations:   app.Config.GetStringMapString("llm.organizations"),
+
+		RequestTimeout:    app.Config.GetDuration("llm.timeout"),
+		MaxRetries:        app.Config.GetInt("llm.max_retries"),
+		RequestsPerMinute: app.Config.GetInt("llm.rate_limit.requests_per_minute"),
+		RequestBurst:      app.Config.GetInt("llm.rate_limit.burst"),
+		SchemaRepairs:     app.Config.GetInt("llm.schema_repairs"),
+
+		UsageRecorder: RecordUsage,
+
+		Cache:    NewCacheStore(app.Config.GetString("llm.cache.store")),
+		CacheTTL: app.Config.GetDuration("llm.cache.ttl"),
+
 		AIPretendsToBe: "Pretend you are a performance management assistant providing insights and " +
 			"recommendations for team growth, performance reviews, and inclusivity.",
 		AIScenarioContext: "You are assisting in the Talent Radar application, focusing on team growth " +
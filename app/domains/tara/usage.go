@@ -0,0 +1,183 @@
+package tara
+
+import (
+	"time"
+
+	"github.com/golly-go/golly"
+	"github.com/golly-go/plugins/orm"
+	"github.com/google/uuid"
+	"github.com/mitchrodrigues/talent-review-backend/app/utils/openai"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// Usage is a single LLM call, kept for the per model / prompt breakdown
+type Usage struct {
+	orm.ModelUUID
+
+	OrganizationID uuid.UUID
+	UserID         uuid.UUID
+
+	Kind       string
+	Model      string
+	PromptType string
+	Cache      string
+
+	PromptTokens     int
+	CompletionTokens int
+	TotalTokens      int
+}
+
+func (Usage) TableName() string { return "llm_usages" }
+
+// MonthlyUsage is the running total per organization per calendar month,
+// this is what budgets are checked against
+type MonthlyUsage struct {
+	OrganizationID uuid.UUID `gorm:"primaryKey"`
+	Month          time.Time `gorm:"primaryKey"`
+
+	PromptTokens     int64
+	CompletionTokens int64
+	TotalTokens      int64
+	Requests         int
+
+	CacheHits   int
+	CacheMisses int
+
+	UpdatedAt time.Time
+}
+
+func (MonthlyUsage) TableName() string { return "llm_usage_monthlies" }
+
+// UsageBreakdown is the usage of a month grouped by model and prompt
+type UsageBreakdown struct {
+	Kind       string
+	Model      string
+	PromptType string
+
+	Requests         int
+	PromptTokens     int64
+	CompletionTokens int64
+	TotalTokens      int64
+
+	CacheHits   int
+	CacheMisses int
+}
+
+func StartOfMonth(t time.Time) time.Time {
+	t = t.UTC()
+	return time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, time.UTC)
+}
+
+// RecordUsage is the openai.UsageRecorder tara installs, failures are only
+// logged as accounting must never break the AI call itself
+func RecordUsage(gctx golly.Context, record openai.UsageRecord) {
+	if err := recordUsage(gctx, record, time.Now()); err != nil {
+		gctx.Logger().Warnf("cannot record llm usage for organization %s: %v", record.OrganizationID, err)
+	}
+}
+
+func recordUsage(gctx golly.Context, record openai.UsageRecord, at time.Time) error {
+	usage := Usage{
+		OrganizationID:   record.OrganizationID,
+		UserID:           record.UserID,
+		Kind:             string(record.Kind),
+		Model:            string(record.Model),
+		PromptType:       record.PromptType,
+		Cache:            string(record.Cache),
+		PromptTokens:     record.PromptTokens,
+		CompletionTokens: record.CompletionTokens,
+		TotalTokens:      record.TotalTokens,
+	}
+	usage.CreatedAt = at
+
+	// Requests are calls that reached the provider, cache hits did not
+	var requests, hits, misses int
+	switch record.Cache {
+	case openai.CacheHit:
+		hits = 1
+	case openai.CacheMiss:
+		requests, misses = 1, 1
+	default:
+		requests = 1
+	}
+
+	return orm.NewDB(gctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&usage).Error; err != nil {
+			return err
+		}
+
+		return tx.Clauses(clause.OnConflict{
+			Columns: []clause.Column{{Name: "organization_id"}, {Name: "month"}},
+			DoUpdates: clause.Assignments(map[string]interface{}{
+				"prompt_tokens":     gorm.Expr("llm_usage_monthlies.prompt_tokens + ?", usage.PromptTokens),
+				"completion_tokens": gorm.Expr("llm_usage_monthlies.completion_tokens + ?", usage.CompletionTokens),
+				"total_tokens":      gorm.Expr("llm_usage_monthlies.total_tokens + ?", usage.TotalTokens),
+				"requests":          gorm.Expr("llm_usage_monthlies.requests + ?", requests),
+				"cache_hits":        gorm.Expr("llm_usage_monthlies.cache_hits + ?", hits),
+				"cache_misses":      gorm.Expr("llm_usage_monthlies.cache_misses + ?", misses),
+				"updated_at":        at,
+			}),
+		}).Create(&MonthlyUsage{
+			OrganizationID:   usage.OrganizationID,
+			Month:            StartOfMonth(at),
+			PromptTokens:     int64(usage.PromptTokens),
+			CompletionTokens: int64(usage.CompletionTokens),
+			TotalTokens:      int64(usage.TotalTokens),
+			Requests:         requests,
+			CacheHits:        hits,
+			CacheMisses:      misses,
+			UpdatedAt:        at,
+		}).Error
+	})
+}
+
+func FindMonthlyUsage(gctx golly.Context, organizationID uuid.UUID, month time.Time) (MonthlyUsage, error) {
+	usage := MonthlyUsage{OrganizationID: organizationID, Month: StartOfMonth(month)}
+
+	err := orm.NewDB(gctx).
+		Model(&usage).
+		Where("organization_id = ? AND month = ?", organizationID, usage.Month).
+		Limit(1).
+		Find(&usage).
+		Error
+
+	return usage, err
+}
+
+func FindMonthlyUsageHistory(gctx golly.Context, organizationID uuid.UUID, months int) ([]MonthlyUsage, error) {
+	var history []MonthlyUsage
+
+	err := orm.NewDB(gctx).
+		Model(&MonthlyUsage{}).
+		Where("organization_id = ?", organizationID).
+		Order("month DESC").
+		Limit(months).
+		Find(&history).
+		Error
+
+	return history, err
+}
+
+func FindUsageBreakdown(gctx golly.Context, organizationID uuid.UUID, month time.Time) ([]UsageBreakdown, error) {
+	var breakdown []UsageBreakdown
+
+	start := StartOfMonth(month)
+
+	err := orm.NewDB(gctx).
+		Model(&Usage{}).
+		Select(`kind, model, prompt_type,
+			SUM(CASE WHEN cache = ? THEN 0 ELSE 1 END) AS requests,
+			SUM(CASE WHEN cache = ? THEN 1 ELSE 0 END) AS cache_hits,
+			SUM(CASE WHEN cache = ? THEN 1 ELSE 0 END) AS cache_misses,
+			SUM(prompt_tokens) AS prompt_tokens,
+			SUM(completion_tokens) AS completion_tokens,
+			SUM(total_tokens) AS total_tokens`, openai.CacheHit, openai.CacheHit, openai.CacheMiss).
+		Where("organization_id = ? AND created_at >= ? AND created_at < ?", organizationID, start, start.AddDate(0, 1, 0)).
+		Group("kind, model, prompt_type").
+		Order("total_tokens DESC").
+		Scan(&breakdown).
+		Error
+
+	return breakdown, err
+}
@@ -0,0 +1,143 @@
+package tara
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/golly-go/golly"
+	"github.com/golly-go/plugins/orm"
+	"github.com/mitchrodrigues/talent-review-backend/app/domains/accounts"
+	"github.com/mitchrodrigues/talent-review-backend/app/domains/accounts/organizations"
+	"github.com/mitchrodrigues/talent-review-backend/app/utils/identity"
+	"github.com/mitchrodrigues/talent-review-backend/app/utils/openai"
+	"github.com/stretchr/testify/assert"
+)
+
+func newUsageTestContext(settings organizations.Settings) (identity.Identity, golly.Context) {
+	gctx := orm.CreateTestContext(golly.NewContext(context.TODO()), Usage{}, MonthlyUsage{}, accounts.Organization{})
+
+	ident, gctx := identity.NewTestIdentity(gctx)
+
+	orm.DB(gctx).Create(&accounts.Organization{
+		Aggregate: organizations.Aggregate{
+			ModelUUID: orm.ModelUUID{ID: ident.OrganizationID},
+			Settings:  settings,
+		},
+	})
+
+	return ident, gctx
+}
+
+func TestRecordUsage(t *testing.T) {
+	ident, gctx := newUsageTestContext(organizations.Settings{})
+
+	now := time.Now()
+
+	records := []openai.UsageRecord{
+		{OrganizationID: ident.OrganizationID, Kind: openai.UsageCompletion, Model: "gpt-4o", PromptType: "SummarizeFeedbackPrompt", Usage: openai.Usage{PromptTokens: 100, CompletionTokens: 20, TotalTokens: 120}},
+		{OrganizationID: ident.OrganizationID, Kind: openai.UsageCompletion, Model: "gpt-4o", PromptType: "SummarizeFeedbackPrompt", Usage: openai.Usage{PromptTokens: 50, CompletionTokens: 10, TotalTokens: 60}},
+		{OrganizationID: ident.OrganizationID, Kind: openai.UsageEmbedding, Model: "text-embedding-ada-002", Usage: openai.Usage{PromptTokens: 8, TotalTokens: 8}},
+	}
+
+	for _, record := range records {
+		assert.NoError(t, recordUsage(gctx, record, now))
+	}
+
+	// last month is kept separately
+	assert.NoError(t, recordUsage(gctx, records[0], now.AddDate(0, -1, 0)))
+
+	monthly, err := FindMonthlyUsage(gctx, ident.OrganizationID, now)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(188), monthly.TotalTokens)
+	assert.Equal(t, int64(158), monthly.PromptTokens)
+	assert.Equal(t, 3, monthly.Requests)
+
+	breakdown, err := FindUsageBreakdown(gctx, ident.OrganizationID, now)
+	assert.NoError(t, err)
+
+	if assert.Len(t, breakdown, 2) {
+		assert.Equal(t, "SummarizeFeedbackPrompt", breakdown[0].PromptType)
+		assert.Equal(t, 2, breakdown[0].Requests)
+		assert.Equal(t, int64(180), breakdown[0].TotalTokens)
+	}
+
+	history, err := FindMonthlyUsageHistory(gctx, ident.OrganizationID, 12)
+	assert.NoError(t, err)
+	assert.Len(t, history, 2)
+}
+
+func TestRecordUsage_Cache(t *testing.T) {
+	ident, gctx := newUsageTestContext(organizations.Settings{})
+
+	now := time.Now()
+
+	records := []openai.UsageRecord{
+		{OrganizationID: ident.OrganizationID, Kind: openai.UsageCompletion, Model: "gpt-4o", Cache: openai.CacheMiss, Usage: openai.Usage{TotalTokens: 100}},
+		{OrganizationID: ident.OrganizationID, Kind: openai.UsageCompletion, Model: "gpt-4o", Cache: openai.CacheHit},
+		{OrganizationID: ident.OrganizationID, Kind: openai.UsageCompletion, Model: "gpt-4o", Cache: openai.CacheHit},
+	}
+
+	for _, record := range records {
+		assert.NoError(t, recordUsage(gctx, record, now))
+	}
+
+	monthly, err := FindMonthlyUsage(gctx, ident.OrganizationID, now)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, monthly.Requests)
+	assert.Equal(t, 2, monthly.CacheHits)
+	assert.Equal(t, 1, monthly.CacheMisses)
+	assert.Equal(t, int64(100), monthly.TotalTokens)
+
+	breakdown, err := FindUsageBreakdown(gctx, ident.OrganizationID, now)
+	assert.NoError(t, err)
+
+	if assert.Len(t, breakdown, 1) {
+		assert.Equal(t, 1, breakdown[0].Requests)
+		assert.Equal(t, 2, breakdown[0].CacheHits)
+		assert.Equal(t, 1, breakdown[0].CacheMisses)
+	}
+}
+
+func TestBudget(t *testing.T) {
+	tests := []struct {
+		name     string
+		settings organizations.Settings
+		used     int
+
+		blocked  bool
+		degraded bool
+	}{
+		{name: "unlimited", settings: organizations.Settings{}, used: 10_000},
+		{name: "under budget", settings: organizations.Settings{MonthlyTokenBudget: 1_000}, used: 999},
+		{name: "blocked", settings: organizations.Settings{MonthlyTokenBudget: 1_000}, used: 1_000, blocked: true},
+		{
+			name:     "degraded",
+			settings: organizations.Settings{MonthlyTokenBudget: 1_000, BudgetMode: organizations.BudgetDegrade},
+			used:     2_000,
+			degraded: true,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			ident, gctx := newUsageTestContext(tc.settings)
+
+			assert.NoError(t, recordUsage(gctx, openai.UsageRecord{
+				OrganizationID: ident.OrganizationID,
+				Usage:          openai.Usage{TotalTokens: tc.used},
+			}, time.Now()))
+
+			budget := Budget(gctx)
+			assert.Equal(t, tc.blocked, budget.Blocked())
+			assert.Equal(t, tc.degraded, budget.Degraded())
+
+			err := Generate(openai.UseProvider(gctx, openai.NewFixtureProvider(nil)), NewSummaryFeedbackPrompt(SummarizeFeedbackInput{}))
+			if tc.blocked {
+				assert.ErrorContains(t, err, ErrBudgetExceeded.Error())
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
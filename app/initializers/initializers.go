@@ -7,8 +7,10 @@ import (
 	"github.com/mitchrodrigues/talent-review-backend/app/domains/accounts"
 	"github.com/mitchrodrigues/talent-review-backend/app/domains/audits"
 	"github.com/mitchrodrigues/talent-review-backend/app/domains/employees"
+	"github.com/mitchrodrigues/talent-review-backend/app/domains/privacy"
 	"github.com/mitchrodrigues/talent-review-backend/app/domains/reviews"
 	"github.com/mitchrodrigues/talent-review-backend/app/domains/tara"
+	"github.com/mitchrodrigues/talent-review-backend/app/utils/crypto"
 	"github.com/mitchrodrigues/talent-review-backend/app/utils/esbackend"
 	"github.com/mitchrodrigues/talent-review-backend/app/utils/workos"
 )
@@ -40,11 +42,14 @@ var Initializers = []golly.GollyAppFunc{
 		})(app)
 	},
 
+	crypto.Initializer,
+
 	accounts.Initializer,
 	employees.Initalizer,
 	reviews.Initializer,
 	audits.Initialize,
 	tara.Initailizer,
+	privacy.Initializer,
 
 	// kafka.InitializerPublisher,
 	controllers.Initializer,
@@ -0,0 +1,81 @@
+// Package crypto encrypts sensitive columns at rest with envelope
+// encryption: each organization has its own data keys, which are stored
+// wrapped by a master key held by a KeyProvider (a local key file or a
+// KMS). Personal data in events is sealed with per subject keys so it
+// can be crypto-shredded
+package crypto
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/golly-go/golly"
+	"github.com/google/uuid"
+)
+
+// KeyProvider wraps and unwraps data keys with a master key
+type KeyProvider interface {
+	// KeyID is the master key new data keys are wrapped with
+	KeyID() string
+
+	Wrap(ctx context.Context, dataKey []byte) ([]byte, error)
+	Unwrap(ctx context.Context, keyID string, wrapped []byte) ([]byte, error)
+}
+
+var (
+	ErrorNoProvider   = fmt.Errorf("content is encrypted but no key provider is configured")
+	ErrorUnknownKey   = fmt.Errorf("unknown master key")
+	ErrorInvalidValue = fmt.Errorf("encrypted value is malformed")
+
+	lock     sync.RWMutex
+	provider KeyProvider
+
+	// dataKeys caches the unwrapped data keys by id
+	dataKeys = map[uuid.UUID][]byte{}
+
+	// subjectKeys caches the unwrapped subject keys by subject id, for
+	// subjectKeyTTL as another process may forget the subject
+	subjectKeys = map[uuid.UUID]cachedKey{}
+)
+
+// Initializer loads the master keys from the key file configured at
+// crypto.key_file, without one content is stored in plaintext
+func Initializer(app golly.Application) error {
+	path := app.Config.GetString("crypto.key_file")
+	if path == "" {
+		app.Logger.Warn("crypto.key_file is not set, feedback content and personal data in events are stored unencrypted")
+		return nil
+	}
+
+	local, err := NewLocalKeyProvider(path)
+	if err != nil {
+		return err
+	}
+
+	UseProvider(local)
+	return nil
+}
+
+// UseProvider sets the provider used to wrap data keys, nil turns
+// encryption off. It returns a func restoring the previous provider,
+// mostly for tests
+func UseProvider(p KeyProvider) func() {
+	lock.Lock()
+	defer lock.Unlock()
+
+	previous := provider
+	provider = p
+	dataKeys = map[uuid.UUID][]byte{}
+	subjectKeys = map[uuid.UUID]cachedKey{}
+
+	return func() { UseProvider(previous) }
+}
+
+// Provider returns the provider in use, nil when encryption is off
+func Provider() KeyProvider {
+	lock.RLock()
+	defer lock.RUnlock()
+
+	return provider
+}
@@ -0,0 +1,199 @@
+package crypto
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golly-go/plugins/orm"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEncrypt(t *testing.T) {
+	ctx := context.TODO()
+	db := orm.NewInMemoryConnection(DataKey{})
+
+	organizationID := uuid.New()
+
+	t.Run("plaintext while encryption is off", func(t *testing.T) {
+		value, err := Encrypt(ctx, db, organizationID, "Runs great meetings")
+		assert.NoError(t, err)
+		assert.Equal(t, "Runs great meetings", value)
+	})
+
+	defer UseProvider(NewTestKeyProvider())()
+
+	encrypted, err := Encrypt(ctx, db, organizationID, "Runs great meetings")
+	assert.NoError(t, err)
+	assert.True(t, IsEncrypted(encrypted))
+	assert.NotContains(t, encrypted, "meetings")
+
+	t.Run("round trips", func(t *testing.T) {
+		decrypted, err := Decrypt(ctx, db, organizationID, encrypted)
+		assert.NoError(t, err)
+		assert.Equal(t, "Runs great meetings", decrypted)
+
+		again, err := Encrypt(ctx, db, organizationID, encrypted)
+		assert.NoError(t, err)
+		assert.Equal(t, encrypted, again)
+
+		plaintext, err := Decrypt(ctx, db, organizationID, "written before encryption")
+		assert.NoError(t, err)
+		assert.Equal(t, "written before encryption", plaintext)
+	})
+
+	t.Run("each organization has its own keys", func(t *testing.T) {
+		_, err := Decrypt(ctx, db, uuid.New(), encrypted)
+		assert.Error(t, err)
+
+		_, err = Encrypt(ctx, db, uuid.Nil, "no organization")
+		assert.ErrorIs(t, err, ErrorOrganizationRequired)
+	})
+
+	t.Run("rotated keys still decrypt", func(t *testing.T) {
+		key, err := RotateDataKey(ctx, db, organizationID)
+		assert.NoError(t, err)
+
+		rotated, err := Encrypt(ctx, db, organizationID, "Runs great meetings")
+		assert.NoError(t, err)
+		assert.True(t, strings.HasPrefix(rotated, prefix+key.ID.String()))
+
+		decrypted, err := Decrypt(ctx, db, organizationID, encrypted)
+		assert.NoError(t, err)
+		assert.Equal(t, "Runs great meetings", decrypted)
+
+		var active int64
+		db.Model(&DataKey{}).Where("organization_id = ? AND retired_at IS NULL", organizationID).Count(&active)
+		assert.Equal(t, int64(1), active)
+	})
+
+	t.Run("cannot decrypt without the provider", func(t *testing.T) {
+		restore := UseProvider(nil)
+		defer restore()
+
+		_, err := Decrypt(ctx, db, organizationID, encrypted)
+		assert.ErrorIs(t, err, ErrorNoProvider)
+	})
+}
+
+func TestLocalKeyProvider(t *testing.T) {
+	ctx := context.TODO()
+
+	first, _ := GenerateMasterKey()
+	second, _ := GenerateMasterKey()
+
+	path := filepath.Join(t.TempDir(), "keys.json")
+	write := func(file KeyFile) {
+		b, _ := json.Marshal(file)
+		assert.NoError(t, os.WriteFile(path, b, 0600))
+	}
+
+	write(KeyFile{Active: "one", Keys: map[string]string{"one": first}})
+
+	local, err := NewLocalKeyProvider(path)
+	assert.NoError(t, err)
+
+	wrapped, err := local.Wrap(ctx, []byte("data key"))
+	assert.NoError(t, err)
+
+	// a new active master key still unwraps the data keys of the old one
+	write(KeyFile{Active: "two", Keys: map[string]string{"one": first, "two": second}})
+
+	rotated, err := NewLocalKeyProvider(path)
+	assert.NoError(t, err)
+	assert.Equal(t, "two", rotated.KeyID())
+
+	unwrapped, err := rotated.Unwrap(ctx, "one", wrapped)
+	assert.NoError(t, err)
+	assert.Equal(t, []byte("data key"), unwrapped)
+
+	_, err = rotated.Unwrap(ctx, "two", wrapped)
+	assert.Error(t, err)
+
+	_, err = rotated.Unwrap(ctx, "three", wrapped)
+	assert.ErrorIs(t, err, ErrorUnknownKey)
+
+	_, err = NewLocalKeyProviderFromKeys(KeyFile{Active: "missing", Keys: map[string]string{"one": first}})
+	assert.Error(t, err)
+
+	_, err = NewLocalKeyProviderFromKeys(KeyFile{Active: "short", Keys: map[string]string{"short": "c2hvcnQ="}})
+	assert.Error(t, err)
+}
+
+func TestForgetSubject(t *testing.T) {
+	ctx := context.TODO()
+	db := orm.NewInMemoryConnection(SubjectKey{})
+
+	organizationID, subjectID := uuid.New(), uuid.New()
+
+	raw := []byte(`{"name":"Jane Smith","email":"jane@example.com","level":3}`)
+
+	t.Run("plaintext while encryption is off", func(t *testing.T) {
+		payload, err := SealPersonalData(ctx, db, organizationID, subjectID, raw, "name", "email")
+		assert.NoError(t, err)
+		assert.Equal(t, raw, payload)
+	})
+
+	defer UseProvider(NewTestKeyProvider())()
+
+	payload, err := SealPersonalData(ctx, db, organizationID, subjectID, raw, "name", "email")
+	assert.NoError(t, err)
+	assert.NotContains(t, string(payload), "jane")
+	assert.Contains(t, string(payload), `"level":3`)
+
+	opened, err := OpenPersonalData(ctx, db, payload)
+	assert.NoError(t, err)
+	assert.JSONEq(t, string(raw), string(opened))
+
+	assert.NoError(t, ForgetSubject(ctx, db, organizationID, subjectID))
+	assert.NoError(t, ForgetSubject(ctx, db, organizationID, subjectID))
+
+	t.Run("forgotten data reads erased", func(t *testing.T) {
+		opened, err := OpenPersonalData(ctx, db, payload)
+		assert.NoError(t, err)
+		assert.JSONEq(t, `{"name":"[erased]","email":"[erased]","level":3}`, string(opened))
+
+		var key SubjectKey
+		db.First(&key, "id = ?", subjectID)
+		assert.Nil(t, key.WrappedKey)
+		assert.NotNil(t, key.ErasedAt)
+	})
+
+	t.Run("later writes are erased", func(t *testing.T) {
+		value, err := SealForSubject(ctx, db, organizationID, subjectID, "Jane Smith")
+		assert.NoError(t, err)
+		assert.Equal(t, Erased, value)
+	})
+
+	t.Run("subjects never seen before can be forgotten", func(t *testing.T) {
+		reviewer := EmailSubjectID(organizationID, "Peer@example.com ")
+		assert.Equal(t, EmailSubjectID(organizationID, "peer@example.com"), reviewer)
+
+		assert.NoError(t, ForgetSubject(ctx, db, organizationID, reviewer))
+
+		value, err := SealForSubject(ctx, db, organizationID, reviewer, "peer@example.com")
+		assert.NoError(t, err)
+		assert.Equal(t, Erased, value)
+	})
+	t.Run("subjects forgotten by another process expire from the cache", func(t *testing.T) {
+		defer func(ttl time.Duration) { subjectKeyTTL = ttl }(subjectKeyTTL)
+		subjectKeyTTL = 0
+
+		other := uuid.New()
+
+		sealed, err := SealForSubject(ctx, db, organizationID, other, "Jane Smith")
+		assert.NoError(t, err)
+
+		// another process only marks the key as erased
+		db.Model(&SubjectKey{}).Where("id = ?", other).Updates(map[string]interface{}{"wrapped_key": nil, "erased_at": time.Now()})
+
+		opened, err := OpenForSubject(ctx, db, sealed)
+		assert.NoError(t, err)
+		assert.Equal(t, Erased, opened)
+	})
+}
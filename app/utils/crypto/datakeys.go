@@ -0,0 +1,165 @@
+package crypto
+
+import (
+	"context"
+	"time"
+
+	"github.com/golly-go/plugins/orm"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// DataKey is a data key of an organization wrapped by a master key, the
+// newest key that is not retired encrypts new content
+type DataKey struct {
+	orm.ModelUUID
+
+	OrganizationID uuid.UUID
+	MasterKeyID    string
+	WrappedKey     []byte
+
+	RetiredAt *time.Time
+}
+
+func (DataKey) TableName() string { return "organization_data_keys" }
+
+// RotateDataKey retires the data keys of an organization and creates a
+// new one wrapped with the active master key. Retired keys still decrypt
+// the content they encrypted until it is re-encrypted
+func RotateDataKey(ctx context.Context, db *gorm.DB, organizationID uuid.UUID) (DataKey, error) {
+	db = db.Session(&gorm.Session{NewDB: true})
+
+	var key DataKey
+
+	err := db.Transaction(func(tx *gorm.DB) error {
+		err := tx.Model(&DataKey{}).
+			Where("organization_id = ? AND retired_at IS NULL", organizationID).
+			Update("retired_at", time.Now()).
+			Error
+
+		if err != nil {
+			return err
+		}
+
+		key, err = createDataKey(ctx, tx, organizationID)
+		return err
+	})
+
+	return key, err
+}
+
+// activeDataKey returns the data key new content of the organization is
+// encrypted with, creating it on first use
+func activeDataKey(ctx context.Context, db *gorm.DB, organizationID uuid.UUID) (DataKey, []byte, error) {
+	var keys []DataKey
+
+	err := db.Session(&gorm.Session{NewDB: true}).
+		Model(&DataKey{}).
+		Where("organization_id = ? AND retired_at IS NULL", organizationID).
+		Order("created_at DESC").
+		Limit(1).
+		Find(&keys).
+		Error
+
+	if err != nil {
+		return DataKey{}, nil, err
+	}
+
+	if len(keys) == 0 {
+		key, err := createDataKey(ctx, db.Session(&gorm.Session{NewDB: true}), organizationID)
+		if err != nil {
+			return key, nil, err
+		}
+		keys = append(keys, key)
+	}
+
+	plaintext, err := unwrapDataKey(ctx, keys[0])
+	return keys[0], plaintext, err
+}
+
+func createDataKey(ctx context.Context, db *gorm.DB, organizationID uuid.UUID) (DataKey, error) {
+	p := Provider()
+	if p == nil {
+		return DataKey{}, ErrorNoProvider
+	}
+
+	plaintext, err := randomBytes(32)
+	if err != nil {
+		return DataKey{}, err
+	}
+
+	wrapped, err := p.Wrap(ctx, plaintext)
+	if err != nil {
+		return DataKey{}, err
+	}
+
+	id, _ := uuid.NewV7()
+
+	key := DataKey{
+		ModelUUID:      orm.ModelUUID{ID: id},
+		OrganizationID: organizationID,
+		MasterKeyID:    p.KeyID(),
+		WrappedKey:     wrapped,
+	}
+
+	if err := db.Create(&key).Error; err != nil {
+		return key, err
+	}
+
+	cacheDataKey(key.ID, plaintext)
+	return key, nil
+}
+
+// findDataKey unwraps a data key of the organization by id
+func findDataKey(ctx context.Context, db *gorm.DB, organizationID, id uuid.UUID) ([]byte, error) {
+	if plaintext, ok := cachedDataKey(id); ok {
+		return plaintext, nil
+	}
+
+	var key DataKey
+
+	err := db.Session(&gorm.Session{NewDB: true}).
+		Model(&DataKey{}).
+		First(&key, "id = ? AND organization_id = ?", id, organizationID).
+		Error
+
+	if err != nil {
+		return nil, err
+	}
+
+	return unwrapDataKey(ctx, key)
+}
+
+func unwrapDataKey(ctx context.Context, key DataKey) ([]byte, error) {
+	if plaintext, ok := cachedDataKey(key.ID); ok {
+		return plaintext, nil
+	}
+
+	p := Provider()
+	if p == nil {
+		return nil, ErrorNoProvider
+	}
+
+	plaintext, err := p.Unwrap(ctx, key.MasterKeyID, key.WrappedKey)
+	if err != nil {
+		return nil, err
+	}
+
+	cacheDataKey(key.ID, plaintext)
+	return plaintext, nil
+}
+
+func cachedDataKey(id uuid.UUID) ([]byte, bool) {
+	lock.RLock()
+	defer lock.RUnlock()
+
+	plaintext, ok := dataKeys[id]
+	return plaintext, ok
+}
+
+func cacheDataKey(id uuid.UUID, plaintext []byte) {
+	lock.Lock()
+	defer lock.Unlock()
+
+	dataKeys[id] = plaintext
+}
@@ -0,0 +1,118 @@
+package crypto
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"strings"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// prefix marks encrypted values, they read enc:v1:<data key id>:<base64
+// nonce and ciphertext>. Values without it are plaintext written before
+// encryption was turned on
+const prefix = "enc:v1:"
+
+var ErrorOrganizationRequired = fmt.Errorf("cannot encrypt content without an organization")
+
+func IsEncrypted(value string) bool {
+	return strings.HasPrefix(value, prefix)
+}
+
+// Encrypt encrypts a value with the active data key of the organization,
+// empty and already encrypted values and everything while encryption is
+// off are returned as is
+func Encrypt(ctx context.Context, db *gorm.DB, organizationID uuid.UUID, value string) (string, error) {
+	if value == "" || IsEncrypted(value) || Provider() == nil {
+		return value, nil
+	}
+
+	if organizationID == uuid.Nil {
+		return value, ErrorOrganizationRequired
+	}
+
+	key, plaintext, err := activeDataKey(ctx, db, organizationID)
+	if err != nil {
+		return value, err
+	}
+
+	sealed, err := seal(plaintext, []byte(value), organizationID[:])
+	if err != nil {
+		return value, err
+	}
+
+	return prefix + key.ID.String() + ":" + base64.StdEncoding.EncodeToString(sealed), nil
+}
+
+// Decrypt reverses Encrypt, plaintext values are returned as is
+func Decrypt(ctx context.Context, db *gorm.DB, organizationID uuid.UUID, value string) (string, error) {
+	if !IsEncrypted(value) {
+		return value, nil
+	}
+
+	keyID, encoded, found := strings.Cut(strings.TrimPrefix(value, prefix), ":")
+	if !found {
+		return value, ErrorInvalidValue
+	}
+
+	id, err := uuid.Parse(keyID)
+	if err != nil {
+		return value, ErrorInvalidValue
+	}
+
+	sealed, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return value, ErrorInvalidValue
+	}
+
+	plaintext, err := findDataKey(ctx, db, organizationID, id)
+	if err != nil {
+		return value, err
+	}
+
+	opened, err := open(plaintext, sealed, organizationID[:])
+	if err != nil {
+		return value, err
+	}
+
+	return string(opened), nil
+}
+
+// EncryptFields encrypts the fields in place, it is meant for BeforeSave
+// hooks
+func EncryptFields(tx *gorm.DB, organizationID uuid.UUID, fields ...*string) error {
+	return eachField(fields, func(value string) (string, error) {
+		return Encrypt(tx.Statement.Context, tx, organizationID, value)
+	})
+}
+
+// DecryptFields decrypts the fields in place, it is meant for AfterFind
+// and AfterSave hooks so models hold plaintext in memory
+func DecryptFields(tx *gorm.DB, organizationID uuid.UUID, fields ...*string) error {
+	return eachField(fields, func(value string) (string, error) {
+		return Decrypt(tx.Statement.Context, tx, organizationID, value)
+	})
+}
+
+// Fields flattens the encrypted columns of a model, and the text nested in
+// its jsonb columns, into the fields EncryptFields and DecryptFields take
+func Fields(columns map[string]*string, nested ...*string) []*string {
+	values := make([]*string, 0, len(columns)+len(nested))
+	for _, field := range columns {
+		values = append(values, field)
+	}
+	return append(values, nested...)
+}
+
+func eachField(fields []*string, fn func(string) (string, error)) error {
+	for _, field := range fields {
+		value, err := fn(*field)
+		if err != nil {
+			return err
+		}
+		*field = value
+	}
+	return nil
+}
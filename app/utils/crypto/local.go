@@ -0,0 +1,144 @@
+package crypto
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"os"
+)
+
+// KeyFile is the json of a local key file, keys are base64 encoded 32
+// byte master keys. Rotating the master key adds a key and makes it
+// active, the previous ones are kept to unwrap existing data keys
+type KeyFile struct {
+	Active string            `json:"active"`
+	Keys   map[string]string `json:"keys"`
+}
+
+// LocalKeyProvider wraps data keys with AES-256-GCM master keys read from
+// a key file, for deployments without a KMS
+type LocalKeyProvider struct {
+	active string
+	keys   map[string][]byte
+}
+
+func NewLocalKeyProvider(path string) (*LocalKeyProvider, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var file KeyFile
+	if err := json.Unmarshal(b, &file); err != nil {
+		return nil, fmt.Errorf("cannot parse key file %s: %w", path, err)
+	}
+
+	return NewLocalKeyProviderFromKeys(file)
+}
+
+func NewLocalKeyProviderFromKeys(file KeyFile) (*LocalKeyProvider, error) {
+	local := &LocalKeyProvider{active: file.Active, keys: map[string][]byte{}}
+
+	for id, encoded := range file.Keys {
+		key, err := base64.StdEncoding.DecodeString(encoded)
+		if err != nil || len(key) != 32 {
+			return nil, fmt.Errorf("master key %s must be 32 base64 encoded bytes", id)
+		}
+		local.keys[id] = key
+	}
+
+	if _, ok := local.keys[local.active]; !ok {
+		return nil, fmt.Errorf("active master key %q is not in the key file", local.active)
+	}
+
+	return local, nil
+}
+
+// GenerateMasterKey returns a new base64 encoded master key for a key file
+func GenerateMasterKey() (string, error) {
+	key, err := randomBytes(32)
+	if err != nil {
+		return "", err
+	}
+	return base64.StdEncoding.EncodeToString(key), nil
+}
+
+func (local *LocalKeyProvider) KeyID() string { return local.active }
+
+func (local *LocalKeyProvider) Wrap(ctx context.Context, dataKey []byte) ([]byte, error) {
+	return seal(local.keys[local.active], dataKey, []byte(local.active))
+}
+
+func (local *LocalKeyProvider) Unwrap(ctx context.Context, keyID string, wrapped []byte) ([]byte, error) {
+	key, ok := local.keys[keyID]
+	if !ok {
+		return nil, fmt.Errorf("%w %s", ErrorUnknownKey, keyID)
+	}
+	return open(key, wrapped, []byte(keyID))
+}
+
+var _ KeyProvider = &LocalKeyProvider{}
+
+// TestKeyProvider keeps a random master key in memory, data wrapped with
+// it cannot be read once the process exits
+type TestKeyProvider struct {
+	LocalKeyProvider
+}
+
+func NewTestKeyProvider() *TestKeyProvider {
+	key, err := randomBytes(32)
+	if err != nil {
+		panic(err)
+	}
+
+	return &TestKeyProvider{LocalKeyProvider{active: "test", keys: map[string][]byte{"test": key}}}
+}
+
+func randomBytes(n int) ([]byte, error) {
+	b := make([]byte, n)
+	if _, err := rand.Read(b); err != nil {
+		return nil, err
+	}
+	return b, nil
+}
+
+// seal encrypts with AES-GCM, the nonce is prepended to the ciphertext
+func seal(key, plaintext, additional []byte) ([]byte, error) {
+	gcm, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+
+	nonce, err := randomBytes(gcm.NonceSize())
+	if err != nil {
+		return nil, err
+	}
+
+	return gcm.Seal(nonce, nonce, plaintext, additional), nil
+}
+
+func open(key, sealed, additional []byte) ([]byte, error) {
+	gcm, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(sealed) < gcm.NonceSize() {
+		return nil, ErrorInvalidValue
+	}
+
+	nonce, ciphertext := sealed[:gcm.NonceSize()], sealed[gcm.NonceSize():]
+	return gcm.Open(nil, nonce, ciphertext, additional)
+}
+
+func newGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
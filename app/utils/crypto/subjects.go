@@ -0,0 +1,334 @@
+package crypto
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/golly-go/plugins/orm"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// Personal data in event payloads is sealed with a key of its data
+// subject (an employee) instead of the organization, events can never be
+// rewritten so forgetting a subject destroys its key and leaves the
+// sealed values unreadable. They read pii:v1:<subject id>:<base64 nonce
+// and ciphertext>
+const subjectPrefix = "pii:v1:"
+
+// Erased is rendered in place of personal data of a forgotten subject
+const Erased = "[erased]"
+
+// subjectKeyTTL bounds how long a process keeps using the key of a
+// subject forgotten by another process
+var subjectKeyTTL = time.Minute
+
+type cachedKey struct {
+	plaintext []byte
+	expiresAt time.Time
+}
+
+// SubjectKey is the key personal data of a subject is sealed with, its id
+// is the id of the subject. Forgetting the subject drops the wrapped key
+// and keeps the row so later writes know the subject is gone
+type SubjectKey struct {
+	orm.ModelUUID
+
+	OrganizationID uuid.UUID
+	MasterKeyID    string
+	WrappedKey     []byte
+
+	ErasedAt *time.Time
+}
+
+func (SubjectKey) TableName() string { return "subject_keys" }
+
+// PersonalData is implemented by event payloads carrying personal data,
+// PersonalFields lists the json fields holding it
+type PersonalData interface {
+	PersonalFields() []string
+}
+
+// DataSubject is implemented by payloads whose personal data belongs to
+// someone else than the aggregate they are applied to
+type DataSubject interface {
+	DataSubjectID() uuid.UUID
+}
+
+// EmailSubjectID is the subject id of personal data only known by email,
+// like the reviewer of a feedback
+func EmailSubjectID(organizationID uuid.UUID, email string) uuid.UUID {
+	return uuid.NewSHA1(organizationID, []byte(strings.ToLower(strings.TrimSpace(email))))
+}
+
+func IsSealed(value string) bool {
+	return strings.HasPrefix(value, subjectPrefix)
+}
+
+// SealForSubject encrypts a value with the key of the subject, creating
+// it on first use. Values of forgotten subjects are stored as Erased,
+// empty and already sealed values and everything while encryption is off
+// are returned as is
+func SealForSubject(ctx context.Context, db *gorm.DB, organizationID, subjectID uuid.UUID, value string) (string, error) {
+	if value == "" || IsSealed(value) || Provider() == nil {
+		return value, nil
+	}
+
+	plaintext, err := subjectKey(ctx, db, organizationID, subjectID)
+	if err != nil {
+		return value, err
+	}
+
+	if plaintext == nil {
+		return Erased, nil
+	}
+
+	sealed, err := seal(plaintext, []byte(value), subjectID[:])
+	if err != nil {
+		return value, err
+	}
+
+	return subjectPrefix + subjectID.String() + ":" + base64.StdEncoding.EncodeToString(sealed), nil
+}
+
+// OpenForSubject reverses SealForSubject, values of forgotten subjects
+// open as Erased and plaintext values are returned as is
+func OpenForSubject(ctx context.Context, db *gorm.DB, value string) (string, error) {
+	if !IsSealed(value) {
+		return value, nil
+	}
+
+	subject, encoded, found := strings.Cut(strings.TrimPrefix(value, subjectPrefix), ":")
+	if !found {
+		return value, ErrorInvalidValue
+	}
+
+	subjectID, err := uuid.Parse(subject)
+	if err != nil {
+		return value, ErrorInvalidValue
+	}
+
+	sealed, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return value, ErrorInvalidValue
+	}
+
+	plaintext, err := findSubjectKey(ctx, db, subjectID)
+	if err != nil {
+		return value, err
+	}
+
+	if plaintext == nil {
+		return Erased, nil
+	}
+
+	opened, err := open(plaintext, sealed, subjectID[:])
+	if err != nil {
+		return value, err
+	}
+
+	return string(opened), nil
+}
+
+// ForgetSubject destroys the key of the subject, everything sealed with
+// it reads Erased from then on. Forgetting twice is a no-op
+func ForgetSubject(ctx context.Context, db *gorm.DB, organizationID, subjectID uuid.UUID) error {
+	db = db.Session(&gorm.Session{NewDB: true})
+
+	var keys []SubjectKey
+	if err := db.Model(&SubjectKey{}).Where("id = ?", subjectID).Limit(1).Find(&keys).Error; err != nil {
+		return err
+	}
+
+	forgetSubjectKey(subjectID)
+
+	now := time.Now()
+
+	if len(keys) == 0 {
+		return db.Create(&SubjectKey{
+			ModelUUID:      orm.ModelUUID{ID: subjectID},
+			OrganizationID: organizationID,
+			ErasedAt:       &now,
+		}).Error
+	}
+
+	if keys[0].ErasedAt != nil {
+		return nil
+	}
+
+	return db.Model(&keys[0]).
+		Select("WrappedKey", "ErasedAt").
+		Updates(SubjectKey{ErasedAt: &now}).
+		Error
+}
+
+// SealPersonalData seals the fields of a marshalled event payload with the
+// key of the subject, other fields are left untouched
+func SealPersonalData(ctx context.Context, db *gorm.DB, organizationID, subjectID uuid.UUID, raw []byte, fields ...string) ([]byte, error) {
+	if Provider() == nil || len(fields) == 0 {
+		return raw, nil
+	}
+
+	var data map[string]interface{}
+	if err := json.Unmarshal(raw, &data); err != nil {
+		return raw, err
+	}
+
+	for _, field := range fields {
+		value, ok := data[field].(string)
+		if !ok {
+			continue
+		}
+
+		sealed, err := SealForSubject(ctx, db, organizationID, subjectID, value)
+		if err != nil {
+			return raw, err
+		}
+		data[field] = sealed
+	}
+
+	return json.Marshal(data)
+}
+
+// OpenPersonalData opens every sealed value of a marshalled event payload,
+// it is what audit views render
+func OpenPersonalData(ctx context.Context, db *gorm.DB, raw []byte) ([]byte, error) {
+	if !strings.Contains(string(raw), subjectPrefix) {
+		return raw, nil
+	}
+
+	var data interface{}
+	if err := json.Unmarshal(raw, &data); err != nil {
+		return raw, err
+	}
+
+	opened, err := openValues(ctx, db, data)
+	if err != nil {
+		return raw, err
+	}
+
+	return json.Marshal(opened)
+}
+
+func openValues(ctx context.Context, db *gorm.DB, data interface{}) (interface{}, error) {
+	var err error
+
+	switch v := data.(type) {
+	case string:
+		return OpenForSubject(ctx, db, v)
+	case map[string]interface{}:
+		for key, value := range v {
+			if v[key], err = openValues(ctx, db, value); err != nil {
+				return v, err
+			}
+		}
+	case []interface{}:
+		for i, value := range v {
+			if v[i], err = openValues(ctx, db, value); err != nil {
+				return v, err
+			}
+		}
+	}
+
+	return data, nil
+}
+
+// subjectKey returns the key of the subject creating it on first use, nil
+// when the subject was forgotten
+func subjectKey(ctx context.Context, db *gorm.DB, organizationID, subjectID uuid.UUID) ([]byte, error) {
+	plaintext, err := findSubjectKey(ctx, db, subjectID)
+	if err == nil || !errors.Is(err, gorm.ErrRecordNotFound) {
+		return plaintext, err
+	}
+
+	p := Provider()
+
+	plaintext, err = randomBytes(32)
+	if err != nil {
+		return nil, err
+	}
+
+	wrapped, err := p.Wrap(ctx, plaintext)
+	if err != nil {
+		return nil, err
+	}
+
+	key := SubjectKey{
+		ModelUUID:      orm.ModelUUID{ID: subjectID},
+		OrganizationID: organizationID,
+		MasterKeyID:    p.KeyID(),
+		WrappedKey:     wrapped,
+	}
+
+	if err := db.Session(&gorm.Session{NewDB: true}).Create(&key).Error; err != nil {
+		return nil, err
+	}
+
+	cacheSubjectKey(subjectID, plaintext)
+	return plaintext, nil
+}
+
+// findSubjectKey unwraps the key of the subject, nil when the subject was
+// forgotten
+func findSubjectKey(ctx context.Context, db *gorm.DB, subjectID uuid.UUID) ([]byte, error) {
+	if plaintext, ok := cachedSubjectKey(subjectID); ok {
+		return plaintext, nil
+	}
+
+	var key SubjectKey
+
+	err := db.Session(&gorm.Session{NewDB: true}).
+		Model(&SubjectKey{}).
+		First(&key, "id = ?", subjectID).
+		Error
+
+	if err != nil {
+		return nil, err
+	}
+
+	if key.ErasedAt != nil {
+		return nil, nil
+	}
+
+	p := Provider()
+	if p == nil {
+		return nil, ErrorNoProvider
+	}
+
+	plaintext, err := p.Unwrap(ctx, key.MasterKeyID, key.WrappedKey)
+	if err != nil {
+		return nil, err
+	}
+
+	cacheSubjectKey(subjectID, plaintext)
+	return plaintext, nil
+}
+
+func cachedSubjectKey(id uuid.UUID) ([]byte, bool) {
+	lock.RLock()
+	defer lock.RUnlock()
+
+	cached, ok := subjectKeys[id]
+	if !ok || time.Now().After(cached.expiresAt) {
+		return nil, false
+	}
+	return cached.plaintext, true
+}
+
+func cacheSubjectKey(id uuid.UUID, plaintext []byte) {
+	lock.Lock()
+	defer lock.Unlock()
+
+	subjectKeys[id] = cachedKey{plaintext: plaintext, expiresAt: time.Now().Add(subjectKeyTTL)}
+}
+
+func forgetSubjectKey(id uuid.UUID) {
+	lock.Lock()
+	defer lock.Unlock()
+
+	delete(subjectKeys, id)
+}
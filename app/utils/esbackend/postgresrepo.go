@@ -10,6 +10,7 @@ import (
 	"github.com/golly-go/plugins/orm"
 	"github.com/google/uuid"
 	"github.com/jinzhu/gorm/dialects/postgres"
+	"github.com/mitchrodrigues/talent-review-backend/app/utils/crypto"
 	"github.com/mitchrodrigues/talent-review-backend/app/utils/identity"
 	"gorm.io/gorm"
 )
@@ -97,7 +98,29 @@ func mapToDB(gctx golly.Context, evt *eventsource.Event) (Event, error) {
 		return ret, err
 	}
 
-	return ret, nil
+	ret.RawData.RawMessage, err = sealPersonalData(gctx, ident.OrganizationID, agID, evt.Data, ret.RawData.RawMessage)
+	return ret, err
+}
+
+// sealPersonalData seals the personal fields of the marshalled payload
+// with the key of its data subject, the aggregate unless the payload
+// names another one
+func sealPersonalData(gctx golly.Context, organizationID, aggregateID uuid.UUID, data interface{}, raw []byte) ([]byte, error) {
+	pd, ok := data.(crypto.PersonalData)
+	if !ok {
+		return raw, nil
+	}
+
+	subjectID := aggregateID
+	if ds, ok := data.(crypto.DataSubject); ok {
+		subjectID = ds.DataSubjectID()
+	}
+
+	if organizationID == uuid.Nil {
+		organizationID, _ = GetOrganizationID(data)
+	}
+
+	return crypto.SealPersonalData(gctx.Context(), orm.DB(gctx), organizationID, subjectID, raw, pd.PersonalFields()...)
 }
 
 // GetOrganizationID extracts the OrganizationID field from any struct using reflection.
@@ -0,0 +1,95 @@
+package esbackend
+
+import (
+	"encoding/json"
+	"reflect"
+
+	"github.com/golly-go/golly"
+	"github.com/golly-go/golly/utils"
+	"github.com/golly-go/plugins/orm"
+	"github.com/google/uuid"
+	"github.com/jinzhu/gorm/dialects/postgres"
+	"github.com/mitchrodrigues/talent-review-backend/app/utils/crypto"
+	"gorm.io/gorm"
+)
+
+const sealBatchSize = 100
+
+// SealEvents seals the personal data of the stored events of the payload
+// types, the ones written before it was sealed or while encryption was
+// off, with the keys of their subjects. Personal data of subjects already
+// forgotten is replaced by crypto.Erased. It returns the number of events
+// rewritten and can be run again safely
+func SealEvents(gctx golly.Context, payloads ...crypto.PersonalData) (int, error) {
+	if crypto.Provider() == nil {
+		return 0, crypto.ErrorNoProvider
+	}
+
+	count := 0
+
+	for _, payload := range payloads {
+		var events []Event
+
+		err := orm.NewDB(gctx).
+			Model(&Event{}).
+			Where("type = ?", utils.GetTypeWithPackage(payload)).
+			FindInBatches(&events, sealBatchSize, func(tx *gorm.DB, batch int) error {
+				for _, event := range events {
+					if !hasPlaintext(event.RawData.RawMessage, payload.PersonalFields()) {
+						continue
+					}
+
+					data := reflect.New(reflect.TypeOf(payload))
+					if err := json.Unmarshal(event.RawData.RawMessage, data.Interface()); err != nil {
+						return err
+					}
+
+					organizationID := uuid.Nil
+					if event.OrganizationID != nil {
+						organizationID = *event.OrganizationID
+					}
+
+					raw, err := sealPersonalData(gctx, organizationID, event.AggregateID, data.Elem().Interface(), event.RawData.RawMessage)
+					if err != nil {
+						return err
+					}
+
+					err = orm.NewDB(gctx).
+						Model(&Event{}).
+						Where("id = ?", event.ID).
+						UpdateColumn("data", postgres.Jsonb{RawMessage: raw}).
+						Error
+
+					if err != nil {
+						return err
+					}
+					count++
+				}
+				return nil
+			}).
+			Error
+
+		if err != nil {
+			return count, err
+		}
+	}
+
+	return count, nil
+}
+
+// hasPlaintext reports whether any of the fields of the payload holds a
+// value that is not sealed yet
+func hasPlaintext(raw []byte, fields []string) bool {
+	var data map[string]interface{}
+	if err := json.Unmarshal(raw, &data); err != nil {
+		return false
+	}
+
+	for _, field := range fields {
+		if value, ok := data[field].(string); ok && value != "" && value != crypto.Erased && !crypto.IsSealed(value) {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,40 @@
+package esbackend
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/golly-go/golly"
+	"github.com/golly-go/plugins/orm"
+	"github.com/google/uuid"
+	"github.com/jinzhu/gorm/dialects/postgres"
+)
+
+// TombstoneData replaces the payload of purged events
+type TombstoneData struct {
+	Tombstone bool      `json:"tombstone"`
+	PurgedAt  time.Time `json:"purgedAt"`
+}
+
+// Tombstone replaces the payload of the events of the aggregates with a
+// tombstone, the events themselves are kept so the audit log still shows
+// what happened and when. Without types every event of the aggregates is
+// tombstoned
+func Tombstone(gctx golly.Context, aggregateIDs []uuid.UUID, types ...string) (int64, error) {
+	if len(aggregateIDs) == 0 {
+		return 0, nil
+	}
+
+	data, err := json.Marshal(TombstoneData{Tombstone: true, PurgedAt: time.Now()})
+	if err != nil {
+		return 0, err
+	}
+
+	db := orm.NewDB(gctx).Model(&Event{}).Where("aggregate_id IN ?", aggregateIDs)
+	if len(types) > 0 {
+		db = db.Where("type IN ?", types)
+	}
+
+	result := db.UpdateColumn("data", postgres.Jsonb{RawMessage: data})
+	return result.RowsAffected, result.Error
+}
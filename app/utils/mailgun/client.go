@@ -15,6 +15,8 @@ type Client interface {
 	SendEmailTemplate(golly.Context, EmailWithTemplate) error
 	SendInviteEmail(golly.Context, InviteEmailParams) error
 	SendFeedbackEmail(golly.Context, FeedbackEmailParams) error
+	SendNominationEmail(golly.Context, NominationEmailParams) error
+	SendFeedbackReleaseEmail(golly.Context, FeedbackReleaseEmailParams) error
 }
 type DefaultClient struct {
 	mailgun *mailgun.MailgunImpl
@@ -58,6 +60,25 @@ func (m *MockEmailClient) SendFeedbackEmail(gctx golly.Context, params FeedbackE
 	return args.Error(0)
 }
 
+// SendNominationEmail mocks the SendNominationEmail method.
+func (m *MockEmailClient) SendNominationEmail(gctx golly.Context, params NominationEmailParams) error {
+	args := m.Called(gctx, params)
+	return args.Error(0)
+}
+
+// SendFeedbackReleaseEmail mocks the SendFeedbackReleaseEmail method.
+func (m *MockEmailClient) SendFeedbackReleaseEmail(gctx golly.Context, params FeedbackReleaseEmailParams) error {
+	args := m.Called(gctx, params)
+	return args.Error(0)
+}
+
+// UseClient sets the client used to send emails, mostly to use a mock
+// in tests
+func UseClient(gctx golly.Context, client Client) golly.Context {
+	gctx.Set(contextKey, client)
+	return gctx
+}
+
 func GetClient(ctx golly.Context) Client {
 	if client, found := ctx.Get(contextKey); found {
 		return client.(Client)
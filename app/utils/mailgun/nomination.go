@@ -0,0 +1,61 @@
+package mailgun
+
+import (
+	"fmt"
+
+	"github.com/golly-go/golly"
+	"github.com/golly-go/golly/errors"
+)
+
+const (
+	NominationProposed = "proposed"
+	NominationUpdated  = "updated"
+	NominationApproved = "approved"
+	NominationRejected = "rejected"
+)
+
+// NominationEmailParams notifies the manager of nominations to review or
+// the employee of the decision on their nominations
+type NominationEmailParams struct {
+	Kind string
+
+	Name          string
+	Email         string
+	EmployeeName  string
+	Reviewers     []string
+	Note          string
+	NominationURL string
+}
+
+func (c *DefaultClient) SendNominationEmail(gctx golly.Context, params NominationEmailParams) error {
+	var subject string
+
+	switch params.Kind {
+	case NominationProposed:
+		subject = fmt.Sprintf("%s nominated reviewers for feedback", params.EmployeeName)
+	case NominationUpdated:
+		subject = fmt.Sprintf("%s changed the reviewers they nominated", params.EmployeeName)
+	case NominationApproved:
+		subject = "Your feedback reviewers were approved"
+	case NominationRejected:
+		subject = "Your feedback reviewers were not approved"
+	default:
+		return errors.WrapGeneric(fmt.Errorf("unknown nomination email %s", params.Kind))
+	}
+
+	return c.SendEmailTemplate(gctx, EmailWithTemplate{
+		Email: Email{
+			Recipient: params.Email,
+			Subject:   subject,
+		},
+		Template: "nomination " + params.Kind,
+		Variables: map[string]interface{}{
+			"name":          params.Name,
+			"email":         params.Email,
+			"employeeName":  params.EmployeeName,
+			"reviewers":     params.Reviewers,
+			"note":          params.Note,
+			"nominationURL": params.NominationURL,
+		},
+	})
+}
@@ -0,0 +1,55 @@
+package mailgun
+
+import (
+	"fmt"
+
+	"github.com/golly-go/golly"
+	"github.com/golly-go/golly/errors"
+)
+
+const (
+	FeedbackReleased     = "released"
+	FeedbackAcknowledged = "acknowledged"
+)
+
+// FeedbackReleaseEmailParams notifies the employee of feedback their
+// manager shared with them or the manager that it was acknowledged
+type FeedbackReleaseEmailParams struct {
+	Kind string
+
+	Name         string
+	Email        string
+	EmployeeName string
+	ManagerName  string
+	Note         string
+	ReleaseURL   string
+}
+
+func (c *DefaultClient) SendFeedbackReleaseEmail(gctx golly.Context, params FeedbackReleaseEmailParams) error {
+	var subject string
+
+	switch params.Kind {
+	case FeedbackReleased:
+		subject = fmt.Sprintf("%s shared your feedback with you", params.ManagerName)
+	case FeedbackAcknowledged:
+		subject = fmt.Sprintf("%s acknowledged the feedback you shared", params.EmployeeName)
+	default:
+		return errors.WrapGeneric(fmt.Errorf("unknown feedback release email %s", params.Kind))
+	}
+
+	return c.SendEmailTemplate(gctx, EmailWithTemplate{
+		Email: Email{
+			Recipient: params.Email,
+			Subject:   subject,
+		},
+		Template: "feedback " + params.Kind,
+		Variables: map[string]interface{}{
+			"name":         params.Name,
+			"email":        params.Email,
+			"employeeName": params.EmployeeName,
+			"managerName":  params.ManagerName,
+			"note":         params.Note,
+			"releaseURL":   params.ReleaseURL,
+		},
+	})
+}
@@ -0,0 +1,230 @@
+package openai
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"sync"
+	"time"
+
+	"github.com/golly-go/golly"
+	"github.com/google/uuid"
+	"github.com/mitchrodrigues/talent-review-backend/app/utils/identity"
+)
+
+type CacheStatus string
+
+const (
+	CacheHit  CacheStatus = "hit"
+	CacheMiss CacheStatus = "miss"
+
+	cacheBypassKey golly.ContextKeyT = "openaiCacheBypass"
+
+	// DefaultCacheTTL is used when no TTL is configured, cached responses
+	// quote feedback so they never live forever
+	DefaultCacheTTL = 24 * time.Hour
+)
+
+// CacheStore is where cached responses are kept, a TTL of zero never expires
+type CacheStore interface {
+	Get(gctx golly.Context, key string) (string, bool, error)
+	Set(gctx golly.Context, key string, value string, ttl time.Duration) error
+}
+
+// VersionedPrompt is implemented by prompts loaded from a registry, the
+// version becomes part of the cache key
+type VersionedPrompt interface {
+	PromptVersionKey() string
+}
+
+// BypassCache makes every call on the context skip the cache lookup, the
+// fresh responses still replace what is cached
+func BypassCache(gctx golly.Context) golly.Context {
+	return gctx.Set(cacheBypassKey, true)
+}
+
+func CacheBypassed(gctx golly.Context) bool {
+	bypass, _ := gctx.Get(cacheBypassKey)
+	return bypass == true
+}
+
+// CachingProvider answers repeated requests from a content addressed
+// cache keyed on the organization, provider, concrete model, prompt
+// version and full payload (messages, format, temperature), the same
+// request is only paid once. Responses are never shared between
+// organizations
+type CachingProvider struct {
+	Provider
+
+	Store CacheStore
+	TTL   time.Duration
+
+	// Namespace separates providers that resolve the same model tier to
+	// different models, usually the provider name
+	Namespace string
+}
+
+// NewCachingProvider wraps provider with the store, a TTL that is not
+// positive uses DefaultCacheTTL
+func NewCachingProvider(provider Provider, store CacheStore, ttl time.Duration, namespace string) CachingProvider {
+	if ttl <= 0 {
+		ttl = DefaultCacheTTL
+	}
+	return CachingProvider{Provider: provider, Store: store, TTL: ttl, Namespace: namespace}
+}
+
+// CompletionCacheKey is the key a completion payload of the organization
+// is cached under
+func CompletionCacheKey(organizationID uuid.UUID, namespace string, payload CompletionPayload) string {
+	b, _ := json.Marshal(payload)
+	return cacheKey("completion", organizationID.String(), namespace, payload.PromptVersion, string(b))
+}
+
+// EmbeddingCacheKey is the key the embedding of a text of the
+// organization is cached under
+func EmbeddingCacheKey(organizationID uuid.UUID, namespace string, model AIModel, text string) string {
+	return cacheKey("embedding", organizationID.String(), namespace, string(model), text)
+}
+
+func cacheKey(parts ...string) string {
+	hash := sha256.New()
+	for _, part := range parts {
+		hash.Write([]byte(part))
+		hash.Write([]byte{0})
+	}
+	return hex.EncodeToString(hash.Sum(nil))
+}
+
+func (cp CachingProvider) Completions(gctx golly.Context, payload CompletionPayload) (CompletionResponse, error) {
+	keyed := payload
+	keyed.Model = cp.model(payload.Model)
+
+	key := CompletionCacheKey(identity.FromContext(gctx).OrganizationID, cp.Namespace, keyed)
+
+	var resp CompletionResponse
+	if cp.lookup(gctx, key, &resp) {
+		resp.Cache = CacheHit
+		return resp, nil
+	}
+
+	resp, err := cp.Provider.Completions(gctx, payload)
+	if err != nil {
+		return resp, err
+	}
+
+	cp.store(gctx, key, resp)
+
+	resp.Cache = CacheMiss
+	return resp, nil
+}
+
+func (cp CachingProvider) Embeddings(gctx golly.Context, text string) (EmbeddingResponse, error) {
+	key := EmbeddingCacheKey(identity.FromContext(gctx).OrganizationID, cp.Namespace, cp.model(EmbeddingModel), text)
+
+	var resp EmbeddingResponse
+	if cp.lookup(gctx, key, &resp) {
+		resp.Cache = CacheHit
+		return resp, nil
+	}
+
+	resp, err := cp.Provider.Embeddings(gctx, text)
+	if err != nil {
+		return resp, err
+	}
+
+	cp.store(gctx, key, resp)
+
+	resp.Cache = CacheMiss
+	return resp, nil
+}
+
+// model resolves a tier the way the wrapped provider will, switching the
+// model behind a tier must not serve responses of the previous one
+func (cp CachingProvider) model(model AIModel) AIModel {
+	if resolver, ok := cp.Provider.(ModelResolver); ok {
+		return resolver.ResolveModel(model)
+	}
+	return model
+}
+
+func (cp CachingProvider) lookup(gctx golly.Context, key string, resp any) bool {
+	if cp.Store == nil || CacheBypassed(gctx) {
+		return false
+	}
+
+	value, found, err := cp.Store.Get(gctx, key)
+	if err != nil {
+		gctx.Logger().Warnf("llm cache lookup failed: %v", err)
+		return false
+	}
+
+	return found && json.Unmarshal([]byte(value), resp) == nil
+}
+
+func (cp CachingProvider) store(gctx golly.Context, key string, resp any) {
+	if cp.Store == nil {
+		return
+	}
+
+	b, err := json.Marshal(resp)
+	if err == nil {
+		err = cp.Store.Set(gctx, key, string(b), cp.TTL)
+	}
+
+	if err != nil {
+		gctx.Logger().Warnf("llm cache store failed: %v", err)
+	}
+}
+
+// MemoryCache is a process local CacheStore
+type MemoryCache struct {
+	lock    sync.Mutex
+	entries map[string]memoryCacheEntry
+
+	now func() time.Time
+}
+
+type memoryCacheEntry struct {
+	value     string
+	expiresAt time.Time
+}
+
+func NewMemoryCache() *MemoryCache {
+	return &MemoryCache{entries: map[string]memoryCacheEntry{}, now: time.Now}
+}
+
+func (mc *MemoryCache) Get(gctx golly.Context, key string) (string, bool, error) {
+	mc.lock.Lock()
+	defer mc.lock.Unlock()
+
+	entry, found := mc.entries[key]
+	if !found {
+		return "", false, nil
+	}
+
+	if !entry.expiresAt.IsZero() && !mc.now().Before(entry.expiresAt) {
+		delete(mc.entries, key)
+		return "", false, nil
+	}
+
+	return entry.value, true, nil
+}
+
+func (mc *MemoryCache) Set(gctx golly.Context, key string, value string, ttl time.Duration) error {
+	mc.lock.Lock()
+	defer mc.lock.Unlock()
+
+	entry := memoryCacheEntry{value: value}
+	if ttl > 0 {
+		entry.expiresAt = mc.now().Add(ttl)
+	}
+
+	mc.entries[key] = entry
+
+	return nil
+}
+
+var (
+	_ Provider   = CachingProvider{}
+	_ CacheStore = &MemoryCache{}
+)
@@ -0,0 +1,192 @@
+package openai
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/golly-go/golly"
+	"github.com/google/uuid"
+	"github.com/mitchrodrigues/talent-review-backend/app/utils/identity"
+	"github.com/stretchr/testify/assert"
+)
+
+// countingProvider counts the calls that reach it
+type countingProvider struct {
+	FixtureProvider
+
+	completions int
+	embeddings  int
+}
+
+func (cp *countingProvider) Completions(gctx golly.Context, payload CompletionPayload) (CompletionResponse, error) {
+	cp.completions++
+
+	resp, err := cp.FixtureProvider.Completions(gctx, payload)
+	resp.Usage = Usage{PromptTokens: 10, CompletionTokens: 5, TotalTokens: 15}
+
+	return resp, err
+}
+
+func (cp *countingProvider) Embeddings(gctx golly.Context, text string) (EmbeddingResponse, error) {
+	cp.embeddings++
+	return cp.FixtureProvider.Embeddings(gctx, text)
+}
+
+// resolvingProvider maps tiers onto models like the OpenAI client
+type resolvingProvider struct {
+	*countingProvider
+
+	models Models
+}
+
+func (rp resolvingProvider) ResolveModel(model AIModel) AIModel { return rp.models.Resolve(model) }
+
+func TestCachingProvider_ResolvedModel(t *testing.T) {
+	gctx := golly.NewContext(context.Background())
+
+	store := NewMemoryCache()
+	upstream := &countingProvider{FixtureProvider: FixtureProvider{Default: `{"aiField": "cached"}`, Dimensions: 4}}
+
+	payload, err := buildCompletionPayload(gctx, TestPrompt{})
+	assert.NoError(t, err)
+
+	call := func(models Models) {
+		provider := NewCachingProvider(resolvingProvider{upstream, models}, store, 0, "test")
+
+		_, err := provider.Completions(gctx, payload)
+		assert.NoError(t, err)
+
+		_, err = provider.Embeddings(gctx, "some feedback")
+		assert.NoError(t, err)
+	}
+
+	call(Models{payload.Model: "model-a", EmbeddingModel: "embed-a"})
+	call(Models{payload.Model: "model-a", EmbeddingModel: "embed-a"})
+
+	assert.Equal(t, 1, upstream.completions)
+	assert.Equal(t, 1, upstream.embeddings)
+
+	// switching the model behind a tier misses the cache
+	call(Models{payload.Model: "model-b", EmbeddingModel: "embed-b"})
+
+	assert.Equal(t, 2, upstream.completions)
+	assert.Equal(t, 2, upstream.embeddings)
+}
+
+func TestCachingProvider_DefaultTTL(t *testing.T) {
+	assert.Equal(t, DefaultCacheTTL, NewCachingProvider(&countingProvider{}, NewMemoryCache(), 0, "test").TTL)
+	assert.Equal(t, time.Hour, NewCachingProvider(&countingProvider{}, NewMemoryCache(), time.Hour, "test").TTL)
+}
+
+func TestCachingProvider(t *testing.T) {
+	gctx := golly.NewContext(context.Background())
+
+	upstream := &countingProvider{FixtureProvider: FixtureProvider{Default: `{"aiField": "cached"}`, Dimensions: 4}}
+	provider := NewCachingProvider(upstream, NewMemoryCache(), 0, "test")
+
+	payload, err := buildCompletionPayload(gctx, TestPrompt{})
+	assert.NoError(t, err)
+
+	first, err := provider.Completions(gctx, payload)
+	assert.NoError(t, err)
+	assert.Equal(t, CacheMiss, first.Cache)
+
+	second, err := provider.Completions(gctx, payload)
+	assert.NoError(t, err)
+	assert.Equal(t, CacheHit, second.Cache)
+	assert.Equal(t, first.Choices, second.Choices)
+	assert.Equal(t, 1, upstream.completions)
+
+	t.Run("the key covers the model and prompt version", func(t *testing.T) {
+		other := payload
+		other.Model = TurboModel
+		_, _ = provider.Completions(gctx, other)
+
+		versioned := payload
+		versioned.PromptVersion = "TestPrompt@v2"
+		_, _ = provider.Completions(gctx, versioned)
+
+		assert.Equal(t, 3, upstream.completions)
+	})
+
+	t.Run("bypass skips the lookup", func(t *testing.T) {
+		resp, err := provider.Completions(BypassCache(golly.NewContext(context.Background())), payload)
+		assert.NoError(t, err)
+		assert.Equal(t, CacheMiss, resp.Cache)
+		assert.Equal(t, 4, upstream.completions)
+	})
+
+	t.Run("embeddings", func(t *testing.T) {
+		first, _ := provider.Embeddings(gctx, "some feedback")
+		second, _ := provider.Embeddings(gctx, "some feedback")
+
+		assert.Equal(t, CacheMiss, first.Cache)
+		assert.Equal(t, CacheHit, second.Cache)
+		assert.Equal(t, first.Data, second.Data)
+		assert.Equal(t, 1, upstream.embeddings)
+	})
+
+	t.Run("responses are not shared between organizations", func(t *testing.T) {
+		for _, organizationID := range []uuid.UUID{uuid.New(), uuid.New()} {
+			gctx := identity.ToContext(gctx, identity.Identity{UID: uuid.New(), OrganizationID: organizationID})
+
+			resp, _ := provider.Completions(gctx, payload)
+			assert.Equal(t, CacheMiss, resp.Cache)
+
+			embedding, _ := provider.Embeddings(gctx, "some feedback")
+			assert.Equal(t, CacheMiss, embedding.Cache)
+		}
+
+		assert.Equal(t, 6, upstream.completions)
+		assert.Equal(t, 3, upstream.embeddings)
+	})
+}
+
+func TestMemoryCache_TTL(t *testing.T) {
+	gctx := golly.NewContext(context.Background())
+
+	now := time.Now()
+
+	cache := NewMemoryCache()
+	cache.now = func() time.Time { return now }
+
+	assert.NoError(t, cache.Set(gctx, "expires", "value", time.Minute))
+	assert.NoError(t, cache.Set(gctx, "forever", "value", 0))
+
+	value, found, _ := cache.Get(gctx, "expires")
+	assert.True(t, found)
+	assert.Equal(t, "value", value)
+
+	now = now.Add(time.Minute)
+
+	_, found, _ = cache.Get(gctx, "expires")
+	assert.False(t, found)
+
+	_, found, _ = cache.Get(gctx, "forever")
+	assert.True(t, found)
+}
+
+func TestMeteredProvider_CacheHit(t *testing.T) {
+	gctx := golly.NewContext(context.Background())
+
+	var records []UsageRecord
+
+	upstream := &countingProvider{FixtureProvider: FixtureProvider{Default: `{"aiField": "cached"}`}}
+	provider := NewMeteredProvider(NewCachingProvider(upstream, NewMemoryCache(), 0, "test"), func(_ golly.Context, record UsageRecord) {
+		records = append(records, record)
+	})
+
+	payload, _ := buildCompletionPayload(gctx, TestPrompt{})
+
+	_, _ = provider.Completions(gctx, payload)
+	_, _ = provider.Completions(gctx, payload)
+
+	if assert.Len(t, records, 2) {
+		assert.Equal(t, CacheMiss, records[0].Cache)
+		assert.Equal(t, 15, records[0].TotalTokens)
+
+		assert.Equal(t, CacheHit, records[1].Cache)
+		assert.Equal(t, Usage{}, records[1].Usage)
+	}
+}
@@ -0,0 +1,75 @@
+package openai
+
+import (
+	"encoding/json"
+	"os"
+	"sync"
+
+	"github.com/golly-go/golly"
+)
+
+// RecordingProvider passes completions through to another provider and
+// keeps the responses keyed like fixtures, a saved cassette is replayed
+// with a strict FixtureProvider so evaluations run offline and repeatably
+type RecordingProvider struct {
+	Provider
+
+	lock     sync.Mutex
+	fixtures map[string]string
+}
+
+// NewRecordingProvider records on top of an existing cassette, pass nil
+// to start a new one
+func NewRecordingProvider(provider Provider, fixtures map[string]string) *RecordingProvider {
+	if fixtures == nil {
+		fixtures = map[string]string{}
+	}
+	return &RecordingProvider{Provider: provider, fixtures: fixtures}
+}
+
+func (rp *RecordingProvider) Completions(gctx golly.Context, payload CompletionPayload) (CompletionResponse, error) {
+	resp, err := rp.Provider.Completions(gctx, payload)
+	if err != nil || len(resp.Choices) == 0 {
+		return resp, err
+	}
+
+	rp.lock.Lock()
+	defer rp.lock.Unlock()
+
+	rp.fixtures[FixtureKey(payload)] = resp.Choices[0].Message.Content
+
+	return resp, nil
+}
+
+// Fixtures returns a copy of everything recorded so far
+func (rp *RecordingProvider) Fixtures() map[string]string {
+	rp.lock.Lock()
+	defer rp.lock.Unlock()
+
+	ret := make(map[string]string, len(rp.fixtures))
+	for key, content := range rp.fixtures {
+		ret[key] = content
+	}
+
+	return ret
+}
+
+// Save writes the cassette in the fixture file format
+func (rp *RecordingProvider) Save(path string) error {
+	b, err := json.MarshalIndent(rp.Fixtures(), "", "  ")
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(path, b, 0o644)
+}
+
+// NewReplayProvider loads a cassette for strict replay
+func NewReplayProvider(path string) (*FixtureProvider, error) {
+	provider, err := NewFixtureProviderFromFile(path)
+	provider.Strict = true
+
+	return provider, err
+}
+
+var _ Provider = &RecordingProvider{}
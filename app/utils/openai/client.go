@@ -2,16 +2,53 @@ package openai
 
 import (
 	"bytes"
+	"cmp"
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
+	"math/rand"
 	"net/http"
+	"strings"
+	"time"
 
 	"github.com/golly-go/golly"
+	"github.com/mitchrodrigues/talent-review-backend/app/utils/identity"
+	"github.com/mitchrodrigues/talent-review-backend/app/utils/ratelimit"
+)
+
+const (
+	defaultRequestTimeout = 60 * time.Second
+	defaultMaxRetries     = 3
+
+	baseBackoff = 500 * time.Millisecond
+	maxBackoff  = 30 * time.Second
+
+	// maxBackoffShift already puts baseBackoff past maxBackoff, larger
+	// shifts would overflow
+	maxBackoffShift = 6
 )
 
 var (
 	httpClient = &http.Client{}
+
+	// orgLimiter is shared by every client so the limit holds per organization
+	// no matter how many providers are built, nil disables limiting
+	orgLimiter *ratelimit.Limiter
+
+	// sleep is swapped out in tests
+	sleep = func(ctx context.Context, d time.Duration) error {
+		timer := time.NewTimer(d)
+		defer timer.Stop()
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-timer.C:
+			return nil
+		}
+	}
 )
 
 type HTTPClient interface {
@@ -28,7 +65,40 @@ type OpenAIClient struct {
 	Token      string
 	HTTPClient HTTPClient
 
+	// BaseURL allows pointing the client at any OpenAI compatible API
+	BaseURL string
+	Models  Models
+
+	// DisableStructuredOutputs downgrades json_schema response formats to
+	// json_object for endpoints that do not support them, responses are
+	// still validated against the schema client side
+	DisableStructuredOutputs bool
+
 	Temperature float64
+
+	// Timeout bounds a single attempt, MaxRetries the number of retries
+	// after the first attempt, zero values fall back to config
+	Timeout    time.Duration
+	MaxRetries int
+
+	// Limiter overrides the per organization limiter from config
+	Limiter *ratelimit.Limiter
+}
+
+func (oai OpenAIClient) url(path string) string {
+	if oai.BaseURL == "" {
+		return openAIBaseURL + path
+	}
+	return strings.TrimSuffix(oai.BaseURL, "/") + path
+}
+
+func (oai OpenAIClient) ResolveModel(model AIModel) AIModel { return oai.model(model) }
+
+func (oai OpenAIClient) model(model AIModel) AIModel {
+	if oai.Models == nil {
+		return DefaultOpenAIModels.Resolve(model)
+	}
+	return oai.Models.Resolve(model)
 }
 
 func (oai OpenAIClient) request(gctx golly.Context, url string, payload any) ([]byte, error) {
@@ -40,15 +110,48 @@ func (oai OpenAIClient) request(gctx golly.Context, url string, payload any) ([]
 
 	gctx.Logger().Debugf("LLM Request: %s", string(payloadBytes))
 
+	maxRetries := cmp.Or(oai.MaxRetries, config.MaxRetries, defaultMaxRetries)
+
+	for attempt := 0; ; attempt++ {
+		if err := oai.limiter().Wait(gctx.Context(), identity.FromContext(gctx).OrganizationID.String()); err != nil {
+			return []byte{}, fmt.Errorf("error waiting for rate limiter: %w", err)
+		}
+
+		body, err := oai.do(gctx, url, payloadBytes)
+		if err == nil {
+			return body, nil
+		}
+
+		if attempt >= maxRetries || !retryable(err) {
+			return body, err
+		}
+
+		delay := backoff(attempt, err)
+
+		gctx.Logger().Warnf("LLM request failed (attempt %d/%d) retrying in %s: %v", attempt+1, maxRetries+1, delay, err)
+
+		if err := sleep(gctx.Context(), delay); err != nil {
+			return []byte{}, err
+		}
+	}
+}
+
+// do sends a single attempt of the request bound to the request timeout
+func (oai OpenAIClient) do(gctx golly.Context, url string, payloadBytes []byte) ([]byte, error) {
+	ctx, cancel := context.WithTimeout(gctx.Context(), cmp.Or(oai.Timeout, config.RequestTimeout, defaultRequestTimeout))
+	defer cancel()
+
 	// Create a new request
-	req, err := http.NewRequest("POST", url, bytes.NewBuffer(payloadBytes))
+	req, err := http.NewRequestWithContext(ctx, "POST", url, bytes.NewBuffer(payloadBytes))
 	if err != nil {
 		return []byte{}, fmt.Errorf("error creating request: %w", err)
 	}
 
 	// Set headers
 	req.Header.Set("Content-Type", "application/json")
-	req.Header.Set("Authorization", "Bearer "+oai.Token)
+	if oai.Token != "" {
+		req.Header.Set("Authorization", "Bearer "+oai.Token)
+	}
 
 	// Send the request using the same client to leverage HTTP Keep-Alive
 	resp, err := oai.HTTPClient.Do(req)
@@ -59,7 +162,58 @@ func (oai OpenAIClient) request(gctx golly.Context, url string, payload any) ([]
 	defer resp.Body.Close()
 
 	// Read the response
-	return io.ReadAll(resp.Body)
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return []byte{}, fmt.Errorf("error reading response: %w", err)
+	}
+
+	// Some of our mocks do not set a status, treat that as success
+	if resp.StatusCode == 0 || (resp.StatusCode >= 200 && resp.StatusCode < 300) {
+		return body, nil
+	}
+
+	errResp := struct {
+		Error *ErrorResponse `json:"error"`
+	}{}
+	_ = json.Unmarshal(body, &errResp)
+
+	return body, &APIError{
+		Kind:       classifyStatus(resp.StatusCode, errResp.Error),
+		StatusCode: resp.StatusCode,
+		RetryAfter: parseRetryAfter(resp.Header.Get("Retry-After"), time.Now()),
+		Response:   errResp.Error,
+	}
+}
+
+func (oai OpenAIClient) limiter() *ratelimit.Limiter {
+	if oai.Limiter != nil {
+		return oai.Limiter
+	}
+	return orgLimiter
+}
+
+// retryable is true for transient API errors and timeouts, a cancelled
+// parent context is never retried
+func retryable(err error) bool {
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		return apiErr.Retryable()
+	}
+
+	return errors.Is(err, context.DeadlineExceeded)
+}
+
+// backoff is exponential with jitter, Retry-After from the server wins
+func backoff(attempt int, err error) time.Duration {
+	var apiErr *APIError
+	if errors.As(err, &apiErr) && apiErr.RetryAfter > 0 {
+		return min(apiErr.RetryAfter, maxBackoff)
+	}
+
+	delay := min(baseBackoff<<min(max(attempt, 0), maxBackoffShift), maxBackoff)
+	delay += time.Duration(rand.Int63n(int64(delay)/2 + 1))
+
+	return min(delay, maxBackoff)
 }
 
 var _ HTTPClient = &CustomHTTPClient{}
@@ -3,9 +3,11 @@ package openai
 import (
 	"bytes"
 	"context"
+	"fmt"
 	"io"
 	"net/http"
 	"testing"
+	"time"
 
 	"github.com/golly-go/golly"
 	"github.com/stretchr/testify/assert"
@@ -41,3 +43,128 @@ func TestOpenAIClient_request(t *testing.T) {
 
 	mockClient.AssertExpectations(t)
 }
+
+func TestOpenAIClient_request_Retries(t *testing.T) {
+	gctx := golly.NewContext(context.Background())
+
+	response := func(status int, body string, header http.Header) *http.Response {
+		return &http.Response{
+			StatusCode: status,
+			Header:     header,
+			Body:       io.NopCloser(bytes.NewReader([]byte(body))),
+		}
+	}
+
+	var delays []time.Duration
+
+	previous := sleep
+	defer func() { sleep = previous }()
+
+	sleep = func(_ context.Context, d time.Duration) error {
+		delays = append(delays, d)
+		return nil
+	}
+
+	tests := []struct {
+		name      string
+		responses []*http.Response
+
+		expectedKind   ErrorKind
+		expectedCalls  int
+		expectedDelays []time.Duration
+	}{
+		{
+			name: "retries after rate limit honoring Retry-After",
+			responses: []*http.Response{
+				response(http.StatusTooManyRequests, `{"error": {"message": "slow down"}}`, http.Header{"Retry-After": []string{"2"}}),
+				response(http.StatusOK, `{}`, nil),
+			},
+			expectedCalls:  2,
+			expectedDelays: []time.Duration{2 * time.Second},
+		},
+		{
+			name: "gives up on server errors after max retries",
+			responses: []*http.Response{
+				response(http.StatusBadGateway, ``, nil),
+				response(http.StatusBadGateway, ``, nil),
+				response(http.StatusBadGateway, ``, nil),
+			},
+			expectedKind:  ErrorServer,
+			expectedCalls: 3,
+		},
+		{
+			name: "retries request timeouts",
+			responses: []*http.Response{
+				response(http.StatusRequestTimeout, ``, nil),
+				response(http.StatusOK, `{}`, nil),
+			},
+			expectedCalls: 2,
+		},
+		{
+			name: "quota is not retried",
+			responses: []*http.Response{
+				response(http.StatusTooManyRequests, `{"error": {"message": "quota", "code": "insufficient_quota"}}`, nil),
+			},
+			expectedKind:  ErrorQuotaExceeded,
+			expectedCalls: 1,
+		},
+		{
+			name: "invalid request is not retried",
+			responses: []*http.Response{
+				response(http.StatusBadRequest, `{"error": {"message": "bad model"}}`, nil),
+			},
+			expectedKind:  ErrorInvalidRequest,
+			expectedCalls: 1,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			delays = nil
+
+			mockClient := new(MockHTTPClient)
+			for _, resp := range tc.responses {
+				mockClient.On("Do", mock.Anything).Return(resp, nil).Once()
+			}
+
+			client := OpenAIClient{HTTPClient: mockClient, MaxRetries: 2}
+
+			_, err := client.request(gctx, "", CompletionPayload{})
+
+			if tc.expectedKind == "" {
+				assert.NoError(t, err)
+			} else {
+				var apiErr *APIError
+
+				assert.ErrorAs(t, err, &apiErr)
+				assert.Equal(t, tc.expectedKind, apiErr.Kind)
+			}
+
+			if tc.expectedDelays != nil {
+				assert.Equal(t, tc.expectedDelays, delays)
+			}
+
+			mockClient.AssertNumberOfCalls(t, "Do", tc.expectedCalls)
+		})
+	}
+}
+
+func TestBackoff(t *testing.T) {
+	err := fmt.Errorf("transient")
+
+	for _, attempt := range []int{0, 1, 10, 63, 64, 1000} {
+		delay := backoff(attempt, err)
+
+		assert.Greater(t, delay, time.Duration(0), "attempt %d", attempt)
+		assert.LessOrEqual(t, delay, maxBackoff, "attempt %d", attempt)
+	}
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	now := time.Now().UTC().Truncate(time.Second)
+
+	assert.Equal(t, 3*time.Second, parseRetryAfter("3", now))
+	assert.Equal(t, 10*time.Second, parseRetryAfter(now.Add(10*time.Second).Format(http.TimeFormat), now))
+	assert.Equal(t, time.Duration(0), parseRetryAfter("", now))
+	assert.Equal(t, time.Duration(0), parseRetryAfter("soon", now))
+}
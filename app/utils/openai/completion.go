@@ -59,6 +59,14 @@ type CompletionPayload struct {
 
 	FrequencyPenalty *float64           `json:"frequency_penalty,omitempty"`
 	LogitBias        map[string]float64 `json:"logit_bias,omitempty"`
+
+	// PromptType is the name of the prompt that built the payload, it is
+	// never sent to the API and only used for usage accounting
+	PromptType string `json:"-"`
+
+	// PromptVersion identifies the registry version of the prompt, it is
+	// part of the cache key
+	PromptVersion string `json:"-"`
 }
 
 type CompletionResponse struct {
@@ -72,6 +80,9 @@ type CompletionResponse struct {
 	Usage   Usage          `json:"usage"`
 	Model   AIModel        `json:"model"`
 	Error   *ErrorResponse `json:"error,omitempty"`
+
+	// Cache is set when the response went through a CachingProvider
+	Cache CacheStatus `json:"-"`
 }
 
 type Choice struct {
@@ -112,7 +123,13 @@ func (oai OpenAIClient) Completions(ctx golly.Context, payload CompletionPayload
 		payload.Model = FastModel
 	}
 
-	body, err := oai.request(ctx, openAICompletionURL, payload)
+	payload.Model = oai.model(payload.Model)
+
+	if oai.DisableStructuredOutputs && payload.Format.Schema() != nil {
+		payload.Format = &FormatJSON
+	}
+
+	body, err := oai.request(ctx, oai.url(completionPath), payload)
 	if err != nil {
 		return CompletionResponse{}, err
 	}
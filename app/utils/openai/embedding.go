@@ -2,6 +2,7 @@ package openai
 
 import (
 	"encoding/json"
+	"math"
 	"time"
 
 	"github.com/golly-go/golly"
@@ -15,6 +16,9 @@ type EmbeddingResponse struct {
 	Usage  Usage           `json:"usage"`
 
 	Error *ErrorResponse `json:"error,omitempty"`
+
+	// Cache is set when the response went through a CachingProvider
+	Cache CacheStatus `json:"-"`
 }
 
 type EmbeddingData struct {
@@ -32,9 +36,9 @@ func (oai OpenAIClient) Embeddings(ctx golly.Context, text string) (EmbeddingRes
 
 	response := EmbeddingResponse{}
 
-	params := map[string]any{"model": EmbeddingModel, "input": text}
+	params := map[string]any{"model": oai.model(EmbeddingModel), "input": text}
 
-	body, err := oai.request(ctx, openAIEmbeddingURL, params)
+	body, err := oai.request(ctx, oai.url(embeddingPath), params)
 	if err != nil {
 		return response, errors.WrapGeneric(err)
 	}
@@ -49,3 +53,24 @@ func (oai OpenAIClient) Embeddings(ctx golly.Context, text string) (EmbeddingRes
 
 	return response, nil
 }
+
+// CosineSimilarity of two vectors, 0 when either is empty or they differ
+// in length (embeddings from different models)
+func CosineSimilarity(a, b []float64) float64 {
+	if len(a) == 0 || len(a) != len(b) {
+		return 0
+	}
+
+	var dot, normA, normB float64
+	for pos := range a {
+		dot += a[pos] * b[pos]
+		normA += a[pos] * a[pos]
+		normB += b[pos] * b[pos]
+	}
+
+	if normA == 0 || normB == 0 {
+		return 0
+	}
+
+	return dot / (math.Sqrt(normA) * math.Sqrt(normB))
+}
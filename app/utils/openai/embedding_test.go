@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"context"
 	"io"
+	"math"
 	"net/http"
 	"reflect"
 	"testing"
@@ -74,3 +75,27 @@ func TestOpenAIClient_Embeddings(t *testing.T) {
 		})
 	}
 }
+
+func TestCosineSimilarity(t *testing.T) {
+	testCases := []struct {
+		name     string
+		a, b     []float64
+		expected float64
+	}{
+		{name: "identical", a: []float64{1, 2, 3}, b: []float64{1, 2, 3}, expected: 1},
+		{name: "scaled", a: []float64{1, 2, 3}, b: []float64{2, 4, 6}, expected: 1},
+		{name: "orthogonal", a: []float64{1, 0}, b: []float64{0, 1}, expected: 0},
+		{name: "opposite", a: []float64{1, 0}, b: []float64{-1, 0}, expected: -1},
+		{name: "length mismatch", a: []float64{1, 0}, b: []float64{1, 0, 0}, expected: 0},
+		{name: "zero vector", a: []float64{0, 0}, b: []float64{1, 0}, expected: 0},
+		{name: "empty", expected: 0},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := CosineSimilarity(tc.a, tc.b); math.Abs(got-tc.expected) > 1e-9 {
+				t.Errorf("Test %s: Expected %v, got %v", tc.name, tc.expected, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,105 @@
+package openai
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+type ErrorKind string
+
+const (
+	ErrorRateLimited    ErrorKind = "rate_limited"
+	ErrorQuotaExceeded  ErrorKind = "quota_exceeded"
+	ErrorInvalidRequest ErrorKind = "invalid_request"
+	ErrorServer         ErrorKind = "server"
+	ErrorTimeout        ErrorKind = "timeout"
+	ErrorUnknown        ErrorKind = "unknown"
+
+	quotaErrorCode = "insufficient_quota"
+)
+
+// APIError is returned for any non 2xx response from the LLM API
+type APIError struct {
+	Kind       ErrorKind
+	StatusCode int
+
+	// RetryAfter is parsed from the Retry-After header when present
+	RetryAfter time.Duration
+
+	Response *ErrorResponse
+}
+
+func (e *APIError) Error() string {
+	if e.Response != nil && e.Response.Message != "" {
+		return fmt.Sprintf("llm %s (%d): %s", e.Kind, e.StatusCode, e.Response.Message)
+	}
+	return fmt.Sprintf("llm %s (%d)", e.Kind, e.StatusCode)
+}
+
+func (e *APIError) Unwrap() error {
+	if e.Response == nil {
+		return nil
+	}
+	return e.Response
+}
+
+// Retryable is true for errors that may succeed if tried again later,
+// quota and invalid requests never will
+func (e *APIError) Retryable() bool {
+	return e.Kind == ErrorRateLimited || e.Kind == ErrorServer || e.Kind == ErrorTimeout
+}
+
+// IsRateLimited reports if err is (or wraps) a rate limit error
+func IsRateLimited(err error) bool {
+	return errorKind(err) == ErrorRateLimited
+}
+
+// IsQuotaExceeded reports if err is (or wraps) a quota error
+func IsQuotaExceeded(err error) bool {
+	return errorKind(err) == ErrorQuotaExceeded
+}
+
+func errorKind(err error) ErrorKind {
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		return apiErr.Kind
+	}
+	return ""
+}
+
+func classifyStatus(status int, resp *ErrorResponse) ErrorKind {
+	switch {
+	case status == http.StatusTooManyRequests:
+		if resp != nil && resp.Code == quotaErrorCode {
+			return ErrorQuotaExceeded
+		}
+		return ErrorRateLimited
+	case status == http.StatusRequestTimeout:
+		return ErrorTimeout
+	case status >= 500:
+		return ErrorServer
+	case status >= 400:
+		return ErrorInvalidRequest
+	}
+	return ErrorUnknown
+}
+
+// parseRetryAfter understands both the delay-seconds and HTTP date forms
+func parseRetryAfter(value string, now time.Time) time.Duration {
+	if value == "" {
+		return 0
+	}
+
+	if seconds, err := strconv.ParseFloat(value, 64); err == nil && seconds > 0 {
+		return time.Duration(seconds * float64(time.Second))
+	}
+
+	if at, err := http.ParseTime(value); err == nil && at.After(now) {
+		return at.Sub(now)
+	}
+
+	return 0
+}
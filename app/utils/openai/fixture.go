@@ -0,0 +1,160 @@
+package openai
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"math"
+	"math/rand"
+	"os"
+	"strings"
+
+	"github.com/golly-go/golly"
+)
+
+const (
+	defaultFixtureDimensions = 1536
+
+	fixtureFormatMarker = "following format:"
+)
+
+var (
+	ErrFixtureNotFound = fmt.Errorf("no fixture recorded for request")
+)
+
+// FixtureProvider answers every request deterministically without any
+// network access, this is what air-gapped deployments and tests run against.
+//
+// Completions are looked up by FixtureKey, when there is no fixture the
+// provider answers with an example built from the response schema (or
+// echoes the JSON example from the prompt) so tara still produces well
+// formed (if generic) results.
+type FixtureProvider struct {
+	Fixtures map[string]string
+
+	// Strict fails requests without a fixture instead of answering with
+	// a fallback, replaying a recorded cassette should never make things up
+	Strict bool
+
+	Default    string
+	Dimensions int
+}
+
+func NewFixtureProvider(fixtures map[string]string) *FixtureProvider {
+	if fixtures == nil {
+		fixtures = map[string]string{}
+	}
+	return &FixtureProvider{Fixtures: fixtures, Dimensions: defaultFixtureDimensions}
+}
+
+// NewFixtureProviderFromFile loads a fixture file, an empty path or missing
+// file still returns a usable provider along with the error
+func NewFixtureProviderFromFile(path string) (*FixtureProvider, error) {
+	provider := NewFixtureProvider(nil)
+
+	if path == "" {
+		return provider, nil
+	}
+
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return provider, err
+	}
+
+	return provider, json.Unmarshal(b, &provider.Fixtures)
+}
+
+// FixtureKey is a stable hash of the messages of a payload
+func FixtureKey(payload CompletionPayload) string {
+	b, _ := json.Marshal(payload.Messages)
+	sum := sha256.Sum256(b)
+
+	return hex.EncodeToString(sum[:])
+}
+
+func (fp *FixtureProvider) Completions(gctx golly.Context, payload CompletionPayload) (CompletionResponse, error) {
+	key := FixtureKey(payload)
+
+	content, ok := fp.Fixtures[key]
+	if !ok {
+		if fp.Strict {
+			return CompletionResponse{}, fmt.Errorf("%w: %s", ErrFixtureNotFound, key)
+		}
+		content = fp.fallback(payload)
+	}
+
+	return CompletionResponse{
+		ID:     "fixture",
+		Object: "chat.completion",
+		Model:  payload.Model,
+		Choices: []Choice{
+			{Message: Message{Role: RoleAI, Content: content}, FinishReason: "stop"},
+		},
+	}, nil
+}
+
+func (fp *FixtureProvider) fallback(payload CompletionPayload) string {
+	if fp.Default != "" {
+		return fp.Default
+	}
+
+	if schema := payload.Format.Schema(); schema != nil {
+		if b, err := json.Marshal(schema.Example()); err == nil {
+			return string(b)
+		}
+	}
+
+	for pos := len(payload.Messages) - 1; pos >= 0; pos-- {
+		content := payload.Messages[pos].Content
+
+		idx := strings.LastIndex(content, fixtureFormatMarker)
+		if idx == -1 {
+			continue
+		}
+
+		example := strings.TrimSpace(content[idx+len(fixtureFormatMarker):])
+		if json.Valid([]byte(example)) {
+			return example
+		}
+	}
+
+	return "{}"
+}
+
+// Embeddings returns a unit vector seeded from the hash of the text, the
+// same text always yields the same vector
+func (fp *FixtureProvider) Embeddings(gctx golly.Context, text string) (EmbeddingResponse, error) {
+	dimensions := fp.Dimensions
+	if dimensions <= 0 {
+		dimensions = defaultFixtureDimensions
+	}
+
+	sum := sha256.Sum256([]byte(text))
+	rnd := rand.New(rand.NewSource(int64(binary.BigEndian.Uint64(sum[:8]))))
+
+	var norm float64
+
+	vector := make([]float64, dimensions)
+	for pos := range vector {
+		vector[pos] = rnd.NormFloat64()
+		norm += vector[pos] * vector[pos]
+	}
+
+	norm = math.Sqrt(norm)
+	for pos := range vector {
+		vector[pos] /= norm
+	}
+
+	return EmbeddingResponse{
+		Object: "list",
+		Model:  "fixture",
+		Data:   []EmbeddingData{{Embedding: vector, Object: "embedding"}},
+	}, nil
+}
+
+// TTS writes an empty audio file
+func (fp *FixtureProvider) TTS(gctx golly.Context, filePath string, voice TTSVoice, text string) error {
+	return os.WriteFile(filePath, []byte{}, 0o600)
+}
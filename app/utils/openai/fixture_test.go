@@ -0,0 +1,74 @@
+package openai
+
+import (
+	"context"
+	"testing"
+
+	"github.com/golly-go/golly"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFixtureProvider_Completions(t *testing.T) {
+	gctx := golly.NewContext(context.Background())
+
+	payload, err := buildCompletionPayload(gctx, TestPrompt{})
+	assert.NoError(t, err)
+
+	t.Run("fixture hit", func(t *testing.T) {
+		provider := NewFixtureProvider(map[string]string{
+			FixtureKey(payload): `{"aiField": "from fixture"}`,
+		})
+
+		result, err := Completion(gctx, provider, &TestPrompt{})
+		assert.NoError(t, err)
+		assert.Equal(t, "from fixture", result.AIField)
+	})
+
+	t.Run("answers from the schema without a fixture", func(t *testing.T) {
+		result, err := Completion(gctx, NewFixtureProvider(nil), &TestPrompt{})
+		assert.NoError(t, err)
+		assert.Equal(t, "string", result.AIField)
+	})
+}
+
+func TestFixtureProvider_Embeddings(t *testing.T) {
+	gctx := golly.NewContext(context.Background())
+	provider := &FixtureProvider{Dimensions: 8}
+
+	first, err := provider.Embeddings(gctx, "some feedback")
+	assert.NoError(t, err)
+
+	second, _ := provider.Embeddings(gctx, "some feedback")
+	other, _ := provider.Embeddings(gctx, "other feedback")
+
+	assert.Len(t, first.Data[0].Embedding, 8)
+	assert.Equal(t, first, second)
+	assert.NotEqual(t, first.Data[0].Embedding, other.Data[0].Embedding)
+}
+
+func TestRecordingProvider_Replay(t *testing.T) {
+	gctx := golly.NewContext(context.Background())
+
+	upstream := NewFixtureProvider(nil)
+	upstream.Default = `{"aiField": "recorded"}`
+
+	recorder := NewRecordingProvider(upstream, nil)
+
+	result, err := Completion(gctx, recorder, &TestPrompt{})
+	assert.NoError(t, err)
+	assert.Equal(t, "recorded", result.AIField)
+	assert.Len(t, recorder.Fixtures(), 1)
+
+	path := t.TempDir() + "/cassette.json"
+	assert.NoError(t, recorder.Save(path))
+
+	replay, err := NewReplayProvider(path)
+	assert.NoError(t, err)
+
+	result, err = Completion(gctx, replay, &TestPrompt{})
+	assert.NoError(t, err)
+	assert.Equal(t, "recorded", result.AIField)
+
+	_, err = Completion(gctx, replay, &TestPrompt{rules: []string{"a rule that was never recorded"}})
+	assert.ErrorContains(t, err, ErrFixtureNotFound.Error())
+}
@@ -1,9 +1,12 @@
 package openai
 
 import (
+	"cmp"
 	"encoding/json"
+	stderrors "errors"
 	"fmt"
 	"reflect"
+	"strings"
 
 	"github.com/golly-go/golly"
 	"github.com/golly-go/golly/errors"
@@ -76,6 +79,11 @@ func buildCompletionPayload[T CompletionPrompt](gctx golly.Context, prompt T, ai
 
 	gctx.Logger().Debugf("Prompt: \n%s\n\n", pstring)
 
+	var version string
+	if versioned, ok := any(prompt).(VersionedPrompt); ok {
+		version = versioned.PromptVersionKey()
+	}
+
 	messages := aiContexts.
 		Append(prompt.DefaultAIContext()...).
 		Append(aiCtxs...).
@@ -84,11 +92,13 @@ func buildCompletionPayload[T CompletionPrompt](gctx golly.Context, prompt T, ai
 
 	return CompletionPayload{
 		Model:            prompt.Model(),
-		Format:           &FormatJSON,
+		Format:           NewJSONSchemaFormat(prompt),
 		Temperature:      temperature,
 		N:                prompt.N(),
 		LogitBias:        prompt.LogitBias(),
 		FrequencyPenalty: frequencyPenalty,
+		PromptType:       PromptTypeName(prompt),
+		PromptVersion:    version,
 		Messages: messages.Append(Message{
 			Role:    RoleUser,
 			Content: pstring,
@@ -96,29 +106,70 @@ func buildCompletionPayload[T CompletionPrompt](gctx golly.Context, prompt T, ai
 	}, nil
 }
 
-func Completion[T CompletionPrompt](gctx golly.Context, llm *OpenAIClient, prompt T, aiCtxs ...AIContext) (T, error) {
+func Completion[T CompletionPrompt](gctx golly.Context, llm Provider, prompt T, aiCtxs ...AIContext) (T, error) {
 	payload, err := buildCompletionPayload(gctx, prompt, aiCtxs...)
 	if err != nil {
 		return prompt, errors.WrapGeneric(err)
 	}
 
-	result, err := llm.Completions(gctx, payload)
+	schema := payload.Format.Schema()
+	repairs := cmp.Or(config.SchemaRepairs, defaultSchemaRepairs)
 
-	if err != nil {
-		return prompt, errors.WrapGeneric(err)
+	for attempt := 0; ; attempt++ {
+		result, err := llm.Completions(gctx, payload)
+		if err != nil {
+			return prompt, errors.WrapGeneric(err)
+		}
+
+		if len(result.Choices) == 0 {
+			return prompt, errors.WrapGeneric(fmt.Errorf("no choices returned"))
+		}
+
+		content := result.Choices[0].Message.Content
+
+		gctx.Logger().Debugf("Response %s", content)
+
+		err = validateResponse(schema, content)
+		if err == nil {
+			err = marshalPrompt(content, prompt)
+		}
+
+		if err == nil {
+			return prompt, nil
+		}
+
+		if attempt >= repairs {
+			return prompt, errors.WrapGeneric(err)
+		}
+
+		gctx.Logger().Warnf("[%s] invalid response (attempt %d/%d) asking for a repair: %v", payload.PromptType, attempt+1, repairs+1, err)
+
+		payload.Messages = append(payload.Messages,
+			Message{Role: RoleAI, Content: content},
+			UserMessage(repairInstructions(err)),
+		)
 	}
+}
 
-	if len(result.Choices) == 0 {
-		return prompt, errors.WrapGeneric(fmt.Errorf("no choices returned"))
+func validateResponse(schema *Schema, content string) error {
+	if schema != nil {
+		if err := schema.Validate([]byte(content)); err != nil {
+			return err
+		}
 	}
+	return nil
+}
 
-	gctx.Logger().Debugf("Response %s", result.Choices[0].Message.Content)
+func repairInstructions(err error) string {
+	problems := []string{err.Error()}
 
-	if err := marshalPrompt(result.Choices[0].Message.Content, prompt); err != nil {
-		return prompt, errors.WrapGeneric(err)
+	var verr SchemaValidationError
+	if stderrors.As(err, &verr) {
+		problems = verr.Errors
 	}
 
-	return prompt, nil
+	return "Your previous response was not valid:\n- " + strings.Join(problems, "\n- ") +
+		"\nRespond again with only valid JSON in the requested format, fixing these problems."
 }
 
 func marshalPrompt(response string, prompt interface{}) error {
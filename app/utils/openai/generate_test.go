@@ -22,9 +22,10 @@ func TestBuildCompletionPayloadTable(t *testing.T) {
 			mockPrompt: TestPrompt{},
 			expectedPayload: CompletionPayload{
 				Model:       FastModel,
-				Format:      &FormatJSON,
+				Format:      NewJSONSchemaFormat(TestPrompt{}),
 				N:           1,
 				Temperature: 0.4,
+				PromptType:  "TestPrompt",
 				Messages: Messages{
 					{Role: RoleUser, Content: "\nScenario:\nResult must be valid JSON in the following format:\n{\n \"aiField\": \"string\"\n}"},
 				}},
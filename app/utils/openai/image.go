@@ -10,7 +10,7 @@ import (
 )
 
 const (
-	openaiImageURL = "https://api.openai.com/v1/images/generations"
+	imagePath = "/images/generations"
 
 	MasterImagePrompt Template = `
 	{% if rules %}Rules:{% for rule in rules %}
@@ -76,9 +76,9 @@ func (oai OpenAIClient) Image(ctx golly.Context, payload ImagePayload) (ImageRes
 			Infof("Image Request took %s", time.Since(start))
 	}(time.Now())
 
-	payload.Model = ImageModel
+	payload.Model = oai.model(ImageModel)
 
-	b, err := oai.request(ctx, openaiImageURL, payload)
+	b, err := oai.request(ctx, oai.url(imagePath), payload)
 	if err != nil {
 		return result, err
 	}
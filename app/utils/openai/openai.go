@@ -1,22 +1,33 @@
 package openai
 
 import (
+	"cmp"
+	"encoding/json"
+	"time"
+
 	"github.com/golly-go/golly"
+	"github.com/mitchrodrigues/talent-review-backend/app/utils/ratelimit"
 )
 
 type AIModel string
 type MessageRole string
 
+// Model constants are tiers, each provider maps them onto a concrete model
+// name through its Models table (see DefaultOpenAIModels)
 const (
-	LargeTokenModel AIModel = "gpt-4o"
-	StandardModel   AIModel = "gpt-4o"
-	FastModel       AIModel = "gpt-4o"
-	TurboModel      AIModel = "gpt-4o"
+	LargeTokenModel AIModel = "large"
+	StandardModel   AIModel = "standard"
+	FastModel       AIModel = "fast"
+	TurboModel      AIModel = "turbo"
+
+	// EconomyModel is the cheapest model a provider offers, tara falls
+	// back to it when an organization runs over its token budget
+	EconomyModel AIModel = "economy"
 
-	EmbeddingModel AIModel = "text-embedding-ada-002"
-	WhisperModel   AIModel = "whipser-1"
-	TTSModel       AIModel = "tts-1"
-	ImageModel     AIModel = "dall-e-3"
+	EmbeddingModel AIModel = "embedding"
+	WhisperModel   AIModel = "whisper"
+	TTSModel       AIModel = "tts"
+	ImageModel     AIModel = "image"
 
 	AutoModel AIModel = ""
 
@@ -24,9 +35,16 @@ const (
 	RoleSystem MessageRole = "system"
 	RoleAI     MessageRole = "assistant"
 
-	openAICompletionURL = "https://api.openai.com/v1/chat/completions"
-	openAIEmbeddingURL  = "https://api.openai.com/v1/embeddings"
-	openAITTSURL        = "https://api.openai.com/v1/audio/speech"
+	formatJSONObject = "json_object"
+	formatJSONSchema = "json_schema"
+
+	defaultSchemaRepairs = 2
+
+	openAIBaseURL = "https://api.openai.com/v1"
+
+	completionPath = "/chat/completions"
+	embeddingPath  = "/embeddings"
+	ttsPath        = "/audio/speech"
 
 	openAIContextKey golly.ContextKeyT = "openaiClient"
 )
@@ -36,7 +54,19 @@ var (
 		{0.0},
 	}
 
-	FormatJSON = ResponseFormat{Type: "json_object"}
+	FormatJSON = ResponseFormat{Type: formatJSONObject}
+
+	DefaultOpenAIModels = Models{
+		LargeTokenModel: "gpt-4o",
+		StandardModel:   "gpt-4o",
+		FastModel:       "gpt-4o",
+		TurboModel:      "gpt-4o",
+		EconomyModel:    "gpt-4o-mini",
+		EmbeddingModel:  "text-embedding-ada-002",
+		WhisperModel:    "whisper-1",
+		TTSModel:        "tts-1",
+		ImageModel:      "dall-e-3",
+	}
 
 	config Config
 )
@@ -48,10 +78,109 @@ type Config struct {
 
 	AIPretendsToBe    string
 	AIScenarioContext string
+
+	// DefaultProvider is the name of the provider used when an organization
+	// has no entry in Organizations, empty means the stock OpenAI client
+	DefaultProvider string
+	Providers       map[string]ProviderConfig
+
+	// Organizations maps an organization ID to a provider name
+	Organizations map[string]string
+
+	// RequestTimeout bounds a single attempt, MaxRetries is the number of
+	// retries after it (negative disables retries)
+	RequestTimeout time.Duration
+	MaxRetries     int
+
+	// RequestsPerMinute is the client side limit per organization, zero disables it
+	RequestsPerMinute int
+	RequestBurst      int
+
+	// SchemaRepairs is how many times an invalid response is sent back to
+	// the model for repair (negative disables it)
+	SchemaRepairs int
+
+	// UsageRecorder receives the token usage of every call made through LLM
+	UsageRecorder UsageRecorder
+
+	// Cache when set answers repeated completions and embeddings made
+	// through LLM, entries expire after CacheTTL (DefaultCacheTTL when
+	// not set)
+	Cache    CacheStore
+	CacheTTL time.Duration
+}
+
+// Models maps a model tier onto the concrete model name of a provider
+type Models map[AIModel]AIModel
+
+// Resolve returns the concrete model for a tier, anything not in the
+// table is assumed to already be a concrete model name and passed through
+func (m Models) Resolve(model AIModel) AIModel {
+	if concrete, ok := m[model]; ok && concrete != "" {
+		return concrete
+	}
+	return model
+}
+
+// Merge returns a copy of m with the entries of overrides layered on top
+func (m Models) Merge(overrides Models) Models {
+	ret := Models{}
+
+	for tier, model := range m {
+		ret[tier] = model
+	}
+
+	for tier, model := range overrides {
+		if model != "" {
+			ret[tier] = model
+		}
+	}
+
+	return ret
 }
 
 type ResponseFormat struct {
-	Type string `json:"type"`
+	Type       string            `json:"type"`
+	JSONSchema *JSONSchemaFormat `json:"json_schema,omitempty"`
+}
+
+// JSONSchemaFormat is the strict structured output format, Schema is the
+// full schema we validate against, only its Strict form is sent
+type JSONSchemaFormat struct {
+	Name   string  `json:"name"`
+	Strict bool    `json:"strict"`
+	Schema *Schema `json:"-"`
+}
+
+func (f JSONSchemaFormat) MarshalJSON() ([]byte, error) {
+	return json.Marshal(struct {
+		Name   string  `json:"name"`
+		Strict bool    `json:"strict"`
+		Schema *Schema `json:"schema"`
+	}{f.Name, f.Strict, f.Schema.Strict()})
+}
+
+// NewJSONSchemaFormat returns a strict json_schema response format for a
+// prompt, prompts that are not objects fall back to json_object
+func NewJSONSchemaFormat(prompt any) *ResponseFormat {
+	schema := SchemaFor(prompt)
+	if schema.Type != SchemaObject {
+		return &FormatJSON
+	}
+
+	return &ResponseFormat{
+		Type:       formatJSONSchema,
+		JSONSchema: &JSONSchemaFormat{Name: golly.Coalesce(PromptTypeName(prompt), "response"), Strict: true, Schema: schema},
+	}
+}
+
+// Schema returns the schema the response must validate against, nil
+// when the format has none
+func (f *ResponseFormat) Schema() *Schema {
+	if f == nil || f.JSONSchema == nil {
+		return nil
+	}
+	return f.JSONSchema.Schema
 }
 
 type Usage struct {
@@ -79,19 +208,40 @@ func UserMessage(content string) Message {
 	return Message{Role: RoleUser, Content: content}
 }
 
-// Maybe hmm
-func LLM(gctx golly.Context) *OpenAIClient {
-	if client, ok := gctx.Get(openAIContextKey); ok {
-		return client.(*OpenAIClient)
+// LLM returns the provider configured for the organization on the context,
+// the provider is memoized on the context so a request only builds it once
+func LLM(gctx golly.Context) Provider {
+	if provider, ok := gctx.Get(openAIContextKey); ok {
+		return provider.(Provider)
 	}
 
-	client := NewClient(gctx)
-	gctx.Set(openAIContextKey, client)
+	name := ProviderNameForContext(gctx)
 
-	return client
+	provider := NewProvider(gctx, name)
+	if config.Cache != nil {
+		provider = NewCachingProvider(provider, config.Cache, config.CacheTTL, cmp.Or(name, string(ProviderOpenAI)))
+	}
+
+	if config.UsageRecorder != nil {
+		provider = NewMeteredProvider(provider, config.UsageRecorder)
+	}
+
+	gctx.Set(openAIContextKey, provider)
+
+	return provider
+}
+
+// UseProvider forces a provider onto the context, this is mainly
+// used by tools and tests that want to swap in the fixture provider
+func UseProvider(gctx golly.Context, provider Provider) golly.Context {
+	return gctx.Set(openAIContextKey, provider)
 }
 
 func NewClient(ctx golly.Context) *OpenAIClient {
+	return NewClientWithConfig(ctx, ProviderConfig{Kind: ProviderOpenAI, Token: config.Token})
+}
+
+func NewClientWithConfig(ctx golly.Context, pc ProviderConfig) *OpenAIClient {
 	var client HTTPClient = &CustomHTTPClient{}
 	if golly.Env().IsTest() {
 		client = &MockHTTPClient{}
@@ -99,11 +249,21 @@ func NewClient(ctx golly.Context) *OpenAIClient {
 
 	return &OpenAIClient{
 		HTTPClient: client,
-		Token:      config.Token,
+		Token:      pc.Token,
+		BaseURL:    pc.BaseURL,
+		Models:     DefaultOpenAIModels.Merge(pc.Models),
+
+		DisableStructuredOutputs: pc.StructuredOutputs != nil && !*pc.StructuredOutputs,
 	}
 }
 
 func Initailizer(c Config) error {
 	config = c
+
+	orgLimiter = nil
+	if c.RequestsPerMinute > 0 {
+		orgLimiter = ratelimit.PerMinute(c.RequestsPerMinute, cmp.Or(c.RequestBurst, c.RequestsPerMinute))
+	}
+
 	return nil
 }
@@ -0,0 +1,122 @@
+package openai
+
+import (
+	"github.com/golly-go/golly"
+	"github.com/mitchrodrigues/talent-review-backend/app/utils/identity"
+)
+
+type ProviderKind string
+
+const (
+	// ProviderOpenAI talks to api.openai.com
+	ProviderOpenAI ProviderKind = "openai"
+
+	// ProviderLocal talks to any OpenAI compatible endpoint (Ollama, vLLM, LocalAI)
+	ProviderLocal ProviderKind = "local"
+
+	// ProviderFixture never leaves the process and answers deterministically
+	ProviderFixture ProviderKind = "fixture"
+
+	defaultLocalBaseURL = "http://localhost:11434/v1"
+)
+
+var (
+	DefaultLocalModels = Models{
+		LargeTokenModel: "llama3.1",
+		StandardModel:   "llama3.1",
+		FastModel:       "llama3.1",
+		TurboModel:      "llama3.1",
+		EconomyModel:    "llama3.1",
+		EmbeddingModel:  "nomic-embed-text",
+		TTSModel:        "tts-1",
+	}
+)
+
+// Provider is implemented by every LLM backend tara can talk to
+type Provider interface {
+	Completions(golly.Context, CompletionPayload) (CompletionResponse, error)
+	Embeddings(golly.Context, string) (EmbeddingResponse, error)
+	TTS(gctx golly.Context, filePath string, voice TTSVoice, text string) error
+}
+
+// ModelResolver is implemented by providers mapping model tiers onto
+// concrete models
+type ModelResolver interface {
+	ResolveModel(AIModel) AIModel
+}
+
+type ProviderConfig struct {
+	Kind ProviderKind `mapstructure:"kind"`
+
+	BaseURL string `mapstructure:"base_url"`
+	Token   string `mapstructure:"token"`
+
+	// Models overrides the tier -> model mapping of the provider
+	Models Models `mapstructure:"models"`
+
+	// StructuredOutputs toggles strict json_schema response formats, it
+	// defaults to on for OpenAI and off for local endpoints
+	StructuredOutputs *bool `mapstructure:"structured_outputs"`
+
+	// Fixtures is a path to a JSON file of fixture key -> completion
+	// content, only used by the fixture provider
+	Fixtures string `mapstructure:"fixtures"`
+}
+
+// ProviderNameForContext returns the provider name configured for the
+// organization on the context falling back to the default provider
+func ProviderNameForContext(gctx golly.Context) string {
+	ident := identity.FromContext(gctx)
+
+	if name, ok := config.Organizations[ident.OrganizationID.String()]; ok && name != "" {
+		return name
+	}
+
+	return config.DefaultProvider
+}
+
+// NewProvider builds the named provider from config, an unknown name
+// falls back to the stock OpenAI client so existing deployments keep working
+func NewProvider(gctx golly.Context, name string) Provider {
+	pc, ok := config.Providers[name]
+	if !ok {
+		pc = ProviderConfig{Kind: ProviderOpenAI}
+	}
+
+	switch pc.Kind {
+	case ProviderLocal:
+		return NewLocalClient(gctx, pc)
+	case ProviderFixture:
+		provider, err := NewFixtureProviderFromFile(pc.Fixtures)
+		if err != nil {
+			gctx.Logger().Warnf("cannot load llm fixtures %s: %v", pc.Fixtures, err)
+		}
+		return provider
+	default:
+		if pc.Token == "" {
+			pc.Token = config.Token
+		}
+		return NewClientWithConfig(gctx, pc)
+	}
+}
+
+// NewLocalClient returns an OpenAI client pointed at a self hosted
+// OpenAI compatible endpoint
+func NewLocalClient(gctx golly.Context, pc ProviderConfig) *OpenAIClient {
+	if pc.BaseURL == "" {
+		pc.BaseURL = defaultLocalBaseURL
+	}
+
+	client := NewClientWithConfig(gctx, pc)
+	client.Models = DefaultLocalModels.Merge(pc.Models)
+	client.DisableStructuredOutputs = pc.StructuredOutputs == nil || !*pc.StructuredOutputs
+
+	return client
+}
+
+var (
+	_ Provider = &OpenAIClient{}
+	_ Provider = &FixtureProvider{}
+
+	_ ModelResolver = &OpenAIClient{}
+)
@@ -0,0 +1,125 @@
+package openai
+
+import (
+	"context"
+	"testing"
+
+	"github.com/golly-go/golly"
+	"github.com/google/uuid"
+	"github.com/mitchrodrigues/talent-review-backend/app/utils/identity"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestModels_Resolve(t *testing.T) {
+	models := DefaultOpenAIModels.Merge(Models{FastModel: "gpt-4o-mini"})
+
+	assert.Equal(t, AIModel("gpt-4o-mini"), models.Resolve(FastModel))
+	assert.Equal(t, AIModel("gpt-4o"), models.Resolve(LargeTokenModel))
+	assert.Equal(t, AIModel("o1-preview"), models.Resolve("o1-preview"))
+
+	// Merge must not mutate the defaults
+	assert.Equal(t, AIModel("gpt-4o"), DefaultOpenAIModels.Resolve(FastModel))
+}
+
+func TestOpenAIClient_url(t *testing.T) {
+	assert.Equal(t, "https://api.openai.com/v1/chat/completions", OpenAIClient{}.url(completionPath))
+	assert.Equal(t, "http://llm.internal/v1/embeddings", OpenAIClient{BaseURL: "http://llm.internal/v1/"}.url(embeddingPath))
+}
+
+func TestProviderConfig_unmarshal(t *testing.T) {
+	v := viper.New()
+	v.Set("llm.providers", map[string]interface{}{
+		"ollama": map[string]interface{}{
+			"kind":               "local",
+			"base_url":           "http://localhost:11434/v1",
+			"structured_outputs": true,
+			"models":             map[string]interface{}{string(FastModel): "mistral"},
+		},
+	})
+
+	var providers map[string]ProviderConfig
+	assert.NoError(t, v.UnmarshalKey("llm.providers", &providers))
+
+	ollama := providers["ollama"]
+	assert.Equal(t, ProviderLocal, ollama.Kind)
+	assert.Equal(t, "http://localhost:11434/v1", ollama.BaseURL)
+	assert.Equal(t, AIModel("mistral"), ollama.Models.Resolve(FastModel))
+	if assert.NotNil(t, ollama.StructuredOutputs) {
+		assert.True(t, *ollama.StructuredOutputs)
+	}
+}
+
+func TestNewProvider(t *testing.T) {
+	orgID := uuid.New()
+
+	previous := config
+	defer func() { config = previous }()
+
+	config = Config{
+		Token:           "token",
+		DefaultProvider: "openai",
+		Providers: map[string]ProviderConfig{
+			"openai":   {Kind: ProviderOpenAI},
+			"ollama":   {Kind: ProviderLocal, Models: Models{FastModel: "mistral"}},
+			"fixtures": {Kind: ProviderFixture},
+		},
+		Organizations: map[string]string{orgID.String(): "ollama"},
+	}
+
+	tests := []struct {
+		name     string
+		provider string
+		assert   func(*testing.T, Provider)
+	}{
+		{
+			name:     "openai inherits the global token",
+			provider: "openai",
+			assert: func(t *testing.T, p Provider) {
+				client := p.(*OpenAIClient)
+				assert.Equal(t, "token", client.Token)
+				assert.Equal(t, AIModel("gpt-4o"), client.model(FastModel))
+			},
+		},
+		{
+			name:     "local endpoint",
+			provider: "ollama",
+			assert: func(t *testing.T, p Provider) {
+				client := p.(*OpenAIClient)
+				assert.Equal(t, defaultLocalBaseURL, client.BaseURL)
+				assert.Equal(t, AIModel("mistral"), client.model(FastModel))
+				assert.Equal(t, AIModel("nomic-embed-text"), client.model(EmbeddingModel))
+			},
+		},
+		{
+			name:     "fixture",
+			provider: "fixtures",
+			assert: func(t *testing.T, p Provider) {
+				assert.IsType(t, &FixtureProvider{}, p)
+			},
+		},
+		{
+			name:     "unknown falls back to openai",
+			provider: "nope",
+			assert: func(t *testing.T, p Provider) {
+				assert.IsType(t, &OpenAIClient{}, p)
+			},
+		},
+	}
+
+	gctx := golly.NewContext(context.Background())
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.assert(t, NewProvider(gctx, tc.provider))
+		})
+	}
+
+	t.Run("organization override", func(t *testing.T) {
+		ctx := identity.ToContext(gctx, identity.Identity{OrganizationID: orgID})
+		assert.Equal(t, "ollama", ProviderNameForContext(ctx))
+
+		ctx = identity.ToContext(gctx, identity.Identity{OrganizationID: uuid.New()})
+		assert.Equal(t, "openai", ProviderNameForContext(ctx))
+	})
+}
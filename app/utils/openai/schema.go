@@ -0,0 +1,352 @@
+package openai
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/golly-go/golly"
+)
+
+const (
+	SchemaString  = "string"
+	SchemaInteger = "integer"
+	SchemaNumber  = "number"
+	SchemaBoolean = "boolean"
+	SchemaArray   = "array"
+	SchemaObject  = "object"
+)
+
+var (
+	timeType = reflect.TypeOf(time.Time{})
+)
+
+// Schema is the subset of JSON Schema we generate from prompt structs,
+// field tags control it:
+//
+//	ai:"description"   description of the field, ai:"-" skips it
+//	enum:"a,b,c"       allowed values
+//	min:"1" max:"5"    item limits for arrays, value limits for numbers
+type Schema struct {
+	Type        string `json:"type"`
+	Description string `json:"description,omitempty"`
+
+	Properties           map[string]*Schema `json:"properties,omitempty"`
+	Required             []string           `json:"required,omitempty"`
+	AdditionalProperties *bool              `json:"additionalProperties,omitempty"`
+
+	Items *Schema  `json:"items,omitempty"`
+	Enum  []string `json:"enum,omitempty"`
+
+	MinItems *int     `json:"minItems,omitempty"`
+	MaxItems *int     `json:"maxItems,omitempty"`
+	Minimum  *float64 `json:"minimum,omitempty"`
+	Maximum  *float64 `json:"maximum,omitempty"`
+}
+
+// SchemaFor builds the schema of a prompt (or any struct) from its tags
+func SchemaFor(v any) *Schema {
+	t := reflect.TypeOf(v)
+	if t == nil {
+		return &Schema{Type: SchemaObject}
+	}
+
+	return schemaForType(t)
+}
+
+func schemaForType(t reflect.Type) *Schema {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	if t == timeType {
+		return &Schema{Type: SchemaString}
+	}
+
+	switch t.Kind() {
+	case reflect.String:
+		return &Schema{Type: SchemaString}
+	case reflect.Bool:
+		return &Schema{Type: SchemaBoolean}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return &Schema{Type: SchemaInteger}
+	case reflect.Float32, reflect.Float64:
+		return &Schema{Type: SchemaNumber}
+	case reflect.Slice, reflect.Array:
+		return &Schema{Type: SchemaArray, Items: schemaForType(t.Elem())}
+	case reflect.Struct:
+		schema := &Schema{Type: SchemaObject, Properties: map[string]*Schema{}, AdditionalProperties: new(bool)}
+		addStructFields(schema, t)
+		return schema
+	}
+
+	return &Schema{Type: SchemaObject}
+}
+
+func addStructFields(schema *Schema, t reflect.Type) {
+	for pos := 0; pos < t.NumField(); pos++ {
+		field := t.Field(pos)
+
+		aiTag := field.Tag.Get("ai")
+		jsonTag := strings.Split(field.Tag.Get("json"), ",")[0]
+
+		if !field.IsExported() || aiTag == "-" || jsonTag == "-" {
+			continue
+		}
+
+		// Embedded structs without a name are flattened like encoding/json does
+		if field.Anonymous && jsonTag == "" && field.Type.Kind() == reflect.Struct {
+			addStructFields(schema, field.Type)
+			continue
+		}
+
+		name := field.Name
+		if jsonTag != "" {
+			name = jsonTag
+		}
+
+		property := schemaForType(field.Type)
+		property.Description = aiTag
+
+		if enum := field.Tag.Get("enum"); enum != "" {
+			property.Enum = golly.Map(strings.Split(enum, ","), strings.TrimSpace)
+		}
+
+		applyLimit(property, field.Tag.Get("min"), true)
+		applyLimit(property, field.Tag.Get("max"), false)
+
+		schema.Properties[name] = property
+		schema.Required = append(schema.Required, name)
+	}
+}
+
+func applyLimit(schema *Schema, tag string, minimum bool) {
+	if tag == "" {
+		return
+	}
+
+	value, err := strconv.ParseFloat(tag, 64)
+	if err != nil {
+		return
+	}
+
+	switch schema.Type {
+	case SchemaArray:
+		n := int(value)
+		if minimum {
+			schema.MinItems = &n
+		} else {
+			schema.MaxItems = &n
+		}
+	case SchemaInteger, SchemaNumber:
+		if minimum {
+			schema.Minimum = &value
+		} else {
+			schema.Maximum = &value
+		}
+	}
+}
+
+// Strict returns a copy that only uses the keywords OpenAI structured
+// outputs accept, limits it does not enforce are moved into the
+// description so the model still sees them (we validate them ourselves)
+func (s *Schema) Strict() *Schema {
+	if s == nil {
+		return nil
+	}
+
+	cp := *s
+	cp.MinItems, cp.MaxItems, cp.Minimum, cp.Maximum = nil, nil, nil, nil
+
+	limits := []string{}
+	if s.MinItems != nil {
+		limits = append(limits, fmt.Sprintf("at least %d items", *s.MinItems))
+	}
+	if s.MaxItems != nil {
+		limits = append(limits, fmt.Sprintf("at most %d items", *s.MaxItems))
+	}
+	if s.Minimum != nil {
+		limits = append(limits, fmt.Sprintf("minimum %v", *s.Minimum))
+	}
+	if s.Maximum != nil {
+		limits = append(limits, fmt.Sprintf("maximum %v", *s.Maximum))
+	}
+
+	if len(limits) > 0 {
+		cp.Description = strings.TrimSpace(cp.Description + " (" + strings.Join(limits, ", ") + ")")
+	}
+
+	cp.Items = s.Items.Strict()
+
+	if s.Properties != nil {
+		cp.Properties = make(map[string]*Schema, len(s.Properties))
+		for name, property := range s.Properties {
+			cp.Properties[name] = property.Strict()
+		}
+	}
+
+	return &cp
+}
+
+// Example returns a value that satisfies the schema, the fixture
+// provider answers with it when it has nothing better
+func (s *Schema) Example() any {
+	if s == nil {
+		return nil
+	}
+
+	if len(s.Enum) > 0 {
+		return s.Enum[0]
+	}
+
+	switch s.Type {
+	case SchemaString:
+		return golly.Coalesce(s.Description, "string")
+	case SchemaBoolean:
+		return false
+	case SchemaInteger, SchemaNumber:
+		if s.Minimum != nil {
+			return *s.Minimum
+		}
+		return 0
+	case SchemaArray:
+		n := 1
+		if s.MinItems != nil {
+			n = *s.MinItems
+		}
+		if s.MaxItems != nil && *s.MaxItems < n {
+			n = *s.MaxItems
+		}
+
+		items := make([]any, n)
+		for pos := range items {
+			items[pos] = s.Items.Example()
+		}
+		return items
+	}
+
+	ret := map[string]any{}
+	for name, property := range s.Properties {
+		ret[name] = property.Example()
+	}
+	return ret
+}
+
+type SchemaValidationError struct {
+	Errors []string
+}
+
+func (e SchemaValidationError) Error() string {
+	return "response does not match schema: " + strings.Join(e.Errors, "; ")
+}
+
+// Validate checks a JSON document against the schema
+func (s *Schema) Validate(document []byte) error {
+	decoder := json.NewDecoder(bytes.NewReader(document))
+	decoder.UseNumber()
+
+	var value any
+	if err := decoder.Decode(&value); err != nil {
+		return SchemaValidationError{Errors: []string{"invalid JSON: " + err.Error()}}
+	}
+
+	errs := s.validate("$", value)
+	if len(errs) > 0 {
+		slices.Sort(errs)
+		return SchemaValidationError{Errors: errs}
+	}
+
+	return nil
+}
+
+func (s *Schema) validate(path string, value any) (errs []string) {
+	if s == nil {
+		return nil
+	}
+
+	switch s.Type {
+	case SchemaObject:
+		obj, ok := value.(map[string]any)
+		if !ok {
+			return []string{fmt.Sprintf("%s must be an object", path)}
+		}
+
+		for _, name := range s.Required {
+			if _, found := obj[name]; !found {
+				errs = append(errs, fmt.Sprintf("%s.%s is required", path, name))
+			}
+		}
+
+		for name, v := range obj {
+			property, found := s.Properties[name]
+			if !found {
+				if s.AdditionalProperties != nil && !*s.AdditionalProperties {
+					errs = append(errs, fmt.Sprintf("%s.%s is not allowed", path, name))
+				}
+				continue
+			}
+			errs = append(errs, property.validate(path+"."+name, v)...)
+		}
+
+	case SchemaArray:
+		items, ok := value.([]any)
+		if !ok {
+			return []string{fmt.Sprintf("%s must be an array", path)}
+		}
+
+		if s.MinItems != nil && len(items) < *s.MinItems {
+			errs = append(errs, fmt.Sprintf("%s must have at least %d items", path, *s.MinItems))
+		}
+
+		if s.MaxItems != nil && len(items) > *s.MaxItems {
+			errs = append(errs, fmt.Sprintf("%s must have at most %d items", path, *s.MaxItems))
+		}
+
+		for pos, item := range items {
+			errs = append(errs, s.Items.validate(fmt.Sprintf("%s[%d]", path, pos), item)...)
+		}
+
+	case SchemaString:
+		str, ok := value.(string)
+		if !ok {
+			return []string{fmt.Sprintf("%s must be a string", path)}
+		}
+
+		if len(s.Enum) > 0 && !slices.Contains(s.Enum, str) {
+			errs = append(errs, fmt.Sprintf("%s must be one of %s", path, strings.Join(s.Enum, ", ")))
+		}
+
+	case SchemaBoolean:
+		if _, ok := value.(bool); !ok {
+			return []string{fmt.Sprintf("%s must be a boolean", path)}
+		}
+
+	case SchemaInteger, SchemaNumber:
+		number, ok := value.(json.Number)
+		if !ok {
+			return []string{fmt.Sprintf("%s must be a %s", path, s.Type)}
+		}
+
+		if s.Type == SchemaInteger {
+			if _, err := number.Int64(); err != nil {
+				return []string{fmt.Sprintf("%s must be an integer", path)}
+			}
+		}
+
+		f, _ := number.Float64()
+		if s.Minimum != nil && f < *s.Minimum {
+			errs = append(errs, fmt.Sprintf("%s must be >= %v", path, *s.Minimum))
+		}
+		if s.Maximum != nil && f > *s.Maximum {
+			errs = append(errs, fmt.Sprintf("%s must be <= %v", path, *s.Maximum))
+		}
+	}
+
+	return errs
+}
@@ -0,0 +1,177 @@
+package openai
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/golly-go/golly"
+	"github.com/stretchr/testify/assert"
+)
+
+type schemaTestItem struct {
+	Item string `json:"item" ai:"string action item"`
+}
+
+type schemaTestPrompt struct {
+	CompletionPromptBase `json:"-"`
+
+	Summary   string           `json:"summary" ai:"string summary"`
+	Sentiment string           `json:"sentiment" enum:"positive, neutral, negative"`
+	Score     int              `json:"score" min:"1" max:"5"`
+	Items     []schemaTestItem `json:"items" max:"2"`
+	Flagged   bool             `json:"flagged"`
+
+	Ignored string `json:"ignored" ai:"-"`
+	private string
+}
+
+func (schemaTestPrompt) Scenario(golly.Context) []string { return []string{"Test the schema"} }
+
+func TestSchemaFor(t *testing.T) {
+	schema := SchemaFor(&schemaTestPrompt{})
+
+	assert.Equal(t, SchemaObject, schema.Type)
+	assert.ElementsMatch(t, []string{"summary", "sentiment", "score", "items", "flagged"}, schema.Required)
+	assert.False(t, *schema.AdditionalProperties)
+
+	assert.Equal(t, "string summary", schema.Properties["summary"].Description)
+	assert.Equal(t, []string{"positive", "neutral", "negative"}, schema.Properties["sentiment"].Enum)
+
+	assert.Equal(t, SchemaInteger, schema.Properties["score"].Type)
+	assert.Equal(t, 1.0, *schema.Properties["score"].Minimum)
+	assert.Equal(t, 5.0, *schema.Properties["score"].Maximum)
+
+	items := schema.Properties["items"]
+	assert.Equal(t, SchemaArray, items.Type)
+	assert.Equal(t, 2, *items.MaxItems)
+	assert.Equal(t, SchemaObject, items.Items.Type)
+	assert.Equal(t, []string{"item"}, items.Items.Required)
+
+	// limits the API does not support move into the description
+	strict := schema.Strict()
+	assert.Nil(t, strict.Properties["items"].MaxItems)
+	assert.Contains(t, strict.Properties["items"].Description, "at most 2 items")
+	assert.NotNil(t, schema.Properties["items"].MaxItems)
+}
+
+func TestSchema_Validate(t *testing.T) {
+	schema := SchemaFor(schemaTestPrompt{})
+
+	tests := []struct {
+		name     string
+		document string
+		errors   []string
+	}{
+		{
+			name:     "valid",
+			document: `{"summary": "ok", "sentiment": "neutral", "score": 3, "items": [{"item": "a"}], "flagged": false}`,
+		},
+		{
+			name:     "invalid json",
+			document: `{"summary": `,
+			errors:   []string{"invalid JSON: unexpected EOF"},
+		},
+		{
+			name:     "missing and extra fields",
+			document: `{"summary": "ok", "sentiment": "neutral", "score": 3, "items": [], "other": 1}`,
+			errors:   []string{"$.flagged is required", "$.other is not allowed"},
+		},
+		{
+			name:     "enum, limits and types",
+			document: `{"summary": 1, "sentiment": "angry", "score": 9, "items": [{"item": "a"}, {"item": "b"}, {"item": 3}], "flagged": "no"}`,
+			errors: []string{
+				"$.flagged must be a boolean",
+				"$.items must have at most 2 items",
+				"$.items[2].item must be a string",
+				"$.score must be <= 5",
+				"$.sentiment must be one of positive, neutral, negative",
+				"$.summary must be a string",
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			err := schema.Validate([]byte(tc.document))
+			if tc.errors == nil {
+				assert.NoError(t, err)
+				return
+			}
+
+			var verr SchemaValidationError
+			assert.ErrorAs(t, err, &verr)
+			assert.Equal(t, tc.errors, verr.Errors)
+		})
+	}
+
+	// the example always validates
+	example, _ := json.Marshal(schema.Example())
+	assert.NoError(t, schema.Validate(example))
+}
+
+type repairProvider struct {
+	FixtureProvider
+
+	responses []string
+	payloads  []CompletionPayload
+}
+
+func (rp *repairProvider) Completions(gctx golly.Context, payload CompletionPayload) (CompletionResponse, error) {
+	rp.payloads = append(rp.payloads, payload)
+
+	content := rp.responses[0]
+	if len(rp.responses) > 1 {
+		rp.responses = rp.responses[1:]
+	}
+
+	return CompletionResponse{Choices: []Choice{{Message: Message{Role: RoleAI, Content: content}}}}, nil
+}
+
+func TestCompletion_Repair(t *testing.T) {
+	gctx := golly.NewContext(context.Background())
+
+	valid := `{"summary": "ok", "sentiment": "positive", "score": 4, "items": [], "flagged": true}`
+	invalid := `{"summary": "ok", "sentiment": "great", "score": 4, "items": [], "flagged": true}`
+
+	t.Run("repairs an invalid response", func(t *testing.T) {
+		provider := &repairProvider{responses: []string{invalid, valid}}
+
+		result, err := Completion(gctx, provider, &schemaTestPrompt{})
+		assert.NoError(t, err)
+		assert.Equal(t, "positive", result.Sentiment)
+
+		if assert.Len(t, provider.payloads, 2) {
+			repair := provider.payloads[1].Messages
+			assert.Equal(t, invalid, repair[len(repair)-2].Content)
+			assert.Contains(t, repair[len(repair)-1].Content, "$.sentiment must be one of")
+		}
+	})
+
+	t.Run("gives up after the repair attempts", func(t *testing.T) {
+		provider := &repairProvider{responses: []string{invalid}}
+
+		_, err := Completion(gctx, provider, &schemaTestPrompt{})
+		assert.ErrorContains(t, err, "does not match schema")
+		assert.Len(t, provider.payloads, defaultSchemaRepairs+1)
+	})
+}
+
+func TestOpenAIClient_DisableStructuredOutputs(t *testing.T) {
+	gctx := golly.NewContext(context.Background())
+
+	payload, err := buildCompletionPayload(gctx, &schemaTestPrompt{})
+	assert.NoError(t, err)
+	assert.Equal(t, formatJSONSchema, payload.Format.Type)
+
+	b, err := json.Marshal(payload)
+	assert.NoError(t, err)
+	assert.Contains(t, string(b), `"strict":true`)
+	assert.NotContains(t, string(b), `maxItems`)
+
+	local := NewLocalClient(gctx, ProviderConfig{})
+	assert.True(t, local.DisableStructuredOutputs)
+
+	enabled := true
+	assert.False(t, NewLocalClient(gctx, ProviderConfig{StructuredOutputs: &enabled}).DisableStructuredOutputs)
+}
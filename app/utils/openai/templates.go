@@ -22,3 +22,11 @@ func (b Template) Generate(bindings map[string]interface{}) (string, error) {
 
 	return strings.ReplaceAll(strings.ReplaceAll(out, "\t", " "), "\n\n", "\n"), nil
 }
+
+// Validate checks the template parses
+func (b Template) Validate() error {
+	if _, err := engine.ParseString(string(b)); err != nil {
+		return err
+	}
+	return nil
+}
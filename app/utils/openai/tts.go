@@ -26,9 +26,9 @@ func (oai OpenAIClient) TTS(ctx golly.Context, filePath string, voice TTSVoice,
 			Infof("TTS Request took %s", time.Since(start))
 	}(time.Now())
 
-	params := map[string]any{"model": TTSModel, "input": text, "voice": voice}
+	params := map[string]any{"model": oai.model(TTSModel), "input": text, "voice": voice}
 
-	b, err := oai.request(ctx, openAITTSURL, params)
+	b, err := oai.request(ctx, oai.url(ttsPath), params)
 	if err != nil {
 		return err
 	}
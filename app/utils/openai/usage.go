@@ -0,0 +1,113 @@
+package openai
+
+import (
+	"reflect"
+
+	"github.com/golly-go/golly"
+	"github.com/google/uuid"
+	"github.com/mitchrodrigues/talent-review-backend/app/utils/identity"
+)
+
+type UsageKind string
+
+const (
+	UsageCompletion UsageKind = "completion"
+	UsageEmbedding  UsageKind = "embedding"
+)
+
+// UsageRecord is handed to the configured UsageRecorder after every
+// successful completion or embedding call
+type UsageRecord struct {
+	OrganizationID uuid.UUID
+	UserID         uuid.UUID
+
+	Kind       UsageKind
+	Model      AIModel
+	PromptType string
+
+	// Cache is hit when the response came from the cache, no tokens
+	// were spent on it
+	Cache CacheStatus
+
+	Usage
+}
+
+type UsageRecorder func(golly.Context, UsageRecord)
+
+// MeteredProvider wraps a provider and reports the token usage of
+// every call to Recorder
+type MeteredProvider struct {
+	Provider
+
+	Recorder UsageRecorder
+}
+
+func NewMeteredProvider(provider Provider, recorder UsageRecorder) MeteredProvider {
+	return MeteredProvider{Provider: provider, Recorder: recorder}
+}
+
+func (mp MeteredProvider) Completions(gctx golly.Context, payload CompletionPayload) (CompletionResponse, error) {
+	resp, err := mp.Provider.Completions(gctx, payload)
+	if err != nil {
+		return resp, err
+	}
+
+	mp.record(gctx, UsageRecord{
+		Kind:       UsageCompletion,
+		Model:      golly.Coalesce(resp.Model, payload.Model),
+		PromptType: payload.PromptType,
+		Cache:      resp.Cache,
+		Usage:      resp.Usage,
+	})
+
+	return resp, nil
+}
+
+func (mp MeteredProvider) Embeddings(gctx golly.Context, text string) (EmbeddingResponse, error) {
+	resp, err := mp.Provider.Embeddings(gctx, text)
+	if err != nil {
+		return resp, err
+	}
+
+	mp.record(gctx, UsageRecord{
+		Kind:  UsageEmbedding,
+		Model: golly.Coalesce(AIModel(resp.Model), EmbeddingModel),
+		Cache: resp.Cache,
+		Usage: resp.Usage,
+	})
+
+	return resp, nil
+}
+
+func (mp MeteredProvider) record(gctx golly.Context, record UsageRecord) {
+	if mp.Recorder == nil {
+		return
+	}
+
+	ident := identity.FromContext(gctx)
+
+	record.OrganizationID = ident.OrganizationID
+	record.UserID = ident.UID
+
+	if record.Cache == CacheHit {
+		record.Usage = Usage{}
+	}
+
+	mp.Recorder(gctx, record)
+}
+
+// PromptTypeName returns the bare type name of a prompt (SummarizeFeedbackPrompt)
+func PromptTypeName(prompt any) string {
+	t := reflect.TypeOf(prompt)
+	for t != nil && t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	if t == nil {
+		return ""
+	}
+
+	return t.Name()
+}
+
+var _ Provider = MeteredProvider{}
@@ -0,0 +1,52 @@
+package openai
+
+import (
+	"context"
+	"testing"
+
+	"github.com/golly-go/golly"
+	"github.com/mitchrodrigues/talent-review-backend/app/utils/identity"
+	"github.com/stretchr/testify/assert"
+)
+
+type usageProvider struct {
+	FixtureProvider
+}
+
+func (usageProvider) Completions(gctx golly.Context, payload CompletionPayload) (CompletionResponse, error) {
+	return CompletionResponse{
+		Model:   "gpt-4o",
+		Choices: []Choice{{Message: Message{Content: `{"aiField": "value"}`}}},
+		Usage:   Usage{PromptTokens: 10, CompletionTokens: 5, TotalTokens: 15},
+	}, nil
+}
+
+func TestMeteredProvider(t *testing.T) {
+	ident, gctx := identity.NewTestIdentity(golly.NewContext(context.Background()))
+
+	var records []UsageRecord
+
+	provider := NewMeteredProvider(&usageProvider{}, func(_ golly.Context, record UsageRecord) {
+		records = append(records, record)
+	})
+
+	_, err := Completion(gctx, provider, &TestPrompt{})
+	assert.NoError(t, err)
+
+	_, err = provider.Embeddings(gctx, "text")
+	assert.NoError(t, err)
+
+	if assert.Len(t, records, 2) {
+		assert.Equal(t, UsageRecord{
+			OrganizationID: ident.OrganizationID,
+			UserID:         ident.UID,
+			Kind:           UsageCompletion,
+			Model:          "gpt-4o",
+			PromptType:     "TestPrompt",
+			Usage:          Usage{PromptTokens: 10, CompletionTokens: 5, TotalTokens: 15},
+		}, records[0])
+
+		assert.Equal(t, UsageEmbedding, records[1].Kind)
+		assert.Equal(t, AIModel("fixture"), records[1].Model)
+	}
+}
@@ -0,0 +1,138 @@
+package ratelimit
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// Limiter is a keyed token bucket, each key (organization, feedback code, ...)
+// gets its own bucket refilled at Rate tokens per second up to Burst
+type Limiter struct {
+	Rate  float64
+	Burst int
+
+	mu        sync.Mutex
+	buckets   map[string]*bucket
+	lastSweep time.Time
+
+	now func() time.Time
+}
+
+type bucket struct {
+	tokens float64
+	last   time.Time
+}
+
+// New returns a limiter allowing rate events per second with the given burst
+func New(rate float64, burst int) *Limiter {
+	if burst < 1 {
+		burst = 1
+	}
+
+	return &Limiter{
+		Rate:    rate,
+		Burst:   burst,
+		buckets: map[string]*bucket{},
+		now:     time.Now,
+	}
+}
+
+// PerMinute is a helper for the common case of configuring a limit per minute
+func PerMinute(count int, burst int) *Limiter {
+	return New(float64(count)/60.0, burst)
+}
+
+// Allow takes a token for key if one is available
+func (l *Limiter) Allow(key string) bool {
+	return l.reserve(key, false) == 0
+}
+
+// Wait blocks until a token for key is available or the context is done,
+// the token is given back when the context is done first
+func (l *Limiter) Wait(ctx context.Context, key string) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	delay := l.reserve(key, true)
+	if delay == 0 {
+		return nil
+	}
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		l.release(key)
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// sweep drops the buckets that refilled completely, they are no different
+// from a new bucket and would otherwise be kept forever. It runs at most
+// once per refill period
+func (l *Limiter) sweep(now time.Time) {
+	refill := time.Duration(float64(l.Burst) / l.Rate * float64(time.Second))
+	if now.Sub(l.lastSweep) < refill {
+		return
+	}
+	l.lastSweep = now
+
+	for key, b := range l.buckets {
+		if b.tokens+now.Sub(b.last).Seconds()*l.Rate >= float64(l.Burst) {
+			delete(l.buckets, key)
+		}
+	}
+}
+
+// release gives back a token reserved by Wait
+func (l *Limiter) release(key string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if b, ok := l.buckets[key]; ok {
+		b.tokens = min(b.tokens+1, float64(l.Burst))
+	}
+}
+
+// reserve returns how long the caller has to wait for a token, when commit
+// is set the token is taken even if it is in the future
+func (l *Limiter) reserve(key string, commit bool) time.Duration {
+	if l == nil || l.Rate <= 0 {
+		return 0
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := l.now()
+	l.sweep(now)
+
+	b, ok := l.buckets[key]
+	if !ok {
+		b = &bucket{tokens: float64(l.Burst), last: now}
+		l.buckets[key] = b
+	}
+
+	b.tokens += now.Sub(b.last).Seconds() * l.Rate
+	if b.tokens > float64(l.Burst) {
+		b.tokens = float64(l.Burst)
+	}
+	b.last = now
+
+	if b.tokens >= 1 {
+		b.tokens--
+		return 0
+	}
+
+	delay := time.Duration((1 - b.tokens) / l.Rate * float64(time.Second))
+	if commit {
+		b.tokens--
+	}
+
+	return delay
+}
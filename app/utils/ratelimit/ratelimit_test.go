@@ -0,0 +1,79 @@
+package ratelimit
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLimiter_Allow(t *testing.T) {
+	now := time.Now()
+
+	limiter := New(1, 2)
+	limiter.now = func() time.Time { return now }
+
+	assert.True(t, limiter.Allow("org-a"))
+	assert.True(t, limiter.Allow("org-a"))
+	assert.False(t, limiter.Allow("org-a"))
+
+	// buckets are independent per key
+	assert.True(t, limiter.Allow("org-b"))
+
+	now = now.Add(time.Second)
+	assert.True(t, limiter.Allow("org-a"))
+	assert.False(t, limiter.Allow("org-a"))
+}
+
+func TestLimiter_Wait(t *testing.T) {
+	now := time.Now()
+
+	limiter := New(1, 1)
+	limiter.now = func() time.Time { return now }
+
+	assert.NoError(t, limiter.Wait(context.Background(), "org"))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	assert.ErrorIs(t, limiter.Wait(ctx, "org"), context.Canceled)
+
+	// a cancelled wait does not take the token of the next caller
+	ctx, cancel = context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	assert.ErrorIs(t, limiter.Wait(ctx, "org"), context.DeadlineExceeded)
+
+	now = now.Add(time.Second)
+	assert.True(t, limiter.Allow("org"))
+}
+
+func TestLimiter_EvictsIdleBuckets(t *testing.T) {
+	now := time.Now()
+
+	limiter := New(1, 2)
+	limiter.now = func() time.Time { return now }
+
+	assert.True(t, limiter.Allow("idle"))
+	assert.True(t, limiter.Allow("busy"))
+	assert.True(t, limiter.Allow("busy"))
+
+	now = now.Add(1500 * time.Millisecond)
+	assert.True(t, limiter.Allow("busy"))
+	now = now.Add(time.Second)
+	assert.True(t, limiter.Allow("busy"))
+
+	// idle refilled and was dropped, busy is still short of tokens
+	assert.NotContains(t, limiter.buckets, "idle")
+	assert.Contains(t, limiter.buckets, "busy")
+}
+
+func TestLimiter_Disabled(t *testing.T) {
+	var limiter *Limiter
+
+	for i := 0; i < 10; i++ {
+		assert.True(t, limiter.Allow("org"))
+	}
+	assert.True(t, New(0, 1).Allow("org"))
+}
@@ -0,0 +1,3 @@
+-- Down Migration 20240801071722545101 add_role_to_users
+
+ALTER TABLE users DROP COLUMN role;
@@ -0,0 +1,17 @@
+-- Up Migration 20240801071722545101 add_role_to_users
+
+-- beginStatement
+ALTER TABLE users ADD COLUMN role VARCHAR(32) NOT NULL DEFAULT 'member'
+-- endStatement
+
+-- beginStatement
+-- Only admins can grant roles, the earliest user of every organization
+-- becomes its admin so existing organizations are not locked out
+UPDATE users SET role = 'admin'
+WHERE id IN (
+    SELECT DISTINCT ON (organization_id) id
+    FROM users
+    WHERE deleted_at IS NULL
+    ORDER BY organization_id, created_at, id
+)
+-- endStatement
@@ -0,0 +1,3 @@
+-- Down Migration 20240801071722545187 add_settings_to_organizations
+
+ALTER TABLE organizations DROP COLUMN settings;
@@ -0,0 +1,3 @@
+-- Up Migration 20240801071722545187 add_settings_to_organizations
+
+ALTER TABLE organizations ADD COLUMN settings jsonb NOT NULL DEFAULT '{}';
@@ -0,0 +1,4 @@
+-- Down Migration 20240801071722545240 create_llm_usages
+
+DROP TABLE IF EXISTS llm_usage_monthlies;
+DROP TABLE IF EXISTS llm_usages;
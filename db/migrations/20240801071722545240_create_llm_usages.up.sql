@@ -0,0 +1,43 @@
+-- Up Migration 20240801071722545240 create_llm_usages
+
+-- beginStatement
+CREATE TABLE llm_usages (
+    id              UUID NOT NULL,
+    organization_id UUID NOT NULL,
+    user_id         UUID,
+
+    kind        VARCHAR(32),
+    model       VARCHAR(255),
+    prompt_type VARCHAR(255),
+
+    prompt_tokens     INT NOT NULL DEFAULT 0,
+    completion_tokens INT NOT NULL DEFAULT 0,
+    total_tokens      INT NOT NULL DEFAULT 0,
+
+    created_at TIMESTAMP WITH TIME ZONE,
+    updated_at TIMESTAMP WITH TIME ZONE,
+    deleted_at TIMESTAMP WITH TIME ZONE,
+
+    PRIMARY KEY (id)
+)
+-- endStatement
+
+-- beginStatement
+CREATE INDEX llm_usages_organization_idx ON llm_usages (organization_id, created_at)
+-- endStatement
+
+-- beginStatement
+CREATE TABLE llm_usage_monthlies (
+    organization_id UUID NOT NULL,
+    month           DATE NOT NULL,
+
+    prompt_tokens     BIGINT NOT NULL DEFAULT 0,
+    completion_tokens BIGINT NOT NULL DEFAULT 0,
+    total_tokens      BIGINT NOT NULL DEFAULT 0,
+    requests          INT NOT NULL DEFAULT 0,
+
+    updated_at TIMESTAMP WITH TIME ZONE,
+
+    PRIMARY KEY (organization_id, month)
+)
+-- endStatement
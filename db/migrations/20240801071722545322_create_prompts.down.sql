@@ -0,0 +1,4 @@
+-- Down Migration 20240801071722545322 create_prompts
+
+ALTER TABLE feedback_summaries DROP COLUMN IF EXISTS prompt_versions;
+DROP TABLE IF EXISTS prompts;
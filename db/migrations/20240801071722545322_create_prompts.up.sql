@@ -0,0 +1,33 @@
+-- Up Migration 20240801071722545322 create_prompts
+
+-- beginStatement
+CREATE TABLE prompts (
+    id              UUID NOT NULL,
+    organization_id UUID NOT NULL DEFAULT '00000000-0000-0000-0000-000000000000',
+
+    name    VARCHAR(255) NOT NULL,
+    version INT NOT NULL,
+
+    template TEXT,
+    rules    jsonb NOT NULL DEFAULT '[]',
+    scenario jsonb NOT NULL DEFAULT '[]',
+    tone     VARCHAR(255),
+
+    active       BOOLEAN NOT NULL DEFAULT FALSE,
+    activated_at TIMESTAMP WITH TIME ZONE,
+
+    created_at TIMESTAMP WITH TIME ZONE,
+    updated_at TIMESTAMP WITH TIME ZONE,
+    deleted_at TIMESTAMP WITH TIME ZONE,
+
+    PRIMARY KEY (id)
+)
+-- endStatement
+
+-- beginStatement
+CREATE UNIQUE INDEX prompts_version_idx ON prompts (organization_id, name, version)
+-- endStatement
+
+-- beginStatement
+ALTER TABLE feedback_summaries ADD COLUMN prompt_versions jsonb NOT NULL DEFAULT '{}'
+-- endStatement
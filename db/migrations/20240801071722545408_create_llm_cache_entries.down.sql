@@ -0,0 +1,6 @@
+-- Down Migration 20240801071722545408 create_llm_cache_entries
+
+ALTER TABLE llm_usage_monthlies DROP COLUMN IF EXISTS cache_misses;
+ALTER TABLE llm_usage_monthlies DROP COLUMN IF EXISTS cache_hits;
+ALTER TABLE llm_usages DROP COLUMN IF EXISTS cache;
+DROP TABLE IF EXISTS llm_cache_entries;
@@ -0,0 +1,29 @@
+-- Up Migration 20240801071722545408 create_llm_cache_entries
+
+-- beginStatement
+CREATE TABLE llm_cache_entries (
+    cache_key  VARCHAR(64) NOT NULL,
+    value      TEXT NOT NULL,
+
+    expires_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE,
+
+    PRIMARY KEY (cache_key)
+)
+-- endStatement
+
+-- beginStatement
+CREATE INDEX llm_cache_entries_expires_idx ON llm_cache_entries (expires_at)
+-- endStatement
+
+-- beginStatement
+ALTER TABLE llm_usages ADD COLUMN cache VARCHAR(8)
+-- endStatement
+
+-- beginStatement
+ALTER TABLE llm_usage_monthlies ADD COLUMN cache_hits INT NOT NULL DEFAULT 0
+-- endStatement
+
+-- beginStatement
+ALTER TABLE llm_usage_monthlies ADD COLUMN cache_misses INT NOT NULL DEFAULT 0
+-- endStatement
@@ -0,0 +1,3 @@
+-- Down Migration 20240801071722545500 create_feedback_embeddings
+
+DROP TABLE IF EXISTS feedback_embeddings;
@@ -0,0 +1,36 @@
+-- Up Migration 20240801071722545500 create_feedback_embeddings
+
+-- beginStatement
+CREATE EXTENSION IF NOT EXISTS vector
+-- endStatement
+
+-- beginStatement
+CREATE TABLE feedback_embeddings (
+    id              UUID NOT NULL,
+    organization_id UUID NOT NULL,
+    feedback_id     UUID NOT NULL,
+    employee_id     UUID NOT NULL,
+
+    section         VARCHAR(32) NOT NULL,
+    content         TEXT NOT NULL,
+
+    -- Dimensions depend on the provider model, searches only compare
+    -- embeddings of the same model
+    model           VARCHAR(255) NOT NULL,
+    embedding       vector NOT NULL,
+
+    created_at      TIMESTAMP WITH TIME ZONE,
+    updated_at      TIMESTAMP WITH TIME ZONE,
+    deleted_at      TIMESTAMP WITH TIME ZONE,
+
+    PRIMARY KEY (id)
+)
+-- endStatement
+
+-- beginStatement
+CREATE INDEX feedback_embeddings_organization_model_idx ON feedback_embeddings (organization_id, model)
+-- endStatement
+
+-- beginStatement
+CREATE INDEX feedback_embeddings_feedback_idx ON feedback_embeddings (feedback_id)
+-- endStatement
@@ -0,0 +1,3 @@
+-- Down Migration 20240801071722545587 create_tara_conversations
+
+DROP TABLE IF EXISTS tara_conversations;
@@ -0,0 +1,24 @@
+-- Up Migration 20240801071722545587 create_tara_conversations
+
+-- beginStatement
+CREATE TABLE tara_conversations (
+    id              UUID NOT NULL,
+    version         INT NOT NULL DEFAULT 1,
+    organization_id UUID NOT NULL,
+    user_id         UUID NOT NULL,
+    employee_id     UUID NOT NULL,
+
+    title    VARCHAR(255),
+    messages jsonb NOT NULL DEFAULT '[]',
+
+    created_at TIMESTAMP WITH TIME ZONE,
+    updated_at TIMESTAMP WITH TIME ZONE,
+    deleted_at TIMESTAMP WITH TIME ZONE,
+
+    PRIMARY KEY (id)
+)
+-- endStatement
+
+-- beginStatement
+CREATE INDEX tara_conversations_user_employee_idx ON tara_conversations (organization_id, user_id, employee_id)
+-- endStatement
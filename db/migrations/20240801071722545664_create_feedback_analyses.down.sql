@@ -0,0 +1,3 @@
+-- Down Migration 20240801071722545664 create_feedback_analyses
+
+DROP TABLE IF EXISTS feedback_analyses;
@@ -0,0 +1,31 @@
+-- Up Migration 20240801071722545664 create_feedback_analyses
+
+-- beginStatement
+CREATE TABLE feedback_analyses (
+    id              UUID NOT NULL,
+    organization_id UUID NOT NULL,
+    employee_id     UUID NOT NULL,
+    feedback_id     UUID NOT NULL,
+
+    gendered_language     INT NOT NULL DEFAULT 0,
+    personality_critiques INT NOT NULL DEFAULT 0,
+    unsupported_claims    INT NOT NULL DEFAULT 0,
+
+    findings        jsonb NOT NULL DEFAULT '[]',
+    prompt_versions jsonb,
+
+    created_at TIMESTAMP WITH TIME ZONE,
+    updated_at TIMESTAMP WITH TIME ZONE,
+    deleted_at TIMESTAMP WITH TIME ZONE,
+
+    PRIMARY KEY (id)
+)
+-- endStatement
+
+-- beginStatement
+CREATE UNIQUE INDEX feedback_analyses_feedback_idx ON feedback_analyses (feedback_id)
+-- endStatement
+
+-- beginStatement
+CREATE INDEX feedback_analyses_organization_idx ON feedback_analyses (organization_id, created_at)
+-- endStatement
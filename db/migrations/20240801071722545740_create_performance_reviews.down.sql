@@ -0,0 +1,3 @@
+-- Down Migration 20240801071722545740 create_performance_reviews
+
+DROP TABLE IF EXISTS performance_reviews;
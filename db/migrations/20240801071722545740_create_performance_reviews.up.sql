@@ -0,0 +1,37 @@
+-- Up Migration 20240801071722545740 create_performance_reviews
+
+-- beginStatement
+CREATE TABLE performance_reviews (
+    id              UUID NOT NULL,
+    version         INT NOT NULL DEFAULT 1,
+    organization_id UUID NOT NULL,
+    employee_id     UUID NOT NULL,
+    author_id       UUID NOT NULL,
+    cycle_id        UUID,
+
+    status VARCHAR(32) NOT NULL,
+
+    summary  TEXT,
+    sections jsonb NOT NULL DEFAULT '[]',
+
+    rating           INT,
+    rating_rationale TEXT,
+
+    suggested_rating           INT,
+    suggested_rating_rationale TEXT,
+
+    prompt_versions jsonb,
+
+    finalized_at TIMESTAMP WITH TIME ZONE,
+
+    created_at TIMESTAMP WITH TIME ZONE,
+    updated_at TIMESTAMP WITH TIME ZONE,
+    deleted_at TIMESTAMP WITH TIME ZONE,
+
+    PRIMARY KEY (id)
+)
+-- endStatement
+
+-- beginStatement
+CREATE INDEX performance_reviews_employee_idx ON performance_reviews (organization_id, employee_id, created_at)
+-- endStatement
@@ -0,0 +1,3 @@
+-- Down Migration 20240801071722545812 create_competencies
+
+DROP TABLE IF EXISTS competencies;
@@ -0,0 +1,25 @@
+-- Up Migration 20240801071722545812 create_competencies
+
+-- beginStatement
+CREATE TABLE competencies (
+    id              UUID NOT NULL,
+    version         INT NOT NULL DEFAULT 1,
+    organization_id UUID NOT NULL,
+
+    name        VARCHAR(255) NOT NULL,
+    description TEXT,
+    track       VARCHAR(4) NOT NULL,
+
+    levels jsonb NOT NULL DEFAULT '[]',
+
+    created_at TIMESTAMP WITH TIME ZONE,
+    updated_at TIMESTAMP WITH TIME ZONE,
+    deleted_at TIMESTAMP WITH TIME ZONE,
+
+    PRIMARY KEY (id)
+)
+-- endStatement
+
+-- beginStatement
+CREATE INDEX competencies_organization_track_idx ON competencies (organization_id, track)
+-- endStatement
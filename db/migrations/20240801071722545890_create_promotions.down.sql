@@ -0,0 +1,3 @@
+-- Down Migration 20240801071722545890 create_promotions
+
+DROP TABLE IF EXISTS promotions;
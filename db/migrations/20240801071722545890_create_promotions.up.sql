@@ -0,0 +1,36 @@
+-- Up Migration 20240801071722545890 create_promotions
+
+-- beginStatement
+CREATE TABLE promotions (
+    id              UUID NOT NULL,
+    version         INT NOT NULL DEFAULT 1,
+    organization_id UUID NOT NULL,
+    employee_id     UUID NOT NULL,
+    proposer_id     UUID NOT NULL,
+
+    current_role_id UUID,
+    target_role_id  UUID NOT NULL,
+
+    justification   TEXT,
+    citations       jsonb NOT NULL DEFAULT '[]',
+    prompt_versions jsonb NOT NULL DEFAULT '{}',
+
+    effective_at TIMESTAMP WITH TIME ZONE NOT NULL,
+
+    approver_ids jsonb NOT NULL DEFAULT '[]',
+    approvals    jsonb NOT NULL DEFAULT '[]',
+
+    status     VARCHAR(16) NOT NULL,
+    decided_at TIMESTAMP WITH TIME ZONE,
+
+    created_at TIMESTAMP WITH TIME ZONE,
+    updated_at TIMESTAMP WITH TIME ZONE,
+    deleted_at TIMESTAMP WITH TIME ZONE,
+
+    PRIMARY KEY (id)
+)
+-- endStatement
+
+-- beginStatement
+CREATE INDEX promotions_organization_employee_idx ON promotions (organization_id, employee_id)
+-- endStatement
@@ -0,0 +1,3 @@
+-- Down Migration 20240801071722545967 create_calibration_sessions
+
+DROP TABLE IF EXISTS calibration_sessions;
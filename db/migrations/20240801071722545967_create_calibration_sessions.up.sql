@@ -0,0 +1,30 @@
+-- Up Migration 20240801071722545967 create_calibration_sessions
+
+-- beginStatement
+CREATE TABLE calibration_sessions (
+    id              UUID NOT NULL,
+    version         INT NOT NULL DEFAULT 1,
+    organization_id UUID NOT NULL,
+    cycle_id        UUID NOT NULL,
+    facilitator_id  UUID NOT NULL,
+
+    name VARCHAR(255) NOT NULL,
+
+    participant_ids jsonb NOT NULL DEFAULT '[]',
+    employee_ids    jsonb NOT NULL DEFAULT '[]',
+    adjustments     jsonb NOT NULL DEFAULT '[]',
+
+    status    VARCHAR(16) NOT NULL,
+    closed_at TIMESTAMP WITH TIME ZONE,
+
+    created_at TIMESTAMP WITH TIME ZONE,
+    updated_at TIMESTAMP WITH TIME ZONE,
+    deleted_at TIMESTAMP WITH TIME ZONE,
+
+    PRIMARY KEY (id)
+)
+-- endStatement
+
+-- beginStatement
+CREATE INDEX calibration_sessions_organization_cycle_idx ON calibration_sessions (organization_id, cycle_id)
+-- endStatement
@@ -0,0 +1,3 @@
+-- Down Migration 20240801071722546040 add_declined_at_to_feedbacks
+
+ALTER TABLE feedbacks DROP COLUMN IF EXISTS declined_at;
@@ -0,0 +1,5 @@
+-- Up Migration 20240801071722546040 add_declined_at_to_feedbacks
+
+-- beginStatement
+ALTER TABLE feedbacks ADD COLUMN declined_at TIMESTAMP WITH TIME ZONE;
+-- endStatement
@@ -0,0 +1,3 @@
+-- Down Migration 20240801071722546110 create_nominations
+
+DROP TABLE IF EXISTS nominations;
@@ -0,0 +1,34 @@
+-- Up Migration 20240801071722546110 create_nominations
+
+-- beginStatement
+CREATE TABLE nominations (
+    id              UUID NOT NULL,
+    version         INT NOT NULL DEFAULT 1,
+    organization_id UUID NOT NULL,
+    cycle_id        UUID NOT NULL,
+    employee_id     UUID NOT NULL,
+    nominator_id    UUID NOT NULL,
+    approver_id     UUID NOT NULL,
+
+    emails       jsonb NOT NULL DEFAULT '[]',
+    feedback_ids jsonb NOT NULL DEFAULT '[]',
+
+    status     VARCHAR(16) NOT NULL,
+    note       TEXT,
+    decided_at TIMESTAMP WITH TIME ZONE,
+
+    created_at TIMESTAMP WITH TIME ZONE,
+    updated_at TIMESTAMP WITH TIME ZONE,
+    deleted_at TIMESTAMP WITH TIME ZONE,
+
+    PRIMARY KEY (id)
+)
+-- endStatement
+
+-- beginStatement
+CREATE INDEX nominations_organization_cycle_idx ON nominations (organization_id, cycle_id)
+-- endStatement
+
+-- beginStatement
+CREATE INDEX nominations_employee_idx ON nominations (employee_id)
+-- endStatement
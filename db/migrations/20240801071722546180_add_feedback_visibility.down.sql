@@ -0,0 +1,5 @@
+-- Down Migration 20240801071722546180 add_feedback_visibility
+
+DROP INDEX IF EXISTS idx_feedbacks_aggregated_responses;
+ALTER TABLE feedbacks DROP COLUMN IF EXISTS visibility;
+ALTER TABLE cycles DROP COLUMN IF EXISTS feedback_visibility;
@@ -0,0 +1,13 @@
+-- Up Migration 20240801071722546180 add_feedback_visibility
+
+-- beginStatement
+ALTER TABLE feedbacks ADD COLUMN visibility VARCHAR(16) NOT NULL DEFAULT 'ATTRIBUTED';
+-- endStatement
+
+-- beginStatement
+CREATE INDEX idx_feedbacks_aggregated_responses ON feedbacks (employee_id, collection_end_at) WHERE visibility = 'AGGREGATED';
+-- endStatement
+
+-- beginStatement
+ALTER TABLE cycles ADD COLUMN feedback_visibility VARCHAR(16);
+-- endStatement
@@ -0,0 +1,3 @@
+-- Down Migration 20240801071722546250 create_feedback_releases
+
+DROP TABLE IF EXISTS feedback_releases;
@@ -0,0 +1,28 @@
+-- Up Migration 20240801071722546250 create_feedback_releases
+
+-- beginStatement
+CREATE TABLE feedback_releases (
+    id              UUID NOT NULL,
+    version         INT NOT NULL DEFAULT 1,
+    organization_id UUID NOT NULL,
+    employee_id     UUID NOT NULL,
+    manager_id      UUID NOT NULL,
+
+    feedback_ids jsonb NOT NULL DEFAULT '[]',
+    action_items jsonb NOT NULL DEFAULT '[]',
+    note         TEXT,
+
+    acknowledged_by UUID,
+    acknowledged_at TIMESTAMP WITH TIME ZONE,
+
+    created_at TIMESTAMP WITH TIME ZONE,
+    updated_at TIMESTAMP WITH TIME ZONE,
+    deleted_at TIMESTAMP WITH TIME ZONE,
+
+    PRIMARY KEY (id)
+)
+-- endStatement
+
+-- beginStatement
+CREATE INDEX feedback_releases_organization_employee_idx ON feedback_releases (organization_id, employee_id)
+-- endStatement
@@ -0,0 +1,4 @@
+-- Down Migration 20240801071722546320 create_organization_data_keys
+
+ALTER TABLE feedback_summaries ALTER COLUMN action_items TYPE jsonb USING action_items::jsonb;
+DROP TABLE IF EXISTS organization_data_keys;
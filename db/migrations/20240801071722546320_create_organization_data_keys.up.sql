@@ -0,0 +1,25 @@
+-- Up Migration 20240801071722546320 create_organization_data_keys
+
+-- beginStatement
+CREATE TABLE organization_data_keys (
+    id              UUID NOT NULL,
+    organization_id UUID NOT NULL,
+    master_key_id   VARCHAR(255) NOT NULL,
+    wrapped_key     BYTEA NOT NULL,
+    retired_at      TIMESTAMP WITH TIME ZONE,
+
+    created_at TIMESTAMP WITH TIME ZONE,
+    updated_at TIMESTAMP WITH TIME ZONE,
+    deleted_at TIMESTAMP WITH TIME ZONE,
+
+    PRIMARY KEY (id)
+)
+-- endStatement
+
+-- beginStatement
+CREATE INDEX organization_data_keys_organization_idx ON organization_data_keys (organization_id, retired_at, created_at)
+-- endStatement
+
+-- beginStatement
+ALTER TABLE feedback_summaries ALTER COLUMN action_items TYPE TEXT USING action_items::TEXT
+-- endStatement
@@ -0,0 +1,4 @@
+-- Down Migration 20240801071722546390 create_subject_keys
+
+ALTER TABLE employees DROP COLUMN erased_at;
+DROP TABLE IF EXISTS subject_keys;
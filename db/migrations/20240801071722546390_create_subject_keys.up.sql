@@ -0,0 +1,25 @@
+-- Up Migration 20240801071722546390 create_subject_keys
+
+-- beginStatement
+CREATE TABLE subject_keys (
+    id              UUID NOT NULL,
+    organization_id UUID NOT NULL,
+    master_key_id   VARCHAR(255),
+    wrapped_key     BYTEA,
+    erased_at       TIMESTAMP WITH TIME ZONE,
+
+    created_at TIMESTAMP WITH TIME ZONE,
+    updated_at TIMESTAMP WITH TIME ZONE,
+    deleted_at TIMESTAMP WITH TIME ZONE,
+
+    PRIMARY KEY (id)
+)
+-- endStatement
+
+-- beginStatement
+CREATE INDEX subject_keys_organization_idx ON subject_keys (organization_id)
+-- endStatement
+
+-- beginStatement
+ALTER TABLE employees ADD COLUMN erased_at TIMESTAMP WITH TIME ZONE
+-- endStatement
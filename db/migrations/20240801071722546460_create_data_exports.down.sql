@@ -0,0 +1,3 @@
+-- Down Migration 20240801071722546460 create_data_exports
+
+DROP TABLE IF EXISTS data_exports;
@@ -0,0 +1,30 @@
+-- Up Migration 20240801071722546460 create_data_exports
+
+-- beginStatement
+CREATE TABLE data_exports (
+    id              UUID NOT NULL,
+    version         INT NOT NULL DEFAULT 1,
+    organization_id UUID NOT NULL,
+    requested_by    UUID NOT NULL,
+    employee_id     UUID,
+    user_id         UUID,
+
+    status  VARCHAR(16) NOT NULL,
+    error   TEXT,
+    archive BYTEA,
+    size    INT NOT NULL DEFAULT 0,
+
+    completed_at TIMESTAMP WITH TIME ZONE,
+    expires_at   TIMESTAMP WITH TIME ZONE,
+
+    created_at TIMESTAMP WITH TIME ZONE,
+    updated_at TIMESTAMP WITH TIME ZONE,
+    deleted_at TIMESTAMP WITH TIME ZONE,
+
+    PRIMARY KEY (id)
+)
+-- endStatement
+
+-- beginStatement
+CREATE INDEX data_exports_organization_idx ON data_exports (organization_id, created_at)
+-- endStatement
@@ -0,0 +1,6 @@
+-- Down Migration 20240801071722546530 add_revision_to_prompts
+
+DROP INDEX prompts_revision_idx;
+UPDATE prompts SET version = revision;
+CREATE UNIQUE INDEX prompts_version_idx ON prompts (organization_id, name, version);
+ALTER TABLE prompts DROP COLUMN revision;
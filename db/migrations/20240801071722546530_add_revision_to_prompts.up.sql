@@ -0,0 +1,18 @@
+-- Up Migration 20240801071722546530 add_revision_to_prompts
+
+-- beginStatement
+-- version is the aggregate version, the prompt revision gets its own column
+ALTER TABLE prompts ADD COLUMN revision INT NOT NULL DEFAULT 1
+-- endStatement
+
+-- beginStatement
+UPDATE prompts SET revision = version
+-- endStatement
+
+-- beginStatement
+DROP INDEX prompts_version_idx
+-- endStatement
+
+-- beginStatement
+CREATE UNIQUE INDEX prompts_revision_idx ON prompts (organization_id, name, revision)
+-- endStatement
@@ -0,0 +1,3 @@
+-- Down Migration 20240801071722546600 add_applied_at_to_promotions
+
+ALTER TABLE promotions DROP COLUMN applied_at;
@@ -0,0 +1,10 @@
+-- Up Migration 20240801071722546600 add_applied_at_to_promotions
+
+-- beginStatement
+ALTER TABLE promotions ADD COLUMN applied_at TIMESTAMP WITH TIME ZONE
+-- endStatement
+
+-- beginStatement
+-- approved promotions used to be applied as soon as they were approved
+UPDATE promotions SET applied_at = decided_at WHERE status = 'APPROVED'
+-- endStatement
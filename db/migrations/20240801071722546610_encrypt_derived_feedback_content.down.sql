@@ -0,0 +1,6 @@
+-- Down Migration 20240801071722546610 encrypt_derived_feedback_content
+
+DROP INDEX llm_cache_entries_organization_idx;
+ALTER TABLE llm_cache_entries DROP COLUMN organization_id;
+DELETE FROM llm_cache_entries;
+ALTER TABLE tara_conversations ALTER COLUMN title TYPE VARCHAR(255);
@@ -0,0 +1,20 @@
+-- Up Migration 20240801071722546610 encrypt_derived_feedback_content
+
+-- beginStatement
+-- encrypted titles do not fit 255 characters
+ALTER TABLE tara_conversations ALTER COLUMN title TYPE TEXT
+-- endStatement
+
+-- beginStatement
+-- cached responses are encrypted with the key of their organization, the
+-- plaintext ones are dropped
+DELETE FROM llm_cache_entries
+-- endStatement
+
+-- beginStatement
+ALTER TABLE llm_cache_entries ADD COLUMN organization_id UUID
+-- endStatement
+
+-- beginStatement
+CREATE INDEX llm_cache_entries_organization_idx ON llm_cache_entries (organization_id)
+-- endStatement
@@ -0,0 +1,117 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/golly-go/golly"
+	"github.com/golly-go/plugins/orm"
+	"github.com/google/uuid"
+	"github.com/mitchrodrigues/talent-review-backend/app/domains/accounts"
+	"github.com/mitchrodrigues/talent-review-backend/app/domains/accounts/users"
+	"github.com/mitchrodrigues/talent-review-backend/app/domains/employees/employee"
+	"github.com/mitchrodrigues/talent-review-backend/app/domains/reviews"
+	"github.com/mitchrodrigues/talent-review-backend/app/domains/reviews/feedback"
+	"github.com/mitchrodrigues/talent-review-backend/app/initializers"
+	"github.com/mitchrodrigues/talent-review-backend/app/utils/crypto"
+	"github.com/mitchrodrigues/talent-review-backend/app/utils/esbackend"
+	"github.com/spf13/cobra"
+)
+
+var commands = []*cobra.Command{
+	{
+		Use:  "generate-master-key",
+		Long: "print a new master key for the local key file",
+		Run:  golly.Command(generateMasterKey),
+	},
+	{
+		Use:  "rotate-keys [organizationID...]",
+		Long: "rotate the data keys of the organizations, all of them when none are given, and re-encrypt their feedback content",
+		Run:  golly.Command(rotateKeys),
+	},
+	{
+		Use:  "seal-events",
+		Long: "seal the personal data of events stored before it was sealed with the keys of their subjects",
+		Run:  golly.Command(sealEvents),
+	},
+}
+
+// personalData are the event payloads carrying personal data
+var personalData = []crypto.PersonalData{
+	employee.Created{},
+	employee.Updated{},
+	employee.PersonalDetailsUpdated{},
+	feedback.Created{},
+	users.UserCreated{},
+	users.UserUpdated{},
+}
+
+func main() {
+	golly.Start(golly.GollyStartOptions{
+		Preboots:     initializers.Preboots,
+		Initializers: initializers.Initializers,
+		CLICommands:  commands,
+	})
+}
+
+func generateMasterKey(gctx golly.Context, cmd *cobra.Command, args []string) error {
+	key, err := crypto.GenerateMasterKey()
+	if err != nil {
+		return err
+	}
+
+	fmt.Println(key)
+	return nil
+}
+
+func rotateKeys(gctx golly.Context, cmd *cobra.Command, args []string) error {
+	if crypto.Provider() == nil {
+		return fmt.Errorf("crypto.key_file is not configured")
+	}
+
+	organizationIDs := []uuid.UUID{}
+
+	for _, arg := range args {
+		id, err := uuid.Parse(arg)
+		if err != nil {
+			return err
+		}
+		organizationIDs = append(organizationIDs, id)
+	}
+
+	if len(organizationIDs) == 0 {
+		err := orm.DB(gctx).
+			Model(&accounts.Organization{}).
+			Pluck("id", &organizationIDs).
+			Error
+
+		if err != nil {
+			return err
+		}
+	}
+
+	for _, organizationID := range organizationIDs {
+		key, err := crypto.RotateDataKey(gctx.Context(), orm.DB(gctx), organizationID)
+		if err != nil {
+			return fmt.Errorf("cannot rotate the data key of %s: %w", organizationID, err)
+		}
+
+		count, err := reviews.ReencryptFeedback(gctx, organizationID)
+		if err != nil {
+			return fmt.Errorf("cannot re-encrypt the feedback of %s: %w", organizationID, err)
+		}
+
+		fmt.Printf("rotated %s to data key %s, re-encrypted %d rows\n", organizationID, key.ID, count)
+	}
+
+	return nil
+}
+
+func sealEvents(gctx golly.Context, cmd *cobra.Command, args []string) error {
+	count, err := esbackend.SealEvents(gctx, personalData...)
+	if err != nil {
+		return fmt.Errorf("cannot seal events, %d sealed: %w", count, err)
+	}
+
+	fmt.Printf("sealed %d events\n", count)
+	return nil
+}
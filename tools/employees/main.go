@@ -1,7 +1,9 @@
 package main
 
 import (
+	"fmt"
 	"strings"
+	"time"
 
 	"github.com/golly-go/golly"
 	"github.com/golly-go/plugins/eventsource"
@@ -21,6 +23,11 @@ var commands = []*cobra.Command{
 		Long: "send test feedback email",
 		Run:  golly.Command(convertRoles),
 	},
+	{
+		Use:  "apply-promotions",
+		Long: "move the employees of approved promotions past their effective date to the target role",
+		Run:  golly.Command(applyPromotions),
+	},
 }
 
 type OldRole struct {
@@ -100,6 +107,18 @@ func convertRoles(gctx golly.Context, cmd *cobra.Command, args []string) error {
 	})
 }
 
+func applyPromotions(gctx golly.Context, cmd *cobra.Command, args []string) error {
+	applied, err := employees.ApplyDuePromotions(gctx, time.Now())
+	if err != nil {
+		return err
+	}
+
+	for _, p := range applied {
+		fmt.Printf("applied promotion %s of employee %s\n", p.ID, p.EmployeeID)
+	}
+	return nil
+}
+
 func main() {
 	golly.Start(golly.GollyStartOptions{
 		Preboots:     initializers.Preboots,
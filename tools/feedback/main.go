@@ -10,13 +10,16 @@ import (
 	"github.com/google/uuid"
 	"github.com/mitchrodrigues/talent-review-backend/app/domains/reviews"
 	"github.com/mitchrodrigues/talent-review-backend/app/initializers"
+	"github.com/mitchrodrigues/talent-review-backend/app/utils/identity"
 	"github.com/mitchrodrigues/talent-review-backend/app/utils/mailgun"
+	"github.com/mitchrodrigues/talent-review-backend/app/utils/openai"
 	"github.com/sirupsen/logrus"
 	"github.com/spf13/cobra"
 	"gorm.io/gorm"
 )
 
 var commands = []*cobra.Command{
+	updateSummaryCommand,
 	{
 		Use:  "test-email",
 		Long: "send test feedback email",
@@ -24,9 +27,20 @@ var commands = []*cobra.Command{
 		Run:  golly.Command(testEmail),
 	},
 	{
-		Use:  "update-summary",
-		Long: "update tara summary for a feedback",
-		Run:  golly.Command(submitFeedback),
+		Use:  "compare-summary [feedbackID]",
+		Long: "re-run tara for a feedback with the recorded prompt versions and the active ones and print both",
+		Args: cobra.MinimumNArgs(1),
+		Run:  golly.Command(compareSummary),
+	},
+	{
+		Use:  "embed-feedback",
+		Long: "embed submitted feedback that has no stored embeddings, used to backfill semantic search",
+		Run:  golly.Command(embedFeedback),
+	},
+	{
+		Use:  "analyze-feedback",
+		Long: "run the bias and inclusivity analysis over submitted feedback that has not been analyzed",
+		Run:  golly.Command(analyzeFeedback),
 	},
 	{
 		Use:  "check [managerID]",
@@ -36,6 +50,20 @@ var commands = []*cobra.Command{
 	},
 }
 
+var (
+	updateSummaryCommand = &cobra.Command{
+		Use:  "update-summary",
+		Long: "update tara summary for a feedback, identical requests are answered from the llm cache unless --bypass-cache is set",
+		Run:  golly.Command(submitFeedback),
+	}
+
+	bypassCache bool
+)
+
+func init() {
+	updateSummaryCommand.Flags().BoolVar(&bypassCache, "bypass-cache", false, "always call the LLM, fresh responses still refresh the cache")
+}
+
 func main() {
 	golly.Start(golly.GollyStartOptions{
 		Preboots:     initializers.Preboots,
@@ -73,6 +101,10 @@ func submitFeedback(gctx golly.Context, cmd *cobra.Command, args []string) error
 
 	var wg sync.WaitGroup
 
+	if bypassCache {
+		gctx = openai.BypassCache(gctx)
+	}
+
 	orm.DB(gctx).
 		Model(&feedbacks).
 		Joins("LEFT JOIN feedback_summaries summary ON summary.feedback_id = feedbacks.id").
@@ -92,6 +124,94 @@ func submitFeedback(gctx golly.Context, cmd *cobra.Command, args []string) error
 	return nil
 }
 
+func embedFeedback(gctx golly.Context, cmd *cobra.Command, args []string) error {
+	var feedbacks []reviews.Feedback
+
+	err := orm.DB(gctx).
+		Model(&feedbacks).
+		Where("feedbacks.submitted_at IS NOT NULL").
+		Where("NOT EXISTS (SELECT 1 FROM feedback_embeddings fe WHERE fe.feedback_id = feedbacks.id)").
+		Find(&feedbacks).
+		Error
+
+	if err != nil {
+		return err
+	}
+
+	for _, feedback := range feedbacks {
+		if err := reviews.EmbedFeedback(gctx, &feedback.Aggregate); err != nil {
+			gctx.Logger().Warnf("cannot embed feedback %s %v", feedback.ID.String(), err)
+			continue
+		}
+
+		fmt.Printf("embedded %s\n", feedback.ID.String())
+	}
+
+	return nil
+}
+
+func analyzeFeedback(gctx golly.Context, cmd *cobra.Command, args []string) error {
+	var feedbacks []reviews.Feedback
+
+	err := orm.DB(gctx).
+		Model(&feedbacks).
+		Joins("LEFT JOIN feedback_analyses analysis ON analysis.feedback_id = feedbacks.id").
+		Where("analysis.id IS NULL AND feedbacks.submitted_at IS NOT NULL").
+		Find(&feedbacks).
+		Error
+
+	if err != nil {
+		return err
+	}
+
+	for _, feedback := range feedbacks {
+		if err := reviews.AnalyzeFeedback(gctx, &feedback.Aggregate); err != nil {
+			gctx.Logger().Warnf("cannot analyze feedback %s %v", feedback.ID.String(), err)
+			continue
+		}
+
+		fmt.Printf("analyzed %s\n", feedback.ID.String())
+	}
+
+	return nil
+}
+
+func compareSummary(gctx golly.Context, cmd *cobra.Command, args []string) error {
+	var fb reviews.Feedback
+
+	err := orm.DB(gctx).
+		Model(&fb).
+		Preload("Summary").
+		First(&fb, "id = ?", uuid.MustParse(args[0])).
+		Error
+
+	if err != nil {
+		return err
+	}
+
+	_, gctx = identity.SetOrganizationID(gctx, fb.OrganizationID)
+
+	recorded, err := reviews.GenerateFeedbackSummary(gctx, &fb.Aggregate, fb.Summary.PromptVersions)
+	if err != nil {
+		return err
+	}
+
+	active, err := reviews.GenerateFeedbackSummary(gctx, &fb.Aggregate, nil)
+	if err != nil {
+		return err
+	}
+
+	b, _ := json.MarshalIndent(map[string]interface{}{
+		"stored":   map[string]interface{}{"summary": fb.Summary.Summary, "promptVersions": fb.Summary.PromptVersions},
+		"recorded": recorded,
+		"active":   active,
+	}, "", "\t")
+
+	fmt.Printf("%s\n", string(b))
+
+	return nil
+}
+
 func testEmail(gctx golly.Context, cmd *cobra.Command, args []string) error {
 	mg := mailgun.NewDefaultClient(gctx)
 
@@ -0,0 +1,157 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/golly-go/golly"
+	"github.com/golly-go/plugins/orm"
+	"github.com/google/uuid"
+	"github.com/mitchrodrigues/talent-review-backend/app/domains/accounts"
+	"github.com/mitchrodrigues/talent-review-backend/app/domains/accounts/organizations"
+	"github.com/mitchrodrigues/talent-review-backend/app/domains/employees"
+	"github.com/mitchrodrigues/talent-review-backend/app/domains/privacy"
+	"github.com/mitchrodrigues/talent-review-backend/app/initializers"
+	"github.com/spf13/cobra"
+)
+
+var commands = []*cobra.Command{
+	{
+		Use:  "export-employee [employeeID] [file]",
+		Long: "write the data subject export of an employee and their user to a ZIP file",
+		Args: cobra.RangeArgs(1, 2),
+		Run:  golly.Command(exportEmployee),
+	},
+	{
+		Use:  "export-user [userID] [file]",
+		Long: "write the data subject export of a user and their employee record to a ZIP file",
+		Args: cobra.RangeArgs(1, 2),
+		Run:  golly.Command(exportUser),
+	},
+	{
+		Use:  "purge [organizationID]",
+		Long: "apply the retention settings of an organization, or of every organization and drop expired data exports, now",
+		Args: cobra.MaximumNArgs(1),
+		Run:  golly.Command(purge),
+	},
+}
+
+func main() {
+	golly.Start(golly.GollyStartOptions{
+		Preboots:     initializers.Preboots,
+		Initializers: initializers.Initializers,
+		CLICommands:  commands,
+	})
+}
+
+func exportEmployee(gctx golly.Context, cmd *cobra.Command, args []string) error {
+	id, err := uuid.Parse(args[0])
+	if err != nil {
+		return err
+	}
+
+	var emp employees.Employee
+	if err := orm.DB(gctx).First(&emp, "id = ?", id).Error; err != nil {
+		return fmt.Errorf("cannot find employee %s: %w", id, err)
+	}
+
+	return writeExport(gctx, emp.OrganizationID, &emp.ID, nil, args[1:])
+}
+
+func exportUser(gctx golly.Context, cmd *cobra.Command, args []string) error {
+	id, err := uuid.Parse(args[0])
+	if err != nil {
+		return err
+	}
+
+	user, err := accounts.FindUserByID(gctx, id.String())
+	if err != nil {
+		return fmt.Errorf("cannot find user %s: %w", id, err)
+	}
+
+	return writeExport(gctx, user.OrganizationID, nil, &user.ID, args[1:])
+}
+
+func writeExport(gctx golly.Context, organizationID uuid.UUID, employeeID, userID *uuid.UUID, args []string) error {
+	subject, err := privacy.FindSubject(gctx, organizationID, employeeID, userID)
+	if err != nil {
+		return err
+	}
+
+	report, err := privacy.Collect(gctx, subject)
+	if err != nil {
+		return err
+	}
+
+	path := fmt.Sprintf("data-export-%s.zip", report.GeneratedAt.Format("20060102150405"))
+	if len(args) > 0 {
+		path = args[0]
+	}
+
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0600)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	if err := privacy.WriteArchive(f, report); err != nil {
+		return err
+	}
+
+	fmt.Printf("wrote %s: %d feedback received, %d given, %d audit events\n",
+		path, len(report.FeedbackReceived), len(report.FeedbackGiven), len(report.AuditEvents))
+	return nil
+}
+
+func purge(gctx golly.Context, cmd *cobra.Command, args []string) error {
+	var reports []organizations.DataPurged
+
+	if len(args) == 0 {
+		var err error
+		if reports, err = privacy.PurgeAll(gctx, time.Now()); err != nil {
+			return err
+		}
+
+		expired, err := privacy.ExpireDataExports(gctx, time.Now())
+		if err != nil {
+			return err
+		}
+
+		fmt.Printf("dropped %d expired data export archives\n", expired)
+	} else {
+		id, err := uuid.Parse(args[0])
+		if err != nil {
+			return err
+		}
+
+		org, err := accounts.FindOrganizationByID(gctx, id)
+		if err != nil {
+			return fmt.Errorf("cannot find organization %s: %w", id, err)
+		}
+
+		if org.ID == uuid.Nil {
+			return fmt.Errorf("cannot find organization %s", id)
+		}
+
+		report, err := privacy.Purge(gctx, org, time.Now())
+		if err != nil {
+			return err
+		}
+
+		if !report.Empty() {
+			reports = append(reports, report)
+		}
+	}
+
+	if len(reports) == 0 {
+		fmt.Println("nothing to purge")
+	}
+
+	for _, report := range reports {
+		fmt.Printf("%s: purged %d feedback and %d reviews, dropped %d requests, tombstoned %d events, deleted %d conversations and %d archives\n",
+			report.OrganizationID, report.FeedbackPurged, report.ReviewsPurged, report.RequestsDropped, report.EventsTombstoned,
+			report.ConversationsDeleted, report.ArchivesDropped)
+	}
+	return nil
+}
@@ -81,6 +81,7 @@ func register(gctx golly.Context, cmd *cobra.Command, args []string) error {
 		FirstName:    args[1],
 		LastName:     args[2],
 		Email:        args[3],
+		Role:         users.RoleAdmin,
 		Organization: &organization,
 	}, eventsource.Metadata{})
 }
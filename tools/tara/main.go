@@ -0,0 +1,249 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/golly-go/golly"
+	"github.com/golly-go/plugins/eventsource"
+	"github.com/google/uuid"
+	"github.com/mitchrodrigues/talent-review-backend/app/domains/tara"
+	"github.com/mitchrodrigues/talent-review-backend/app/domains/tara/eval"
+	"github.com/mitchrodrigues/talent-review-backend/app/domains/tara/prompt"
+	"github.com/mitchrodrigues/talent-review-backend/app/initializers"
+	"github.com/mitchrodrigues/talent-review-backend/app/utils/openai"
+	"github.com/spf13/cobra"
+)
+
+var commands = []*cobra.Command{
+	{
+		Use:  "seed-prompts",
+		Long: "store the built in prompts as the first version of each prompt",
+		Run:  golly.Command(seedPrompts),
+	},
+	{
+		Use:  "create-prompt [file]",
+		Long: "create and activate a new global prompt version from a JSON file (name, template, rules, scenario, tone)",
+		Args: cobra.MinimumNArgs(1),
+		Run:  golly.Command(createPrompt),
+	},
+	{
+		Use:  "list-prompts [name]",
+		Long: "list the global prompt versions",
+		Run:  golly.Command(listPrompts),
+	},
+	evalCommand,
+	{
+		Use:  "purge-cache",
+		Long: "delete expired llm cache entries",
+		Run:  golly.Command(purgeCache),
+	},
+	{
+		Use:  "eval-diff [base report] [head report]",
+		Long: "compare two evaluation reports",
+		Args: cobra.ExactArgs(2),
+		Run:  golly.Command(evalDiff),
+	},
+}
+
+var (
+	evalCommand = &cobra.Command{
+		Use:  "eval",
+		Long: "summarize the evaluation corpus and score the results, replays a recorded cassette unless --record is set",
+		Run:  golly.Command(runEval),
+	}
+
+	evalCorpus   string
+	evalCassette string
+	evalOut      string
+	evalRecord   bool
+	evalRubric   bool
+	evalPrompts  []string
+)
+
+func init() {
+	flags := evalCommand.Flags()
+
+	flags.StringVar(&evalCorpus, "corpus", "app/domains/tara/eval/corpus", "directory or file of feedback cases")
+	flags.StringVar(&evalCassette, "cassette", "", "recorded responses to replay, or to record into with --record")
+	flags.StringVar(&evalOut, "out", "", "write the JSON report to this file instead of stdout")
+	flags.BoolVar(&evalRecord, "record", false, "call the configured LLM and record responses into the cassette")
+	flags.BoolVar(&evalRubric, "rubric", false, "also grade summaries with the LLM rubric")
+	flags.StringArrayVar(&evalPrompts, "prompt", nil, "pin a prompt version as name=id[+overrideID], defaults to the active versions")
+}
+
+func main() {
+	golly.Start(golly.GollyStartOptions{
+		Preboots:     initializers.Preboots,
+		Initializers: initializers.Initializers,
+		CLICommands:  commands,
+	})
+}
+
+func seedPrompts(gctx golly.Context, cmd *cobra.Command, args []string) error {
+	return tara.SeedPrompts(gctx)
+}
+
+func createPrompt(gctx golly.Context, cmd *cobra.Command, args []string) error {
+	b, err := os.ReadFile(args[0])
+	if err != nil {
+		return err
+	}
+
+	var create prompt.Create
+	if err := json.Unmarshal(b, &create); err != nil {
+		return err
+	}
+
+	p, err := tara.CreatePromptVersion(gctx, prompt.Create{
+		Name:     create.Name,
+		Template: create.Template,
+		Rules:    create.Rules,
+		Scenario: create.Scenario,
+		Tone:     create.Tone,
+	}, true, eventsource.Metadata{})
+
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("created %s v%d (%s)\n", p.Name, p.Revision, p.ID)
+	return nil
+}
+
+func runEval(gctx golly.Context, cmd *cobra.Command, args []string) error {
+	corpus, err := eval.LoadCorpus(evalCorpus)
+	if err != nil {
+		return err
+	}
+
+	pinned, err := parsePromptRefs(evalPrompts)
+	if err != nil {
+		return err
+	}
+
+	var recorder *openai.RecordingProvider
+
+	switch {
+	case evalRecord:
+		fixtures := map[string]string{}
+		if evalCassette != "" {
+			if existing, err := openai.NewFixtureProviderFromFile(evalCassette); err == nil {
+				fixtures = existing.Fixtures
+			}
+		}
+
+		recorder = openai.NewRecordingProvider(openai.LLM(gctx), fixtures)
+		gctx = openai.UseProvider(gctx, recorder)
+
+	case evalCassette != "":
+		replay, err := openai.NewReplayProvider(evalCassette)
+		if err != nil {
+			return err
+		}
+		gctx = openai.UseProvider(gctx, replay)
+	}
+
+	report := eval.Run(gctx, corpus, eval.Options{
+		Rubric:   evalRubric,
+		Generate: tara.GenerateOptions{Prompts: pinned},
+	})
+
+	if recorder != nil && evalCassette != "" {
+		if err := recorder.Save(evalCassette); err != nil {
+			return err
+		}
+	}
+
+	if evalOut != "" {
+		return report.Save(evalOut)
+	}
+
+	b, _ := json.MarshalIndent(report, "", "  ")
+	fmt.Println(string(b))
+
+	return nil
+}
+
+func purgeCache(gctx golly.Context, cmd *cobra.Command, args []string) error {
+	count, err := tara.PurgeExpiredCache(gctx)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("purged %d cache entries\n", count)
+	return nil
+}
+
+func evalDiff(gctx golly.Context, cmd *cobra.Command, args []string) error {
+	base, err := eval.LoadReport(args[0])
+	if err != nil {
+		return err
+	}
+
+	head, err := eval.LoadReport(args[1])
+	if err != nil {
+		return err
+	}
+
+	for _, line := range eval.Diff(base, head) {
+		fmt.Println(line)
+	}
+
+	return nil
+}
+
+// parsePromptRefs parses name=id[+overrideID] pins
+func parsePromptRefs(pins []string) (prompt.Refs, error) {
+	refs := prompt.Refs{}
+
+	for _, pin := range pins {
+		name, ids, found := strings.Cut(pin, "=")
+		if !found {
+			return nil, fmt.Errorf("invalid prompt pin %s, expected name=id", pin)
+		}
+
+		base, override, _ := strings.Cut(ids, "+")
+
+		var ref prompt.Ref
+		var err error
+
+		if ref.ID, err = uuid.Parse(base); err != nil {
+			return nil, fmt.Errorf("invalid prompt id %s: %w", base, err)
+		}
+
+		if override != "" {
+			if ref.OverrideID, err = uuid.Parse(override); err != nil {
+				return nil, fmt.Errorf("invalid prompt override id %s: %w", override, err)
+			}
+		}
+
+		refs[name] = ref
+	}
+
+	return refs, nil
+}
+
+func listPrompts(gctx golly.Context, cmd *cobra.Command, args []string) error {
+	name := ""
+	if len(args) > 0 {
+		name = args[0]
+	}
+
+	prompts, err := tara.FindPromptVersions(gctx, uuid.Nil, name)
+	if err != nil {
+		return err
+	}
+
+	for _, p := range prompts {
+		active := ""
+		if p.Active {
+			active = " (active)"
+		}
+		fmt.Printf("%s v%d %s%s\n", p.Name, p.Revision, p.ID, active)
+	}
+
+	return nil
+}